@@ -0,0 +1,136 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package approval_list
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// GitHubOrgLookup returns the GitHub organizations the GitHub user is a public member of
+type GitHubOrgLookup func(githubUsername string) ([]string, error)
+
+// AutoApproval describes the auto-approval rule which approved a CCLA approval list request
+type AutoApproval struct {
+	RuleType     string
+	MatchedValue string
+	// ListType and Entry are the approval list entry added for the requester
+	ListType string
+	Entry    string
+}
+
+// isVerifiedEmail returns true if the email address is one of the email addresses on file for the user - these are
+// the addresses verified by the identity provider when the user logged in
+func isVerifiedEmail(userModel *models.User, email string) bool {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return false
+	}
+	if strings.EqualFold(userModel.LfEmail, email) {
+		return true
+	}
+	for _, userEmail := range userModel.Emails {
+		if strings.EqualFold(userEmail, email) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAutoApprovalRules returns the first auto-approval rule of the signature matching the requester, nil if the
+// request must wait for a CLA Manager
+func (s service) matchAutoApprovalRules(sig *models.Signature, userModel *models.User, contributorEmail string) *AutoApproval {
+	var githubOrgs []string
+	githubOrgsLoaded := false
+
+	for _, rule := range sig.AutoApprovalRules {
+		switch utils.StringValue(rule.RuleType) {
+		case signatures.AutoApprovalRuleTypeEmailDomain:
+			// The domain of an address the requester merely typed in proves nothing
+			if !isVerifiedEmail(userModel, contributorEmail) {
+				continue
+			}
+			if matched, ok := utils.MatchEmailDomain(contributorEmail, rule.Values); ok {
+				return &AutoApproval{
+					RuleType:     signatures.AutoApprovalRuleTypeEmailDomain,
+					MatchedValue: matched,
+					ListType:     signatures.ApprovalListTypeEmail,
+					Entry:        strings.TrimSpace(contributorEmail),
+				}
+			}
+		case signatures.AutoApprovalRuleTypeGithubOrg:
+			if userModel.GithubUsername == "" || s.githubOrgLookup == nil {
+				continue
+			}
+			if !githubOrgsLoaded {
+				githubOrgsLoaded = true
+				orgs, err := s.githubOrgLookup(userModel.GithubUsername)
+				if err != nil {
+					log.Warnf("unable to lookup the GitHub organizations of %s, error: %+v", userModel.GithubUsername, err)
+				}
+				githubOrgs = orgs
+			}
+			for _, org := range rule.Values {
+				if containsFold(githubOrgs, org) {
+					return &AutoApproval{
+						RuleType:     signatures.AutoApprovalRuleTypeGithubOrg,
+						MatchedValue: org,
+						ListType:     signatures.ApprovalListTypeGithubUsername,
+						Entry:        userModel.GithubUsername,
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// autoApprove adds the requester to the approval list of the signature and approves the request
+func (s service) autoApprove(sig *models.Signature, userModel *models.User, requestID string, autoApproval *AutoApproval) error {
+	params := &models.ApprovalList{}
+	switch autoApproval.ListType {
+	case signatures.ApprovalListTypeEmail:
+		if !containsFold(sig.EmailApprovalList, autoApproval.Entry) {
+			params.AddEmailApprovalList = []string{autoApproval.Entry}
+		}
+	case signatures.ApprovalListTypeGithubUsername:
+		if !containsFold(sig.GithubUsernameApprovalList, autoApproval.Entry) {
+			params.AddGithubUsernameApprovalList = []string{autoApproval.Entry}
+		}
+	default:
+		return fmt.Errorf("unsupported approval list type %s", autoApproval.ListType)
+	}
+
+	if len(params.AddEmailApprovalList) > 0 || len(params.AddGithubUsernameApprovalList) > 0 {
+		recordVersion := sig.RecordVersion
+		params.RecordVersion = &recordVersion
+		updatedSig, err := s.signatureRepo.UpdateApprovalList(sig.ProjectID, sig.SignatureReferenceID.String(), params)
+		if err != nil {
+			return err
+		}
+
+		// The requester is recorded as the author of the change - the rule approved it on the CLA Managers' behalf
+		if s.revisionsService != nil && updatedSig != nil && updatedSig.RecordVersion > sig.RecordVersion {
+			if _, revisionErr := s.revisionsService.CreateRevision(sig, updatedSig, userModel); revisionErr != nil {
+				log.Warnf("unable to record the approval list revision for signature ID: %s, error: %+v", updatedSig.SignatureID, revisionErr)
+			}
+		}
+	}
+
+	return s.repo.ApproveCclaWhitelistRequest(requestID)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
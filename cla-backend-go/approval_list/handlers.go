@@ -12,15 +12,15 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/signatures"
 	"github.com/communitybridge/easycla/cla-backend-go/user"
 	"github.com/go-openapi/runtime/middleware"
-	"github.com/savaki/dynastore"
+	"github.com/gorilla/sessions"
 )
 
 // Configure setups handlers on api with service
-func Configure(api *operations.ClaAPI, service IService, sessionStore *dynastore.Store, signatureService signatures.SignatureService, eventsService events.Service) {
+func Configure(api *operations.ClaAPI, service IService, sessionStore sessions.Store, signatureService signatures.SignatureService, eventsService events.Service) {
 
 	api.CompanyAddCclaWhitelistRequestHandler = company.AddCclaWhitelistRequestHandlerFunc(
 		func(params company.AddCclaWhitelistRequestParams) middleware.Responder {
-			requestID, err := service.AddCclaWhitelistRequest(params.CompanyID, params.ProjectID, params.Body)
+			requestID, autoApproval, err := service.AddCclaWhitelistRequest(params.CompanyID, params.ProjectID, params.Body)
 			if err != nil {
 				return company.NewAddCclaWhitelistRequestBadRequest().WithPayload(errorResponse(err))
 			}
@@ -33,6 +33,21 @@ func Configure(api *operations.ClaAPI, service IService, sessionStore *dynastore
 				EventData: &events.CCLAApprovalListRequestCreatedEventData{RequestID: requestID},
 			})
 
+			// Requests approved by an auto-approval rule are on behalf of the contributor
+			if autoApproval != nil {
+				eventsService.LogEvent(&events.LogEventArgs{
+					EventType: events.CCLAApprovalListRequestApproved,
+					ProjectID: params.ProjectID,
+					CompanyID: params.CompanyID,
+					UserID:    params.Body.ContributorID,
+					EventData: &events.CCLAApprovalListRequestApprovedEventData{
+						RequestID:                requestID,
+						AutoApprovalRuleType:     autoApproval.RuleType,
+						AutoApprovalMatchedValue: autoApproval.MatchedValue,
+					},
+				})
+			}
+
 			return company.NewAddCclaWhitelistRequestOK()
 		})
 
@@ -19,4 +19,6 @@ type CLARequestModel struct {
 	DateCreated        string   `dynamodbav:"date_created"`
 	DateModified       string   `dynamodbav:"date_modified"`
 	Version            string   `dynamodbav:"version"`
+	ReminderCount      int      `dynamodbav:"reminder_count"`
+	LastReminderDate   string   `dynamodbav:"last_reminder_date"`
 }
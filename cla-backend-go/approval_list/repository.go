@@ -6,6 +6,7 @@ package approval_list
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
@@ -16,6 +17,7 @@ import (
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 )
@@ -25,8 +27,13 @@ const (
 	Version = "v1"
 	// StatusPending is status of CclaWhitelistRequest
 	StatusPending = "pending"
+	// StatusExpired is the terminal status of a CclaWhitelistRequest which was not reviewed in time
+	StatusExpired = "expired"
 )
 
+// ErrCclaWhitelistRequestNotPending is returned when a request is expected to be pending but was already reviewed
+var ErrCclaWhitelistRequestNotPending = errors.New("ccla approval list request is no longer pending")
+
 // IRepository interface defines the functions for the whitelist service
 type IRepository interface {
 	AddCclaWhitelistRequest(company *models.Company, project *models.Project, user *models.User, requesterName, requesterEmail string) (string, error)
@@ -34,6 +41,9 @@ type IRepository interface {
 	ApproveCclaWhitelistRequest(requestID string) error
 	RejectCclaWhitelistRequest(requestID string) error
 	ListCclaWhitelistRequest(companyID string, projectID, status, userID *string) (*models.CclaWhitelistRequestList, error)
+	ListPendingCclaWhitelistRequests() ([]CLARequestModel, error)
+	UpdateCclaWhitelistRequestReminder(requestID string, reminderCount int) error
+	ExpireCclaWhitelistRequest(requestID string) error
 }
 
 type repository struct {
@@ -65,6 +75,8 @@ type CclaWhitelistRequest struct {
 	DateCreated        string   `dynamodbav:"date_created"`
 	DateModified       string   `dynamodbav:"date_modified"`
 	Version            string   `dynamodbav:"version"`
+	ReminderCount      int      `dynamodbav:"reminder_count"`
+	LastReminderDate   string   `dynamodbav:"last_reminder_date"`
 }
 
 // AddCclaWhitelistRequest adds the specified request
@@ -279,6 +291,132 @@ func (repo repository) ListCclaWhitelistRequest(companyID string, projectID, sta
 	return &models.CclaWhitelistRequestList{List: list}, nil
 }
 
+// ListPendingCclaWhitelistRequests returns all the pending requests across the companies and projects
+func (repo repository) ListPendingCclaWhitelistRequests() ([]CLARequestModel, error) {
+	tableName := fmt.Sprintf("cla-%s-ccla-whitelist-requests", repo.stage)
+
+	filter := expression.Name("request_status").Equal(expression.Value(StatusPending))
+	expr, err := expression.NewBuilder().WithFilter(filter).WithProjection(buildProjection()).Build()
+	if err != nil {
+		log.Warnf("error building expression for pending request scan, error: %v", err)
+		return nil, err
+	}
+
+	scanInput := &dynamodb.ScanInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		TableName:                 aws.String(tableName),
+	}
+
+	var requests []CLARequestModel
+	for {
+		results, scanErr := repo.dynamoDBClient.Scan(scanInput)
+		if scanErr != nil {
+			log.Warnf("error scanning for pending requests, error: %v", scanErr)
+			return nil, scanErr
+		}
+
+		var page []CLARequestModel
+		if err := dynamodbattribute.UnmarshalListOfMaps(results.Items, &page); err != nil {
+			log.Warnf("error unmarshalling pending requests, error: %v", err)
+			return nil, err
+		}
+		requests = append(requests, page...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return requests, nil
+}
+
+// UpdateCclaWhitelistRequestReminder records the number of reminders sent for the pending request - the modified
+// date is left alone as it tells how long the request is pending
+func (repo repository) UpdateCclaWhitelistRequestReminder(requestID string, reminderCount int) error {
+	input := &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"request_id": {
+				S: aws.String(requestID),
+			},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#S": aws.String("request_status"),
+			"#C": aws.String("reminder_count"),
+			"#R": aws.String("last_reminder_date"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":p": {
+				S: aws.String(StatusPending),
+			},
+			":c": {
+				N: aws.String(strconv.Itoa(reminderCount)),
+			},
+			":r": {
+				S: aws.String(currentTime()),
+			},
+		},
+		ConditionExpression: aws.String("#S = :p"),
+		UpdateExpression:    aws.String("SET #C = :c, #R = :r"),
+		TableName:           aws.String(fmt.Sprintf("cla-%s-ccla-whitelist-requests", repo.stage)),
+	}
+
+	_, err := repo.dynamoDBClient.UpdateItem(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrCclaWhitelistRequestNotPending
+		}
+		log.Warnf("UpdateCclaWhitelistRequestReminder - unable to update approval request reminder, error: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// ExpireCclaWhitelistRequest moves the specified request to the expired status - returns
+// ErrCclaWhitelistRequestNotPending if the request was reviewed in the meantime
+func (repo repository) ExpireCclaWhitelistRequest(requestID string) error {
+	input := &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"request_id": {
+				S: aws.String(requestID),
+			},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#S": aws.String("request_status"),
+			"#M": aws.String("date_modified"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":p": {
+				S: aws.String(StatusPending),
+			},
+			":s": {
+				S: aws.String(StatusExpired),
+			},
+			":m": {
+				S: aws.String(currentTime()),
+			},
+		},
+		ConditionExpression: aws.String("#S = :p"),
+		UpdateExpression:    aws.String("SET #S = :s, #M = :m"),
+		TableName:           aws.String(fmt.Sprintf("cla-%s-ccla-whitelist-requests", repo.stage)),
+	}
+
+	_, err := repo.dynamoDBClient.UpdateItem(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrCclaWhitelistRequestNotPending
+		}
+		log.Warnf("ExpireCclaWhitelistRequest - unable to update approval request with expired status, error: %v", err)
+		return err
+	}
+
+	return nil
+}
+
 // buildProjects builds the response model projection for a given query
 func buildProjection() expression.ProjectionBuilder {
 	// These are the columns we want returned
@@ -297,13 +435,13 @@ func buildProjection() expression.ProjectionBuilder {
 		expression.Name("date_created"),
 		expression.Name("date_modified"),
 		expression.Name("version"),
+		expression.Name("reminder_count"),
+		expression.Name("last_reminder_date"),
 	)
 }
 
 // buildCclaWhitelistRequestsModels builds the request models
 func buildCclaWhitelistRequestsModels(results *dynamodb.QueryOutput) ([]models.CclaWhitelistRequest, error) {
-	requests := make([]models.CclaWhitelistRequest, 0)
-
 	var itemRequests []CclaWhitelistRequest
 
 	err := dynamodbattribute.UnmarshalListOfMaps(results.Items, &itemRequests)
@@ -312,6 +450,12 @@ func buildCclaWhitelistRequestsModels(results *dynamodb.QueryOutput) ([]models.C
 			err)
 		return nil, err
 	}
+	return toCclaWhitelistRequestModels(itemRequests), nil
+}
+
+// toCclaWhitelistRequestModels converts the database models into the response models
+func toCclaWhitelistRequestModels(itemRequests []CclaWhitelistRequest) []models.CclaWhitelistRequest {
+	requests := make([]models.CclaWhitelistRequest, 0)
 	for _, r := range itemRequests {
 		requests = append(requests, models.CclaWhitelistRequest{
 			CompanyID:          r.CompanyID,
@@ -328,9 +472,11 @@ func buildCclaWhitelistRequestsModels(results *dynamodb.QueryOutput) ([]models.C
 			UserID:             r.UserID,
 			UserName:           r.UserName,
 			Version:            r.Version,
+			ReminderCount:      int64(r.ReminderCount),
+			LastReminderDate:   r.LastReminderDate,
 		})
 	}
-	return requests, nil
+	return requests
 }
 
 // addStringAttribute adds the specified attribute as a string
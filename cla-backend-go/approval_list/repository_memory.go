@@ -0,0 +1,164 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package approval_list
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/gofrs/uuid"
+)
+
+type memoryRepository struct {
+	store     *storage.MemoryStore
+	tableName string
+}
+
+// NewMemoryRepository creates a new instance of the whitelist repository backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string) IRepository {
+	return memoryRepository{
+		store:     store,
+		tableName: fmt.Sprintf("cla-%s-ccla-whitelist-requests", stage),
+	}
+}
+
+// AddCclaWhitelistRequest adds the specified request
+func (repo memoryRepository) AddCclaWhitelistRequest(company *models.Company, project *models.Project, user *models.User, requesterName, requesterEmail string) (string, error) {
+	requestID, err := uuid.NewV4()
+	status := "status:fail"
+	if err != nil {
+		log.Warnf("AddCclaWhitelistRequest - unable to generate a UUID for a approval request, error: %v", err)
+		return status, err
+	}
+
+	currentTime := currentTime()
+	request := CclaWhitelistRequest{
+		RequestID:          requestID.String(),
+		RequestStatus:      StatusPending,
+		CompanyID:          company.CompanyID,
+		CompanyName:        company.CompanyName,
+		ProjectID:          project.ProjectID,
+		ProjectName:        project.ProjectName,
+		UserID:             user.UserID,
+		UserEmails:         []string{requesterEmail},
+		UserName:           requesterName,
+		UserGithubID:       user.GithubID,
+		UserGithubUsername: user.GithubUsername,
+		DateCreated:        currentTime,
+		DateModified:       currentTime,
+		Version:            Version,
+	}
+	if err := repo.store.Put(repo.tableName, request.RequestID, request); err != nil {
+		log.Warnf("AddCclaWhitelistRequest - unable to create a new ccla approval request, error: %v", err)
+		return status, err
+	}
+	return request.RequestID, nil
+}
+
+// GetCclaWhitelistRequest fetches the specified request by ID
+func (repo memoryRepository) GetCclaWhitelistRequest(requestID string) (*CLARequestModel, error) {
+	requestModel := CLARequestModel{}
+	_, err := repo.store.Get(repo.tableName, requestID, &requestModel)
+	if err != nil {
+		log.Warnf("error fetching request by ID: %s, error: %v", requestID, err)
+		return nil, err
+	}
+	return &requestModel, nil
+}
+
+// ApproveCclaWhitelistRequest approves the specified request
+func (repo memoryRepository) ApproveCclaWhitelistRequest(requestID string) error {
+	return repo.updateRequestStatus(requestID, "approved")
+}
+
+// RejectCclaWhitelistRequest rejects the specified request
+func (repo memoryRepository) RejectCclaWhitelistRequest(requestID string) error {
+	return repo.updateRequestStatus(requestID, "rejected")
+}
+
+func (repo memoryRepository) updateRequestStatus(requestID, status string) error {
+	var request CclaWhitelistRequest
+	err := repo.store.Update(repo.tableName, requestID, &request, func() error {
+		request.RequestStatus = status
+		request.DateModified = currentTime()
+		return nil
+	})
+	if err != nil {
+		log.Warnf("unable to update approval request with %s status, error: %v", status, err)
+		return err
+	}
+	return nil
+}
+
+// ListCclaWhitelistRequest list the requests for the specified query parameters
+func (repo memoryRepository) ListCclaWhitelistRequest(companyID string, projectID, status, userID *string) (*models.CclaWhitelistRequestList, error) {
+	if projectID == nil {
+		return nil, errors.New("project ID can not be nil for ListCclaWhitelistRequest")
+	}
+
+	var all []CclaWhitelistRequest
+	if err := repo.store.Scan(repo.tableName, &all); err != nil {
+		log.Warnf("list requests error while scanning, error: %+v", err)
+		return nil, err
+	}
+	var itemRequests []CclaWhitelistRequest
+	for _, r := range all {
+		if r.CompanyID != companyID || r.ProjectID != *projectID {
+			continue
+		}
+		if status != nil && r.RequestStatus != *status {
+			continue
+		}
+		if userID != nil && r.UserID != *userID {
+			continue
+		}
+		itemRequests = append(itemRequests, r)
+	}
+	return &models.CclaWhitelistRequestList{List: toCclaWhitelistRequestModels(itemRequests)}, nil
+}
+
+// ListPendingCclaWhitelistRequests returns all the pending requests across the companies and projects
+func (repo memoryRepository) ListPendingCclaWhitelistRequests() ([]CLARequestModel, error) {
+	var all []CLARequestModel
+	if err := repo.store.Scan(repo.tableName, &all); err != nil {
+		log.Warnf("error scanning for pending requests, error: %v", err)
+		return nil, err
+	}
+	var requests []CLARequestModel
+	for _, r := range all {
+		if r.RequestStatus == StatusPending {
+			requests = append(requests, r)
+		}
+	}
+	return requests, nil
+}
+
+// UpdateCclaWhitelistRequestReminder records the number of reminders sent for the pending request
+func (repo memoryRepository) UpdateCclaWhitelistRequestReminder(requestID string, reminderCount int) error {
+	var request CclaWhitelistRequest
+	return repo.store.Update(repo.tableName, requestID, &request, func() error {
+		if request.RequestStatus != StatusPending {
+			return ErrCclaWhitelistRequestNotPending
+		}
+		request.ReminderCount = reminderCount
+		request.LastReminderDate = currentTime()
+		return nil
+	})
+}
+
+// ExpireCclaWhitelistRequest moves the specified request to the expired status
+func (repo memoryRepository) ExpireCclaWhitelistRequest(requestID string) error {
+	var request CclaWhitelistRequest
+	return repo.store.Update(repo.tableName, requestID, &request, func() error {
+		if request.RequestStatus != StatusPending {
+			return ErrCclaWhitelistRequestNotPending
+		}
+		request.RequestStatus = StatusExpired
+		request.DateModified = currentTime()
+		return nil
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list_revisions"
 	"github.com/communitybridge/easycla/cla-backend-go/signatures"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 
@@ -32,7 +33,7 @@ const (
 
 // IService interface defines the service methods/functions
 type IService interface {
-	AddCclaWhitelistRequest(companyID string, projectID string, args models.CclaWhitelistRequestInput) (string, error)
+	AddCclaWhitelistRequest(companyID string, projectID string, args models.CclaWhitelistRequestInput) (string, *AutoApproval, error)
 	ApproveCclaWhitelistRequest(companyID, projectID, requestID string) error
 	RejectCclaWhitelistRequest(companyID, projectID, requestID string) error
 	ListCclaWhitelistRequest(companyID string, projectID, status *string) (*models.CclaWhitelistRequestList, error)
@@ -40,62 +41,70 @@ type IService interface {
 }
 
 type service struct {
-	repo           IRepository
-	userRepo       users.UserRepository
-	companyRepo    company.IRepository
-	projectRepo    project.ProjectRepository
-	signatureRepo  signatures.SignatureRepository
-	corpConsoleURL string
-	httpClient     *http.Client
+	repo             IRepository
+	userRepo         users.UserRepository
+	companyRepo      company.IRepository
+	projectRepo      project.ProjectRepository
+	signatureRepo    signatures.SignatureRepository
+	revisionsService approval_list_revisions.Service
+	githubOrgLookup  GitHubOrgLookup
+	corpConsoleURL   string
+	httpClient       *http.Client
 }
 
-// NewService creates a new whitelist service
-func NewService(repo IRepository, userRepo users.UserRepository, companyRepo company.IRepository, projectRepo project.ProjectRepository, signatureRepo signatures.SignatureRepository, corpConsoleURL string, httpClient *http.Client) IService {
+// NewService creates a new whitelist service - GitHub organization auto-approval rules only match when a GitHub
+// organization lookup is specified
+func NewService(repo IRepository, userRepo users.UserRepository, companyRepo company.IRepository, projectRepo project.ProjectRepository, signatureRepo signatures.SignatureRepository, revisionsService approval_list_revisions.Service, githubOrgLookup GitHubOrgLookup, corpConsoleURL string, httpClient *http.Client) IService {
 	return service{
-		repo:           repo,
-		userRepo:       userRepo,
-		companyRepo:    companyRepo,
-		projectRepo:    projectRepo,
-		signatureRepo:  signatureRepo,
-		corpConsoleURL: corpConsoleURL,
-		httpClient:     httpClient,
+		repo:             repo,
+		userRepo:         userRepo,
+		companyRepo:      companyRepo,
+		projectRepo:      projectRepo,
+		signatureRepo:    signatureRepo,
+		revisionsService: revisionsService,
+		githubOrgLookup:  githubOrgLookup,
+		corpConsoleURL:   corpConsoleURL,
+		httpClient:       httpClient,
 	}
 }
 
-func (s service) AddCclaWhitelistRequest(companyID string, projectID string, args models.CclaWhitelistRequestInput) (string, error) {
+// AddCclaWhitelistRequest creates the CCLA approval list request - when the request matches one of the auto-approval
+// rules of the signature, the requester is added to the approval list and the request is approved right away,
+// otherwise the CLA Managers are asked to review the request
+func (s service) AddCclaWhitelistRequest(companyID string, projectID string, args models.CclaWhitelistRequestInput) (string, *AutoApproval, error) {
 	list, err := s.ListCclaWhitelistRequestByCompanyProjectUser(companyID, &projectID, nil, &args.ContributorID)
 	if err != nil {
 		log.Warnf("AddCclaWhitelistRequest - error looking up existing contributor invite requests for company: %s, project: %s, user by id: %s with name: %s, email: %s, error: %+v",
 			companyID, projectID, args.ContributorID, args.ContributorName, args.ContributorEmail, err)
-		return "", err
+		return "", nil, err
 	}
 	for _, item := range list.List {
 		if item.RequestStatus == "pending" || item.RequestStatus == "approved" {
 			log.Warnf("AddCclaWhitelistRequest - found existing contributor invite - id: %s, request for company: %s, project: %s, user by id: %s with name: %s, email: %s",
 				list.List[0].RequestID, companyID, projectID, args.ContributorID, args.ContributorName, args.ContributorEmail)
-			return "", ErrCclaWhitelistRequestAlreadyExists
+			return "", nil, ErrCclaWhitelistRequestAlreadyExists
 		}
 	}
 	companyModel, err := s.companyRepo.GetCompany(companyID)
 	if err != nil {
 		log.Warnf("AddCclaWhitelistRequest - unable to lookup company by id: %s, error: %+v", companyID, err)
-		return "", err
+		return "", nil, err
 	}
 	projectModel, err := s.projectRepo.GetCLAGroupByID(projectID, DontLoadRepoDetails)
 	if err != nil {
 		log.Warnf("AddCclaWhitelistRequest - unable to lookup project by id: %s, error: %+v", projectID, err)
-		return "", err
+		return "", nil, err
 	}
 	userModel, err := s.userRepo.GetUser(args.ContributorID)
 	if err != nil {
 		log.Warnf("AddCclaWhitelistRequest - unable to lookup user by id: %s with name: %s, email: %s, error: %+v",
 			args.ContributorID, args.ContributorName, args.ContributorEmail, err)
-		return "", err
+		return "", nil, err
 	}
 	if userModel == nil {
 		log.Warnf("AddCclaWhitelistRequest - unable to lookup user by id: %s with name: %s, email: %s, error: user object not found",
 			args.ContributorID, args.ContributorName, args.ContributorEmail)
-		return "", errors.New("invalid user")
+		return "", nil, errors.New("invalid user")
 	}
 
 	signed, approved := true, true
@@ -104,7 +113,7 @@ func (s service) AddCclaWhitelistRequest(companyID string, projectID string, arg
 	if sigErr != nil || sig == nil || sig.Signatures == nil {
 		log.Warnf("AddCclaWhitelistRequest - unable to lookup signature by company id: %s project id: %s - (or no managers), sig: %+v, error: %+v",
 			companyID, projectID, sig, err)
-		return "", err
+		return "", nil, err
 	}
 
 	requestID, addErr := s.repo.AddCclaWhitelistRequest(companyModel, projectModel, userModel, args.ContributorName, args.ContributorEmail)
@@ -113,10 +122,26 @@ func (s service) AddCclaWhitelistRequest(companyID string, projectID string, arg
 			args.ContributorID, args.ContributorName, args.ContributorEmail, addErr)
 	}
 
+	// Approve the request right away when it matches one of the auto-approval rules of the signature - any failure
+	// falls back to the CLA Managers reviewing the request
+	if addErr == nil {
+		if autoApproval := s.matchAutoApprovalRules(sig.Signatures[0], userModel, args.ContributorEmail); autoApproval != nil {
+			approveErr := s.autoApprove(sig.Signatures[0], userModel, requestID, autoApproval)
+			if approveErr == nil {
+				log.Debugf("AddCclaWhitelistRequest - request: %s auto-approved by the %s rule matching %s",
+					requestID, autoApproval.RuleType, autoApproval.MatchedValue)
+				s.sendAutoApprovedEmails(companyModel, projectModel, sig.Signatures[0], args.ContributorName, args.ContributorEmail, autoApproval)
+				return requestID, autoApproval, nil
+			}
+			log.Warnf("AddCclaWhitelistRequest - unable to auto-approve request: %s by the %s rule matching %s, error: %+v",
+				requestID, autoApproval.RuleType, autoApproval.MatchedValue, approveErr)
+		}
+	}
+
 	// Send the emails to the CLA managers for this CCLA Signature which includes the managers in the ACL list
 	s.sendRequestSentEmail(companyModel, projectModel, sig.Signatures[0], args.ContributorName, args.ContributorEmail, args.RecipientName, args.RecipientEmail, args.Message)
 
-	return requestID, nil
+	return requestID, nil, nil
 }
 
 // ApproveCclaWhitelistRequest is the handler for the approve CLA request
@@ -248,6 +273,61 @@ func (s service) sendRequestSentEmail(companyModel *models.Company, projectModel
 	}
 }
 
+// sendAutoApprovedEmails lets the contributor know the request was approved and lets each CLA Manager of the signature
+// know which rule approved it
+func (s service) sendAutoApprovedEmails(companyModel *models.Company, projectModel *models.Project, signature *models.Signature, contributorName, contributorEmail string, autoApproval *AutoApproval) {
+	s.sendRequestApprovedEmailToRecipient(companyModel, projectModel, contributorName, contributorEmail)
+
+	for _, manager := range signature.SignatureACL {
+		whichEmail := manager.LfEmail
+		if whichEmail == "" && manager.Emails != nil {
+			whichEmail = manager.Emails[0]
+		}
+		if whichEmail == "" {
+			log.Warnf("unable to send email to manager: %+v - no email on file...", manager)
+			continue
+		}
+		s.sendRequestAutoApprovedEmailToRecipient(companyModel, projectModel, contributorName, contributorEmail, manager.Username, whichEmail, autoApproval)
+	}
+}
+
+// sendRequestAutoApprovedEmailToRecipient generates and sends an email to the specified CLA Manager
+func (s service) sendRequestAutoApprovedEmailToRecipient(companyModel *models.Company, projectModel *models.Project, contributorName, contributorEmail, recipientName, recipientAddress string, autoApproval *AutoApproval) {
+	companyName := companyModel.CompanyName
+	projectName := projectModel.ProjectName
+
+	ruleDescription := fmt.Sprintf("their verified email address matches the email domain %s", autoApproval.MatchedValue)
+	if autoApproval.RuleType == signatures.AutoApprovalRuleTypeGithubOrg {
+		ruleDescription = fmt.Sprintf("they are a member of the GitHub organization %s", autoApproval.MatchedValue)
+	}
+
+	// subject string, body string, recipients []string
+	subject := fmt.Sprintf("EasyCLA: %s Automatically Authorized for %s", contributorName, projectName)
+	recipients := []string{recipientAddress}
+	body := fmt.Sprintf(`
+<p>Hello %s,</p>
+<p>This is a notification email from EasyCLA regarding the project %s.</p>
+<p>%s (%s) requested to be added to the Allow List as an authorized contributor from
+%s to the project %s. The request was approved automatically because %s, as configured in
+the auto-approval rules of %s for %s. You are receiving this message as a CLA Manager from %s for %s.</p>
+<p>%s was added to the Allow List. If this contributor should not be authorized, please
+<a href="https://%s#/company/%s" target="_blank">log into the EasyCLA Corporate
+Console</a> and remove them from the Approved List.</p>
+%s
+%s`,
+		recipientName, projectName, contributorName, contributorEmail,
+		companyName, projectName, ruleDescription, companyName, projectName, companyName, projectName,
+		autoApproval.Entry, s.corpConsoleURL, companyModel.CompanyID,
+		utils.GetEmailHelpContent(projectModel.Version == utils.V2), utils.GetEmailSignOffContent())
+
+	err := utils.SendEmail(subject, body, recipients)
+	if err != nil {
+		log.Warnf("problem sending email with subject: %s to recipients: %+v, error: %+v", subject, recipients, err)
+	} else {
+		log.Debugf("sent email with subject: %s to recipients: %+v", subject, recipients)
+	}
+}
+
 // sendRequestEmailToRecipient generates and sends an email to the specified recipient
 func (s service) sendRequestEmailToRecipient(companyModel *models.Company, projectModel *models.Project, contributorName, contributorEmail, recipientName, recipientAddress, message string) {
 	companyName := companyModel.CompanyName
@@ -0,0 +1,273 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package approval_list_expirations
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list_revisions"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultReminderDays is the number of days before an approval list entry expires when the CLA managers are notified
+const DefaultReminderDays = 7
+
+// systemUser is the actor recorded on the events and revisions created when entries expire
+var systemUser = &models.User{
+	UserID:     "easycla system",
+	LfUsername: "easycla system",
+	Username:   "easycla system",
+}
+
+// Summary holds the results of processing the approval list expirations
+type Summary struct {
+	SignaturesProcessed int
+	EntriesExpired      int
+	RemindersSent       int
+}
+
+// Service defines the functions of the approval list expiration service
+type Service interface {
+	ProcessExpirations(now time.Time) (*Summary, error)
+}
+
+type service struct {
+	signaturesRepo   signatures.SignatureRepository
+	revisionsService approval_list_revisions.Service
+	companyRepo      company.IRepository
+	projectRepo      project.ProjectRepository
+	eventsService    events.Service
+	reminderDays     int
+}
+
+// NewService creates a new instance of the approval list expiration service - the CLA managers are notified the
+// specified number of days before an entry expires
+func NewService(signaturesRepo signatures.SignatureRepository, revisionsService approval_list_revisions.Service, companyRepo company.IRepository, projectRepo project.ProjectRepository, eventsService events.Service, reminderDays int) Service {
+	if reminderDays <= 0 {
+		reminderDays = DefaultReminderDays
+	}
+	return service{
+		signaturesRepo:   signaturesRepo,
+		revisionsService: revisionsService,
+		companyRepo:      companyRepo,
+		projectRepo:      projectRepo,
+		eventsService:    eventsService,
+		reminderDays:     reminderDays,
+	}
+}
+
+// ProcessExpirations removes the expired approval list entries of all the CCLA signatures and notifies the CLA
+// managers of the entries which expire within the configured number of days
+func (s service) ProcessExpirations(now time.Time) (*Summary, error) {
+	f := logrus.Fields{
+		"functionName": "ProcessExpirations",
+		"now":          utils.TimeToString(now),
+		"reminderDays": s.reminderDays,
+	}
+
+	sigs, err := s.signaturesRepo.GetSignaturesWithApprovalListExpirations()
+	if err != nil {
+		log.WithFields(f).Warnf("unable to load the signatures with approval list expirations, error: %+v", err)
+		return nil, err
+	}
+
+	summary := &Summary{}
+	for _, sig := range sigs {
+		expired, reminded, sigErr := s.processSignature(sig, now)
+		if sigErr != nil {
+			// Keep going - the remaining signatures are independent and the next run retries this one
+			log.WithFields(f).Warnf("unable to process the approval list expirations for signature ID: %s, error: %+v", sig.SignatureID, sigErr)
+			continue
+		}
+		summary.SignaturesProcessed++
+		summary.EntriesExpired += expired
+		summary.RemindersSent += reminded
+	}
+
+	log.WithFields(f).Debugf("processed approval list expirations - signatures: %d, expired entries: %d, reminders: %d",
+		summary.SignaturesProcessed, summary.EntriesExpired, summary.RemindersSent)
+	return summary, nil
+}
+
+// processSignature removes the expired entries of the signature and sends the reminders for the entries which expire
+// soon, returns the number of expired entries and reminders
+func (s service) processSignature(sig *models.Signature, now time.Time) (int, int, error) {
+	companyModel, err := s.companyRepo.GetCompany(sig.SignatureReferenceID.String())
+	if err != nil {
+		return 0, 0, err
+	}
+	projectModel, err := s.projectRepo.GetCLAGroupByID(sig.ProjectID, project.DontLoadRepoDetails)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	expired := signatures.ExpiredApprovalListEntries(sig, now)
+	if len(expired) > 0 {
+		updatedSig, removeErr := s.removeExpiredEntries(sig, companyModel, projectModel, expired)
+		if removeErr != nil {
+			return 0, 0, removeErr
+		}
+		sig = updatedSig
+	}
+
+	// Entries expiring within the reminder window which were not announced yet
+	reminderCutoff := now.AddDate(0, 0, s.reminderDays)
+	var expiring []*models.ApprovalListExpiration
+	for _, expiration := range sig.ApprovalListExpirations {
+		if expiration.ReminderSentDate != "" {
+			continue
+		}
+		expirationTime, parseErr := utils.ParseDateTime(expiration.ExpirationDate)
+		if parseErr != nil || expirationTime.After(reminderCutoff) {
+			continue
+		}
+		expiring = append(expiring, expiration)
+	}
+	if len(expiring) == 0 {
+		return len(expired), 0, nil
+	}
+
+	// The entries are reminded once the reminder reached a CLA manager, the reminders which could not be sent are
+	// retried by the next run
+	reminded := false
+	for _, claManager := range sig.SignatureACL {
+		if sendErr := s.sendExpirationReminderEmail(companyModel, projectModel, claManager, expiring); sendErr == nil {
+			reminded = true
+		}
+	}
+	if !reminded {
+		return len(expired), 0, nil
+	}
+
+	// Remember the reminders so the CLA managers are only notified once per entry
+	_, reminderSentDate := utils.CurrentTime()
+	var updated []*models.ApprovalListExpiration
+	for _, expiration := range sig.ApprovalListExpirations {
+		entry := *expiration
+		for _, reminded := range expiring {
+			if reminded.ListType == entry.ListType && reminded.Value == entry.Value {
+				entry.ReminderSentDate = reminderSentDate
+			}
+		}
+		updated = append(updated, &entry)
+	}
+	if _, err := s.signaturesRepo.UpdateApprovalListExpirations(sig.SignatureID.String(), sig.RecordVersion, updated); err != nil {
+		return len(expired), len(expiring), err
+	}
+
+	return len(expired), len(expiring), nil
+}
+
+// removeExpiredEntries removes the expired entries from the approval lists, records the revision and logs an event
+// for each of the entries
+func (s service) removeExpiredEntries(sig *models.Signature, companyModel *models.Company, projectModel *models.Project, expired []*models.ApprovalListExpiration) (*models.Signature, error) {
+	recordVersion := sig.RecordVersion
+	params := &models.ApprovalList{
+		RecordVersion: &recordVersion,
+	}
+	for _, expiration := range expired {
+		switch expiration.ListType {
+		case signatures.ApprovalListTypeEmail:
+			params.RemoveEmailApprovalList = append(params.RemoveEmailApprovalList, expiration.Value)
+		case signatures.ApprovalListTypeDomain:
+			params.RemoveDomainApprovalList = append(params.RemoveDomainApprovalList, expiration.Value)
+		case signatures.ApprovalListTypeGithubUsername:
+			params.RemoveGithubUsernameApprovalList = append(params.RemoveGithubUsernameApprovalList, expiration.Value)
+		case signatures.ApprovalListTypeGithubOrg:
+			params.RemoveGithubOrgApprovalList = append(params.RemoveGithubOrgApprovalList, expiration.Value)
+		case signatures.ApprovalListTypeGithubTeam:
+			params.RemoveGithubTeamApprovalList = append(params.RemoveGithubTeamApprovalList, expiration.Value)
+		}
+	}
+
+	updatedSig, err := s.signaturesRepo.UpdateApprovalList(sig.ProjectID, sig.SignatureReferenceID.String(), params)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.revisionsService != nil && updatedSig.RecordVersion > sig.RecordVersion {
+		if _, revisionErr := s.revisionsService.CreateRevision(sig, updatedSig, systemUser); revisionErr != nil {
+			log.Warnf("unable to record the approval list revision for signature ID: %s, error: %+v", updatedSig.SignatureID, revisionErr)
+		}
+	}
+
+	for _, expiration := range expired {
+		s.eventsService.LogEvent(&events.LogEventArgs{
+			EventType:         events.ApprovalListEntryExpired,
+			ProjectID:         projectModel.ProjectID,
+			ProjectModel:      projectModel,
+			CompanyID:         companyModel.CompanyID,
+			CompanyModel:      companyModel,
+			UserModel:         systemUser,
+			ExternalProjectID: projectModel.ProjectExternalID,
+			EventData: &events.ApprovalListEntryExpiredEventData{
+				ListType:       expiration.ListType,
+				Value:          expiration.Value,
+				ExpirationDate: expiration.ExpirationDate,
+			},
+		})
+	}
+
+	return updatedSig, nil
+}
+
+// sendExpirationReminderEmail notifies the CLA manager of the approval list entries which expire soon, returns an
+// error if the reminder was not sent
+func (s service) sendExpirationReminderEmail(companyModel *models.Company, projectModel *models.Project, claManager models.User, expiring []*models.ApprovalListExpiration) error {
+	recipientAddress := claManager.LfEmail
+	if recipientAddress == "" && len(claManager.Emails) > 0 {
+		recipientAddress = claManager.Emails[0]
+	}
+	f := logrus.Fields{
+		"function":          "sendExpirationReminderEmail",
+		"projectName":       projectModel.ProjectName,
+		"projectExternalID": projectModel.ProjectExternalID,
+		"companyName":       companyModel.CompanyName,
+		"companyExternalID": companyModel.CompanyExternalID,
+		"recipientName":     claManager.Username,
+		"recipientAddress":  recipientAddress}
+	if recipientAddress == "" {
+		log.WithFields(f).Warn("unable to send the approval list expiration reminder - CLA manager has no email address")
+		return fmt.Errorf("CLA manager %s has no email address", claManager.Username)
+	}
+
+	var entries strings.Builder
+	entries.WriteString("<ul>")
+	for _, expiration := range expiring {
+		entries.WriteString(fmt.Sprintf("<li>%s %s - expires on %s</li>", expiration.ListType, expiration.Value, expiration.ExpirationDate))
+	}
+	entries.WriteString("</ul>")
+
+	subject := fmt.Sprintf("EasyCLA: Approval List Entries Expiring for %s on %s", companyModel.CompanyName, projectModel.ProjectName)
+	recipients := []string{recipientAddress}
+	body := fmt.Sprintf(`
+<p>Hello %s,</p>
+<p>This is a notification email from EasyCLA regarding the project %s.</p>
+<p>The following entries of the EasyCLA approval list for %s for project %s will expire within the next %d days:</p>
+%s
+<p>Once an entry expires it is removed from the approval list and the contributors it covered will no longer be
+authorized to contribute on behalf of %s. To keep an entry, update its expiration date in the approval list.</p>
+%s
+%s`,
+		claManager.Username, projectModel.ProjectName, companyModel.CompanyName, projectModel.ProjectName, s.reminderDays,
+		entries.String(), companyModel.CompanyName,
+		utils.GetEmailHelpContent(projectModel.Version == utils.V2), utils.GetEmailSignOffContent())
+
+	err := utils.SendEmail(subject, body, recipients)
+	if err != nil {
+		log.WithFields(f).Warnf("problem sending email with subject: %s to recipients: %+v, error: %+v", subject, recipients, err)
+		return err
+	}
+	log.WithFields(f).Debugf("sent email with subject: %s to recipients: %+v", subject, recipients)
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package approval_list_revisions
+
+// ApprovalListEntries holds the entries of the signature approval lists
+type ApprovalListEntries struct {
+	EmailApprovalList          []string `json:"email_whitelist,omitempty"`
+	DomainApprovalList         []string `json:"domain_whitelist,omitempty"`
+	GithubUsernameApprovalList []string `json:"github_whitelist,omitempty"`
+	GithubOrgApprovalList      []string `json:"github_org_whitelist,omitempty"`
+	GithubTeamApprovalList     []string `json:"github_team_whitelist,omitempty"`
+}
+
+// Revision is an immutable record of a single change to the approval lists of a signature. The revision number
+// matches the signature record version after the change was applied.
+type Revision struct {
+	SignatureID     string              `json:"signature_id"`
+	RevisionNumber  int64               `json:"revision_number"`
+	ClaGroupID      string              `json:"cla_group_id"`
+	CompanyID       string              `json:"company_id"`
+	ActorUserID     string              `json:"actor_user_id"`
+	ActorUsername   string              `json:"actor_username"`
+	ActorLFUsername string              `json:"actor_lf_username"`
+	ActorEmail      string              `json:"actor_email"`
+	DateCreated     string              `json:"date_created"`
+	Added           ApprovalListEntries `json:"added"`
+	Removed         ApprovalListEntries `json:"removed"`
+	ApprovalList    ApprovalListEntries `json:"approval_list"`
+}
+
+// RevisionDiff is the difference between the approval lists of two revisions
+type RevisionDiff struct {
+	SignatureID  string
+	FromRevision int64
+	ToRevision   int64
+	Added        ApprovalListEntries
+	Removed      ApprovalListEntries
+}
+
+// ApprovalListSnapshot is the approval list of a signature as of a point in time. The revision number is the latest
+// revision at that time - zero when the approval list was not changed since the revision history was introduced.
+type ApprovalListSnapshot struct {
+	SignatureID    string
+	AsOf           string
+	RevisionNumber int64
+	// SignatureExists is false when the signature was created after the point in time
+	SignatureExists bool
+	ApprovalList    ApprovalListEntries
+}
+
+// difference returns the entries of a which are not in b
+func difference(a, b ApprovalListEntries) ApprovalListEntries {
+	return ApprovalListEntries{
+		EmailApprovalList:          listDifference(a.EmailApprovalList, b.EmailApprovalList),
+		DomainApprovalList:         listDifference(a.DomainApprovalList, b.DomainApprovalList),
+		GithubUsernameApprovalList: listDifference(a.GithubUsernameApprovalList, b.GithubUsernameApprovalList),
+		GithubOrgApprovalList:      listDifference(a.GithubOrgApprovalList, b.GithubOrgApprovalList),
+		GithubTeamApprovalList:     listDifference(a.GithubTeamApprovalList, b.GithubTeamApprovalList),
+	}
+}
+
+// union returns the entries of both a and b
+func union(a, b ApprovalListEntries) ApprovalListEntries {
+	return ApprovalListEntries{
+		EmailApprovalList:          listUnion(a.EmailApprovalList, b.EmailApprovalList),
+		DomainApprovalList:         listUnion(a.DomainApprovalList, b.DomainApprovalList),
+		GithubUsernameApprovalList: listUnion(a.GithubUsernameApprovalList, b.GithubUsernameApprovalList),
+		GithubOrgApprovalList:      listUnion(a.GithubOrgApprovalList, b.GithubOrgApprovalList),
+		GithubTeamApprovalList:     listUnion(a.GithubTeamApprovalList, b.GithubTeamApprovalList),
+	}
+}
+
+func listDifference(a, b []string) []string {
+	exclude := make(map[string]struct{}, len(b))
+	for _, value := range b {
+		exclude[value] = struct{}{}
+	}
+	var result []string
+	for _, value := range a {
+		if _, ok := exclude[value]; !ok {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func listUnion(a, b []string) []string {
+	result := listDifference(a, nil)
+	return append(result, listDifference(b, a)...)
+}
@@ -0,0 +1,140 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package approval_list_revisions
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// errors
+var (
+	ErrRevisionNotFound      = errors.New("approval list revision not found")
+	ErrRevisionAlreadyExists = errors.New("approval list revision already exists")
+)
+
+// Repository defines the functions of the approval list revision repository
+type Repository interface {
+	CreateRevision(revision *Revision) error
+	GetRevisions(signatureID string) ([]*Revision, error)
+	GetRevision(signatureID string, revisionNumber int64) (*Revision, error)
+}
+
+// NewRepository creates a new instance of the approval list revision repository
+func NewRepository(awsSession *session.Session, stage string) Repository {
+	return &repo{
+		stage:          stage,
+		dynamoDBClient: dynamodb.New(awsSession),
+		tableName:      fmt.Sprintf("cla-%s-approval-list-revisions", stage),
+	}
+}
+
+type repo struct {
+	stage          string
+	dynamoDBClient *dynamodb.DynamoDB
+	tableName      string
+}
+
+// CreateRevision stores the revision - revisions are immutable, storing the same revision number twice fails with
+// ErrRevisionAlreadyExists
+func (repo *repo) CreateRevision(revision *Revision) error {
+	av, err := dynamodbattribute.MarshalMap(revision)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(repo.tableName),
+		ConditionExpression: aws.String("attribute_not_exists(signature_id)"),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrRevisionAlreadyExists
+		}
+		log.Warnf("unable to create approval list revision %d for signature ID: %s, error: %v",
+			revision.RevisionNumber, revision.SignatureID, err)
+		return err
+	}
+
+	return nil
+}
+
+// GetRevisions returns the revisions of the signature approval lists ordered by revision number
+func (repo *repo) GetRevisions(signatureID string) ([]*Revision, error) {
+	condition := expression.Key("signature_id").Equal(expression.Value(signatureID))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.Warnf("error building expression for approval list revision query, signatureID: %s, error: %v",
+			signatureID, err)
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(repo.tableName),
+	}
+
+	var revisions []*Revision
+	for {
+		results, queryErr := repo.dynamoDBClient.Query(queryInput)
+		if queryErr != nil {
+			log.Warnf("error retrieving approval list revisions for signature ID: %s, error: %v", signatureID, queryErr)
+			return nil, queryErr
+		}
+
+		var page []*Revision
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &page)
+		if err != nil {
+			log.Warnf("error unmarshalling approval list revisions for signature ID: %s, error: %v", signatureID, err)
+			return nil, err
+		}
+		revisions = append(revisions, page...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return revisions, nil
+}
+
+// GetRevision returns the specified revision of the signature approval lists
+func (repo *repo) GetRevision(signatureID string, revisionNumber int64) (*Revision, error) {
+	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"signature_id":    {S: aws.String(signatureID)},
+			"revision_number": {N: aws.String(strconv.FormatInt(revisionNumber, 10))},
+		},
+		TableName: aws.String(repo.tableName),
+	})
+	if err != nil {
+		log.Warnf("error retrieving approval list revision %d for signature ID: %s, error: %v", revisionNumber, signatureID, err)
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrRevisionNotFound
+	}
+
+	var revision Revision
+	err = dynamodbattribute.UnmarshalMap(result.Item, &revision)
+	if err != nil {
+		log.Warnf("error unmarshalling approval list revision %d for signature ID: %s, error: %v", revisionNumber, signatureID, err)
+		return nil, err
+	}
+
+	return &revision, nil
+}
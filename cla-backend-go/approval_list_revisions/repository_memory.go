@@ -0,0 +1,63 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package approval_list_revisions
+
+import (
+	"fmt"
+
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+)
+
+// NewMemoryRepository creates a new approval list revision repository backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string) Repository {
+	return &memoryRepo{
+		store:     store,
+		tableName: fmt.Sprintf("cla-%s-approval-list-revisions", stage),
+	}
+}
+
+type memoryRepo struct {
+	store     *storage.MemoryStore
+	tableName string
+}
+
+// revisionKey returns the store key of the revision - the revision number is zero padded so the store returns the
+// revisions of a signature ordered by revision number
+func revisionKey(signatureID string, revisionNumber int64) string {
+	return fmt.Sprintf("%s#%020d", signatureID, revisionNumber)
+}
+
+func (repo *memoryRepo) CreateRevision(revision *Revision) error {
+	err := repo.store.Create(repo.tableName, revisionKey(revision.SignatureID, revision.RevisionNumber), revision)
+	if err == storage.ErrItemAlreadyExists {
+		return ErrRevisionAlreadyExists
+	}
+	return err
+}
+
+func (repo *memoryRepo) GetRevisions(signatureID string) ([]*Revision, error) {
+	var revisions []*Revision
+	if err := repo.store.Scan(repo.tableName, &revisions); err != nil {
+		return nil, err
+	}
+	var result []*Revision
+	for _, revision := range revisions {
+		if revision.SignatureID == signatureID {
+			result = append(result, revision)
+		}
+	}
+	return result, nil
+}
+
+func (repo *memoryRepo) GetRevision(signatureID string, revisionNumber int64) (*Revision, error) {
+	var revision Revision
+	found, err := repo.store.Get(repo.tableName, revisionKey(signatureID, revisionNumber), &revision)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrRevisionNotFound
+	}
+	return &revision, nil
+}
@@ -0,0 +1,201 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package approval_list_revisions
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Service defines the functions of the approval list revision service
+type Service interface {
+	CreateRevision(previous, updated *models.Signature, actor *models.User) (*Revision, error)
+	GetRevisions(signatureID string) ([]*Revision, error)
+	GetRevisionDiff(signatureID string, fromRevision, toRevision int64) (*RevisionDiff, error)
+	GetApprovalListAsOf(signature *models.Signature, asOf time.Time) (*ApprovalListSnapshot, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new instance of the approval list revision service
+func NewService(repo Repository) Service {
+	return service{
+		repo: repo,
+	}
+}
+
+// CreateRevision records the change between the previous and the updated signature approval lists made by the actor
+func (s service) CreateRevision(previous, updated *models.Signature, actor *models.User) (*Revision, error) {
+	f := logrus.Fields{
+		"functionName":   "CreateRevision",
+		"signatureID":    updated.SignatureID,
+		"revisionNumber": updated.RecordVersion,
+	}
+
+	revisions, err := s.repo.GetRevisions(updated.SignatureID.String())
+	if err != nil {
+		log.WithFields(f).Warnf("unable to load the approval list revisions, error: %+v", err)
+		return nil, err
+	}
+
+	// The previous signature is exact unless another request modified the record in between, in which case the latest
+	// recorded revision is the best known previous state
+	previousList := toApprovalListEntries(previous)
+	if previous == nil || previous.RecordVersion != updated.RecordVersion-1 {
+		for _, revision := range revisions {
+			if revision.RevisionNumber < updated.RecordVersion {
+				previousList = revision.ApprovalList
+			}
+		}
+	}
+
+	updatedList := toApprovalListEntries(updated)
+	_, now := utils.CurrentTime()
+	revision := &Revision{
+		SignatureID:    updated.SignatureID.String(),
+		RevisionNumber: updated.RecordVersion,
+		ClaGroupID:     updated.ProjectID,
+		CompanyID:      updated.SignatureReferenceID.String(),
+		DateCreated:    now,
+		Added:          difference(updatedList, previousList),
+		Removed:        difference(previousList, updatedList),
+		ApprovalList:   updatedList,
+	}
+	if actor != nil {
+		revision.ActorUserID = actor.UserID
+		revision.ActorUsername = actor.Username
+		revision.ActorLFUsername = actor.LfUsername
+		revision.ActorEmail = actor.LfEmail
+	}
+
+	err = s.repo.CreateRevision(revision)
+	if err != nil {
+		log.WithFields(f).Warnf("unable to store the approval list revision, error: %+v", err)
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+// GetRevisions returns the approval list revisions of the signature ordered by revision number
+func (s service) GetRevisions(signatureID string) ([]*Revision, error) {
+	revisions, err := s.repo.GetRevisions(signatureID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].RevisionNumber < revisions[j].RevisionNumber
+	})
+	return revisions, nil
+}
+
+// GetRevisionDiff returns the entries added and removed between the two revisions - revision zero is the approval list
+// before the first recorded revision
+func (s service) GetRevisionDiff(signatureID string, fromRevision, toRevision int64) (*RevisionDiff, error) {
+	revisions, err := s.GetRevisions(signatureID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromList, err := approvalListAtRevision(revisions, fromRevision)
+	if err != nil {
+		return nil, err
+	}
+	toList, err := approvalListAtRevision(revisions, toRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RevisionDiff{
+		SignatureID:  signatureID,
+		FromRevision: fromRevision,
+		ToRevision:   toRevision,
+		Added:        difference(toList, fromList),
+		Removed:      difference(fromList, toList),
+	}, nil
+}
+
+// GetApprovalListAsOf reconstructs the approval list of the signature as of the specified point in time
+func (s service) GetApprovalListAsOf(signature *models.Signature, asOf time.Time) (*ApprovalListSnapshot, error) {
+	snapshot := &ApprovalListSnapshot{
+		SignatureID:     signature.SignatureID.String(),
+		AsOf:            utils.TimeToString(asOf),
+		SignatureExists: true,
+	}
+
+	// Nothing was approved before the signature was created
+	created, err := utils.ParseDateTime(signature.SignatureCreated)
+	if err != nil {
+		log.Warnf("unable to parse the created date: %s of signature ID: %s, error: %+v",
+			signature.SignatureCreated, signature.SignatureID, err)
+	} else if created.After(asOf) {
+		snapshot.SignatureExists = false
+		return snapshot, nil
+	}
+
+	revisions, err := s.GetRevisions(signature.SignatureID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a revision history the current approval list has been in place since the signature was created
+	if len(revisions) == 0 {
+		snapshot.ApprovalList = toApprovalListEntries(signature)
+		return snapshot, nil
+	}
+
+	for _, revision := range revisions {
+		revisionDate, parseErr := utils.ParseDateTime(revision.DateCreated)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		if revisionDate.After(asOf) {
+			break
+		}
+		snapshot.RevisionNumber = revision.RevisionNumber
+	}
+
+	snapshot.ApprovalList, err = approvalListAtRevision(revisions, snapshot.RevisionNumber)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// approvalListAtRevision returns the approval list after the specified revision, revision zero is derived from the
+// first revision by reverting its changes
+func approvalListAtRevision(revisions []*Revision, revisionNumber int64) (ApprovalListEntries, error) {
+	if revisionNumber == 0 && len(revisions) > 0 {
+		first := revisions[0]
+		return union(difference(first.ApprovalList, first.Added), first.Removed), nil
+	}
+	for _, revision := range revisions {
+		if revision.RevisionNumber == revisionNumber {
+			return revision.ApprovalList, nil
+		}
+	}
+	return ApprovalListEntries{}, fmt.Errorf("%w: revision %d", ErrRevisionNotFound, revisionNumber)
+}
+
+// toApprovalListEntries returns the approval list entries of the signature
+func toApprovalListEntries(signature *models.Signature) ApprovalListEntries {
+	if signature == nil {
+		return ApprovalListEntries{}
+	}
+	return ApprovalListEntries{
+		EmailApprovalList:          signature.EmailApprovalList,
+		DomainApprovalList:         signature.DomainApprovalList,
+		GithubUsernameApprovalList: signature.GithubUsernameApprovalList,
+		GithubOrgApprovalList:      signature.GithubOrgApprovalList,
+		GithubTeamApprovalList:     signature.GithubTeamApprovalList,
+	}
+}
@@ -5,6 +5,13 @@ package cla_manager
 
 import "github.com/communitybridge/easycla/cla-backend-go/gen/models"
 
+// CLA Manager request statuses
+const (
+	StatusPending = "pending"
+	// StatusExpired is the terminal status of a request which was not reviewed in time
+	StatusExpired = "expired"
+)
+
 // CLAManagerRequests data model
 type CLAManagerRequests struct {
 	Requests []CLAManagerRequest
@@ -26,6 +33,8 @@ type CLAManagerRequest struct {
 	Status            string `json:"status"`
 	Created           string `json:"date_created"`
 	Updated           string `json:"date_modified"`
+	ReminderCount     int    `json:"reminder_count"`
+	LastReminderDate  string `json:"last_reminder_date"`
 }
 
 // dbModelToServiceModel converts a database model to a service model
@@ -45,5 +54,7 @@ func dbModelToServiceModel(dbModel CLAManagerRequest) models.ClaManagerRequest {
 		Status:            dbModel.Status,
 		Created:           dbModel.Created,
 		Updated:           dbModel.Updated,
+		ReminderCount:     int64(dbModel.ReminderCount),
+		LastReminderDate:  dbModel.LastReminderDate,
 	}
 }
@@ -6,6 +6,7 @@ package cla_manager
 import (
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 
@@ -19,6 +20,9 @@ import (
 	"github.com/gofrs/uuid"
 )
 
+// ErrRequestNotPending is returned when a request is expected to be pending but was already reviewed
+var ErrRequestNotPending = errors.New("cla manager request is no longer pending")
+
 // IRepository interface methods
 type IRepository interface { //nolint
 	CreateRequest(reqModel *CLAManagerRequest) (*CLAManagerRequest, error)
@@ -30,6 +34,9 @@ type IRepository interface { //nolint
 	DenyRequest(companyID, projectID, requestID string) (*CLAManagerRequest, error)
 	PendingRequest(companyID, projectID, requestID string) (*CLAManagerRequest, error)
 	DeleteRequest(requestID string) error
+	GetPendingRequests() (*CLAManagerRequests, error)
+	UpdateRequestReminder(requestID string, reminderCount int) error
+	ExpireRequest(companyID, projectID, requestID string) (*CLAManagerRequest, error)
 	updateRequestStatus(companyID, projectID, requestID, status string) (*CLAManagerRequest, error)
 }
 
@@ -335,6 +342,8 @@ func (repo repository) updateRequestStatus(companyID, projectID, requestID, stat
 		ExpressionAttributeNames: map[string]*string{
 			"#S": aws.String("status"),
 			"#M": aws.String("date_modified"),
+			"#C": aws.String("reminder_count"),
+			"#R": aws.String("last_reminder_date"),
 		},
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
 			":s": {
@@ -344,7 +353,8 @@ func (repo repository) updateRequestStatus(companyID, projectID, requestID, stat
 				S: aws.String(now),
 			},
 		},
-		UpdateExpression: aws.String("SET #S = :s, #M = :m"),
+		// The reminders start over when the request is reopened
+		UpdateExpression: aws.String("SET #S = :s, #M = :m REMOVE #C, #R"),
 		TableName:        aws.String(fmt.Sprintf("cla-%s-cla-manager-requests", repo.stage)),
 	}
 
@@ -381,6 +391,143 @@ func (repo repository) PendingRequest(companyID, projectID, requestID string) (*
 	return repo.updateRequestStatus(companyID, projectID, requestID, "pending")
 }
 
+// GetPendingRequests returns all the pending requests across the companies and projects
+func (repo repository) GetPendingRequests() (*CLAManagerRequests, error) {
+	tableName := fmt.Sprintf("cla-%s-cla-manager-requests", repo.stage)
+
+	filter := expression.Name("status").Equal(expression.Value(StatusPending))
+	expr, err := expression.NewBuilder().
+		WithFilter(filter).
+		WithProjection(buildRequestProjection()).
+		Build()
+	if err != nil {
+		log.Warnf("error building expression for pending cla manager request scan, error: %v", err)
+		return nil, err
+	}
+
+	scanInput := &dynamodb.ScanInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		TableName:                 aws.String(tableName),
+	}
+
+	var requests []CLAManagerRequest
+	for {
+		results, errScan := repo.dynamoDBClient.Scan(scanInput)
+		if errScan != nil {
+			log.Warnf("error scanning for pending cla manager requests, error: %v", errScan)
+			return nil, errScan
+		}
+
+		var page []CLAManagerRequest
+		unmarshallErr := dynamodbattribute.UnmarshalListOfMaps(results.Items, &page)
+		if unmarshallErr != nil {
+			log.Warnf("error converting DB model for pending cla manager requests, error: %v", unmarshallErr)
+			return nil, unmarshallErr
+		}
+		requests = append(requests, page...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return &CLAManagerRequests{
+		Requests: requests,
+	}, nil
+}
+
+// UpdateRequestReminder records the number of reminders sent for the pending request - the modified date is left
+// alone as it tells since when the request is pending
+func (repo repository) UpdateRequestReminder(requestID string, reminderCount int) error {
+	_, now := utils.CurrentTime()
+
+	input := &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"request_id": {
+				S: aws.String(requestID),
+			},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#S": aws.String("status"),
+			"#C": aws.String("reminder_count"),
+			"#R": aws.String("last_reminder_date"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":p": {
+				S: aws.String(StatusPending),
+			},
+			":c": {
+				N: aws.String(strconv.Itoa(reminderCount)),
+			},
+			":r": {
+				S: aws.String(now),
+			},
+		},
+		ConditionExpression: aws.String("#S = :p"),
+		UpdateExpression:    aws.String("SET #C = :c, #R = :r"),
+		TableName:           aws.String(fmt.Sprintf("cla-%s-cla-manager-requests", repo.stage)),
+	}
+
+	_, err := repo.dynamoDBClient.UpdateItem(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrRequestNotPending
+		}
+		log.Warnf("CLA Manager UpdateRequestReminder - unable to update the reminder of request ID: %s, error: %v",
+			requestID, err)
+		return err
+	}
+	return nil
+}
+
+// ExpireRequest moves the specified pending request to the expired status - returns ErrRequestNotPending if the
+// request was reviewed in the meantime
+func (repo repository) ExpireRequest(companyID, projectID, requestID string) (*CLAManagerRequest, error) {
+	_, now := utils.CurrentTime()
+
+	input := &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"request_id": {
+				S: aws.String(requestID),
+			},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#S": aws.String("status"),
+			"#M": aws.String("date_modified"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":p": {
+				S: aws.String(StatusPending),
+			},
+			":s": {
+				S: aws.String(StatusExpired),
+			},
+			":m": {
+				S: aws.String(now),
+			},
+		},
+		ConditionExpression: aws.String("#S = :p"),
+		UpdateExpression:    aws.String("SET #S = :s, #M = :m"),
+		TableName:           aws.String(fmt.Sprintf("cla-%s-cla-manager-requests", repo.stage)),
+	}
+
+	_, err := repo.dynamoDBClient.UpdateItem(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return nil, ErrRequestNotPending
+		}
+		log.Warnf("CLA Manager ExpireRequest - unable to update request with '%s' status for request ID: %s, company ID: %s, project ID: %s, error: %v",
+			StatusExpired, requestID, companyID, projectID, err)
+		return nil, err
+	}
+
+	return repo.GetRequest(requestID)
+}
+
 // buildRequestProjection returns the database field projection for the table
 func buildRequestProjection() expression.ProjectionBuilder {
 	// These are the columns we want returned
@@ -399,5 +546,7 @@ func buildRequestProjection() expression.ProjectionBuilder {
 		expression.Name("status"),
 		expression.Name("date_created"),
 		expression.Name("date_modified"),
+		expression.Name("reminder_count"),
+		expression.Name("last_reminder_date"),
 	)
 }
@@ -0,0 +1,186 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package cla_manager
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+)
+
+type memoryRepository struct {
+	store     *storage.MemoryStore
+	tableName string
+}
+
+// NewMemoryRepository creates a new instance of the CLA Manager request repository backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string) IRepository {
+	return memoryRepository{
+		store:     store,
+		tableName: fmt.Sprintf("cla-%s-cla-manager-requests", stage),
+	}
+}
+
+// CreateRequest generates a new CLA manager request
+func (repo memoryRepository) CreateRequest(reqModel *CLAManagerRequest) (*CLAManagerRequest, error) {
+	requestID, err := uuid.NewV4()
+	if err != nil {
+		log.Warnf("Unable to generate a UUID for a pending invite, error: %v", err)
+		return nil, err
+	}
+
+	_, now := utils.CurrentTime()
+	request := CLAManagerRequest{
+		RequestID:         requestID.String(),
+		CompanyID:         reqModel.CompanyID,
+		CompanyExternalID: reqModel.CompanyExternalID,
+		CompanyName:       reqModel.CompanyName,
+		ProjectID:         reqModel.ProjectID,
+		ProjectExternalID: reqModel.ProjectExternalID,
+		ProjectName:       reqModel.ProjectName,
+		UserID:            reqModel.UserID,
+		UserExternalID:    reqModel.UserExternalID,
+		UserName:          reqModel.UserName,
+		UserEmail:         reqModel.UserEmail,
+		Status:            "pending",
+		Created:           now,
+		Updated:           now,
+	}
+	if err := repo.store.Put(repo.tableName, request.RequestID, request); err != nil {
+		log.Warnf("unable to create a new CLA Manager request, error: %v", err)
+		return nil, err
+	}
+	return repo.GetRequest(request.RequestID)
+}
+
+// GetRequests returns the CLA manager requests for the company and project
+func (repo memoryRepository) GetRequests(companyID, projectID string) (*CLAManagerRequests, error) {
+	return repo.scan(func(request CLAManagerRequest) bool {
+		return request.CompanyID == companyID && request.ProjectID == projectID
+	})
+}
+
+// GetRequestsByUserID returns the CLA manager requests for the company, project and user
+func (repo memoryRepository) GetRequestsByUserID(companyID, projectID, userID string) (*CLAManagerRequests, error) {
+	return repo.scan(func(request CLAManagerRequest) bool {
+		return request.CompanyID == companyID && request.ProjectID == projectID && strings.Contains(request.UserID, userID)
+	})
+}
+
+// GetRequest returns the CLA manager request by ID - returns nil if the request does not exist
+func (repo memoryRepository) GetRequest(requestID string) (*CLAManagerRequest, error) {
+	var request CLAManagerRequest
+	found, err := repo.store.Get(repo.tableName, requestID, &request)
+	if err != nil {
+		log.Warnf("error loading cla manager request using request ID: %s, error: %v", requestID, err)
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &request, nil
+}
+
+// DeleteRequest deletes the CLA manager request
+func (repo memoryRepository) DeleteRequest(requestID string) error {
+	return repo.store.Delete(repo.tableName, requestID)
+}
+
+func (repo memoryRepository) updateRequestStatus(companyID, projectID, requestID, status string) (*CLAManagerRequest, error) {
+	_, now := utils.CurrentTime()
+	var request CLAManagerRequest
+	err := repo.store.Update(repo.tableName, requestID, &request, func() error {
+		request.Status = status
+		request.Updated = now
+		// The reminders start over when the request is reopened
+		request.ReminderCount = 0
+		request.LastReminderDate = ""
+		return nil
+	})
+	if err == storage.ErrItemNotFound {
+		log.Warnf("CLA Manager updateRequestStatus - unable to locate previous request with request ID: %s, company ID: %s, project ID: %s",
+			requestID, companyID, projectID)
+		return nil, nil
+	}
+	if err != nil {
+		log.Warnf("CLA Manager updateRequestStatus - unable to update request with '%s' status for request ID: %s, company ID: %s, project ID: %s, error: %v",
+			status, requestID, companyID, projectID, err)
+		return nil, err
+	}
+	return &request, nil
+}
+
+// ApproveRequest approves the CLA manager request
+func (repo memoryRepository) ApproveRequest(companyID, projectID, requestID string) (*CLAManagerRequest, error) {
+	return repo.updateRequestStatus(companyID, projectID, requestID, "approved")
+}
+
+// DenyRequest denies the CLA manager request
+func (repo memoryRepository) DenyRequest(companyID, projectID, requestID string) (*CLAManagerRequest, error) {
+	return repo.updateRequestStatus(companyID, projectID, requestID, "denied")
+}
+
+// PendingRequest moves the CLA manager request back into the pending state
+func (repo memoryRepository) PendingRequest(companyID, projectID, requestID string) (*CLAManagerRequest, error) {
+	return repo.updateRequestStatus(companyID, projectID, requestID, "pending")
+}
+
+// GetPendingRequests returns all the pending CLA manager requests across the companies and projects
+func (repo memoryRepository) GetPendingRequests() (*CLAManagerRequests, error) {
+	return repo.scan(func(request CLAManagerRequest) bool {
+		return request.Status == StatusPending
+	})
+}
+
+// UpdateRequestReminder records the number of reminders sent for the pending CLA manager request
+func (repo memoryRepository) UpdateRequestReminder(requestID string, reminderCount int) error {
+	_, now := utils.CurrentTime()
+	var request CLAManagerRequest
+	return repo.store.Update(repo.tableName, requestID, &request, func() error {
+		if request.Status != StatusPending {
+			return ErrRequestNotPending
+		}
+		request.ReminderCount = reminderCount
+		request.LastReminderDate = now
+		return nil
+	})
+}
+
+// ExpireRequest moves the pending CLA manager request to the expired status
+func (repo memoryRepository) ExpireRequest(companyID, projectID, requestID string) (*CLAManagerRequest, error) {
+	_, now := utils.CurrentTime()
+	var request CLAManagerRequest
+	err := repo.store.Update(repo.tableName, requestID, &request, func() error {
+		if request.Status != StatusPending {
+			return ErrRequestNotPending
+		}
+		request.Status = StatusExpired
+		request.Updated = now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (repo memoryRepository) scan(filter func(request CLAManagerRequest) bool) (*CLAManagerRequests, error) {
+	var all []CLAManagerRequest
+	if err := repo.store.Scan(repo.tableName, &all); err != nil {
+		return nil, err
+	}
+	var requests []CLAManagerRequest
+	for _, request := range all {
+		if filter(request) {
+			requests = append(requests, request)
+		}
+	}
+	return &CLAManagerRequests{
+		Requests: requests,
+	}, nil
+}
@@ -0,0 +1,147 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list_revisions"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	claevents "github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/user"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/export_jobs"
+	v2Signatures "github.com/communitybridge/easycla/cla-backend-go/v2/signatures"
+)
+
+var (
+	// version the application version
+	version string
+
+	// build/Commit the application build number
+	commit string
+
+	// branch the build branch
+	branch string
+
+	// build date
+	buildDate string
+)
+
+var exportJobsService export_jobs.Service
+
+func init() {
+	var awsSession = session.Must(session.NewSession(&aws.Config{}))
+	stage := os.Getenv("STAGE")
+	if stage == "" {
+		log.Fatal("stage not set")
+	}
+	log.Infof("STAGE set to %s\n", stage)
+	configFile, err := config.LoadConfig("", awsSession, stage)
+	if err != nil {
+		log.Panicf("Unable to load config - Error: %v", err)
+	}
+
+	usersRepo := users.NewRepository(awsSession, stage)
+	companyRepo := company.NewRepository(awsSession, stage)
+	signaturesRepo := signatures.NewRepository(awsSession, stage, companyRepo, usersRepo)
+	projectClaGroupRepo := projects_cla_groups.NewRepository(awsSession, stage)
+	repositoriesRepo := repositories.NewRepository(awsSession, stage)
+	gerritRepo := gerrits.NewRepository(awsSession, stage)
+	projectRepo := project.NewRepository(awsSession, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
+	eventsRepo := claevents.NewRepository(awsSession, stage)
+	exportJobsRepo := export_jobs.NewRepository(awsSession, stage)
+
+	type combinedRepo struct {
+		users.UserRepository
+		company.IRepository
+		project.ProjectRepository
+	}
+	eventsService := claevents.NewService(eventsRepo, combinedRepo{
+		usersRepo,
+		companyRepo,
+		projectRepo,
+	})
+	usersService := users.NewService(usersRepo, eventsService)
+	companyService := company.NewService(companyRepo, configFile.CorporateConsoleURL, user.NewDynamoRepository(awsSession, stage), usersService)
+	projectService := project.NewService(projectRepo, repositoriesRepo, gerritRepo, projectClaGroupRepo)
+	signaturesService := signatures.NewService(signaturesRepo, companyService, usersService, eventsService,
+		approval_list_revisions.NewService(approval_list_revisions.NewRepository(awsSession, stage)), false, nil)
+	blobStore, err := storage.NewBlobStore(awsSession, configFile.BlobStorage, configFile.SignatureFilesBucket)
+	if err != nil {
+		log.Panicf("Unable to create the blob store - Error: %v", err)
+	}
+	v2SignatureService := v2Signatures.NewService(blobStore, projectService, companyService, signaturesService, projectClaGroupRepo)
+
+	utils.SetBlobStorage(blobStore)
+	// the jobs are run by this lambda, they are never dispatched from here
+	exportJobsService = export_jobs.NewService(exportJobsRepo, companyRepo, v2SignatureService, eventsService,
+		v2Signatures.NewZipBuilder(blobStore, signaturesRepo),
+		export_jobs.NewBlobArtifactStore(blobStore), nil, export_jobs.DefaultRetention)
+}
+
+func handler(ctx context.Context, event export_jobs.JobEvent) error {
+	if event.Cleanup {
+		summary, err := exportJobsService.CleanupExpiredJobs(time.Now().UTC())
+		if err != nil {
+			log.Warnf("Unable to clean up the expired export jobs. error = %s", err)
+			return err
+		}
+		log.Infof("expired %d export jobs - %d artifacts deleted", summary.JobsExpired, summary.ArtifactsDeleted)
+		return nil
+	}
+
+	log.WithField("event", event).Debug("export job called")
+	err := exportJobsService.RunJob(event.JobID)
+	switch err {
+	case nil:
+		log.Infof("export job %s succeeded", event.JobID)
+	case export_jobs.ErrJobNotPending, export_jobs.ErrJobNotFound:
+		// nothing to retry
+		log.Warnf("export job %s not run. error = %s", event.JobID, err)
+	default:
+		// the failure is recorded on the job, the job isn't run again
+		log.Warnf("export job %s failed. error = %s", event.JobID, err)
+	}
+	return nil
+}
+
+func printBuildInfo() {
+	log.Infof("Version                 : %s", version)
+	log.Infof("Git commit hash         : %s", commit)
+	log.Infof("Branch                  : %s", branch)
+	log.Infof("Build date              : %s", buildDate)
+}
+
+func main() {
+	log.Info("Lambda server starting...")
+	printBuildInfo()
+	if os.Getenv("LOCAL_MODE") == "true" {
+		event := export_jobs.JobEvent{Cleanup: true}
+		if len(os.Args) == 2 {
+			event = export_jobs.JobEvent{JobID: os.Args[1]}
+		}
+		if err := handler(context.Background(), event); err != nil {
+			log.Fatal(fmt.Sprintf("export job lambda failed: %v", err))
+		}
+	} else {
+		lambda.Start(handler)
+	}
+	log.Infof("Lambda shutting down...")
+}
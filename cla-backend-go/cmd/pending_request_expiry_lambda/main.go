@@ -0,0 +1,133 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list"
+	"github.com/communitybridge/easycla/cla-backend-go/cla_manager"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	claevents "github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/pending_request_expirations"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+var (
+	// version the application version
+	version string
+
+	// build/Commit the application build number
+	commit string
+
+	// branch the build branch
+	branch string
+
+	// build date
+	buildDate string
+)
+
+var expirationService pending_request_expirations.Service
+
+func init() {
+	var awsSession = session.Must(session.NewSession(&aws.Config{}))
+	stage := os.Getenv("STAGE")
+	if stage == "" {
+		log.Fatal("stage not set")
+	}
+	log.Infof("STAGE set to %s\n", stage)
+	configFile, err := config.LoadConfig("", awsSession, stage)
+	if err != nil {
+		log.Panicf("Unable to load config - Error: %v", err)
+	}
+
+	// Number of days a request is pending when the CLA managers are reminded of it, such as 3,7,14
+	reminderDays := pending_request_expirations.DefaultReminderDays
+	if value := os.Getenv("PENDING_REQUEST_REMINDER_DAYS"); value != "" {
+		reminderDays, err = pending_request_expirations.ParseReminderDays(value)
+		if err != nil {
+			log.Fatalf("invalid PENDING_REQUEST_REMINDER_DAYS value: %s", value)
+		}
+	}
+	log.Infof("PENDING_REQUEST_REMINDER_DAYS set to %v\n", reminderDays)
+
+	// Number of days a request is pending when it expires
+	expiryDays := pending_request_expirations.DefaultExpiryDays
+	if value := os.Getenv("PENDING_REQUEST_EXPIRY_DAYS"); value != "" {
+		expiryDays, err = strconv.Atoi(value)
+		if err != nil {
+			log.Fatalf("invalid PENDING_REQUEST_EXPIRY_DAYS value: %s", value)
+		}
+	}
+	log.Infof("PENDING_REQUEST_EXPIRY_DAYS set to %d\n", expiryDays)
+
+	usersRepo := users.NewRepository(awsSession, stage)
+	companyRepo := company.NewRepository(awsSession, stage)
+	signaturesRepo := signatures.NewRepository(awsSession, stage, companyRepo, usersRepo)
+	projectClaGroupRepo := projects_cla_groups.NewRepository(awsSession, stage)
+	repositoriesRepo := repositories.NewRepository(awsSession, stage)
+	gerritRepo := gerrits.NewRepository(awsSession, stage)
+	projectRepo := project.NewRepository(awsSession, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
+	eventsRepo := claevents.NewRepository(awsSession, stage)
+	approvalListRepo := approval_list.NewRepository(awsSession, stage)
+	claManagerRepo := cla_manager.NewRepository(awsSession, stage)
+
+	type combinedRepo struct {
+		users.UserRepository
+		company.IRepository
+		project.ProjectRepository
+	}
+	eventsService := claevents.NewService(eventsRepo, combinedRepo{
+		usersRepo,
+		companyRepo,
+		projectRepo,
+	})
+
+	utils.SetSnsEmailSender(awsSession, configFile.SNSEventTopicARN, configFile.SenderEmailAddress)
+
+	expirationService = pending_request_expirations.NewService(approvalListRepo, claManagerRepo, signaturesRepo,
+		companyRepo, projectRepo, eventsService, configFile.CorporateConsoleURL, reminderDays, expiryDays)
+}
+
+func handler(ctx context.Context, event events.CloudWatchEvent) {
+	summary, err := expirationService.ProcessPendingRequests(time.Now().UTC())
+	if err != nil {
+		log.Fatalf("Unable to process the pending requests. error = %s", err)
+	}
+	log.Infof("processed pending requests - approval list requests: %d reminded, %d expired - CLA manager requests: %d reminded, %d expired",
+		summary.ApprovalListRequestsReminded, summary.ApprovalListRequestsExpired, summary.CLAManagerRequestsReminded, summary.CLAManagerRequestsExpired)
+}
+
+func printBuildInfo() {
+	log.Infof("Version                 : %s", version)
+	log.Infof("Git commit hash         : %s", commit)
+	log.Infof("Branch                  : %s", branch)
+	log.Infof("Build date              : %s", buildDate)
+}
+
+func main() {
+	log.Info("Lambda server starting...")
+	printBuildInfo()
+	if os.Getenv("LOCAL_MODE") == "true" {
+		handler(context.Background(), events.CloudWatchEvent{})
+	} else {
+		lambda.Start(handler)
+	}
+	log.Infof("Lambda shutting down...")
+}
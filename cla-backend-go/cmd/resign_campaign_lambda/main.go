@@ -0,0 +1,130 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	claevents "github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/resign_campaigns"
+)
+
+var (
+	// version the application version
+	version string
+
+	// build/Commit the application build number
+	commit string
+
+	// branch the build branch
+	branch string
+
+	// build date
+	buildDate string
+)
+
+var campaignService resign_campaigns.Service
+
+func init() {
+	var awsSession = session.Must(session.NewSession(&aws.Config{}))
+	stage := os.Getenv("STAGE")
+	if stage == "" {
+		log.Fatal("stage not set")
+	}
+	log.Infof("STAGE set to %s\n", stage)
+	configFile, err := config.LoadConfig("", awsSession, stage)
+	if err != nil {
+		log.Panicf("Unable to load config - Error: %v", err)
+	}
+
+	// Maximum number of re-sign notifications sent per run
+	batchSize := resign_campaigns.DefaultBatchSize
+	if value := os.Getenv("RESIGN_NOTIFICATION_BATCH_SIZE"); value != "" {
+		batchSize, err = strconv.Atoi(value)
+		if err != nil {
+			log.Fatalf("invalid RESIGN_NOTIFICATION_BATCH_SIZE value: %s", value)
+		}
+	}
+	log.Infof("RESIGN_NOTIFICATION_BATCH_SIZE set to %d\n", batchSize)
+
+	// Number of days between two re-sign notifications of the same signer
+	intervalDays := resign_campaigns.DefaultNotificationIntervalDays
+	if value := os.Getenv("RESIGN_NOTIFICATION_INTERVAL_DAYS"); value != "" {
+		intervalDays, err = strconv.Atoi(value)
+		if err != nil {
+			log.Fatalf("invalid RESIGN_NOTIFICATION_INTERVAL_DAYS value: %s", value)
+		}
+	}
+	log.Infof("RESIGN_NOTIFICATION_INTERVAL_DAYS set to %d\n", intervalDays)
+
+	usersRepo := users.NewRepository(awsSession, stage)
+	companyRepo := company.NewRepository(awsSession, stage)
+	signaturesRepo := signatures.NewRepository(awsSession, stage, companyRepo, usersRepo)
+	projectClaGroupRepo := projects_cla_groups.NewRepository(awsSession, stage)
+	repositoriesRepo := repositories.NewRepository(awsSession, stage)
+	gerritRepo := gerrits.NewRepository(awsSession, stage)
+	projectRepo := project.NewRepository(awsSession, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
+	eventsRepo := claevents.NewRepository(awsSession, stage)
+	campaignRepo := resign_campaigns.NewRepository(awsSession, stage)
+
+	type combinedRepo struct {
+		users.UserRepository
+		company.IRepository
+		project.ProjectRepository
+	}
+	eventsService := claevents.NewService(eventsRepo, combinedRepo{
+		usersRepo,
+		companyRepo,
+		projectRepo,
+	})
+	usersService := users.NewService(usersRepo, eventsService)
+	utils.SetSnsEmailSender(awsSession, configFile.SNSEventTopicARN, configFile.SenderEmailAddress)
+
+	campaignService = resign_campaigns.NewService(campaignRepo, projectRepo, signaturesRepo, usersService, eventsService,
+		configFile.CorporateConsoleURL, batchSize, intervalDays)
+}
+
+func handler(ctx context.Context, event events.CloudWatchEvent) {
+	summary, err := campaignService.ProcessCampaigns(time.Now().UTC())
+	if err != nil {
+		log.Fatalf("Unable to process the re-sign campaigns. error = %s", err)
+	}
+	log.Infof("processed re-sign campaigns - targets: %d re-signed, %d notified, %d lapsed, %d awaiting notice - campaigns: %d completed, %d enforced",
+		summary.TargetsResigned, summary.TargetsNotified, summary.TargetsLapsed, summary.TargetsAwaitingNotice, summary.CampaignsCompleted, summary.CampaignsEnforced)
+}
+
+func printBuildInfo() {
+	log.Infof("Version                 : %s", version)
+	log.Infof("Git commit hash         : %s", commit)
+	log.Infof("Branch                  : %s", branch)
+	log.Infof("Build date              : %s", buildDate)
+}
+
+func main() {
+	log.Info("Lambda server starting...")
+	printBuildInfo()
+	if os.Getenv("LOCAL_MODE") == "true" {
+		handler(context.Background(), events.CloudWatchEvent{})
+	} else {
+		lambda.Start(handler)
+	}
+	log.Infof("Lambda shutting down...")
+}
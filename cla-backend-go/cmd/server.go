@@ -6,6 +6,7 @@ package cmd
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -15,6 +16,7 @@ import (
 	"strings"
 
 	"github.com/communitybridge/easycla/cla-backend-go/approval_list"
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list_revisions"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/cla_groups"
 	openapi_runtime "github.com/go-openapi/runtime"
 
@@ -44,10 +46,16 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/docs"
 	"github.com/communitybridge/easycla/cla-backend-go/repositories"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	v2CompanyMerge "github.com/communitybridge/easycla/cla-backend-go/v2/company_merge"
+	v2Coverage "github.com/communitybridge/easycla/cla-backend-go/v2/coverage"
 	v2Docs "github.com/communitybridge/easycla/cla-backend-go/v2/docs"
+	v2DocumentIntegrity "github.com/communitybridge/easycla/cla-backend-go/v2/document_integrity"
 	v2Events "github.com/communitybridge/easycla/cla-backend-go/v2/events"
+	v2ExportJobs "github.com/communitybridge/easycla/cla-backend-go/v2/export_jobs"
 	v2Metrics "github.com/communitybridge/easycla/cla-backend-go/v2/metrics"
 	v2Repositories "github.com/communitybridge/easycla/cla-backend-go/v2/repositories"
+	v2ResignCampaigns "github.com/communitybridge/easycla/cla-backend-go/v2/resign_campaigns"
+	v2Scim "github.com/communitybridge/easycla/cla-backend-go/v2/scim"
 	v2Version "github.com/communitybridge/easycla/cla-backend-go/v2/version"
 	"github.com/communitybridge/easycla/cla-backend-go/version"
 
@@ -59,6 +67,7 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/users"
 
 	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 	v2Signatures "github.com/communitybridge/easycla/cla-backend-go/v2/signatures"
 
 	ini "github.com/communitybridge/easycla/cla-backend-go/init"
@@ -67,7 +76,6 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/auth"
 	"github.com/communitybridge/easycla/cla-backend-go/company"
 	"github.com/communitybridge/easycla/cla-backend-go/config"
-	"github.com/communitybridge/easycla/cla-backend-go/docraptor"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/restapi"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/restapi/operations"
@@ -75,6 +83,7 @@ import (
 	v2Ops "github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
 	"github.com/communitybridge/easycla/cla-backend-go/github"
 	"github.com/communitybridge/easycla/cla-backend-go/health"
+	"github.com/communitybridge/easycla/cla-backend-go/pdf"
 	"github.com/communitybridge/easycla/cla-backend-go/template"
 	"github.com/communitybridge/easycla/cla-backend-go/user"
 	v2ClaManager "github.com/communitybridge/easycla/cla-backend-go/v2/cla_manager"
@@ -83,6 +92,7 @@ import (
 	v2Template "github.com/communitybridge/easycla/cla-backend-go/v2/template"
 
 	"github.com/go-openapi/loads"
+	"github.com/gorilla/sessions"
 	"github.com/lytics/logrus"
 	"github.com/rs/cors"
 	"github.com/savaki/dynastore"
@@ -191,9 +201,9 @@ func server(localMode bool) http.Handler {
 	api := operations.NewClaAPI(swaggerSpec)
 	v2API := v2Ops.NewEasyclaAPI(v2SwaggerSpec)
 
-	docraptorClient, err := docraptor.NewDocraptorClient(configFile.Docraptor.APIKey, configFile.Docraptor.TestMode)
+	pdfRenderer, err := pdf.NewRenderer(configFile.PDFRenderer, configFile.Docraptor)
 	if err != nil {
-		logrus.Panicf("Unable to setup docraptor client - Error: %v", err)
+		logrus.Panicf("Unable to setup the PDF renderer - Error: %v", err)
 	}
 
 	authValidator, err := auth.NewAuthValidator(
@@ -209,20 +219,73 @@ func server(localMode bool) http.Handler {
 	github.Init(configFile.Github.AppID, configFile.Github.AppPrivateKey, configFile.Github.AccessToken)
 
 	// Our backend repository handlers
-	userRepo := user.NewDynamoRepository(awsSession, stage)
-	usersRepo := users.NewRepository(awsSession, stage)
-	repositoriesRepo := repositories.NewRepository(awsSession, stage)
-	gerritRepo := gerrits.NewRepository(awsSession, stage)
-	templateRepo := template.NewRepository(awsSession, stage)
-	approvalListRepo := approval_list.NewRepository(awsSession, stage)
-	companyRepo := company.NewRepository(awsSession, stage)
-	signaturesRepo := signatures.NewRepository(awsSession, stage, companyRepo, usersRepo)
-	projectClaGroupRepo := projects_cla_groups.NewRepository(awsSession, stage)
-	projectRepo := project.NewRepository(awsSession, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
-	eventsRepo := events.NewRepository(awsSession, stage)
-	metricsRepo := metrics.NewRepository(awsSession, stage, configFile.APIGatewayURL, projectClaGroupRepo)
-	githubOrganizationsRepo := github_organizations.NewRepository(awsSession, stage)
-	claManagerReqRepo := cla_manager.NewRepository(awsSession, stage)
+	var userRepo user.RepositoryService
+	var usersRepo users.UserRepository
+	var repositoriesRepo repositories.Repository
+	var gerritRepo gerrits.Repository
+	var templateRepo template.Repository
+	var approvalListRepo approval_list.IRepository
+	var companyRepo company.IRepository
+	var signaturesRepo signatures.SignatureRepository
+	var projectClaGroupRepo projects_cla_groups.Repository
+	var projectRepo project.ProjectRepository
+	var eventsRepo events.Repository
+	var metricsRepo metrics.Repository
+	var githubOrganizationsRepo github_organizations.Repository
+	var claManagerReqRepo cla_manager.IRepository
+	var approvalListRevisionsRepo approval_list_revisions.Repository
+	var scimRepo v2Scim.Repository
+	var resignCampaignsRepo v2ResignCampaigns.Repository
+	var exportJobsRepo v2ExportJobs.Repository
+	if configFile.Storage.Driver == storage.DriverMemory {
+		log.Infof("Using the in-memory storage driver - file: %s", configFile.Storage.FilePath)
+		store, storeErr := storage.NewMemoryStore(configFile.Storage.FilePath)
+		if storeErr != nil {
+			log.Panicf("Unable to load the in-memory store - Error: %v", storeErr)
+		}
+		userRepo = user.NewMemoryRepository(store, stage)
+		usersRepo = users.NewMemoryRepository(store, stage)
+		repositoriesRepo = repositories.NewMemoryRepository(store, stage)
+		gerritRepo = gerrits.NewMemoryRepository(store, stage)
+		templateRepo = template.NewMemoryRepository(store, stage)
+		approvalListRepo = approval_list.NewMemoryRepository(store, stage)
+		companyRepo = company.NewMemoryRepository(store, stage)
+		signaturesRepo = signatures.NewMemoryRepository(store, stage, companyRepo, usersRepo)
+		projectClaGroupRepo = projects_cla_groups.NewMemoryRepository(store, stage)
+		projectRepo = project.NewMemoryRepository(store, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
+		eventsRepo = events.NewMemoryRepository(store, stage)
+		metricsRepo = metrics.NewMemoryRepository(store, stage, configFile.APIGatewayURL, projectClaGroupRepo)
+		githubOrganizationsRepo = github_organizations.NewMemoryRepository(store, stage)
+		claManagerReqRepo = cla_manager.NewMemoryRepository(store, stage)
+		approvalListRevisionsRepo = approval_list_revisions.NewMemoryRepository(store, stage)
+		scimRepo = v2Scim.NewMemoryRepository(store, stage)
+		resignCampaignsRepo = v2ResignCampaigns.NewMemoryRepository(store, stage)
+		exportJobsRepo = v2ExportJobs.NewMemoryRepository(store, stage)
+	} else {
+		userRepo = user.NewDynamoRepository(awsSession, stage)
+		usersRepo = users.NewRepository(awsSession, stage)
+		repositoriesRepo = repositories.NewRepository(awsSession, stage)
+		gerritRepo = gerrits.NewRepository(awsSession, stage)
+		templateRepo = template.NewRepository(awsSession, stage)
+		approvalListRepo = approval_list.NewRepository(awsSession, stage)
+		companyRepo = company.NewRepository(awsSession, stage)
+		signaturesRepo = signatures.NewRepository(awsSession, stage, companyRepo, usersRepo)
+		projectClaGroupRepo = projects_cla_groups.NewRepository(awsSession, stage)
+		projectRepo = project.NewRepository(awsSession, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
+		eventsRepo = events.NewRepository(awsSession, stage)
+		metricsRepo = metrics.NewRepository(awsSession, stage, configFile.APIGatewayURL, projectClaGroupRepo)
+		githubOrganizationsRepo = github_organizations.NewRepository(awsSession, stage)
+		claManagerReqRepo = cla_manager.NewRepository(awsSession, stage)
+		approvalListRevisionsRepo = approval_list_revisions.NewRepository(awsSession, stage)
+		scimRepo = v2Scim.NewRepository(awsSession, stage)
+		resignCampaignsRepo = v2ResignCampaigns.NewRepository(awsSession, stage)
+		exportJobsRepo = v2ExportJobs.NewRepository(awsSession, stage)
+	}
+
+	blobStore, err := storage.NewBlobStore(awsSession, configFile.BlobStorage, configFile.SignatureFilesBucket)
+	if err != nil {
+		log.Panicf("Unable to create the blob store - Error: %v", err)
+	}
 
 	// Our service layer handlers
 	eventsService := events.NewService(eventsRepo, combinedRepo{
@@ -231,20 +294,36 @@ func server(localMode bool) http.Handler {
 		projectRepo,
 	})
 	usersService := users.NewService(usersRepo, eventsService)
-	healthService := health.New(Version, Commit, Branch, BuildDate)
-	templateService := template.NewService(stage, templateRepo, docraptorClient, awsSession)
+	healthService := health.New(Version, Commit, Branch, BuildDate, configFile.Storage.Driver)
+	resignCampaignsService := v2ResignCampaigns.NewService(resignCampaignsRepo, projectRepo, signaturesRepo, usersService, eventsService,
+		configFile.CorporateConsoleURL, v2ResignCampaigns.DefaultBatchSize, v2ResignCampaigns.DefaultNotificationIntervalDays)
+	templateService := template.NewService(stage, templateRepo, pdfRenderer, blobStore, resignCampaignsService)
 	projectService := project.NewService(projectRepo, repositoriesRepo, gerritRepo, projectClaGroupRepo)
 	v2ProjectService := v2Project.NewService(projectRepo, projectClaGroupRepo)
 	companyService := company.NewService(companyRepo, configFile.CorporateConsoleURL, userRepo, usersService)
 	v2CompanyService := v2Company.NewService(companyService, signaturesRepo, projectRepo, usersRepo, companyRepo, projectClaGroupRepo)
 	v2SignService := sign.NewService(configFile.ClaV1ApiURL, companyRepo, projectRepo, projectClaGroupRepo, companyService)
-	signaturesService := signatures.NewService(signaturesRepo, companyService, usersService, eventsService, githubOrgValidation)
-	v2SignatureService := v2Signatures.NewService(awsSession, configFile.SignatureFilesBucket, projectService, companyService, signaturesService, projectClaGroupRepo)
+	approvalListRevisionsService := approval_list_revisions.NewService(approvalListRevisionsRepo)
+	githubTeamMembership := signatures.NewGitHubTeamMembership(githubOrganizationsRepo)
+	signaturesService := signatures.NewService(signaturesRepo, companyService, usersService, eventsService, approvalListRevisionsService, githubOrgValidation, githubTeamMembership)
+	v2SignatureService := v2Signatures.NewService(blobStore, projectService, companyService, signaturesService, projectClaGroupRepo)
 	v1ClaManagerService := cla_manager.NewService(claManagerReqRepo, companyService, projectService, usersService, signaturesService, eventsService, configFile.CorporateConsoleURL)
 	repositoriesService := repositories.NewService(repositoriesRepo)
 	v2RepositoriesService := v2Repositories.NewService(repositoriesRepo, projectClaGroupRepo, githubOrganizationsRepo)
 	v2ClaManagerService := v2ClaManager.NewService(companyService, projectService, v1ClaManagerService, usersService, repositoriesService, v2CompanyService, eventsService, projectClaGroupRepo)
-	approvalListService := approval_list.NewService(approvalListRepo, usersRepo, companyRepo, projectRepo, signaturesRepo, configFile.CorporateConsoleURL, http.DefaultClient)
+	approvalListService := approval_list.NewService(approvalListRepo, usersRepo, companyRepo, projectRepo, signaturesRepo, approvalListRevisionsService, github.GetUserOrganizations, configFile.CorporateConsoleURL, http.DefaultClient)
+	v2CoverageService := v2Coverage.NewService(signaturesService, approvalListService, usersService, nil, githubTeamMembership)
+	v2ScimService := v2Scim.NewService(scimRepo, signaturesRepo, approvalListRevisionsService, usersService, eventsService)
+	v2CompanyMergeService := v2CompanyMerge.NewService(companyRepo, signaturesRepo, approvalListRevisionsService, eventsService)
+	v2DocumentIntegrityService := v2DocumentIntegrity.NewService(signaturesRepo, projectRepo, eventsService, utils.DownloadFromS3)
+	// The export jobs run in the background of the server in local mode, in the export job lambda otherwise
+	var exportJobDispatcher v2ExportJobs.Dispatcher
+	if !localMode && configFile.Storage.Driver != storage.DriverMemory {
+		exportJobDispatcher = v2ExportJobs.NewLambdaDispatcher(awsSession, fmt.Sprintf("cla-backend-%s-export-job-lambda", stage))
+	}
+	v2ExportJobsService := v2ExportJobs.NewService(exportJobsRepo, companyRepo, v2SignatureService, eventsService,
+		v2Signatures.NewZipBuilder(blobStore, signaturesRepo),
+		v2ExportJobs.NewBlobArtifactStore(blobStore), exportJobDispatcher, v2ExportJobs.DefaultRetention)
 	authorizer := auth.NewAuthorizer(authValidator, userRepo)
 	v2MetricsService := metrics.NewService(metricsRepo, projectClaGroupRepo)
 	githubOrganizationsService := github_organizations.NewService(githubOrganizationsRepo, repositoriesRepo)
@@ -257,12 +336,18 @@ func server(localMode bool) http.Handler {
 	})
 	v2ClaGroupService := cla_groups.NewService(projectService, templateService, projectClaGroupRepo, v1ClaManagerService, signaturesService, metricsRepo, gerritService, repositoriesService, eventsService)
 
-	sessionStore, err := dynastore.New(dynastore.Path("/"), dynastore.HTTPOnly(), dynastore.TableName(configFile.SessionStoreTableName), dynastore.DynamoDB(dynamodb.New(awsSession)))
-	if err != nil {
-		log.Fatalf("Unable to create new Dynastore session - Error: %v", err)
+	// The sessions are kept in memory with the in-memory storage driver, so that the server runs without AWS
+	var sessionStore sessions.Store
+	if configFile.Storage.Driver == storage.DriverMemory {
+		sessionStore = storage.NewMemorySessionStore("/")
+	} else {
+		sessionStore, err = dynastore.New(dynastore.Path("/"), dynastore.HTTPOnly(), dynastore.TableName(configFile.SessionStoreTableName), dynastore.DynamoDB(dynamodb.New(awsSession)))
+		if err != nil {
+			log.Fatalf("Unable to create new Dynastore session - Error: %v", err)
+		}
 	}
 	utils.SetSnsEmailSender(awsSession, configFile.SNSEventTopicARN, configFile.SenderEmailAddress)
-	utils.SetS3Storage(awsSession, configFile.SignatureFilesBucket)
+	utils.SetBlobStorage(blobStore)
 
 	// Setup security handlers
 	api.OauthSecurityAuth = authorizer.SecurityAuth
@@ -280,11 +365,16 @@ func server(localMode bool) http.Handler {
 	health.Configure(api, healthService)
 	v2Health.Configure(v2API, healthService)
 	template.Configure(api, templateService, eventsService)
-	v2Template.Configure(v2API, templateService, eventsService)
+	v2Template.Configure(v2API, templateService, eventsService, projectRepo)
 	github.Configure(api, configFile.Github.ClientID, configFile.Github.ClientSecret, configFile.Github.AccessToken, sessionStore)
 	signatures.Configure(api, signaturesService, sessionStore, eventsService)
-	v2Signatures.Configure(v2API, projectService, projectRepo, companyService, signaturesService, sessionStore, eventsService, v2SignatureService, projectClaGroupRepo)
+	v2Signatures.Configure(v2API, projectService, projectRepo, companyService, signaturesService, sessionStore, eventsService, v2SignatureService, projectClaGroupRepo, approvalListRevisionsService, v2DocumentIntegrityService)
 	approval_list.Configure(api, approvalListService, sessionStore, signaturesService, eventsService)
+	v2Coverage.Configure(v2API, v2CoverageService, projectRepo)
+	v2Scim.Configure(v2API, v2ScimService, companyService, projectRepo)
+	v2ResignCampaigns.Configure(v2API, resignCampaignsService, projectRepo)
+	v2CompanyMerge.Configure(v2API, v2CompanyMergeService)
+	v2ExportJobs.Configure(v2API, v2ExportJobsService, projectRepo)
 	company.Configure(api, companyService, usersService, companyUserValidation, eventsService)
 	docs.Configure(api)
 	v2Docs.Configure(v2API)
@@ -5,11 +5,18 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	v1Signatures "github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/signatures"
 
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	awslambda "github.com/aws/aws-sdk-go/service/lambda"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -38,6 +45,8 @@ type BuildZipEvent struct {
 
 var zipBuilder signatures.ZipBuilder
 
+var lambdaClient *awslambda.Lambda
+
 func init() {
 	var awsSession = session.Must(session.NewSession(&aws.Config{}))
 	stage := os.Getenv("STAGE")
@@ -50,7 +59,11 @@ func init() {
 		log.Fatal("CLA_SIGNATURE_FILES_BUCKET is not set in environment")
 	}
 	log.Infof("CLA_SIGNATURE_FILES_BUCKET : %s", signaturesFileBucket)
-	zipBuilder = signatures.NewZipBuilder(awsSession, signaturesFileBucket)
+	usersRepo := users.NewRepository(awsSession, stage)
+	companyRepo := company.NewRepository(awsSession, stage)
+	signaturesRepo := v1Signatures.NewRepository(awsSession, stage, companyRepo, usersRepo)
+	zipBuilder = signatures.NewZipBuilder(storage.NewS3BlobStore(awsSession, signaturesFileBucket), signaturesRepo)
+	lambdaClient = awslambda.New(awsSession)
 }
 
 func handler(ctx context.Context, event BuildZipEvent) error {
@@ -64,12 +77,37 @@ func handler(ctx context.Context, event BuildZipEvent) error {
 	default:
 		log.WithField("event", event).Debug("Invalid event")
 	}
+	if err == signatures.ErrZipIncomplete {
+		log.WithField("event", event).Debug("zip build incomplete, resuming the build")
+		if os.Getenv("LOCAL_MODE") == "true" {
+			return handler(ctx, event)
+		}
+		return resumeBuild(event)
+	}
 	if err != nil {
 		log.WithField("args", event).Error("failed to build zip", err)
 	}
 	return err
 }
 
+// resumeBuild invokes the zipbuilder-lambda asynchronously to add the remaining signed documents to the zip
+func resumeBuild(event BuildZipEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = lambdaClient.Invoke(&awslambda.InvokeInput{
+		FunctionName:   aws.String(lambdacontext.FunctionName),
+		InvocationType: aws.String(awslambda.InvocationTypeEvent),
+		Payload:        payload,
+	})
+	if err != nil {
+		// the build is resumed by the next scheduled build
+		log.WithField("event", event).Warnf("unable to resume the zip build, error: %v", err)
+	}
+	return nil
+}
+
 func printBuildInfo() {
 	log.Infof("Version                 : %s", version)
 	log.Infof("Git commit hash         : %s", commit)
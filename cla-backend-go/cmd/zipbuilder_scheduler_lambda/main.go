@@ -23,6 +23,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/signatures"
 )
 
 var (
@@ -58,6 +60,12 @@ func handler(ctx context.Context, event events.CloudWatchEvent) {
 	if stage == "" {
 		log.Fatal("stage not set")
 	}
+	signaturesFileBucket := os.Getenv("CLA_SIGNATURE_FILES_BUCKET")
+	if signaturesFileBucket == "" {
+		log.Fatal("CLA_SIGNATURE_FILES_BUCKET is not set in environment")
+	}
+	// the zips are only checked here, the signatures are loaded by the zipbuilder-lambda building them
+	zipBuilder := signatures.NewZipBuilder(storage.NewS3BlobStore(awsSession, signaturesFileBucket), nil)
 	dynamoDBClient := dynamodb.New(awsSession)
 	claGroups, err := getClaGroups(dynamoDBClient, stage)
 	if err != nil {
@@ -87,13 +95,22 @@ func handler(ctx context.Context, event events.CloudWatchEvent) {
 	wg := &sync.WaitGroup{}
 	wg.Add(len(eventPayloads))
 	for _, buildZipArg := range eventPayloads {
-		go invokeLambda(wg, lambdaClient, stage, buildZipArg)
+		go invokeLambda(wg, lambdaClient, zipBuilder, stage, buildZipArg)
 	}
 	wg.Wait()
 }
 
-func invokeLambda(wg *sync.WaitGroup, lambdaClient *lambda.Lambda, stage string, buildZipEvent BuildZipEvent) {
+// invokeLambda invokes the zipbuilder-lambda if the signed documents of the cla group changed since its zip was built
+func invokeLambda(wg *sync.WaitGroup, lambdaClient *lambda.Lambda, zipBuilder signatures.ZipBuilder, stage string, buildZipEvent BuildZipEvent) {
 	defer wg.Done()
+	outdated, err := zipBuilder.ZipOutdated(buildZipEvent.SignatureType, buildZipEvent.ClaGroupID)
+	if err != nil {
+		// rebuild the zip rather than missing the changes
+		log.WithField("buildZipEvent", buildZipEvent).Warnf("unable to check the zip, error: %v", err)
+	} else if !outdated {
+		log.WithField("buildZipEvent", buildZipEvent).Debug("zip is up to date")
+		return
+	}
 	log.WithField("buildZipEvent", buildZipEvent).Debug("invoking zipbuilder-lambda")
 	payload, err := json.Marshal(buildZipEvent)
 	if err != nil {
@@ -21,6 +21,13 @@ type DBModel struct {
 	Updated           string   `dynamodbav:"date_modified" json:"date_modified"`
 	Note              string   `dynamodbav:"note" json:"note"`
 	Version           string   `dynamodbav:"version" json:"version"`
+	// lineage of company merges - set on both the merged (source) and the surviving (target) company records
+	MergedIntoCompanyID string   `dynamodbav:"merged_into_company_id,omitempty" json:"merged_into_company_id,omitempty"`
+	MergedCompanyIDs    []string `dynamodbav:"merged_company_ids,stringset,omitempty" json:"merged_company_ids,omitempty"`
+	DateMerged          string   `dynamodbav:"date_merged,omitempty" json:"date_merged,omitempty"`
+	// progress of the merge of the (source) company - removed once the lineage is recorded
+	MergeTargetCompanyID string   `dynamodbav:"merge_target_company_id,omitempty" json:"merge_target_company_id,omitempty"`
+	MergeAppliedChanges  []string `dynamodbav:"merge_applied_changes,stringset,omitempty" json:"merge_applied_changes,omitempty"`
 }
 
 // Invite data model
@@ -66,15 +73,18 @@ func (dbCompanyModel *DBModel) toModel() (*models.Company, error) {
 
 	// Convert the local DB model to a public swagger model
 	return &models.Company{
-		CompanyACL:        dbCompanyModel.CompanyACL,
-		CompanyID:         dbCompanyModel.CompanyID,
-		CompanyName:       dbCompanyModel.CompanyName,
-		CompanyExternalID: dbCompanyModel.CompanyExternalID,
-		CompanyManagerID:  dbCompanyModel.CompanyManagerID,
-		Created:           strfmt.DateTime(createdDateTime),
-		Updated:           strfmt.DateTime(updateDateTime),
-		Note:              dbCompanyModel.Note,
-		Version:           dbCompanyModel.Version,
+		CompanyACL:          dbCompanyModel.CompanyACL,
+		CompanyID:           dbCompanyModel.CompanyID,
+		CompanyName:         dbCompanyModel.CompanyName,
+		CompanyExternalID:   dbCompanyModel.CompanyExternalID,
+		CompanyManagerID:    dbCompanyModel.CompanyManagerID,
+		Created:             strfmt.DateTime(createdDateTime),
+		Updated:             strfmt.DateTime(updateDateTime),
+		Note:                dbCompanyModel.Note,
+		Version:             dbCompanyModel.Version,
+		MergedIntoCompanyID: dbCompanyModel.MergedIntoCompanyID,
+		MergedCompanyIDs:    dbCompanyModel.MergedCompanyIDs,
+		DateMerged:          dbCompanyModel.DateMerged,
 	}, nil
 }
 
@@ -94,14 +104,17 @@ func toSwaggerModel(dbCompanyModel *DBModel) (*models.Company, error) {
 
 	// Convert the local DB model to a public swagger model
 	return &models.Company{
-		CompanyACL:        dbCompanyModel.CompanyACL,
-		CompanyID:         dbCompanyModel.CompanyID,
-		CompanyName:       dbCompanyModel.CompanyName,
-		CompanyExternalID: dbCompanyModel.CompanyExternalID,
-		CompanyManagerID:  dbCompanyModel.CompanyManagerID,
-		Created:           strfmt.DateTime(createdDateTime),
-		Updated:           strfmt.DateTime(updateDateTime),
-		Note:              dbCompanyModel.Note,
-		Version:           dbCompanyModel.Version,
+		CompanyACL:          dbCompanyModel.CompanyACL,
+		CompanyID:           dbCompanyModel.CompanyID,
+		CompanyName:         dbCompanyModel.CompanyName,
+		CompanyExternalID:   dbCompanyModel.CompanyExternalID,
+		CompanyManagerID:    dbCompanyModel.CompanyManagerID,
+		Created:             strfmt.DateTime(createdDateTime),
+		Updated:             strfmt.DateTime(updateDateTime),
+		Note:                dbCompanyModel.Note,
+		Version:             dbCompanyModel.Version,
+		MergedIntoCompanyID: dbCompanyModel.MergedIntoCompanyID,
+		MergedCompanyIDs:    dbCompanyModel.MergedCompanyIDs,
+		DateMerged:          dbCompanyModel.DateMerged,
 	}, nil
 }
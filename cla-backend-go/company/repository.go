@@ -21,6 +21,7 @@ import (
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
@@ -29,7 +30,9 @@ import (
 
 // errors
 var (
-	ErrCompanyDoesNotExist = errors.New("company does not exist")
+	ErrCompanyDoesNotExist  = errors.New("company does not exist")
+	ErrCompanyAlreadyMerged = errors.New("company already merged into another company")
+	ErrCompanyMergeConflict = errors.New("company is being merged into another company")
 )
 
 // IRepository interface methods
@@ -55,6 +58,9 @@ type IRepository interface { //nolint
 	updateInviteRequestStatus(companyInviteID, status string) error
 
 	UpdateCompanyAccessList(companyID string, companyACL []string) error
+	StartCompanyMerge(sourceCompanyID, targetCompanyID string) ([]string, error)
+	RecordCompanyMergeChange(sourceCompanyID, changeKey string) error
+	RecordCompanyMerge(sourceCompanyID, targetCompanyID, mergedOn string) error
 }
 
 type repository struct {
@@ -1027,6 +1033,131 @@ func (repo repository) UpdateCompanyAccessList(companyID string, companyACL []st
 	return nil
 }
 
+// StartCompanyMerge records on the source company that it is being merged into the target company, returns the keys of
+// the changes of the merge already applied when an interrupted merge into the same company is resumed. Returns
+// ErrCompanyMergeConflict if the source company was merged or is being merged into another company.
+func (repo repository) StartCompanyMerge(sourceCompanyID, targetCompanyID string) ([]string, error) {
+	f := logrus.Fields{
+		"functionName":    "StartCompanyMerge",
+		"sourceCompanyID": sourceCompanyID,
+		"targetCompanyID": targetCompanyID,
+	}
+
+	result, err := repo.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+		ExpressionAttributeNames: map[string]*string{
+			"#I": aws.String("merged_into_company_id"),
+			"#T": aws.String("merge_target_company_id"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":t": {S: aws.String(targetCompanyID)},
+		},
+		TableName: aws.String(repo.companyTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"company_id": {S: aws.String(sourceCompanyID)},
+		},
+		UpdateExpression:    aws.String("SET #T = :t"),
+		ConditionExpression: aws.String("attribute_exists(company_id) AND attribute_not_exists(#I) AND (attribute_not_exists(#T) OR #T = :t)"),
+		ReturnValues:        aws.String(dynamodb.ReturnValueAllNew),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return nil, ErrCompanyMergeConflict
+		}
+		log.WithFields(f).Warnf("unable to record the merge progress on the source company, error: %v", err)
+		return nil, err
+	}
+
+	var dbModel DBModel
+	if err = dynamodbattribute.UnmarshalMap(result.Attributes, &dbModel); err != nil {
+		log.WithFields(f).Warnf("unable to unmarshal the source company, error: %v", err)
+		return nil, err
+	}
+	return dbModel.MergeAppliedChanges, nil
+}
+
+// RecordCompanyMergeChange records the key of a change applied by the merge of the source company, the change is
+// skipped if the merge is resumed
+func (repo repository) RecordCompanyMergeChange(sourceCompanyID, changeKey string) error {
+	_, err := repo.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+		ExpressionAttributeNames: map[string]*string{
+			"#A": aws.String("merge_applied_changes"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":a": {SS: aws.StringSlice([]string{changeKey})},
+		},
+		TableName: aws.String(repo.companyTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"company_id": {S: aws.String(sourceCompanyID)},
+		},
+		UpdateExpression: aws.String("ADD #A :a"),
+	})
+	if err != nil {
+		log.Warnf("unable to record the merge change: %s on company: %s, error: %v", changeKey, sourceCompanyID, err)
+	}
+	return err
+}
+
+// RecordCompanyMerge records the lineage of a company merge - the source company is marked as merged into the target
+// company and the source company ID is added to the merged company IDs of the target. The merge progress of the source
+// company is removed. Returns ErrCompanyAlreadyMerged if the source company was already merged.
+func (repo repository) RecordCompanyMerge(sourceCompanyID, targetCompanyID, mergedOn string) error {
+	f := logrus.Fields{
+		"functionName":    "RecordCompanyMerge",
+		"sourceCompanyID": sourceCompanyID,
+		"targetCompanyID": targetCompanyID,
+	}
+
+	_, err := repo.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+		ExpressionAttributeNames: map[string]*string{
+			"#I": aws.String("merged_into_company_id"),
+			"#D": aws.String("date_merged"),
+			"#M": aws.String("date_modified"),
+			"#T": aws.String("merge_target_company_id"),
+			"#A": aws.String("merge_applied_changes"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":i": {S: aws.String(targetCompanyID)},
+			":d": {S: aws.String(mergedOn)},
+			":m": {S: aws.String(mergedOn)},
+		},
+		TableName: aws.String(repo.companyTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"company_id": {S: aws.String(sourceCompanyID)},
+		},
+		UpdateExpression:    aws.String("SET #I = :i, #D = :d, #M = :m REMOVE #T, #A"),
+		ConditionExpression: aws.String("attribute_exists(company_id) AND attribute_not_exists(#I)"),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrCompanyAlreadyMerged
+		}
+		log.WithFields(f).Warnf("unable to record the merge on the source company, error: %v", err)
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+		ExpressionAttributeNames: map[string]*string{
+			"#C": aws.String("merged_company_ids"),
+			"#M": aws.String("date_modified"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":c": {SS: aws.StringSlice([]string{sourceCompanyID})},
+			":m": {S: aws.String(mergedOn)},
+		},
+		TableName: aws.String(repo.companyTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"company_id": {S: aws.String(targetCompanyID)},
+		},
+		UpdateExpression: aws.String("ADD #C :c SET #M = :m"),
+	})
+	if err != nil {
+		log.WithFields(f).Warnf("unable to record the merge on the target company, error: %v", err)
+		return err
+	}
+
+	return nil
+}
+
 // CreateCompany creates a new company record
 func (repo repository) CreateCompany(in *models.Company) (*models.Company, error) {
 	companyID, err := uuid.NewV4()
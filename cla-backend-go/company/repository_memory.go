@@ -0,0 +1,485 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package company
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/user"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// memoryInvite is the stored representation of a company invite - includes the optional user attributes
+type memoryInvite struct {
+	Invite
+	UserName           string `dynamodbav:"user_name,omitempty"`
+	UserGithubID       string `dynamodbav:"user_github_id,omitempty"`
+	UserGithubUsername string `dynamodbav:"user_github_username,omitempty"`
+	UserLFUsername     string `dynamodbav:"user_lf_user_name,omitempty"`
+}
+
+type memoryRepository struct {
+	store                   *storage.MemoryStore
+	companyTableName        string
+	companyInvitesTableName string
+}
+
+// NewMemoryRepository creates a new company repository instance backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string) IRepository {
+	return memoryRepository{
+		store:                   store,
+		companyTableName:        fmt.Sprintf("cla-%s-companies", stage),
+		companyInvitesTableName: fmt.Sprintf("cla-%s-company-invites", stage),
+	}
+}
+
+// scan returns the companies matching the filter
+func (repo memoryRepository) scan(filter func(company *DBModel) bool) ([]DBModel, error) {
+	var dbModels []DBModel
+	if err := repo.store.Scan(repo.companyTableName, &dbModels); err != nil {
+		log.Warnf("error retrieving companies, error: %v", err)
+		return nil, err
+	}
+	var matched []DBModel
+	for i := range dbModels {
+		if filter(&dbModels[i]) {
+			matched = append(matched, dbModels[i])
+		}
+	}
+	return matched, nil
+}
+
+// buildCompanies converts the DB models to a company list response
+func buildCompanies(dbModels []DBModel, totalCount int64) (*models.Companies, error) {
+	companies := []models.Company{}
+	for i := range dbModels {
+		company, err := dbModels[i].toModel()
+		if err != nil {
+			return nil, err
+		}
+		companies = append(companies, *company)
+	}
+	return &models.Companies{
+		ResultCount: int64(len(companies)),
+		TotalCount:  totalCount,
+		Companies:   companies,
+	}, nil
+}
+
+// GetCompanies retrieves all the companies
+func (repo memoryRepository) GetCompanies() (*models.Companies, error) {
+	dbModels, err := repo.scan(func(*DBModel) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+	return buildCompanies(dbModels, int64(len(dbModels)))
+}
+
+// GetCompanyByExternalID returns a company based on the company external ID
+func (repo memoryRepository) GetCompanyByExternalID(companySFID string) (*models.Company, error) {
+	dbModels, err := repo.scan(func(company *DBModel) bool {
+		return company.CompanyExternalID == companySFID
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(dbModels) == 0 {
+		return nil, ErrCompanyDoesNotExist
+	}
+	return dbModels[0].toModel()
+}
+
+// GetCompanyByName searches the store and returns the matching company
+func (repo memoryRepository) GetCompanyByName(companyName string) (*models.Company, error) {
+	dbModels, err := repo.scan(func(company *DBModel) bool {
+		return company.CompanyName == companyName
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(dbModels) == 0 {
+		log.Debugf("Company query by name returned no results using companyName: %s", companyName)
+		return nil, nil
+	}
+	return toSwaggerModel(&dbModels[0])
+}
+
+// GetCompany returns a company based on the company ID
+func (repo memoryRepository) GetCompany(companyID string) (*models.Company, error) {
+	dbCompanyModel := DBModel{}
+	found, err := repo.store.Get(repo.companyTableName, companyID, &dbCompanyModel)
+	if err != nil {
+		log.Warnf("error fetching company using company id: %s, error: %v", companyID, err)
+		return nil, err
+	}
+	if !found {
+		return nil, ErrCompanyDoesNotExist
+	}
+	return dbCompanyModel.toModel()
+}
+
+// SearchCompanyByName locates companies by the matching name and return any potential matches
+func (repo memoryRepository) SearchCompanyByName(companyName string, nextKey string) (*models.Companies, error) {
+	if strings.TrimSpace(companyName) == "" {
+		return &models.Companies{
+			Companies:   []models.Company{},
+			SearchTerms: companyName,
+		}, nil
+	}
+
+	dbModels, err := repo.scan(func(company *DBModel) bool {
+		return strings.Contains(company.CompanyName, companyName) && company.CompanyID > nextKey
+	})
+	if err != nil {
+		return nil, err
+	}
+	response, err := buildCompanies(dbModels, int64(len(dbModels)))
+	if err != nil {
+		return nil, err
+	}
+	response.SearchTerms = companyName
+	return response, nil
+}
+
+// DeleteCompanyByID deletes the company by ID
+func (repo memoryRepository) DeleteCompanyByID(companyID string) error {
+	return repo.store.Delete(repo.companyTableName, companyID)
+}
+
+// DeleteCompanyBySFID deletes the company by SFID
+func (repo memoryRepository) DeleteCompanyBySFID(companySFID string) error {
+	dbModels, err := repo.scan(func(company *DBModel) bool {
+		return company.CompanyExternalID == companySFID
+	})
+	if err != nil {
+		return err
+	}
+	for _, dbModel := range dbModels {
+		if err := repo.store.Delete(repo.companyTableName, dbModel.CompanyID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetCompaniesByUserManager the get a list of companies when provided the company id and user manager
+func (repo memoryRepository) GetCompaniesByUserManager(userID string, userModel user.User) (*models.Companies, error) {
+	var userName string
+	if userModel.LFUsername != "" {
+		userName = userModel.LFUsername
+	} else if userModel.UserName != "" {
+		userName = userModel.UserName
+	}
+	if strings.TrimSpace(userID) == "" || userName == "" {
+		return &models.Companies{
+			Companies: []models.Company{},
+		}, nil
+	}
+
+	dbModels, err := repo.scan(func(company *DBModel) bool {
+		return utils.StringInSlice(userName, company.CompanyACL)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buildCompanies(dbModels, int64(len(dbModels)))
+}
+
+// GetCompaniesByUserManagerWithInvites the get a list of companies including status when provided the company id and user manager
+func (repo memoryRepository) GetCompaniesByUserManagerWithInvites(userID string, userModel user.User) (*models.CompaniesWithInvites, error) {
+	companies, err := repo.GetCompaniesByUserManager(userID, userModel)
+	if err != nil {
+		log.Warnf("error retrieving companies for userID %s in ACL, error: %v", userID, err)
+		return nil, err
+	}
+
+	invites, err := repo.GetUserInviteRequests(userID)
+	if err != nil {
+		log.Warnf("error retrieving companies invites for userID %s, error: %v", userID, err)
+		return nil, err
+	}
+
+	companiesWithInvites := models.CompaniesWithInvites{
+		ResultCount: int64(len(companies.Companies) + len(invites)),
+		TotalCount:  companies.TotalCount + int64(len(invites)),
+	}
+	var companyWithInvite []models.CompanyWithInvite
+	for _, company := range companies.Companies {
+		companyWithInvite = append(companyWithInvite, models.CompanyWithInvite{
+			CompanyName:       company.CompanyName,
+			CompanyID:         company.CompanyID,
+			CompanyExternalID: company.CompanyExternalID,
+			CompanyACL:        company.CompanyACL,
+			Created:           company.Created,
+			Updated:           company.Updated,
+			Status:            "Joined",
+		})
+	}
+	for _, invite := range invites {
+		company, err := repo.GetCompany(invite.RequestedCompanyID)
+		if err != nil {
+			log.Warnf("error retrieving company with company ID %s, error: %v - skipping invite", invite.RequestedCompanyID, err)
+			continue
+		}
+		if invite.Status == "" {
+			invite.Status = StatusPending
+		}
+		companyWithInvite = append(companyWithInvite, models.CompanyWithInvite{
+			CompanyName: company.CompanyName,
+			CompanyID:   company.CompanyID,
+			CompanyACL:  company.CompanyACL,
+			Created:     company.Created,
+			Updated:     company.Updated,
+			Status:      invite.Status,
+		})
+	}
+	companiesWithInvites.CompaniesWithInvites = companyWithInvite
+	return &companiesWithInvites, nil
+}
+
+// scanInvites returns the company invites matching the filter
+func (repo memoryRepository) scanInvites(filter func(invite *Invite) bool) ([]Invite, error) {
+	var items []memoryInvite
+	if err := repo.store.Scan(repo.companyInvitesTableName, &items); err != nil {
+		log.Warnf("Unable to retrieve data from Company-Invites table, error: %v", err)
+		return nil, err
+	}
+	var invites []Invite
+	for i := range items {
+		if filter(&items[i].Invite) {
+			invites = append(invites, items[i].Invite)
+		}
+	}
+	return invites, nil
+}
+
+// GetCompanyInviteRequest returns the specified request
+func (repo memoryRepository) GetCompanyInviteRequest(companyInviteID string) (*Invite, error) {
+	var item memoryInvite
+	found, err := repo.store.Get(repo.companyInvitesTableName, companyInviteID, &item)
+	if err != nil {
+		log.Warnf("unable to load the company invite based on invite ID: %s, error: %v", companyInviteID, err)
+		return nil, err
+	}
+	if !found {
+		log.Warnf("unable to locate the company invite based on invite ID: %s", companyInviteID)
+		return nil, nil
+	}
+	return &item.Invite, nil
+}
+
+// GetCompanyInviteRequests returns a list of company invites when provided the company ID
+func (repo memoryRepository) GetCompanyInviteRequests(companyID string, status *string) ([]Invite, error) {
+	return repo.scanInvites(func(invite *Invite) bool {
+		return invite.RequestedCompanyID == companyID && (status == nil || invite.Status == *status)
+	})
+}
+
+// GetCompanyUserInviteRequests returns a list of company invites when provided the company ID and user ID
+func (repo memoryRepository) GetCompanyUserInviteRequests(companyID string, userID string) (*Invite, error) {
+	invites, err := repo.scanInvites(func(invite *Invite) bool {
+		return invite.RequestedCompanyID == companyID && invite.UserID == userID
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(invites) == 0 {
+		log.Debugf("Unable to find company invite for company id: %s and user id: %s", companyID, userID)
+		return nil, nil
+	}
+	return &invites[0], nil
+}
+
+// GetUserInviteRequests returns a list of company invites when provided the user ID
+func (repo memoryRepository) GetUserInviteRequests(userID string) ([]Invite, error) {
+	return repo.scanInvites(func(invite *Invite) bool {
+		return invite.UserID == userID
+	})
+}
+
+// AddPendingCompanyInviteRequest adds a pending company invite when provided the company ID and user ID
+func (repo memoryRepository) AddPendingCompanyInviteRequest(companyID string, userModel user.User) (*Invite, error) {
+	f := logrus.Fields{
+		"functionName": "AddPendingCompanyInviteRequest",
+		"companyID":    companyID,
+		"UserID":       userModel.UserID,
+	}
+
+	previousInvite, err := repo.GetCompanyUserInviteRequests(companyID, userModel.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if previousInvite != nil {
+		if previousInvite.Status == "rejected" {
+			if updateErr := repo.updateInviteRequestStatus(previousInvite.CompanyInviteID, "pending"); updateErr != nil {
+				return nil, updateErr
+			}
+		}
+		log.WithFields(f).Warnf("Invite already exists for company id: %s and user: %s - skipping creation",
+			companyID, userModel.UserID)
+		return previousInvite, nil
+	}
+
+	companyInviteID, err := uuid.NewV4()
+	if err != nil {
+		log.WithFields(f).Warnf("Unable to generate a UUID for a pending invite, error: %v", err)
+		return nil, err
+	}
+
+	_, now := utils.CurrentTime()
+	item := memoryInvite{
+		Invite: Invite{
+			CompanyInviteID:    companyInviteID.String(),
+			RequestedCompanyID: companyID,
+			UserID:             userModel.UserID,
+			Status:             "pending",
+			Created:            now,
+			Updated:            now,
+		},
+		UserName:           userModel.UserName,
+		UserGithubID:       userModel.UserGithubID,
+		UserGithubUsername: userModel.UserGithubUsername,
+		UserLFUsername:     userModel.LFUsername,
+	}
+	if err := repo.store.Put(repo.companyInvitesTableName, item.CompanyInviteID, item); err != nil {
+		log.WithFields(f).Warnf("Unable to create a new pending invite, error: %v", err)
+		return nil, err
+	}
+	return &item.Invite, nil
+}
+
+// ApproveCompanyAccessRequest approves the specified company invite
+func (repo memoryRepository) ApproveCompanyAccessRequest(companyInviteID string) error {
+	return repo.updateInviteRequestStatus(companyInviteID, "approved")
+}
+
+// RejectCompanyAccessRequest rejects the specified company invite
+func (repo memoryRepository) RejectCompanyAccessRequest(companyInviteID string) error {
+	return repo.updateInviteRequestStatus(companyInviteID, "rejected")
+}
+
+// updateInviteRequestStatus updates the specified invite with the specified status
+func (repo memoryRepository) updateInviteRequestStatus(companyInviteID, status string) error {
+	var item memoryInvite
+	err := repo.store.Update(repo.companyInvitesTableName, companyInviteID, &item, func() error {
+		_, now := utils.CurrentTime()
+		item.Status = status
+		item.Updated = now
+		return nil
+	})
+	if err != nil {
+		log.Warnf("unable to update invite request with %s status, error: %v", status, err)
+		return err
+	}
+	return nil
+}
+
+// UpdateCompanyAccessList updates the company ACL when provided the company ID and ACL list
+func (repo memoryRepository) UpdateCompanyAccessList(companyID string, companyACL []string) error {
+	var dbModel DBModel
+	err := repo.store.Update(repo.companyTableName, companyID, &dbModel, func() error {
+		_, now := utils.CurrentTime()
+		dbModel.CompanyACL = companyACL
+		dbModel.Updated = now
+		return nil
+	})
+	if err != nil {
+		log.Warnf("Error updating Company Access List, error: %v", err)
+		return err
+	}
+	return nil
+}
+
+// StartCompanyMerge records on the source company that it is being merged into the target company, returns the keys of
+// the changes of the merge already applied when an interrupted merge into the same company is resumed. Returns
+// ErrCompanyMergeConflict if the source company was merged or is being merged into another company.
+func (repo memoryRepository) StartCompanyMerge(sourceCompanyID, targetCompanyID string) ([]string, error) {
+	var source DBModel
+	err := repo.store.Update(repo.companyTableName, sourceCompanyID, &source, func() error {
+		if source.MergedIntoCompanyID != "" || (source.MergeTargetCompanyID != "" && source.MergeTargetCompanyID != targetCompanyID) {
+			return ErrCompanyMergeConflict
+		}
+		source.MergeTargetCompanyID = targetCompanyID
+		return nil
+	})
+	if err != nil {
+		if err == storage.ErrItemNotFound {
+			return nil, ErrCompanyMergeConflict
+		}
+		return nil, err
+	}
+	return source.MergeAppliedChanges, nil
+}
+
+// RecordCompanyMergeChange records the key of a change applied by the merge of the source company
+func (repo memoryRepository) RecordCompanyMergeChange(sourceCompanyID, changeKey string) error {
+	var source DBModel
+	return repo.store.Update(repo.companyTableName, sourceCompanyID, &source, func() error {
+		if !utils.StringInSlice(changeKey, source.MergeAppliedChanges) {
+			source.MergeAppliedChanges = append(source.MergeAppliedChanges, changeKey)
+		}
+		return nil
+	})
+}
+
+// RecordCompanyMerge records the lineage of a company merge - the source company is marked as merged into the target
+// company and the source company ID is added to the merged company IDs of the target. The merge progress of the source
+// company is removed. Returns ErrCompanyAlreadyMerged if the source company was already merged.
+func (repo memoryRepository) RecordCompanyMerge(sourceCompanyID, targetCompanyID, mergedOn string) error {
+	var source DBModel
+	err := repo.store.Update(repo.companyTableName, sourceCompanyID, &source, func() error {
+		if source.MergedIntoCompanyID != "" {
+			return ErrCompanyAlreadyMerged
+		}
+		source.MergedIntoCompanyID = targetCompanyID
+		source.MergeTargetCompanyID = ""
+		source.MergeAppliedChanges = nil
+		source.DateMerged = mergedOn
+		source.Updated = mergedOn
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var target DBModel
+	return repo.store.Update(repo.companyTableName, targetCompanyID, &target, func() error {
+		if !utils.StringInSlice(sourceCompanyID, target.MergedCompanyIDs) {
+			target.MergedCompanyIDs = append(target.MergedCompanyIDs, sourceCompanyID)
+		}
+		target.Updated = mergedOn
+		return nil
+	})
+}
+
+// CreateCompany creates a new company record
+func (repo memoryRepository) CreateCompany(in *models.Company) (*models.Company, error) {
+	companyID, err := uuid.NewV4()
+	if err != nil {
+		log.Warnf("Unable to generate a UUID for a new company, error: %v", err)
+		return nil, err
+	}
+	_, now := utils.CurrentTime()
+	comp := &DBModel{
+		CompanyID:         companyID.String(),
+		CompanyName:       in.CompanyName,
+		CompanyExternalID: in.CompanyExternalID,
+		CompanyACL:        in.CompanyACL,
+		CompanyManagerID:  in.CompanyManagerID,
+		Note:              in.Note,
+		Created:           now,
+		Updated:           now,
+		Version:           "v1",
+	}
+	if err := repo.store.Put(repo.companyTableName, comp.CompanyID, comp); err != nil {
+		return nil, err
+	}
+	return comp.toModel()
+}
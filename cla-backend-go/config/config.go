@@ -41,6 +41,15 @@ type Config struct {
 	// Github Application
 	Github Github `json:"github"`
 
+	// Storage driver used by the repositories
+	Storage Storage `json:"storage"`
+
+	// BlobStorage of the signed documents, templates and zips
+	BlobStorage BlobStorage `json:"blob_storage"`
+
+	// PDFRenderer of the templates
+	PDFRenderer PDFRenderer `json:"pdf_renderer"`
+
 	// Dynamo Session Store
 	SessionStoreTableName string `json:"sessionStoreTableName"`
 
@@ -107,6 +116,35 @@ type AWS struct {
 	Region string `json:"region"`
 }
 
+// Storage model - the driver is either dynamodb (default) or memory, the file path is optional and only used by
+// the memory driver to load and persist the records
+type Storage struct {
+	Driver   string `json:"driver"`
+	FilePath string `json:"file_path"`
+}
+
+// BlobStorage model - the driver is either s3 (default), s3-compatible or filesystem. The s3 drivers store the blobs in
+// the signature files bucket, the s3-compatible driver requires the endpoint of the server - path-style addressing is
+// required by MinIO for example - and uses the static credentials when configured. The filesystem driver stores the
+// blobs in the directory, the base URL is the URL the directory is served at.
+type BlobStorage struct {
+	Driver          string `json:"driver"`
+	Endpoint        string `json:"endpoint"`
+	Region          string `json:"region"`
+	ForcePathStyle  bool   `json:"force_path_style"`
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	Directory       string `json:"directory"`
+	BaseURL         string `json:"base_url"`
+}
+
+// PDFRenderer model - the driver is either docraptor (default) or local. The docraptor driver renders the templates
+// with the DocRaptor API, the local driver renders them in process for the deployments without the DocRaptor API and
+// the tests.
+type PDFRenderer struct {
+	Driver string `json:"driver"`
+}
+
 // Github model
 type Github struct {
 	ClientID      string `json:"clientId"`
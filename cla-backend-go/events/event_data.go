@@ -3,6 +3,7 @@ package events
 
 import (
 	"fmt"
+	"strings"
 )
 
 // EventData returns event data string which is used for event logging and containsPII field
@@ -37,6 +38,56 @@ type SignatureProjectInvalidatedEventData struct {
 	InvalidatedCount int
 }
 
+type SignatureRevokedEventData struct {
+	SignatureID   string
+	SignatureType string
+	SignerName    string
+	Reason        string
+	Comment       string
+	RevokedOn     string
+}
+
+type ResignPolicyUpdatedEventData struct {
+	ResignRequired  bool
+	GracePeriodDays int64
+}
+
+type ResignCampaignStartedEventData struct {
+	CampaignID       string
+	ICLAMajorVersion int
+	CCLAMajorVersion int
+	TargetCount      int
+	Deadline         string
+}
+
+type ResignCampaignNotificationsSentEventData struct {
+	CampaignID        string
+	NotificationCount int
+}
+
+type ResignCampaignCompletedEventData struct {
+	CampaignID    string
+	ResignedCount int
+}
+
+type ResignCampaignEnforcedEventData struct {
+	CampaignID   string
+	RevokedCount int
+	Deadline     string
+}
+
+type SignedDocumentAuditFailedEventData struct {
+	DocumentCount int
+	MismatchCount int
+	MissingCount  int
+	// SignatureIDs are the signatures whose signed document is mismatched or missing
+	SignatureIDs []string
+}
+
+type ResignCampaignCancelledEventData struct {
+	CampaignID string
+}
+
 type UserCreatedEventData struct{}
 type UserDeletedEventData struct {
 	DeletedUserID string
@@ -65,8 +116,23 @@ type CompanyACLUserAddedEventData struct {
 	UserLFID string
 }
 
+type CompanyMergedEventData struct {
+	SourceCompanyID                 string
+	SourceCompanyName               string
+	CorporateSignaturesTransferred  int
+	CorporateSignaturesConsolidated int
+	EmployeeSignaturesTransferred   int
+	ChangeCount                     int
+}
+
 type CLATemplateCreatedEventData struct{}
 
+type CLATemplateRolledBackEventData struct {
+	DocumentType    string
+	RestoredVersion string
+	Version         string
+}
+
 type GithubOrganizationAddedEventData struct {
 	GithubOrganizationName string
 }
@@ -81,12 +147,32 @@ type CCLAApprovalListRequestCreatedEventData struct {
 
 type CCLAApprovalListRequestApprovedEventData struct {
 	RequestID string
+	// AutoApprovalRuleType and AutoApprovalMatchedValue are set when the request was approved by an auto-approval rule
+	AutoApprovalRuleType     string
+	AutoApprovalMatchedValue string
 }
 
 type CCLAApprovalListRequestRejectedEventData struct {
 	RequestID string
 }
 
+type CCLAApprovalListRequestReminderEventData struct {
+	RequestID      string
+	RequesterName  string
+	ReminderNumber int
+	DaysPending    int
+}
+
+type CCLAApprovalListRequestExpiredEventData struct {
+	RequestID     string
+	RequesterName string
+	DaysPending   int
+}
+
+type CCLAApprovalListRequestAutoApprovalRulesUpdatedEventData struct {
+	Rules []string
+}
+
 type CLAManagerCreatedEventData struct {
 	CompanyName string
 	ProjectName string
@@ -198,12 +284,45 @@ type CLAApprovalListRemoveGitHubOrgData struct {
 	ApprovalListGitHubOrg string
 }
 
+type CLAApprovalListAddGitHubTeamData struct {
+	UserName               string
+	UserEmail              string
+	UserLFID               string
+	ApprovalListGitHubTeam string
+}
+
+type CLAApprovalListRemoveGitHubTeamData struct {
+	UserName               string
+	UserEmail              string
+	UserLFID               string
+	ApprovalListGitHubTeam string
+}
+
 type ApprovalListGithubOrganizationAddedEventData struct {
 	GithubOrganizationName string
 }
 type ApprovalListGithubOrganizationDeletedEventData struct {
 	GithubOrganizationName string
 }
+type ApprovalListEntryExpiredEventData struct {
+	ListType       string
+	Value          string
+	ExpirationDate string
+}
+type ApprovalListSCIMEmailAddedEventData struct {
+	Email        string
+	SCIMUserName string
+}
+type ApprovalListSCIMEmailRemovedEventData struct {
+	Email        string
+	SCIMUserName string
+}
+type SCIMTokenCreatedEventData struct {
+	TokenHint string
+}
+type SCIMTokenRevokedEventData struct {
+	TokenHint string
+}
 type ClaManagerAccessRequestAddedEventData struct {
 	ProjectName string
 	CompanyName string
@@ -211,6 +330,17 @@ type ClaManagerAccessRequestAddedEventData struct {
 type ClaManagerAccessRequestDeletedEventData struct {
 	RequestID string
 }
+type ClaManagerAccessRequestReminderEventData struct {
+	RequestID      string
+	RequesterName  string
+	ReminderNumber int
+	DaysPending    int
+}
+type ClaManagerAccessRequestExpiredEventData struct {
+	RequestID     string
+	RequesterName string
+	DaysPending   int
+}
 
 type CLAGroupCreatedEventData struct{}
 type CLAGroupUpdatedEventData struct{}
@@ -300,11 +430,24 @@ func (ed *CompanyACLUserAddedEventData) GetEventString(args *LogEventArgs) (stri
 	return data, true
 }
 
+func (ed *CompanyMergedEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("user [%s] merged company [%s / %s] into company [%s / %s] - %d CCLAs transferred, %d CCLAs consolidated, %d employee acknowledgements transferred, %d changes in total",
+		args.userName, ed.SourceCompanyName, ed.SourceCompanyID, args.companyName, args.CompanyID,
+		ed.CorporateSignaturesTransferred, ed.CorporateSignaturesConsolidated, ed.EmployeeSignaturesTransferred, ed.ChangeCount)
+	return data, true
+}
+
 func (ed *CLATemplateCreatedEventData) GetEventString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("user [%s] created PDF templates for project [%s]", args.userName, args.projectName)
 	return data, true
 }
 
+func (ed *CLATemplateRolledBackEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("user [%s] rolled back the %s document of project [%s] to version %s, published as version %s",
+		args.userName, ed.DocumentType, args.projectName, ed.RestoredVersion, ed.Version)
+	return data, true
+}
+
 func (ed *GithubOrganizationAddedEventData) GetEventString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("user [%s] added github organization [%s]",
 		args.userName, ed.GithubOrganizationName)
@@ -318,6 +461,11 @@ func (ed *GithubOrganizationDeletedEventData) GetEventString(args *LogEventArgs)
 }
 
 func (ed *CCLAApprovalListRequestApprovedEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	if ed.AutoApprovalRuleType != "" {
+		data := fmt.Sprintf("the CCLA Approval Request of user [%s] for project: [%s], company: [%s] was automatically approved by the %s auto-approval rule matching %s - request id: %s",
+			args.userName, args.projectName, args.companyName, ed.AutoApprovalRuleType, ed.AutoApprovalMatchedValue, ed.RequestID)
+		return data, true
+	}
 	data := fmt.Sprintf("user [%s] approved a CCLA Approval Request for project: [%s], company: [%s] - request id: %s",
 		args.userName, args.projectName, args.companyName, ed.RequestID)
 	return data, true
@@ -329,6 +477,28 @@ func (ed *CCLAApprovalListRequestRejectedEventData) GetEventString(args *LogEven
 	return data, true
 }
 
+func (ed *CCLAApprovalListRequestReminderEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("reminder %d sent to the CLA Managers of company: [%s] for the CCLA Approval Request of user [%s] for project: [%s] pending for %d days - request id: %s",
+		ed.ReminderNumber, args.companyName, ed.RequesterName, args.projectName, ed.DaysPending, ed.RequestID)
+	return data, true
+}
+
+func (ed *CCLAApprovalListRequestExpiredEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("the CCLA Approval Request of user [%s] for project: [%s], company: [%s] expired after %d days without review - request id: %s",
+		ed.RequesterName, args.projectName, args.companyName, ed.DaysPending, ed.RequestID)
+	return data, true
+}
+
+func (ed *CCLAApprovalListRequestAutoApprovalRulesUpdatedEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	rules := "none"
+	if len(ed.Rules) > 0 {
+		rules = strings.Join(ed.Rules, "; ")
+	}
+	data := fmt.Sprintf("user [%s] updated the CCLA Approval Request auto-approval rules for project: [%s], company: [%s] - rules: %s",
+		args.userName, args.projectName, args.companyName, rules)
+	return data, true
+}
+
 func (ed *CLAManagerRequestCreatedEventData) GetEventString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("user [%s / %s / %s] added CLA Manager Request [%s] for Company: %s, Project: %s",
 		ed.UserLFID, ed.UserName, ed.UserEmail, ed.RequestID, ed.CompanyName, ed.ProjectName)
@@ -413,6 +583,18 @@ func (ed *CLAApprovalListRemoveGitHubOrgData) GetEventString(args *LogEventArgs)
 	return data, true
 }
 
+func (ed *CLAApprovalListAddGitHubTeamData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("CLA Manager [%s / %s / %s] added GitHub Team %s to the approval list for Company: %s, Project: %s",
+		ed.UserName, ed.UserEmail, ed.UserLFID, ed.ApprovalListGitHubTeam, args.companyName, args.projectName)
+	return data, true
+}
+
+func (ed *CLAApprovalListRemoveGitHubTeamData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("CLA Manager [%s / %s / %s] removed GitHub Team %s from the approval list for Company: %s, Project: %s",
+		ed.UserName, ed.UserEmail, ed.UserLFID, ed.ApprovalListGitHubTeam, args.companyName, args.projectName)
+	return data, true
+}
+
 func (ed *CCLAApprovalListRequestCreatedEventData) GetEventString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("user [%s] created a CCLA Approval Request for project: [%s], company: [%s] - request id: %s",
 		args.userName, args.projectName, args.companyName, ed.RequestID)
@@ -431,6 +613,36 @@ func (ed *ApprovalListGithubOrganizationDeletedEventData) GetEventString(args *L
 	return data, true
 }
 
+func (ed *ApprovalListEntryExpiredEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("approval list %s entry %s expired on %s and was removed from the approval list for Company: %s, Project: %s",
+		ed.ListType, ed.Value, ed.ExpirationDate, args.companyName, args.projectName)
+	return data, true
+}
+
+func (ed *ApprovalListSCIMEmailAddedEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("SCIM provisioning of user %s added Email %s to the approval list for Company: %s, Project: %s",
+		ed.SCIMUserName, ed.Email, args.companyName, args.projectName)
+	return data, true
+}
+
+func (ed *ApprovalListSCIMEmailRemovedEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("SCIM deprovisioning of user %s removed Email %s from the approval list for Company: %s, Project: %s",
+		ed.SCIMUserName, ed.Email, args.companyName, args.projectName)
+	return data, true
+}
+
+func (ed *SCIMTokenCreatedEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("CLA Manager %s created the SCIM token ending in %s for Company: %s, Project: %s",
+		args.userName, ed.TokenHint, args.companyName, args.projectName)
+	return data, true
+}
+
+func (ed *SCIMTokenRevokedEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("CLA Manager %s revoked the SCIM token ending in %s for Company: %s, Project: %s",
+		args.userName, ed.TokenHint, args.companyName, args.projectName)
+	return data, true
+}
+
 func (ed *ClaManagerAccessRequestAddedEventData) GetEventString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("user [%s] has requested to be cla manager for project [%s] company [%s]",
 		args.userName, ed.ProjectName, ed.CompanyName)
@@ -443,6 +655,18 @@ func (ed *ClaManagerAccessRequestDeletedEventData) GetEventString(args *LogEvent
 	return data, true
 }
 
+func (ed *ClaManagerAccessRequestReminderEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("reminder %d sent to the CLA Managers of company [%s] for the request of user [%s] to be cla manager for project [%s] pending for %d days - request id: %s",
+		ed.ReminderNumber, args.companyName, ed.RequesterName, args.projectName, ed.DaysPending, ed.RequestID)
+	return data, true
+}
+
+func (ed *ClaManagerAccessRequestExpiredEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("the request of user [%s] to be cla manager for company [%s] project [%s] expired after %d days without review - request id: %s",
+		ed.RequesterName, args.companyName, args.projectName, ed.DaysPending, ed.RequestID)
+	return data, true
+}
+
 func (ed *CLAGroupCreatedEventData) GetEventString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("user [%s] has created a CLA Group [%s - %s]",
 		args.userName, args.projectName, args.ProjectID)
@@ -491,6 +715,60 @@ func (ed *SignatureProjectInvalidatedEventData) GetEventString(args *LogEventArg
 	return data, true
 }
 
+func (ed *SignatureRevokedEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("user [%s] revoked the %s signature of [%s] for project [%s] on %s - reason: %s, signature id: %s",
+		args.userName, ed.SignatureType, ed.SignerName, args.projectName, ed.RevokedOn, ed.Reason, ed.SignatureID)
+	if ed.Comment != "" {
+		data = fmt.Sprintf("%s, comment: %s", data, ed.Comment)
+	}
+	return data, true
+}
+
+func (ed *ResignPolicyUpdatedEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("user [%s] updated the re-sign policy of project [%s] - re-sign required: %t, grace period days: %d",
+		args.userName, args.projectName, ed.ResignRequired, ed.GracePeriodDays)
+	return data, true
+}
+
+func (ed *ResignCampaignStartedEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("re-sign campaign started for project [%s] with %d previous version signatures - icla major version: %d, ccla major version: %d, campaign id: %s",
+		args.projectName, ed.TargetCount, ed.ICLAMajorVersion, ed.CCLAMajorVersion, ed.CampaignID)
+	if ed.Deadline != "" {
+		data = fmt.Sprintf("%s, deadline: %s", data, ed.Deadline)
+	}
+	return data, true
+}
+
+func (ed *ResignCampaignNotificationsSentEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("re-sign notifications sent to %d previous version signers of project [%s] - campaign id: %s",
+		ed.NotificationCount, args.projectName, ed.CampaignID)
+	return data, true
+}
+
+func (ed *ResignCampaignCompletedEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("re-sign campaign completed for project [%s] after %d signers re-signed - campaign id: %s",
+		args.projectName, ed.ResignedCount, ed.CampaignID)
+	return data, true
+}
+
+func (ed *ResignCampaignEnforcedEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("re-sign campaign deadline %s passed for project [%s], %d previous version signatures revoked - campaign id: %s",
+		ed.Deadline, args.projectName, ed.RevokedCount, ed.CampaignID)
+	return data, true
+}
+
+func (ed *SignedDocumentAuditFailedEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("signed document audit of project [%s] found %d mismatched and %d missing documents out of %d documents - signature ids: %s",
+		args.projectName, ed.MismatchCount, ed.MissingCount, ed.DocumentCount, strings.Join(ed.SignatureIDs, ", "))
+	return data, true
+}
+
+func (ed *ResignCampaignCancelledEventData) GetEventString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("user [%s] cancelled the re-sign campaign of project [%s] - campaign id: %s",
+		args.userName, args.projectName, ed.CampaignID)
+	return data, true
+}
+
 func (ed *ContributorNotifyCompanyAdminData) GetEventString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("user [%s] notified company admin by email: %s %s for company [%s / %s]",
 		args.userName, ed.AdminName, ed.AdminEmail, args.companyName, args.CompanyID)
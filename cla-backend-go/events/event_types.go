@@ -36,6 +36,8 @@ const (
 	UserUpdated        = "user.updated"
 	UserDeleted        = "user.deleted"
 
+	CLATemplateRolledBack = "cla_template.rolled_back"
+
 	GithubRepositoryAdded   = "github_repository.added"
 	GithubRepositoryDeleted = "github_repository.deleted"
 
@@ -50,17 +52,31 @@ const (
 	CompanyACLRequestApproved = "company_acl.request_approved"
 	CompanyACLRequestDenied   = "company_acl.request_denied"
 
+	CompanyMerged = "company.merged"
+
 	CCLAApprovalListRequestCreated  = "ccla_approval_list_request.created"
 	CCLAApprovalListRequestApproved = "ccla_approval_list_request.approved"
 	CCLAApprovalListRequestRejected = "ccla_approval_list_request.rejected"
+	CCLAApprovalListRequestReminder = "ccla_approval_list_request.reminder_sent"
+	CCLAApprovalListRequestExpired  = "ccla_approval_list_request.expired"
+
+	CCLAApprovalListRequestAutoApprovalRulesUpdated = "ccla_approval_list_request.auto_approval_rules_updated"
 
 	ApprovalListGithubOrganizationAdded   = "approval_list.github_organization_added"
 	ApprovalListGithubOrganizationDeleted = "approval_list.github_organization_deleted"
+	ApprovalListEntryExpired              = "approval_list.entry_expired"
+	ApprovalListSCIMEmailAdded            = "approval_list.scim_email_added"
+	ApprovalListSCIMEmailRemoved          = "approval_list.scim_email_removed"
+
+	SCIMTokenCreated = "scim.token_created"
+	SCIMTokenRevoked = "scim.token_revoked"
 
 	ClaManagerAccessRequestCreated  = "cla_manager.access_request_created"
 	ClaManagerAccessRequestApproved = "cla_manager.access_request_approved"
 	ClaManagerAccessRequestDenied   = "cla_manager.access_request_denied"
 	ClaManagerAccessRequestDeleted  = "cla_manager.access_request_deleted"
+	ClaManagerAccessRequestReminder = "cla_manager.access_request_reminder_sent"
+	ClaManagerAccessRequestExpired  = "cla_manager.access_request_expired"
 
 	ClaApprovalListUpdated = "cla_manager.approval_list_updated"
 
@@ -73,7 +89,16 @@ const (
 	CLAGroupUpdated = "cla_group.updated"
 	CLAGroupDeleted = "cla_group.deleted"
 
-	InvalidatedSignature = "signature.invalidated"
+	ResignPolicyUpdated             = "cla_group.resign_policy_updated"
+	ResignCampaignStarted           = "cla_group.resign_campaign_started"
+	ResignCampaignNotificationsSent = "cla_group.resign_campaign_notifications_sent"
+	ResignCampaignCompleted         = "cla_group.resign_campaign_completed"
+	ResignCampaignEnforced          = "cla_group.resign_campaign_enforced"
+	ResignCampaignCancelled         = "cla_group.resign_campaign_cancelled"
+
+	InvalidatedSignature      = "signature.invalidated"
+	RevokedSignature          = "signature.revoked"
+	SignedDocumentAuditFailed = "signature.signed_document_audit_failed"
 
 	ContributorNotifyCompanyAdminType = "contributor.notify_company_admin"
 	ContributorNotifyCLADesigneeType  = "contributor.notify_cla_designee"
@@ -0,0 +1,289 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	eventOps "github.com/communitybridge/easycla/cla-backend-go/gen/restapi/operations/events"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+)
+
+// memoryEvent is the stored representation of an event - includes the attributes which back the DynamoDB indexes
+type memoryEvent struct {
+	Event
+	ContainsPII               bool   `dynamodbav:"contains_pii"`
+	EventDate                 string `dynamodbav:"event_date"`
+	CompanySFIDFoundationSFID string `dynamodbav:"company_sfid_foundation_sfid"`
+	CompanySFIDProjectID      string `dynamodbav:"company_sfid_project_id"`
+}
+
+// memoryRepository data model
+type memoryRepository struct {
+	store     *storage.MemoryStore
+	tableName string
+}
+
+// NewMemoryRepository creates a new instance of the event repository backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string) Repository {
+	return &memoryRepository{
+		store:     store,
+		tableName: fmt.Sprintf("cla-%s-events", stage),
+	}
+}
+
+// CreateEvent will create event in the store
+func (repo *memoryRepository) CreateEvent(event *models.Event) error {
+	if event.UserID == "" {
+		return ErrUserIDRequired
+	}
+	if event.EventType == "" {
+		return ErrEventTypeRequired
+	}
+	eventID, err := uuid.NewV4()
+	if err != nil {
+		log.Warnf("Unable to generate a UUID for a whitelist request, error: %v", err)
+		return err
+	}
+
+	currentTime, currentTimeString := utils.CurrentTime()
+	item := memoryEvent{
+		Event: Event{
+			EventID:                eventID.String(),
+			EventType:              event.EventType,
+			EventUserID:            event.UserID,
+			EventUserName:          event.UserName,
+			EventLfUsername:        event.LfUsername,
+			EventProjectID:         event.EventProjectID,
+			EventProjectExternalID: event.EventProjectExternalID,
+			EventProjectName:       event.EventProjectName,
+			EventCompanyID:         event.EventCompanyID,
+			EventCompanyName:       event.EventCompanyName,
+			EventTime:              currentTimeString,
+			EventTimeEpoch:         currentTime.Unix(),
+			EventData:              event.EventData,
+		},
+		ContainsPII: event.ContainsPII,
+		EventDate:   toDateFormat(currentTime),
+	}
+
+	if err := repo.store.Put(repo.tableName, item.EventID, item); err != nil {
+		log.Warnf("Unable to create a new event, error: %v", err)
+		return err
+	}
+	log.Printf("added event : %s", eventID.String())
+	return nil
+}
+
+// AddDataToEvent adds the salesforce details to the event
+func (repo *memoryRepository) AddDataToEvent(eventID, foundationSFID, projectSFID, projectSFName, companySFID, projectID string) error {
+	var item memoryEvent
+	return repo.store.Update(repo.tableName, eventID, &item, func() error {
+		if foundationSFID != "" {
+			item.EventFoundationSFID = foundationSFID
+		}
+		if projectSFID != "" {
+			item.EventProjectSFID = projectSFID
+		}
+		if projectSFName != "" {
+			item.EventSFProjectName = projectSFName
+		}
+		if companySFID != "" {
+			item.EventCompanySFID = companySFID
+			if foundationSFID != "" {
+				item.CompanySFIDFoundationSFID = fmt.Sprintf("%s#%s", companySFID, foundationSFID)
+			}
+			if projectID != "" {
+				item.CompanySFIDProjectID = fmt.Sprintf("%s#%s", companySFID, projectID)
+			}
+		}
+		return nil
+	})
+}
+
+// SearchEvents returns list of events matching with filter criteria.
+func (repo *memoryRepository) SearchEvents(params *eventOps.SearchEventsParams, pageSize int64) (*models.EventList, error) {
+	if params.ProjectID == nil {
+		return nil, errors.New("invalid request. projectID is compulsory")
+	}
+	after, err := parseEpoch(params.After)
+	if err != nil {
+		return nil, err
+	}
+	before, err := parseEpoch(params.Before)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := repo.scan(func(e *memoryEvent) bool {
+		switch {
+		case e.EventProjectID != *params.ProjectID:
+			return false
+		case params.CompanyID != nil && e.EventCompanyID != *params.CompanyID:
+			return false
+		case params.UserID != nil && e.EventUserID != *params.UserID:
+			return false
+		case params.EventType != nil && e.EventType != *params.EventType:
+			return false
+		case after != nil && e.EventTimeEpoch < *after:
+			return false
+		case before != nil && e.EventTimeEpoch > *before:
+			return false
+		case params.UserName != nil && !strings.Contains(strings.ToLower(e.EventUserName), strings.ToLower(*params.UserName)):
+			return false
+		case params.CompanyName != nil && !strings.Contains(strings.ToLower(e.EventCompanyName), strings.ToLower(*params.CompanyName)):
+			return false
+		case params.SearchTerm != nil && !strings.Contains(e.EventData, *params.SearchTerm):
+			return false
+		}
+		return true
+	}, params.SortOrder != nil && *params.SortOrder == "desc")
+	if err != nil {
+		return nil, err
+	}
+	return page(items, params.NextKey, pageSize)
+}
+
+// GetRecentEvents returns the most recent events which do not contain PII
+func (repo *memoryRepository) GetRecentEvents(pageSize int64) (*models.EventList, error) {
+	oldest := time.Now().Add(-(30 * 24 * time.Hour)).Unix()
+	items, err := repo.scan(func(e *memoryEvent) bool {
+		return !e.ContainsPII && e.EventProjectID != "" && e.EventTimeEpoch >= oldest
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(items)) > pageSize {
+		items = items[0:pageSize]
+	}
+	return &models.EventList{
+		Events: items,
+	}, nil
+}
+
+// GetCompanyFoundationEvents returns the list of events for foundation and company
+func (repo *memoryRepository) GetCompanyFoundationEvents(companySFID, foundationSFID string, nextKey *string, paramPageSize *int64, all bool) (*models.EventList, error) {
+	key := fmt.Sprintf("%s#%s", companySFID, foundationSFID)
+	return repo.queryEvents(func(e *memoryEvent) bool {
+		return e.CompanySFIDFoundationSFID == key
+	}, nextKey, paramPageSize, all, nil)
+}
+
+// GetCompanyClaGroupEvents returns the list of events for cla group and the company
+func (repo *memoryRepository) GetCompanyClaGroupEvents(companySFID, claGroupID string, nextKey *string, paramPageSize *int64, all bool) (*models.EventList, error) {
+	key := fmt.Sprintf("%s#%s", companySFID, claGroupID)
+	return repo.queryEvents(func(e *memoryEvent) bool {
+		return e.CompanySFIDProjectID == key
+	}, nextKey, paramPageSize, all, nil)
+}
+
+// GetFoundationEvents returns the list of foundation events
+func (repo *memoryRepository) GetFoundationEvents(foundationSFID string, nextKey *string, paramPageSize *int64, all bool, searchTerm *string) (*models.EventList, error) {
+	return repo.queryEvents(func(e *memoryEvent) bool {
+		return e.EventFoundationSFID == foundationSFID
+	}, nextKey, paramPageSize, all, searchTerm)
+}
+
+// GetClaGroupEvents returns the list of cla-group events
+func (repo *memoryRepository) GetClaGroupEvents(claGroupID string, nextKey *string, paramPageSize *int64, all bool, searchTerm *string) (*models.EventList, error) {
+	return repo.queryEvents(func(e *memoryEvent) bool {
+		return e.EventProjectID == claGroupID
+	}, nextKey, paramPageSize, all, searchTerm)
+}
+
+// queryEvents returns the events matching the filter, newest first
+func (repo *memoryRepository) queryEvents(filter func(e *memoryEvent) bool, nextKey *string, pageSize *int64, all bool, searchTerm *string) (*models.EventList, error) {
+	if searchTerm != nil {
+		searchTerm = aws.String(strings.ToLower(*searchTerm))
+	}
+	items, err := repo.scan(func(e *memoryEvent) bool {
+		if !filter(e) {
+			return false
+		}
+		return searchTerm == nil || strings.Contains(strings.ToLower(e.EventData), *searchTerm)
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		return &models.EventList{Events: items}, nil
+	}
+	if pageSize == nil {
+		pageSize = aws.Int64(DefaultPageSize)
+	}
+	return page(items, nextKey, *pageSize)
+}
+
+// scan returns the events matching the filter sorted by event time
+func (repo *memoryRepository) scan(filter func(e *memoryEvent) bool, descending bool) ([]*models.Event, error) {
+	var items []*memoryEvent
+	if err := repo.store.Scan(repo.tableName, &items); err != nil {
+		log.Warnf("error retrieving events. error = %s", err.Error())
+		return nil, err
+	}
+	var matched []*memoryEvent
+	for _, item := range items {
+		if filter(item) {
+			matched = append(matched, item)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if descending {
+			return matched[i].EventTimeEpoch > matched[j].EventTimeEpoch
+		}
+		return matched[i].EventTimeEpoch < matched[j].EventTimeEpoch
+	})
+	events := make([]*models.Event, 0, len(matched))
+	for _, item := range matched {
+		events = append(events, item.toEvent())
+	}
+	return events, nil
+}
+
+// page returns a single page of events - the next key is the offset of the following page
+func page(events []*models.Event, nextKey *string, pageSize int64) (*models.EventList, error) {
+	var offset int64
+	if nextKey != nil && *nextKey != "" {
+		var err error
+		offset, err = strconv.ParseInt(*nextKey, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if offset > int64(len(events)) {
+		offset = int64(len(events))
+	}
+	end := offset + pageSize
+	var lastEvaluatedKey string
+	if end < int64(len(events)) {
+		lastEvaluatedKey = strconv.FormatInt(end, 10)
+	} else {
+		end = int64(len(events))
+	}
+	return &models.EventList{
+		Events:  events[offset:end],
+		NextKey: lastEvaluatedKey,
+	}, nil
+}
+
+func parseEpoch(value *string) (*int64, error) {
+	if value == nil {
+		return nil, nil
+	}
+	epoch, err := strconv.ParseInt(*value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &epoch, nil
+}
@@ -0,0 +1,57 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+)
+
+// csvFlushInterval is the number of rows written between two flushes of the CSV writer
+const csvFlushInterval = 500
+
+// csvExporter writes the header row of each sheet followed by its rows
+type csvExporter struct {
+	w       *csv.Writer
+	columns []Column
+	rows    int
+}
+
+func newCSVExporter(w io.Writer) *csvExporter {
+	return &csvExporter{w: csv.NewWriter(w)}
+}
+
+func (e *csvExporter) BeginSheet(name string, columns []Column) error {
+	e.columns = columns
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header
+	}
+	return e.w.Write(headers)
+}
+
+func (e *csvExporter) WriteRow(values []interface{}) error {
+	if len(values) != len(e.columns) {
+		return errors.New("csv export row does not match the sheet columns")
+	}
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = textValue(value, e.columns[i])
+	}
+	if err := e.w.Write(record); err != nil {
+		return err
+	}
+	e.rows++
+	if e.rows%csvFlushInterval == 0 {
+		e.w.Flush()
+		return e.w.Error()
+	}
+	return nil
+}
+
+func (e *csvExporter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
@@ -0,0 +1,125 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"time"
+)
+
+// export formats
+const (
+	FormatCSV       = "csv"
+	FormatJSONLines = "jsonl"
+	FormatXLSX      = "xlsx"
+)
+
+// content types of the export formats
+const (
+	CSVMime       = "text/csv"
+	JSONLinesMime = "application/x-ndjson"
+	XLSXMime      = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// ErrUnsupportedFormat is returned when the requested export format is not supported
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// Column is a column of an export - Name is the key of the column in the JSON Lines records, Header the column
+// header of the CSV and XLSX sheets and DateLayout the layout of the date values in the CSV sheets, RFC3339 if empty
+type Column struct {
+	Name       string
+	Header     string
+	DateLayout string
+}
+
+// Exporter writes the rows of an export in one of the export formats. The rows are written sheet by sheet, the
+// values of a row are in the order of the columns of its sheet. The cell type follows the value type: string, int,
+// int64, float64, bool and time.Time values are supported, nil is an empty cell. Formats without sheets, CSV and
+// JSON Lines, write the rows of all the sheets one after the other.
+type Exporter interface {
+	BeginSheet(name string, columns []Column) error
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// NewExporter returns the exporter of the format writing to w
+func NewExporter(format string, w io.Writer) (Exporter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVExporter(w), nil
+	case FormatJSONLines:
+		return newJSONLinesExporter(w), nil
+	case FormatXLSX:
+		return newXLSXExporter(w), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+}
+
+// ContentType returns the content type of the export format
+func ContentType(format string) string {
+	switch format {
+	case FormatJSONLines:
+		return JSONLinesMime
+	case FormatXLSX:
+		return XLSXMime
+	}
+	return CSVMime
+}
+
+// Filename returns the file name of the export with the extension of the format
+func Filename(name, format string) string {
+	return name + "." + format
+}
+
+// NegotiateFormat returns the export format of the request - the format parameter takes precedence over the accept
+// header, the first media type of the accept header matching an export format is used otherwise. CSV is the default
+// format.
+func NegotiateFormat(format *string, accept string) (string, error) {
+	if format != nil && *format != "" {
+		f := strings.ToLower(*format)
+		if f != FormatCSV && f != FormatJSONLines && f != FormatXLSX {
+			return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, *format)
+		}
+		return f, nil
+	}
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case CSVMime:
+			return FormatCSV, nil
+		case JSONLinesMime, "application/jsonl", "application/x-jsonlines":
+			return FormatJSONLines, nil
+		case XLSXMime:
+			return FormatXLSX, nil
+		}
+	}
+	return FormatCSV, nil
+}
+
+// textValue returns the text of the value for the text based export formats
+func textValue(value interface{}, column Column) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		layout := column.DateLayout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return v.UTC().Format(layout)
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,75 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+// jsonLinesExporter writes each row as a JSON object keyed by the column names, one object per line. Dates are
+// RFC3339 formatted, the sheet names and headers are not written.
+type jsonLinesExporter struct {
+	w       *bufio.Writer
+	columns []Column
+}
+
+func newJSONLinesExporter(w io.Writer) *jsonLinesExporter {
+	return &jsonLinesExporter{w: bufio.NewWriter(w)}
+}
+
+func (e *jsonLinesExporter) BeginSheet(name string, columns []Column) error {
+	e.columns = columns
+	return nil
+}
+
+func (e *jsonLinesExporter) WriteRow(values []interface{}) error {
+	if len(values) != len(e.columns) {
+		return errors.New("json lines export row does not match the sheet columns")
+	}
+	// the object is written key by key to keep the column order, a map would be sorted by key
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if t, ok := value.(time.Time); ok {
+			value = nil
+			if !t.IsZero() {
+				value = t.UTC().Format(time.RFC3339)
+			}
+		}
+		if err := writeJSON(&b, e.columns[i].Name); err != nil {
+			return err
+		}
+		b.WriteByte(':')
+		if err := writeJSON(&b, value); err != nil {
+			return err
+		}
+	}
+	b.WriteString("}\n")
+	_, err := e.w.Write(b.Bytes())
+	return err
+}
+
+// writeJSON writes the JSON encoding of the value to the buffer, without escaping the HTML characters
+func writeJSON(b *bytes.Buffer, value interface{}) error {
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return err
+	}
+	// the encoder terminates the value with a new line
+	b.Truncate(b.Len() - 1)
+	return nil
+}
+
+func (e *jsonLinesExporter) Close() error {
+	return e.w.Flush()
+}
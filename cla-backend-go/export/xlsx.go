@@ -0,0 +1,257 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// the maximum length of an XLSX sheet name
+const xlsxMaxSheetNameLength = 31
+
+// excelEpoch is the day zero of the XLSX date serial numbers
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// the cell styles of styles.xml
+const (
+	xlsxHeaderStyle = 1
+	xlsxDateStyle   = 2
+)
+
+const xlsxContentTypesHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// the styles define a bold header cell style and a yyyy-mm-dd hh:mm:ss date cell style
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+	`</styleSheet>`
+
+// xlsxExporter streams the sheets of an XLSX workbook, the zip entry of each sheet is written row by row and the
+// workbook parts listing the sheets are written when the exporter is closed
+type xlsxExporter struct {
+	zw      *zip.Writer
+	sheet   io.Writer
+	sheets  []string
+	columns []Column
+	rowNum  int
+}
+
+func newXLSXExporter(w io.Writer) *xlsxExporter {
+	return &xlsxExporter{zw: zip.NewWriter(w)}
+}
+
+func (e *xlsxExporter) BeginSheet(name string, columns []Column) error {
+	if err := e.endSheet(); err != nil {
+		return err
+	}
+	sheet, err := e.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(e.sheets)+1))
+	if err != nil {
+		return err
+	}
+	e.sheet = sheet
+	e.sheets = append(e.sheets, xlsxSheetName(name, e.sheets))
+	e.columns = columns
+	e.rowNum = 0
+	if _, err = io.WriteString(e.sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return err
+	}
+	headers := make([]interface{}, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header
+	}
+	return e.writeRow(headers, xlsxHeaderStyle)
+}
+
+func (e *xlsxExporter) WriteRow(values []interface{}) error {
+	if e.sheet == nil {
+		return errors.New("xlsx export row written before the sheet")
+	}
+	if len(values) != len(e.columns) {
+		return errors.New("xlsx export row does not match the sheet columns")
+	}
+	return e.writeRow(values, 0)
+}
+
+// writeRow writes the row with the cell types following the value types, style is the style of the string cells
+func (e *xlsxExporter) writeRow(values []interface{}, style int) error {
+	e.rowNum++
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<row r="%d">`, e.rowNum)
+	for i, value := range values {
+		ref := xlsxColumnName(i) + strconv.Itoa(e.rowNum)
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			if v == "" {
+				continue
+			}
+			fmt.Fprintf(&b, `<c r="%s" t="inlineStr"`, ref)
+			if style != 0 {
+				fmt.Fprintf(&b, ` s="%d"`, style)
+			}
+			b.WriteString(`><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(&b, []byte(v)); err != nil {
+				return err
+			}
+			b.WriteString(`</t></is></c>`)
+		case time.Time:
+			if v.IsZero() {
+				continue
+			}
+			serial := float64(v.UTC().Sub(excelEpoch)) / float64(24*time.Hour)
+			fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, xlsxDateStyle, strconv.FormatFloat(serial, 'f', -1, 64))
+		case bool:
+			boolValue := 0
+			if v {
+				boolValue = 1
+			}
+			fmt.Fprintf(&b, `<c r="%s" t="b"><v>%d</v></c>`, ref, boolValue)
+		case int, int64, float64:
+			fmt.Fprintf(&b, `<c r="%s"><v>%v</v></c>`, ref, v)
+		default:
+			return fmt.Errorf("unsupported xlsx export value type: %T", value)
+		}
+	}
+	b.WriteString(`</row>`)
+	_, err := e.sheet.Write(b.Bytes())
+	return err
+}
+
+// endSheet terminates the sheet being written, if any
+func (e *xlsxExporter) endSheet() error {
+	if e.sheet == nil {
+		return nil
+	}
+	_, err := io.WriteString(e.sheet, `</sheetData></worksheet>`)
+	e.sheet = nil
+	return err
+}
+
+func (e *xlsxExporter) Close() error {
+	// a workbook requires at least one sheet
+	if len(e.sheets) == 0 {
+		if err := e.BeginSheet("Sheet1", nil); err != nil {
+			return err
+		}
+	}
+	if err := e.endSheet(); err != nil {
+		return err
+	}
+
+	var contentTypes, workbook, workbookRels bytes.Buffer
+	contentTypes.WriteString(xlsxContentTypesHeader)
+	workbook.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	workbookRels.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i, name := range e.sheets {
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		workbook.WriteString(`<sheet name="`)
+		if err := xml.EscapeText(&workbook, []byte(name)); err != nil {
+			return err
+		}
+		fmt.Fprintf(&workbook, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	contentTypes.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(e.sheets)+1)
+	workbookRels.WriteString(`</Relationships>`)
+
+	parts := []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", contentTypes.Bytes()},
+		{"_rels/.rels", []byte(xlsxRootRels)},
+		{"xl/workbook.xml", workbook.Bytes()},
+		{"xl/_rels/workbook.xml.rels", workbookRels.Bytes()},
+		{"xl/styles.xml", []byte(xlsxStyles)},
+	}
+	for _, part := range parts {
+		w, err := e.zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err = w.Write(part.content); err != nil {
+			return err
+		}
+	}
+	return e.zw.Close()
+}
+
+// xlsxColumnName returns the name of the column at the zero based index - A, B, ..., Z, AA, AB, ...
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// xlsxSheetName returns a valid sheet name, unique among the existing sheet names - the characters not allowed in
+// sheet names are replaced and the name is truncated to the maximum sheet name length
+func xlsxSheetName(name string, existing []string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" {
+		name = "Sheet"
+	}
+	candidate := truncateRunes(name, xlsxMaxSheetNameLength)
+	for i := 2; containsFold(existing, candidate); i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		candidate = truncateRunes(name, xlsxMaxSheetNameLength-len(suffix)) + suffix
+	}
+	return candidate
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
+
+// containsFold returns true if the list contains the value, ignoring the case as the sheet names do
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gerrits
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+)
+
+// NewMemoryRepository creates a new gerrit repository backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string) Repository {
+	return &memoryRepo{
+		store:     store,
+		tableName: fmt.Sprintf("cla-%s-gerrit-instances", stage),
+	}
+}
+
+type memoryRepo struct {
+	store     *storage.MemoryStore
+	tableName string
+}
+
+func (repo *memoryRepo) GetClaGroupGerrits(projectID string, projectSFID *string) (*models.GerritList, error) {
+	var gerrits []*Gerrit
+	if err := repo.store.Scan(repo.tableName, &gerrits); err != nil {
+		return nil, err
+	}
+	resultList := make([]*models.Gerrit, 0)
+	for _, g := range gerrits {
+		if g.ProjectID != projectID {
+			continue
+		}
+		if projectSFID != nil && g.ProjectSFID != *projectSFID {
+			continue
+		}
+		resultList = append(resultList, g.toModel())
+	}
+	sort.Slice(resultList, func(i, j int) bool {
+		return resultList[i].GerritName < resultList[j].GerritName
+	})
+	return &models.GerritList{List: resultList}, nil
+}
+
+func (repo *memoryRepo) DeleteGerrit(gerritID string) error {
+	return repo.store.Delete(repo.tableName, gerritID)
+}
+
+func (repo *memoryRepo) GetGerrit(gerritID string) (*models.Gerrit, error) {
+	var gerrit Gerrit
+	found, err := repo.store.Get(repo.tableName, gerritID, &gerrit)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrGerritNotFound
+	}
+	return gerrit.toModel(), nil
+}
+
+func (repo *memoryRepo) AddGerrit(input *models.Gerrit) (*models.Gerrit, error) {
+	gerritID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	_, currentTime := utils.CurrentTime()
+	gerrit := &Gerrit{
+		DateCreated:   currentTime,
+		DateModified:  currentTime,
+		GerritID:      gerritID.String(),
+		GerritName:    input.GerritName,
+		GerritURL:     input.GerritURL.String(),
+		GroupIDCcla:   input.GroupIDCcla,
+		GroupIDIcla:   input.GroupIDCcla,
+		GroupNameCcla: input.GroupNameCcla,
+		GroupNameIcla: input.GroupNameIcla,
+		ProjectID:     input.ProjectID,
+		ProjectSFID:   input.ProjectSFID,
+		Version:       "v1",
+	}
+	if err := repo.store.Put(repo.tableName, gerrit.GerritID, gerrit); err != nil {
+		return nil, err
+	}
+	return repo.GetGerrit(gerrit.GerritID)
+}
@@ -0,0 +1,124 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/google/go-github/github"
+)
+
+// errors
+var (
+	ErrGithubTeamNotFound = errors.New("github team not found")
+)
+
+// TeamMembersCacheTTL is how long the members of a team are cached before they are loaded from GitHub again
+const TeamMembersCacheTTL = 10 * time.Minute
+
+type teamMembersCacheEntry struct {
+	members map[string]bool
+	expires time.Time
+}
+
+// teamMembersCache holds the lower case logins of the team members keyed by organization/team-slug - team membership
+// is checked for every contributor of every CCLA with a team approval list, so the lookups are shared
+var teamMembersCache = struct {
+	sync.Mutex
+	entries map[string]teamMembersCacheEntry
+}{entries: map[string]teamMembersCacheEntry{}}
+
+// IsTeamMember returns true if the github user is a member of the team of the organization - the organization must
+// have the EasyCLA GitHub App installed with the specified installation ID
+func IsTeamMember(installationID int64, organizationName, teamSlug, user string) (bool, error) {
+	members, err := getTeamMembers(installationID, organizationName, teamSlug)
+	if err != nil {
+		return false, err
+	}
+	return members[strings.ToLower(user)], nil
+}
+
+// getTeamMembers returns the team members from the cache, loading them from GitHub when missing or expired
+func getTeamMembers(installationID int64, organizationName, teamSlug string) (map[string]bool, error) {
+	key := strings.ToLower(organizationName + "/" + teamSlug)
+
+	teamMembersCache.Lock()
+	entry, ok := teamMembersCache.entries[key]
+	teamMembersCache.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.members, nil
+	}
+
+	members, err := loadTeamMembers(installationID, organizationName, teamSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	teamMembersCache.Lock()
+	teamMembersCache.entries[key] = teamMembersCacheEntry{
+		members: members,
+		expires: time.Now().Add(TeamMembersCacheTTL),
+	}
+	teamMembersCache.Unlock()
+	return members, nil
+}
+
+// loadTeamMembers loads the members of the team from GitHub using the GitHub App installation client
+func loadTeamMembers(installationID int64, organizationName, teamSlug string) (map[string]bool, error) {
+	client, err := newGithubAppClient(installationID)
+	if err != nil {
+		logging.Warnf("loadTeamMembers - unable to create the github client for installation ID: %d, error = %s", installationID, err.Error())
+		return nil, err
+	}
+
+	teamID, err := findTeamID(client, organizationName, teamSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	members := map[string]bool{}
+	opts := &github.TeamListTeamMembersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		users, resp, listErr := client.Teams.ListTeamMembers(context.TODO(), teamID, opts)
+		if listErr != nil {
+			logging.Warnf("loadTeamMembers - listing members of team %s/%s failed, error = %s", organizationName, teamSlug, listErr.Error())
+			return nil, fmt.Errorf("unable to get the members of github team %s/%s", organizationName, teamSlug)
+		}
+		for _, user := range users {
+			members[strings.ToLower(user.GetLogin())] = true
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return members, nil
+}
+
+// findTeamID returns the ID of the team with the specified slug
+func findTeamID(client *github.Client, organizationName, teamSlug string) (int64, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		teams, resp, err := client.Teams.ListTeams(context.TODO(), organizationName, opts)
+		if err != nil {
+			logging.Warnf("findTeamID - listing teams of organization %s failed, error = %s", organizationName, err.Error())
+			return 0, fmt.Errorf("unable to get the teams of github organization %s", organizationName)
+		}
+		for _, team := range teams {
+			if strings.EqualFold(team.GetSlug(), teamSlug) {
+				return team.GetID(), nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return 0, ErrGithubTeamNotFound
+		}
+		opts.Page = resp.NextPage
+	}
+}
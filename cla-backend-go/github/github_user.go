@@ -2,21 +2,51 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/google/go-github/github"
 )
 
+// ErrGithubUserNotFound is returned when the github user doesn't exist
+var ErrGithubUserNotFound = errors.New("github user not found")
+
 // GetUserDetails return github users details
 func GetUserDetails(user string) (*github.User, error) {
 	client := newGithubOauthClient()
-	userResp, _, err := client.Users.Get(context.TODO(), user)
+	userResp, resp, err := client.Users.Get(context.TODO(), user)
 	if err != nil {
 		logging.Warnf("GetUserDetails failed for user : %s, error = %s\n", user, err.Error())
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, ErrGithubUserNotFound
+		}
 		err = fmt.Errorf("unable to get github info of %s", user)
 		return nil, err
 	}
 	return userResp, nil
 }
+
+// GetUserOrganizations returns the login names of the github organizations the user is a public member of
+func GetUserOrganizations(user string) ([]string, error) {
+	client := newGithubOauthClient()
+	var orgNames []string
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		orgs, resp, err := client.Organizations.List(context.TODO(), user, opts)
+		if err != nil {
+			logging.Warnf("GetUserOrganizations failed for user : %s, error = %s\n", user, err.Error())
+			return nil, fmt.Errorf("unable to get github organizations of %s", user)
+		}
+		for _, org := range orgs {
+			orgNames = append(orgNames, org.GetLogin())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return orgNames, nil
+}
@@ -19,7 +19,7 @@ import (
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/gofrs/uuid"
 	ghLib "github.com/google/go-github/github"
-	"github.com/savaki/dynastore"
+	"github.com/gorilla/sessions"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/github"
 )
@@ -30,7 +30,7 @@ const (
 )
 
 // Configure API call
-func Configure(api *operations.ClaAPI, clientID, clientSecret, accessToken string, sessionStore *dynastore.Store) {
+func Configure(api *operations.ClaAPI, clientID, clientSecret, accessToken string, sessionStore sessions.Store) {
 	oauthConfig := &oauth2.Config{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
@@ -0,0 +1,98 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package github_organizations
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+type memoryRepository struct {
+	store              *storage.MemoryStore
+	githubOrgTableName string
+}
+
+// NewMemoryRepository creates a new instance of the githubOrganizations repository backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string) Repository {
+	return memoryRepository{
+		store:              store,
+		githubOrgTableName: fmt.Sprintf("cla-%s-github-orgs", stage),
+	}
+}
+
+func (repo memoryRepository) AddGithubOrganization(externalProjectID string, projectSFID string, input *models.CreateGithubOrganization) (*models.GithubOrganization, error) {
+	_, currentTime := utils.CurrentTime()
+	githubOrg := &GithubOrganization{
+		DateCreated:                currentTime,
+		DateModified:               currentTime,
+		OrganizationInstallationID: 0,
+		OrganizationName:           input.OrganizationName,
+		OrganizationNameLower:      strings.ToLower(input.OrganizationName),
+		OrganizationSfid:           externalProjectID,
+		ProjectSFID:                projectSFID,
+		Version:                    "v1",
+	}
+	err := repo.store.Create(repo.githubOrgTableName, githubOrg.OrganizationName, githubOrg)
+	if err != nil {
+		if err == storage.ErrItemAlreadyExists {
+			return nil, errors.New("github organization already exist")
+		}
+		log.Error("cannot put github organization in memory store", err)
+		return nil, err
+	}
+	return toModel(githubOrg), nil
+}
+
+func (repo memoryRepository) DeleteGithubOrganization(externalProjectID string, projectSFID string, githubOrgName string) error {
+	var org GithubOrganization
+	found, err := repo.store.Get(repo.githubOrgTableName, githubOrgName, &org)
+	if err != nil {
+		return err
+	}
+	if !found || (externalProjectID != "" && org.OrganizationSfid != externalProjectID) || (externalProjectID == "" && org.ProjectSFID != projectSFID) {
+		errMsg := fmt.Sprintf("error deleting github organization: %s - conditional check failed", githubOrgName)
+		log.Warnf(errMsg)
+		return errors.New(errMsg)
+	}
+	return repo.store.Delete(repo.githubOrgTableName, githubOrgName)
+}
+
+func (repo memoryRepository) GetGithubOrganizations(externalProjectID string, projectSFID string) (*models.GithubOrganizations, error) {
+	var orgs []*GithubOrganization
+	if err := repo.store.Scan(repo.githubOrgTableName, &orgs); err != nil {
+		return nil, err
+	}
+	var resultOutput []*GithubOrganization
+	for _, org := range orgs {
+		if externalProjectID != "" && org.OrganizationSfid == externalProjectID ||
+			externalProjectID == "" && org.ProjectSFID == projectSFID {
+			resultOutput = append(resultOutput, org)
+		}
+	}
+	if len(resultOutput) == 0 {
+		return &models.GithubOrganizations{
+			List: []*models.GithubOrganization{},
+		}, nil
+	}
+	ghOrgList := buildGithubOrganizationListModels(resultOutput)
+	return &models.GithubOrganizations{List: ghOrgList}, nil
+}
+
+func (repo memoryRepository) GetGithubOrganization(githubOrganizationName string) (*models.GithubOrganization, error) {
+	var org GithubOrganization
+	found, err := repo.store.Get(repo.githubOrgTableName, githubOrganizationName, &org)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrOrganizationDoesNotExist
+	}
+	return toModel(&org), nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	ini "github.com/communitybridge/easycla/cla-backend-go/init"
@@ -21,6 +22,9 @@ type Service struct {
 	commit    string
 	branch    string
 	buildDate string
+	// storageDriver is the storage driver of the repositories, the DynamoDB tables are only checked with the DynamoDB
+	// driver
+	storageDriver string
 }
 
 // HealthService interface
@@ -29,12 +33,13 @@ type HealthService interface { // nolint
 }
 
 // New is a simple helper function to create a health service instance
-func New(version, commit, branch, buildDate string) Service {
+func New(version, commit, branch, buildDate, storageDriver string) Service {
 	return Service{
-		version:   version,
-		commit:    commit,
-		branch:    branch,
-		buildDate: buildDate,
+		version:       version,
+		commit:        commit,
+		branch:        branch,
+		buildDate:     buildDate,
+		storageDriver: storageDriver,
 	}
 }
 
@@ -51,7 +56,10 @@ func (s Service) HealthCheck(ctx context.Context) (*models.Health, error) {
 
 	var allStatus []*models.HealthStatus
 	allStatus = append(allStatus, &hs)
-	allStatus = append(allStatus, getDynamoTableStatus()...)
+	// The in-memory storage driver has no tables to check and works without AWS
+	if s.storageDriver != storage.DriverMemory {
+		allStatus = append(allStatus, getDynamoTableStatus()...)
+	}
 
 	var status = "healthy"
 	for _, item := range allStatus {
@@ -0,0 +1,120 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// page is the content of a page of the document, in the PDF coordinates - the origin is the bottom left corner of the
+// page
+type page struct {
+	content bytes.Buffer
+}
+
+// text shows the WinAnsi encoded text with its baseline starting at the position, the word spacing is added to the
+// width of each space of the text
+func (p *page) text(f *font, size float64, x, y, wordSpacing float64, text []byte) {
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s Tw 1 0 0 1 %s %s Tm (", f.resource, formatNumber(size), formatNumber(wordSpacing), formatNumber(x), formatNumber(y))
+	for _, c := range text {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			p.content.WriteByte('\\')
+			p.content.WriteByte(c)
+		case c < 32 || c > 126:
+			fmt.Fprintf(&p.content, "\\%03o", c)
+		default:
+			p.content.WriteByte(c)
+		}
+	}
+	p.content.WriteString(") Tj ET\n")
+}
+
+// line strokes a horizontal line
+func (p *page) line(x1, x2, y, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", formatNumber(width), formatNumber(x1), formatNumber(y), formatNumber(x2), formatNumber(y))
+}
+
+// writeDocument writes the PDF document of the pages, the fonts are the standard fonts of the PDF readers and the
+// content streams are compressed
+func writeDocument(pages []*page, width, height float64) ([]byte, error) {
+	var b bytes.Buffer
+	var offsets []int
+	beginObject := func() int {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n", len(offsets))
+		return len(offsets)
+	}
+	endObject := func() {
+		b.WriteString("endobj\n")
+	}
+
+	// The catalog, the page tree and the fonts come first, followed by each page and its content
+	const catalogObject, pagesObject, firstFontObject = 1, 2, 3
+	firstPageObject := firstFontObject + len(fonts)
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	beginObject()
+	fmt.Fprintf(&b, "<< /Type /Catalog /Pages %d 0 R >>\n", pagesObject)
+	endObject()
+
+	beginObject()
+	b.WriteString("<< /Type /Pages /Kids [")
+	for i := range pages {
+		fmt.Fprintf(&b, " %d 0 R", firstPageObject+2*i)
+	}
+	fmt.Fprintf(&b, " ] /Count %d >>\n", len(pages))
+	endObject()
+
+	var fontResources bytes.Buffer
+	for i, f := range fonts {
+		beginObject()
+		fmt.Fprintf(&b, "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\n", f.baseFont)
+		endObject()
+		fmt.Fprintf(&fontResources, " /%s %d 0 R", f.resource, firstFontObject+i)
+	}
+
+	for i, p := range pages {
+		beginObject()
+		fmt.Fprintf(&b, "<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /Font <<%s >> >> /Contents %d 0 R >>\n",
+			pagesObject, formatNumber(width), formatNumber(height), fontResources.String(), firstPageObject+2*i+1)
+		endObject()
+
+		var content bytes.Buffer
+		zw := zlib.NewWriter(&content)
+		if _, err := zw.Write(p.content.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		beginObject()
+		fmt.Fprintf(&b, "<< /Length %d /Filter /FlateDecode >>\nstream\n", content.Len())
+		b.Write(content.Bytes())
+		b.WriteString("\nendstream\n")
+		endObject()
+	}
+
+	beginObject()
+	b.WriteString("<< /Producer (EasyCLA) >>\n")
+	endObject()
+	infoObject := len(offsets)
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, catalogObject, infoObject, xref)
+	return b.Bytes(), nil
+}
+
+// formatNumber formats the number with at most two decimals, as expected in the PDF operands
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(math.Round(n*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,146 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package pdf
+
+import "unicode/utf8"
+
+// font is one of the standard Type1 fonts of the PDF readers, the fonts aren't embedded and the text is encoded with
+// the WinAnsi encoding
+type font struct {
+	resource string
+	baseFont string
+	widths   *[224]uint16
+}
+
+// the Times fonts are the serif fonts the HTML documents are rendered with by default. The italic fonts are measured
+// with the widths of the upright fonts, which are close enough to lay out the lines.
+var (
+	fontRegular    = &font{resource: "F1", baseFont: "Times-Roman", widths: &timesRomanWidths}
+	fontBold       = &font{resource: "F2", baseFont: "Times-Bold", widths: &timesBoldWidths}
+	fontItalic     = &font{resource: "F3", baseFont: "Times-Italic", widths: &timesRomanWidths}
+	fontBoldItalic = &font{resource: "F4", baseFont: "Times-BoldItalic", widths: &timesBoldWidths}
+
+	fonts = []*font{fontRegular, fontBold, fontItalic, fontBoldItalic}
+)
+
+// font metrics in thousandths of the font size
+const (
+	fontAscent  = 683
+	fontDescent = 217
+)
+
+// selectFont returns the font of the style
+func selectFont(bold, italic bool) *font {
+	switch {
+	case bold && italic:
+		return fontBoldItalic
+	case bold:
+		return fontBold
+	case italic:
+		return fontItalic
+	}
+	return fontRegular
+}
+
+// width returns the width of the WinAnsi encoded text at the font size
+func (f *font) width(text []byte, size float64) float64 {
+	total := 0
+	for _, c := range text {
+		if c >= 32 {
+			total += int(f.widths[c-32])
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// encodeWinAnsi encodes the text with the WinAnsi encoding of the fonts, the characters missing from the encoding are
+// replaced by question marks
+func encodeWinAnsi(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r == utf8.RuneError:
+			encoded = append(encoded, '?')
+		case r == '\u00AD':
+			// soft hyphens are only shown when the words are hyphenated
+		case r >= 32 && r < 127, r >= 0xA0 && r <= 0xFF:
+			encoded = append(encoded, byte(r))
+		case r == '\u2010' || r == '\u2011':
+			encoded = append(encoded, '-')
+		default:
+			if c, ok := winAnsiCodes[r]; ok {
+				encoded = append(encoded, c)
+			} else {
+				encoded = append(encoded, '?')
+			}
+		}
+	}
+	return encoded
+}
+
+// winAnsiCodes are the codes of the characters of the WinAnsi encoding outside of the ASCII and Latin-1 ranges
+var winAnsiCodes = map[rune]byte{
+	0x20AC: 0x80,
+	0x201A: 0x82,
+	0x0192: 0x83,
+	0x201E: 0x84,
+	0x2026: 0x85,
+	0x2020: 0x86,
+	0x2021: 0x87,
+	0x02C6: 0x88,
+	0x2030: 0x89,
+	0x0160: 0x8A,
+	0x2039: 0x8B,
+	0x0152: 0x8C,
+	0x017D: 0x8E,
+	0x2018: 0x91,
+	0x2019: 0x92,
+	0x201C: 0x93,
+	0x201D: 0x94,
+	0x2022: 0x95,
+	0x2013: 0x96,
+	0x2014: 0x97,
+	0x02DC: 0x98,
+	0x2122: 0x99,
+	0x0161: 0x9A,
+	0x203A: 0x9B,
+	0x0153: 0x9C,
+	0x017E: 0x9E,
+	0x0178: 0x9F,
+}
+
+// the widths of the WinAnsi characters 32 to 255 of the Times fonts, zero for the undefined codes
+var timesRomanWidths = [224]uint16{
+	250, 333, 408, 500, 500, 833, 778, 180, 333, 333, 500, 564, 250, 333, 250, 278,
+	500, 500, 500, 500, 500, 500, 500, 500, 500, 500, 278, 278, 564, 564, 564, 444,
+	921, 722, 667, 667, 722, 611, 556, 722, 722, 333, 389, 722, 611, 889, 722, 722,
+	556, 722, 667, 556, 611, 722, 722, 944, 722, 722, 611, 333, 278, 333, 469, 500,
+	333, 444, 500, 444, 500, 444, 333, 500, 500, 278, 278, 500, 278, 778, 500, 500,
+	500, 500, 333, 389, 278, 500, 500, 722, 500, 500, 444, 480, 200, 480, 541, 0,
+	500, 0, 333, 500, 444, 1000, 500, 500, 333, 1000, 556, 333, 889, 0, 611, 0,
+	0, 333, 333, 444, 444, 350, 500, 1000, 333, 980, 389, 333, 722, 0, 444, 722,
+	250, 333, 500, 500, 500, 500, 200, 500, 333, 760, 276, 500, 564, 333, 760, 333,
+	400, 564, 300, 300, 333, 500, 453, 250, 333, 300, 310, 500, 750, 750, 750, 444,
+	722, 722, 722, 722, 722, 722, 889, 667, 611, 611, 611, 611, 333, 333, 333, 333,
+	722, 722, 722, 722, 722, 722, 722, 564, 722, 722, 722, 722, 722, 722, 556, 500,
+	444, 444, 444, 444, 444, 444, 667, 444, 444, 444, 444, 444, 278, 278, 278, 278,
+	500, 500, 500, 500, 500, 500, 500, 564, 500, 500, 500, 500, 500, 500, 500, 500,
+}
+
+var timesBoldWidths = [224]uint16{
+	250, 333, 555, 500, 500, 1000, 833, 278, 333, 333, 500, 570, 250, 333, 250, 278,
+	500, 500, 500, 500, 500, 500, 500, 500, 500, 500, 333, 333, 570, 570, 570, 500,
+	930, 722, 667, 722, 722, 667, 611, 778, 778, 389, 500, 778, 667, 944, 722, 778,
+	611, 778, 722, 556, 667, 722, 722, 1000, 722, 722, 667, 333, 278, 333, 581, 500,
+	333, 500, 556, 444, 556, 444, 333, 500, 556, 278, 333, 556, 278, 833, 556, 500,
+	556, 556, 444, 389, 333, 556, 500, 722, 500, 500, 444, 394, 220, 394, 520, 0,
+	500, 0, 333, 500, 500, 1000, 500, 500, 333, 1000, 556, 333, 1000, 0, 667, 0,
+	0, 333, 333, 500, 500, 350, 500, 1000, 333, 1000, 389, 333, 722, 0, 444, 722,
+	250, 333, 500, 500, 500, 500, 220, 500, 333, 747, 300, 500, 570, 333, 747, 333,
+	400, 570, 300, 300, 333, 556, 540, 250, 333, 300, 330, 500, 750, 750, 750, 500,
+	722, 722, 722, 722, 722, 722, 1000, 722, 667, 667, 667, 667, 389, 389, 389, 389,
+	722, 722, 778, 778, 778, 778, 778, 570, 778, 722, 722, 722, 722, 722, 611, 556,
+	500, 500, 500, 500, 500, 500, 722, 444, 444, 444, 444, 444, 278, 278, 278, 278,
+	500, 556, 500, 500, 500, 500, 500, 570, 500, 556, 556, 556, 556, 500, 556, 500,
+}
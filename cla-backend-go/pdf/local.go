@@ -0,0 +1,577 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package pdf
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// The page layout of the local renderer follows the layout of the documents rendered by DocRaptor: US Letter pages,
+// serif fonts and the default HTML styles - 12pt text, the heading sizes and the block margins. The DocuSign tabs are
+// anchored to the text of the documents, the text is kept as text and the words of a line of the same font are shown
+// together so the anchor strings are found on the same pages.
+const (
+	pageWidth     = 612.0
+	pageHeight    = 792.0
+	pageMargin    = 54.0
+	baseFontSize  = 12.0
+	lineHeight    = 1.2
+	listIndent    = 30.0
+	markerSpacing = 6.0
+	ruleWidth     = 0.5
+)
+
+// localRenderer lays out the HTML documents in process
+type localRenderer struct{}
+
+// NewLocalRenderer returns the renderer laying out the HTML documents in process, without the DocRaptor API. The
+// paragraphs, headings, lists, line breaks, bold and italic text, text alignment and page breaks of the templates are
+// supported, the other styles and the images are ignored.
+func NewLocalRenderer() Renderer {
+	return localRenderer{}
+}
+
+// CreatePDF renders the HTML document
+func (r localRenderer) CreatePDF(document string) (io.ReadCloser, error) {
+	root, err := html.Parse(strings.NewReader(document))
+	if err != nil {
+		return nil, err
+	}
+	l := newLayout()
+	b := &blockBuilder{layout: l}
+	b.walk(root, inlineStyle{size: baseFontSize}, blockStyle{})
+	b.flush()
+	l.finish()
+
+	content, err := writeDocument(l.pages, pageWidth, pageHeight)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewReader(content)), nil
+}
+
+// inlineStyle is the style of the text
+type inlineStyle struct {
+	bold   bool
+	italic bool
+	size   float64
+}
+
+// blockStyle is the style of the blocks inherited by the nested blocks
+type blockStyle struct {
+	align  string
+	indent float64
+}
+
+// piece is a part of a word of a single font, or a line break
+type piece struct {
+	text        []byte
+	font        *font
+	size        float64
+	spaceBefore bool
+	lineBreak   bool
+}
+
+func (p piece) width() float64 {
+	return p.font.width(p.text, p.size)
+}
+
+// block is a paragraph of text laid out as lines
+type block struct {
+	pieces []piece
+	style  blockStyle
+	size   float64
+	marker string
+}
+
+// element defaults of the HTML elements, the margins are in ems of the element font size
+type elementDefaults struct {
+	block  bool
+	margin float64
+	sizeEm float64
+	bold   bool
+	italic bool
+	indent float64
+	align  string
+}
+
+var elements = map[atom.Atom]elementDefaults{
+	atom.P:          {block: true, margin: 1},
+	atom.Div:        {block: true},
+	atom.Section:    {block: true},
+	atom.Article:    {block: true},
+	atom.Header:     {block: true},
+	atom.Footer:     {block: true},
+	atom.Main:       {block: true},
+	atom.Center:     {block: true, align: "center"},
+	atom.Address:    {block: true, italic: true},
+	atom.Blockquote: {block: true, margin: 1, indent: listIndent},
+	atom.Pre:        {block: true, margin: 1},
+	atom.H1:         {block: true, margin: 0.67, sizeEm: 2, bold: true},
+	atom.H2:         {block: true, margin: 0.83, sizeEm: 1.5, bold: true},
+	atom.H3:         {block: true, margin: 1, sizeEm: 1.17, bold: true},
+	atom.H4:         {block: true, margin: 1.33, sizeEm: 1, bold: true},
+	atom.H5:         {block: true, margin: 1.67, sizeEm: 0.83, bold: true},
+	atom.H6:         {block: true, margin: 2.33, sizeEm: 0.67, bold: true},
+	atom.Ul:         {block: true, margin: 1, indent: listIndent},
+	atom.Ol:         {block: true, margin: 1, indent: listIndent},
+	atom.Li:         {block: true},
+	atom.Table:      {block: true},
+	atom.Tr:         {block: true},
+	atom.Th:         {bold: true},
+	atom.B:          {bold: true},
+	atom.Strong:     {bold: true},
+	atom.I:          {italic: true},
+	atom.Em:         {italic: true},
+	atom.Cite:       {italic: true},
+	atom.Var:        {italic: true},
+}
+
+// ignoredElements aren't rendered
+var ignoredElements = map[atom.Atom]bool{
+	atom.Head:     true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Title:    true,
+	atom.Img:      true,
+	atom.Noscript: true,
+	atom.Template: true,
+}
+
+// blockBuilder collects the pieces of the current block while walking the document
+type blockBuilder struct {
+	layout       *layout
+	pieces       []piece
+	pendingSpace bool
+	style        blockStyle
+	size         float64
+	marker       string
+	// nextMarker is the marker of the next list item
+	nextMarker string
+}
+
+// walk adds the node and its descendants to the layout
+func (b *blockBuilder) walk(n *html.Node, inline inlineStyle, style blockStyle) {
+	switch n.Type {
+	case html.TextNode:
+		b.text(n.Data, inline)
+		return
+	case html.DocumentNode:
+		b.walkChildren(n, inline, style)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+	if ignoredElements[n.DataAtom] {
+		return
+	}
+
+	defaults := elements[n.DataAtom]
+	if defaults.sizeEm > 0 {
+		inline.size = baseFontSize * defaults.sizeEm
+	}
+	inline.bold = inline.bold || defaults.bold
+	inline.italic = inline.italic || defaults.italic
+	if defaults.align != "" {
+		style.align = defaults.align
+	}
+	breakBefore, breakAfter := false, false
+	if align := strings.ToLower(getAttribute(n, "align")); align != "" {
+		style.align = align
+	}
+	for property, value := range parseStyle(getAttribute(n, "style")) {
+		switch property {
+		case "text-align":
+			style.align = value
+		case "font-weight":
+			weight, err := strconv.Atoi(value)
+			inline.bold = value == "bold" || value == "bolder" || (err == nil && weight >= 600)
+		case "font-style":
+			inline.italic = value == "italic" || value == "oblique"
+		case "page-break-before", "break-before":
+			breakBefore = value == "always" || value == "page"
+		case "page-break-after", "break-after":
+			breakAfter = value == "always" || value == "page"
+		}
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		b.pieces = append(b.pieces, piece{lineBreak: true, font: selectFont(inline.bold, inline.italic), size: inline.size})
+		b.pendingSpace = false
+		return
+	case atom.Hr:
+		b.flush()
+		b.layout.rule(style.indent, inline.size/2)
+		return
+	case atom.Td, atom.Th:
+		b.pendingSpace = len(b.pieces) > 0
+		b.walkChildren(n, inline, style)
+		b.pendingSpace = len(b.pieces) > 0
+		return
+	}
+
+	if !defaults.block {
+		if breakBefore {
+			b.flush()
+			b.layout.pageBreak()
+		}
+		b.walkChildren(n, inline, style)
+		if breakAfter {
+			b.flush()
+			b.layout.pageBreak()
+		}
+		return
+	}
+
+	b.flush()
+	if breakBefore {
+		b.layout.pageBreak()
+	}
+	margin := defaults.margin * inline.size
+	b.layout.margin(margin)
+	style.indent += defaults.indent
+	b.style, b.size = style, inline.size
+	b.marker, b.nextMarker = b.nextMarker, ""
+
+	switch n.DataAtom {
+	case atom.Ul, atom.Ol:
+		number := 1
+		if start, err := strconv.Atoi(getAttribute(n, "start")); err == nil {
+			number = start
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.DataAtom == atom.Li {
+				if n.DataAtom == atom.Ol {
+					b.nextMarker = strconv.Itoa(number) + "."
+					number++
+				} else {
+					b.nextMarker = "•"
+				}
+			}
+			b.walk(c, inline, style)
+		}
+	default:
+		b.walkChildren(n, inline, style)
+	}
+
+	b.flush()
+	b.layout.margin(margin)
+	if breakAfter {
+		b.layout.pageBreak()
+	}
+	// The text following the block belongs to the parent block
+	b.style, b.size = style, inline.size
+	b.style.indent -= defaults.indent
+}
+
+func (b *blockBuilder) walkChildren(n *html.Node, inline inlineStyle, style blockStyle) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.walk(c, inline, style)
+	}
+}
+
+// text adds the words of the text, the white space is collapsed as in the HTML documents
+func (b *blockBuilder) text(text string, inline inlineStyle) {
+	f := selectFont(inline.bold, inline.italic)
+	if b.size == 0 {
+		b.size = inline.size
+	}
+	start := -1
+	for i, r := range text + " " {
+		if isCollapsibleSpace(r) {
+			if start >= 0 {
+				b.pieces = append(b.pieces, piece{text: encodeWinAnsi(text[start:i]), font: f, size: inline.size, spaceBefore: b.pendingSpace})
+				start = -1
+			}
+			b.pendingSpace = len(b.pieces) > 0 && !b.pieces[len(b.pieces)-1].lineBreak
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+}
+
+// flush lays out the current block
+func (b *blockBuilder) flush() {
+	if len(b.pieces) > 0 || b.marker != "" {
+		size := b.size
+		if size == 0 {
+			size = baseFontSize
+		}
+		b.layout.block(&block{pieces: b.pieces, style: b.style, size: size, marker: b.marker})
+	}
+	b.pieces, b.pendingSpace, b.marker = nil, false, ""
+}
+
+func isCollapsibleSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f'
+}
+
+func getAttribute(n *html.Node, name string) string {
+	for _, attribute := range n.Attr {
+		if attribute.Key == name {
+			return attribute.Val
+		}
+	}
+	return ""
+}
+
+// parseStyle returns the properties of the style attribute
+func parseStyle(style string) map[string]string {
+	properties := map[string]string{}
+	for _, declaration := range strings.Split(style, ";") {
+		parts := strings.SplitN(declaration, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		properties[strings.ToLower(strings.TrimSpace(parts[0]))] = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(parts[1]), "!important")))
+	}
+	return properties
+}
+
+// layout places the blocks on the pages
+type layout struct {
+	pages         []*page
+	current       *page
+	y             float64
+	pendingMargin float64
+}
+
+func newLayout() *layout {
+	l := &layout{}
+	l.newPage()
+	return l
+}
+
+func (l *layout) newPage() {
+	l.current = &page{}
+	l.pages = append(l.pages, l.current)
+	l.y = pageHeight - pageMargin
+	l.pendingMargin = 0
+}
+
+// atPageTop returns true if nothing was placed on the current page
+func (l *layout) atPageTop() bool {
+	return l.y == pageHeight-pageMargin
+}
+
+// margin adds the vertical margin, the adjacent margins collapse and the margins at the top of the pages are dropped
+func (l *layout) margin(margin float64) {
+	if margin > l.pendingMargin {
+		l.pendingMargin = margin
+	}
+}
+
+func (l *layout) pageBreak() {
+	if !l.atPageTop() {
+		l.newPage()
+	}
+}
+
+// finish drops the last page if it is empty, a document always has at least one page
+func (l *layout) finish() {
+	if len(l.pages) > 1 && l.atPageTop() && l.current.content.Len() == 0 {
+		l.pages = l.pages[:len(l.pages)-1]
+	}
+}
+
+// advance moves down by the height, on the next page if the height doesn't fit on the current page
+func (l *layout) advance(height float64) {
+	margin := l.pendingMargin
+	if l.atPageTop() {
+		margin = 0
+	}
+	if l.y-margin-height < pageMargin && !l.atPageTop() {
+		l.newPage()
+		margin = 0
+	}
+	l.y -= margin + height
+	l.pendingMargin = 0
+}
+
+// rule draws a horizontal rule
+func (l *layout) rule(indent, margin float64) {
+	l.margin(margin)
+	l.advance(ruleWidth)
+	l.current.line(pageMargin+indent, pageWidth-pageMargin, l.y, ruleWidth)
+	l.margin(margin)
+}
+
+// line is a line of words of a block
+type line struct {
+	words      [][]piece
+	width      float64
+	spaces     int
+	size       float64
+	lineBreak  bool
+	spaceWidth []float64
+}
+
+// block breaks the pieces of the block into lines and places the lines
+func (l *layout) block(blk *block) {
+	available := pageWidth - 2*pageMargin - blk.style.indent
+	lines := breakLines(blk, available)
+	for i, ln := range lines {
+		size := ln.size
+		if size == 0 {
+			size = blk.size
+		}
+		l.advance(size * lineHeight)
+		baseline := l.y + size*(lineHeight-1)/2 + size*fontDescent/1000
+
+		x := pageMargin + blk.style.indent
+		wordSpacing := 0.0
+		switch blk.style.align {
+		case "center":
+			x += (available - ln.width) / 2
+		case "right":
+			x += available - ln.width
+		case "justify":
+			if i < len(lines)-1 && !ln.lineBreak && ln.spaces > 0 {
+				wordSpacing = (available - ln.width) / float64(ln.spaces)
+			}
+		}
+		if i == 0 && blk.marker != "" {
+			marker := encodeWinAnsi(blk.marker)
+			l.current.text(fontRegular, blk.size, x-markerSpacing-fontRegular.width(marker, blk.size), baseline, 0, marker)
+		}
+		l.placeLine(ln, x, baseline, wordSpacing)
+	}
+}
+
+// placeLine shows the words of the line, the consecutive words of the same font are shown together
+func (l *layout) placeLine(ln *line, x, baseline, wordSpacing float64) {
+	var segment []byte
+	var segmentFont *font
+	var segmentSize, segmentX float64
+	showSegment := func() {
+		if len(segment) > 0 {
+			l.current.text(segmentFont, segmentSize, segmentX, baseline, wordSpacing, segment)
+		}
+		segment = nil
+	}
+	for i, word := range ln.words {
+		for j, p := range word {
+			sameFont := segment != nil && p.font == segmentFont && p.size == segmentSize
+			if i > 0 && j == 0 {
+				if sameFont {
+					segment = append(segment, ' ')
+				} else {
+					showSegment()
+				}
+				x += ln.spaceWidth[i] + wordSpacing
+			}
+			if segment == nil || p.font != segmentFont || p.size != segmentSize {
+				showSegment()
+				segment, segmentFont, segmentSize, segmentX = []byte{}, p.font, p.size, x
+			}
+			segment = append(segment, p.text...)
+			x += p.width()
+		}
+	}
+	showSegment()
+}
+
+// breakLines breaks the pieces into lines of the available width, the words wider than a line are broken
+func breakLines(blk *block, available float64) []*line {
+	var lines []*line
+	current := &line{}
+	addWord := func(word []piece, spaceWidth float64) {
+		width := 0.0
+		for _, p := range word {
+			width += p.width()
+			if p.size > current.size {
+				current.size = p.size
+			}
+		}
+		if len(current.words) > 0 {
+			current.width += spaceWidth
+			current.spaces++
+		}
+		current.words = append(current.words, word)
+		current.spaceWidth = append(current.spaceWidth, spaceWidth)
+		current.width += width
+	}
+
+	for _, word := range splitWords(blk.pieces) {
+		if word[0].lineBreak {
+			current.lineBreak = true
+			if current.size == 0 {
+				current.size = word[0].size
+			}
+			lines = append(lines, current)
+			current = &line{}
+			continue
+		}
+		spaceWidth := word[0].font.width([]byte{' '}, word[0].size)
+		width := 0.0
+		for _, p := range word {
+			width += p.width()
+		}
+		if len(current.words) > 0 && current.width+spaceWidth+width > available {
+			lines = append(lines, current)
+			current = &line{}
+		}
+		if width <= available {
+			addWord(word, spaceWidth)
+			continue
+		}
+		// The words wider than a line are broken at the last character fitting the line
+		for _, p := range word {
+			text := p.text
+			for len(text) > 0 {
+				n := len(text)
+				for n > 1 && current.width+p.font.width(text[:n], p.size) > available {
+					n--
+				}
+				if len(current.words) > 0 && current.width+p.font.width(text[:n], p.size) > available {
+					lines = append(lines, current)
+					current = &line{}
+					continue
+				}
+				part := piece{text: text[:n], font: p.font, size: p.size}
+				if len(current.words) > 0 {
+					// Continue the word on the same line without a space
+					last := len(current.words) - 1
+					current.words[last] = append(current.words[last], part)
+					current.width += part.width()
+				} else {
+					addWord([]piece{part}, 0)
+				}
+				text = text[n:]
+				if len(text) > 0 {
+					lines = append(lines, current)
+					current = &line{}
+				}
+			}
+		}
+	}
+	if len(current.words) > 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// splitWords groups the pieces into words, a word starts with a piece following a space or a line break
+func splitWords(pieces []piece) [][]piece {
+	var words [][]piece
+	for i, p := range pieces {
+		if i == 0 || p.spaceBefore || p.lineBreak || pieces[i-1].lineBreak {
+			words = append(words, []piece{p})
+			continue
+		}
+		words[len(words)-1] = append(words[len(words)-1], p)
+	}
+	return words
+}
@@ -0,0 +1,37 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package pdf
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	"github.com/communitybridge/easycla/cla-backend-go/docraptor"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// PDF renderer drivers
+const (
+	RendererDocraptor = "docraptor"
+	RendererLocal     = "local"
+)
+
+// Renderer renders the HTML documents of the templates as PDF documents
+type Renderer interface {
+	// CreatePDF accepts an HTML document and returns a PDF
+	CreatePDF(html string) (io.ReadCloser, error)
+}
+
+// NewRenderer returns the renderer of the configured driver - the DocRaptor API by default
+func NewRenderer(rendererConfig config.PDFRenderer, docraptorConfig config.Docraptor) (Renderer, error) {
+	switch rendererConfig.Driver {
+	case "", RendererDocraptor:
+		return docraptor.NewDocraptorClient(docraptorConfig.APIKey, docraptorConfig.TestMode)
+	case RendererLocal:
+		log.Info("Using the local PDF renderer")
+		return NewLocalRenderer(), nil
+	}
+	return nil, fmt.Errorf("unsupported PDF renderer: %s", rendererConfig.Driver)
+}
@@ -0,0 +1,126 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package pending_request_expirations
+
+import (
+	"fmt"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// claManagerAddress returns the email address of the CLA manager
+func claManagerAddress(claManager models.User) string {
+	if claManager.LfEmail != "" {
+		return claManager.LfEmail
+	}
+	if len(claManager.Emails) > 0 {
+		return claManager.Emails[0]
+	}
+	return ""
+}
+
+// sendApprovalListRequestReminderEmail reminds the CLA manager of the CCLA approval list request waiting for review
+func (s service) sendApprovalListRequestReminderEmail(companyModel *models.Company, projectModel *models.Project, claManager models.User, request *pendingRequest, reminder int) {
+	subject := fmt.Sprintf("EasyCLA: %s - Request to Authorize %s for %s", s.reminderLabel(reminder), request.requesterName, projectModel.ProjectName)
+	body := fmt.Sprintf(`
+<p>Hello %s,</p>
+<p>This is a notification email from EasyCLA regarding the project %s.</p>
+<p>%s (%s) requested %d days ago to be added to the Allow List as an authorized contributor from %s to the project
+%s and the request is still waiting for review. You are receiving this message as a CLA Manager from %s for %s.</p>
+<p>The request expires on %s if it is not reviewed. To review the request, please
+<a href="https://%s#/company/%s" target="_blank">log into the EasyCLA Corporate Console</a>.</p>
+%s
+%s`,
+		claManager.Username, projectModel.ProjectName,
+		request.requesterName, request.requesterEmail, request.daysPending, companyModel.CompanyName,
+		projectModel.ProjectName, companyModel.CompanyName, projectModel.ProjectName,
+		s.expiryDate(request), s.corporateConsoleURL, companyModel.CompanyID,
+		utils.GetEmailHelpContent(projectModel.Version == utils.V2), utils.GetEmailSignOffContent())
+
+	sendEmail("sendApprovalListRequestReminderEmail", subject, body, claManager.Username, claManagerAddress(claManager))
+}
+
+// sendApprovalListRequestExpiredEmail notifies the contributor that the CCLA approval list request lapsed
+func (s service) sendApprovalListRequestExpiredEmail(companyModel *models.Company, projectModel *models.Project, request *pendingRequest) {
+	subject := fmt.Sprintf("EasyCLA: Request to Authorize %s for %s Expired", request.requesterName, projectModel.ProjectName)
+	body := fmt.Sprintf(`
+<p>Hello %s,</p>
+<p>This is a notification email from EasyCLA regarding the project %s.</p>
+<p>Your request to be added to the Allow List as an authorized contributor from %s to the project %s was not reviewed
+by a CLA Manager within %d days and has expired.</p>
+<p>If you still want to contribute on behalf of %s, please submit a new request or reach out to your CLA Managers
+directly.</p>
+%s
+%s`,
+		request.requesterName, projectModel.ProjectName,
+		companyModel.CompanyName, projectModel.ProjectName, s.expiryDays,
+		companyModel.CompanyName,
+		utils.GetEmailHelpContent(projectModel.Version == utils.V2), utils.GetEmailSignOffContent())
+
+	sendEmail("sendApprovalListRequestExpiredEmail", subject, body, request.requesterName, request.requesterEmail)
+}
+
+// sendCLAManagerRequestReminderEmail reminds the CLA manager of the CLA manager request waiting for review
+func (s service) sendCLAManagerRequestReminderEmail(companyModel *models.Company, projectModel *models.Project, claManager models.User, request *pendingRequest, reminder int) {
+	subject := fmt.Sprintf("EasyCLA: %s - CLA Manager Request for %s", s.reminderLabel(reminder), projectModel.ProjectName)
+	body := fmt.Sprintf(`
+<p>Hello %s,</p>
+<p>This is a notification email from EasyCLA regarding the project %s.</p>
+<p>%s (%s) requested %d days ago to be added as a CLA Manager for %s on the project %s and the request is still
+waiting for review. You are receiving this message as a CLA Manager from %s for %s.</p>
+<p>The request expires on %s if it is not reviewed. To review the request, please
+<a href="https://%s#/company/%s" target="_blank">log into the EasyCLA Corporate Console</a>.</p>
+%s
+%s`,
+		claManager.Username, projectModel.ProjectName,
+		request.requesterName, request.requesterEmail, request.daysPending, companyModel.CompanyName,
+		projectModel.ProjectName, companyModel.CompanyName, projectModel.ProjectName,
+		s.expiryDate(request), s.corporateConsoleURL, companyModel.CompanyID,
+		utils.GetEmailHelpContent(projectModel.Version == utils.V2), utils.GetEmailSignOffContent())
+
+	sendEmail("sendCLAManagerRequestReminderEmail", subject, body, claManager.Username, claManagerAddress(claManager))
+}
+
+// sendCLAManagerRequestExpiredEmail notifies the requester that the CLA manager request lapsed
+func (s service) sendCLAManagerRequestExpiredEmail(companyModel *models.Company, projectModel *models.Project, request *pendingRequest) {
+	subject := fmt.Sprintf("EasyCLA: CLA Manager Request for %s Expired", projectModel.ProjectName)
+	body := fmt.Sprintf(`
+<p>Hello %s,</p>
+<p>This is a notification email from EasyCLA regarding the project %s.</p>
+<p>Your request to be added as a CLA Manager for %s on the project %s was not reviewed by the existing CLA Managers
+within %d days and has expired.</p>
+<p>If you still need access, please submit a new request or reach out to the CLA Managers of %s directly.</p>
+%s
+%s`,
+		request.requesterName, projectModel.ProjectName,
+		companyModel.CompanyName, projectModel.ProjectName, s.expiryDays,
+		companyModel.CompanyName,
+		utils.GetEmailHelpContent(projectModel.Version == utils.V2), utils.GetEmailSignOffContent())
+
+	sendEmail("sendCLAManagerRequestExpiredEmail", subject, body, request.requesterName, request.requesterEmail)
+}
+
+// sendEmail sends the email to the recipient, problems are only logged
+func sendEmail(functionName, subject, body, recipientName, recipientAddress string) {
+	f := logrus.Fields{
+		"function":         functionName,
+		"recipientName":    recipientName,
+		"recipientAddress": recipientAddress,
+	}
+	if recipientAddress == "" {
+		log.WithFields(f).Warnf("unable to send email with subject: %s - recipient has no email address", subject)
+		return
+	}
+
+	recipients := []string{recipientAddress}
+	err := utils.SendEmail(subject, body, recipients)
+	if err != nil {
+		log.WithFields(f).Warnf("problem sending email with subject: %s to recipients: %+v, error: %+v", subject, recipients, err)
+	} else {
+		log.WithFields(f).Debugf("sent email with subject: %s to recipients: %+v", subject, recipients)
+	}
+}
@@ -0,0 +1,424 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package pending_request_expirations
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list"
+	"github.com/communitybridge/easycla/cla-backend-go/cla_manager"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultExpiryDays is the number of days a request stays pending before it expires
+const DefaultExpiryDays = 30
+
+// DefaultReminderDays are the number of days a request is pending when the CLA managers are reminded of it
+var DefaultReminderDays = []int{3, 7, 14}
+
+// systemUser is the actor recorded on the events created by the scheduled job
+var systemUser = &models.User{
+	UserID:     "easycla system",
+	LfUsername: "easycla system",
+	Username:   "easycla system",
+}
+
+// Summary holds the results of processing the pending requests
+type Summary struct {
+	ApprovalListRequestsReminded int
+	ApprovalListRequestsExpired  int
+	CLAManagerRequestsReminded   int
+	CLAManagerRequestsExpired    int
+}
+
+// Service defines the functions of the pending request expiration service
+type Service interface {
+	ProcessPendingRequests(now time.Time) (*Summary, error)
+}
+
+type service struct {
+	approvalListRepo    approval_list.IRepository
+	claManagerRepo      cla_manager.IRepository
+	signaturesRepo      signatures.SignatureRepository
+	companyRepo         company.IRepository
+	projectRepo         project.ProjectRepository
+	eventsService       events.Service
+	corporateConsoleURL string
+	reminderDays        []int
+	expiryDays          int
+}
+
+// NewService creates a new instance of the pending request expiration service - the CLA managers are reminded of a
+// request once it is pending for each of the reminder days and the request expires once it is pending for the expiry
+// days. Reminder days on or after the expiry are ignored.
+func NewService(approvalListRepo approval_list.IRepository, claManagerRepo cla_manager.IRepository, signaturesRepo signatures.SignatureRepository,
+	companyRepo company.IRepository, projectRepo project.ProjectRepository, eventsService events.Service, corporateConsoleURL string, reminderDays []int, expiryDays int) Service {
+	if expiryDays <= 0 {
+		expiryDays = DefaultExpiryDays
+	}
+	if len(reminderDays) == 0 {
+		reminderDays = DefaultReminderDays
+	}
+	var validReminderDays []int
+	for _, days := range reminderDays {
+		if days > 0 && days < expiryDays {
+			validReminderDays = append(validReminderDays, days)
+		}
+	}
+	sort.Ints(validReminderDays)
+
+	return service{
+		approvalListRepo:    approvalListRepo,
+		claManagerRepo:      claManagerRepo,
+		signaturesRepo:      signaturesRepo,
+		companyRepo:         companyRepo,
+		projectRepo:         projectRepo,
+		eventsService:       eventsService,
+		corporateConsoleURL: corporateConsoleURL,
+		reminderDays:        validReminderDays,
+		expiryDays:          expiryDays,
+	}
+}
+
+// ParseReminderDays parses a comma separated list of days, such as 3,7,14
+func ParseReminderDays(value string) ([]int, error) {
+	var reminderDays []int
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		days, err := strconv.Atoi(item)
+		if err != nil || days <= 0 {
+			return nil, fmt.Errorf("invalid reminder days value: %s", item)
+		}
+		reminderDays = append(reminderDays, days)
+	}
+	return reminderDays, nil
+}
+
+// pendingRequest holds the details common to the CCLA approval list and CLA manager requests
+type pendingRequest struct {
+	requestID      string
+	companyID      string
+	projectID      string
+	requesterName  string
+	requesterEmail string
+	pendingSince   time.Time
+	daysPending    int
+	reminderCount  int
+}
+
+// ProcessPendingRequests expires the CCLA approval list and CLA manager requests which are pending for too long and
+// reminds the CLA managers of the remaining ones
+func (s service) ProcessPendingRequests(now time.Time) (*Summary, error) {
+	f := logrus.Fields{
+		"functionName": "ProcessPendingRequests",
+		"now":          utils.TimeToString(now),
+		"reminderDays": s.reminderDays,
+		"expiryDays":   s.expiryDays,
+	}
+
+	approvalListRequests, err := s.approvalListRepo.ListPendingCclaWhitelistRequests()
+	if err != nil {
+		log.WithFields(f).Warnf("unable to load the pending CCLA approval list requests, error: %+v", err)
+		return nil, err
+	}
+	claManagerRequests, err := s.claManagerRepo.GetPendingRequests()
+	if err != nil {
+		log.WithFields(f).Warnf("unable to load the pending CLA manager requests, error: %+v", err)
+		return nil, err
+	}
+
+	summary := &Summary{}
+	for _, request := range approvalListRequests {
+		var requesterEmail string
+		if len(request.UserEmails) > 0 {
+			requesterEmail = request.UserEmails[0]
+		}
+		// Approval list requests are never reopened - a new request is created instead
+		pending, ok := s.newPendingRequest(request.RequestID, request.CompanyID, request.ProjectID, request.UserName,
+			requesterEmail, request.DateCreated, request.ReminderCount, now)
+		if !ok {
+			continue
+		}
+		expired, reminded, processErr := s.processApprovalListRequest(pending)
+		if processErr != nil {
+			// Keep going - the remaining requests are independent and the next run retries this one
+			log.WithFields(f).Warnf("unable to process the CCLA approval list request ID: %s, error: %+v", request.RequestID, processErr)
+			continue
+		}
+		if expired {
+			summary.ApprovalListRequestsExpired++
+		}
+		if reminded {
+			summary.ApprovalListRequestsReminded++
+		}
+	}
+
+	for _, request := range claManagerRequests.Requests {
+		// Denied CLA manager requests may be reopened, which resets the modified date
+		pending, ok := s.newPendingRequest(request.RequestID, request.CompanyID, request.ProjectID, request.UserName,
+			request.UserEmail, request.Updated, request.ReminderCount, now)
+		if !ok {
+			continue
+		}
+		expired, reminded, processErr := s.processCLAManagerRequest(pending)
+		if processErr != nil {
+			log.WithFields(f).Warnf("unable to process the CLA manager request ID: %s, error: %+v", request.RequestID, processErr)
+			continue
+		}
+		if expired {
+			summary.CLAManagerRequestsExpired++
+		}
+		if reminded {
+			summary.CLAManagerRequestsReminded++
+		}
+	}
+
+	log.WithFields(f).Debugf("processed pending requests - approval list requests reminded: %d, expired: %d, CLA manager requests reminded: %d, expired: %d",
+		summary.ApprovalListRequestsReminded, summary.ApprovalListRequestsExpired, summary.CLAManagerRequestsReminded, summary.CLAManagerRequestsExpired)
+	return summary, nil
+}
+
+// newPendingRequest builds the pending request, returns false if the pending date of the request can not be parsed
+func (s service) newPendingRequest(requestID, companyID, projectID, requesterName, requesterEmail, pendingSince string, reminderCount int, now time.Time) (*pendingRequest, bool) {
+	pendingSinceTime, err := utils.ParseDateTime(pendingSince)
+	if err != nil {
+		log.Warnf("unable to parse the date: %s of request ID: %s, error: %+v", pendingSince, requestID, err)
+		return nil, false
+	}
+	return &pendingRequest{
+		requestID:      requestID,
+		companyID:      companyID,
+		projectID:      projectID,
+		requesterName:  requesterName,
+		requesterEmail: requesterEmail,
+		pendingSince:   pendingSinceTime,
+		daysPending:    int(now.Sub(pendingSinceTime).Hours() / 24),
+		reminderCount:  reminderCount,
+	}, true
+}
+
+// isExpired returns true if the request is pending for the expiry days
+func (s service) isExpired(request *pendingRequest) bool {
+	return request.daysPending >= s.expiryDays
+}
+
+// dueReminder returns the number of the reminder due for the request, 0 if the CLA managers were already reminded -
+// when the job did not run for a while only the latest reminder is sent
+func (s service) dueReminder(request *pendingRequest) int {
+	due := 0
+	for _, days := range s.reminderDays {
+		if request.daysPending >= days {
+			due++
+		}
+	}
+	if due > request.reminderCount {
+		return due
+	}
+	return 0
+}
+
+// processApprovalListRequest expires the CCLA approval list request or sends the due reminder, returns whether the
+// request expired and whether a reminder was sent
+func (s service) processApprovalListRequest(request *pendingRequest) (bool, bool, error) {
+	expired := s.isExpired(request)
+	reminder := s.dueReminder(request)
+	if !expired && reminder == 0 {
+		return false, false, nil
+	}
+
+	companyModel, projectModel, err := s.loadCompanyAndProject(request)
+	if err != nil {
+		return false, false, err
+	}
+
+	if expired {
+		if err := s.approvalListRepo.ExpireCclaWhitelistRequest(request.requestID); err != nil {
+			if err == approval_list.ErrCclaWhitelistRequestNotPending {
+				// Reviewed since it was loaded - nothing to do
+				return false, false, nil
+			}
+			return false, false, err
+		}
+		s.eventsService.LogEvent(&events.LogEventArgs{
+			EventType:         events.CCLAApprovalListRequestExpired,
+			ProjectID:         projectModel.ProjectID,
+			ProjectModel:      projectModel,
+			CompanyID:         companyModel.CompanyID,
+			CompanyModel:      companyModel,
+			UserModel:         systemUser,
+			ExternalProjectID: projectModel.ProjectExternalID,
+			EventData: &events.CCLAApprovalListRequestExpiredEventData{
+				RequestID:     request.requestID,
+				RequesterName: request.requesterName,
+				DaysPending:   request.daysPending,
+			},
+		})
+		s.sendApprovalListRequestExpiredEmail(companyModel, projectModel, request)
+		return true, false, nil
+	}
+
+	claManagers, err := s.loadCLAManagers(request)
+	if err != nil {
+		return false, false, err
+	}
+	if len(claManagers) == 0 {
+		log.Warnf("unable to remind the CLA managers of CCLA approval list request ID: %s - no CLA managers found", request.requestID)
+		return false, false, nil
+	}
+	if err := s.approvalListRepo.UpdateCclaWhitelistRequestReminder(request.requestID, reminder); err != nil {
+		if err == approval_list.ErrCclaWhitelistRequestNotPending {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	for _, claManager := range claManagers {
+		s.sendApprovalListRequestReminderEmail(companyModel, projectModel, claManager, request, reminder)
+	}
+	s.eventsService.LogEvent(&events.LogEventArgs{
+		EventType:         events.CCLAApprovalListRequestReminder,
+		ProjectID:         projectModel.ProjectID,
+		ProjectModel:      projectModel,
+		CompanyID:         companyModel.CompanyID,
+		CompanyModel:      companyModel,
+		UserModel:         systemUser,
+		ExternalProjectID: projectModel.ProjectExternalID,
+		EventData: &events.CCLAApprovalListRequestReminderEventData{
+			RequestID:      request.requestID,
+			RequesterName:  request.requesterName,
+			ReminderNumber: reminder,
+			DaysPending:    request.daysPending,
+		},
+	})
+	return false, true, nil
+}
+
+// processCLAManagerRequest expires the CLA manager request or sends the due reminder, returns whether the request
+// expired and whether a reminder was sent
+func (s service) processCLAManagerRequest(request *pendingRequest) (bool, bool, error) {
+	expired := s.isExpired(request)
+	reminder := s.dueReminder(request)
+	if !expired && reminder == 0 {
+		return false, false, nil
+	}
+
+	companyModel, projectModel, err := s.loadCompanyAndProject(request)
+	if err != nil {
+		return false, false, err
+	}
+
+	if expired {
+		if _, err := s.claManagerRepo.ExpireRequest(request.companyID, request.projectID, request.requestID); err != nil {
+			if err == cla_manager.ErrRequestNotPending {
+				return false, false, nil
+			}
+			return false, false, err
+		}
+		s.eventsService.LogEvent(&events.LogEventArgs{
+			EventType:         events.ClaManagerAccessRequestExpired,
+			ProjectID:         projectModel.ProjectID,
+			ProjectModel:      projectModel,
+			CompanyID:         companyModel.CompanyID,
+			CompanyModel:      companyModel,
+			UserModel:         systemUser,
+			ExternalProjectID: projectModel.ProjectExternalID,
+			EventData: &events.ClaManagerAccessRequestExpiredEventData{
+				RequestID:     request.requestID,
+				RequesterName: request.requesterName,
+				DaysPending:   request.daysPending,
+			},
+		})
+		s.sendCLAManagerRequestExpiredEmail(companyModel, projectModel, request)
+		return true, false, nil
+	}
+
+	claManagers, err := s.loadCLAManagers(request)
+	if err != nil {
+		return false, false, err
+	}
+	if len(claManagers) == 0 {
+		log.Warnf("unable to remind the CLA managers of CLA manager request ID: %s - no CLA managers found", request.requestID)
+		return false, false, nil
+	}
+	if err := s.claManagerRepo.UpdateRequestReminder(request.requestID, reminder); err != nil {
+		if err == cla_manager.ErrRequestNotPending {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	for _, claManager := range claManagers {
+		s.sendCLAManagerRequestReminderEmail(companyModel, projectModel, claManager, request, reminder)
+	}
+	s.eventsService.LogEvent(&events.LogEventArgs{
+		EventType:         events.ClaManagerAccessRequestReminder,
+		ProjectID:         projectModel.ProjectID,
+		ProjectModel:      projectModel,
+		CompanyID:         companyModel.CompanyID,
+		CompanyModel:      companyModel,
+		UserModel:         systemUser,
+		ExternalProjectID: projectModel.ProjectExternalID,
+		EventData: &events.ClaManagerAccessRequestReminderEventData{
+			RequestID:      request.requestID,
+			RequesterName:  request.requesterName,
+			ReminderNumber: reminder,
+			DaysPending:    request.daysPending,
+		},
+	})
+	return false, true, nil
+}
+
+// loadCompanyAndProject loads the company and CLA group of the request
+func (s service) loadCompanyAndProject(request *pendingRequest) (*models.Company, *models.Project, error) {
+	companyModel, err := s.companyRepo.GetCompany(request.companyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	projectModel, err := s.projectRepo.GetCLAGroupByID(request.projectID, project.DontLoadRepoDetails)
+	if err != nil {
+		return nil, nil, err
+	}
+	return companyModel, projectModel, nil
+}
+
+// loadCLAManagers returns the CLA managers of the corporate signature the request is made for
+func (s service) loadCLAManagers(request *pendingRequest) ([]models.User, error) {
+	sig, err := s.signaturesRepo.GetCorporateSignature(request.projectID, request.companyID)
+	if err != nil {
+		return nil, err
+	}
+	if sig == nil {
+		return nil, nil
+	}
+	return sig.SignatureACL, nil
+}
+
+// expiryDate returns the date the request expires
+func (s service) expiryDate(request *pendingRequest) string {
+	return request.pendingSince.AddDate(0, 0, s.expiryDays).Format("2006-01-02")
+}
+
+// reminderLabel returns the label of the reminder, the last reminder before the request expires is the final one
+func (s service) reminderLabel(reminder int) string {
+	switch {
+	case reminder >= len(s.reminderDays):
+		return "Final Reminder"
+	case reminder == 1:
+		return "Reminder"
+	default:
+		return fmt.Sprintf("Reminder %d", reminder)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/gofrs/uuid"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
@@ -56,6 +57,7 @@ type ProjectRepository interface { //nolint
 	GetClaGroupsByFoundationSFID(foundationSFID string, loadRepoDetails bool) (*models.Projects, error)
 	GetClaGroupByProjectSFID(projectSFID string, loadRepoDetails bool) (*models.Project, error)
 	UpdateRootCLAGroupRepositoriesCount(claGroupID string, diff int64) error
+	UpdateCLAGroupResignPolicy(claGroupID string, resignRequired bool, gracePeriodDays int64) (*models.Project, error)
 }
 
 // NewRepository creates instance of project repository
@@ -716,6 +718,39 @@ func (repo *repo) UpdateCLAGroup(projectModel *models.Project) (*models.Project,
 	return repo.GetCLAGroupByID(projectModel.ProjectID, LoadRepoDetails)
 }
 
+// UpdateCLAGroupResignPolicy updates whether a new major version of the CLA Group documents requires re-signature and
+// the grace period of the previous version signatures
+func (repo *repo) UpdateCLAGroupResignPolicy(claGroupID string, resignRequired bool, gracePeriodDays int64) (*models.Project, error) {
+	_, currentTimeString := utils.CurrentTime()
+	input := &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"project_id": {S: aws.String(claGroupID)},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#R": aws.String("project_resign_required"),
+			"#G": aws.String("project_resign_grace_period_days"),
+			"#M": aws.String("date_modified"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":r": {BOOL: aws.Bool(resignRequired)},
+			":g": {N: aws.String(strconv.FormatInt(gracePeriodDays, 10))},
+			":m": {S: aws.String(currentTimeString)},
+		},
+		UpdateExpression:    aws.String("SET #R = :r, #G = :g, #M = :m"),
+		ConditionExpression: aws.String("attribute_exists(project_id)"),
+		TableName:           aws.String(repo.claGroupTable),
+	}
+	_, err := repo.dynamoDBClient.UpdateItem(input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return nil, ErrProjectDoesNotExist
+		}
+		log.WithField("cla_group_id", claGroupID).Warnf("unable to update the re-sign policy, error: %v", err)
+		return nil, err
+	}
+	return repo.GetCLAGroupByID(claGroupID, DontLoadRepoDetails)
+}
+
 func (repo *repo) UpdateRootCLAGroupRepositoriesCount(claGroupID string, diff int64) error {
 	val := strconv.FormatInt(diff, 10)
 	updateExp := "ADD root_project_repositories_count :val"
@@ -819,6 +854,8 @@ func (repo *repo) buildCLAGroupModel(dbModel DBProjectModel, loadRepoDetails boo
 		ProjectCCLAEnabled:           dbModel.ProjectCclaEnabled,
 		ProjectICLAEnabled:           dbModel.ProjectIclaEnabled,
 		ProjectCCLARequiresICLA:      dbModel.ProjectCclaRequiresIclaSignature,
+		ProjectResignRequired:        dbModel.ProjectResignRequired,
+		ProjectResignGracePeriodDays: dbModel.ProjectResignGracePeriodDays,
 		ProjectCorporateDocuments:    repo.buildCLAGroupDocumentModels(dbModel.ProjectCorporateDocuments),
 		ProjectIndividualDocuments:   repo.buildCLAGroupDocumentModels(dbModel.ProjectIndividualDocuments),
 		ProjectMemberDocuments:       repo.buildCLAGroupDocumentModels(dbModel.ProjectMemberDocuments),
@@ -851,12 +888,26 @@ func (repo *repo) buildCLAGroupDocumentModels(dbDocumentModels []DBProjectDocume
 			DocumentMajorVersion:    dbDocumentModel.DocumentMajorVersion,
 			DocumentMinorVersion:    dbDocumentModel.DocumentMinorVersion,
 			DocumentCreationDate:    dbDocumentModel.DocumentCreationDate,
+			DocumentLocale:          dbDocumentModel.DocumentLocale,
+			DocumentTranslations:    repo.buildCLAGroupDocumentTranslationModels(dbDocumentModel.DocumentTranslations),
 		})
 	}
 
 	return response
 }
 
+// buildCLAGroupDocumentTranslationModels builds the response models of the document translations
+func (repo *repo) buildCLAGroupDocumentTranslationModels(dbTranslationModels []DBProjectDocumentTranslationModel) []models.ProjectDocumentTranslation {
+	var response []models.ProjectDocumentTranslation
+	for _, dbTranslationModel := range dbTranslationModels {
+		response = append(response, models.ProjectDocumentTranslation{
+			Locale:        dbTranslationModel.Locale,
+			DocumentS3URL: dbTranslationModel.DocumentS3URL,
+		})
+	}
+	return response
+}
+
 // buildProject is a helper function to build a common set of projection/columns for the query
 func buildProjection() expression.ProjectionBuilder {
 	// These are the columns we want returned
@@ -872,6 +923,8 @@ func buildProjection() expression.ProjectionBuilder {
 		expression.Name("project_ccla_enabled"),
 		expression.Name("project_icla_enabled"),
 		expression.Name("project_ccla_requires_icla_signature"),
+		expression.Name("project_resign_required"),
+		expression.Name("project_resign_grace_period_days"),
 		expression.Name("project_corporate_documents"),
 		expression.Name("project_individual_documents"),
 		expression.Name("project_member_documents"),
@@ -0,0 +1,329 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/restapi/operations/project"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// memoryRepo re-uses the model builders of the DynamoDB repository and keeps the CLA Groups in the in-memory store
+type memoryRepo struct {
+	*repo
+	store *storage.MemoryStore
+}
+
+// NewMemoryRepository creates instance of project repository backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string, ghRepo repositories.Repository, gerritRepo gerrits.Repository, projectClaGroupRepo projects_cla_groups.Repository) ProjectRepository {
+	return &memoryRepo{
+		repo: &repo{
+			stage:               stage,
+			ghRepo:              ghRepo,
+			gerritRepo:          gerritRepo,
+			projectClaGroupRepo: projectClaGroupRepo,
+			claGroupTable:       fmt.Sprintf("cla-%s-projects", stage),
+		},
+		store: store,
+	}
+}
+
+// scan returns the CLA Groups matching the filter
+func (repo *memoryRepo) scan(filter func(dbModel *DBProjectModel) bool) ([]DBProjectModel, error) {
+	var dbModels []DBProjectModel
+	if err := repo.store.Scan(repo.claGroupTable, &dbModels); err != nil {
+		log.Warnf("error retrieving projects, error: %v", err)
+		return nil, err
+	}
+	var matched []DBProjectModel
+	for i := range dbModels {
+		if filter(&dbModels[i]) {
+			matched = append(matched, dbModels[i])
+		}
+	}
+	return matched, nil
+}
+
+// CreateCLAGroup creates a new project
+func (repo *memoryRepo) CreateCLAGroup(projectModel *models.Project) (*models.Project, error) {
+	f := logrus.Fields{
+		"function":          "CreateCLAGroup",
+		"projectName":       projectModel.ProjectName,
+		"projectExternalID": projectModel.ProjectExternalID,
+		"foundationSFID":    projectModel.FoundationSFID,
+		"tableName":         repo.claGroupTable}
+	projectID, err := uuid.NewV4()
+	if err != nil {
+		log.WithFields(f).Warnf("Unable to generate a UUID for a new project request, error: %v", err)
+		return nil, err
+	}
+
+	_, currentTimeString := utils.CurrentTime()
+	if projectModel.Version == "" {
+		projectModel.Version = "v1" // default value
+	}
+	dbModel := DBProjectModel{
+		DateCreated:                      currentTimeString,
+		DateModified:                     currentTimeString,
+		ProjectExternalID:                projectModel.ProjectExternalID,
+		ProjectID:                        projectID.String(),
+		FoundationSFID:                   projectModel.FoundationSFID,
+		ProjectDescription:               projectModel.ProjectDescription,
+		ProjectName:                      projectModel.ProjectName,
+		ProjectNameLower:                 strings.ToLower(projectModel.ProjectName),
+		Version:                          projectModel.Version,
+		ProjectCclaEnabled:               projectModel.ProjectCCLAEnabled,
+		ProjectCclaRequiresIclaSignature: projectModel.ProjectCCLARequiresICLA,
+		ProjectIclaEnabled:               projectModel.ProjectICLAEnabled,
+		ProjectCorporateDocuments:        []DBProjectDocumentModel{},
+		ProjectIndividualDocuments:       []DBProjectDocumentModel{},
+		ProjectMemberDocuments:           []DBProjectDocumentModel{},
+		ProjectACL:                       projectModel.ProjectACL,
+	}
+	if err := repo.store.Put(repo.claGroupTable, dbModel.ProjectID, dbModel); err != nil {
+		log.WithFields(f).Warnf("Unable to create a new project record, error: %v", err)
+		return nil, err
+	}
+
+	projectModel.ProjectID = dbModel.ProjectID
+	projectModel.DateCreated = currentTimeString
+	projectModel.DateModified = currentTimeString
+	return projectModel, nil
+}
+
+func (repo *memoryRepo) getCLAGroupByID(projectID string, loadCLAGroupDetails bool) (*models.Project, error) {
+	var dbModel DBProjectModel
+	found, err := repo.store.Get(repo.claGroupTable, projectID, &dbModel)
+	if err != nil {
+		log.Warnf("error unmarshalling db project model, error: %+v", err)
+		return nil, err
+	}
+	if !found {
+		return nil, ErrProjectDoesNotExist
+	}
+	return repo.buildCLAGroupModel(dbModel, loadCLAGroupDetails), nil
+}
+
+// GetCLAGroupByID returns the project model associated for the specified projectID
+func (repo *memoryRepo) GetCLAGroupByID(projectID string, loadRepoDetails bool) (*models.Project, error) {
+	return repo.getCLAGroupByID(projectID, loadRepoDetails)
+}
+
+// GetCLAGroupsByExternalID returns a list of the projects with the specified external ID
+func (repo *memoryRepo) GetCLAGroupsByExternalID(params *project.GetProjectsByExternalIDParams, loadRepoDetails bool) (*models.Projects, error) {
+	dbModels, err := repo.scan(func(dbModel *DBProjectModel) bool {
+		return dbModel.ProjectExternalID == params.ProjectSFID
+	})
+	if err != nil {
+		return nil, err
+	}
+	pageSize := int64(50)
+	if params.PageSize != nil && *params.PageSize > 0 {
+		pageSize = *params.PageSize
+	}
+	dbModels, lastEvaluatedKey := pageCLAGroups(dbModels, params.NextKey, pageSize)
+	projects := repo.buildMemoryCLAGroupModels(dbModels, loadRepoDetails)
+	return &models.Projects{
+		LastKeyScanned: lastEvaluatedKey,
+		PageSize:       pageSize,
+		ResultCount:    int64(len(projects)),
+		Projects:       projects,
+	}, nil
+}
+
+// GetClaGroupsByFoundationSFID returns a list of all cla_groups associated with foundation
+func (repo *memoryRepo) GetClaGroupsByFoundationSFID(foundationSFID string, loadRepoDetails bool) (*models.Projects, error) {
+	dbModels, err := repo.scan(func(dbModel *DBProjectModel) bool {
+		return dbModel.FoundationSFID == foundationSFID
+	})
+	if err != nil {
+		return nil, err
+	}
+	projects := repo.buildMemoryCLAGroupModels(dbModels, loadRepoDetails)
+	return &models.Projects{
+		ResultCount: int64(len(projects)),
+		Projects:    projects,
+	}, nil
+}
+
+// GetClaGroupByProjectSFID returns cla_group associated with project
+func (repo *memoryRepo) GetClaGroupByProjectSFID(projectSFID string, loadRepoDetails bool) (*models.Project, error) {
+	claGroupProject, err := repo.projectClaGroupRepo.GetClaGroupIDForProject(projectSFID)
+	if err != nil {
+		log.Warnf("error fetching CLA Group ID for project: %s, error: %v", projectSFID, err)
+		return nil, err
+	}
+	return repo.getCLAGroupByID(claGroupProject.ClaGroupID, loadRepoDetails)
+}
+
+// GetCLAGroupByName returns the project model associated for the specified project name
+func (repo *memoryRepo) GetCLAGroupByName(projectName string) (*models.Project, error) {
+	dbModels, err := repo.scan(func(dbModel *DBProjectModel) bool {
+		return dbModel.ProjectNameLower == strings.ToLower(projectName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(dbModels) == 0 {
+		return nil, nil
+	}
+	if len(dbModels) > 1 {
+		log.Warnf("CLAGroup scan by name returned more than one result using projectName: %s", projectName)
+	}
+	return repo.buildCLAGroupModel(dbModels[0], LoadRepoDetails), nil
+}
+
+// GetExternalCLAGroup returns the project model associated for the specified external project ID
+func (repo *memoryRepo) GetExternalCLAGroup(projectExternalID string) (*models.Project, error) {
+	dbModels, err := repo.scan(func(dbModel *DBProjectModel) bool {
+		return dbModel.ProjectExternalID == projectExternalID
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(dbModels) == 0 {
+		return nil, nil
+	}
+	if len(dbModels) > 1 {
+		log.Warnf("CLAGroup query returned more than one result using projectExternalID: %s", projectExternalID)
+	}
+	return repo.buildCLAGroupModel(dbModels[0], LoadRepoDetails), nil
+}
+
+// GetCLAGroups returns a list of the projects
+func (repo *memoryRepo) GetCLAGroups(params *project.GetProjectsParams) (*models.Projects, error) {
+	dbModels, err := repo.scan(func(dbModel *DBProjectModel) bool {
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	pageSize := int64(50)
+	if params.PageSize != nil && *params.PageSize > 0 {
+		pageSize = *params.PageSize
+	}
+	dbModels, lastEvaluatedKey := pageCLAGroups(dbModels, params.NextKey, pageSize)
+	return &models.Projects{
+		LastKeyScanned: lastEvaluatedKey,
+		PageSize:       pageSize,
+		Projects:       repo.buildMemoryCLAGroupModels(dbModels, LoadRepoDetails),
+	}, nil
+}
+
+// DeleteCLAGroup deletes the CLAGroup by projectID
+func (repo *memoryRepo) DeleteCLAGroup(projectID string) error {
+	found, err := repo.store.Get(repo.claGroupTable, projectID, &DBProjectModel{})
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Warnf("unable to locate CLA Group by ID: %s - CLA Group does not exist", projectID)
+		return ErrProjectDoesNotExist
+	}
+	return repo.store.Delete(repo.claGroupTable, projectID)
+}
+
+// UpdateCLAGroup updates the project by projectID
+func (repo *memoryRepo) UpdateCLAGroup(projectModel *models.Project) (*models.Project, error) {
+	if projectModel.ProjectID == "" {
+		return nil, ErrProjectIDMissing
+	}
+
+	_, currentTimeString := utils.CurrentTime()
+	var dbModel DBProjectModel
+	err := repo.store.Update(repo.claGroupTable, projectModel.ProjectID, &dbModel, func() error {
+		if projectModel.ProjectName != "" {
+			dbModel.ProjectName = projectModel.ProjectName
+			dbModel.ProjectNameLower = strings.ToLower(projectModel.ProjectName)
+		}
+		if len(projectModel.ProjectACL) > 0 {
+			dbModel.ProjectACL = projectModel.ProjectACL
+		}
+		dbModel.ProjectIclaEnabled = projectModel.ProjectICLAEnabled
+		dbModel.ProjectCclaEnabled = projectModel.ProjectCCLAEnabled
+		dbModel.ProjectCclaRequiresIclaSignature = projectModel.ProjectCCLARequiresICLA
+		dbModel.DateModified = currentTimeString
+		return nil
+	})
+	if err == storage.ErrItemNotFound {
+		return nil, ErrProjectDoesNotExist
+	}
+	if err != nil {
+		log.Warnf("error updating CLAGroup by projectID: %s, error: %v", projectModel.ProjectID, err)
+		return nil, err
+	}
+	return repo.GetCLAGroupByID(projectModel.ProjectID, LoadRepoDetails)
+}
+
+// UpdateRootCLAGroupRepositoriesCount adds the diff to the root project repositories count
+func (repo *memoryRepo) UpdateRootCLAGroupRepositoriesCount(claGroupID string, diff int64) error {
+	var dbModel DBProjectModel
+	err := repo.store.Update(repo.claGroupTable, claGroupID, &dbModel, func() error {
+		dbModel.RootProjectRepositoriesCount += diff
+		return nil
+	})
+	if err != nil {
+		log.WithField("cla_group_id", claGroupID).Error("unable to update repositories count", err)
+	}
+	return err
+}
+
+// UpdateCLAGroupResignPolicy updates the re-sign policy of the CLA Group
+func (repo *memoryRepo) UpdateCLAGroupResignPolicy(claGroupID string, resignRequired bool, gracePeriodDays int64) (*models.Project, error) {
+	_, currentTimeString := utils.CurrentTime()
+	var dbModel DBProjectModel
+	err := repo.store.Update(repo.claGroupTable, claGroupID, &dbModel, func() error {
+		dbModel.ProjectResignRequired = resignRequired
+		dbModel.ProjectResignGracePeriodDays = gracePeriodDays
+		dbModel.DateModified = currentTimeString
+		return nil
+	})
+	if err == storage.ErrItemNotFound {
+		return nil, ErrProjectDoesNotExist
+	}
+	if err != nil {
+		log.WithField("cla_group_id", claGroupID).Warnf("unable to update the re-sign policy, error: %v", err)
+		return nil, err
+	}
+	return repo.GetCLAGroupByID(claGroupID, DontLoadRepoDetails)
+}
+
+// buildMemoryCLAGroupModels converts the database models into API response data models
+func (repo *memoryRepo) buildMemoryCLAGroupModels(dbModels []DBProjectModel, loadRepoDetails bool) []models.Project {
+	var projects []models.Project
+	for _, dbModel := range dbModels {
+		projects = append(projects, *repo.buildCLAGroupModel(dbModel, loadRepoDetails))
+	}
+	return projects
+}
+
+// pageCLAGroups returns the page of CLA Groups following the next key - the returned key is the ID of the last
+// CLA Group on the page, or empty if this is the last page
+func pageCLAGroups(dbModels []DBProjectModel, nextKey *string, pageSize int64) ([]DBProjectModel, string) {
+	start := 0
+	if nextKey != nil && *nextKey != "" {
+		for i, dbModel := range dbModels {
+			if dbModel.ProjectID == *nextKey {
+				start = i + 1
+				break
+			}
+		}
+	}
+	dbModels = dbModels[start:]
+	if int64(len(dbModels)) <= pageSize {
+		return dbModels, ""
+	}
+	dbModels = dbModels[:pageSize]
+	return dbModels, dbModels[len(dbModels)-1].ProjectID
+}
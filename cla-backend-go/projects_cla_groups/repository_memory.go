@@ -0,0 +1,188 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package projects_cla_groups
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	v2ProjectService "github.com/communitybridge/easycla/cla-backend-go/v2/project-service"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+type memoryRepo struct {
+	store          *storage.MemoryStore
+	tableName      string
+	claGroupsTable string
+}
+
+// NewMemoryRepository provides an in-memory implementation of projects_cla_group repository
+func NewMemoryRepository(store *storage.MemoryStore, stage string) Repository {
+	return &memoryRepo{
+		store:          store,
+		tableName:      fmt.Sprintf("cla-%s-projects-cla-groups", stage),
+		claGroupsTable: fmt.Sprintf("cla-%s-projects", stage),
+	}
+}
+
+// scan returns all the project cla group entries matching the filter
+func (repo *memoryRepo) scan(filter func(pcg *ProjectClaGroup) bool) ([]*ProjectClaGroup, error) {
+	var all []*ProjectClaGroup
+	if err := repo.store.Scan(repo.tableName, &all); err != nil {
+		log.Warnf("error retrieving project cla-groups, error: %v", err)
+		return nil, err
+	}
+	var projectClaGroups []*ProjectClaGroup
+	for _, pcg := range all {
+		if filter(pcg) {
+			projectClaGroups = append(projectClaGroups, pcg)
+		}
+	}
+	return projectClaGroups, nil
+}
+
+// GetClaGroupIDForProject retrieves the CLA Group ID for the project
+func (repo *memoryRepo) GetClaGroupIDForProject(projectSFID string) (*ProjectClaGroup, error) {
+	var out ProjectClaGroup
+	found, err := repo.store.Get(repo.tableName, projectSFID, &out)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrProjectNotAssociatedWithClaGroup
+	}
+	return &out, nil
+}
+
+func (repo *memoryRepo) GetProjectsIdsForClaGroup(claGroupID string) ([]*ProjectClaGroup, error) {
+	return repo.scan(func(pcg *ProjectClaGroup) bool {
+		return pcg.ClaGroupID == claGroupID
+	})
+}
+
+func (repo *memoryRepo) GetProjectsIdsForFoundation(foundationSFID string) ([]*ProjectClaGroup, error) {
+	return repo.scan(func(pcg *ProjectClaGroup) bool {
+		return pcg.FoundationSFID == foundationSFID
+	})
+}
+
+func (repo *memoryRepo) GetProjectsIdsForAllFoundation() ([]*ProjectClaGroup, error) {
+	return repo.scan(func(pcg *ProjectClaGroup) bool {
+		return true
+	})
+}
+
+// AssociateClaGroupWithProject creates entry in the store to track cla_group association with project/foundation
+func (repo *memoryRepo) AssociateClaGroupWithProject(claGroupID string, projectSFID string, foundationSFID string) error {
+	var foundationName = NotDefined
+	projectServiceModel, projErr := v2ProjectService.GetClient().GetProject(foundationSFID)
+	if projErr != nil {
+		log.Warnf("unable to lookup foundation SFID: %s - error: %+v - using '%s'",
+			foundationSFID, projErr, NotDefined)
+	} else {
+		foundationName = projectServiceModel.Name
+	}
+
+	var projectName = NotDefined
+	projectServiceModel, projErr = v2ProjectService.GetClient().GetProject(projectSFID)
+	if projErr != nil {
+		log.Warnf("unable to lookup project SFID: %s - error: %+v - using '%s'",
+			projectSFID, projErr, NotDefined)
+	} else {
+		projectName = projectServiceModel.Name
+	}
+
+	claGroupName, claGroupLookupErr := repo.getCLAGroupNameByID(claGroupID)
+	if claGroupLookupErr != nil {
+		claGroupName = NotDefined
+		log.Warnf("unable to lookup CLA Group ID/Project ID: %s - error: %+v - using '%s'",
+			claGroupID, claGroupLookupErr, NotDefined)
+	}
+
+	input := &ProjectClaGroup{
+		ProjectSFID:    projectSFID,
+		ProjectName:    projectName,
+		ClaGroupID:     claGroupID,
+		ClaGroupName:   claGroupName,
+		FoundationSFID: foundationSFID,
+		FoundationName: foundationName,
+		Version:        "v1",
+	}
+	err := repo.store.Create(repo.tableName, projectSFID, input)
+	if err == storage.ErrItemAlreadyExists {
+		return ErrAssociationAlreadyExist
+	}
+	return err
+}
+
+// RemoveProjectAssociatedWithClaGroup removes all associated project with cla_group
+func (repo *memoryRepo) RemoveProjectAssociatedWithClaGroup(claGroupID string, projectSFIDList []string, all bool) error {
+	list, err := repo.GetProjectsIdsForClaGroup(claGroupID)
+	if err != nil {
+		return err
+	}
+	var projectFilter *utils.StringSet
+	if !all {
+		projectFilter = utils.NewStringSetFromStringArray(projectSFIDList)
+	}
+	for _, pr := range list {
+		if !all && !projectFilter.Include(pr.ProjectSFID) {
+			continue
+		}
+		if err := repo.store.Delete(repo.tableName, pr.ProjectSFID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getCLAGroupNameByID helper function to fetch the CLA Group name
+func (repo *memoryRepo) getCLAGroupNameByID(claGroupID string) (string, error) {
+	// Quick model to grab the bare minimum values
+	type claGroupIDNameModel struct {
+		ProjectID   string `dynamodbav:"project_id"`
+		ProjectName string `dynamodbav:"project_name"`
+	}
+
+	var claGroupModel claGroupIDNameModel
+	found, err := repo.store.Get(repo.claGroupsTable, claGroupID, &claGroupModel)
+	if err != nil {
+		return NotFound, err
+	}
+	if !found {
+		return NotFound, ErrCLAGroupDoesNotExist
+	}
+	return claGroupModel.ProjectName, nil
+}
+
+func (repo *memoryRepo) UpdateRepositoriesCount(projectSFID string, diff int64) error {
+	var pcg ProjectClaGroup
+	err := repo.store.Update(repo.tableName, projectSFID, &pcg, func() error {
+		pcg.RepositoriesCount += diff
+		return nil
+	})
+	if err != nil {
+		log.WithField("project_sfid", projectSFID).Error("update repositories count failed", err)
+	}
+	return err
+}
+
+func (repo *memoryRepo) IsAssociated(projectSFID string, claGroupID string) (bool, error) {
+	pmlist, err := repo.GetProjectsIdsForClaGroup(claGroupID)
+	if err != nil {
+		return false, err
+	}
+	if len(pmlist) == 0 {
+		return false, errors.New("no cla-group mapping found for cla-group")
+	}
+	for _, pm := range pmlist {
+		if pm.ProjectSFID == projectSFID || pm.FoundationSFID == projectSFID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,178 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+)
+
+// NewMemoryRepository creates a new github repositories repository backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string) Repository {
+	return &memoryRepo{
+		store:               store,
+		repositoryTableName: fmt.Sprintf("cla-%s-repositories", stage),
+	}
+}
+
+type memoryRepo struct {
+	store               *storage.MemoryStore
+	repositoryTableName string
+}
+
+// scan returns all the github repositories which match the specified filter
+func (repo *memoryRepo) scan(filter func(gr *GithubRepository) bool) ([]*models.GithubRepository, error) {
+	var result []*GithubRepository
+	if err := repo.store.Scan(repo.repositoryTableName, &result); err != nil {
+		return nil, err
+	}
+	out := make([]*models.GithubRepository, 0)
+	for _, gr := range result {
+		if filter(gr) {
+			out = append(out, gr.toModel())
+		}
+	}
+	return out, nil
+}
+
+// GetProjectRepositoriesGroupByOrgs returns a list of GH orgs by project id
+func (repo *memoryRepo) GetProjectRepositoriesGroupByOrgs(projectID string) ([]*models.GithubRepositoriesGroupByOrgs, error) {
+	out := make([]*models.GithubRepositoriesGroupByOrgs, 0)
+	outMap := make(map[string]*models.GithubRepositoriesGroupByOrgs)
+	ghrepos, err := repo.scan(func(gr *GithubRepository) bool {
+		return gr.RepositoryProjectID == projectID
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, ghrepo := range ghrepos {
+		ghrepoGroup, ok := outMap[ghrepo.RepositoryOrganizationName]
+		if !ok {
+			ghrepoGroup = &models.GithubRepositoriesGroupByOrgs{
+				OrganizationName: ghrepo.RepositoryOrganizationName,
+			}
+			out = append(out, ghrepoGroup)
+			outMap[ghrepo.RepositoryOrganizationName] = ghrepoGroup
+		}
+		ghrepoGroup.List = append(ghrepoGroup.List, ghrepo)
+	}
+	return out, nil
+}
+
+func (repo *memoryRepo) deleteGithubRepository(externalProjectID, projectSFID, ghRepoID string) error {
+	var existing GithubRepository
+	found, err := repo.store.Get(repo.repositoryTableName, ghRepoID, &existing)
+	if err != nil {
+		return err
+	}
+	if !found || (projectSFID != "" && existing.ProjectSFID != projectSFID) || (projectSFID == "" && existing.RepositorySfdcID != externalProjectID) {
+		return errors.New("github repository does not exist or repository_sfdc_id does not match with specifiled project id")
+	}
+	return repo.store.Delete(repo.repositoryTableName, ghRepoID)
+}
+
+func (repo *memoryRepo) DeleteRepositoriesOfGithubOrganization(externalProjectID, projectSFID, githubOrgName string) error {
+	ghrepos, err := repo.scan(func(gr *GithubRepository) bool {
+		return gr.RepositoryOrganizationName == githubOrgName
+	})
+	if err != nil {
+		return err
+	}
+	for _, ghrepo := range ghrepos {
+		err = repo.deleteGithubRepository(externalProjectID, projectSFID, ghrepo.RepositoryID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *memoryRepo) AddGithubRepository(externalProjectID string, projectSFID string, input *models.GithubRepositoryInput) (*models.GithubRepository, error) {
+	existing, err := repo.scan(func(gr *GithubRepository) bool {
+		return gr.RepositoryExternalID == utils.StringValue(input.RepositoryExternalID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, errors.New("github repository already exist")
+	}
+	_, currentTime := utils.CurrentTime()
+	repoID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	repository := &GithubRepository{
+		DateCreated:                currentTime,
+		DateModified:               currentTime,
+		RepositoryExternalID:       utils.StringValue(input.RepositoryExternalID),
+		RepositoryID:               repoID.String(),
+		RepositoryName:             utils.StringValue(input.RepositoryName),
+		RepositoryOrganizationName: utils.StringValue(input.RepositoryOrganizationName),
+		RepositoryProjectID:        utils.StringValue(input.RepositoryProjectID),
+		RepositorySfdcID:           externalProjectID,
+		RepositoryType:             utils.StringValue(input.RepositoryType),
+		RepositoryURL:              utils.StringValue(input.RepositoryURL),
+		ProjectSFID:                projectSFID,
+		Version:                    "v1",
+	}
+	if err := repo.store.Put(repo.repositoryTableName, repository.RepositoryID, repository); err != nil {
+		return nil, err
+	}
+	return repository.toModel(), nil
+}
+
+func (repo *memoryRepo) DeleteGithubRepository(externalProjectID string, projectSFID string, repositoryID string) error {
+	return repo.deleteGithubRepository(externalProjectID, projectSFID, repositoryID)
+}
+
+// List github repositories of project by external/salesforce project id
+func (repo *memoryRepo) ListProjectRepositories(externalProjectID string, projectSFID string) (*models.ListGithubRepositories, error) {
+	list, err := repo.scan(func(gr *GithubRepository) bool {
+		if externalProjectID != "" {
+			return gr.RepositorySfdcID == externalProjectID
+		}
+		return gr.ProjectSFID == projectSFID
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.ListGithubRepositories{List: list}, nil
+}
+
+func (repo *memoryRepo) GetGithubRepository(repositoryID string) (*models.GithubRepository, error) {
+	var out GithubRepository
+	found, err := repo.store.Get(repo.repositoryTableName, repositoryID, &out)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrGithubRepositoryNotFound
+	}
+	return out.toModel(), nil
+}
+
+// Unassign project from given repository
+func (repo *memoryRepo) DeleteProject(repositoryID string) error {
+	return repo.store.Delete(repo.repositoryTableName, repositoryID)
+}
+
+// GetGithubRepositoryByCLAGroup gets GHRepo by project|ClaGroup ID
+func (repo *memoryRepo) GetGithubRepositoryByCLAGroup(claGroupID string) (*models.GithubRepository, error) {
+	list, err := repo.scan(func(gr *GithubRepository) bool {
+		return gr.RepositoryProjectID == claGroupID
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, ErrGithubRepositoryNotFound
+	}
+	return list[0], nil
+}
@@ -0,0 +1,196 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// Approval list types which may carry an expiration date
+const (
+	ApprovalListTypeEmail          = "email"
+	ApprovalListTypeDomain         = "domain"
+	ApprovalListTypeGithubUsername = "githubUsername"
+	ApprovalListTypeGithubOrg      = "githubOrg"
+	ApprovalListTypeGithubTeam     = "githubTeam"
+)
+
+// validApprovalListType returns true if the specified value is one of the approval list types
+func validApprovalListType(listType string) bool {
+	switch listType {
+	case ApprovalListTypeEmail, ApprovalListTypeDomain, ApprovalListTypeGithubUsername, ApprovalListTypeGithubOrg, ApprovalListTypeGithubTeam:
+		return true
+	}
+	return false
+}
+
+// approvalListEntries returns the entries of the specified approval list of the signature
+func approvalListEntries(sig *models.Signature, listType string) []string {
+	switch listType {
+	case ApprovalListTypeEmail:
+		return sig.EmailApprovalList
+	case ApprovalListTypeDomain:
+		return sig.DomainApprovalList
+	case ApprovalListTypeGithubUsername:
+		return sig.GithubUsernameApprovalList
+	case ApprovalListTypeGithubOrg:
+		return sig.GithubOrgApprovalList
+	case ApprovalListTypeGithubTeam:
+		return sig.GithubTeamApprovalList
+	}
+	return nil
+}
+
+// approvalListChanges returns the entries added to and removed from the specified approval list by the update
+func approvalListChanges(params *models.ApprovalList, listType string) ([]string, []string) {
+	switch listType {
+	case ApprovalListTypeEmail:
+		return params.AddEmailApprovalList, params.RemoveEmailApprovalList
+	case ApprovalListTypeDomain:
+		return params.AddDomainApprovalList, params.RemoveDomainApprovalList
+	case ApprovalListTypeGithubUsername:
+		return params.AddGithubUsernameApprovalList, params.RemoveGithubUsernameApprovalList
+	case ApprovalListTypeGithubOrg:
+		return params.AddGithubOrgApprovalList, params.RemoveGithubOrgApprovalList
+	case ApprovalListTypeGithubTeam:
+		return params.AddGithubTeamApprovalList, params.RemoveGithubTeamApprovalList
+	}
+	return nil, nil
+}
+
+// buildApprovalListExpirationModels converts the database expiration records into the response models
+func buildApprovalListExpirationModels(dbExpirations []DBApprovalListExpiration) []*models.ApprovalListExpiration {
+	if len(dbExpirations) == 0 {
+		return nil
+	}
+	expirations := make([]*models.ApprovalListExpiration, 0, len(dbExpirations))
+	for _, dbExpiration := range dbExpirations {
+		expirations = append(expirations, &models.ApprovalListExpiration{
+			ListType:         dbExpiration.ListType,
+			Value:            dbExpiration.Value,
+			ExpirationDate:   dbExpiration.ExpirationDate,
+			ReminderSentDate: dbExpiration.ReminderSentDate,
+		})
+	}
+	return expirations
+}
+
+// buildApprovalListExpirationsAttribute converts the expiration models into a dynamodb attribute, returns nil if
+// there are no expirations so the caller can remove the column
+func buildApprovalListExpirationsAttribute(expirations []*models.ApprovalListExpiration) (*dynamodb.AttributeValue, error) {
+	if len(expirations) == 0 {
+		return nil, nil
+	}
+	dbExpirations := make([]DBApprovalListExpiration, 0, len(expirations))
+	for _, expiration := range expirations {
+		dbExpirations = append(dbExpirations, DBApprovalListExpiration{
+			ListType:         expiration.ListType,
+			Value:            expiration.Value,
+			ExpirationDate:   expiration.ExpirationDate,
+			ReminderSentDate: expiration.ReminderSentDate,
+		})
+	}
+	return dynamodbattribute.Marshal(dbExpirations)
+}
+
+// mergeApprovalListExpirations returns the expirations of the signature after applying the approval list update -
+// expirations of removed entries are dropped, expirations in the update replace the existing ones. Returns false if
+// the update doesn't change the expirations.
+func mergeApprovalListExpirations(sig *models.Signature, params *models.ApprovalList) ([]*models.ApprovalListExpiration, bool, error) {
+	changed := false
+	var merged []*models.ApprovalListExpiration
+	for _, expiration := range sig.ApprovalListExpirations {
+		_, removeEntries := approvalListChanges(params, expiration.ListType)
+		if utils.StringInSlice(expiration.Value, removeEntries) {
+			changed = true
+			continue
+		}
+		merged = append(merged, expiration)
+	}
+
+	for _, expiration := range params.ApprovalListExpirations {
+		if expiration == nil {
+			continue
+		}
+		value := strings.TrimSpace(expiration.Value)
+		if !validApprovalListType(expiration.ListType) {
+			return nil, false, NewBadRequestError(fmt.Sprintf("invalid approval list type %s for expiration of %s", expiration.ListType, value))
+		}
+		addEntries, removeEntries := approvalListChanges(params, expiration.ListType)
+		inList := (utils.StringInSlice(value, approvalListEntries(sig, expiration.ListType)) || utils.StringInSlice(value, addEntries)) &&
+			!utils.StringInSlice(value, removeEntries)
+		if !inList {
+			return nil, false, NewBadRequestError(fmt.Sprintf("unable to set expiration for %s - %s is not in the approval list", expiration.ListType, value))
+		}
+
+		var expirationDate string
+		if strings.TrimSpace(expiration.ExpirationDate) != "" {
+			expirationTime, err := utils.ParseDateTime(expiration.ExpirationDate)
+			if err != nil {
+				return nil, false, NewBadRequestError(fmt.Sprintf("invalid expiration date %s for %s - %s", expiration.ExpirationDate, value, err))
+			}
+			if !expirationTime.After(time.Now()) {
+				return nil, false, NewBadRequestError(fmt.Sprintf("expiration date %s for %s must be in the future", expiration.ExpirationDate, value))
+			}
+			expirationDate = utils.TimeToString(expirationTime)
+		}
+
+		// Replace any existing expiration of the entry - an empty expiration date clears it
+		changed = true
+		var updated []*models.ApprovalListExpiration
+		for _, existing := range merged {
+			if existing.ListType != expiration.ListType || existing.Value != value {
+				updated = append(updated, existing)
+			}
+		}
+		if expirationDate != "" {
+			updated = append(updated, &models.ApprovalListExpiration{
+				ListType:       expiration.ListType,
+				Value:          value,
+				ExpirationDate: expirationDate,
+			})
+		}
+		merged = updated
+	}
+
+	return merged, changed, nil
+}
+
+// IsApprovalListEntryExpired returns true if the specified approval list entry carries an expiration date which has
+// passed - expired entries no longer grant coverage, even before they are removed from the approval list
+func IsApprovalListEntryExpired(sig *models.Signature, listType, value string, now time.Time) bool {
+	for _, expiration := range sig.ApprovalListExpirations {
+		if expiration.ListType == listType && strings.EqualFold(expiration.Value, value) {
+			return approvalListExpirationPassed(expiration, now)
+		}
+	}
+	return false
+}
+
+// ExpiredApprovalListEntries returns the expirations of the signature which have passed
+func ExpiredApprovalListEntries(sig *models.Signature, now time.Time) []*models.ApprovalListExpiration {
+	var expired []*models.ApprovalListExpiration
+	for _, expiration := range sig.ApprovalListExpirations {
+		if approvalListExpirationPassed(expiration, now) {
+			expired = append(expired, expiration)
+		}
+	}
+	return expired
+}
+
+// approvalListExpirationPassed returns true if the expiration date is at or before the specified time
+func approvalListExpirationPassed(expiration *models.ApprovalListExpiration, now time.Time) bool {
+	expirationTime, err := utils.ParseDateTime(expiration.ExpirationDate)
+	if err != nil {
+		return false
+	}
+	return !expirationTime.After(now)
+}
@@ -0,0 +1,113 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// Auto-approval rule types
+const (
+	AutoApprovalRuleTypeEmailDomain = "emailDomain"
+	AutoApprovalRuleTypeGithubOrg   = "githubOrg"
+)
+
+// buildAutoApprovalRuleModels converts the database auto-approval rules into the response models
+func buildAutoApprovalRuleModels(dbRules []DBAutoApprovalRule) []*models.AutoApprovalRule {
+	if len(dbRules) == 0 {
+		return nil
+	}
+	rules := make([]*models.AutoApprovalRule, 0, len(dbRules))
+	for _, dbRule := range dbRules {
+		ruleType := dbRule.RuleType
+		rules = append(rules, &models.AutoApprovalRule{
+			RuleType: &ruleType,
+			Values:   dbRule.Values,
+		})
+	}
+	return rules
+}
+
+// buildAutoApprovalRulesAttribute converts the auto-approval rules into a dynamodb attribute, returns nil if there are
+// no rules so the caller can remove the column
+func buildAutoApprovalRulesAttribute(rules []*models.AutoApprovalRule) (*dynamodb.AttributeValue, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	dbRules := make([]DBAutoApprovalRule, 0, len(rules))
+	for _, rule := range rules {
+		dbRules = append(dbRules, DBAutoApprovalRule{
+			RuleType: utils.StringValue(rule.RuleType),
+			Values:   rule.Values,
+		})
+	}
+	return dynamodbattribute.Marshal(dbRules)
+}
+
+// NormalizeAutoApprovalRules validates the auto-approval rules and returns them in their canonical form - email
+// domains may be exact domains or *. wildcard patterns, GitHub organizations are matched case insensitively
+func NormalizeAutoApprovalRules(rules []*models.AutoApprovalRule) ([]*models.AutoApprovalRule, error) {
+	var normalized []*models.AutoApprovalRule
+	var listOfErrors []string
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		ruleType := strings.TrimSpace(utils.StringValue(rule.RuleType))
+		if ruleType != AutoApprovalRuleTypeEmailDomain && ruleType != AutoApprovalRuleTypeGithubOrg {
+			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid auto-approval rule type %s", ruleType))
+			continue
+		}
+
+		var values []string
+		for _, value := range rule.Values {
+			value = strings.TrimSpace(value)
+			switch ruleType {
+			case AutoApprovalRuleTypeEmailDomain:
+				if msg, valid := utils.ValidDomainPattern(value); !valid {
+					listOfErrors = append(listOfErrors, fmt.Sprintf("invalid auto-approval rule domain %s - %s", value, msg))
+					continue
+				}
+				value = utils.NormalizeDomainPattern(value)
+			case AutoApprovalRuleTypeGithubOrg:
+				if value == "" || strings.ContainsAny(value, "/ ") {
+					listOfErrors = append(listOfErrors, fmt.Sprintf("invalid auto-approval rule GitHub organization %s", value))
+					continue
+				}
+			}
+			if !containsFold(values, value) {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			listOfErrors = append(listOfErrors, fmt.Sprintf("the %s auto-approval rule requires at least one value", ruleType))
+			continue
+		}
+		normalized = append(normalized, &models.AutoApprovalRule{
+			RuleType: &ruleType,
+			Values:   values,
+		})
+	}
+
+	if len(listOfErrors) > 0 {
+		return nil, NewBadRequestError(strings.Join(listOfErrors, ", "))
+	}
+	return normalized, nil
+}
+
+// containsFold returns true if the value is in the list, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,38 +5,68 @@ package signatures
 
 // ItemSignature database model
 type ItemSignature struct {
-	SignatureID                   string   `json:"signature_id"`
-	DateCreated                   string   `json:"date_created"`
-	DateModified                  string   `json:"date_modified"`
-	SignatureApproved             bool     `json:"signature_approved"`
-	SignatureSigned               bool     `json:"signature_signed"`
-	SignatureDocumentMajorVersion string   `json:"signature_document_major_version"`
-	SignatureDocumentMinorVersion string   `json:"signature_document_minor_version"`
-	SignatureReferenceID          string   `json:"signature_reference_id"`
-	SignatureReferenceName        string   `json:"signature_reference_name"`
-	SignatureReferenceNameLower   string   `json:"signature_reference_name_lower"`
-	SignatureProjectID            string   `json:"signature_project_id"`
-	SignatureReferenceType        string   `json:"signature_reference_type"`
-	SignatureType                 string   `json:"signature_type"`
-	SignatureUserCompanyID        string   `json:"signature_user_ccla_company_id"`
-	EmailWhitelist                []string `json:"email_whitelist"`
-	DomainWhitelist               []string `json:"domain_whitelist"`
-	GitHubWhitelist               []string `json:"github_whitelist"`
-	GitHubOrgWhitelist            []string `json:"github_org_whitelist"`
-	SignatureACL                  []string `json:"signature_acl"`
-	UserGithubUsername            string   `json:"user_github_username"`
-	UserLFUsername                string   `json:"user_lf_username"`
-	UserName                      string   `json:"user_name"`
-	UserEmail                     string   `json:"user_email"`
-	SigtypeSignedApprovedID       string   `json:"sigtype_signed_approved_id"`
-	SignedOn                      string   `json:"signed_on"`
-	SignatoryName                 string   `json:"signatory_name"`
+	SignatureID                   string                     `json:"signature_id"`
+	DateCreated                   string                     `json:"date_created"`
+	DateModified                  string                     `json:"date_modified"`
+	SignatureApproved             bool                       `json:"signature_approved"`
+	SignatureSigned               bool                       `json:"signature_signed"`
+	SignatureDocumentMajorVersion string                     `json:"signature_document_major_version"`
+	SignatureDocumentMinorVersion string                     `json:"signature_document_minor_version"`
+	SignatureReferenceID          string                     `json:"signature_reference_id"`
+	SignatureReferenceName        string                     `json:"signature_reference_name"`
+	SignatureReferenceNameLower   string                     `json:"signature_reference_name_lower"`
+	SignatureProjectID            string                     `json:"signature_project_id"`
+	SignatureReferenceType        string                     `json:"signature_reference_type"`
+	SignatureType                 string                     `json:"signature_type"`
+	SignatureUserCompanyID        string                     `json:"signature_user_ccla_company_id"`
+	EmailWhitelist                []string                   `json:"email_whitelist"`
+	DomainWhitelist               []string                   `json:"domain_whitelist"`
+	GitHubWhitelist               []string                   `json:"github_whitelist"`
+	GitHubOrgWhitelist            []string                   `json:"github_org_whitelist"`
+	GitHubTeamWhitelist           []string                   `json:"github_team_whitelist"`
+	SignatureACL                  []string                   `json:"signature_acl"`
+	UserGithubUsername            string                     `json:"user_github_username"`
+	UserLFUsername                string                     `json:"user_lf_username"`
+	UserName                      string                     `json:"user_name"`
+	UserEmail                     string                     `json:"user_email"`
+	SigtypeSignedApprovedID       string                     `json:"sigtype_signed_approved_id"`
+	SignedOn                      string                     `json:"signed_on"`
+	SignatoryName                 string                     `json:"signatory_name"`
+	RecordVersion                 int64                      `json:"record_version"`
+	ApprovalListExpirations       []DBApprovalListExpiration `json:"approval_list_expirations"`
+	AutoApprovalRules             []DBAutoApprovalRule       `json:"auto_approval_rules"`
+	SignatureRevocation           *DBSignatureRevocation     `json:"signature_revocation,omitempty"`
+	SignatureDocumentSHA256       string                     `json:"signature_document_sha256,omitempty"`
+	SignatureDocumentLocale       string                     `json:"signature_document_locale,omitempty"`
+}
+
+// DBApprovalListExpiration is a database model for the expiration of a single approval list entry
+type DBApprovalListExpiration struct {
+	ListType         string `json:"list_type"`
+	Value            string `json:"value"`
+	ExpirationDate   string `json:"expiration_date"`
+	ReminderSentDate string `json:"reminder_sent_date,omitempty"`
+}
+
+// DBAutoApprovalRule is a database model for a CCLA approval list request auto-approval rule
+type DBAutoApprovalRule struct {
+	RuleType string   `json:"rule_type"`
+	Values   []string `json:"values"`
+}
+
+// DBSignatureRevocation is a database model for the revocation of a signature
+type DBSignatureRevocation struct {
+	Reason    string `json:"reason"`
+	Comment   string `json:"comment,omitempty"`
+	RevokedOn string `json:"revoked_on"`
+	RevokedBy string `json:"revoked_by"`
 }
 
 // DBManagersModel is a database model for only the ACL/Manager column
 type DBManagersModel struct {
-	SignatureID  string   `json:"signature_id"`
-	SignatureACL []string `json:"signature_acl"`
+	SignatureID   string   `json:"signature_id"`
+	SignatureACL  []string `json:"signature_acl"`
+	RecordVersion int64    `json:"record_version"`
 }
 
 // DBSignatureUsersModel is a database model for only the signature ID and signature_reference_id fields
@@ -3,6 +3,8 @@
 
 package signatures
 
+import "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+
 // NewBadRequestError returns an error that formats as the given text.
 func NewBadRequestError(text string) error {
 	return &BadRequestError{text}
@@ -32,3 +34,20 @@ type ForbiddenError struct {
 func (e ForbiddenError) Error() string {
 	return e.s
 }
+
+// NewConflictError returns an error that formats as the given text and holds the current signature record
+func NewConflictError(text string, current *models.Signature) error {
+	return &ConflictError{s: text, Current: current}
+}
+
+// ConflictError is returned when the signature record was modified by another request since it was read
+type ConflictError struct {
+	s string
+	// Current is the signature record as currently stored, may be nil if it could not be loaded
+	Current *models.Signature
+}
+
+// Error is the to string method for an error
+func (e ConflictError) Error() string {
+	return e.s
+}
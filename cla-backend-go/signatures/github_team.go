@@ -0,0 +1,93 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/github"
+	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// GitHubTeamMembership resolves the membership of GitHub team approval list entries
+type GitHubTeamMembership interface {
+	IsMember(githubTeam, githubUsername string) (bool, error)
+}
+
+// githubTeamMembership resolves team membership through the EasyCLA GitHub App installed on the team organization
+type githubTeamMembership struct {
+	githubOrgRepo github_organizations.Repository
+}
+
+// NewGitHubTeamMembership creates a GitHub team membership resolver - the organization of the team must have the
+// EasyCLA GitHub App installed
+func NewGitHubTeamMembership(githubOrgRepo github_organizations.Repository) GitHubTeamMembership {
+	return githubTeamMembership{
+		githubOrgRepo: githubOrgRepo,
+	}
+}
+
+// IsMember returns true if the GitHub user is a member of the team, in the form organization/team-slug
+func (m githubTeamMembership) IsMember(githubTeam, githubUsername string) (bool, error) {
+	orgName, teamSlug := utils.SplitGitHubTeam(githubTeam)
+	if orgName == "" || teamSlug == "" {
+		return false, fmt.Errorf("invalid GitHub team: %s", githubTeam)
+	}
+
+	githubOrg, err := m.githubOrgRepo.GetGithubOrganization(orgName)
+	if err != nil {
+		return false, err
+	}
+	if githubOrg == nil || githubOrg.OrganizationInstallationID == 0 {
+		return false, fmt.Errorf("the EasyCLA GitHub App is not installed on the GitHub organization %s", orgName)
+	}
+
+	return github.IsTeamMember(githubOrg.OrganizationInstallationID, orgName, teamSlug, githubUsername)
+}
+
+// MatchGitHubTeams returns the GitHub team approval list entries the GitHub user is a member of - teams which can't
+// be resolved are logged and skipped
+func MatchGitHubTeams(membership GitHubTeamMembership, githubTeams []string, githubUsername string) []string {
+	var matched []string
+	if membership == nil || strings.TrimSpace(githubUsername) == "" {
+		return matched
+	}
+	for _, githubTeam := range githubTeams {
+		isMember, err := membership.IsMember(githubTeam, githubUsername)
+		if err != nil {
+			log.Warnf("unable to resolve the membership of GitHub team %s for %s, error: %+v", githubTeam, githubUsername, err)
+			continue
+		}
+		if isMember {
+			matched = append(matched, githubTeam)
+		}
+	}
+	return matched
+}
+
+// annotateGitHubTeamCoverage sets the GitHub teams of the CCLA approval list which cover each of the corporate
+// contributors
+func annotateGitHubTeamCoverage(membership GitHubTeamMembership, cclaSignature *models.Signature, contributors []*models.CorporateContributor) {
+	if cclaSignature == nil {
+		return
+	}
+	now := time.Now()
+	var githubTeams []string
+	for _, githubTeam := range cclaSignature.GithubTeamApprovalList {
+		if !IsApprovalListEntryExpired(cclaSignature, ApprovalListTypeGithubTeam, githubTeam, now) {
+			githubTeams = append(githubTeams, githubTeam)
+		}
+	}
+	if len(githubTeams) == 0 {
+		return
+	}
+	for _, contributor := range contributors {
+		contributor.GithubTeams = MatchGitHubTeams(membership, githubTeams, contributor.GithubID)
+	}
+}
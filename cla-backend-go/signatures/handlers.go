@@ -17,11 +17,11 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
-	"github.com/savaki/dynastore"
+	"github.com/gorilla/sessions"
 )
 
 // Configure setups handlers on api with service
-func Configure(api *operations.ClaAPI, service SignatureService, sessionStore *dynastore.Store, eventsService events.Service) { // nolint
+func Configure(api *operations.ClaAPI, service SignatureService, sessionStore sessions.Store, eventsService events.Service) { // nolint
 
 	api.SignaturesGetSignedICLADocumentHandler = signatures.GetSignedICLADocumentHandlerFunc(func(params signatures.GetSignedICLADocumentParams) middleware.Responder {
 		signatureModel, sigErr := service.GetIndividualSignature(params.ClaGroupID, params.UserID)
@@ -178,7 +178,7 @@ func Configure(api *operations.ClaAPI, service SignatureService, sessionStore *d
 			githubAccessToken = ""
 		}
 
-		ghApprovalList, err := service.AddGithubOrganizationToWhitelist(params.SignatureID, params.Body, githubAccessToken)
+		ghApprovalList, err := service.AddGithubOrganizationToWhitelist(params.SignatureID, params.Body, githubAccessToken, claUserActor(claUser))
 		if err != nil {
 			log.Warnf("error adding github organization %s using signature_id: %s to the whitelist, error: %+v",
 				*params.Body.OrganizationID, params.SignatureID, err)
@@ -225,7 +225,7 @@ func Configure(api *operations.ClaAPI, service SignatureService, sessionStore *d
 			githubAccessToken = ""
 		}
 
-		ghApprovalList, err := service.DeleteGithubOrganizationFromWhitelist(params.SignatureID, params.Body, githubAccessToken)
+		ghApprovalList, err := service.DeleteGithubOrganizationFromWhitelist(params.SignatureID, params.Body, githubAccessToken, claUserActor(claUser))
 		if err != nil {
 			log.Warnf("error deleting github organization %s using signature_id: %s from the whitelist, error: %+v",
 				*params.Body.OrganizationID, params.SignatureID, err)
@@ -371,3 +371,13 @@ func generateHTMLRedirectPage(downloadLink, claType string) string {
                             </body>
                         </html>`, claType, downloadLink, claType, downloadLink)
 }
+
+// claUserActor returns the user model of the CLA user, recorded as the actor of the approval list revisions
+func claUserActor(claUser *user.CLAUser) *models.User {
+	return &models.User{
+		UserID:     claUser.UserID,
+		Username:   claUser.Name,
+		LfUsername: claUser.LFUsername,
+		LfEmail:    claUser.LFEmail,
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -28,6 +29,7 @@ import (
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
@@ -48,6 +50,8 @@ const (
 	CCLA = "ccla"
 
 	HugePageSize = 10000
+	// ExportPageSize is the number of signatures loaded per query page when streaming the signature exports
+	ExportPageSize = 1000
 )
 
 // SignatureRepository interface defines the functions for the github whitelist service
@@ -70,6 +74,11 @@ type SignatureRepository interface {
 	GetUserSignatures(params signatures.GetUserSignaturesParams, pageSize int64) (*models.Signatures, error)
 	ProjectSignatures(projectID string) (*models.Signatures, error)
 	UpdateApprovalList(projectID, companyID string, params *models.ApprovalList) (*models.Signature, error)
+	GetSignaturesWithApprovalListExpirations() ([]*models.Signature, error)
+	UpdateApprovalListExpirations(signatureID string, recordVersion int64, expirations []*models.ApprovalListExpiration) (*models.Signature, error)
+	UpdateAutoApprovalRules(signatureID string, recordVersion int64, rules []*models.AutoApprovalRule) (*models.Signature, error)
+	RevokeSignature(signatureID string, revocation *models.SignatureRevocation) (*models.Signature, error)
+	TransferSignatureCompany(signatureID string, targetCompany *models.Company) (*models.Signature, error)
 
 	AddCLAManager(signatureID, claManagerID string) (*models.Signature, error)
 	RemoveCLAManager(signatureID, claManagerID string) (*models.Signature, error)
@@ -82,6 +91,9 @@ type SignatureRepository interface {
 
 	GetClaGroupICLASignatures(claGroupID string, searchTerm *string) (*models.IclaSignatures, error)
 	GetClaGroupCorporateContributors(claGroupID string, companyID *string, searchTerm *string) (*models.CorporateContributorList, error)
+	ForEachClaGroupICLASignature(claGroupID string, fn func(sig *models.IclaSignature) error) error
+	ForEachClaGroupCorporateContributor(claGroupID string, companyID *string, fn func(contributor *models.CorporateContributor) error) error
+	ForEachProjectSignatureVersion(projectID string, fn func(sig *models.Signature) error) error
 }
 
 // repository data model
@@ -163,6 +175,12 @@ func (repo repository) AddGithubOrganizationToWhitelist(signatureID, GithubOrgan
 		return nil, err
 	}
 
+	recordVersion, err := itemRecordVersion(result.Item)
+	if err != nil {
+		log.Warnf("Error decoding the record version of signatureID: %s, error: %v", signatureID, err)
+		return nil, err
+	}
+
 	itemFromMap, ok := result.Item["github_org_whitelist"]
 	if !ok {
 		log.Debugf("signatureID: %s is missing the 'github_org_whitelist' column - will add", signatureID)
@@ -207,15 +225,17 @@ func (repo repository) AddGithubOrganizationToWhitelist(signatureID, GithubOrgan
 				L: newList,
 			},
 		},
-		UpdateExpression: aws.String("SET #L = :l"),
+		UpdateExpression: aws.String("SET #L = :l, #RV = :nrv"),
 		ReturnValues:     &addReturnValues,
 	}
 
+	addRecordVersionCondition(input, recordVersion)
+
 	log.Warnf("updating database record using signatureID: %s with values: %v", signatureID, newList)
 	updatedValues, err := repo.dynamoDBClient.UpdateItem(input)
 	if err != nil {
 		log.Warnf("Error updating white list, error: %v", err)
-		return nil, err
+		return nil, repo.toConflictError(signatureID, err)
 	}
 
 	updatedItemFromMap, ok := updatedValues.Attributes["github_org_whitelist"]
@@ -248,6 +268,12 @@ func (repo repository) DeleteGithubOrganizationFromWhitelist(signatureID, Github
 		return nil, err
 	}
 
+	recordVersion, err := itemRecordVersion(result.Item)
+	if err != nil {
+		log.Warnf("error decoding the record version of signatureID: %s, error: %v", signatureID, err)
+		return nil, err
+	}
+
 	itemFromMap, ok := result.Item["github_org_whitelist"]
 	if !ok {
 		log.Warnf("unable to remove whitelist organization: %s for signature: %s - list is empty",
@@ -290,13 +316,15 @@ func (repo repository) DeleteGithubOrganizationFromWhitelist(signatureID, Github
 					S: aws.String(signatureID),
 				},
 			},
-			UpdateExpression: aws.String("SET #L = :l"),
+			UpdateExpression: aws.String("SET #L = :l, #RV = :nrv"),
 		}
 
+		addRecordVersionCondition(input, recordVersion)
+
 		_, err = repo.dynamoDBClient.UpdateItem(input)
 		if err != nil {
 			log.Warnf("error updating github org whitelist to NULL value, error: %v", err)
-			return nil, err
+			return nil, repo.toConflictError(signatureID, err)
 		}
 
 		// Return an empty list
@@ -322,14 +350,16 @@ func (repo repository) DeleteGithubOrganizationFromWhitelist(signatureID, Github
 				S: aws.String(signatureID),
 			},
 		},
-		UpdateExpression: aws.String("SET #L = :l"),
+		UpdateExpression: aws.String("SET #L = :l, #RV = :nrv"),
 		ReturnValues:     &updatedReturnValues,
 	}
 
+	addRecordVersionCondition(input, recordVersion)
+
 	updatedValues, err := repo.dynamoDBClient.UpdateItem(input)
 	if err != nil {
 		log.Warnf("Error updating github org whitelist, error: %v", err)
-		return nil, err
+		return nil, repo.toConflictError(signatureID, err)
 	}
 
 	updatedItemFromMap, ok := updatedValues.Attributes["github_org_whitelist"]
@@ -582,6 +612,16 @@ func (repo repository) GetCorporateSignature(claGroupID, companyID string) (*mod
 
 // GetSignatureACL returns the signature ACL for the specified signature id
 func (repo repository) GetSignatureACL(signatureID string) ([]string, error) {
+	dbModel, err := repo.getSignatureManagers(signatureID)
+	if err != nil || dbModel == nil {
+		return nil, err
+	}
+
+	return dbModel.SignatureACL, nil
+}
+
+// getSignatureManagers returns the signature ACL and the record version for the specified signature id
+func (repo repository) getSignatureManagers(signatureID string) (*DBManagersModel, error) {
 	// Use the nice builder to create the expression
 	expr, err := expression.NewBuilder().
 		WithProjection(buildSignatureACLProjection()).
@@ -623,7 +663,7 @@ func (repo repository) GetSignatureACL(signatureID string) ([]string, error) {
 		return nil, unmarshallErr
 	}
 
-	return dbModel.SignatureACL, nil
+	return &dbModel, nil
 }
 
 func addConditionToFilter(filter expression.ConditionBuilder, cond expression.ConditionBuilder, filterAdded *bool) expression.ConditionBuilder {
@@ -1453,18 +1493,21 @@ func (repo repository) GetUserSignatures(params signatures.GetUserSignaturesPara
 	}, nil
 }
 
+// AddCLAManager adds the specified manager to the signature ACL - returns a ConflictError if the signature ACL was
+// modified by another request in the meantime
 func (repo repository) AddCLAManager(signatureID, claManagerID string) (*models.Signature, error) {
-	aclEntries, err := repo.GetSignatureACL(signatureID)
+	managers, err := repo.getSignatureManagers(signatureID)
 	if err != nil {
 		log.Warnf("unable to fetch signature by ID: %s, error: %+v", signatureID, err)
 		return nil, err
 	}
 
-	if aclEntries == nil {
+	if managers == nil || managers.SignatureACL == nil {
 		log.Warnf("unable to fetch signature by ID: %s - record not found", signatureID)
 		return nil, nil
 	}
 
+	aclEntries := managers.SignatureACL
 	for _, manager := range aclEntries {
 		if claManagerID == manager {
 			return nil, errors.New("manager already in signature ACL")
@@ -1474,31 +1517,7 @@ func (repo repository) AddCLAManager(signatureID, claManagerID string) (*models.
 	aclEntries = append(aclEntries, claManagerID)
 	log.Debugf("To be updated acllist : %+v", aclEntries)
 
-	_, now := utils.CurrentTime()
-
-	input := &dynamodb.UpdateItemInput{
-		Key: map[string]*dynamodb.AttributeValue{
-			"signature_id": {
-				S: aws.String(signatureID),
-			},
-		},
-		ExpressionAttributeNames: map[string]*string{
-			"#A": aws.String("signature_acl"),
-			"#M": aws.String("date_modified"),
-		},
-		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-			":a": {
-				SS: aws.StringSlice(aclEntries),
-			},
-			":m": {
-				S: aws.String(now),
-			},
-		},
-		UpdateExpression: aws.String("SET #A = :a, #M = :m"),
-		TableName:        aws.String(fmt.Sprintf("cla-%s-signatures", repo.stage)),
-	}
-
-	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	updateErr := repo.updateSignatureACL(signatureID, aclEntries, managers.RecordVersion)
 	if updateErr != nil {
 		log.Warnf("add CLA manager - unable to update request with new ACL entry of '%s' for signature ID: %s, error: %v",
 			claManagerID, signatureID, updateErr)
@@ -1515,14 +1534,16 @@ func (repo repository) AddCLAManager(signatureID, claManagerID string) (*models.
 	return sigModel, nil
 }
 
+// RemoveCLAManager removes the specified manager from the signature ACL - returns a ConflictError if the signature ACL
+// was modified by another request in the meantime
 func (repo repository) RemoveCLAManager(signatureID, claManagerID string) (*models.Signature, error) {
-	aclEntries, err := repo.GetSignatureACL(signatureID)
+	managers, err := repo.getSignatureManagers(signatureID)
 	if err != nil {
 		log.Warnf("unable to fetch signature by ID: %s, error: %+v", signatureID, err)
 		return nil, err
 	}
 
-	if aclEntries == nil {
+	if managers == nil || managers.SignatureACL == nil {
 		log.Warnf("unable to fetch signature by ID: %s - record not found", signatureID)
 		return nil, nil
 	}
@@ -1530,7 +1551,7 @@ func (repo repository) RemoveCLAManager(signatureID, claManagerID string) (*mode
 	// A bit of logic to determine if the manager is listed and to build the new list without the specified manager
 	found := false
 	var updateEntries []string
-	for _, manager := range aclEntries {
+	for _, manager := range managers.SignatureACL {
 		if claManagerID == manager {
 			found = true
 		} else {
@@ -1542,6 +1563,26 @@ func (repo repository) RemoveCLAManager(signatureID, claManagerID string) (*mode
 		return nil, fmt.Errorf("manager ID: %s not found in signature ACL", claManagerID)
 	}
 
+	updateErr := repo.updateSignatureACL(signatureID, updateEntries, managers.RecordVersion)
+	if updateErr != nil {
+		log.Warnf("remove CLA manager - unable to remove ACL entry of '%s' for signature ID: %s, error: %v",
+			claManagerID, signatureID, updateErr)
+		return nil, updateErr
+	}
+
+	// Load the updated document and return it
+	sigModel, err := repo.GetSignature(signatureID)
+	if err != nil {
+		log.Warnf("unable to fetch signature by ID: %s - record not found", signatureID)
+		return nil, err
+	}
+
+	return sigModel, nil
+}
+
+// updateSignatureACL writes the signature ACL entries if the signature record version still matches the record
+// version read before the update
+func (repo repository) updateSignatureACL(signatureID string, aclEntries []string, recordVersion int64) error {
 	_, now := utils.CurrentTime()
 
 	input := &dynamodb.UpdateItemInput{
@@ -1556,31 +1597,23 @@ func (repo repository) RemoveCLAManager(signatureID, claManagerID string) (*mode
 		},
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
 			":a": {
-				SS: aws.StringSlice(updateEntries),
+				SS: aws.StringSlice(aclEntries),
 			},
 			":m": {
 				S: aws.String(now),
 			},
 		},
-		UpdateExpression: aws.String("SET #A = :a, #M = :m"),
+		UpdateExpression: aws.String("SET #A = :a, #M = :m, #RV = :nrv"),
 		TableName:        aws.String(fmt.Sprintf("cla-%s-signatures", repo.stage)),
 	}
+	addRecordVersionCondition(input, recordVersion)
 
 	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
 	if updateErr != nil {
-		log.Warnf("remove CLA manager - unable to remove ACL entry of '%s' for signature ID: %s, error: %v",
-			claManagerID, signatureID, updateErr)
-		return nil, updateErr
+		return repo.toConflictError(signatureID, updateErr)
 	}
 
-	// Load the updated document and return it
-	sigModel, err := repo.GetSignature(signatureID)
-	if err != nil {
-		log.Warnf("unable to fetch signature by ID: %s - record not found", signatureID)
-		return nil, err
-	}
-
-	return sigModel, nil
+	return nil
 }
 
 // UpdateApprovalList updates the specified project/company signature with the updated approval list information
@@ -1610,105 +1643,72 @@ func (repo repository) UpdateApprovalList(projectID, companyID string, params *m
 
 	// Just grab and use the first one - need to figure out conflict resolution if more than one
 	sig := sigs.Signatures[0]
+
+	// If the caller provided the record version the changes are based on, the signature must not have been modified since
+	if params.RecordVersion != nil && *params.RecordVersion != sig.RecordVersion {
+		msg := fmt.Sprintf("approval list for signature ID: %s was modified by another request - expected record version: %d, current record version: %d",
+			sig.SignatureID, *params.RecordVersion, sig.RecordVersion)
+		log.Warn(msg)
+		return nil, NewConflictError(msg, sig)
+	}
+
 	expressionAttributeNames := map[string]*string{}
 	expressionAttributeValues := map[string]*dynamodb.AttributeValue{}
-	haveAdditions := false
-	updateExpression := ""
-
-	// If we have an add or remove email list...we need to run an update for this column
-	if params.AddEmailApprovalList != nil || params.RemoveEmailApprovalList != nil {
-		columnName := "email_whitelist"
-		attrList := buildApprovalAttributeList(sig.EmailApprovalList, params.AddEmailApprovalList, params.RemoveEmailApprovalList)
-		// If no entries after consolidating all the updates, we need to remove the column
-		if attrList == nil || attrList.L == nil {
-			var rmColErr error
-			sig, rmColErr = repo.removeColumn(sig.SignatureID.String(), columnName)
-			if rmColErr != nil {
-				msg := fmt.Sprintf("unable to remove column %s for signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
-					columnName, companyID, projectID, signed, approved)
-				log.Warn(msg)
-				return nil, errors.New(msg)
-			}
-		} else {
-			haveAdditions = true
-			expressionAttributeNames["#E"] = aws.String("email_whitelist")
-			expressionAttributeValues[":e"] = attrList
-			updateExpression = updateExpression + " #E = :e, "
-		}
-	}
+	var setExpressions, removeExpressions []string
 
-	if params.AddDomainApprovalList != nil || params.RemoveDomainApprovalList != nil {
-		columnName := "domain_whitelist"
-		attrList := buildApprovalAttributeList(sig.DomainApprovalList, params.AddDomainApprovalList, params.RemoveDomainApprovalList)
-		// If no entries after consolidating all the updates, we need to remove the column
-		if attrList == nil || attrList.L == nil {
-			var rmColErr error
-			sig, rmColErr = repo.removeColumn(sig.SignatureID.String(), columnName)
-			if rmColErr != nil {
-				msg := fmt.Sprintf("unable to remove column %s for signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
-					columnName, companyID, projectID, signed, approved)
-				log.Warn(msg)
-				return nil, errors.New(msg)
-			}
-		} else {
-			haveAdditions = true
-			expressionAttributeNames["#D"] = aws.String(columnName)
-			expressionAttributeValues[":d"] = attrList
-			updateExpression = updateExpression + " #D = :d, "
+	// For each approval list with add or remove entries we either set the consolidated list or, if no entries remain
+	// after consolidating all the updates, remove the column
+	updateColumn := func(name, columnName string, existingList, addEntries, removeEntries []string) {
+		if addEntries == nil && removeEntries == nil {
+			return
 		}
-	}
-
-	if params.AddGithubUsernameApprovalList != nil || params.RemoveGithubUsernameApprovalList != nil {
-		columnName := "github_whitelist"
-		attrList := buildApprovalAttributeList(sig.GithubUsernameApprovalList, params.AddGithubUsernameApprovalList, params.RemoveGithubUsernameApprovalList)
-		// If no entries after consolidating all the updates, we need to remove the column
+		expressionAttributeNames["#"+name] = aws.String(columnName)
+		attrList := buildApprovalAttributeList(existingList, addEntries, removeEntries)
 		if attrList == nil || attrList.L == nil {
-			var rmColErr error
-			sig, rmColErr = repo.removeColumn(sig.SignatureID.String(), columnName)
-			if rmColErr != nil {
-				msg := fmt.Sprintf("unable to remove column %s for signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
-					columnName, companyID, projectID, signed, approved)
-				log.Warn(msg)
-				return nil, errors.New(msg)
-			}
+			removeExpressions = append(removeExpressions, "#"+name)
 		} else {
-			haveAdditions = true
-			expressionAttributeNames["#G"] = aws.String(columnName)
-			expressionAttributeValues[":g"] = attrList
-			updateExpression = updateExpression + " #G = :g, "
+			expressionAttributeValues[":"+strings.ToLower(name)] = attrList
+			setExpressions = append(setExpressions, fmt.Sprintf("#%s = :%s", name, strings.ToLower(name)))
 		}
 	}
-
-	if params.AddGithubOrgApprovalList != nil || params.RemoveGithubOrgApprovalList != nil {
-		columnName := "github_org_whitelist"
-		attrList := buildApprovalAttributeList(sig.GithubOrgApprovalList, params.AddGithubOrgApprovalList, params.RemoveGithubOrgApprovalList)
-		// If no entries after consolidating all the updates, we need to remove the column
-		if attrList == nil || attrList.L == nil {
-			var rmColErr error
-			sig, rmColErr = repo.removeColumn(sig.SignatureID.String(), columnName)
-			if rmColErr != nil {
-				msg := fmt.Sprintf("unable to remove column %s for signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
-					columnName, companyID, projectID, signed, approved)
-				log.Warn(msg)
-				return nil, errors.New(msg)
-			}
+	updateColumn("E", "email_whitelist", sig.EmailApprovalList, params.AddEmailApprovalList, params.RemoveEmailApprovalList)
+	updateColumn("D", "domain_whitelist", sig.DomainApprovalList, params.AddDomainApprovalList, params.RemoveDomainApprovalList)
+	updateColumn("G", "github_whitelist", sig.GithubUsernameApprovalList, params.AddGithubUsernameApprovalList, params.RemoveGithubUsernameApprovalList)
+	updateColumn("GO", "github_org_whitelist", sig.GithubOrgApprovalList, params.AddGithubOrgApprovalList, params.RemoveGithubOrgApprovalList)
+	updateColumn("GT", "github_team_whitelist", sig.GithubTeamApprovalList, params.AddGithubTeamApprovalList, params.RemoveGithubTeamApprovalList)
+
+	// Expirations follow the entries - removed entries lose their expiration, new expirations replace existing ones
+	expirations, expirationsChanged, expirationErr := mergeApprovalListExpirations(sig, params)
+	if expirationErr != nil {
+		return nil, expirationErr
+	}
+	if expirationsChanged {
+		expressionAttributeNames["#X"] = aws.String("approval_list_expirations")
+		attrList, marshalErr := buildApprovalListExpirationsAttribute(expirations)
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+		if attrList == nil {
+			removeExpressions = append(removeExpressions, "#X")
 		} else {
-			haveAdditions = true
-			expressionAttributeNames["#GO"] = aws.String("github_org_whitelist")
-			expressionAttributeValues[":go"] = attrList
-			updateExpression = updateExpression + " #GO = :go, "
+			expressionAttributeValues[":x"] = attrList
+			setExpressions = append(setExpressions, "#X = :x")
 		}
 	}
 
 	// Ensure at least one value is set for us to update
-	if !haveAdditions {
+	if len(setExpressions) == 0 && len(removeExpressions) == 0 {
 		log.Debugf("no updates required to any of the approved list values company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t - expecting at least something to update",
 			companyID, projectID, signed, approved)
 		return sig, nil
 	}
 
-	// Remove trailing comma from the expression, if present
-	updateExpression = utils.TrimRemoveTrailingComma("SET " + updateExpression)
+	// Every update increments the record version - the update fails if the record was updated since we read it
+	setExpressions = append(setExpressions, "#RV = :nrv")
+	updateExpression := "SET " + strings.Join(setExpressions, ", ")
+	if len(removeExpressions) > 0 {
+		updateExpression = updateExpression + " REMOVE " + strings.Join(removeExpressions, ", ")
+	}
 
 	// Update dynamoDB table
 	input := &dynamodb.UpdateItemInput{
@@ -1720,8 +1720,9 @@ func (repo repository) UpdateApprovalList(projectID, companyID string, params *m
 		},
 		ExpressionAttributeNames:  expressionAttributeNames,
 		ExpressionAttributeValues: expressionAttributeValues,
-		UpdateExpression:          aws.String(updateExpression), //aws.String("SET #L = :l"),
+		UpdateExpression:          aws.String(updateExpression),
 	}
+	addRecordVersionCondition(input, sig.RecordVersion)
 
 	log.Debugf("updating approval list for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
 		companyID, projectID, signed, approved)
@@ -1730,7 +1731,7 @@ func (repo repository) UpdateApprovalList(projectID, companyID string, params *m
 	if updateErr != nil {
 		log.Warnf("error updating approval lists for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t, error: %v",
 			companyID, projectID, signed, approved, updateErr)
-		return nil, updateErr
+		return nil, repo.toConflictError(sig.SignatureID.String(), updateErr)
 	}
 
 	log.Debugf("querying database for approval list details after update using company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
@@ -1757,6 +1758,216 @@ func (repo repository) UpdateApprovalList(projectID, companyID string, params *m
 	return updatedSig.Signatures[0], nil
 }
 
+// GetSignaturesWithApprovalListExpirations returns the signed and approved CCLA signatures which have one or more
+// time-bounded approval list entries
+func (repo repository) GetSignaturesWithApprovalListExpirations() ([]*models.Signature, error) {
+	filter := expression.Name("approval_list_expirations").AttributeExists().
+		And(expression.Name("signature_type").Equal(expression.Value(CCLA))).
+		And(expression.Name("signature_signed").Equal(expression.Value(true))).
+		And(expression.Name("signature_approved").Equal(expression.Value(true)))
+
+	expr, err := expression.NewBuilder().WithFilter(filter).WithProjection(buildProjection()).Build()
+	if err != nil {
+		log.Warnf("error building expression for approval list expiration signature scan, error: %v", err)
+		return nil, err
+	}
+
+	scanInput := &dynamodb.ScanInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		TableName:                 aws.String(repo.signatureTableName),
+	}
+
+	var dbSignatures []ItemSignature
+	for {
+		results, scanErr := repo.dynamoDBClient.Scan(scanInput)
+		if scanErr != nil {
+			log.Warnf("error scanning for signatures with approval list expirations, error: %v", scanErr)
+			return nil, scanErr
+		}
+
+		var page []ItemSignature
+		if err := dynamodbattribute.UnmarshalListOfMaps(results.Items, &page); err != nil {
+			log.Warnf("error unmarshalling signatures with approval list expirations, error: %v", err)
+			return nil, err
+		}
+		dbSignatures = append(dbSignatures, page...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return repo.buildSignatureModels(dbSignatures, LoadACLDetails), nil
+}
+
+// UpdateApprovalListExpirations replaces the approval list expirations of the specified signature - the update is
+// rejected with a ConflictError if the signature was modified since the specified record version
+func (repo repository) UpdateApprovalListExpirations(signatureID string, recordVersion int64, expirations []*models.ApprovalListExpiration) (*models.Signature, error) {
+	attrList, err := buildApprovalListExpirationsAttribute(expirations)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(repo.signatureTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"signature_id": {
+				S: aws.String(signatureID),
+			},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#X": aws.String("approval_list_expirations"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{},
+	}
+	if attrList == nil {
+		input.UpdateExpression = aws.String("SET #RV = :nrv REMOVE #X")
+	} else {
+		input.ExpressionAttributeValues[":x"] = attrList
+		input.UpdateExpression = aws.String("SET #X = :x, #RV = :nrv")
+	}
+	addRecordVersionCondition(input, recordVersion)
+
+	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	if updateErr != nil {
+		log.Warnf("error updating approval list expirations for signature ID: %s, error: %v", signatureID, updateErr)
+		return nil, repo.toConflictError(signatureID, updateErr)
+	}
+
+	return repo.GetSignature(signatureID)
+}
+
+// UpdateAutoApprovalRules replaces the auto-approval rules of the specified signature - the update is rejected with a
+// ConflictError if the signature was modified since the specified record version
+func (repo repository) UpdateAutoApprovalRules(signatureID string, recordVersion int64, rules []*models.AutoApprovalRule) (*models.Signature, error) {
+	attrList, err := buildAutoApprovalRulesAttribute(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(repo.signatureTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"signature_id": {
+				S: aws.String(signatureID),
+			},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#AR": aws.String("auto_approval_rules"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{},
+	}
+	if attrList == nil {
+		input.UpdateExpression = aws.String("SET #RV = :nrv REMOVE #AR")
+	} else {
+		input.ExpressionAttributeValues[":ar"] = attrList
+		input.UpdateExpression = aws.String("SET #AR = :ar, #RV = :nrv")
+	}
+	addRecordVersionCondition(input, recordVersion)
+
+	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	if updateErr != nil {
+		log.Warnf("error updating auto-approval rules for signature ID: %s, error: %v", signatureID, updateErr)
+		return nil, repo.toConflictError(signatureID, updateErr)
+	}
+
+	return repo.GetSignature(signatureID)
+}
+
+// RevokeSignature revokes the specified signature - the signature is no longer approved and records the revocation
+// details, the update is rejected with a ConflictError if the signature was already revoked. The sigtype_signed_approved_id
+// key is updated by the signature table stream handler.
+func (repo repository) RevokeSignature(signatureID string, revocation *models.SignatureRevocation) (*models.Signature, error) {
+	revocationAttribute, err := buildSignatureRevocationAttribute(revocation)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(repo.signatureTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"signature_id": {
+				S: aws.String(signatureID),
+			},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#A":  aws.String("signature_approved"),
+			"#SR": aws.String("signature_revocation"),
+			"#N":  aws.String("note"),
+			"#M":  aws.String("date_modified"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":a":  {BOOL: aws.Bool(false)},
+			":sr": revocationAttribute,
+			":n":  {S: aws.String(buildRevocationNote(revocation))},
+			":m":  {S: aws.String(revocation.RevokedOn)},
+		},
+		UpdateExpression:    aws.String("SET #A = :a, #SR = :sr, #N = :n, #M = :m"),
+		ConditionExpression: aws.String("attribute_exists(signature_id) AND attribute_not_exists(#SR)"),
+	}
+
+	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	if updateErr != nil {
+		aerr, ok := updateErr.(awserr.Error)
+		if !ok || aerr.Code() != dynamodb.ErrCodeConditionalCheckFailedException {
+			log.Warnf("error revoking signature ID: %s, error: %v", signatureID, updateErr)
+			return nil, updateErr
+		}
+		current, sigErr := repo.GetSignature(signatureID)
+		if sigErr != nil || current == nil {
+			// the signature does not exist
+			return nil, sigErr
+		}
+		return nil, NewConflictError(fmt.Sprintf("signature ID: %s is already revoked", signatureID), current)
+	}
+
+	return repo.GetSignature(signatureID)
+}
+
+// TransferSignatureCompany moves the CCLA or the employee acknowledgement to the target company, returns nil if the
+// signature does not exist
+func (repo repository) TransferSignatureCompany(signatureID string, targetCompany *models.Company) (*models.Signature, error) {
+	sig, err := repo.GetSignature(signatureID)
+	if err != nil || sig == nil {
+		return nil, err
+	}
+
+	_, now := utils.CurrentTime()
+	expressionAttributeNames := map[string]*string{}
+	expressionAttributeValues := map[string]*dynamodb.AttributeValue{}
+	var updates []string
+	i := 0
+	for name, value := range transferAttributeValues(sig, targetCompany, now) {
+		expressionAttributeNames[fmt.Sprintf("#A%d", i)] = aws.String(name)
+		expressionAttributeValues[fmt.Sprintf(":a%d", i)] = &dynamodb.AttributeValue{S: aws.String(value)}
+		updates = append(updates, fmt.Sprintf("#A%d = :a%d", i, i))
+		i++
+	}
+
+	_, updateErr := repo.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName: aws.String(repo.signatureTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"signature_id": {
+				S: aws.String(signatureID),
+			},
+		},
+		ExpressionAttributeNames:  expressionAttributeNames,
+		ExpressionAttributeValues: expressionAttributeValues,
+		UpdateExpression:          aws.String("SET " + strings.Join(updates, ", ")),
+		ConditionExpression:       aws.String("attribute_exists(signature_id)"),
+	})
+	if updateErr != nil {
+		log.Warnf("error transferring signature ID: %s to company ID: %s, error: %v", signatureID, targetCompany.CompanyID, updateErr)
+		return nil, updateErr
+	}
+
+	return repo.GetSignature(signatureID)
+}
+
 // removeColumn is a helper function to remove a given column when we need to zero out the column value - typically the approval list
 func (repo repository) removeColumn(signatureID, columnName string) (*models.Signature, error) {
 	log.Debugf("removing column %s from signature ID: %s", columnName, signatureID)
@@ -1907,8 +2118,6 @@ func (repo repository) AddSignedOn(signatureID string) error {
 
 // buildProjectSignatureModels converts the response model into a response data model
 func (repo repository) buildProjectSignatureModels(results *dynamodb.QueryOutput, projectID string, loadACLDetails bool) ([]*models.Signature, error) {
-	var sigs []*models.Signature
-
 	// The DB signature model
 	var dbSignatures []ItemSignature
 
@@ -1919,6 +2128,12 @@ func (repo repository) buildProjectSignatureModels(results *dynamodb.QueryOutput
 		return nil, err
 	}
 
+	return repo.buildSignatureModels(dbSignatures, loadACLDetails), nil
+}
+
+// buildSignatureModels converts the database models into response models, loading the company, user and ACL details
+func (repo repository) buildSignatureModels(dbSignatures []ItemSignature, loadACLDetails bool) []*models.Signature {
+	var sigs []*models.Signature
 	var wg sync.WaitGroup
 	wg.Add(len(dbSignatures))
 	for _, dbSignature := range dbSignatures {
@@ -1943,11 +2158,19 @@ func (repo repository) buildProjectSignatureModels(results *dynamodb.QueryOutput
 			DomainApprovalList:          dbSignature.DomainWhitelist,
 			GithubUsernameApprovalList:  dbSignature.GitHubWhitelist,
 			GithubOrgApprovalList:       dbSignature.GitHubOrgWhitelist,
+			GithubTeamApprovalList:      dbSignature.GitHubTeamWhitelist,
 			UserName:                    dbSignature.UserName,
 			UserLFID:                    dbSignature.UserLFUsername,
 			UserGHID:                    dbSignature.UserGithubUsername,
 			SignedOn:                    dbSignature.SignedOn,
 			SignatoryName:               dbSignature.SignatoryName,
+			RecordVersion:               dbSignature.RecordVersion,
+			ApprovalListExpirations:     buildApprovalListExpirationModels(dbSignature.ApprovalListExpirations),
+			AutoApprovalRules:           buildAutoApprovalRuleModels(dbSignature.AutoApprovalRules),
+			SignatureUserCompanyID:      dbSignature.SignatureUserCompanyID,
+			Revocation:                  buildSignatureRevocationModel(dbSignature.SignatureRevocation),
+			SignatureDocumentSha256:     dbSignature.SignatureDocumentSHA256,
+			SignatureDocumentLocale:     dbSignature.SignatureDocumentLocale,
 		}
 		sigs = append(sigs, sig)
 		go func(sigModel *models.Signature, signatureUserCompanyID string, sigACL []string) {
@@ -2021,7 +2244,7 @@ func (repo repository) buildProjectSignatureModels(results *dynamodb.QueryOutput
 		}(sig, dbSignature.SignatureUserCompanyID, dbSignature.SignatureACL)
 	}
 	wg.Wait()
-	return sigs, nil
+	return sigs
 }
 
 // buildResponse is a helper function which converts a database model to a GitHub organization response model
@@ -2062,12 +2285,39 @@ func buildProjection() expression.ProjectionBuilder {
 		expression.Name("domain_whitelist"),
 		expression.Name("github_whitelist"),
 		expression.Name("github_org_whitelist"),
+		expression.Name("github_team_whitelist"),
 		expression.Name("user_github_username"),
 		expression.Name("user_lf_username"),
 		expression.Name("user_name"),
 		expression.Name("user_email"),
 		expression.Name("signed_on"),
 		expression.Name("signatory_name"),
+		expression.Name("record_version"),
+		expression.Name("approval_list_expirations"),
+		expression.Name("auto_approval_rules"),
+		expression.Name("signature_revocation"),
+		expression.Name("signature_document_sha256"),
+		expression.Name("signature_document_locale"),
+	)
+}
+
+// buildSignatureVersionProjection is a helper function to build the projection of the signature type, reference and
+// document version attributes
+func buildSignatureVersionProjection() expression.ProjectionBuilder {
+	// These are the columns we want returned
+	return expression.NamesList(
+		expression.Name("signature_id"),
+		expression.Name("signature_approved"),
+		expression.Name("signature_signed"),
+		expression.Name("signature_document_major_version"),
+		expression.Name("signature_document_minor_version"),
+		expression.Name("signature_project_id"),
+		expression.Name("signature_reference_id"),
+		expression.Name("signature_reference_name"),
+		expression.Name("signature_reference_type"),
+		expression.Name("signature_type"),
+		expression.Name("signature_user_ccla_company_id"),
+		expression.Name("user_name"),
 	)
 }
 
@@ -2077,6 +2327,7 @@ func buildSignatureACLProjection() expression.ProjectionBuilder {
 	return expression.NamesList(
 		expression.Name("signature_id"),
 		expression.Name("signature_acl"),
+		expression.Name("record_version"),
 	)
 }
 
@@ -2138,8 +2389,6 @@ func buildApprovalAttributeList(existingList, addEntries, removeEntries []string
 
 // buildCompanyIDList is a helper function to convert the DB response models into a simple list of company IDs
 func (repo repository) buildCompanyIDList(results *dynamodb.QueryOutput) ([]SignatureCompanyID, error) {
-	var response []SignatureCompanyID
-
 	// The DB signature model
 	var dbSignatures []ItemSignature
 	err := dynamodbattribute.UnmarshalListOfMaps(results.Items, &dbSignatures)
@@ -2148,6 +2397,13 @@ func (repo repository) buildCompanyIDList(results *dynamodb.QueryOutput) ([]Sign
 		return nil, err
 	}
 
+	return repo.toSignatureCompanyIDs(dbSignatures), nil
+}
+
+// toSignatureCompanyIDs is a helper function to convert the DB models into a simple list of company IDs
+func (repo repository) toSignatureCompanyIDs(dbSignatures []ItemSignature) []SignatureCompanyID {
+	var response []SignatureCompanyID
+
 	// Loop and extract the company ID (signature_reference_id) value
 	for _, item := range dbSignatures {
 		// Lookup the company by ID - try to get more information like the external ID and name
@@ -2173,7 +2429,7 @@ func (repo repository) buildCompanyIDList(results *dynamodb.QueryOutput) ([]Sign
 		}
 	}
 
-	return response, nil
+	return response
 }
 
 func (repo repository) GetClaGroupICLASignatures(claGroupID string, searchTerm *string) (*models.IclaSignatures, error) {
@@ -2227,18 +2483,7 @@ func (repo repository) GetClaGroupICLASignatures(claGroupID string, searchTerm *
 					continue
 				}
 			}
-			signedOn := sig.DateCreated
-			if sig.SignedOn != "" {
-				signedOn = sig.SignedOn
-			}
-			out.List = append(out.List, &models.IclaSignature{
-				GithubUsername: sig.UserGithubUsername,
-				LfUsername:     sig.UserLFUsername,
-				SignatureID:    sig.SignatureID,
-				UserEmail:      sig.UserEmail,
-				UserName:       sig.UserName,
-				SignedOn:       signedOn,
-			})
+			out.List = append(out.List, toIclaSignature(sig))
 		}
 
 		if len(results.LastEvaluatedKey) == 0 {
@@ -2305,22 +2550,7 @@ func (repo repository) GetClaGroupCorporateContributors(claGroupID string, compa
 					continue
 				}
 			}
-			var sigCreatedTime = sig.DateCreated
-			t, err := utils.ParseDateTime(sig.DateCreated)
-			if err != nil {
-				log.Error("fillCorporateContributorModel: unable to parse time", err)
-			} else {
-				sigCreatedTime = utils.TimeToString(t)
-			}
-			signatureVersion := fmt.Sprintf("v%s.%s", sig.SignatureDocumentMajorVersion, sig.SignatureDocumentMinorVersion)
-			out.List = append(out.List, &models.CorporateContributor{
-				GithubID:          sig.UserGithubUsername,
-				LinuxFoundationID: sig.UserLFUsername,
-				Name:              sig.UserName,
-				SignatureVersion:  signatureVersion,
-				Email:             sig.UserEmail,
-				Timestamp:         sigCreatedTime,
-			})
+			out.List = append(out.List, toCorporateContributor(sig))
 		}
 
 		if len(results.LastEvaluatedKey) == 0 {
@@ -2335,3 +2565,235 @@ func (repo repository) GetClaGroupCorporateContributors(claGroupID string, compa
 
 	return out, nil
 }
+
+// ForEachClaGroupICLASignature calls fn with each ICLA signature of the CLA Group, the signatures are loaded one page
+// at a time so that exports don't hold every signature in memory. Iteration stops at the first error returned by fn.
+func (repo repository) ForEachClaGroupICLASignature(claGroupID string, fn func(sig *models.IclaSignature) error) error {
+	sortKeyPrefix := fmt.Sprintf("%s#%v#%v", ICLA, true, true)
+	condition := expression.Key("signature_project_id").Equal(expression.Value(claGroupID)).
+		And(expression.Key("sigtype_signed_approved_id").BeginsWith(sortKeyPrefix))
+	return repo.forEachClaGroupSignaturePage(claGroupID, condition, func(dbSignatures []ItemSignature) error {
+		for _, sig := range dbSignatures {
+			if err := fn(toIclaSignature(sig)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ForEachClaGroupCorporateContributor calls fn with each corporate contributor of the CLA Group, optionally limited
+// to the employees of the company. The contributors are loaded one page at a time, unlike
+// GetClaGroupCorporateContributors they are not sorted by name. Iteration stops at the first error returned by fn.
+func (repo repository) ForEachClaGroupCorporateContributor(claGroupID string, companyID *string, fn func(contributor *models.CorporateContributor) error) error {
+	condition := expression.Key("signature_project_id").Equal(expression.Value(claGroupID))
+	if companyID != nil {
+		sortKey := fmt.Sprintf("%s#%v#%v#%v", ECLA, true, true, *companyID)
+		condition = condition.And(expression.Key("sigtype_signed_approved_id").Equal(expression.Value(sortKey)))
+	} else {
+		sortKeyPrefix := fmt.Sprintf("%s#%v#%v", ECLA, true, true)
+		condition = condition.And(expression.Key("sigtype_signed_approved_id").BeginsWith(sortKeyPrefix))
+	}
+	return repo.forEachClaGroupSignaturePage(claGroupID, condition, func(dbSignatures []ItemSignature) error {
+		for _, sig := range dbSignatures {
+			if err := fn(toCorporateContributor(sig)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// forEachClaGroupSignaturePage queries the signatures matching the key condition on the CLA Group signature type
+// index and calls fn with each page of ExportPageSize signatures
+func (repo repository) forEachClaGroupSignaturePage(claGroupID string, condition expression.KeyConditionBuilder, fn func(dbSignatures []ItemSignature) error) error {
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).WithProjection(buildProjection()).Build()
+	if err != nil {
+		log.Warnf("error building expression for cla group signatures export, claGroupID: %s, error: %v",
+			claGroupID, err)
+		return err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ProjectionExpression:      expr.Projection(),
+		TableName:                 aws.String(repo.signatureTableName),
+		IndexName:                 aws.String(SignatureProjectIDSigTypeSignedApprovedIDIndex),
+		Limit:                     aws.Int64(ExportPageSize),
+	}
+	for {
+		results, queryErr := repo.dynamoDBClient.Query(queryInput)
+		if queryErr != nil {
+			log.Warnf("error retrieving signatures for cla group: %s, error: %v", claGroupID, queryErr)
+			return queryErr
+		}
+
+		var dbSignatures []ItemSignature
+		err := dynamodbattribute.UnmarshalListOfMaps(results.Items, &dbSignatures)
+		if err != nil {
+			log.Warnf("error unmarshalling signatures from database for cla group: %s, error: %v",
+				claGroupID, err)
+			return err
+		}
+		if err = fn(dbSignatures); err != nil {
+			return err
+		}
+
+		if len(results.LastEvaluatedKey) == 0 {
+			return nil
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+		log.Debug("querying next page")
+	}
+}
+
+// ForEachProjectSignatureVersion calls fn with each signed and approved signature of the project. Unlike
+// ProjectSignatures the signatures are loaded one page at a time with only the signature type, reference and document
+// version attributes - the user, company and ACL details are not loaded. Iteration stops at the first error returned
+// by fn.
+func (repo repository) ForEachProjectSignatureVersion(projectID string, fn func(sig *models.Signature) error) error {
+	condition := expression.Key("signature_project_id").Equal(expression.Value(projectID))
+	filter := expression.Name("signature_approved").Equal(expression.Value(true)).
+		And(expression.Name("signature_signed").Equal(expression.Value(true)))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).WithFilter(filter).WithProjection(buildSignatureVersionProjection()).Build()
+	if err != nil {
+		log.Warnf("error building expression for project signature versions query, projectID: %s, error: %v",
+			projectID, err)
+		return err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ProjectionExpression:      expr.Projection(),
+		FilterExpression:          expr.Filter(),
+		TableName:                 aws.String(repo.signatureTableName),
+		IndexName:                 aws.String(SignatureProjectIDIndex),
+		Limit:                     aws.Int64(ExportPageSize),
+	}
+	for {
+		results, queryErr := repo.dynamoDBClient.Query(queryInput)
+		if queryErr != nil {
+			log.Warnf("error retrieving signature versions for project: %s, error: %v", projectID, queryErr)
+			return queryErr
+		}
+
+		var dbSignatures []ItemSignature
+		err := dynamodbattribute.UnmarshalListOfMaps(results.Items, &dbSignatures)
+		if err != nil {
+			log.Warnf("error unmarshalling signature versions from database for project: %s, error: %v",
+				projectID, err)
+			return err
+		}
+		for _, sig := range dbSignatures {
+			if err = fn(toSignatureVersion(sig)); err != nil {
+				return err
+			}
+		}
+
+		if len(results.LastEvaluatedKey) == 0 {
+			return nil
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+		log.Debug("querying next page")
+	}
+}
+
+// toSignatureVersion converts the database model into a signature response model holding only the attributes of
+// buildSignatureVersionProjection
+func toSignatureVersion(sig ItemSignature) *models.Signature {
+	return &models.Signature{
+		SignatureID:            strfmt.UUID4(sig.SignatureID),
+		SignatureType:          sig.SignatureType,
+		SignatureReferenceID:   strfmt.UUID4(sig.SignatureReferenceID),
+		SignatureReferenceName: sig.SignatureReferenceName,
+		SignatureReferenceType: sig.SignatureReferenceType,
+		SignatureUserCompanyID: sig.SignatureUserCompanyID,
+		SignatureSigned:        sig.SignatureSigned,
+		SignatureApproved:      sig.SignatureApproved,
+		SignatureMajorVersion:  sig.SignatureDocumentMajorVersion,
+		SignatureMinorVersion:  sig.SignatureDocumentMinorVersion,
+		ProjectID:              sig.SignatureProjectID,
+		UserName:               sig.UserName,
+	}
+}
+
+// toIclaSignature converts the database model into an ICLA signature response model
+func toIclaSignature(sig ItemSignature) *models.IclaSignature {
+	signedOn := sig.DateCreated
+	if sig.SignedOn != "" {
+		signedOn = sig.SignedOn
+	}
+	return &models.IclaSignature{
+		GithubUsername: sig.UserGithubUsername,
+		LfUsername:     sig.UserLFUsername,
+		SignatureID:    sig.SignatureID,
+		UserEmail:      sig.UserEmail,
+		UserName:       sig.UserName,
+		SignedOn:       signedOn,
+	}
+}
+
+// toCorporateContributor converts the database model into a corporate contributor response model
+func toCorporateContributor(sig ItemSignature) *models.CorporateContributor {
+	var sigCreatedTime = sig.DateCreated
+	t, err := utils.ParseDateTime(sig.DateCreated)
+	if err != nil {
+		log.Error("fillCorporateContributorModel: unable to parse time", err)
+	} else {
+		sigCreatedTime = utils.TimeToString(t)
+	}
+	signatureVersion := fmt.Sprintf("v%s.%s", sig.SignatureDocumentMajorVersion, sig.SignatureDocumentMinorVersion)
+	return &models.CorporateContributor{
+		GithubID:          sig.UserGithubUsername,
+		LinuxFoundationID: sig.UserLFUsername,
+		Name:              sig.UserName,
+		SignatureVersion:  signatureVersion,
+		Email:             sig.UserEmail,
+		Timestamp:         sigCreatedTime,
+	}
+}
+
+// addRecordVersionCondition adds the record version condition to the update input - the update only succeeds if the
+// signature record version still matches the specified record version (records written before the record version was
+// introduced don't have the attribute). The update expression is expected to set #RV to :nrv.
+func addRecordVersionCondition(input *dynamodb.UpdateItemInput, recordVersion int64) {
+	input.ExpressionAttributeNames["#RV"] = aws.String("record_version")
+	input.ExpressionAttributeValues[":rv"] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(recordVersion, 10))}
+	input.ExpressionAttributeValues[":nrv"] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(recordVersion+1, 10))}
+	if recordVersion == 0 {
+		input.ConditionExpression = aws.String("attribute_not_exists(#RV) OR #RV = :rv")
+	} else {
+		input.ConditionExpression = aws.String("#RV = :rv")
+	}
+}
+
+// itemRecordVersion returns the record version of the signature item, zero for the records written before the record
+// version was introduced
+func itemRecordVersion(item map[string]*dynamodb.AttributeValue) (int64, error) {
+	var recordVersion int64
+	av, ok := item["record_version"]
+	if !ok || av.N == nil {
+		return 0, nil
+	}
+	err := dynamodbattribute.Unmarshal(av, &recordVersion)
+	return recordVersion, err
+}
+
+// toConflictError converts a failed record version condition into a ConflictError holding the current signature,
+// other errors are returned as is
+func (repo repository) toConflictError(signatureID string, err error) error {
+	aerr, ok := err.(awserr.Error)
+	if !ok || aerr.Code() != dynamodb.ErrCodeConditionalCheckFailedException {
+		return err
+	}
+
+	current, sigErr := repo.GetSignature(signatureID)
+	if sigErr != nil {
+		log.Warnf("unable to load the current signature record for signature ID: %s, error: %+v", signatureID, sigErr)
+	}
+	return NewConflictError(fmt.Sprintf("signature ID: %s was modified by another request", signatureID), current)
+}
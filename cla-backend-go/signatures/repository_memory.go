@@ -0,0 +1,835 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/restapi/operations/signatures"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// memoryRepository shares the response model builders with the DynamoDB repository and keeps the signatures in the
+// in-memory store
+type memoryRepository struct {
+	repository
+	store *storage.MemoryStore
+}
+
+// NewMemoryRepository creates a new instance of the signature repository backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string, companyRepo company.IRepository, usersRepo users.UserRepository) SignatureRepository {
+	return memoryRepository{
+		repository: repository{
+			stage:              stage,
+			companyRepo:        companyRepo,
+			usersRepo:          usersRepo,
+			signatureTableName: fmt.Sprintf("cla-%s-signatures", stage),
+		},
+		store: store,
+	}
+}
+
+// scan returns the signatures matching the filter ordered by signature ID
+func (repo memoryRepository) scan(filter func(sig *ItemSignature) bool) ([]ItemSignature, error) {
+	var dbSignatures []ItemSignature
+	if err := repo.store.Scan(repo.signatureTableName, &dbSignatures); err != nil {
+		log.Warnf("error retrieving signatures, error: %v", err)
+		return nil, err
+	}
+	var matched []ItemSignature
+	for i := range dbSignatures {
+		if filter(&dbSignatures[i]) {
+			matched = append(matched, dbSignatures[i])
+		}
+	}
+	return matched, nil
+}
+
+// page returns the signatures following the next key (a signature ID) along with the key of the last returned record
+// if more records are available
+func page(dbSignatures []ItemSignature, nextKey *string, pageSize int64) ([]ItemSignature, string) {
+	start := 0
+	if nextKey != nil && *nextKey != "" {
+		start = sort.Search(len(dbSignatures), func(i int) bool {
+			return dbSignatures[i].SignatureID > *nextKey
+		})
+	}
+	dbSignatures = dbSignatures[start:]
+	if pageSize <= 0 || int64(len(dbSignatures)) <= pageSize {
+		return dbSignatures, ""
+	}
+	dbSignatures = dbSignatures[0:pageSize]
+	return dbSignatures, dbSignatures[pageSize-1].SignatureID
+}
+
+// setAttributes sets (or removes, when the value is nil) the specified attributes of the signature
+func (repo memoryRepository) setAttributes(signatureID string, attributes map[string]*dynamodb.AttributeValue) error {
+	return repo.store.UpdateItem(repo.signatureTableName, signatureID, func(item map[string]*dynamodb.AttributeValue) error {
+		for name, value := range attributes {
+			if value == nil {
+				delete(item, name)
+			} else {
+				item[name] = value
+			}
+		}
+		return nil
+	})
+}
+
+// setVersionedAttributes sets the signature attributes and increments the record version if the record version still
+// matches the specified record version, otherwise a ConflictError holding the current signature is returned
+func (repo memoryRepository) setVersionedAttributes(signatureID string, recordVersion int64, attributes map[string]*dynamodb.AttributeValue) error {
+	conflict := false
+	err := repo.store.UpdateItem(repo.signatureTableName, signatureID, func(item map[string]*dynamodb.AttributeValue) error {
+		var currentVersion int64
+		if av, ok := item["record_version"]; ok && av.N != nil {
+			var err error
+			if currentVersion, err = strconv.ParseInt(*av.N, 10, 64); err != nil {
+				return err
+			}
+		}
+		if currentVersion != recordVersion {
+			conflict = true
+			return nil
+		}
+		for name, value := range attributes {
+			if value == nil {
+				delete(item, name)
+			} else {
+				item[name] = value
+			}
+		}
+		item["record_version"] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(recordVersion+1, 10))}
+		return nil
+	})
+	if err != nil || !conflict {
+		return err
+	}
+
+	current, sigErr := repo.GetSignature(signatureID)
+	if sigErr != nil {
+		log.Warnf("unable to load the current signature record for signature ID: %s, error: %+v", signatureID, sigErr)
+	}
+	return NewConflictError(fmt.Sprintf("signature ID: %s was modified by another request", signatureID), current)
+}
+
+// GetGithubOrganizationsFromWhitelist returns a list of GH organizations stored in the whitelist
+func (repo memoryRepository) GetGithubOrganizationsFromWhitelist(signatureID string) ([]models.GithubOrg, error) {
+	var dbSignature ItemSignature
+	found, err := repo.store.Get(repo.signatureTableName, signatureID, &dbSignature)
+	if err != nil {
+		log.Warnf("Error retrieving GH organization whitelist for signatureID: %s, error: %v", signatureID, err)
+		return nil, err
+	}
+	if !found || dbSignature.GitHubOrgWhitelist == nil {
+		return nil, nil
+	}
+
+	orgs := toGithubOrgs(dbSignature.GitHubOrgWhitelist)
+	sort.Slice(orgs, func(i, j int) bool {
+		return *orgs[i].ID < *orgs[j].ID
+	})
+	return orgs, nil
+}
+
+// AddGithubOrganizationToWhitelist adds the specified GH organization to the whitelist
+func (repo memoryRepository) AddGithubOrganizationToWhitelist(signatureID, GithubOrganizationID string) ([]models.GithubOrg, error) {
+	var dbSignature ItemSignature
+	if _, err := repo.store.Get(repo.signatureTableName, signatureID, &dbSignature); err != nil {
+		log.Warnf("Error retrieving GH organization whitelist for signatureID: %s and GH Org: %s, error: %v",
+			signatureID, GithubOrganizationID, err)
+		return nil, err
+	}
+	if utils.StringInSlice(GithubOrganizationID, dbSignature.GitHubOrgWhitelist) {
+		return toGithubOrgs(dbSignature.GitHubOrgWhitelist), nil
+	}
+
+	orgList := append(dbSignature.GitHubOrgWhitelist, GithubOrganizationID)
+	err := repo.setVersionedAttributes(signatureID, dbSignature.RecordVersion, map[string]*dynamodb.AttributeValue{
+		"github_org_whitelist": buildApprovalAttributeList(orgList, nil, nil),
+	})
+	if err != nil {
+		log.Warnf("Error updating white list, error: %v", err)
+		return nil, err
+	}
+	return toGithubOrgs(orgList), nil
+}
+
+// DeleteGithubOrganizationFromWhitelist removes the specified GH organization from the whitelist
+func (repo memoryRepository) DeleteGithubOrganizationFromWhitelist(signatureID, GithubOrganizationID string) ([]models.GithubOrg, error) {
+	var dbSignature ItemSignature
+	if _, err := repo.store.Get(repo.signatureTableName, signatureID, &dbSignature); err != nil {
+		log.Warnf("error retrieving GH organization whitelist for signatureID: %s and GH Org: %s, error: %v",
+			signatureID, GithubOrganizationID, err)
+		return nil, err
+	}
+	if dbSignature.GitHubOrgWhitelist == nil {
+		log.Warnf("unable to remove whitelist organization: %s for signature: %s - list is empty",
+			GithubOrganizationID, signatureID)
+		return nil, errors.New("no github_org_whitelist column")
+	}
+
+	orgList := utils.RemoveItemsFromList(dbSignature.GitHubOrgWhitelist, []string{GithubOrganizationID})
+	attrList := buildApprovalAttributeList(orgList, nil, nil)
+	if len(orgList) == 0 {
+		attrList = &dynamodb.AttributeValue{NULL: aws.Bool(true)}
+	}
+	err := repo.setVersionedAttributes(signatureID, dbSignature.RecordVersion, map[string]*dynamodb.AttributeValue{
+		"github_org_whitelist": attrList,
+	})
+	if err != nil {
+		log.Warnf("Error updating github org whitelist, error: %v", err)
+		return nil, err
+	}
+	if len(orgList) == 0 {
+		return []models.GithubOrg{}, nil
+	}
+	return toGithubOrgs(orgList), nil
+}
+
+// InvalidateProjectRecord invalidates the specified project record by setting the signature_approved flag to false
+func (repo memoryRepository) InvalidateProjectRecord(signatureID string, projectName string) error {
+	note := fmt.Sprintf("Signature invalidated (approved set to false) due to CLA Group/Project: %s deletion", projectName)
+	err := repo.setAttributes(signatureID, map[string]*dynamodb.AttributeValue{
+		"signature_approved": {BOOL: aws.Bool(false)},
+		"note":               {S: aws.String(note)},
+	})
+	if err != nil {
+		log.Warnf("error updating signature_approved for signature_id : %s error : %v ", signatureID, err)
+		return err
+	}
+	return nil
+}
+
+// GetSignature returns the signature for the specified signature id
+func (repo memoryRepository) GetSignature(signatureID string) (*models.Signature, error) {
+	var dbSignature ItemSignature
+	found, err := repo.store.Get(repo.signatureTableName, signatureID, &dbSignature)
+	if err != nil {
+		log.Warnf("error retrieving signature ID: %s, error: %v", signatureID, err)
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return repo.buildSignatureModels([]ItemSignature{dbSignature}, LoadACLDetails)[0], nil
+}
+
+// GetIndividualSignature returns the signature record for the specified CLA Group and User
+func (repo memoryRepository) GetIndividualSignature(claGroupID, userID string) (*models.Signature, error) {
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		return sig.SignatureProjectID == claGroupID && sig.SignatureReferenceID == userID &&
+			sig.SignatureType == "cla" && sig.SignatureReferenceType == "user" &&
+			sig.SignatureApproved && sig.SignatureSigned && sig.SignatureUserCompanyID == ""
+	})
+	if err != nil || len(dbSignatures) == 0 {
+		return nil, err
+	}
+	return repo.buildSignatureModels(dbSignatures[0:1], LoadACLDetails)[0], nil
+}
+
+// GetCorporateSignature returns the signature record for the specified CLA Group and Company ID
+func (repo memoryRepository) GetCorporateSignature(claGroupID, companyID string) (*models.Signature, error) {
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		return sig.SignatureProjectID == claGroupID && sig.SignatureReferenceID == companyID &&
+			sig.SignatureType == "ccla" && sig.SignatureReferenceType == "company" &&
+			sig.SignatureApproved && sig.SignatureSigned && sig.SignatureUserCompanyID == ""
+	})
+	if err != nil || len(dbSignatures) == 0 {
+		return nil, err
+	}
+	return repo.buildSignatureModels(dbSignatures[0:1], LoadACLDetails)[0], nil
+}
+
+// GetSignatureACL returns the signature ACL for the specified signature id
+func (repo memoryRepository) GetSignatureACL(signatureID string) ([]string, error) {
+	var dbModel DBManagersModel
+	found, err := repo.store.Get(repo.signatureTableName, signatureID, &dbModel)
+	if err != nil {
+		log.Warnf("error retrieving signature ID: %s, error: %v", signatureID, err)
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return dbModel.SignatureACL, nil
+}
+
+// GetProjectSignatures returns a list of signatures for the specified project
+func (repo memoryRepository) GetProjectSignatures(params signatures.GetProjectSignaturesParams, pageSize int64) (*models.Signatures, error) {
+	var searchTerm string
+	if params.SearchTerm != nil {
+		searchTerm = strings.ToLower(*params.SearchTerm)
+	}
+	fullMatch := params.FullMatch != nil && *params.FullMatch
+
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		switch {
+		case sig.SignatureProjectID != params.ProjectID || !sig.SignatureApproved || !sig.SignatureSigned:
+			return false
+		case params.SearchField != nil && sig.SignatureReferenceType != *params.SearchField:
+			return false
+		case params.SignatureType != nil && sig.SignatureType != strings.ToLower(*params.SignatureType):
+			return false
+		case params.SignatureType != nil && *params.SignatureType == "ccla" && (sig.SignatureReferenceID == "" || sig.SignatureUserCompanyID != ""):
+			return false
+		case params.SearchTerm != nil && fullMatch && sig.SignatureReferenceNameLower != searchTerm:
+			return false
+		case params.SearchTerm != nil && !fullMatch && !strings.Contains(sig.SignatureReferenceNameLower, searchTerm):
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount := int64(len(dbSignatures))
+	dbSignatures, lastEvaluatedKey := page(dbSignatures, params.NextKey, pageSize)
+	return &models.Signatures{
+		ProjectID:      params.ProjectID,
+		ResultCount:    int64(len(dbSignatures)),
+		TotalCount:     totalCount,
+		LastKeyScanned: lastEvaluatedKey,
+		Signatures:     repo.buildSignatureModels(dbSignatures, LoadACLDetails),
+	}, nil
+}
+
+// GetProjectCompanySignature returns a the signature for the specified project and specified company with the other query flags
+func (repo memoryRepository) GetProjectCompanySignature(companyID, projectID string, signed, approved *bool, nextKey *string, pageSize *int64) (*models.Signature, error) {
+	sigs, getErr := repo.GetProjectCompanySignatures(companyID, projectID, signed, approved, nextKey, pageSize)
+	if getErr != nil {
+		return nil, getErr
+	}
+	if sigs == nil || len(sigs.Signatures) == 0 {
+		return nil, nil
+	}
+	return sigs.Signatures[0], nil
+}
+
+// GetProjectCompanySignatures returns a list of signatures for the specified project and specified company
+func (repo memoryRepository) GetProjectCompanySignatures(companyID, projectID string, signed, approved *bool, nextKey *string, pageSize *int64) (*models.Signatures, error) {
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		return sig.SignatureProjectID == projectID && sig.SignatureReferenceID == companyID &&
+			sig.SignatureType == "ccla" && sig.SignatureReferenceType == "company" &&
+			(signed == nil || sig.SignatureSigned == *signed) &&
+			(approved == nil || sig.SignatureApproved == *approved)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int64(10)
+	if pageSize != nil {
+		limit = *pageSize
+	}
+	totalCount := int64(len(dbSignatures))
+	dbSignatures, lastEvaluatedKey := page(dbSignatures, nextKey, limit)
+
+	// Mirror the DynamoDB repository which returns a nil list when nothing matches
+	var sigs []*models.Signature
+	if len(dbSignatures) > 0 {
+		sigs = repo.buildSignatureModels(dbSignatures, LoadACLDetails)
+	}
+	return &models.Signatures{
+		ProjectID:      projectID,
+		ResultCount:    int64(len(sigs)),
+		TotalCount:     totalCount,
+		LastKeyScanned: lastEvaluatedKey,
+		Signatures:     sigs,
+	}, nil
+}
+
+// GetProjectCompanyEmployeeSignatures returns a list of employee signatures for the specified project and specified company
+func (repo memoryRepository) GetProjectCompanyEmployeeSignatures(params signatures.GetProjectCompanyEmployeeSignaturesParams, pageSize int64) (*models.Signatures, error) {
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		return sig.SignatureUserCompanyID == params.CompanyID && sig.SignatureProjectID == params.ProjectID &&
+			sig.SignatureApproved && sig.SignatureSigned
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount := int64(len(dbSignatures))
+	dbSignatures, lastEvaluatedKey := page(dbSignatures, params.NextKey, pageSize)
+	return &models.Signatures{
+		ProjectID:      params.ProjectID,
+		ResultCount:    int64(len(dbSignatures)),
+		TotalCount:     totalCount,
+		LastKeyScanned: lastEvaluatedKey,
+		Signatures:     repo.buildSignatureModels(dbSignatures, LoadACLDetails),
+	}, nil
+}
+
+// GetCompanySignatures returns a list of company signatures for the specified company
+func (repo memoryRepository) GetCompanySignatures(params signatures.GetCompanySignaturesParams, pageSize int64, loadACL bool) (*models.Signatures, error) {
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		return sig.SignatureReferenceID == params.CompanyID && sig.SignatureApproved && sig.SignatureSigned &&
+			(params.SignatureType == nil || sig.SignatureType == *params.SignatureType)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount := int64(len(dbSignatures))
+	dbSignatures, lastEvaluatedKey := page(dbSignatures, params.NextKey, pageSize)
+	return &models.Signatures{
+		ProjectID:      "",
+		ResultCount:    int64(len(dbSignatures)),
+		TotalCount:     totalCount,
+		LastKeyScanned: lastEvaluatedKey,
+		Signatures:     repo.buildSignatureModels(dbSignatures, loadACL),
+	}, nil
+}
+
+// GetCompanyIDsWithSignedCorporateSignatures returns a list of company IDs that have signed a CLA agreement
+func (repo memoryRepository) GetCompanyIDsWithSignedCorporateSignatures(claGroupID string) ([]SignatureCompanyID, error) {
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		return sig.SignatureProjectID == claGroupID && sig.SignatureType == "ccla" &&
+			sig.SignatureReferenceType == "company" && sig.SignatureSigned && sig.SignatureApproved
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repo.toSignatureCompanyIDs(dbSignatures), nil
+}
+
+// GetUserSignatures returns a list of user signatures for the specified user
+func (repo memoryRepository) GetUserSignatures(params signatures.GetUserSignaturesParams, pageSize int64) (*models.Signatures, error) {
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		return sig.SignatureReferenceID == params.UserID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount := int64(len(dbSignatures))
+	dbSignatures, lastEvaluatedKey := page(dbSignatures, params.NextKey, pageSize)
+	return &models.Signatures{
+		ProjectID:      "",
+		ResultCount:    int64(len(dbSignatures)),
+		TotalCount:     totalCount,
+		LastKeyScanned: lastEvaluatedKey,
+		Signatures:     repo.buildSignatureModels(dbSignatures, LoadACLDetails),
+	}, nil
+}
+
+// ProjectSignatures returns the project signatures with no pagination
+func (repo memoryRepository) ProjectSignatures(projectID string) (*models.Signatures, error) {
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		return sig.SignatureProjectID == projectID && sig.SignatureApproved && sig.SignatureSigned
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.Signatures{
+		ProjectID:  projectID,
+		Signatures: repo.buildSignatureModels(dbSignatures, LoadACLDetails),
+	}, nil
+}
+
+// UpdateApprovalList updates the specified project/company signature with the updated approval list information
+func (repo memoryRepository) UpdateApprovalList(projectID, companyID string, params *models.ApprovalList) (*models.Signature, error) {
+	signed, approved := true, true
+	sig, err := repo.GetProjectCompanySignature(companyID, projectID, &signed, &approved, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if sig == nil {
+		msg := fmt.Sprintf("unable to locate signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
+			companyID, projectID, signed, approved)
+		log.Warn(msg)
+		return nil, errors.New(msg)
+	}
+
+	attributes := map[string]*dynamodb.AttributeValue{}
+	addColumn := func(columnName string, existingList, addEntries, removeEntries []string) {
+		if addEntries == nil && removeEntries == nil {
+			return
+		}
+		attrList := buildApprovalAttributeList(existingList, addEntries, removeEntries)
+		if attrList == nil || attrList.L == nil {
+			// No entries after consolidating all the updates - remove the column
+			attributes[columnName] = nil
+		} else {
+			attributes[columnName] = attrList
+		}
+	}
+	addColumn("email_whitelist", sig.EmailApprovalList, params.AddEmailApprovalList, params.RemoveEmailApprovalList)
+	addColumn("domain_whitelist", sig.DomainApprovalList, params.AddDomainApprovalList, params.RemoveDomainApprovalList)
+	addColumn("github_whitelist", sig.GithubUsernameApprovalList, params.AddGithubUsernameApprovalList, params.RemoveGithubUsernameApprovalList)
+	addColumn("github_org_whitelist", sig.GithubOrgApprovalList, params.AddGithubOrgApprovalList, params.RemoveGithubOrgApprovalList)
+	addColumn("github_team_whitelist", sig.GithubTeamApprovalList, params.AddGithubTeamApprovalList, params.RemoveGithubTeamApprovalList)
+
+	expirations, expirationsChanged, err := mergeApprovalListExpirations(sig, params)
+	if err != nil {
+		return nil, err
+	}
+	if expirationsChanged {
+		attrList, marshalErr := buildApprovalListExpirationsAttribute(expirations)
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+		attributes["approval_list_expirations"] = attrList
+	}
+
+	if len(attributes) == 0 {
+		return sig, nil
+	}
+
+	// If the caller provided the record version the changes are based on, the signature must not have been modified since
+	recordVersion := sig.RecordVersion
+	if params.RecordVersion != nil {
+		recordVersion = *params.RecordVersion
+	}
+	if err := repo.setVersionedAttributes(sig.SignatureID.String(), recordVersion, attributes); err != nil {
+		log.Warnf("error updating approval lists for company ID: %s project ID: %s, error: %v",
+			companyID, projectID, err)
+		return nil, err
+	}
+	return repo.GetSignature(sig.SignatureID.String())
+}
+
+// GetSignaturesWithApprovalListExpirations returns the signed and approved CCLA signatures which have one or more
+// time-bounded approval list entries
+func (repo memoryRepository) GetSignaturesWithApprovalListExpirations() ([]*models.Signature, error) {
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		return sig.SignatureType == CCLA && sig.SignatureSigned && sig.SignatureApproved && len(sig.ApprovalListExpirations) > 0
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repo.buildSignatureModels(dbSignatures, LoadACLDetails), nil
+}
+
+// UpdateApprovalListExpirations replaces the approval list expirations of the specified signature
+func (repo memoryRepository) UpdateApprovalListExpirations(signatureID string, recordVersion int64, expirations []*models.ApprovalListExpiration) (*models.Signature, error) {
+	attrList, err := buildApprovalListExpirationsAttribute(expirations)
+	if err != nil {
+		return nil, err
+	}
+	err = repo.setVersionedAttributes(signatureID, recordVersion, map[string]*dynamodb.AttributeValue{
+		"approval_list_expirations": attrList,
+	})
+	if err != nil {
+		log.Warnf("error updating approval list expirations for signature ID: %s, error: %v", signatureID, err)
+		return nil, err
+	}
+	return repo.GetSignature(signatureID)
+}
+
+// UpdateAutoApprovalRules replaces the auto-approval rules of the specified signature
+func (repo memoryRepository) UpdateAutoApprovalRules(signatureID string, recordVersion int64, rules []*models.AutoApprovalRule) (*models.Signature, error) {
+	attrList, err := buildAutoApprovalRulesAttribute(rules)
+	if err != nil {
+		return nil, err
+	}
+	err = repo.setVersionedAttributes(signatureID, recordVersion, map[string]*dynamodb.AttributeValue{
+		"auto_approval_rules": attrList,
+	})
+	if err != nil {
+		log.Warnf("error updating auto-approval rules for signature ID: %s, error: %v", signatureID, err)
+		return nil, err
+	}
+	return repo.GetSignature(signatureID)
+}
+
+// RevokeSignature revokes the specified signature, the sigtype_signed_approved_id key is updated as the signature table
+// stream handler would
+func (repo memoryRepository) RevokeSignature(signatureID string, revocation *models.SignatureRevocation) (*models.Signature, error) {
+	revocationAttribute, err := buildSignatureRevocationAttribute(revocation)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked := false
+	err = repo.store.UpdateItem(repo.signatureTableName, signatureID, func(item map[string]*dynamodb.AttributeValue) error {
+		if _, ok := item["signature_revocation"]; ok {
+			revoked = true
+			return nil
+		}
+		item["signature_approved"] = &dynamodb.AttributeValue{BOOL: aws.Bool(false)}
+		item["signature_revocation"] = revocationAttribute
+		item["note"] = &dynamodb.AttributeValue{S: aws.String(buildRevocationNote(revocation))}
+		item["date_modified"] = &dynamodb.AttributeValue{S: aws.String(revocation.RevokedOn)}
+		if av, ok := item["sigtype_signed_approved_id"]; ok && av.S != nil {
+			item["sigtype_signed_approved_id"] = &dynamodb.AttributeValue{S: aws.String(revokedSigTypeSignedApprovedID(*av.S))}
+		}
+		return nil
+	})
+	if err == storage.ErrItemNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		log.Warnf("error revoking signature ID: %s, error: %v", signatureID, err)
+		return nil, err
+	}
+	if revoked {
+		current, sigErr := repo.GetSignature(signatureID)
+		if sigErr != nil {
+			log.Warnf("unable to load the current signature record for signature ID: %s, error: %+v", signatureID, sigErr)
+		}
+		return nil, NewConflictError(fmt.Sprintf("signature ID: %s is already revoked", signatureID), current)
+	}
+	return repo.GetSignature(signatureID)
+}
+
+// TransferSignatureCompany moves the CCLA or the employee acknowledgement to the target company, returns nil if the
+// signature does not exist
+func (repo memoryRepository) TransferSignatureCompany(signatureID string, targetCompany *models.Company) (*models.Signature, error) {
+	sig, err := repo.GetSignature(signatureID)
+	if err != nil || sig == nil {
+		return nil, err
+	}
+
+	_, now := utils.CurrentTime()
+	attributes := map[string]*dynamodb.AttributeValue{}
+	for name, value := range transferAttributeValues(sig, targetCompany, now) {
+		attributes[name] = &dynamodb.AttributeValue{S: aws.String(value)}
+	}
+	if err := repo.setAttributes(signatureID, attributes); err != nil {
+		log.Warnf("error transferring signature ID: %s to company ID: %s, error: %v", signatureID, targetCompany.CompanyID, err)
+		return nil, err
+	}
+	return repo.GetSignature(signatureID)
+}
+
+// AddCLAManager adds the specified manager to the signature ACL
+func (repo memoryRepository) AddCLAManager(signatureID, claManagerID string) (*models.Signature, error) {
+	var managers DBManagersModel
+	found, err := repo.store.Get(repo.signatureTableName, signatureID, &managers)
+	if err != nil {
+		return nil, err
+	}
+	aclEntries := managers.SignatureACL
+	if !found || aclEntries == nil {
+		log.Warnf("unable to fetch signature by ID: %s - record not found", signatureID)
+		return nil, nil
+	}
+	if utils.StringInSlice(claManagerID, aclEntries) {
+		return nil, errors.New("manager already in signature ACL")
+	}
+
+	_, now := utils.CurrentTime()
+	err = repo.setVersionedAttributes(signatureID, managers.RecordVersion, map[string]*dynamodb.AttributeValue{
+		"signature_acl": {SS: aws.StringSlice(append(aclEntries, claManagerID))},
+		"date_modified": {S: aws.String(now)},
+	})
+	if err != nil {
+		log.Warnf("add CLA manager - unable to update request with new ACL entry of '%s' for signature ID: %s, error: %v",
+			claManagerID, signatureID, err)
+		return nil, err
+	}
+	return repo.GetSignature(signatureID)
+}
+
+// RemoveCLAManager removes the specified manager from the signature ACL
+func (repo memoryRepository) RemoveCLAManager(signatureID, claManagerID string) (*models.Signature, error) {
+	var managers DBManagersModel
+	found, err := repo.store.Get(repo.signatureTableName, signatureID, &managers)
+	if err != nil {
+		return nil, err
+	}
+	aclEntries := managers.SignatureACL
+	if !found || aclEntries == nil {
+		log.Warnf("unable to fetch signature by ID: %s - record not found", signatureID)
+		return nil, nil
+	}
+	if !utils.StringInSlice(claManagerID, aclEntries) {
+		return nil, fmt.Errorf("manager ID: %s not found in signature ACL", claManagerID)
+	}
+
+	_, now := utils.CurrentTime()
+	err = repo.setVersionedAttributes(signatureID, managers.RecordVersion, map[string]*dynamodb.AttributeValue{
+		"signature_acl": {SS: aws.StringSlice(utils.RemoveItemsFromList(aclEntries, []string{claManagerID}))},
+		"date_modified": {S: aws.String(now)},
+	})
+	if err != nil {
+		log.Warnf("remove CLA manager - unable to remove ACL entry of '%s' for signature ID: %s, error: %v",
+			claManagerID, signatureID, err)
+		return nil, err
+	}
+	return repo.GetSignature(signatureID)
+}
+
+// removeColumn is a helper function to remove a given column when we need to zero out the column value
+func (repo memoryRepository) removeColumn(signatureID, columnName string) (*models.Signature, error) {
+	if err := repo.setAttributes(signatureID, map[string]*dynamodb.AttributeValue{columnName: nil}); err != nil {
+		log.Warnf("error removing approval lists column %s for signature ID: %s, error: %v", columnName, signatureID, err)
+		return nil, err
+	}
+	return repo.GetSignature(signatureID)
+}
+
+// AddSigTypeSignedApprovedID sets the sigtype_signed_approved_id value of the signature
+func (repo memoryRepository) AddSigTypeSignedApprovedID(signatureID string, val string) error {
+	return repo.setAttributes(signatureID, map[string]*dynamodb.AttributeValue{
+		"sigtype_signed_approved_id": {S: aws.String(val)},
+	})
+}
+
+// AddUsersDetails copies the user details into the signature
+func (repo memoryRepository) AddUsersDetails(signatureID string, userID string) error {
+	userModel, err := repo.usersRepo.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	if userModel == nil {
+		return fmt.Errorf("invalid user id : %s for signature : %s", userID, signatureID)
+	}
+	email := userModel.LfEmail
+	if email == "" && len(userModel.Emails) > 0 {
+		email = userModel.Emails[0]
+	}
+
+	attributes := map[string]*dynamodb.AttributeValue{}
+	for name, value := range map[string]string{
+		"user_github_username": userModel.GithubUsername,
+		"user_lf_username":     userModel.LfUsername,
+		"user_name":            userModel.Username,
+		"user_email":           email,
+	} {
+		if value != "" {
+			attributes[name] = &dynamodb.AttributeValue{S: aws.String(value)}
+		}
+	}
+	if len(attributes) == 0 {
+		// nothing to update
+		return nil
+	}
+	return repo.setAttributes(signatureID, attributes)
+}
+
+// AddSignedOn sets the signed_on value of the signature to the current time
+func (repo memoryRepository) AddSignedOn(signatureID string) error {
+	_, currentTime := utils.CurrentTime()
+	return repo.setAttributes(signatureID, map[string]*dynamodb.AttributeValue{
+		"signed_on": {S: aws.String(currentTime)},
+	})
+}
+
+// GetClaGroupICLASignatures returns the ICLA signatures of the CLA Group
+func (repo memoryRepository) GetClaGroupICLASignatures(claGroupID string, searchTerm *string) (*models.IclaSignatures, error) {
+	sortKeyPrefix := fmt.Sprintf("%s#%v#%v", ICLA, true, true)
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		return sig.SignatureProjectID == claGroupID && strings.HasPrefix(sig.SigtypeSignedApprovedID, sortKeyPrefix) &&
+			(searchTerm == nil || strings.Contains(sig.SignatureReferenceNameLower, strings.ToLower(*searchTerm)))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &models.IclaSignatures{List: make([]*models.IclaSignature, 0)}
+	for _, sig := range dbSignatures {
+		out.List = append(out.List, toIclaSignature(sig))
+	}
+	return out, nil
+}
+
+// GetClaGroupCorporateContributors returns the corporate contributors of the CLA Group
+func (repo memoryRepository) GetClaGroupCorporateContributors(claGroupID string, companyID *string, searchTerm *string) (*models.CorporateContributorList, error) {
+	sortKeyPrefix := fmt.Sprintf("%s#%v#%v", ECLA, true, true)
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		if sig.SignatureProjectID != claGroupID {
+			return false
+		}
+		if companyID != nil && sig.SigtypeSignedApprovedID != fmt.Sprintf("%s#%s", sortKeyPrefix, *companyID) {
+			return false
+		}
+		return strings.HasPrefix(sig.SigtypeSignedApprovedID, sortKeyPrefix) &&
+			(searchTerm == nil || strings.Contains(sig.SignatureReferenceNameLower, strings.ToLower(*searchTerm)))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &models.CorporateContributorList{List: make([]*models.CorporateContributor, 0)}
+	for _, sig := range dbSignatures {
+		out.List = append(out.List, toCorporateContributor(sig))
+	}
+	sort.Slice(out.List, func(i, j int) bool {
+		return out.List[i].Name < out.List[j].Name
+	})
+	return out, nil
+}
+
+// ForEachClaGroupICLASignature calls fn with each ICLA signature of the CLA Group
+func (repo memoryRepository) ForEachClaGroupICLASignature(claGroupID string, fn func(sig *models.IclaSignature) error) error {
+	sortKeyPrefix := fmt.Sprintf("%s#%v#%v", ICLA, true, true)
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		return sig.SignatureProjectID == claGroupID && strings.HasPrefix(sig.SigtypeSignedApprovedID, sortKeyPrefix)
+	})
+	if err != nil {
+		return err
+	}
+	for _, sig := range dbSignatures {
+		if err = fn(toIclaSignature(sig)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForEachClaGroupCorporateContributor calls fn with each corporate contributor of the CLA Group
+func (repo memoryRepository) ForEachClaGroupCorporateContributor(claGroupID string, companyID *string, fn func(contributor *models.CorporateContributor) error) error {
+	sortKeyPrefix := fmt.Sprintf("%s#%v#%v", ECLA, true, true)
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		if sig.SignatureProjectID != claGroupID {
+			return false
+		}
+		if companyID != nil && sig.SigtypeSignedApprovedID != fmt.Sprintf("%s#%s", sortKeyPrefix, *companyID) {
+			return false
+		}
+		return strings.HasPrefix(sig.SigtypeSignedApprovedID, sortKeyPrefix)
+	})
+	if err != nil {
+		return err
+	}
+	for _, sig := range dbSignatures {
+		if err = fn(toCorporateContributor(sig)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForEachProjectSignatureVersion calls fn with each signed and approved signature of the project
+func (repo memoryRepository) ForEachProjectSignatureVersion(projectID string, fn func(sig *models.Signature) error) error {
+	dbSignatures, err := repo.scan(func(sig *ItemSignature) bool {
+		return sig.SignatureProjectID == projectID && sig.SignatureApproved && sig.SignatureSigned
+	})
+	if err != nil {
+		return err
+	}
+	for _, sig := range dbSignatures {
+		if err = fn(toSignatureVersion(sig)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toGithubOrgs converts the list of organization IDs into a GitHub organization response model
+func toGithubOrgs(orgIDs []string) []models.GithubOrg {
+	var orgs []models.GithubOrg
+	for _, orgID := range orgIDs {
+		selected := true
+		orgs = append(orgs, models.GithubOrg{
+			ID:       aws.String(orgID),
+			Selected: &selected,
+		})
+	}
+	return orgs
+}
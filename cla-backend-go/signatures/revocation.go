@@ -0,0 +1,116 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// Signature revocation reason codes
+const (
+	RevocationReasonEmploymentEnded = "employmentEnded"
+	RevocationReasonSignedInError   = "signedInError"
+	RevocationReasonLegalRequest    = "legalRequest"
+)
+
+// RevocationReasonResignDeadlinePassed is the reason code of the previous version signatures revoked by a re-sign
+// campaign, it is not accepted from the API
+const RevocationReasonResignDeadlinePassed = "resignDeadlinePassed"
+
+// RevocationReasonCompanyMerged is the reason code of the signatures consolidated into the signatures of the target
+// company of a company merge, it is not accepted from the API
+const RevocationReasonCompanyMerged = "companyMerged"
+
+// maxRevocationCommentLength is the maximum length of the revocation comment
+const maxRevocationCommentLength = 1024
+
+// revocationReasonDescriptions maps the revocation reason codes to the descriptions used in the notes, events and emails
+var revocationReasonDescriptions = map[string]string{
+	RevocationReasonEmploymentEnded: "employment ended",
+	RevocationReasonSignedInError:   "signed in error",
+	RevocationReasonLegalRequest:    "legal request",
+}
+
+// RevocationReasonDescription returns the human readable description of the revocation reason code
+func RevocationReasonDescription(reason string) string {
+	if description, ok := revocationReasonDescriptions[reason]; ok {
+		return description
+	}
+	switch reason {
+	case RevocationReasonResignDeadlinePassed:
+		return "the new document version was not signed before the re-sign deadline"
+	case RevocationReasonCompanyMerged:
+		return "the company was merged into another company"
+	}
+	return reason
+}
+
+// ValidateRevocation validates the revocation reason code and comment, returns a BadRequestError if invalid
+func ValidateRevocation(revocation *models.SignatureRevocation) error {
+	if revocation == nil {
+		return NewBadRequestError("missing signature revocation")
+	}
+	reason := utils.StringValue(revocation.Reason)
+	if _, ok := revocationReasonDescriptions[reason]; !ok {
+		return NewBadRequestError(fmt.Sprintf("invalid signature revocation reason: %s - expecting one of: %s, %s, %s",
+			reason, RevocationReasonEmploymentEnded, RevocationReasonSignedInError, RevocationReasonLegalRequest))
+	}
+	if len(revocation.Comment) > maxRevocationCommentLength {
+		return NewBadRequestError(fmt.Sprintf("signature revocation comment exceeds %d characters", maxRevocationCommentLength))
+	}
+	return nil
+}
+
+// RevocableByCompanyManager returns true if the CLA Managers of the company may revoke the signature, they may only
+// revoke the employee acknowledgements of their company - the CCLA itself is revoked by the project managers
+func RevocableByCompanyManager(sig *models.Signature) bool {
+	return sig.SignatureType != CCLA && sig.SignatureUserCompanyID != ""
+}
+
+// buildSignatureRevocationModel converts the database revocation into the response model
+func buildSignatureRevocationModel(dbRevocation *DBSignatureRevocation) *models.SignatureRevocation {
+	if dbRevocation == nil {
+		return nil
+	}
+	reason := dbRevocation.Reason
+	return &models.SignatureRevocation{
+		Reason:    &reason,
+		Comment:   dbRevocation.Comment,
+		RevokedOn: dbRevocation.RevokedOn,
+		RevokedBy: dbRevocation.RevokedBy,
+	}
+}
+
+// buildSignatureRevocationAttribute converts the revocation into a dynamodb attribute
+func buildSignatureRevocationAttribute(revocation *models.SignatureRevocation) (*dynamodb.AttributeValue, error) {
+	return dynamodbattribute.Marshal(DBSignatureRevocation{
+		Reason:    utils.StringValue(revocation.Reason),
+		Comment:   revocation.Comment,
+		RevokedOn: revocation.RevokedOn,
+		RevokedBy: revocation.RevokedBy,
+	})
+}
+
+// buildRevocationNote returns the signature note recording the revocation
+func buildRevocationNote(revocation *models.SignatureRevocation) string {
+	return fmt.Sprintf("Signature revoked (approved set to false) by %s on %s - reason: %s",
+		revocation.RevokedBy, revocation.RevokedOn, RevocationReasonDescription(utils.StringValue(revocation.Reason)))
+}
+
+// revokedSigTypeSignedApprovedID returns the sigtype_signed_approved_id value with the approved segment cleared, the
+// value is returned as is if it does not have the expected type#signed#approved#id format
+func revokedSigTypeSignedApprovedID(val string) string {
+	parts := strings.Split(val, "#")
+	if len(parts) != 4 {
+		return val
+	}
+	parts[2] = "false"
+	return strings.Join(parts, "#")
+}
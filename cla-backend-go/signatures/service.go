@@ -7,10 +7,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list_revisions"
 	"github.com/communitybridge/easycla/cla-backend-go/events"
 
 	"github.com/communitybridge/easycla/cla-backend-go/users"
@@ -43,15 +45,19 @@ type SignatureService interface {
 	InvalidateProjectRecords(projectID string, projectName string) (int, error)
 
 	GetGithubOrganizationsFromWhitelist(signatureID string, githubAccessToken string) ([]models.GithubOrg, error)
-	AddGithubOrganizationToWhitelist(signatureID string, whiteListParams models.GhOrgWhitelist, githubAccessToken string) ([]models.GithubOrg, error)
-	DeleteGithubOrganizationFromWhitelist(signatureID string, whiteListParams models.GhOrgWhitelist, githubAccessToken string) ([]models.GithubOrg, error)
+	AddGithubOrganizationToWhitelist(signatureID string, whiteListParams models.GhOrgWhitelist, githubAccessToken string, actor *models.User) ([]models.GithubOrg, error)
+	DeleteGithubOrganizationFromWhitelist(signatureID string, whiteListParams models.GhOrgWhitelist, githubAccessToken string, actor *models.User) ([]models.GithubOrg, error)
 	UpdateApprovalList(authUser *auth.User, projectModel *models.Project, companyModel *models.Company, claGroupID string, params *models.ApprovalList) (*models.Signature, error)
+	UpdateAutoApprovalRules(authUser *auth.User, projectModel *models.Project, companyModel *models.Company, claGroupID string, rules []*models.AutoApprovalRule, recordVersion *int64) (*models.Signature, error)
+	RevokeSignature(authUser *auth.User, projectModel *models.Project, signatureID string, revocation *models.SignatureRevocation) (*models.Signature, error)
 
 	AddCLAManager(signatureID, claManagerID string) (*models.Signature, error)
 	RemoveCLAManager(signatureID, claManagerID string) (*models.Signature, error)
 
 	GetClaGroupICLASignatures(claGroupID string, searchTerm *string) (*models.IclaSignatures, error)
 	GetClaGroupCorporateContributors(claGroupID string, companyID *string, searchTerm *string) (*models.CorporateContributorList, error)
+	ForEachClaGroupICLASignature(claGroupID string, fn func(sig *models.IclaSignature) error) error
+	ForEachClaGroupCorporateContributor(claGroupID string, companyID *string, fn func(contributor *models.CorporateContributor) error) error
 }
 
 type service struct {
@@ -59,17 +65,21 @@ type service struct {
 	companyService      company.IService
 	usersService        users.Service
 	eventsService       events.Service
+	revisionsService    approval_list_revisions.Service
 	githubOrgValidation bool
+	teamMembership      GitHubTeamMembership
 }
 
 // NewService creates a new whitelist service
-func NewService(repo SignatureRepository, companyService company.IService, usersService users.Service, eventsService events.Service, githubOrgValidation bool) SignatureService {
+func NewService(repo SignatureRepository, companyService company.IService, usersService users.Service, eventsService events.Service, revisionsService approval_list_revisions.Service, githubOrgValidation bool, teamMembership GitHubTeamMembership) SignatureService {
 	return service{
 		repo,
 		companyService,
 		usersService,
 		eventsService,
+		revisionsService,
 		githubOrgValidation,
+		teamMembership,
 	}
 }
 
@@ -240,8 +250,9 @@ func (s service) GetGithubOrganizationsFromWhitelist(signatureID string, githubA
 	return orgIds, nil
 }
 
-// AddGithubOrganizationToWhitelist adds the GH organization to the whitelist
-func (s service) AddGithubOrganizationToWhitelist(signatureID string, whiteListParams models.GhOrgWhitelist, githubAccessToken string) ([]models.GithubOrg, error) {
+// AddGithubOrganizationToWhitelist adds the GH organization to the whitelist, the change is recorded in the approval
+// list revisions as made by the actor
+func (s service) AddGithubOrganizationToWhitelist(signatureID string, whiteListParams models.GhOrgWhitelist, githubAccessToken string, actor *models.User) ([]models.GithubOrg, error) {
 	organizationID := whiteListParams.OrganizationID
 
 	if signatureID == "" {
@@ -300,18 +311,24 @@ func (s service) AddGithubOrganizationToWhitelist(signatureID string, whiteListP
 		}
 	}
 
+	previous, err := s.repo.GetSignature(signatureID)
+	if err != nil {
+		return nil, err
+	}
 	gitHubWhiteList, err := s.repo.AddGithubOrganizationToWhitelist(signatureID, *organizationID)
 	if err != nil {
 		log.Warnf("issue adding github organization to white list using signatureID: %s, gh org id: %s, error: %v",
 			signatureID, *organizationID, err)
 		return nil, err
 	}
+	s.recordSignatureRevision(previous, actor)
 
 	return gitHubWhiteList, nil
 }
 
-// DeleteGithubOrganizationFromWhitelist deletes the specified GH organization from the whitelist
-func (s service) DeleteGithubOrganizationFromWhitelist(signatureID string, whiteListParams models.GhOrgWhitelist, githubAccessToken string) ([]models.GithubOrg, error) {
+// DeleteGithubOrganizationFromWhitelist deletes the specified GH organization from the whitelist, the change is recorded
+// in the approval list revisions as made by the actor
+func (s service) DeleteGithubOrganizationFromWhitelist(signatureID string, whiteListParams models.GhOrgWhitelist, githubAccessToken string, actor *models.User) ([]models.GithubOrg, error) {
 
 	// Extract the payload values
 	organizationID := whiteListParams.OrganizationID
@@ -372,16 +389,27 @@ func (s service) DeleteGithubOrganizationFromWhitelist(signatureID string, white
 		}
 	}
 
+	previous, err := s.repo.GetSignature(signatureID)
+	if err != nil {
+		return nil, err
+	}
 	gitHubWhiteList, err := s.repo.DeleteGithubOrganizationFromWhitelist(signatureID, *organizationID)
 	if err != nil {
 		return nil, err
 	}
+	s.recordSignatureRevision(previous, actor)
 
 	return gitHubWhiteList, nil
 }
 
 // UpdateApprovalList service method
 func (s service) UpdateApprovalList(authUser *auth.User, projectModel *models.Project, companyModel *models.Company, claGroupID string, params *models.ApprovalList) (*models.Signature, error) {
+	// Domain entries may be exact domains or *. wildcard patterns - reject anything else before it is stored
+	if msg, valid := normalizeDomainApprovalList(params); !valid {
+		log.Warn(msg)
+		return nil, NewBadRequestError(msg)
+	}
+
 	pageSize := int64(1)
 	signed, approved := true, true
 	sigModel, sigErr := s.GetProjectCompanySignature(companyModel.CompanyID, claGroupID, &signed, &approved, nil, &pageSize)
@@ -398,9 +426,9 @@ func (s service) UpdateApprovalList(authUser *auth.User, projectModel *models.Pr
 		return nil, NewBadRequestError(msg)
 	}
 
-	// Ensure current user is in the Signature ACL
+	// Ensure current user is in the Signature ACL - admins may update any approval list
 	claManagers := sigModel.SignatureACL
-	if !utils.CurrentUserInACL(authUser, claManagers) {
+	if !utils.IsUserAdmin(authUser) && !utils.CurrentUserInACL(authUser, claManagers) {
 		msg := fmt.Sprintf("EasyCLA - 403 Forbidden - CLA Manager %s / %s is not authorized to approve request for company ID: %s / %s / %s, project ID: %s / %s / %s",
 			authUser.UserName, authUser.Email,
 			companyModel.CompanyName, companyModel.CompanyExternalID, companyModel.CompanyID,
@@ -413,12 +441,19 @@ func (s service) UpdateApprovalList(authUser *auth.User, projectModel *models.Pr
 	if userErr != nil {
 		return nil, userErr
 	}
+	if userModel == nil {
+		// admins may not have a user record, the events record their username
+		userModel = &models.User{LfUsername: authUser.UserName, LfEmail: authUser.Email}
+	}
 
 	updatedSig, err := s.repo.UpdateApprovalList(projectModel.ProjectID, companyModel.CompanyID, params)
 	if err != nil {
 		return updatedSig, err
 	}
 
+	// Record the change in the approval list revision history
+	s.recordRevision(sigModel, updatedSig, userModel)
+
 	// Log Events
 	s.createEventLogEntries(companyModel, projectModel, userModel, params)
 
@@ -434,6 +469,176 @@ func (s service) UpdateApprovalList(authUser *auth.User, projectModel *models.Pr
 	return updatedSig, nil
 }
 
+// recordRevision records the change between the previous and the updated signature in the approval list revision
+// history - the approval list was updated when the record version moved on. Services created without a revisions
+// service don't record revisions.
+func (s service) recordRevision(previous, updated *models.Signature, actor *models.User) {
+	if s.revisionsService == nil || previous == nil || updated == nil || updated.RecordVersion <= previous.RecordVersion {
+		return
+	}
+	if _, revisionErr := s.revisionsService.CreateRevision(previous, updated, actor); revisionErr != nil {
+		log.Warnf("unable to record the approval list revision for signature ID: %s, error: %+v", updated.SignatureID, revisionErr)
+	}
+}
+
+// recordSignatureRevision loads the updated signature and records the change made since the previous signature
+func (s service) recordSignatureRevision(previous *models.Signature, actor *models.User) {
+	if s.revisionsService == nil || previous == nil {
+		return
+	}
+	updated, err := s.repo.GetSignature(previous.SignatureID.String())
+	if err != nil {
+		log.Warnf("unable to load the updated signature ID: %s, error: %+v", previous.SignatureID, err)
+		return
+	}
+	s.recordRevision(previous, updated, actor)
+}
+
+// UpdateAutoApprovalRules replaces the CCLA approval list request auto-approval rules of the company signature - only
+// the CLA Managers of the signature and the admins may change the rules
+func (s service) UpdateAutoApprovalRules(authUser *auth.User, projectModel *models.Project, companyModel *models.Company, claGroupID string, rules []*models.AutoApprovalRule, recordVersion *int64) (*models.Signature, error) {
+	normalizedRules, err := NormalizeAutoApprovalRules(rules)
+	if err != nil {
+		log.Warn(err.Error())
+		return nil, err
+	}
+
+	pageSize := int64(1)
+	signed, approved := true, true
+	sigModel, sigErr := s.GetProjectCompanySignature(companyModel.CompanyID, claGroupID, &signed, &approved, nil, &pageSize)
+	if sigErr != nil {
+		msg := fmt.Sprintf("unable to locate project company signature by Company ID: %s, CLA Group ID: %s, error: %+v",
+			companyModel.CompanyID, claGroupID, sigErr)
+		log.Warn(msg)
+		return nil, NewBadRequestError(msg)
+	}
+	if sigModel == nil {
+		msg := fmt.Sprintf("unable to locate signature for company ID: %s CLA Group ID: %s, type: ccla, signed: %t, approved: %t",
+			companyModel.CompanyID, claGroupID, signed, approved)
+		log.Warn(msg)
+		return nil, NewBadRequestError(msg)
+	}
+
+	if !utils.IsUserAdmin(authUser) && !utils.CurrentUserInACL(authUser, sigModel.SignatureACL) {
+		msg := fmt.Sprintf("EasyCLA - 403 Forbidden - CLA Manager %s / %s is not authorized to update the auto-approval rules for company ID: %s / %s / %s, project ID: %s / %s / %s",
+			authUser.UserName, authUser.Email,
+			companyModel.CompanyName, companyModel.CompanyExternalID, companyModel.CompanyID,
+			projectModel.ProjectName, projectModel.ProjectExternalID, projectModel.ProjectID)
+		return nil, NewForbiddenError(msg)
+	}
+
+	// Without a record version from the caller, the rules replace whatever is currently stored
+	currentVersion := sigModel.RecordVersion
+	if recordVersion != nil {
+		currentVersion = *recordVersion
+	}
+	updatedSig, err := s.repo.UpdateAutoApprovalRules(sigModel.SignatureID.String(), currentVersion, normalizedRules)
+	if err != nil {
+		return nil, err
+	}
+
+	userModel, userErr := s.usersService.GetUserByUserName(authUser.UserName, true)
+	if userErr != nil || userModel == nil {
+		log.Warnf("unable to lookup user by username: %s to log the auto-approval rules update, error: %+v", authUser.UserName, userErr)
+		return updatedSig, nil
+	}
+
+	var ruleDescriptions []string
+	for _, rule := range normalizedRules {
+		ruleDescriptions = append(ruleDescriptions, fmt.Sprintf("%s: %s", utils.StringValue(rule.RuleType), strings.Join(rule.Values, ", ")))
+	}
+	s.eventsService.LogEvent(&events.LogEventArgs{
+		EventType:         events.CCLAApprovalListRequestAutoApprovalRulesUpdated,
+		ProjectID:         projectModel.ProjectID,
+		ProjectModel:      projectModel,
+		CompanyID:         companyModel.CompanyID,
+		CompanyModel:      companyModel,
+		LfUsername:        userModel.LfUsername,
+		UserID:            userModel.UserID,
+		UserModel:         userModel,
+		ExternalProjectID: projectModel.ProjectExternalID,
+		EventData:         &events.CCLAApprovalListRequestAutoApprovalRulesUpdatedEventData{Rules: ruleDescriptions},
+	})
+
+	return updatedSig, nil
+}
+
+// RevokeSignature revokes the ICLA, CCLA or employee acknowledgement signature - the revoked signature no longer covers
+// any contribution but stays in the signature history. The signer and the CLA Managers are notified by email.
+func (s service) RevokeSignature(authUser *auth.User, projectModel *models.Project, signatureID string, revocation *models.SignatureRevocation) (*models.Signature, error) {
+	if err := ValidateRevocation(revocation); err != nil {
+		log.Warn(err.Error())
+		return nil, err
+	}
+
+	_, currentTime := utils.CurrentTime()
+	revocation.RevokedOn = currentTime
+	revocation.RevokedBy = authUser.UserName
+	revokedSig, err := s.repo.RevokeSignature(signatureID, revocation)
+	if err != nil || revokedSig == nil {
+		return nil, err
+	}
+
+	var companyModel *models.Company
+	if companyID := signatureCompanyID(revokedSig); companyID != "" {
+		var companyErr error
+		companyModel, companyErr = s.companyService.GetCompany(companyID)
+		if companyErr != nil {
+			log.Warnf("unable to lookup company by ID: %s for the revoked signature ID: %s, error: %+v", companyID, signatureID, companyErr)
+		}
+	}
+
+	userModel, userErr := s.usersService.GetUserByUserName(authUser.UserName, true)
+	if userErr != nil || userModel == nil {
+		log.Warnf("unable to lookup user by username: %s to log the signature revocation, error: %+v", authUser.UserName, userErr)
+	} else {
+		eventArgs := &events.LogEventArgs{
+			EventType:         events.RevokedSignature,
+			ProjectID:         projectModel.ProjectID,
+			ProjectModel:      projectModel,
+			LfUsername:        userModel.LfUsername,
+			UserID:            userModel.UserID,
+			UserModel:         userModel,
+			ExternalProjectID: projectModel.ProjectExternalID,
+			EventData: &events.SignatureRevokedEventData{
+				SignatureID:   signatureID,
+				SignatureType: signatureTypeDescription(revokedSig),
+				SignerName:    revokedSig.SignatureReferenceName,
+				Reason:        utils.StringValue(revocation.Reason),
+				Comment:       revocation.Comment,
+				RevokedOn:     revocation.RevokedOn,
+			},
+		}
+		if companyModel != nil {
+			eventArgs.CompanyID = companyModel.CompanyID
+			eventArgs.CompanyModel = companyModel
+		}
+		s.eventsService.LogEvent(eventArgs)
+	}
+
+	s.sendSignatureRevokedEmails(projectModel, companyModel, revokedSig, revocation)
+
+	return revokedSig, nil
+}
+
+// normalizeDomainApprovalList validates the domain entries being added to the approval list and rewrites them in
+// their canonical form, returns false and a message if any of the entries is not a valid domain or wildcard pattern
+func normalizeDomainApprovalList(params *models.ApprovalList) (string, bool) {
+	var listOfErrors []string
+	for i, domain := range params.AddDomainApprovalList {
+		if msg, valid := utils.ValidDomainPattern(domain); !valid {
+			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid add approval list domain %s - %s", domain, msg))
+			continue
+		}
+		params.AddDomainApprovalList[i] = utils.NormalizeDomainPattern(domain)
+	}
+
+	if len(listOfErrors) > 0 {
+		return strings.Join(listOfErrors, ", "), false
+	}
+	return "", true
+}
+
 // Disassociate project signatures
 func (s service) InvalidateProjectRecords(projectID string, projectName string) (int, error) {
 	f := logrus.Fields{
@@ -505,6 +710,8 @@ func buildApprovalListSummary(approvalListChanges *models.ApprovalList) string {
 	approvalListSummary += appendList(approvalListChanges.RemoveGithubUsernameApprovalList, "Removed GitHub User:")
 	approvalListSummary += appendList(approvalListChanges.AddGithubOrgApprovalList, "Added GithHub Organization:")
 	approvalListSummary += appendList(approvalListChanges.RemoveGithubOrgApprovalList, "Removed GitHub Organization:")
+	approvalListSummary += appendList(approvalListChanges.AddGithubTeamApprovalList, "Added GitHub Team:")
+	approvalListSummary += appendList(approvalListChanges.RemoveGithubTeamApprovalList, "Removed GitHub Team:")
 	approvalListSummary += "</ul>"
 	return approvalListSummary
 }
@@ -787,6 +994,46 @@ func (s service) createEventLogEntries(companyModel *models.Company, projectMode
 			},
 		})
 	}
+	for _, value := range approvalList.AddGithubTeamApprovalList {
+		// Send an event
+		s.eventsService.LogEvent(&events.LogEventArgs{
+			EventType:         events.ClaApprovalListUpdated,
+			ProjectID:         projectModel.ProjectID,
+			ProjectModel:      projectModel,
+			CompanyID:         companyModel.CompanyID,
+			CompanyModel:      companyModel,
+			LfUsername:        userModel.LfUsername,
+			UserID:            userModel.UserID,
+			UserModel:         userModel,
+			ExternalProjectID: projectModel.ProjectExternalID,
+			EventData: &events.CLAApprovalListAddGitHubTeamData{
+				UserName:               userModel.LfUsername,
+				UserEmail:              userModel.LfEmail,
+				UserLFID:               userModel.UserID,
+				ApprovalListGitHubTeam: value,
+			},
+		})
+	}
+	for _, value := range approvalList.RemoveGithubTeamApprovalList {
+		// Send an event
+		s.eventsService.LogEvent(&events.LogEventArgs{
+			EventType:         events.ClaApprovalListUpdated,
+			ProjectID:         projectModel.ProjectID,
+			ProjectModel:      projectModel,
+			CompanyID:         companyModel.CompanyID,
+			CompanyModel:      companyModel,
+			LfUsername:        userModel.LfUsername,
+			UserID:            userModel.UserID,
+			UserModel:         userModel,
+			ExternalProjectID: projectModel.ProjectExternalID,
+			EventData: &events.CLAApprovalListRemoveGitHubTeamData{
+				UserName:               userModel.LfUsername,
+				UserEmail:              userModel.LfEmail,
+				UserLFID:               userModel.UserID,
+				ApprovalListGitHubTeam: value,
+			},
+		})
+	}
 }
 
 func (s service) GetClaGroupICLASignatures(claGroupID string, searchTerm *string) (*models.IclaSignatures, error) {
@@ -794,7 +1041,29 @@ func (s service) GetClaGroupICLASignatures(claGroupID string, searchTerm *string
 }
 
 func (s service) GetClaGroupCorporateContributors(claGroupID string, companyID *string, searchTerm *string) (*models.CorporateContributorList, error) {
-	return s.repo.GetClaGroupCorporateContributors(claGroupID, companyID, searchTerm)
+	result, err := s.repo.GetClaGroupCorporateContributors(claGroupID, companyID, searchTerm)
+	if err != nil || companyID == nil || s.teamMembership == nil {
+		return result, err
+	}
+
+	// Report which GitHub team entries of the company approval list cover each contributor
+	cclaSignature, sigErr := s.repo.GetCorporateSignature(claGroupID, *companyID)
+	if sigErr != nil {
+		log.Warnf("unable to load the CCLA of company %s for CLA Group %s, error: %+v", *companyID, claGroupID, sigErr)
+		return result, nil
+	}
+	annotateGitHubTeamCoverage(s.teamMembership, cclaSignature, result.List)
+	return result, nil
+}
+
+// ForEachClaGroupICLASignature calls fn with each ICLA signature of the CLA Group, one page at a time
+func (s service) ForEachClaGroupICLASignature(claGroupID string, fn func(sig *models.IclaSignature) error) error {
+	return s.repo.ForEachClaGroupICLASignature(claGroupID, fn)
+}
+
+// ForEachClaGroupCorporateContributor calls fn with each corporate contributor of the CLA Group, one page at a time
+func (s service) ForEachClaGroupCorporateContributor(claGroupID string, companyID *string, fn func(contributor *models.CorporateContributor) error) error {
+	return s.repo.ForEachClaGroupCorporateContributor(claGroupID, companyID, fn)
 }
 
 // sendRequestAccessEmailToContributors sends the request access email to the specified contributors
@@ -825,6 +1094,100 @@ close and re-open the pull request to force a recheck by the EasyCLA system.</p>
 	}
 }
 
+// signatureCompanyID returns the company ID of the CCLA or employee acknowledgement signature, empty for an ICLA
+func signatureCompanyID(sig *models.Signature) string {
+	if sig.SignatureReferenceType == "company" {
+		return sig.SignatureReferenceID.String()
+	}
+	return sig.SignatureUserCompanyID
+}
+
+// signatureTypeDescription returns the ICLA, CCLA or ECLA description of the signature type
+func signatureTypeDescription(sig *models.Signature) string {
+	switch {
+	case sig.SignatureType == CCLA:
+		return "CCLA"
+	case sig.SignatureUserCompanyID != "":
+		return "ECLA"
+	default:
+		return "ICLA"
+	}
+}
+
+// sendSignatureRevokedEmails notifies the individual signer and the CLA Managers of the company about the revoked
+// signature, problems are only logged
+func (s service) sendSignatureRevokedEmails(projectModel *models.Project, companyModel *models.Company, sig *models.Signature, revocation *models.SignatureRevocation) {
+	var claManagers []models.User
+	if sig.SignatureReferenceType == "user" {
+		signer, err := s.usersService.GetUser(sig.SignatureReferenceID.String())
+		if err != nil || signer == nil {
+			log.Warnf("unable to lookup the signer by user ID: %s of the revoked signature ID: %s, error: %+v",
+				sig.SignatureReferenceID, sig.SignatureID, err)
+		} else {
+			sendSignatureRevokedEmail(projectModel, companyModel, sig, revocation, signer.Username, getBestEmail(*signer),
+				"the signer")
+		}
+		if companyModel != nil {
+			// employee acknowledgement - notify the CLA Managers of the company CCLA
+			corporateSig, err := s.repo.GetCorporateSignature(sig.ProjectID, companyModel.CompanyID)
+			if err != nil {
+				log.Warnf("unable to lookup the corporate signature of company ID: %s for CLA Group ID: %s, error: %+v",
+					companyModel.CompanyID, sig.ProjectID, err)
+			} else if corporateSig != nil {
+				claManagers = corporateSig.SignatureACL
+			}
+		}
+	} else {
+		claManagers = sig.SignatureACL
+	}
+
+	for _, claManager := range claManagers {
+		sendSignatureRevokedEmail(projectModel, companyModel, sig, revocation, claManager.Username, getBestEmail(claManager),
+			"a CLA Manager of the company")
+	}
+}
+
+// sendSignatureRevokedEmail sends the signature revoked email to the specified recipient
+func sendSignatureRevokedEmail(projectModel *models.Project, companyModel *models.Company, sig *models.Signature, revocation *models.SignatureRevocation, recipientName, recipientAddress, recipientReason string) {
+	if recipientAddress == "" {
+		log.Warnf("unable to send the signature revoked email for signature ID: %s to %s - no email address", sig.SignatureID, recipientName)
+		return
+	}
+	projectName := projectModel.ProjectName
+	signatureDescription := fmt.Sprintf("%s signature of %s", signatureTypeDescription(sig), sig.SignatureReferenceName)
+	if companyModel != nil && sig.SignatureReferenceType == "user" {
+		signatureDescription = fmt.Sprintf("%s on behalf of %s", signatureDescription, companyModel.CompanyName)
+	}
+	comment := ""
+	if revocation.Comment != "" {
+		comment = fmt.Sprintf("<p>Comment: %s</p>", revocation.Comment)
+	}
+
+	// subject string, body string, recipients []string
+	subject := fmt.Sprintf("EasyCLA: Signature Revoked for %s", projectName)
+	recipients := []string{recipientAddress}
+	body := fmt.Sprintf(`
+<p>Hello %s,</p>
+<p>This is a notification email from EasyCLA regarding the project %s.</p>
+<p>The %s for the project %s was revoked by %s on %s. You are receiving this message as %s.</p>
+<p>Reason: %s</p>
+%s
+<p>Contributions covered by this signature are no longer authorized. The signature remains in the signature history of
+the project.</p>
+%s
+%s`,
+		recipientName, projectName, signatureDescription, projectName, revocation.RevokedBy, revocation.RevokedOn,
+		recipientReason, RevocationReasonDescription(utils.StringValue(revocation.Reason)), comment,
+		utils.GetEmailHelpContent(projectModel.Version == utils.V2), utils.GetEmailSignOffContent())
+
+	err := utils.SendEmail(subject, body, recipients)
+	if err != nil {
+		log.Warnf("problem sending email with subject: %s to recipients: %+v, error: %+v", subject, recipients, err)
+	} else {
+		log.Debugf("sent email with subject: %s to recipients: %+v", subject, recipients)
+	}
+}
+
 // getBestEmail is a helper function to return the best email address for the user model
 func getBestEmail(claManager models.User) string {
 	if claManager.LfEmail != "" {
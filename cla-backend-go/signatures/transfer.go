@@ -0,0 +1,59 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+)
+
+// isCorporateSignature returns true if the signature is a CCLA signed on behalf of a company, otherwise it is an
+// employee acknowledgement referencing the company
+func isCorporateSignature(sig *models.Signature) bool {
+	return sig.SignatureType == CCLA && sig.SignatureReferenceType == "company"
+}
+
+// companySigTypeSignedApprovedID returns the sigtype_signed_approved_id value of the signature referencing the
+// specified company, as the signature table stream handler would set it
+func companySigTypeSignedApprovedID(sig *models.Signature, companyID string) string {
+	sigType := ECLA
+	if isCorporateSignature(sig) {
+		sigType = CCLA
+	}
+	return fmt.Sprintf("%s#%v#%v#%s", sigType, sig.SignatureSigned, sig.SignatureApproved, companyID)
+}
+
+// buildTransferNote returns the signature note recording the transfer to the target company
+func buildTransferNote(sig *models.Signature, targetCompany *models.Company, transferredOn string) string {
+	return fmt.Sprintf("Signature transferred from company ID: %s to company: %s (%s) on %s",
+		transferredFromCompanyID(sig), targetCompany.CompanyName, targetCompany.CompanyID, transferredOn)
+}
+
+// transferredFromCompanyID returns the ID of the company the signature references
+func transferredFromCompanyID(sig *models.Signature) string {
+	if isCorporateSignature(sig) {
+		return sig.SignatureReferenceID.String()
+	}
+	return sig.SignatureUserCompanyID
+}
+
+// transferAttributeValues returns the names and the string values of the signature attributes to set to transfer the
+// signature to the target company
+func transferAttributeValues(sig *models.Signature, targetCompany *models.Company, transferredOn string) map[string]string {
+	values := map[string]string{
+		"sigtype_signed_approved_id": companySigTypeSignedApprovedID(sig, targetCompany.CompanyID),
+		"note":                       buildTransferNote(sig, targetCompany, transferredOn),
+		"date_modified":              transferredOn,
+	}
+	if isCorporateSignature(sig) {
+		values["signature_reference_id"] = targetCompany.CompanyID
+		values["signature_reference_name"] = targetCompany.CompanyName
+		values["signature_reference_name_lower"] = strings.ToLower(targetCompany.CompanyName)
+	} else {
+		values["signature_user_ccla_company_id"] = targetCompany.CompanyID
+	}
+	return values
+}
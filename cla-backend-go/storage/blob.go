@@ -0,0 +1,106 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// blob storage drivers
+const (
+	BlobDriverS3           = "s3"
+	BlobDriverS3Compatible = "s3-compatible"
+	BlobDriverFilesystem   = "filesystem"
+)
+
+// blob storage errors
+var (
+	ErrBlobNotFound = errors.New("blob not found")
+	ErrBlobChanged  = errors.New("blob changed since it was opened")
+)
+
+// BlobStore stores the signed documents, the templates and the zips of the signed documents. The keys are slash
+// separated paths - contract-group/<cla-group-ID>/<claType>/<identifier>/<signatureID>.pdf for example.
+type BlobStore interface {
+	// Put stores the content read from body at the key, replacing the previous blob once the body is completely read
+	Put(key string, body io.Reader, options *PutOptions) error
+	// Get returns the content of the blob, ErrBlobNotFound if there is no blob at the key
+	Get(key string) (io.ReadCloser, error)
+	// GetRange returns the content of the byte range of the blob, start and end included. The read fails with
+	// ErrBlobChanged if the blob does not match the etag, if any, anymore.
+	GetRange(key string, start, end int64, etag string) (io.ReadCloser, error)
+	// Stat returns the information of the blob, ErrBlobNotFound if there is no blob at the key
+	Stat(key string) (*BlobInfo, error)
+	// List calls fn with the blobs whose key starts with the prefix until fn returns false, the metadata of the blobs
+	// isn't loaded
+	List(prefix string, fn func(info *BlobInfo) bool) error
+	// Delete deletes the blob, deleting a missing blob isn't an error
+	Delete(key string) error
+	// DownloadURL returns a URL to download the blob, valid for the given duration when the URL is signed
+	DownloadURL(key string, validity time.Duration) (string, error)
+	// PublicURL returns the URL of the blobs stored with the public option
+	PublicURL(key string) string
+}
+
+// PutOptions are the optional attributes of a stored blob
+type PutOptions struct {
+	ContentType string
+	// Public blobs are readable by anyone with their public URL
+	Public   bool
+	Metadata map[string]string
+}
+
+// BlobInfo is the information of a stored blob
+type BlobInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	ETag         string
+	Metadata     map[string]string
+}
+
+// StaleUploadAborter is implemented by the blob stores whose interrupted uploads are left behind, the multipart
+// uploads of S3 for example
+type StaleUploadAborter interface {
+	// AbortStaleUploads aborts the uploads of the key started before the given age, the recent uploads may be in
+	// progress and are left as is
+	AbortStaleUploads(key string, olderThan time.Duration) error
+}
+
+// NewBlobStore returns the blob store of the configured driver - the AWS S3 bucket by default
+func NewBlobStore(awsSession *session.Session, blobConfig config.BlobStorage, bucketName string) (BlobStore, error) {
+	switch blobConfig.Driver {
+	case "", BlobDriverS3:
+		return NewS3BlobStore(awsSession, bucketName), nil
+	case BlobDriverS3Compatible:
+		if blobConfig.Endpoint == "" {
+			return nil, errors.New("the endpoint of the s3-compatible blob storage is required")
+		}
+		awsConfig := &aws.Config{
+			Endpoint:         aws.String(blobConfig.Endpoint),
+			S3ForcePathStyle: aws.Bool(blobConfig.ForcePathStyle),
+		}
+		if blobConfig.Region != "" {
+			awsConfig.Region = aws.String(blobConfig.Region)
+		}
+		if blobConfig.AccessKeyID != "" {
+			awsConfig.Credentials = credentials.NewStaticCredentials(blobConfig.AccessKeyID, blobConfig.SecretAccessKey, "")
+		}
+		log.Infof("Using the s3-compatible blob storage - endpoint: %s, bucket: %s", blobConfig.Endpoint, bucketName)
+		return NewS3BlobStore(awsSession, bucketName, awsConfig), nil
+	case BlobDriverFilesystem:
+		log.Infof("Using the filesystem blob storage - directory: %s", blobConfig.Directory)
+		return NewFilesystemBlobStore(blobConfig.Directory, blobConfig.BaseURL)
+	}
+	return nil, fmt.Errorf("unsupported blob storage driver: %s", blobConfig.Driver)
+}
@@ -0,0 +1,274 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// blobMetadataSuffix is the filename suffix of the files holding the metadata of the blobs, next to the blob files
+const blobMetadataSuffix = ".metadata.json"
+
+// filesystemBlobStore stores the blobs as files of a directory, the key of a blob is the path of its file relative to
+// the directory. The files are written to temporary files which replace the previous files once completely written,
+// the hidden files and the metadata files aren't listed.
+type filesystemBlobStore struct {
+	directory string
+	baseURL   string
+}
+
+// NewFilesystemBlobStore returns the blob store of the directory, created if missing. The download URLs are the URLs
+// of the base URL the directory is served at, the file URLs when no base URL is configured.
+func NewFilesystemBlobStore(directory string, baseURL string) (BlobStore, error) {
+	if directory == "" {
+		return nil, errors.New("the directory of the filesystem blob storage is required")
+	}
+	absDirectory, err := filepath.Abs(directory)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(absDirectory, 0750); err != nil {
+		return nil, err
+	}
+	return &filesystemBlobStore{
+		directory: absDirectory,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+	}, nil
+}
+
+// filename returns the file of the key, the keys leaving the directory are rejected
+func (s *filesystemBlobStore) filename(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if key == "" || strings.HasSuffix(key, "/") || cleaned != "/"+key {
+		return "", fmt.Errorf("invalid blob key: %s", key)
+	}
+	return filepath.Join(s.directory, filepath.FromSlash(cleaned)), nil
+}
+
+// Put writes the blob to a temporary file which replaces the blob file once the body is completely read
+func (s *filesystemBlobStore) Put(key string, body io.Reader, options *PutOptions) error {
+	filename, err := s.filename(key)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(filename)
+	if err = os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(filename)+".upload-")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = s.writeMetadata(filename, options)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filename)
+	}
+	if err != nil {
+		if removeErr := os.Remove(tmp.Name()); removeErr != nil && !os.IsNotExist(removeErr) {
+			log.Warnf("unable to remove the temporary file: %s, error: %+v", tmp.Name(), removeErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// writeMetadata writes the metadata file of the blob file, the previous metadata file is removed if the blob has no
+// metadata
+func (s *filesystemBlobStore) writeMetadata(filename string, options *PutOptions) error {
+	if options == nil || len(options.Metadata) == 0 {
+		if err := os.Remove(filename + blobMetadataSuffix); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(options.Metadata)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename+blobMetadataSuffix, data, 0640)
+}
+
+// Get opens the blob file
+func (s *filesystemBlobStore) Get(key string) (io.ReadCloser, error) {
+	filename, err := s.filename(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrBlobNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// GetRange opens the blob file and reads the range, the file replacing the blob file while it is read isn't read
+func (s *filesystemBlobStore) GetRange(key string, start, end int64, etag string) (io.ReadCloser, error) {
+	rc, err := s.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	f := rc.(*os.File)
+	fi, err := f.Stat()
+	if err != nil {
+		s.closeFile(f)
+		return nil, err
+	}
+	if etag != "" && fileETag(fi) != etag {
+		s.closeFile(f)
+		return nil, ErrBlobChanged
+	}
+	return &sectionReadCloser{
+		Reader: io.NewSectionReader(f, start, end-start+1),
+		Closer: f,
+	}, nil
+}
+
+// Stat returns the information of the blob file and its metadata
+func (s *filesystemBlobStore) Stat(key string) (*BlobInfo, error) {
+	filename, err := s.filename(key)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrBlobNotFound
+		}
+		return nil, err
+	}
+	info := fileBlobInfo(key, fi)
+	data, err := ioutil.ReadFile(filepath.Clean(filename + blobMetadataSuffix))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return info, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &info.Metadata); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// errStopListing stops walking the directory once the listing function returns false
+var errStopListing = errors.New("stop listing")
+
+// List walks the directory of the prefix, the blobs are listed by key
+func (s *filesystemBlobStore) List(prefix string, fn func(info *BlobInfo) bool) error {
+	root := s.directory
+	if dir := path.Dir("/" + prefix); dir != "/" {
+		root = filepath.Join(s.directory, filepath.FromSlash(dir))
+	}
+	err := filepath.Walk(root, func(filename string, fi os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if strings.HasPrefix(fi.Name(), ".") && filename != root {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if fi.IsDir() || strings.HasSuffix(fi.Name(), blobMetadataSuffix) {
+			return nil
+		}
+		rel, err := filepath.Rel(s.directory, filename)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		if !fn(fileBlobInfo(key, fi)) {
+			return errStopListing
+		}
+		return nil
+	})
+	if err == errStopListing {
+		return nil
+	}
+	return err
+}
+
+// Delete removes the blob file and its metadata file
+func (s *filesystemBlobStore) Delete(key string) error {
+	filename, err := s.filename(key)
+	if err != nil {
+		return err
+	}
+	for _, name := range []string{filename, filename + blobMetadataSuffix} {
+		if err = os.Remove(name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// DownloadURL returns the URL of the blob, the URLs aren't signed and don't expire
+func (s *filesystemBlobStore) DownloadURL(key string, validity time.Duration) (string, error) {
+	if _, err := s.filename(key); err != nil {
+		return "", err
+	}
+	return s.PublicURL(key), nil
+}
+
+// PublicURL returns the URL of the blob under the base URL, the file URL when no base URL is configured
+func (s *filesystemBlobStore) PublicURL(key string) string {
+	if s.baseURL != "" {
+		return s.baseURL + (&url.URL{Path: "/" + key}).EscapedPath()
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(s.directory, filepath.FromSlash(key)))}).String()
+}
+
+func (s *filesystemBlobStore) closeFile(f *os.File) {
+	if err := f.Close(); err != nil {
+		log.Warnf("unable to close the blob file: %s, error: %+v", f.Name(), err)
+	}
+}
+
+// fileETag returns the etag of the blob file, the file is replaced when the blob is stored so the modification time
+// and the size identify its content
+func fileETag(fi os.FileInfo) string {
+	return fmt.Sprintf("%x-%x", fi.ModTime().UnixNano(), fi.Size())
+}
+
+func fileBlobInfo(key string, fi os.FileInfo) *BlobInfo {
+	return &BlobInfo{
+		Key:          key,
+		Size:         fi.Size(),
+		LastModified: fi.ModTime(),
+		ETag:         fileETag(fi),
+	}
+}
+
+// sectionReadCloser reads a section of the file and closes the file
+type sectionReadCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,207 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// the large blobs are streamed to multipart uploads, the memory used by an upload is bounded by the part size times
+// the upload concurrency
+const (
+	s3UploadPartSize    = 16 * 1024 * 1024
+	s3UploadConcurrency = 3
+)
+
+// s3BlobStore stores the blobs in an S3 bucket, of AWS or of an S3 API compatible server
+type s3BlobStore struct {
+	s3         *s3.S3
+	uploader   *s3manager.Uploader
+	bucketName string
+}
+
+// NewS3BlobStore returns the blob store of the bucket, the optional configs override the session configuration - the
+// endpoint and path-style addressing of an S3 compatible server such as MinIO for example
+func NewS3BlobStore(awsSession *session.Session, bucketName string, cfgs ...*aws.Config) BlobStore {
+	s3Client := s3.New(awsSession, cfgs...)
+	return &s3BlobStore{
+		s3: s3Client,
+		uploader: s3manager.NewUploaderWithClient(s3Client, func(u *s3manager.Uploader) {
+			u.PartSize = s3UploadPartSize
+			u.Concurrency = s3UploadConcurrency
+		}),
+		bucketName: bucketName,
+	}
+}
+
+// Put uploads the blob, in parts for the large blobs - the parts are discarded if the upload fails
+func (s *s3BlobStore) Put(key string, body io.Reader, options *PutOptions) error {
+	input := &s3manager.UploadInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+		Body:   body,
+	}
+	if options != nil {
+		if options.ContentType != "" {
+			input.ContentType = aws.String(options.ContentType)
+		}
+		if options.Public {
+			input.ACL = aws.String(s3.ObjectCannedACLPublicRead)
+		}
+		if len(options.Metadata) > 0 {
+			input.Metadata = aws.StringMap(options.Metadata)
+		}
+	}
+	_, err := s.uploader.Upload(input)
+	if err != nil {
+		log.Warnf("problem uploading to s3 bucket: %s resource: %s, error: %+v", s.bucketName, key, err)
+	}
+	return err
+}
+
+// Get returns the content of the blob
+func (s *s3BlobStore) Get(key string) (io.ReadCloser, error) {
+	output, err := s.s3.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+	return output.Body, nil
+}
+
+// GetRange returns the content of the byte range of the blob
+func (s *s3BlobStore) GetRange(key string, start, end int64, etag string) (io.ReadCloser, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+	}
+	if etag != "" {
+		input.IfMatch = aws.String(etag)
+	}
+	output, err := s.s3.GetObject(input)
+	if err != nil {
+		return nil, s3Error(err)
+	}
+	return output.Body, nil
+}
+
+// Stat returns the information of the blob
+func (s *s3BlobStore) Stat(key string) (*BlobInfo, error) {
+	head, err := s.s3.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+	return &BlobInfo{
+		Key:          key,
+		Size:         aws.Int64Value(head.ContentLength),
+		LastModified: aws.TimeValue(head.LastModified),
+		ETag:         aws.StringValue(head.ETag),
+		Metadata:     aws.StringValueMap(head.Metadata),
+	}, nil
+}
+
+// List lists the blobs of the prefix, by pages of the bucket listing
+func (s *s3BlobStore) List(prefix string, fn func(info *BlobInfo) bool) error {
+	return s.s3.ListObjectsPages(&s3.ListObjectsInput{
+		Bucket: aws.String(s.bucketName),
+		Prefix: aws.String(prefix),
+	}, func(output *s3.ListObjectsOutput, lastPage bool) bool {
+		for _, obj := range output.Contents {
+			if !fn(&BlobInfo{
+				Key:          aws.StringValue(obj.Key),
+				Size:         aws.Int64Value(obj.Size),
+				LastModified: aws.TimeValue(obj.LastModified),
+				ETag:         aws.StringValue(obj.ETag),
+			}) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Delete deletes the blob
+func (s *s3BlobStore) Delete(key string) error {
+	_, err := s.s3.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// DownloadURL returns the presigned URL of the blob
+func (s *s3BlobStore) DownloadURL(key string, validity time.Duration) (string, error) {
+	req, _ := s.s3.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	return req.Presign(validity)
+}
+
+// PublicURL returns the unsigned URL of the blob, addressed as configured for the endpoint
+func (s *s3BlobStore) PublicURL(key string) string {
+	req, _ := s.s3.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err := req.Build(); err != nil {
+		log.Warnf("unable to build the URL of s3 bucket: %s resource: %s, error: %+v", s.bucketName, key, err)
+		return ""
+	}
+	return req.HTTPRequest.URL.String()
+}
+
+// AbortStaleUploads aborts the multipart uploads of the key left by the interrupted uploads
+func (s *s3BlobStore) AbortStaleUploads(key string, olderThan time.Duration) error {
+	output, err := s.s3.ListMultipartUploads(&s3.ListMultipartUploadsInput{
+		Bucket: aws.String(s.bucketName),
+		Prefix: aws.String(key),
+	})
+	if err != nil {
+		return err
+	}
+	for _, upload := range output.Uploads {
+		if aws.StringValue(upload.Key) != key || upload.Initiated == nil || time.Since(*upload.Initiated) < olderThan {
+			continue
+		}
+		log.Debugf("aborting the stale multipart upload %s of %s", aws.StringValue(upload.UploadId), key)
+		_, err = s.s3.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(s.bucketName),
+			Key:      upload.Key,
+			UploadId: upload.UploadId,
+		})
+		if err != nil {
+			log.Warnf("unable to abort the multipart upload %s of %s. error = %v", aws.StringValue(upload.UploadId), key, err)
+		}
+	}
+	return nil
+}
+
+// s3Error returns the blob storage error of the s3 error - HeadObject reports missing keys as NotFound
+func s3Error(err error) error {
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case s3.ErrCodeNoSuchKey, "NotFound":
+			return ErrBlobNotFound
+		case "PreconditionFailed":
+			return ErrBlobChanged
+		}
+	}
+	return err
+}
@@ -0,0 +1,302 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// storage drivers
+const (
+	DriverDynamoDB = "dynamodb"
+	DriverMemory   = "memory"
+)
+
+// errors
+var (
+	ErrItemNotFound      = errors.New("item not found")
+	ErrItemAlreadyExists = errors.New("item already exists")
+)
+
+// MemoryStore is a process local key/value store used by the in-memory repository implementations. Records are
+// kept in the DynamoDB attribute value format so that the in-memory repositories marshal and unmarshal the same
+// dynamodbav/json tagged models as their DynamoDB counterparts. Every read returns a fresh copy of the record.
+//
+// When a file path is provided the store is loaded from the file on startup and written back (atomically) after
+// each mutation - this allows developers to seed a local environment from a fixture file and to keep their data
+// between restarts.
+type MemoryStore struct {
+	lock     sync.RWMutex
+	filePath string
+	tables   map[string]map[string][]byte
+}
+
+// NewMemoryStore creates a new in-memory store, optionally backed by the specified file
+func NewMemoryStore(filePath string) (*MemoryStore, error) {
+	f := logrus.Fields{
+		"functionName": "NewMemoryStore",
+		"filePath":     filePath,
+	}
+	store := &MemoryStore{
+		filePath: filePath,
+		tables:   make(map[string]map[string][]byte),
+	}
+
+	if filePath == "" {
+		return store, nil
+	}
+
+	data, err := ioutil.ReadFile(filepath.Clean(filePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.WithFields(f).Info("memory store file does not exist - starting with an empty store")
+			return store, nil
+		}
+		log.WithFields(f).Warnf("unable to read memory store file, error: %+v", err)
+		return nil, err
+	}
+
+	var fileTables map[string]map[string]map[string]*dynamodb.AttributeValue
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &fileTables); err != nil {
+			log.WithFields(f).Warnf("unable to decode memory store file, error: %+v", err)
+			return nil, err
+		}
+	}
+
+	count := 0
+	for tableName, items := range fileTables {
+		table := make(map[string][]byte, len(items))
+		for key, item := range items {
+			b, err := json.Marshal(item)
+			if err != nil {
+				return nil, err
+			}
+			table[key] = b
+			count++
+		}
+		store.tables[tableName] = table
+	}
+
+	log.WithFields(f).Infof("loaded %d records from %d tables", count, len(store.tables))
+	return store, nil
+}
+
+// Get loads the record with the specified key into out - returns false if the record does not exist
+func (s *MemoryStore) Get(tableName, key string, out interface{}) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.get(tableName, key, out)
+}
+
+// Put creates or replaces the record with the specified key - the item may be a model or a raw attribute value map
+func (s *MemoryStore) Put(tableName, key string, item interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	b, err := encode(item)
+	if err != nil {
+		return err
+	}
+	return s.write(tableName, key, b)
+}
+
+// Create stores a new record - returns ErrItemAlreadyExists if a record with the same key is already present
+func (s *MemoryStore) Create(tableName, key string, item interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.tables[tableName][key]; ok {
+		return ErrItemAlreadyExists
+	}
+	b, err := encode(item)
+	if err != nil {
+		return err
+	}
+	return s.write(tableName, key, b)
+}
+
+// Delete removes the record with the specified key, deleting a missing record is not an error
+func (s *MemoryStore) Delete(tableName, key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.tables[tableName][key]; !ok {
+		return nil
+	}
+	return s.write(tableName, key, nil)
+}
+
+// Update atomically loads the record with the specified key into out, invokes the update function and stores the
+// value of out back into the table. If the update function returns an error the record is left unchanged and the
+// error is returned. Returns ErrItemNotFound if the record does not exist.
+func (s *MemoryStore) Update(tableName, key string, out interface{}, update func() error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	found, err := s.get(tableName, key, out)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrItemNotFound
+	}
+	if err := update(); err != nil {
+		return err
+	}
+	b, err := encode(out)
+	if err != nil {
+		return err
+	}
+	return s.write(tableName, key, b)
+}
+
+// UpdateItem atomically applies the update function to the raw attribute values of the record with the specified
+// key. This mirrors a DynamoDB UpdateItem call for callers which only touch a subset of the record attributes.
+// Returns ErrItemNotFound if the record does not exist.
+func (s *MemoryStore) UpdateItem(tableName, key string, update func(item map[string]*dynamodb.AttributeValue) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	b, ok := s.tables[tableName][key]
+	if !ok {
+		return ErrItemNotFound
+	}
+	var item map[string]*dynamodb.AttributeValue
+	if err := json.Unmarshal(b, &item); err != nil {
+		return err
+	}
+	if err := update(item); err != nil {
+		return err
+	}
+	b, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return s.write(tableName, key, b)
+}
+
+// Scan loads all the records of the table into out which must be a pointer to a slice. Records are returned in key
+// order so that results are stable between calls.
+func (s *MemoryStore) Scan(tableName string, out interface{}) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	table := s.tables[tableName]
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	items := make([]map[string]*dynamodb.AttributeValue, 0, len(keys))
+	for _, key := range keys {
+		var item map[string]*dynamodb.AttributeValue
+		if err := json.Unmarshal(table[key], &item); err != nil {
+			return err
+		}
+		items = append(items, item)
+	}
+	return dynamodbattribute.UnmarshalListOfMaps(items, out)
+}
+
+func (s *MemoryStore) get(tableName, key string, out interface{}) (bool, error) {
+	table, ok := s.tables[tableName]
+	if !ok {
+		return false, nil
+	}
+	b, ok := table[key]
+	if !ok {
+		return false, nil
+	}
+	var item map[string]*dynamodb.AttributeValue
+	if err := json.Unmarshal(b, &item); err != nil {
+		return false, err
+	}
+	return true, dynamodbattribute.UnmarshalMap(item, out)
+}
+
+// encode converts the item to its stored form - raw attribute values are stored as is, everything else goes through
+// the DynamoDB marshaller
+func encode(item interface{}) ([]byte, error) {
+	av, ok := item.(map[string]*dynamodb.AttributeValue)
+	if !ok {
+		var err error
+		av, err = dynamodbattribute.MarshalMap(item)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(av)
+}
+
+// write stores the encoded record, or deletes it when b is nil, and persists the store. If the store can't be
+// persisted the change is rolled back so that the records in memory always match the backing file - callers must
+// hold the write lock
+func (s *MemoryStore) write(tableName, key string, b []byte) error {
+	table, ok := s.tables[tableName]
+	if !ok {
+		table = make(map[string][]byte)
+		s.tables[tableName] = table
+	}
+	previous, existed := table[key]
+	if b == nil {
+		delete(table, key)
+	} else {
+		table[key] = b
+	}
+
+	if err := s.persist(); err != nil {
+		if existed {
+			table[key] = previous
+		} else {
+			delete(table, key)
+		}
+		return err
+	}
+	return nil
+}
+
+// persist writes the store to the backing file, if any - callers must hold the write lock
+func (s *MemoryStore) persist() error {
+	if s.filePath == "" {
+		return nil
+	}
+
+	fileTables := make(map[string]map[string]json.RawMessage, len(s.tables))
+	for tableName, table := range s.tables {
+		items := make(map[string]json.RawMessage, len(table))
+		for key, b := range table {
+			items[key] = b
+		}
+		fileTables[tableName] = items
+	}
+	data, err := json.MarshalIndent(fileTables, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file in the same directory and rename so that a crash never leaves a partial file behind
+	tmpFile, err := ioutil.TempFile(filepath.Dir(s.filePath), filepath.Base(s.filePath)+".tmp")
+	if err != nil {
+		log.Warnf("unable to create temporary memory store file, error: %+v", err)
+		return err
+	}
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return err
+	}
+	return os.Rename(tmpFile.Name(), s.filePath)
+}
@@ -0,0 +1,102 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/sessions"
+)
+
+// MemorySessionStore is a process local session store used with the in-memory storage driver in place of the
+// DynamoDB session store - the session values are kept in memory and the cookie only holds the session ID
+type MemorySessionStore struct {
+	lock     sync.Mutex
+	options  sessions.Options
+	sessions map[string]map[interface{}]interface{}
+}
+
+// NewMemorySessionStore creates a new in-memory session store, the session cookies use the specified path
+func NewMemorySessionStore(path string) *MemorySessionStore {
+	return &MemorySessionStore{
+		options:  sessions.Options{Path: path, HttpOnly: true},
+		sessions: make(map[string]map[interface{}]interface{}),
+	}
+}
+
+// Get returns the session of the request, cached for the duration of the request
+func (s *MemorySessionStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// New returns the stored session of the session cookie of the request, a new session otherwise
+func (s *MemorySessionStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	options := s.options
+	session.Options = &options
+	session.IsNew = true
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if values, ok := s.sessions[cookie.Value]; ok {
+		session.ID = cookie.Value
+		session.Values = copyValues(values)
+		session.IsNew = false
+	}
+	return session, nil
+}
+
+// Save stores the session values and sets the session cookie, sessions with a negative max age are deleted
+func (s *MemorySessionStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if session.Options != nil && session.Options.MaxAge < 0 {
+		delete(s.sessions, session.ID)
+		http.SetCookie(w, s.cookie(session, ""))
+		return nil
+	}
+
+	if session.ID == "" {
+		id, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+		session.ID = id.String()
+	}
+	s.sessions[session.ID] = copyValues(session.Values)
+	http.SetCookie(w, s.cookie(session, session.ID))
+	return nil
+}
+
+// cookie returns the session cookie with the value
+func (s *MemorySessionStore) cookie(session *sessions.Session, value string) *http.Cookie {
+	options := s.options
+	if session.Options != nil {
+		options = *session.Options
+	}
+	return &http.Cookie{
+		Name:     session.Name(),
+		Value:    value,
+		Path:     options.Path,
+		Domain:   options.Domain,
+		MaxAge:   options.MaxAge,
+		Secure:   options.Secure,
+		HttpOnly: options.HttpOnly,
+	}
+}
+
+// copyValues returns a copy of the session values, so that the stored session isn't modified by the handlers
+func copyValues(values map[interface{}]interface{}) map[interface{}]interface{} {
+	copied := make(map[interface{}]interface{}, len(values))
+	for key, value := range values {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,220 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aymerick/raymond"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+)
+
+// custom template errors
+var (
+	// ErrInvalidTemplate is returned when an uploaded template is rejected by the validation
+	ErrInvalidTemplate = errors.New("invalid template")
+	// ErrBuiltInTemplate is returned when updating or deleting a built-in template
+	ErrBuiltInTemplate = errors.New("built-in templates can't be modified")
+	// ErrTemplateVersionConflict is returned when the template version was uploaded concurrently
+	ErrTemplateVersionConflict = errors.New("template version already exists")
+)
+
+// document tab types supported by the signing flow
+const (
+	FieldTypeText         = "text"
+	FieldTypeTextUnlocked = "text_unlocked"
+	FieldTypeTextOptional = "text_optional"
+	FieldTypeNumber       = "number"
+	FieldTypeSign         = "sign"
+	FieldTypeDate         = "date"
+)
+
+var validFieldTypes = map[string]bool{
+	FieldTypeText:         true,
+	FieldTypeTextUnlocked: true,
+	FieldTypeTextOptional: true,
+	FieldTypeNumber:       true,
+	FieldTypeSign:         true,
+	FieldTypeDate:         true,
+}
+
+var (
+	placeholderRegex      = regexp.MustCompile(`{{{?\s*([^{}]*?)\s*}?}}`)
+	templateVariableRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// DBTemplateModel is the data model of a version of a custom template, the versions of a template share the template
+// ID and the creation date of the first version
+type DBTemplateModel struct {
+	TemplateID   string              `dynamodbav:"template_id"`
+	Version      int64               `dynamodbav:"version"`
+	Name         string              `dynamodbav:"name"`
+	Description  string              `dynamodbav:"description"`
+	IclaHTMLBody string              `dynamodbav:"icla_html_body"`
+	CclaHTMLBody string              `dynamodbav:"ccla_html_body"`
+	MetaFields   []*models.MetaField `dynamodbav:"meta_fields"`
+	IclaFields   []*models.Field     `dynamodbav:"icla_fields"`
+	CclaFields   []*models.Field     `dynamodbav:"ccla_fields"`
+	CreatedBy    string              `dynamodbav:"created_by"`
+	DateCreated  string              `dynamodbav:"date_created"`
+	DateModified string              `dynamodbav:"date_modified"`
+
+	Locale               string                          `dynamodbav:"locale"`
+	LegallyBindingLocale string                          `dynamodbav:"legally_binding_locale"`
+	LocaleVariants       []*models.TemplateLocaleVariant `dynamodbav:"locale_variants"`
+}
+
+// toModel converts the database model to the template model
+func (t DBTemplateModel) toModel() models.Template {
+	return models.Template{
+		ID:           t.TemplateID,
+		Name:         t.Name,
+		Description:  t.Description,
+		IclaHTMLBody: t.IclaHTMLBody,
+		CclaHTMLBody: t.CclaHTMLBody,
+		MetaFields:   t.MetaFields,
+		IclaFields:   t.IclaFields,
+		CclaFields:   t.CclaFields,
+		Version:      t.Version,
+		CreatedBy:    t.CreatedBy,
+		DateCreated:  t.DateCreated,
+		DateModified: t.DateModified,
+
+		Locale:               t.Locale,
+		LegallyBindingLocale: t.LegallyBindingLocale,
+		LocaleVariants:       t.LocaleVariants,
+	}
+}
+
+// latestTemplateVersions returns the latest version of each template
+func latestTemplateVersions(versions []DBTemplateModel) []models.Template {
+	latest := map[string]DBTemplateModel{}
+	var templateIDs []string
+	for _, version := range versions {
+		current, ok := latest[version.TemplateID]
+		if !ok {
+			templateIDs = append(templateIDs, version.TemplateID)
+		}
+		if !ok || version.Version > current.Version {
+			latest[version.TemplateID] = version
+		}
+	}
+	templates := make([]models.Template, 0, len(templateIDs))
+	for _, templateID := range templateIDs {
+		templates = append(templates, latest[templateID].toModel())
+	}
+	return templates
+}
+
+// ValidateTemplate checks an uploaded template: the HTML bodies must parse, may only use the placeholders declared by
+// the meta fields, and each document requires a sign and a date field whose anchor strings - like the anchors of the
+// other required fields - appear in the document. The locale variants are checked like the documents of the template.
+func ValidateTemplate(template *models.Template) error {
+	var problems []string
+	if strings.TrimSpace(template.Name) == "" {
+		problems = append(problems, "the template name is required")
+	}
+	if template.IclaHTMLBody == "" && template.CclaHTMLBody == "" {
+		problems = append(problems, "an ICLA or a CCLA HTML body is required")
+	}
+
+	variables := map[string]bool{}
+	for _, metaField := range template.MetaFields {
+		if metaField == nil {
+			continue
+		}
+		if metaField.Name == "" {
+			problems = append(problems, fmt.Sprintf("the name of the meta field of variable %s is required", metaField.TemplateVariable))
+		}
+		if !templateVariableRegex.MatchString(metaField.TemplateVariable) {
+			problems = append(problems, fmt.Sprintf("invalid template variable: '%s'", metaField.TemplateVariable))
+			continue
+		}
+		if variables[metaField.TemplateVariable] {
+			problems = append(problems, fmt.Sprintf("duplicate template variable: %s", metaField.TemplateVariable))
+		}
+		variables[metaField.TemplateVariable] = true
+	}
+
+	problems = append(problems, validateDocument("ICLA", template.IclaHTMLBody, template.IclaFields, variables)...)
+	problems = append(problems, validateDocument("CCLA", template.CclaHTMLBody, template.CclaFields, variables)...)
+	problems = append(problems, validateLocales(template, variables)...)
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidTemplate, strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// validateDocument returns the problems of the document HTML body and its fields
+func validateDocument(documentType string, body string, fields []*models.Field, variables map[string]bool) []string {
+	var problems []string
+	if body == "" {
+		if len(fields) > 0 {
+			problems = append(problems, fmt.Sprintf("the %s fields require an %s HTML body", documentType, documentType))
+		}
+		return problems
+	}
+	problems = append(problems, validateBody(documentType, body, variables)...)
+
+	ids := map[string]bool{}
+	hasSign, hasDate := false, false
+	for _, field := range fields {
+		if field == nil {
+			continue
+		}
+		if field.ID == "" {
+			problems = append(problems, fmt.Sprintf("the %s field %s requires an id", documentType, field.Name))
+		} else if ids[field.ID] {
+			problems = append(problems, fmt.Sprintf("duplicate %s field id: %s", documentType, field.ID))
+		}
+		ids[field.ID] = true
+		if !validFieldTypes[field.FieldType] {
+			problems = append(problems, fmt.Sprintf("invalid type of the %s field %s: '%s'", documentType, field.ID, field.FieldType))
+		}
+		if field.AnchorString == "" {
+			problems = append(problems, fmt.Sprintf("the %s field %s requires an anchor string", documentType, field.ID))
+		}
+		hasSign = hasSign || field.FieldType == FieldTypeSign
+		hasDate = hasDate || field.FieldType == FieldTypeDate
+	}
+	if !hasSign {
+		problems = append(problems, fmt.Sprintf("the %s document requires a %s field", documentType, FieldTypeSign))
+	}
+	if !hasDate {
+		problems = append(problems, fmt.Sprintf("the %s document requires a %s field", documentType, FieldTypeDate))
+	}
+	return append(problems, validateAnchorStrings(documentType, body, fields)...)
+}
+
+// validateBody returns the problems of the document HTML body, which must parse and may only use the placeholders of
+// the template variables
+func validateBody(documentType string, body string, variables map[string]bool) []string {
+	if _, err := raymond.Parse(body); err != nil {
+		return []string{fmt.Sprintf("the %s HTML body is not a valid template: %v", documentType, err)}
+	}
+	var problems []string
+	for _, match := range placeholderRegex.FindAllStringSubmatch(body, -1) {
+		if !variables[match[1]] {
+			problems = append(problems, fmt.Sprintf("unresolved placeholder in the %s HTML body: %s", documentType, match[0]))
+		}
+	}
+	return problems
+}
+
+// validateAnchorStrings returns the anchor strings of the required fields missing from the document HTML body
+func validateAnchorStrings(documentType string, body string, fields []*models.Field) []string {
+	var problems []string
+	for _, field := range fields {
+		if field == nil || field.AnchorString == "" || field.IsOptional {
+			continue
+		}
+		if !strings.Contains(body, field.AnchorString) {
+			problems = append(problems, fmt.Sprintf("the anchor string of the %s field %s is not in the %s HTML body: %s", documentType, field.ID, documentType, field.AnchorString))
+		}
+	}
+	return problems
+}
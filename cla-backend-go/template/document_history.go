@@ -0,0 +1,349 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// the types of the CLA Group documents
+const (
+	DocumentTypeICLA = "icla"
+	DocumentTypeCCLA = "ccla"
+)
+
+// document history errors
+var (
+	// ErrInvalidDocumentType is returned for the document types other than icla and ccla
+	ErrInvalidDocumentType = errors.New("invalid document type, expecting icla or ccla")
+	// ErrInvalidDocumentVersion is returned when the document version isn't formatted as major.minor
+	ErrInvalidDocumentVersion = errors.New("invalid document version, expecting major.minor")
+	// ErrDocumentVersionNotFound is returned when the CLA Group has no document of the version
+	ErrDocumentVersionNotFound = errors.New("document version not found")
+	// ErrDocumentSourceNotFound is returned when the source of the document version wasn't kept, the documents published
+	// before the document history was recorded have no source
+	ErrDocumentSourceNotFound = errors.New("the source of the document version is not available")
+	// ErrDocumentVersionCurrent is returned when rolling back to the current document version
+	ErrDocumentVersionCurrent = errors.New("the document version is already the current version")
+)
+
+// DocumentVersion is a version of a CLA Group document
+type DocumentVersion struct {
+	DocumentType    string
+	MajorVersion    int
+	MinorVersion    int
+	DocumentName    string
+	TemplateID      string
+	TemplateVersion int64
+	MetaFields      []DocumentMetaField
+	PublishedBy     string
+	DateCreated     string
+	DocumentS3URL   string
+	RestoredVersion string
+	Current         bool
+	// the legally binding locale of the document and the locales of its translations
+	Locale             string
+	TranslationLocales []string
+}
+
+// Version returns the major.minor version of the document
+func (v DocumentVersion) Version() string {
+	return formatDocumentVersion(v.MajorVersion, v.MinorVersion)
+}
+
+// documentLocale returns the legally binding locale of the document, the documents published before the translations
+// were supported are in the default locale
+func documentLocale(document DynamoProjectDocument) string {
+	if document.DocumentLocale == "" {
+		return DefaultLocale
+	}
+	return document.DocumentLocale
+}
+
+// translationLocales returns the locales of the translations of the document
+func translationLocales(document DynamoProjectDocument) []string {
+	var locales []string
+	for _, translation := range document.DocumentTranslations {
+		locales = append(locales, translation.Locale)
+	}
+	return locales
+}
+
+// documentsAttributeName returns the CLA Group attribute holding the documents of the document type
+func documentsAttributeName(documentType string) (string, error) {
+	switch documentType {
+	case DocumentTypeICLA:
+		return "project_individual_documents", nil
+	case DocumentTypeCCLA:
+		return "project_corporate_documents", nil
+	}
+	return "", ErrInvalidDocumentType
+}
+
+// documentBlobKey returns the key of the file of the document version, the PDF and the HTML source of each version
+// are kept
+func documentBlobKey(claGroupID string, documentType string, majorVersion, minorVersion int, extension string) string {
+	return fmt.Sprintf("contract-group/%s/template/%s/%s.%s", claGroupID, documentType, formatDocumentVersion(majorVersion, minorVersion), extension)
+}
+
+// translationBlobKey returns the key of the file of a translation of the document version
+func translationBlobKey(claGroupID string, documentType string, majorVersion, minorVersion int, locale string, extension string) string {
+	return documentBlobKey(claGroupID, documentType, majorVersion, minorVersion, locale+"."+extension)
+}
+
+func formatDocumentVersion(majorVersion, minorVersion int) string {
+	return fmt.Sprintf("%d.%d", majorVersion, minorVersion)
+}
+
+// parseDocumentVersion parses the major.minor document version
+func parseDocumentVersion(version string) (int, int, error) {
+	parts := strings.Split(version, ".")
+	if len(parts) != 2 {
+		return 0, 0, ErrInvalidDocumentVersion
+	}
+	majorVersion, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, ErrInvalidDocumentVersion
+	}
+	minorVersion, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, ErrInvalidDocumentVersion
+	}
+	return majorVersion, minorVersion, nil
+}
+
+// currentDocumentIndex returns the index of the latest version of the documents, -1 if there are no documents
+func currentDocumentIndex(documents []DynamoProjectDocument) int {
+	current := -1
+	for i, document := range documents {
+		if current < 0 || document.DocumentMajorVersion > documents[current].DocumentMajorVersion ||
+			(document.DocumentMajorVersion == documents[current].DocumentMajorVersion && document.DocumentMinorVersion > documents[current].DocumentMinorVersion) {
+			current = i
+		}
+	}
+	return current
+}
+
+// findDocumentVersion returns the document of the major.minor version
+func findDocumentVersion(documents []DynamoProjectDocument, version string) (*DynamoProjectDocument, error) {
+	majorVersion, minorVersion, err := parseDocumentVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	for i := range documents {
+		if documents[i].DocumentMajorVersion == majorVersion && documents[i].DocumentMinorVersion == minorVersion {
+			return &documents[i], nil
+		}
+	}
+	return nil, ErrDocumentVersionNotFound
+}
+
+// GetCLAGroupDocumentHistory returns every version of the CLA Group document, oldest first
+func (s service) GetCLAGroupDocumentHistory(ctx context.Context, claGroupID string, documentType string) ([]DocumentVersion, error) {
+	documents, err := s.templateRepo.GetCLAGroupDocuments(claGroupID, documentType)
+	if err != nil {
+		return nil, err
+	}
+	current := currentDocumentIndex(documents)
+	versions := make([]DocumentVersion, 0, len(documents))
+	for i, document := range documents {
+		versions = append(versions, DocumentVersion{
+			DocumentType:    documentType,
+			MajorVersion:    document.DocumentMajorVersion,
+			MinorVersion:    document.DocumentMinorVersion,
+			DocumentName:    document.DocumentName,
+			TemplateID:      document.DocumentFileID,
+			TemplateVersion: document.DocumentTemplateVersion,
+			MetaFields:      document.DocumentMetaFields,
+			PublishedBy:     document.DocumentPublishedBy,
+			DateCreated:     document.DocumentCreationDate,
+			DocumentS3URL:   document.DocumentS3URL,
+			RestoredVersion: document.DocumentRestoredVersion,
+			Current:         i == current,
+
+			Locale:             documentLocale(document),
+			TranslationLocales: translationLocales(document),
+		})
+	}
+	return versions, nil
+}
+
+// DiffCLAGroupDocuments returns the unified diff of the HTML sources of the two versions of the CLA Group document
+func (s service) DiffCLAGroupDocuments(ctx context.Context, claGroupID string, documentType string, fromVersion, toVersion string) (string, error) {
+	documents, err := s.templateRepo.GetCLAGroupDocuments(claGroupID, documentType)
+	if err != nil {
+		return "", err
+	}
+	sources := make([]string, 2)
+	for i, version := range []string{fromVersion, toVersion} {
+		document, findErr := findDocumentVersion(documents, version)
+		if findErr != nil {
+			return "", findErr
+		}
+		sources[i], err = s.readDocumentSource(claGroupID, documentType, document)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(sources[0]),
+		B:        difflib.SplitLines(sources[1]),
+		FromFile: fmt.Sprintf("%s-%s.html", documentType, fromVersion),
+		ToFile:   fmt.Sprintf("%s-%s.html", documentType, toVersion),
+		Context:  3,
+	})
+}
+
+// readDocumentSource returns the HTML source of the document version
+func (s service) readDocumentSource(claGroupID string, documentType string, document *DynamoProjectDocument) (string, error) {
+	rc, err := s.blobStore.Get(documentBlobKey(claGroupID, documentType, document.DocumentMajorVersion, document.DocumentMinorVersion, "html"))
+	if err != nil {
+		if err == storage.ErrBlobNotFound {
+			return "", ErrDocumentSourceNotFound
+		}
+		return "", err
+	}
+	defer func() {
+		if closeErr := rc.Close(); closeErr != nil {
+			log.Warnf("error closing the document source, error: %v", closeErr)
+		}
+	}()
+	source, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return "", err
+	}
+	return string(source), nil
+}
+
+// RollbackCLAGroupDocument re-publishes the version of the CLA Group document as a new version, with the PDF, the HTML
+// source and the tabs of the version. Like a template update the new version is a new major version starting a re-sign
+// campaign when the CLA Group requires re-signature, a new minor version otherwise.
+func (s service) RollbackCLAGroupDocument(ctx context.Context, claGroupID string, documentType string, version string, publishedBy string) (*DocumentVersion, error) {
+	claGroup, err := s.templateRepo.GetCLAGroup(claGroupID)
+	if err != nil {
+		return nil, err
+	}
+	documents, err := s.templateRepo.GetCLAGroupDocuments(claGroupID, documentType)
+	if err != nil {
+		return nil, err
+	}
+	document, err := findDocumentVersion(documents, version)
+	if err != nil {
+		return nil, err
+	}
+	current := documents[currentDocumentIndex(documents)]
+	if current.DocumentMajorVersion == document.DocumentMajorVersion && current.DocumentMinorVersion == document.DocumentMinorVersion {
+		return nil, ErrDocumentVersionCurrent
+	}
+
+	restored := *document
+	if claGroup.ProjectResignRequired {
+		restored.DocumentMajorVersion = current.DocumentMajorVersion + 1
+		restored.DocumentMinorVersion = 0
+	} else {
+		restored.DocumentMajorVersion = current.DocumentMajorVersion
+		restored.DocumentMinorVersion = current.DocumentMinorVersion + 1
+	}
+	_, restored.DocumentCreationDate = utils.CurrentTime()
+	restored.DocumentPublishedBy = publishedBy
+	restored.DocumentRestoredVersion = version
+
+	// Both files of the version are copied, the documents published before the history was recorded can't be restored
+	files := []struct {
+		extension string
+		options   *storage.PutOptions
+	}{
+		{extension: "html", options: &storage.PutOptions{ContentType: "text/html"}},
+		{extension: "pdf", options: &storage.PutOptions{ContentType: "application/pdf", Public: true}},
+	}
+	for _, file := range files {
+		source := documentBlobKey(claGroupID, documentType, document.DocumentMajorVersion, document.DocumentMinorVersion, file.extension)
+		target := documentBlobKey(claGroupID, documentType, restored.DocumentMajorVersion, restored.DocumentMinorVersion, file.extension)
+		if err = s.copyBlob(source, target, file.options); err != nil {
+			if err == storage.ErrBlobNotFound {
+				return nil, ErrDocumentSourceNotFound
+			}
+			return nil, err
+		}
+	}
+	restored.DocumentS3URL = s.blobStore.PublicURL(documentBlobKey(claGroupID, documentType, restored.DocumentMajorVersion, restored.DocumentMinorVersion, "pdf"))
+
+	// The translations of the version are restored with the document
+	restored.DocumentTranslations = make([]DocumentTranslation, 0, len(document.DocumentTranslations))
+	for _, translation := range document.DocumentTranslations {
+		for _, file := range files {
+			source := translationBlobKey(claGroupID, documentType, document.DocumentMajorVersion, document.DocumentMinorVersion, translation.Locale, file.extension)
+			target := translationBlobKey(claGroupID, documentType, restored.DocumentMajorVersion, restored.DocumentMinorVersion, translation.Locale, file.extension)
+			if err = s.copyBlob(source, target, file.options); err != nil {
+				if err == storage.ErrBlobNotFound {
+					return nil, ErrDocumentSourceNotFound
+				}
+				return nil, err
+			}
+		}
+		translation.DocumentS3URL = s.blobStore.PublicURL(translationBlobKey(claGroupID, documentType, restored.DocumentMajorVersion, restored.DocumentMinorVersion, translation.Locale, "pdf"))
+		restored.DocumentTranslations = append(restored.DocumentTranslations, translation)
+	}
+
+	var iclaDocument, cclaDocument *DynamoProjectDocument
+	iclaMajorVersion, cclaMajorVersion := 0, 0
+	if documentType == DocumentTypeICLA {
+		iclaDocument, iclaMajorVersion = &restored, restored.DocumentMajorVersion
+	} else {
+		cclaDocument, cclaMajorVersion = &restored, restored.DocumentMajorVersion
+	}
+	if !claGroup.ProjectResignRequired {
+		// a new minor version doesn't need to be re-signed
+		iclaMajorVersion, cclaMajorVersion = 0, 0
+	}
+	err = s.templateRepo.UpdateDynamoContractGroupTemplates(ctx, claGroupID, cclaDocument, iclaDocument)
+	if err != nil {
+		log.Warnf("Problem updating the database with the restored %s version %s of CLA Group: %s, error: %v", documentType, version, claGroupID, err)
+		return nil, err
+	}
+	s.startResignCampaign(claGroupID, iclaMajorVersion, cclaMajorVersion)
+
+	return &DocumentVersion{
+		DocumentType:    documentType,
+		MajorVersion:    restored.DocumentMajorVersion,
+		MinorVersion:    restored.DocumentMinorVersion,
+		DocumentName:    restored.DocumentName,
+		TemplateID:      restored.DocumentFileID,
+		TemplateVersion: restored.DocumentTemplateVersion,
+		MetaFields:      restored.DocumentMetaFields,
+		PublishedBy:     restored.DocumentPublishedBy,
+		DateCreated:     restored.DocumentCreationDate,
+		DocumentS3URL:   restored.DocumentS3URL,
+		RestoredVersion: restored.DocumentRestoredVersion,
+		Current:         true,
+
+		Locale:             documentLocale(restored),
+		TranslationLocales: translationLocales(restored),
+	}, nil
+}
+
+// copyBlob copies the file of a document version to the file of another version
+func (s service) copyBlob(source, target string, options *storage.PutOptions) error {
+	rc, err := s.blobStore.Get(source)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := rc.Close(); closeErr != nil {
+			log.Warnf("error closing the document file: %s, error: %v", source, closeErr)
+		}
+	}()
+	return s.blobStore.Put(target, rc, options)
+}
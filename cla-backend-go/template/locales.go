@@ -0,0 +1,199 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+)
+
+// DefaultLocale is the locale of the templates and the CLA Group documents which don't declare their locale
+const DefaultLocale = "en"
+
+// ErrLocaleNotFound is returned when the template or the CLA Group document isn't available in the locale
+var ErrLocaleNotFound = errors.New("locale not available")
+
+// localeRegex matches the language tags like en, ja or pt-BR
+var localeRegex = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// templateLocale returns the locale of the HTML bodies of the template
+func templateLocale(template models.Template) string {
+	if template.Locale == "" {
+		return DefaultLocale
+	}
+	return template.Locale
+}
+
+// legallyBindingLocale returns the locale of the legally binding documents of the template
+func legallyBindingLocale(template models.Template) string {
+	if template.LegallyBindingLocale == "" {
+		return templateLocale(template)
+	}
+	return template.LegallyBindingLocale
+}
+
+// templateLocales returns the locales of the template, the legally binding locale first
+func templateLocales(template models.Template) []string {
+	binding := legallyBindingLocale(template)
+	locales := []string{binding}
+	if locale := templateLocale(template); locale != binding {
+		locales = append(locales, locale)
+	}
+	for _, variant := range template.LocaleVariants {
+		if variant != nil && variant.Locale != binding {
+			locales = append(locales, variant.Locale)
+		}
+	}
+	return locales
+}
+
+// localizeTemplate returns the template with the HTML bodies and the anchor strings of the fields of the locale, the
+// legally binding locale when the locale is empty
+func localizeTemplate(template models.Template, locale string) (models.Template, error) {
+	if locale == "" {
+		locale = legallyBindingLocale(template)
+	}
+	if locale == templateLocale(template) {
+		template.Locale = locale
+		return template, nil
+	}
+	for _, variant := range template.LocaleVariants {
+		if variant == nil || variant.Locale != locale {
+			continue
+		}
+		template.Locale = locale
+		template.IclaHTMLBody = variant.IclaHTMLBody
+		template.CclaHTMLBody = variant.CclaHTMLBody
+		template.IclaFields = localizeFields(template.IclaFields, variant.AnchorStrings)
+		template.CclaFields = localizeFields(template.CclaFields, variant.AnchorStrings)
+		return template, nil
+	}
+	return models.Template{}, fmt.Errorf("%w: the template %s has no %s variant", ErrLocaleNotFound, template.Name, locale)
+}
+
+// localizeFields returns the fields with their translated anchor strings
+func localizeFields(fields []*models.Field, anchorStrings map[string]string) []*models.Field {
+	localized := make([]*models.Field, 0, len(fields))
+	for _, field := range fields {
+		if field == nil {
+			continue
+		}
+		localizedField := *field
+		if anchorString, ok := anchorStrings[field.ID]; ok && anchorString != "" {
+			localizedField.AnchorString = anchorString
+		}
+		localized = append(localized, &localizedField)
+	}
+	return localized
+}
+
+// validateLocales returns the problems of the locales of the template: the locale variants must translate the
+// documents of the template - the HTML bodies are checked like the documents of the template and the translated anchor
+// strings must appear in the translations - and the legally binding locale must be one of the locales of the template
+func validateLocales(template *models.Template, variables map[string]bool) []string {
+	var problems []string
+	if template.Locale != "" && !localeRegex.MatchString(template.Locale) {
+		problems = append(problems, fmt.Sprintf("invalid locale: '%s'", template.Locale))
+	}
+	locales := map[string]bool{templateLocale(*template): true}
+	fieldIDs := map[string]bool{}
+	for _, field := range append(append([]*models.Field{}, template.IclaFields...), template.CclaFields...) {
+		if field != nil {
+			fieldIDs[field.ID] = true
+		}
+	}
+
+	for _, variant := range template.LocaleVariants {
+		if variant == nil {
+			continue
+		}
+		if !localeRegex.MatchString(variant.Locale) {
+			problems = append(problems, fmt.Sprintf("invalid locale of the locale variant: '%s'", variant.Locale))
+			continue
+		}
+		if locales[variant.Locale] {
+			problems = append(problems, fmt.Sprintf("duplicate locale: %s", variant.Locale))
+			continue
+		}
+		locales[variant.Locale] = true
+		for fieldID := range variant.AnchorStrings {
+			if !fieldIDs[fieldID] {
+				problems = append(problems, fmt.Sprintf("the %s anchor strings translate an unknown field: %s", variant.Locale, fieldID))
+			}
+		}
+
+		localized, err := localizeTemplate(*template, variant.Locale)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		for _, document := range []struct {
+			documentType  string
+			body          string
+			localizedBody string
+			fields        []*models.Field
+		}{
+			{documentType: "ICLA", body: template.IclaHTMLBody, localizedBody: localized.IclaHTMLBody, fields: localized.IclaFields},
+			{documentType: "CCLA", body: template.CclaHTMLBody, localizedBody: localized.CclaHTMLBody, fields: localized.CclaFields},
+		} {
+			if (document.body == "") != (document.localizedBody == "") {
+				problems = append(problems, fmt.Sprintf("the %s locale variant must provide the documents of the template, %s HTML body", variant.Locale, document.documentType))
+				continue
+			}
+			if document.body != "" {
+				documentType := document.documentType + " " + variant.Locale
+				problems = append(problems, validateBody(documentType, document.localizedBody, variables)...)
+				problems = append(problems, validateAnchorStrings(documentType, document.localizedBody, document.fields)...)
+			}
+		}
+	}
+
+	if !locales[legallyBindingLocale(*template)] {
+		problems = append(problems, fmt.Sprintf("the legally binding locale is not a locale of the template: %s", template.LegallyBindingLocale))
+	}
+	return problems
+}
+
+// LatestProjectDocument returns the latest version of the CLA Group documents, nil if there are no documents
+func LatestProjectDocument(documents []models.ProjectDocument) *models.ProjectDocument {
+	var latest *models.ProjectDocument
+	var latestMajor, latestMinor int
+	for i := range documents {
+		major, majorErr := strconv.Atoi(documents[i].DocumentMajorVersion)
+		minor, minorErr := strconv.Atoi(documents[i].DocumentMinorVersion)
+		if majorErr != nil || minorErr != nil {
+			continue
+		}
+		if latest == nil || major > latestMajor || (major == latestMajor && minor > latestMinor) {
+			latest, latestMajor, latestMinor = &documents[i], major, minor
+		}
+	}
+	return latest
+}
+
+// ProjectDocumentLocale returns the legally binding locale of the CLA Group document, the documents published before
+// the translations were supported are in the default locale
+func ProjectDocumentLocale(document models.ProjectDocument) string {
+	if document.DocumentLocale == "" {
+		return DefaultLocale
+	}
+	return document.DocumentLocale
+}
+
+// ProjectDocumentURL returns the URL of the PDF of the CLA Group document in the locale
+func ProjectDocumentURL(document models.ProjectDocument, locale string) (string, error) {
+	if locale == ProjectDocumentLocale(document) {
+		return document.DocumentS3URL, nil
+	}
+	for _, translation := range document.DocumentTranslations {
+		if translation.Locale == locale {
+			return translation.DocumentS3URL, nil
+		}
+	}
+	return "", ErrLocaleNotFound
+}
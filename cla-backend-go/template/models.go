@@ -14,6 +14,7 @@ type DBProjectModel struct {
 	ProjectCclaEnabled               bool                     `dynamodbav:"project_ccla_enabled"`
 	ProjectCclaRequiresIclaSignature bool                     `dynamodbav:"project_ccla_requires_icla_signature"`
 	ProjectIclaEnabled               bool                     `dynamodbav:"project_icla_enabled"`
+	ProjectResignRequired            bool                     `dynamodbav:"project_resign_required"`
 	ProjectCorporateDocuments        []DBProjectDocumentModel `dynamodbav:"project_corporate_documents"`
 	ProjectIndividualDocuments       []DBProjectDocumentModel `dynamodbav:"project_individual_documents"`
 	ProjectMemberDocuments           []DBProjectDocumentModel `dynamodbav:"project_member_documents"`
@@ -32,4 +33,13 @@ type DBProjectDocumentModel struct {
 	DocumentMajorVersion    string `dynamodbav:"document_major_version"`
 	DocumentMinorVersion    string `dynamodbav:"document_minor_version"`
 	DocumentCreationDate    string `dynamodbav:"document_creation_date"`
+
+	DocumentLocale       string                              `dynamodbav:"document_locale"`
+	DocumentTranslations []DBProjectDocumentTranslationModel `dynamodbav:"document_translations"`
+}
+
+// DBProjectDocumentTranslationModel is a data model for the translations of the CLA Group Project documents
+type DBProjectDocumentTranslationModel struct {
+	Locale        string `dynamodbav:"locale"`
+	DocumentS3URL string `dynamodbav:"document_s3_url"`
 }
@@ -7,15 +7,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
 )
 
@@ -34,12 +37,20 @@ type Repository interface {
 	GetTemplates() ([]models.Template, error)
 	GetTemplate(templateID string) (models.Template, error)
 	GetCLAGroup(claGroupID string) (*models.Project, error)
-	UpdateDynamoContractGroupTemplates(ctx context.Context, ContractGroupID string, template models.Template, pdfUrls models.TemplatePdfs, projectCCLAEnabled, projectICLAEnabled bool) error
+	UpdateDynamoContractGroupTemplates(ctx context.Context, ContractGroupID string, cclaDocument, iclaDocument *DynamoProjectDocument) error
+	GetCLAGroupDocuments(claGroupID string, documentType string) ([]DynamoProjectDocument, error)
+
+	CreateCustomTemplateVersion(template *DBTemplateModel) error
+	GetCustomTemplate(templateID string, version int64) (models.Template, error)
+	GetCustomTemplateVersions(templateID string) ([]models.Template, error)
+	GetCustomTemplates() ([]models.Template, error)
+	DeleteCustomTemplate(templateID string) error
 }
 
 type repository struct {
 	stage          string // The AWS stage (dev, staging, prod)
 	dynamoDBClient *dynamodb.DynamoDB
+	templatesTable string
 }
 
 // CLAGroup structure
@@ -69,6 +80,31 @@ type DynamoProjectDocument struct {
 	DocumentAuthorName      string        `json:"document_author_name"`
 	DocumentS3URL           string        `json:"document_s3_url"`
 	DocumentTabs            []DocumentTab `json:"document_tabs"`
+
+	// the history of the documents created from the templates
+	DocumentTemplateVersion int64               `json:"document_template_version,omitempty"`
+	DocumentMetaFields      []DocumentMetaField `json:"document_meta_fields,omitempty"`
+	DocumentPublishedBy     string              `json:"document_published_by,omitempty"`
+	DocumentRestoredVersion string              `json:"document_restored_version,omitempty"`
+
+	// the locale of the legally binding document and the translations of the document
+	DocumentLocale       string                `json:"document_locale,omitempty"`
+	DocumentTranslations []DocumentTranslation `json:"document_translations,omitempty"`
+}
+
+// DocumentTranslation is a translation of a CLA Group document, the signers may view the translations but the document
+// in the legally binding locale prevails
+type DocumentTranslation struct {
+	Locale        string        `json:"locale"`
+	DocumentS3URL string        `json:"document_s3_url"`
+	DocumentTabs  []DocumentTab `json:"document_tabs"`
+}
+
+// DocumentMetaField is the value of a template meta field used to create the document
+type DocumentMetaField struct {
+	Name             string `json:"name"`
+	TemplateVariable string `json:"template_variable"`
+	Value            string `json:"value"`
 }
 
 // DocumentTab structure
@@ -95,6 +131,7 @@ func NewRepository(awsSession *session.Session, stage string) repository {
 	return repository{
 		stage:          stage,
 		dynamoDBClient: dynamodb.New(awsSession),
+		templatesTable: fmt.Sprintf("cla-%s-templates", stage),
 	}
 }
 
@@ -107,6 +144,7 @@ func (r repository) GetTemplates() ([]models.Template, error) {
 		if template.Name == "LF Style Template" && strings.ToLower(r.stage) != "dev" {
 			log.Debugf("Skipping '%s' template since we are in stage: %s - only shown in 'dev'", template.Name, r.stage)
 		} else {
+			template.BuiltIn = true
 			templates = append(templates, template)
 		}
 	}
@@ -120,6 +158,7 @@ func (r repository) GetTemplate(templateID string) (models.Template, error) {
 	if !ok {
 		return models.Template{}, ErrTemplateNotFound
 	}
+	template.BuiltIn = true
 
 	return template, nil
 }
@@ -156,21 +195,55 @@ func (r repository) GetCLAGroup(claGroupID string) (*models.Project, error) {
 // buildProjectModel maps the database model to the API response model
 func (r repository) buildProjectModel(dbModel DBProjectModel) *models.Project {
 	return &models.Project{
-		ProjectID:               dbModel.ProjectID,
-		ProjectExternalID:       dbModel.ProjectExternalID,
-		ProjectName:             dbModel.ProjectName,
-		ProjectACL:              dbModel.ProjectACL,
-		ProjectCCLAEnabled:      dbModel.ProjectCclaEnabled,
-		ProjectICLAEnabled:      dbModel.ProjectIclaEnabled,
-		ProjectCCLARequiresICLA: dbModel.ProjectCclaRequiresIclaSignature,
-		DateCreated:             dbModel.DateCreated,
-		DateModified:            dbModel.DateModified,
-		Version:                 dbModel.Version,
+		ProjectID:                  dbModel.ProjectID,
+		ProjectExternalID:          dbModel.ProjectExternalID,
+		ProjectName:                dbModel.ProjectName,
+		ProjectACL:                 dbModel.ProjectACL,
+		ProjectCCLAEnabled:         dbModel.ProjectCclaEnabled,
+		ProjectICLAEnabled:         dbModel.ProjectIclaEnabled,
+		ProjectCCLARequiresICLA:    dbModel.ProjectCclaRequiresIclaSignature,
+		ProjectResignRequired:      dbModel.ProjectResignRequired,
+		ProjectCorporateDocuments:  buildProjectDocumentModels(dbModel.ProjectCorporateDocuments),
+		ProjectIndividualDocuments: buildProjectDocumentModels(dbModel.ProjectIndividualDocuments),
+		DateCreated:                dbModel.DateCreated,
+		DateModified:               dbModel.DateModified,
+		Version:                    dbModel.Version,
 	}
 }
 
-// UpdateDynamoContractGroupTemplates updates the templates in the data store
-func (r repository) UpdateDynamoContractGroupTemplates(ctx context.Context, ContractGroupID string, template models.Template, pdfUrls models.TemplatePdfs, projectCCLAEnabled, projectICLAEnabled bool) error {
+// buildProjectDocumentModels maps the database document models to the API response models
+func buildProjectDocumentModels(dbDocumentModels []DBProjectDocumentModel) []models.ProjectDocument {
+	var response []models.ProjectDocument
+	for _, dbDocumentModel := range dbDocumentModels {
+		response = append(response, models.ProjectDocument{
+			DocumentName:         dbDocumentModel.DocumentName,
+			DocumentFileID:       dbDocumentModel.DocumentFileID,
+			DocumentS3URL:        dbDocumentModel.DocumentS3URL,
+			DocumentMajorVersion: dbDocumentModel.DocumentMajorVersion,
+			DocumentMinorVersion: dbDocumentModel.DocumentMinorVersion,
+			DocumentCreationDate: dbDocumentModel.DocumentCreationDate,
+			DocumentLocale:       dbDocumentModel.DocumentLocale,
+			DocumentTranslations: buildProjectDocumentTranslationModels(dbDocumentModel.DocumentTranslations),
+		})
+	}
+	return response
+}
+
+// buildProjectDocumentTranslationModels maps the database document translation models to the API response models
+func buildProjectDocumentTranslationModels(dbTranslationModels []DBProjectDocumentTranslationModel) []models.ProjectDocumentTranslation {
+	var response []models.ProjectDocumentTranslation
+	for _, dbTranslationModel := range dbTranslationModels {
+		response = append(response, models.ProjectDocumentTranslation{
+			Locale:        dbTranslationModel.Locale,
+			DocumentS3URL: dbTranslationModel.DocumentS3URL,
+		})
+	}
+	return response
+}
+
+// UpdateDynamoContractGroupTemplates appends the new documents to the CLA Group documents, the nil documents aren't
+// updated. The previous documents are kept as the history of the CLA Group documents.
+func (r repository) UpdateDynamoContractGroupTemplates(ctx context.Context, ContractGroupID string, cclaDocument, iclaDocument *DynamoProjectDocument) error {
 	tableName := fmt.Sprintf("cla-%s-projects", r.stage)
 	// Find Contract Group to update the Templates on
 	key := map[string]*dynamodb.AttributeValue{
@@ -179,54 +252,10 @@ func (r repository) UpdateDynamoContractGroupTemplates(ctx context.Context, Cont
 		},
 	}
 
-	if projectCCLAEnabled {
-		// Map the fields to the dynamo model as the attribute names are different
-		// Map Template Fields into DocumentTab
-		cclaDocumentTabs := []DocumentTab{}
-
-		for _, field := range template.CclaFields {
-			dynamoTab := DocumentTab{
-				DocumentTabType:                     field.FieldType,
-				DocumentTabID:                       field.ID,
-				DocumentTabPage:                     1,
-				DocumentTabName:                     field.Name,
-				DocumentTabWidth:                    field.Width,
-				DocumentTabHeight:                   field.Height,
-				DocumentTabIsLocked:                 field.IsEditable,
-				DocumentTabAnchorString:             field.AnchorString,
-				DocumentTabIsRequired:               field.IsOptional,
-				DocumentTabAnchorIgnoreIfNotPresent: field.IsOptional,
-				DocumentTabAnchorXOffset:            field.OffsetX,
-				DocumentTabAnchorYOffset:            field.OffsetY,
-				DocumentTabPositionX:                0,
-				DocumentTabPositionY:                0,
-			}
-			cclaDocumentTabs = append(cclaDocumentTabs, dynamoTab)
-		}
-
-		currentTime := time.Now().Format(time.RFC3339)
-
-		// Map CCLA Template to Document
-		dynamoCorporateProjectDocument := DynamoProjectDocument{
-			DocumentName:            template.Name,
-			DocumentFileID:          template.ID,
-			DocumentContentType:     "storage+pdf",
-			DocumentMajorVersion:    2,
-			DocumentMinorVersion:    0,
-			DocumentCreationDate:    currentTime,
-			DocumentPreamble:        template.Name,
-			DocumentLegalEntityName: template.Name,
-			DocumentAuthorName:      template.Name,
-			DocumentS3URL:           pdfUrls.CorporatePDFURL,
-			DocumentTabs:            cclaDocumentTabs,
-		}
-
+	if cclaDocument != nil {
 		// project_corporate_documents is a List type, and thus the item needs to be in a slice
-		dynamoCorporateProjectDocuments := []DynamoProjectDocument{}
-		dynamoCorporateProjectDocuments = append(dynamoCorporateProjectDocuments, dynamoCorporateProjectDocument)
-
 		dynamoCorporateProject := DynamoProjectCorporateDocuments{
-			DynamoProjectDocument: dynamoCorporateProjectDocuments,
+			DynamoProjectDocument: []DynamoProjectDocument{*cclaDocument},
 		}
 
 		// Marshal object into dynamodb attribute
@@ -234,6 +263,7 @@ func (r repository) UpdateDynamoContractGroupTemplates(ctx context.Context, Cont
 		if err != nil {
 			return err
 		}
+		expr[":empty_list"] = &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}
 
 		log.Debugf("Updating table %s with corporate template details - CLA Group id: %s.", tableName, ContractGroupID)
 		input := &dynamodb.UpdateItemInput{
@@ -241,82 +271,40 @@ func (r repository) UpdateDynamoContractGroupTemplates(ctx context.Context, Cont
 			TableName:                 aws.String(tableName),
 			Key:                       key,
 			ReturnValues:              aws.String("UPDATED_NEW"),
-			// UpdateExpression:          aws.String("set project_corporate_documents =  list_append(project_corporate_documents, :project_corporate_documents)"),
-			UpdateExpression: aws.String("set project_corporate_documents =  :project_corporate_documents"),
+			UpdateExpression:          aws.String("set project_corporate_documents = list_append(if_not_exists(project_corporate_documents, :empty_list), :project_corporate_documents)"),
 		}
 
 		_, err = r.dynamoDBClient.UpdateItem(input)
 		if err != nil {
-			log.Warnf("Error updating the CLA Group corporate document with template from: %s, error: %+v", template.Name, err)
+			log.Warnf("Error updating the CLA Group corporate document with template from: %s, error: %+v", cclaDocument.DocumentName, err)
 			return err
 		}
 	}
 
-	if projectICLAEnabled {
-		// Map ICLA Template Fields into DocumentTab
-		iclaDocumentTabs := []DocumentTab{}
-
-		for _, field := range template.IclaFields {
-			dynamoTab := DocumentTab{
-				DocumentTabType:                     field.FieldType,
-				DocumentTabID:                       field.ID,
-				DocumentTabPage:                     1,
-				DocumentTabName:                     field.Name,
-				DocumentTabWidth:                    field.Width,
-				DocumentTabHeight:                   field.Height,
-				DocumentTabIsLocked:                 field.IsEditable,
-				DocumentTabIsRequired:               field.IsOptional,
-				DocumentTabAnchorString:             field.AnchorString,
-				DocumentTabAnchorIgnoreIfNotPresent: field.IsOptional,
-				DocumentTabAnchorXOffset:            field.OffsetX,
-				DocumentTabAnchorYOffset:            field.OffsetY,
-				DocumentTabPositionX:                0,
-				DocumentTabPositionY:                0,
-			}
-			iclaDocumentTabs = append(iclaDocumentTabs, dynamoTab)
-		}
-
-		currentTime := time.Now().Format(time.RFC3339)
-		// Map Template to Document
-		dynamoIndividualDocument := DynamoProjectDocument{
-			DocumentName:            template.Name,
-			DocumentFileID:          template.ID,
-			DocumentContentType:     "storage+pdf",
-			DocumentMajorVersion:    2,
-			DocumentMinorVersion:    0,
-			DocumentCreationDate:    currentTime,
-			DocumentPreamble:        template.Name,
-			DocumentLegalEntityName: template.Name,
-			DocumentAuthorName:      template.Name,
-			DocumentS3URL:           pdfUrls.IndividualPDFURL,
-			DocumentTabs:            iclaDocumentTabs,
-		}
-
-		dynamoProjectIndividualDocuments := []DynamoProjectDocument{}
-		dynamoProjectIndividualDocuments = append(dynamoProjectIndividualDocuments, dynamoIndividualDocument)
-
+	if iclaDocument != nil {
 		dynamoIndividualProject := DynamoProjectIndividualDocuments{
-			DynamoProjectDocument: dynamoProjectIndividualDocuments,
+			DynamoProjectDocument: []DynamoProjectDocument{*iclaDocument},
 		}
 
 		expr, err := dynamodbattribute.MarshalMap(dynamoIndividualProject)
 		if err != nil {
-			log.Warnf("Error updating the CLA Group individual document with template from: %s, error: %+v", template.Name, err)
+			log.Warnf("Error updating the CLA Group individual document with template from: %s, error: %+v", iclaDocument.DocumentName, err)
 			return err
 		}
+		expr[":empty_list"] = &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}
 
 		input := &dynamodb.UpdateItemInput{
 			ExpressionAttributeValues: expr,
 			TableName:                 aws.String(tableName),
 			Key:                       key,
 			ReturnValues:              aws.String("UPDATED_NEW"),
-			UpdateExpression:          aws.String("set project_individual_documents =  list_append(project_individual_documents, :project_individual_documents)"),
+			UpdateExpression:          aws.String("set project_individual_documents = list_append(if_not_exists(project_individual_documents, :empty_list), :project_individual_documents)"),
 		}
 
 		log.Debugf("Updating table %s with individual template details - CLA Group id: %s.", tableName, ContractGroupID)
 		_, err = r.dynamoDBClient.UpdateItem(input)
 		if err != nil {
-			log.Warnf("Error updating the CLA Group individual document with template from: %s, error: %+v", template.Name, err)
+			log.Warnf("Error updating the CLA Group individual document with template from: %s, error: %+v", iclaDocument.DocumentName, err)
 			return err
 		}
 
@@ -324,6 +312,249 @@ func (r repository) UpdateDynamoContractGroupTemplates(ctx context.Context, Cont
 	return nil
 }
 
+// GetCLAGroupDocuments returns the documents of the document type of the CLA Group, in the order they were published
+func (r repository) GetCLAGroupDocuments(claGroupID string, documentType string) ([]DynamoProjectDocument, error) {
+	attributeName, err := documentsAttributeName(documentType)
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(fmt.Sprintf("cla-%s-projects", r.stage)),
+		Key: map[string]*dynamodb.AttributeValue{
+			"project_id": {
+				S: aws.String(claGroupID),
+			},
+		},
+		ProjectionExpression: aws.String(attributeName),
+	})
+	if err != nil {
+		log.Warnf("error retrieving the %s documents of CLA Group: %s, error: %v", documentType, claGroupID, err)
+		return nil, err
+	}
+	var documents []DynamoProjectDocument
+	if av, ok := result.Item[attributeName]; ok {
+		if err = dynamodbattribute.Unmarshal(av, &documents); err != nil {
+			return nil, err
+		}
+	}
+	return documents, nil
+}
+
+// CreateCustomTemplateVersion stores the new version of the custom template, ErrTemplateVersionConflict if the version
+// already exists
+func (r repository) CreateCustomTemplateVersion(template *DBTemplateModel) error {
+	av, err := dynamodbattribute.MarshalMap(template)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(r.templatesTable),
+		ConditionExpression: aws.String("attribute_not_exists(template_id)"),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrTemplateVersionConflict
+		}
+		log.Warnf("unable to create version %d of template ID: %s, error: %v", template.Version, template.TemplateID, err)
+		return err
+	}
+	return nil
+}
+
+// GetCustomTemplate returns the version of the custom template, the latest version when the version is zero
+func (r repository) GetCustomTemplate(templateID string, version int64) (models.Template, error) {
+	if version == 0 {
+		versions, err := r.queryCustomTemplateVersions(templateID, true)
+		if err != nil {
+			return models.Template{}, err
+		}
+		return versions[0].toModel(), nil
+	}
+
+	result, err := r.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"template_id": {S: aws.String(templateID)},
+			"version":     {N: aws.String(strconv.FormatInt(version, 10))},
+		},
+		TableName: aws.String(r.templatesTable),
+	})
+	if err != nil {
+		log.Warnf("error retrieving version %d of template ID: %s, error: %v", version, templateID, err)
+		return models.Template{}, err
+	}
+	if len(result.Item) == 0 {
+		return models.Template{}, ErrTemplateNotFound
+	}
+
+	var dbModel DBTemplateModel
+	err = dynamodbattribute.UnmarshalMap(result.Item, &dbModel)
+	if err != nil {
+		log.Warnf("error unmarshalling version %d of template ID: %s, error: %v", version, templateID, err)
+		return models.Template{}, err
+	}
+	return dbModel.toModel(), nil
+}
+
+// GetCustomTemplateVersions returns the versions of the custom template, oldest first
+func (r repository) GetCustomTemplateVersions(templateID string) ([]models.Template, error) {
+	versions, err := r.queryCustomTemplateVersions(templateID, false)
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]models.Template, 0, len(versions))
+	for _, version := range versions {
+		templates = append(templates, version.toModel())
+	}
+	return templates, nil
+}
+
+// queryCustomTemplateVersions returns the versions of the custom template, only the latest version when latestOnly is
+// set, ErrTemplateNotFound if the template has no version
+func (r repository) queryCustomTemplateVersions(templateID string, latestOnly bool) ([]DBTemplateModel, error) {
+	condition := expression.Key("template_id").Equal(expression.Value(templateID))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.Warnf("error building expression for template query, template ID: %s, error: %v", templateID, err)
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(r.templatesTable),
+	}
+	if latestOnly {
+		queryInput.ScanIndexForward = aws.Bool(false)
+		queryInput.Limit = aws.Int64(1)
+	}
+
+	var versions []DBTemplateModel
+	for {
+		results, queryErr := r.dynamoDBClient.Query(queryInput)
+		if queryErr != nil {
+			log.Warnf("error retrieving the versions of template ID: %s, error: %v", templateID, queryErr)
+			return nil, queryErr
+		}
+
+		var page []DBTemplateModel
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &page)
+		if err != nil {
+			log.Warnf("error unmarshalling the versions of template ID: %s, error: %v", templateID, err)
+			return nil, err
+		}
+		versions = append(versions, page...)
+
+		if latestOnly || len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	if len(versions) == 0 {
+		return nil, ErrTemplateNotFound
+	}
+	return versions, nil
+}
+
+// GetCustomTemplates returns the latest version of each custom template
+func (r repository) GetCustomTemplates() ([]models.Template, error) {
+	scanInput := &dynamodb.ScanInput{
+		TableName: aws.String(r.templatesTable),
+	}
+
+	var versions []DBTemplateModel
+	for {
+		results, scanErr := r.dynamoDBClient.Scan(scanInput)
+		if scanErr != nil {
+			log.Warnf("error scanning the custom templates, error: %v", scanErr)
+			return nil, scanErr
+		}
+
+		var page []DBTemplateModel
+		if err := dynamodbattribute.UnmarshalListOfMaps(results.Items, &page); err != nil {
+			log.Warnf("error unmarshalling the custom templates, error: %v", err)
+			return nil, err
+		}
+		versions = append(versions, page...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return latestTemplateVersions(versions), nil
+}
+
+// DeleteCustomTemplate deletes every version of the custom template
+func (r repository) DeleteCustomTemplate(templateID string) error {
+	versions, err := r.queryCustomTemplateVersions(templateID, false)
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		_, err = r.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+			Key: map[string]*dynamodb.AttributeValue{
+				"template_id": {S: aws.String(templateID)},
+				"version":     {N: aws.String(strconv.FormatInt(version.Version, 10))},
+			},
+			TableName: aws.String(r.templatesTable),
+		})
+		if err != nil {
+			log.Warnf("unable to delete version %d of template ID: %s, error: %v", version.Version, templateID, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// buildProjectDocument maps the template and the template fields into the CLA Group document model with the specified
+// major version
+func buildProjectDocument(template models.Template, documentS3URL string, fields []*models.Field, majorVersion, minorVersion int) DynamoProjectDocument {
+	currentTime := time.Now().Format(time.RFC3339)
+	return DynamoProjectDocument{
+		DocumentName:            template.Name,
+		DocumentFileID:          template.ID,
+		DocumentContentType:     "storage+pdf",
+		DocumentMajorVersion:    majorVersion,
+		DocumentMinorVersion:    minorVersion,
+		DocumentCreationDate:    currentTime,
+		DocumentPreamble:        template.Name,
+		DocumentLegalEntityName: template.Name,
+		DocumentAuthorName:      template.Name,
+		DocumentS3URL:           documentS3URL,
+		DocumentTabs:            buildDocumentTabs(fields),
+	}
+}
+
+// buildDocumentTabs maps the template fields into the DocuSign tabs of the document
+func buildDocumentTabs(fields []*models.Field) []DocumentTab {
+	documentTabs := []DocumentTab{}
+	for _, field := range fields {
+		dynamoTab := DocumentTab{
+			DocumentTabType:                     field.FieldType,
+			DocumentTabID:                       field.ID,
+			DocumentTabPage:                     1,
+			DocumentTabName:                     field.Name,
+			DocumentTabWidth:                    field.Width,
+			DocumentTabHeight:                   field.Height,
+			DocumentTabIsLocked:                 field.IsEditable,
+			DocumentTabAnchorString:             field.AnchorString,
+			DocumentTabIsRequired:               field.IsOptional,
+			DocumentTabAnchorIgnoreIfNotPresent: field.IsOptional,
+			DocumentTabAnchorXOffset:            field.OffsetX,
+			DocumentTabAnchorYOffset:            field.OffsetY,
+			DocumentTabPositionX:                0,
+			DocumentTabPositionY:                0,
+		}
+		documentTabs = append(documentTabs, dynamoTab)
+	}
+	return documentTabs
+}
+
 // templateMap contains a list of our template models
 var templateMap = map[string]models.Template{
 	ApacheStyleTemplateID: {
@@ -0,0 +1,181 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+)
+
+// memoryRepository shares the built-in template lookups with the DynamoDB repository and keeps the CLA Group
+// documents in the in-memory store
+type memoryRepository struct {
+	repository
+	store          *storage.MemoryStore
+	tableName      string
+	templatesTable string
+}
+
+// NewMemoryRepository creates a new instance of the repository service backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string) Repository {
+	return memoryRepository{
+		repository:     repository{stage: stage},
+		store:          store,
+		tableName:      fmt.Sprintf("cla-%s-projects", stage),
+		templatesTable: fmt.Sprintf("cla-%s-templates", stage),
+	}
+}
+
+// GetCLAGroup returns the CLA Group model
+func (r memoryRepository) GetCLAGroup(claGroupID string) (*models.Project, error) {
+	log.Debugf("GetCLAGroup - claGroupID: %s", claGroupID)
+	var dbModel DBProjectModel
+	_, err := r.store.Get(r.tableName, claGroupID, &dbModel)
+	if err != nil {
+		log.Warnf("error unmarshalling db project model, error: %+v", err)
+		return nil, err
+	}
+	return r.buildProjectModel(dbModel), nil
+}
+
+// UpdateDynamoContractGroupTemplates appends the new documents to the CLA Group documents, the nil documents aren't
+// updated
+func (r memoryRepository) UpdateDynamoContractGroupTemplates(ctx context.Context, ContractGroupID string, cclaDocument, iclaDocument *DynamoProjectDocument) error {
+	return r.store.UpdateItem(r.tableName, ContractGroupID, func(item map[string]*dynamodb.AttributeValue) error {
+		for attributeName, document := range map[string]*DynamoProjectDocument{
+			"project_corporate_documents":  cclaDocument,
+			"project_individual_documents": iclaDocument,
+		} {
+			if document == nil {
+				continue
+			}
+			av, err := dynamodbattribute.Marshal(document)
+			if err != nil {
+				log.Warnf("Error updating the CLA Group documents with template from: %s, error: %+v", document.DocumentName, err)
+				return err
+			}
+			existing, ok := item[attributeName]
+			if !ok || existing.L == nil {
+				existing = &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}
+			}
+			existing.L = append(existing.L, av)
+			item[attributeName] = existing
+		}
+		return nil
+	})
+}
+
+// GetCLAGroupDocuments returns the documents of the document type of the CLA Group, in the order they were published
+func (r memoryRepository) GetCLAGroupDocuments(claGroupID string, documentType string) ([]DynamoProjectDocument, error) {
+	if _, err := documentsAttributeName(documentType); err != nil {
+		return nil, err
+	}
+	var dbModel struct {
+		ProjectCorporateDocuments  []DynamoProjectDocument `json:"project_corporate_documents"`
+		ProjectIndividualDocuments []DynamoProjectDocument `json:"project_individual_documents"`
+	}
+	if _, err := r.store.Get(r.tableName, claGroupID, &dbModel); err != nil {
+		return nil, err
+	}
+	if documentType == DocumentTypeCCLA {
+		return dbModel.ProjectCorporateDocuments, nil
+	}
+	return dbModel.ProjectIndividualDocuments, nil
+}
+
+// templateVersionKey returns the store key of the custom template version
+func templateVersionKey(templateID string, version int64) string {
+	return fmt.Sprintf("%s#%d", templateID, version)
+}
+
+// CreateCustomTemplateVersion stores the new version of the custom template
+func (r memoryRepository) CreateCustomTemplateVersion(template *DBTemplateModel) error {
+	err := r.store.Create(r.templatesTable, templateVersionKey(template.TemplateID, template.Version), template)
+	if err == storage.ErrItemAlreadyExists {
+		return ErrTemplateVersionConflict
+	}
+	return err
+}
+
+// GetCustomTemplate returns the version of the custom template, the latest version when the version is zero
+func (r memoryRepository) GetCustomTemplate(templateID string, version int64) (models.Template, error) {
+	versions, err := r.customTemplateVersions(templateID)
+	if err != nil {
+		return models.Template{}, err
+	}
+	if version == 0 {
+		return versions[len(versions)-1].toModel(), nil
+	}
+	for _, v := range versions {
+		if v.Version == version {
+			return v.toModel(), nil
+		}
+	}
+	return models.Template{}, ErrTemplateNotFound
+}
+
+// GetCustomTemplateVersions returns the versions of the custom template, oldest first
+func (r memoryRepository) GetCustomTemplateVersions(templateID string) ([]models.Template, error) {
+	versions, err := r.customTemplateVersions(templateID)
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]models.Template, 0, len(versions))
+	for _, version := range versions {
+		templates = append(templates, version.toModel())
+	}
+	return templates, nil
+}
+
+// customTemplateVersions returns the versions of the custom template, oldest first, ErrTemplateNotFound if the
+// template has no version
+func (r memoryRepository) customTemplateVersions(templateID string) ([]DBTemplateModel, error) {
+	var all []DBTemplateModel
+	if err := r.store.Scan(r.templatesTable, &all); err != nil {
+		return nil, err
+	}
+	var versions []DBTemplateModel
+	for _, version := range all {
+		if version.TemplateID == templateID {
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, ErrTemplateNotFound
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+	return versions, nil
+}
+
+// GetCustomTemplates returns the latest version of each custom template
+func (r memoryRepository) GetCustomTemplates() ([]models.Template, error) {
+	var versions []DBTemplateModel
+	if err := r.store.Scan(r.templatesTable, &versions); err != nil {
+		return nil, err
+	}
+	return latestTemplateVersions(versions), nil
+}
+
+// DeleteCustomTemplate deletes every version of the custom template
+func (r memoryRepository) DeleteCustomTemplate(templateID string) error {
+	versions, err := r.customTemplateVersions(templateID)
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		if err = r.store.Delete(r.templatesTable, templateVersionKey(templateID, version.Version)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
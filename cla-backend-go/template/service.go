@@ -9,48 +9,71 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"strconv"
+	"strings"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
-	"github.com/communitybridge/easycla/cla-backend-go/docraptor"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/pdf"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aymerick/raymond"
 )
 
 // Service interface
 type Service interface {
 	GetTemplates(ctx context.Context) ([]models.Template, error)
-	CreateCLAGroupTemplate(ctx context.Context, claGroupID string, claGroupFields *models.CreateClaGroupTemplate) (models.TemplatePdfs, error)
-	CreateTemplatePreview(claGroupFields *models.CreateClaGroupTemplate, templateFor string) ([]byte, error)
+	GetTemplate(ctx context.Context, templateID string, version int64) (models.Template, error)
+	GetTemplateVersions(ctx context.Context, templateID string) ([]models.Template, error)
+	CreateTemplate(ctx context.Context, template *models.Template, createdBy string) (models.Template, error)
+	UpdateTemplate(ctx context.Context, templateID string, template *models.Template, createdBy string) (models.Template, error)
+	DeleteTemplate(ctx context.Context, templateID string) error
+	CreateCLAGroupTemplate(ctx context.Context, claGroupID string, claGroupFields *models.CreateClaGroupTemplate, publishedBy string) (models.TemplatePdfs, error)
+	GetCLAGroupDocumentHistory(ctx context.Context, claGroupID string, documentType string) ([]DocumentVersion, error)
+	DiffCLAGroupDocuments(ctx context.Context, claGroupID string, documentType string, fromVersion, toVersion string) (string, error)
+	RollbackCLAGroupDocument(ctx context.Context, claGroupID string, documentType string, version string, publishedBy string) (*DocumentVersion, error)
+	CreateTemplatePreview(claGroupFields *models.CreateClaGroupTemplate, templateFor string, locale string) ([]byte, error)
+}
+
+// ResignCampaignStarter is notified when a template creates a new major version of existing CLA Group documents, a
+// zero major version means the document type was not updated
+type ResignCampaignStarter interface {
+	StartCampaign(claGroupID string, iclaMajorVersion, cclaMajorVersion int) error
 }
 
 type service struct {
 	stage           string // The AWS stage (dev, staging, prod)
 	templateRepo    Repository
-	docraptorClient docraptor.Client
-	s3Client        *s3manager.Uploader
+	pdfRenderer     pdf.Renderer
+	blobStore       storage.BlobStore
+	resignCampaigns ResignCampaignStarter
 }
 
 // NewService API call
-func NewService(stage string, templateRepo Repository, docraptorClient docraptor.Client, awsSession *session.Session) service {
+func NewService(stage string, templateRepo Repository, pdfRenderer pdf.Renderer, blobStore storage.BlobStore, resignCampaigns ResignCampaignStarter) service {
 	return service{
 		stage:           stage,
 		templateRepo:    templateRepo,
-		docraptorClient: docraptorClient,
-		s3Client:        s3manager.NewUploader(awsSession),
+		pdfRenderer:     pdfRenderer,
+		blobStore:       blobStore,
+		resignCampaigns: resignCampaigns,
 	}
 }
 
-// GetTemplates API call
+// GetTemplates API call, returns the built-in templates and the latest version of the custom templates
 func (s service) GetTemplates(ctx context.Context) ([]models.Template, error) {
 	templates, err := s.templateRepo.GetTemplates()
 	if err != nil {
 		return nil, err
 	}
+	customTemplates, err := s.templateRepo.GetCustomTemplates()
+	if err != nil {
+		return nil, err
+	}
+	templates = append(templates, customTemplates...)
 
 	// Remove HTML from template
 	for i, template := range templates {
@@ -62,12 +85,115 @@ func (s service) GetTemplates(ctx context.Context) ([]models.Template, error) {
 	return templates, nil
 }
 
-func (s service) CreateTemplatePreview(claGroupFields *models.CreateClaGroupTemplate, templateFor string) ([]byte, error) {
+// GetTemplate returns the built-in template or the version of the custom template, the latest version when the version
+// is zero
+func (s service) GetTemplate(ctx context.Context, templateID string, version int64) (models.Template, error) {
+	return s.lookupTemplate(templateID, version)
+}
+
+// lookupTemplate returns the built-in template or the version of the custom template
+func (s service) lookupTemplate(templateID string, version int64) (models.Template, error) {
+	template, err := s.templateRepo.GetTemplate(templateID)
+	if err == nil || err != ErrTemplateNotFound {
+		return template, err
+	}
+	return s.templateRepo.GetCustomTemplate(templateID, version)
+}
+
+// GetTemplateVersions returns the versions of the custom template without the HTML bodies, oldest first
+func (s service) GetTemplateVersions(ctx context.Context, templateID string) ([]models.Template, error) {
+	if _, err := s.templateRepo.GetTemplate(templateID); err == nil {
+		return nil, ErrBuiltInTemplate
+	}
+	versions, err := s.templateRepo.GetCustomTemplateVersions(templateID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range versions {
+		versions[i].IclaHTMLBody = ""
+		versions[i].CclaHTMLBody = ""
+		for j, variant := range versions[i].LocaleVariants {
+			versions[i].LocaleVariants[j] = &models.TemplateLocaleVariant{Locale: variant.Locale}
+		}
+	}
+	return versions, nil
+}
+
+// CreateTemplate validates and stores the first version of a custom template
+func (s service) CreateTemplate(ctx context.Context, template *models.Template, createdBy string) (models.Template, error) {
+	if err := ValidateTemplate(template); err != nil {
+		return models.Template{}, err
+	}
+	templateID, err := uuid.NewV4()
+	if err != nil {
+		return models.Template{}, err
+	}
+	_, now := utils.CurrentTime()
+	dbModel := newDBTemplateModel(templateID.String(), 1, template, createdBy, now)
+	if err = s.templateRepo.CreateCustomTemplateVersion(dbModel); err != nil {
+		return models.Template{}, err
+	}
+	return dbModel.toModel(), nil
+}
+
+// UpdateTemplate validates and stores a new version of the custom template, the previous versions are kept
+func (s service) UpdateTemplate(ctx context.Context, templateID string, template *models.Template, createdBy string) (models.Template, error) {
+	if _, err := s.templateRepo.GetTemplate(templateID); err == nil {
+		return models.Template{}, ErrBuiltInTemplate
+	}
+	latest, err := s.templateRepo.GetCustomTemplate(templateID, 0)
+	if err != nil {
+		return models.Template{}, err
+	}
+	if err = ValidateTemplate(template); err != nil {
+		return models.Template{}, err
+	}
+	dbModel := newDBTemplateModel(templateID, latest.Version+1, template, createdBy, latest.DateCreated)
+	_, dbModel.DateModified = utils.CurrentTime()
+	if err = s.templateRepo.CreateCustomTemplateVersion(dbModel); err != nil {
+		return models.Template{}, err
+	}
+	return dbModel.toModel(), nil
+}
+
+// DeleteTemplate deletes every version of the custom template
+func (s service) DeleteTemplate(ctx context.Context, templateID string) error {
+	if _, err := s.templateRepo.GetTemplate(templateID); err == nil {
+		return ErrBuiltInTemplate
+	}
+	return s.templateRepo.DeleteCustomTemplate(templateID)
+}
+
+// newDBTemplateModel returns the database model of the template version, created on the date
+func newDBTemplateModel(templateID string, version int64, template *models.Template, createdBy string, dateCreated string) *DBTemplateModel {
+	return &DBTemplateModel{
+		TemplateID:   templateID,
+		Version:      version,
+		Name:         strings.TrimSpace(template.Name),
+		Description:  template.Description,
+		IclaHTMLBody: template.IclaHTMLBody,
+		CclaHTMLBody: template.CclaHTMLBody,
+		MetaFields:   template.MetaFields,
+		IclaFields:   template.IclaFields,
+		CclaFields:   template.CclaFields,
+		CreatedBy:    createdBy,
+		DateCreated:  dateCreated,
+		DateModified: dateCreated,
+
+		Locale:               template.Locale,
+		LegallyBindingLocale: template.LegallyBindingLocale,
+		LocaleVariants:       template.LocaleVariants,
+	}
+}
+
+// CreateTemplatePreview renders the ICLA or the CCLA of the template in the locale, the legally binding locale of the
+// template when the locale is empty
+func (s service) CreateTemplatePreview(claGroupFields *models.CreateClaGroupTemplate, templateFor string, locale string) ([]byte, error) {
 	var template models.Template
 	var err error
 	if claGroupFields.TemplateID != "" {
 		// Get Template
-		template, err = s.templateRepo.GetTemplate(claGroupFields.TemplateID)
+		template, err = s.lookupTemplate(claGroupFields.TemplateID, claGroupFields.TemplateVersion)
 		if err != nil {
 			log.Warnf("Unable to fetch template fields: %s, error: %v",
 				claGroupFields.TemplateID, err)
@@ -83,6 +209,12 @@ func (s service) CreateTemplatePreview(claGroupFields *models.CreateClaGroupTemp
 		}
 	}
 
+	template, err = localizeTemplate(template, locale)
+	if err != nil {
+		log.Warnf("Unable to preview the template: %s in the locale: %s, error: %v", template.ID, locale, err)
+		return nil, err
+	}
+
 	// Apply template fields
 	iclaTemplateHTML, cclaTemplateHTML, err := s.InjectProjectInformationIntoTemplate(template, claGroupFields.MetaFields)
 	if err != nil {
@@ -98,16 +230,16 @@ func (s service) CreateTemplatePreview(claGroupFields *models.CreateClaGroupTemp
 	default:
 		return nil, errors.New("invalid value of template_for")
 	}
-	pdf, err := s.docraptorClient.CreatePDF(templateHTML)
+	pdfFile, err := s.pdfRenderer.CreatePDF(templateHTML)
 	if err != nil {
 		return nil, err
 	}
-	defer pdf.Close()
-	return ioutil.ReadAll(pdf)
+	defer pdfFile.Close()
+	return ioutil.ReadAll(pdfFile)
 }
 
 // CreateCLAGroupTemplate
-func (s service) CreateCLAGroupTemplate(ctx context.Context, claGroupID string, claGroupFields *models.CreateClaGroupTemplate) (models.TemplatePdfs, error) {
+func (s service) CreateCLAGroupTemplate(ctx context.Context, claGroupID string, claGroupFields *models.CreateClaGroupTemplate, publishedBy string) (models.TemplatePdfs, error) {
 	// Verify claGroupID matches an existing CLA Group
 	claGroup, err := s.templateRepo.GetCLAGroup(claGroupID)
 	if err != nil {
@@ -118,95 +250,224 @@ func (s service) CreateCLAGroupTemplate(ctx context.Context, claGroupID string,
 	// Verify the caller is authorized for the project that owns this CLA Group
 
 	// Get Template
-	template, err := s.templateRepo.GetTemplate(claGroupFields.TemplateID)
+	template, err := s.lookupTemplate(claGroupFields.TemplateID, claGroupFields.TemplateVersion)
 	if err != nil {
 		log.Warnf("Unable to fetch template fields: %s, error: %v - returning empty template PDFs",
 			claGroupFields.TemplateID, err)
 		return models.TemplatePdfs{}, err
 	}
+	// Custom templates may only provide one of the documents
+	if (claGroup.ProjectICLAEnabled && template.IclaHTMLBody == "") || (claGroup.ProjectCCLAEnabled && template.CclaHTMLBody == "") {
+		return models.TemplatePdfs{}, fmt.Errorf("bad request: template %s does not provide the documents enabled for the CLA Group", template.Name)
+	}
 
-	// Apply template fields
-	iclaTemplateHTML, cclaTemplateHTML, err := s.InjectProjectInformationIntoTemplate(template, claGroupFields.MetaFields)
+	// Apply template fields to each locale of the template
+	localizedDocuments, err := s.renderLocalizedDocuments(template, claGroupFields.MetaFields)
 	if err != nil {
 		log.Warnf("Unable to inject metadata details into template, error: %v - returning empty template PDFs", err)
 		return models.TemplatePdfs{}, err
 	}
 
-	bucket := fmt.Sprintf("cla-signature-files-%s", s.stage)
-	fileNameTemplate := "contract-group/%s/template/%s"
+	// A template update is a new major version of the CLA Group documents when the CLA Group requires re-signature,
+	// a new minor version otherwise
+	cclaMajorVersion, cclaMinorVersion := nextDocumentVersion(claGroup.ProjectCorporateDocuments, claGroup.ProjectResignRequired)
+	iclaMajorVersion, iclaMinorVersion := nextDocumentVersion(claGroup.ProjectIndividualDocuments, claGroup.ProjectResignRequired)
+	metaFields := documentMetaFields(template, claGroupFields.MetaFields)
 
 	// Create PDF
 	var pdfUrls models.TemplatePdfs
-	var iclaFileURL string
-	var cclaFileURL string
+	var iclaDocument, cclaDocument *DynamoProjectDocument
 
 	if claGroup.ProjectICLAEnabled {
-		iclaPdf, iclaErr := s.docraptorClient.CreatePDF(iclaTemplateHTML)
-		if iclaErr != nil {
-			log.Warnf("Problem generating ICLA template via docraptor client, error: %v - returning empty template PDFs", err)
-			return models.TemplatePdfs{}, err
-		}
-		defer func() {
-			closeErr := iclaPdf.Close()
-			if closeErr != nil {
-				log.Warnf("error closing ICLA PDF, error: %v", closeErr)
-			}
-		}()
-		iclaFileName := fmt.Sprintf(fileNameTemplate, claGroupID, "icla.pdf")
-		iclaFileURL, err = s.SaveTemplateToS3(bucket, iclaFileName, iclaPdf)
+		iclaDocument, err = s.publishLocalizedDocuments(claGroupID, DocumentTypeICLA, iclaMajorVersion, iclaMinorVersion, localizedDocuments)
 		if err != nil {
-			log.Warnf("Problem uploading ICLA PDF: %s to s3, error: %v - returning empty template PDFs", iclaFileName, err)
 			return models.TemplatePdfs{}, err
 		}
-		template.IclaHTMLBody = iclaTemplateHTML
+		pdfUrls.IndividualPDFURL = iclaDocument.DocumentS3URL
 	}
 
 	if claGroup.ProjectCCLAEnabled {
-		cclaPdf, cclaErr := s.docraptorClient.CreatePDF(cclaTemplateHTML)
-		if cclaErr != nil {
-			log.Warnf("Problem generating CCLA template via docraptor client, error: %v - returning empty template PDFs", err)
-			return models.TemplatePdfs{}, err
-		}
-		defer func() {
-			closeErr := cclaPdf.Close()
-			if closeErr != nil {
-				log.Warnf("error closing CCLA PDF, error: %v", closeErr)
-			}
-		}()
-		cclaFileName := fmt.Sprintf(fileNameTemplate, claGroupID, "ccla.pdf")
-		cclaFileURL, err = s.SaveTemplateToS3(bucket, cclaFileName, cclaPdf)
+		cclaDocument, err = s.publishLocalizedDocuments(claGroupID, DocumentTypeCCLA, cclaMajorVersion, cclaMinorVersion, localizedDocuments)
 		if err != nil {
-			log.Warnf("Problem uploading CCLA PDF: %s to s3, error: %v - returning empty template PDFs", cclaFileName, err)
 			return models.TemplatePdfs{}, err
 		}
-		template.CclaHTMLBody = cclaTemplateHTML
+		pdfUrls.CorporatePDFURL = cclaDocument.DocumentS3URL
 	}
 
-	if claGroup.ProjectICLAEnabled && claGroup.ProjectCCLAEnabled {
-		pdfUrls = models.TemplatePdfs{
-			IndividualPDFURL: iclaFileURL,
-			CorporatePDFURL:  cclaFileURL,
-		}
-	} else if claGroup.ProjectCCLAEnabled {
-		pdfUrls = models.TemplatePdfs{
-			CorporatePDFURL: cclaFileURL,
-		}
-	} else if claGroup.ProjectICLAEnabled {
-		pdfUrls = models.TemplatePdfs{
-			IndividualPDFURL: iclaFileURL,
+	for _, document := range []*DynamoProjectDocument{iclaDocument, cclaDocument} {
+		if document != nil {
+			document.DocumentTemplateVersion = template.Version
+			document.DocumentMetaFields = metaFields
+			document.DocumentPublishedBy = publishedBy
 		}
 	}
 
 	// Save Template to DynamoDB
-	err = s.templateRepo.UpdateDynamoContractGroupTemplates(ctx, claGroupID, template, pdfUrls, claGroup.ProjectCCLAEnabled, claGroup.ProjectICLAEnabled)
+	err = s.templateRepo.UpdateDynamoContractGroupTemplates(ctx, claGroupID, cclaDocument, iclaDocument)
 	if err != nil {
 		log.Warnf("Problem updating the database with ICLA/CCLA new PDF details, error: %v - returning empty template PDFs", err)
 		return models.TemplatePdfs{}, err
 	}
 
+	// Signatures of the previous major version need to be re-signed - only existing documents have signers
+	campaignICLAMajorVersion, campaignCCLAMajorVersion := 0, 0
+	if claGroup.ProjectResignRequired {
+		if claGroup.ProjectICLAEnabled && len(claGroup.ProjectIndividualDocuments) > 0 {
+			campaignICLAMajorVersion = iclaMajorVersion
+		}
+		if claGroup.ProjectCCLAEnabled && len(claGroup.ProjectCorporateDocuments) > 0 {
+			campaignCCLAMajorVersion = cclaMajorVersion
+		}
+	}
+	s.startResignCampaign(claGroupID, campaignICLAMajorVersion, campaignCCLAMajorVersion)
+
 	return pdfUrls, nil
 }
 
+// localizedDocument is the template localized in a locale, with the HTML of its documents
+type localizedDocument struct {
+	template models.Template
+	iclaHTML string
+	cclaHTML string
+}
+
+// renderLocalizedDocuments applies the template fields to each locale of the template, the legally binding locale
+// first
+func (s service) renderLocalizedDocuments(template models.Template, metaFields []*models.MetaField) ([]localizedDocument, error) {
+	var documents []localizedDocument
+	for _, locale := range templateLocales(template) {
+		localized, err := localizeTemplate(template, locale)
+		if err != nil {
+			return nil, err
+		}
+		iclaHTML, cclaHTML, err := s.InjectProjectInformationIntoTemplate(localized, metaFields)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, localizedDocument{template: localized, iclaHTML: iclaHTML, cclaHTML: cclaHTML})
+	}
+	return documents, nil
+}
+
+// publishLocalizedDocuments publishes the document in each locale. The document in the legally binding locale is the
+// CLA Group document, the documents in the other locales are its translations.
+func (s service) publishLocalizedDocuments(claGroupID string, documentType string, majorVersion, minorVersion int, localizedDocuments []localizedDocument) (*DynamoProjectDocument, error) {
+	var document DynamoProjectDocument
+	for i, localized := range localizedDocuments {
+		documentHTML, fields := localized.iclaHTML, localized.template.IclaFields
+		if documentType == DocumentTypeCCLA {
+			documentHTML, fields = localized.cclaHTML, localized.template.CclaFields
+		}
+		translation := localized.template.Locale
+		if i == 0 {
+			translation = ""
+		}
+		fileURL, err := s.publishDocument(claGroupID, documentType, majorVersion, minorVersion, translation, documentHTML)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			document = buildProjectDocument(localized.template, fileURL, fields, majorVersion, minorVersion)
+			document.DocumentLocale = localized.template.Locale
+			continue
+		}
+		document.DocumentTranslations = append(document.DocumentTranslations, DocumentTranslation{
+			Locale:        localized.template.Locale,
+			DocumentS3URL: fileURL,
+			DocumentTabs:  buildDocumentTabs(fields),
+		})
+	}
+	return &document, nil
+}
+
+// publishDocument generates the PDF of the document version and stores it with its HTML source, the source is kept
+// for the document history. The translations, identified by their locale, are stored next to the document in the
+// legally binding locale. Returns the URL of the PDF.
+func (s service) publishDocument(claGroupID string, documentType string, majorVersion, minorVersion int, translation string, documentHTML string) (string, error) {
+	blobKey := func(extension string) string {
+		if translation != "" {
+			return translationBlobKey(claGroupID, documentType, majorVersion, minorVersion, translation, extension)
+		}
+		return documentBlobKey(claGroupID, documentType, majorVersion, minorVersion, extension)
+	}
+	pdfFile, err := s.pdfRenderer.CreatePDF(documentHTML)
+	if err != nil {
+		log.Warnf("Problem generating %s template via the PDF renderer, error: %v - returning empty template PDFs", documentType, err)
+		return "", err
+	}
+	fileName := blobKey("pdf")
+	fileURL, err := s.SaveTemplateToS3(fileName, pdfFile)
+	if err != nil {
+		log.Warnf("Problem uploading %s PDF: %s to s3, error: %v - returning empty template PDFs", documentType, fileName, err)
+		return "", err
+	}
+
+	sourceName := blobKey("html")
+	err = s.blobStore.Put(sourceName, strings.NewReader(documentHTML), &storage.PutOptions{ContentType: "text/html"})
+	if err != nil {
+		log.Warnf("Problem uploading %s source: %s to s3, error: %v - returning empty template PDFs", documentType, sourceName, err)
+		return "", err
+	}
+	return fileURL, nil
+}
+
+// startResignCampaign starts the re-sign campaign of the new major versions, a zero major version means the document
+// type was not updated
+func (s service) startResignCampaign(claGroupID string, iclaMajorVersion, cclaMajorVersion int) {
+	if s.resignCampaigns == nil || (iclaMajorVersion == 0 && cclaMajorVersion == 0) {
+		return
+	}
+	// The documents are saved, problems starting the campaign are only logged
+	campaignErr := s.resignCampaigns.StartCampaign(claGroupID, iclaMajorVersion, cclaMajorVersion)
+	if campaignErr != nil {
+		log.Warnf("Problem starting the re-sign campaign for CLA Group: %s, error: %v", claGroupID, campaignErr)
+	}
+}
+
+// documentMetaFields returns the values of the template meta fields used to create the documents
+func documentMetaFields(template models.Template, metaFields []*models.MetaField) []DocumentMetaField {
+	values := map[string]string{}
+	for _, metaField := range metaFields {
+		values[metaField.TemplateVariable] = metaField.Value
+	}
+	var documentMetaFields []DocumentMetaField
+	for _, metaField := range template.MetaFields {
+		documentMetaFields = append(documentMetaFields, DocumentMetaField{
+			Name:             metaField.Name,
+			TemplateVariable: metaField.TemplateVariable,
+			Value:            values[metaField.TemplateVariable],
+		})
+	}
+	return documentMetaFields
+}
+
+// nextDocumentVersion returns the major and minor version of the next CLA Group document, the next major version when
+// newMajorVersion is set and the next minor version of the latest document otherwise. Templates start at major
+// version 2.0.
+func nextDocumentVersion(documents []models.ProjectDocument, newMajorVersion bool) (int, int) {
+	latestMajor, latestMinor := 0, 0
+	for _, document := range documents {
+		major, err := strconv.Atoi(document.DocumentMajorVersion)
+		if err != nil {
+			log.Warnf("invalid document major version: %s", document.DocumentMajorVersion)
+			continue
+		}
+		// documents without a valid minor version are the first version of their major version
+		minor, _ := strconv.Atoi(document.DocumentMinorVersion)
+		if major > latestMajor || (major == latestMajor && minor > latestMinor) {
+			latestMajor, latestMinor = major, minor
+		}
+	}
+	if latestMajor < 2 {
+		return 2, 0
+	}
+	if newMajorVersion {
+		return latestMajor + 1, 0
+	}
+	return latestMajor, latestMinor + 1
+}
+
 // InjectProjectInformationIntoTemplate
 func (s service) InjectProjectInformationIntoTemplate(template models.Template, metaFields []*models.MetaField) (string, string, error) {
 	lookupMap := map[string]models.MetaField{}
@@ -246,21 +507,17 @@ func (s service) InjectProjectInformationIntoTemplate(template models.Template,
 	return iclaTemplateHTML, cclaTemplateHTML, nil
 }
 
-// SaveTemplateToS3
-func (s service) SaveTemplateToS3(bucket, filepath string, template io.ReadCloser) (string, error) {
+// SaveTemplateToS3 stores the template PDF in the blob store, publicly readable, and returns its URL
+func (s service) SaveTemplateToS3(filepath string, template io.ReadCloser) (string, error) {
 	defer template.Close()
 
-	// Upload the file to S3.
-	result, err := s.s3Client.Upload(&s3manager.UploadInput{
-		Bucket:      aws.String(bucket),
-		Key:         aws.String(filepath),
-		Body:        template,
-		ACL:         aws.String("public-read"),
-		ContentType: aws.String("application/pdf"),
+	err := s.blobStore.Put(filepath, template, &storage.PutOptions{
+		ContentType: "application/pdf",
+		Public:      true,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to upload file to S3 Bucket: %s / %s, %v", bucket, filepath, err)
+		return "", fmt.Errorf("failed to upload file: %s, %v", filepath, err)
 	}
 
-	return result.Location, nil
+	return s.blobStore.PublicURL(filepath), nil
 }
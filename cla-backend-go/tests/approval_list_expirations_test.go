@@ -0,0 +1,164 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list_expirations"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalListExpirations(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	signaturesRepo := signatures.NewMemoryRepository(store, "test", company.NewMemoryRepository(store, "test"), users.NewMemoryRepository(store, "test"))
+
+	signatureID := "c71c469a-55ea-492d-9722-fd30b31da2aa"
+	assert.Nil(t, store.Put("cla-test-signatures", signatureID, signatures.ItemSignature{
+		SignatureID:            signatureID,
+		SignatureProjectID:     "cla-group-1234",
+		SignatureReferenceID:   "company-1234",
+		SignatureReferenceType: "company",
+		SignatureType:          "ccla",
+		SignatureSigned:        true,
+		SignatureApproved:      true,
+		EmailWhitelist:         []string{"employee@acme.com"},
+	}))
+
+	// Expirations may only be set on entries in the approval list
+	expiresAt := utils.TimeToString(time.Now().Add(48 * time.Hour))
+	_, err = signaturesRepo.UpdateApprovalList("cla-group-1234", "company-1234", &models.ApprovalList{
+		ApprovalListExpirations: []*models.ApprovalListExpiration{
+			{ListType: signatures.ApprovalListTypeEmail, Value: "contractor@acme.com", ExpirationDate: expiresAt},
+		},
+	})
+	assert.IsType(t, &signatures.BadRequestError{}, err)
+
+	// Expirations must be in the future
+	_, err = signaturesRepo.UpdateApprovalList("cla-group-1234", "company-1234", &models.ApprovalList{
+		AddEmailApprovalList: []string{"contractor@acme.com"},
+		ApprovalListExpirations: []*models.ApprovalListExpiration{
+			{ListType: signatures.ApprovalListTypeEmail, Value: "contractor@acme.com", ExpirationDate: "2020-01-01T00:00:00Z"},
+		},
+	})
+	assert.IsType(t, &signatures.BadRequestError{}, err)
+
+	sig, err := signaturesRepo.UpdateApprovalList("cla-group-1234", "company-1234", &models.ApprovalList{
+		AddEmailApprovalList: []string{"contractor@acme.com"},
+		ApprovalListExpirations: []*models.ApprovalListExpiration{
+			{ListType: signatures.ApprovalListTypeEmail, Value: "contractor@acme.com", ExpirationDate: expiresAt},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"employee@acme.com", "contractor@acme.com"}, sig.EmailApprovalList)
+	assert.Len(t, sig.ApprovalListExpirations, 1)
+
+	// The entry only stops granting coverage once the expiration date has passed
+	assert.False(t, signatures.IsApprovalListEntryExpired(sig, signatures.ApprovalListTypeEmail, "contractor@acme.com", time.Now()))
+	assert.True(t, signatures.IsApprovalListEntryExpired(sig, signatures.ApprovalListTypeEmail, "contractor@acme.com", time.Now().Add(72*time.Hour)))
+	assert.False(t, signatures.IsApprovalListEntryExpired(sig, signatures.ApprovalListTypeEmail, "employee@acme.com", time.Now().Add(72*time.Hour)))
+	assert.Len(t, signatures.ExpiredApprovalListEntries(sig, time.Now().Add(72*time.Hour)), 1)
+
+	expiring, err := signaturesRepo.GetSignaturesWithApprovalListExpirations()
+	assert.Nil(t, err)
+	assert.Len(t, expiring, 1)
+
+	// Removing the entry drops its expiration
+	sig, err = signaturesRepo.UpdateApprovalList("cla-group-1234", "company-1234", &models.ApprovalList{
+		RemoveEmailApprovalList: []string{"contractor@acme.com"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"employee@acme.com"}, sig.EmailApprovalList)
+	assert.Empty(t, sig.ApprovalListExpirations)
+
+	expiring, err = signaturesRepo.GetSignaturesWithApprovalListExpirations()
+	assert.Nil(t, err)
+	assert.Empty(t, expiring)
+}
+
+// testEmailSender counts the sent emails, or fails to send them
+type testEmailSender struct {
+	err  error
+	sent int
+}
+
+func (s *testEmailSender) SendEmail(subject string, body string, recipients []string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent++
+	return nil
+}
+
+func TestApprovalListExpirationReminders(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	companyRepo := company.NewMemoryRepository(store, "test")
+	usersRepo := users.NewMemoryRepository(store, "test")
+	signaturesRepo := signatures.NewMemoryRepository(store, "test", companyRepo, usersRepo)
+	projectRepo := project.NewMemoryRepository(store, "test", repositories.NewMemoryRepository(store, "test"), gerrits.NewMemoryRepository(store, "test"), nil)
+	eventsService := events.NewService(events.NewMemoryRepository(store, "test"), events.NewMockRepository())
+	service := approval_list_expirations.NewService(signaturesRepo, nil, companyRepo, projectRepo, eventsService, approval_list_expirations.DefaultReminderDays)
+
+	acme, err := companyRepo.CreateCompany(&models.Company{CompanyName: "Acme"})
+	assert.Nil(t, err)
+	claGroup, err := projectRepo.CreateCLAGroup(&models.Project{ProjectName: "Project"})
+	assert.Nil(t, err)
+	assert.Nil(t, store.Put("cla-test-users", "user-manager", users.DBUser{UserID: "user-manager", LFUsername: "manager", LFEmail: "manager@acme.com"}))
+	signatureID := "c71c469a-55ea-492d-9722-fd30b31da2aa"
+	assert.Nil(t, store.Put("cla-test-signatures", signatureID, signatures.ItemSignature{
+		SignatureID:            signatureID,
+		SignatureProjectID:     claGroup.ProjectID,
+		SignatureReferenceID:   acme.CompanyID,
+		SignatureReferenceType: "company",
+		SignatureType:          "ccla",
+		SignatureSigned:        true,
+		SignatureApproved:      true,
+		SignatureACL:           []string{"manager"},
+		EmailWhitelist:         []string{"contractor@acme.com"},
+	}))
+	_, err = signaturesRepo.UpdateApprovalList(claGroup.ProjectID, acme.CompanyID, &models.ApprovalList{
+		ApprovalListExpirations: []*models.ApprovalListExpiration{
+			{ListType: signatures.ApprovalListTypeEmail, Value: "contractor@acme.com", ExpirationDate: utils.TimeToString(time.Now().Add(48 * time.Hour))},
+		},
+	})
+	assert.Nil(t, err)
+	defer utils.SetEmailSender(nil)
+
+	// The entries are not marked as reminded when the reminder could not be sent
+	sender := &testEmailSender{err: errors.New("email sender not set")}
+	utils.SetEmailSender(sender)
+	summary, err := service.ProcessExpirations(time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, summary.RemindersSent)
+	sig, err := signaturesRepo.GetSignature(signatureID)
+	assert.Nil(t, err)
+	assert.Empty(t, sig.ApprovalListExpirations[0].ReminderSentDate)
+
+	// The next run sends the reminder, once
+	sender.err = nil
+	summary, err = service.ProcessExpirations(time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, summary.RemindersSent)
+	summary, err = service.ProcessExpirations(time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, summary.RemindersSent)
+	assert.Equal(t, 1, sender.sent)
+	sig, err = signaturesRepo.GetSignature(signatureID)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, sig.ApprovalListExpirations[0].ReminderSentDate)
+}
@@ -0,0 +1,112 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	v2Signatures "github.com/communitybridge/easycla/cla-backend-go/v2/signatures"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockGitHubLookup struct {
+	users []string
+	orgs  []string
+}
+
+func (m mockGitHubLookup) UserExists(githubUsername string) (bool, error) {
+	return utils.StringInSlice(githubUsername, m.users), nil
+}
+
+func (m mockGitHubLookup) OrganizationExists(githubOrg string) (bool, error) {
+	return utils.StringInSlice(githubOrg, m.orgs), nil
+}
+
+func TestValidateApprovalListImport(t *testing.T) {
+	expiresAt := utils.TimeToString(time.Now().Add(48 * time.Hour))
+	content := "type,value,expirationDate,comment\n" +
+		"email,contractor@acme.com," + expiresAt + ",Q4 contractor\n" +
+		"email,not-an-email,,\n" +
+		"\n" +
+		"domain,*.ACME.com,,all of acme\n" +
+		"domain,acme.*.com,,\n" +
+		"githubUsername,octocat,,\n" +
+		"githubUsername,ghost_user,,\n" +
+		"githubOrg,acme-eng,,\n" +
+		"email,contractor@acme.com,,\n" +
+		"email,former@acme.com,2020-01-01T00:00:00Z,\n" +
+		"phone,555-1234,,\n"
+
+	report, err := v2Signatures.ValidateApprovalListImport(content, mockGitHubLookup{users: []string{"octocat"}, orgs: []string{"acme-eng"}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), report.TotalRows)
+	assert.Equal(t, int64(4), report.ValidRows)
+	assert.Equal(t, int64(6), report.InvalidRows)
+
+	valid := map[int64]bool{}
+	for _, row := range report.Rows {
+		valid[row.LineNumber] = row.Valid
+		if !row.Valid {
+			assert.NotEmpty(t, row.Message)
+		}
+	}
+	assert.Equal(t, map[int64]bool{2: true, 3: false, 5: true, 6: false, 7: true, 8: false, 9: true, 10: false, 11: false, 12: false}, valid)
+
+	// Comments are echoed and domain entries are normalized
+	assert.Equal(t, "Q4 contractor", report.Rows[0].Comment)
+	assert.Equal(t, "*.acme.com", report.Rows[2].Value)
+	assert.Equal(t, "duplicate of the entry on line 2", report.Rows[7].Message)
+
+	// Content which isn't CSV is rejected as a whole
+	_, err = v2Signatures.ValidateApprovalListImport("email,\"unterminated\n", mockGitHubLookup{})
+	assert.NotNil(t, err)
+}
+
+// countingGitHubLookup counts the lookups, the users of the failing list can't be looked up
+type countingGitHubLookup struct {
+	mockGitHubLookup
+	failing []string
+	calls   int
+}
+
+func (m *countingGitHubLookup) UserExists(githubUsername string) (bool, error) {
+	m.calls++
+	if utils.StringInSlice(githubUsername, m.failing) {
+		return false, errors.New("API rate limit exceeded")
+	}
+	return m.mockGitHubLookup.UserExists(githubUsername)
+}
+
+func (m *countingGitHubLookup) OrganizationExists(githubOrg string) (bool, error) {
+	m.calls++
+	return m.mockGitHubLookup.OrganizationExists(githubOrg)
+}
+
+func TestValidateApprovalListImportLookups(t *testing.T) {
+	content := "githubUsername,octocat,,\n" +
+		"githubUsername,octocat,,\n" +
+		"githubUsername,ghostuser,,\n" +
+		"githubTeam,acme-eng/reviewers,,\n" +
+		"githubTeam,acme-eng/maintainers,,\n" +
+		"githubUsername,limited,,\n"
+	lookup := &countingGitHubLookup{
+		mockGitHubLookup: mockGitHubLookup{users: []string{"octocat", "limited"}, orgs: []string{"acme-eng"}},
+		failing:          []string{"limited"},
+	}
+
+	// Duplicates aren't looked up and each user and organization is looked up once
+	report, err := v2Signatures.ValidateApprovalListImport(content, lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, lookup.calls)
+	assert.Equal(t, int64(3), report.ValidRows)
+	assert.Equal(t, "duplicate of the entry on line 1", report.Rows[1].Message)
+	assert.Equal(t, "GitHub user ghostuser does not exist", report.Rows[2].Message)
+
+	// Lookup failures aren't reported as missing users
+	assert.False(t, report.Rows[5].Valid)
+	assert.Contains(t, report.Rows[5].Message, "unable to confirm GitHub user limited exists")
+}
@@ -0,0 +1,112 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list_revisions"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalListRevisions(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	revisionsService := approval_list_revisions.NewService(approval_list_revisions.NewMemoryRepository(store, "test"))
+
+	created := time.Now().UTC().Add(-time.Hour)
+	original := &models.Signature{
+		SignatureID:       "c71c469a-55ea-492d-9722-fd30b31da2aa",
+		SignatureCreated:  created.Format(time.RFC3339),
+		EmailApprovalList: []string{"a@acme.com"},
+		RecordVersion:     4,
+	}
+	updated := &models.Signature{
+		SignatureID:        original.SignatureID,
+		SignatureCreated:   original.SignatureCreated,
+		EmailApprovalList:  []string{"b@acme.com"},
+		DomainApprovalList: []string{"acme.com"},
+		RecordVersion:      5,
+	}
+
+	revision, err := revisionsService.CreateRevision(original, updated, &models.User{UserID: "user-1234", LfUsername: "manager"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), revision.RevisionNumber)
+	assert.Equal(t, []string{"b@acme.com"}, revision.Added.EmailApprovalList)
+	assert.Equal(t, []string{"acme.com"}, revision.Added.DomainApprovalList)
+	assert.Equal(t, []string{"a@acme.com"}, revision.Removed.EmailApprovalList)
+
+	// Revisions are immutable
+	_, err = revisionsService.CreateRevision(original, updated, nil)
+	assert.Equal(t, approval_list_revisions.ErrRevisionAlreadyExists, err)
+
+	diff, err := revisionsService.GetRevisionDiff(original.SignatureID.String(), 0, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b@acme.com"}, diff.Added.EmailApprovalList)
+	assert.Equal(t, []string{"a@acme.com"}, diff.Removed.EmailApprovalList)
+
+	// Before the first revision the original approval list applies
+	snapshot, err := revisionsService.GetApprovalListAsOf(updated, created.Add(time.Minute))
+	assert.Nil(t, err)
+	assert.True(t, snapshot.SignatureExists)
+	assert.Equal(t, int64(0), snapshot.RevisionNumber)
+	assert.Equal(t, []string{"a@acme.com"}, snapshot.ApprovalList.EmailApprovalList)
+
+	snapshot, err = revisionsService.GetApprovalListAsOf(updated, time.Now().UTC().Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), snapshot.RevisionNumber)
+	assert.Equal(t, []string{"b@acme.com"}, snapshot.ApprovalList.EmailApprovalList)
+
+	// Nothing was covered before the signature was created
+	snapshot, err = revisionsService.GetApprovalListAsOf(updated, created.Add(-time.Minute))
+	assert.Nil(t, err)
+	assert.False(t, snapshot.SignatureExists)
+	assert.Empty(t, snapshot.ApprovalList.EmailApprovalList)
+}
+
+func TestGithubOrganizationApprovalListRevisions(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	companyRepo := company.NewMemoryRepository(store, "test")
+	usersRepo := users.NewMemoryRepository(store, "test")
+	signaturesRepo := signatures.NewMemoryRepository(store, "test", companyRepo, usersRepo)
+	revisionsService := approval_list_revisions.NewService(approval_list_revisions.NewMemoryRepository(store, "test"))
+	signatureID := "c71c469a-55ea-492d-9722-fd30b31da2aa"
+	assert.Nil(t, store.Put("cla-test-signatures", signatureID, signatures.ItemSignature{
+		SignatureID:            signatureID,
+		SignatureProjectID:     "cla-group",
+		SignatureReferenceID:   "company",
+		SignatureReferenceType: "company",
+		SignatureType:          "ccla",
+		SignatureSigned:        true,
+		SignatureApproved:      true,
+	}))
+	org := "acme-org"
+	actor := &models.User{LfUsername: "manager"}
+
+	// The GitHub organization changes are recorded like the other approval list changes
+	service := signatures.NewService(signaturesRepo, nil, nil, nil, revisionsService, false, nil)
+	_, err = service.AddGithubOrganizationToWhitelist(signatureID, models.GhOrgWhitelist{OrganizationID: &org}, "", actor)
+	assert.Nil(t, err)
+	_, err = service.DeleteGithubOrganizationFromWhitelist(signatureID, models.GhOrgWhitelist{OrganizationID: &org}, "", actor)
+	assert.Nil(t, err)
+	revisions, err := revisionsService.GetRevisions(signatureID)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(revisions))
+	assert.Equal(t, []string{org}, revisions[0].Added.GithubOrgApprovalList)
+	assert.Equal(t, []string{org}, revisions[1].Removed.GithubOrgApprovalList)
+	assert.Equal(t, "manager", revisions[1].ActorLFUsername)
+
+	// Services without a revisions service still update the approval list
+	service = signatures.NewService(signaturesRepo, nil, nil, nil, nil, false, nil)
+	orgs, err := service.AddGithubOrganizationToWhitelist(signatureID, models.GhOrgWhitelist{OrganizationID: &org}, "", actor)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(orgs))
+}
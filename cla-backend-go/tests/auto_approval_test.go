@@ -0,0 +1,139 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list"
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list_revisions"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoApprovalRules(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	companyRepo := company.NewMemoryRepository(store, "test")
+	usersRepo := users.NewMemoryRepository(store, "test")
+	projectRepo := project.NewMemoryRepository(store, "test", repositories.NewMemoryRepository(store, "test"), gerrits.NewMemoryRepository(store, "test"), nil)
+	signaturesRepo := signatures.NewMemoryRepository(store, "test", companyRepo, usersRepo)
+	revisionsService := approval_list_revisions.NewService(approval_list_revisions.NewMemoryRepository(store, "test"))
+	eventsService := events.NewService(events.NewMemoryRepository(store, "test"), events.NewMockRepository())
+	signaturesService := signatures.NewService(signaturesRepo, nil, users.NewService(usersRepo, nil), eventsService, revisionsService, false, nil)
+	githubOrgs := func(githubUsername string) ([]string, error) {
+		if githubUsername == "bob-gh" {
+			return []string{"acme-eng"}, nil
+		}
+		return nil, nil
+	}
+	approvalListRepo := approval_list.NewMemoryRepository(store, "test")
+	service := approval_list.NewService(approvalListRepo, usersRepo, companyRepo, projectRepo, signaturesRepo, revisionsService, githubOrgs, "", nil)
+
+	acme, err := companyRepo.CreateCompany(&models.Company{CompanyName: "Acme"})
+	assert.Nil(t, err)
+	claGroup, err := projectRepo.CreateCLAGroup(&models.Project{ProjectName: "Project"})
+	assert.Nil(t, err)
+
+	putUser := func(userID, lfUsername, email, githubUsername string) {
+		assert.Nil(t, store.Put("cla-test-users", userID, users.DBUser{
+			UserID:             userID,
+			LFUsername:         lfUsername,
+			LFEmail:            email,
+			UserGithubUsername: githubUsername,
+		}))
+	}
+	putUser("user-manager", "manager", "manager@acme.com", "")
+	putUser("user-alice", "alice", "alice@acme.com", "")
+	putUser("user-bob", "bob", "bob@gmail.com", "bob-gh")
+	putUser("user-carol", "carol", "carol@gmail.com", "")
+	assert.Nil(t, store.Put("cla-test-signatures", "ccla-acme", signatures.ItemSignature{
+		SignatureID:            "ccla-acme",
+		SignatureProjectID:     claGroup.ProjectID,
+		SignatureReferenceID:   acme.CompanyID,
+		SignatureReferenceType: "company",
+		SignatureType:          "ccla",
+		SignatureSigned:        true,
+		SignatureApproved:      true,
+		SignatureACL:           []string{"manager"},
+	}))
+	getSignature := func() *models.Signature {
+		sig, sigErr := signaturesRepo.GetSignature("ccla-acme")
+		assert.Nil(t, sigErr)
+		return sig
+	}
+	ruleType := func(value string) *string { return &value }
+
+	// Only the CLA Managers update the rules, which are validated and normalized
+	manager := &auth.User{UserName: "manager", Email: "manager@acme.com"}
+	rules := []*models.AutoApprovalRule{
+		{RuleType: ruleType(signatures.AutoApprovalRuleTypeEmailDomain), Values: []string{"ACME.com", "acme.com"}},
+		{RuleType: ruleType(signatures.AutoApprovalRuleTypeGithubOrg), Values: []string{"Acme-Eng"}},
+	}
+	_, err = signaturesService.UpdateAutoApprovalRules(&auth.User{UserName: "alice"}, claGroup, acme, claGroup.ProjectID, rules, nil)
+	assert.IsType(t, &signatures.ForbiddenError{}, err)
+	_, err = signaturesService.UpdateAutoApprovalRules(manager, claGroup, acme, claGroup.ProjectID,
+		[]*models.AutoApprovalRule{{RuleType: ruleType("githubUser"), Values: []string{"alice"}}}, nil)
+	assert.IsType(t, &signatures.BadRequestError{}, err)
+	staleVersion := getSignature().RecordVersion
+	updatedSig, err := signaturesService.UpdateAutoApprovalRules(manager, claGroup, acme, claGroup.ProjectID, rules, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"acme.com"}, updatedSig.AutoApprovalRules[0].Values)
+	_, err = signaturesService.UpdateAutoApprovalRules(manager, claGroup, acme, claGroup.ProjectID, nil, &staleVersion)
+	assert.IsType(t, &signatures.ConflictError{}, err)
+
+	requestStatus := func(userID string) string {
+		requests, listErr := service.ListCclaWhitelistRequestByCompanyProjectUser(acme.CompanyID, &claGroup.ProjectID, nil, &userID)
+		assert.Nil(t, listErr)
+		assert.Len(t, requests.List, 1)
+		return requests.List[0].RequestStatus
+	}
+
+	// Verified email address in one of the configured domains
+	_, autoApproval, err := service.AddCclaWhitelistRequest(acme.CompanyID, claGroup.ProjectID, models.CclaWhitelistRequestInput{
+		ContributorID: "user-alice", ContributorName: "Alice", ContributorEmail: "alice@acme.com",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &approval_list.AutoApproval{
+		RuleType:     signatures.AutoApprovalRuleTypeEmailDomain,
+		MatchedValue: "acme.com",
+		ListType:     signatures.ApprovalListTypeEmail,
+		Entry:        "alice@acme.com",
+	}, autoApproval)
+	assert.Equal(t, "approved", requestStatus("user-alice"))
+	assert.Equal(t, []string{"alice@acme.com"}, getSignature().EmailApprovalList)
+
+	// Member of one of the configured GitHub organizations
+	_, autoApproval, err = service.AddCclaWhitelistRequest(acme.CompanyID, claGroup.ProjectID, models.CclaWhitelistRequestInput{
+		ContributorID: "user-bob", ContributorName: "Bob", ContributorEmail: "bob@gmail.com",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, signatures.AutoApprovalRuleTypeGithubOrg, autoApproval.RuleType)
+	assert.Equal(t, "approved", requestStatus("user-bob"))
+	assert.Equal(t, []string{"bob-gh"}, getSignature().GithubUsernameApprovalList)
+
+	// An email address in the domain which isn't verified falls back to the CLA Managers
+	_, autoApproval, err = service.AddCclaWhitelistRequest(acme.CompanyID, claGroup.ProjectID, models.CclaWhitelistRequestInput{
+		ContributorID: "user-carol", ContributorName: "Carol", ContributorEmail: "carol@acme.com",
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, autoApproval)
+	assert.Equal(t, "pending", requestStatus("user-carol"))
+	assert.Equal(t, []string{"alice@acme.com"}, getSignature().EmailApprovalList)
+
+	// The approval list changes are recorded on behalf of the requesters
+	revisions, err := revisionsService.GetRevisions("ccla-acme")
+	assert.Nil(t, err)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, "alice", revisions[0].ActorLFUsername)
+}
@@ -0,0 +1,166 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	v2Signatures "github.com/communitybridge/easycla/cla-backend-go/v2/signatures"
+	"github.com/stretchr/testify/assert"
+)
+
+func readBlob(t *testing.T, store storage.BlobStore, key string) string {
+	rc, err := store.Get(key)
+	assert.Nil(t, err)
+	content, err := ioutil.ReadAll(rc)
+	assert.Nil(t, err)
+	assert.Nil(t, rc.Close())
+	return string(content)
+}
+
+func TestFilesystemBlobStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	store, err := storage.NewFilesystemBlobStore(dir, "http://localhost:8080/blobs/")
+	assert.Nil(t, err)
+
+	// Stored blobs are read back with their metadata
+	assert.Nil(t, store.Put("contract-group/g1/icla/u1/s1.pdf", strings.NewReader("first"), &storage.PutOptions{
+		Metadata: map[string]string{"sha256": "digest"},
+	}))
+	assert.Nil(t, store.Put("contract-group/g1/icla/u2/s2.pdf", strings.NewReader("second"), nil))
+	assert.Equal(t, "first", readBlob(t, store, "contract-group/g1/icla/u1/s1.pdf"))
+	info, err := store.Stat("contract-group/g1/icla/u1/s1.pdf")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), info.Size)
+	assert.Equal(t, "digest", info.Metadata["sha256"])
+
+	// Missing blobs and keys leaving the directory
+	_, err = store.Get("contract-group/g1/icla/u3/s3.pdf")
+	assert.Equal(t, storage.ErrBlobNotFound, err)
+	_, err = store.Stat("missing.zip")
+	assert.Equal(t, storage.ErrBlobNotFound, err)
+	assert.NotNil(t, store.Put("../outside.pdf", strings.NewReader("x"), nil))
+
+	// Ranged reads fail once the blob is replaced
+	rc, err := store.GetRange("contract-group/g1/icla/u1/s1.pdf", 1, 3, info.ETag)
+	assert.Nil(t, err)
+	content, err := ioutil.ReadAll(rc)
+	assert.Nil(t, err)
+	assert.Nil(t, rc.Close())
+	assert.Equal(t, "irs", string(content))
+	assert.Nil(t, store.Put("contract-group/g1/icla/u1/s1.pdf", strings.NewReader("replaced"), nil))
+	_, err = store.GetRange("contract-group/g1/icla/u1/s1.pdf", 1, 3, info.ETag)
+	assert.Equal(t, storage.ErrBlobChanged, err)
+	info, err = store.Stat("contract-group/g1/icla/u1/s1.pdf")
+	assert.Nil(t, err)
+	assert.Nil(t, info.Metadata)
+
+	// Only the blobs of the prefix are listed, without the metadata files
+	assert.Nil(t, store.Put("contract-group/g1/ccla/c1/s4.pdf", strings.NewReader("third"), &storage.PutOptions{
+		Metadata: map[string]string{"sha256": "digest"},
+	}))
+	var keys []string
+	assert.Nil(t, store.List("contract-group/g1/", func(info *storage.BlobInfo) bool {
+		keys = append(keys, info.Key)
+		return true
+	}))
+	assert.Equal(t, []string{"contract-group/g1/ccla/c1/s4.pdf", "contract-group/g1/icla/u1/s1.pdf", "contract-group/g1/icla/u2/s2.pdf"}, keys)
+	keys = nil
+	assert.Nil(t, store.List("contract-group/g1/icla/", func(info *storage.BlobInfo) bool {
+		keys = append(keys, info.Key)
+		return false
+	}))
+	assert.Equal(t, []string{"contract-group/g1/icla/u1/s1.pdf"}, keys)
+
+	// The download URLs are the URLs of the served directory
+	url, err := store.DownloadURL("contract-group/g1/icla/u2/s2.pdf", time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://localhost:8080/blobs/contract-group/g1/icla/u2/s2.pdf", url)
+
+	// Deleting a missing blob isn't an error
+	assert.Nil(t, store.Delete("contract-group/g1/icla/u2/s2.pdf"))
+	assert.Nil(t, store.Delete("contract-group/g1/icla/u2/s2.pdf"))
+	_, err = store.Stat("contract-group/g1/icla/u2/s2.pdf")
+	assert.Equal(t, storage.ErrBlobNotFound, err)
+}
+
+func TestZipBuilderFilesystemBlobStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	blobStore, err := storage.NewFilesystemBlobStore(dir, "")
+	assert.Nil(t, err)
+	utils.SetBlobStorage(blobStore)
+
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	companyRepo := company.NewMemoryRepository(store, "test")
+	usersRepo := users.NewMemoryRepository(store, "test")
+	signaturesRepo := signatures.NewMemoryRepository(store, "test", companyRepo, usersRepo)
+	addDocument := func(name string) {
+		assert.Nil(t, store.Put("cla-test-signatures", "icla-"+name, signatures.ItemSignature{
+			SignatureID:             "icla-" + name,
+			SignatureProjectID:      "cla-group",
+			SignatureReferenceID:    "user-" + name,
+			SignatureType:           "cla",
+			SignatureSigned:         true,
+			SignatureApproved:       true,
+			UserLFUsername:          name,
+			SignedOn:                "2020-03-01T10:00:00Z",
+			SigtypeSignedApprovedID: "icla#true#true#user-" + name,
+		}))
+		_, uploadErr := utils.UploadToS3([]byte("document of "+name), "cla-group", "icla", "user-"+name, "icla-"+name)
+		assert.Nil(t, uploadErr)
+	}
+	zipBuilder := v2Signatures.NewZipBuilder(blobStore, signaturesRepo)
+
+	// A zip is built with the stored documents and a manifest
+	addDocument("alice")
+	addDocument("bob")
+	assert.Nil(t, zipBuilder.BuildICLAZip("cla-group"))
+	zipContent, err := utils.DownloadFromS3(utils.SignedClaGroupZipFilename("cla-group", "icla"))
+	assert.Nil(t, err)
+	entries := readZipEntries(t, zipContent)
+	assert.Equal(t, 3, len(entries))
+	assert.Equal(t, "document of bob", entries["icla-bob.pdf"])
+	var manifest v2Signatures.ArchiveManifest
+	assert.Nil(t, json.Unmarshal([]byte(entries[v2Signatures.ManifestFilename]), &manifest))
+	assert.Equal(t, 2, len(manifest.Documents))
+	assert.Equal(t, "alice", manifest.Documents[0].SignerLFID)
+
+	// The zip is outdated once a document is added and the next build adds it
+	outdated, err := zipBuilder.ZipOutdated(v2Signatures.ICLA, "cla-group")
+	assert.Nil(t, err)
+	assert.False(t, outdated)
+	addDocument("carol")
+	outdated, err = zipBuilder.ZipOutdated(v2Signatures.ICLA, "cla-group")
+	assert.Nil(t, err)
+	assert.True(t, outdated)
+	assert.Nil(t, zipBuilder.BuildICLAZip("cla-group"))
+	zipContent, err = utils.DownloadFromS3(utils.SignedClaGroupZipFilename("cla-group", "icla"))
+	assert.Nil(t, err)
+	entries = readZipEntries(t, zipContent)
+	assert.Equal(t, 4, len(entries))
+	assert.Equal(t, "document of alice", entries["icla-alice.pdf"])
+
+	// Filtered archives are built from the same documents
+	var b bytes.Buffer
+	manifestOut, err := zipBuilder.BuildFilteredZip(&b, v2Signatures.ICLA, "cla-group", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(manifestOut.Documents))
+	assert.Empty(t, manifestOut.MissingSignatureIDs)
+}
@@ -0,0 +1,67 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockReaderAt(t *testing.T) {
+	// a zip with a few entries, read by small blocks
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for _, name := range []string{"a.pdf", "b.pdf", "c.pdf"} {
+		w, err := zw.Create(name)
+		assert.Nil(t, err)
+		_, err = w.Write(bytes.Repeat([]byte(name), 1000))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, zw.Close())
+	content := b.Bytes()
+
+	var fetches int
+	fetch := func(start, end int64) (io.ReadCloser, error) {
+		fetches++
+		assert.True(t, end-start < 512)
+		return ioutil.NopCloser(bytes.NewReader(content[start : end+1])), nil
+	}
+	reader := utils.NewBlockReaderAt(int64(len(content)), 512, fetch)
+	zr, err := zip.NewReader(reader, reader.Size())
+	assert.Nil(t, err)
+	assert.Len(t, zr.File, 3)
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		assert.Nil(t, err)
+		data, err := ioutil.ReadAll(rc)
+		assert.Nil(t, err)
+		assert.Nil(t, rc.Close())
+		assert.Equal(t, bytes.Repeat([]byte(file.Name), 1000), data)
+	}
+	// the sequential reads only fetch each block once, besides the central directory read first
+	blocks := (len(content) + 511) / 512
+	assert.True(t, fetches <= blocks+2)
+
+	// reads past the end
+	p := make([]byte, 10)
+	n, err := reader.ReadAt(p, int64(len(content)-4))
+	assert.Equal(t, 4, n)
+	assert.Equal(t, io.EOF, err)
+	_, err = reader.ReadAt(p, int64(len(content)))
+	assert.Equal(t, io.EOF, err)
+
+	// fetch errors are returned
+	failing := utils.NewBlockReaderAt(int64(len(content)), 512, func(start, end int64) (io.ReadCloser, error) {
+		return nil, errors.New("range not satisfiable")
+	})
+	_, err = failing.ReadAt(p, 0)
+	assert.NotNil(t, err)
+}
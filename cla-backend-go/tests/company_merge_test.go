@@ -0,0 +1,238 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list_revisions"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/company_merge"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompanyMerge(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	companyRepo := company.NewMemoryRepository(store, "test")
+	usersRepo := users.NewMemoryRepository(store, "test")
+	signaturesRepo := signatures.NewMemoryRepository(store, "test", companyRepo, usersRepo)
+	revisionsService := approval_list_revisions.NewService(approval_list_revisions.NewMemoryRepository(store, "test"))
+	eventsService := events.NewService(events.NewMemoryRepository(store, "test"), events.NewMockRepository())
+	service := company_merge.NewService(companyRepo, signaturesRepo, revisionsService, eventsService)
+	admin := &auth.User{UserName: "admin", Admin: true}
+
+	acme, err := companyRepo.CreateCompany(&models.Company{CompanyName: "Acme", CompanyACL: []string{"acme-admin"}})
+	assert.Nil(t, err)
+	widgets, err := companyRepo.CreateCompany(&models.Company{CompanyName: "Widgets", CompanyACL: []string{"widgets-admin", "acme-admin"}})
+	assert.Nil(t, err)
+	gadgets, err := companyRepo.CreateCompany(&models.Company{CompanyName: "Gadgets"})
+	assert.Nil(t, err)
+	for _, user := range []users.DBUser{
+		{UserID: "user-alice", LFUsername: "alice", LFEmail: "alice@widgets.com"},
+		{UserID: "user-bob", LFUsername: "bob", LFEmail: "bob@widgets.com"},
+	} {
+		assert.Nil(t, store.Put("cla-test-users", user.UserID, user))
+	}
+	putSignature := func(sig signatures.ItemSignature) {
+		sig.SignatureSigned = true
+		sig.SignatureApproved = true
+		assert.Nil(t, store.Put("cla-test-signatures", sig.SignatureID, sig))
+	}
+	// Only Widgets signed the CCLA of the first CLA Group, both signed the CCLA of the second CLA Group
+	putSignature(signatures.ItemSignature{
+		SignatureID:             "ccla-widgets-1",
+		SignatureProjectID:      "cla-group-1",
+		SignatureReferenceID:    widgets.CompanyID,
+		SignatureReferenceName:  "Widgets",
+		SignatureReferenceType:  "company",
+		SignatureType:           "ccla",
+		SignatureACL:            []string{"widgets-admin"},
+		SigtypeSignedApprovedID: "ccla#true#true#" + widgets.CompanyID,
+	})
+	putSignature(signatures.ItemSignature{
+		SignatureID:            "ccla-widgets-2",
+		SignatureProjectID:     "cla-group-2",
+		SignatureReferenceID:   widgets.CompanyID,
+		SignatureReferenceName: "Widgets",
+		SignatureReferenceType: "company",
+		SignatureType:          "ccla",
+		SignatureACL:           []string{"widgets-admin"},
+		DomainWhitelist:        []string{"widgets.com"},
+		EmailWhitelist:         []string{"carol@gmail.com", "dave@gmail.com", "erin@gmail.com"},
+		ApprovalListExpirations: []signatures.DBApprovalListExpiration{
+			{ListType: signatures.ApprovalListTypeEmail, Value: "dave@gmail.com", ExpirationDate: utils.TimeToString(time.Now().Add(48 * time.Hour))},
+			{ListType: signatures.ApprovalListTypeEmail, Value: "erin@gmail.com", ExpirationDate: utils.TimeToString(time.Now().Add(-48 * time.Hour))},
+		},
+		AutoApprovalRules: []signatures.DBAutoApprovalRule{
+			{RuleType: signatures.AutoApprovalRuleTypeEmailDomain, Values: []string{"widgets.com"}},
+		},
+	})
+	putSignature(signatures.ItemSignature{
+		SignatureID:            "ccla-acme-2",
+		SignatureProjectID:     "cla-group-2",
+		SignatureReferenceID:   acme.CompanyID,
+		SignatureReferenceName: "Acme",
+		SignatureReferenceType: "company",
+		SignatureType:          "ccla",
+		SignatureACL:           []string{"acme-admin"},
+		DomainWhitelist:        []string{"acme.com"},
+		EmailWhitelist:         []string{"carol@gmail.com"},
+		AutoApprovalRules: []signatures.DBAutoApprovalRule{
+			{RuleType: signatures.AutoApprovalRuleTypeEmailDomain, Values: []string{"acme.com"}},
+		},
+	})
+	// Alice acknowledged the CCLA of Widgets only, Bob acknowledged both
+	putSignature(signatures.ItemSignature{
+		SignatureID:            "ecla-alice-widgets",
+		SignatureProjectID:     "cla-group-2",
+		SignatureReferenceID:   "user-alice",
+		SignatureReferenceType: "user",
+		SignatureType:          "cla",
+		SignatureUserCompanyID: widgets.CompanyID,
+	})
+	putSignature(signatures.ItemSignature{
+		SignatureID:            "ecla-bob-widgets",
+		SignatureProjectID:     "cla-group-2",
+		SignatureReferenceID:   "user-bob",
+		SignatureReferenceType: "user",
+		SignatureType:          "cla",
+		SignatureUserCompanyID: widgets.CompanyID,
+	})
+	putSignature(signatures.ItemSignature{
+		SignatureID:            "ecla-bob-acme",
+		SignatureProjectID:     "cla-group-2",
+		SignatureReferenceID:   "user-bob",
+		SignatureReferenceType: "user",
+		SignatureType:          "cla",
+		SignatureUserCompanyID: acme.CompanyID,
+	})
+
+	// A company can't be merged into itself or into a company which does not exist
+	_, err = service.MergeCompanies(admin, acme.CompanyID, acme.CompanyID, true)
+	assert.Equal(t, company_merge.ErrSameCompany, err)
+	_, err = service.MergeCompanies(admin, widgets.CompanyID, "unknown-company", true)
+	assert.Equal(t, company.ErrCompanyDoesNotExist, err)
+
+	// The dry run reports every change without applying them
+	report, err := service.MergeCompanies(admin, widgets.CompanyID, acme.CompanyID, true)
+	assert.Nil(t, err)
+	assert.True(t, report.DryRun)
+	var actions []string
+	for _, c := range report.Changes {
+		actions = append(actions, c.Action)
+	}
+	assert.Equal(t, []string{
+		company_merge.ActionTransferCorporateSignature,
+		company_merge.ActionMergeApprovalList,
+		company_merge.ActionMergeAutoApprovalRules,
+		company_merge.ActionAddCLAManager,
+		company_merge.ActionRevokeCorporateSignature,
+		company_merge.ActionTransferEmployeeSignature,
+		company_merge.ActionRevokeEmployeeSignature,
+		company_merge.ActionMergeCompanyACL,
+		company_merge.ActionRecordLineage,
+	}, actions)
+	sig, err := signaturesRepo.GetSignature("ccla-widgets-1")
+	assert.Nil(t, err)
+	assert.Equal(t, widgets.CompanyID, sig.SignatureReferenceID.String())
+
+	// A merge interrupted after moving the acknowledgement of Alice is resumed by merging the companies again,
+	// meanwhile Widgets can't be merged into another company
+	_, err = companyRepo.StartCompanyMerge(widgets.CompanyID, acme.CompanyID)
+	assert.Nil(t, err)
+	_, err = signaturesRepo.TransferSignatureCompany("ecla-alice-widgets", acme)
+	assert.Nil(t, err)
+	assert.Nil(t, companyRepo.RecordCompanyMergeChange(widgets.CompanyID, company_merge.ActionTransferEmployeeSignature+"#ecla-alice-widgets"))
+	_, err = service.MergeCompanies(admin, widgets.CompanyID, gadgets.CompanyID, false)
+	assert.Equal(t, company.ErrCompanyMergeConflict, err)
+
+	// Applying the merge moves the signatures, approval lists, CLA Managers and ACL to Acme
+	report, err = service.MergeCompanies(admin, widgets.CompanyID, acme.CompanyID, false)
+	assert.Nil(t, err)
+	assert.False(t, report.DryRun)
+	assert.Len(t, report.Changes, 8)
+	for _, c := range report.Changes {
+		assert.NotEqual(t, company_merge.ActionTransferEmployeeSignature, c.Action)
+	}
+
+	sig, err = signaturesRepo.GetCorporateSignature("cla-group-1", acme.CompanyID)
+	assert.Nil(t, err)
+	if assert.NotNil(t, sig) {
+		assert.Equal(t, "ccla-widgets-1", sig.SignatureID.String())
+		assert.Equal(t, "Acme", sig.SignatureReferenceName)
+	}
+	sig, err = signaturesRepo.GetCorporateSignature("cla-group-2", acme.CompanyID)
+	assert.Nil(t, err)
+	if assert.NotNil(t, sig) {
+		assert.Equal(t, "ccla-acme-2", sig.SignatureID.String())
+		assert.ElementsMatch(t, []string{"acme.com", "widgets.com"}, sig.DomainApprovalList)
+		// The expired entries aren't carried over, the other entries keep their expiration dates
+		assert.ElementsMatch(t, []string{"carol@gmail.com", "dave@gmail.com"}, sig.EmailApprovalList)
+		if assert.Len(t, sig.ApprovalListExpirations, 1) {
+			assert.Equal(t, "dave@gmail.com", sig.ApprovalListExpirations[0].Value)
+		}
+		if assert.Len(t, sig.AutoApprovalRules, 1) {
+			assert.ElementsMatch(t, []string{"acme.com", "widgets.com"}, sig.AutoApprovalRules[0].Values)
+		}
+	}
+	// The approval list changes are recorded in the revision history
+	revisions, err := revisionsService.GetRevisions("ccla-acme-2")
+	assert.Nil(t, err)
+	if assert.Len(t, revisions, 1) {
+		assert.Equal(t, "admin", revisions[0].ActorLFUsername)
+		assert.ElementsMatch(t, []string{"dave@gmail.com"}, revisions[0].Added.EmailApprovalList)
+	}
+	acl, err := signaturesRepo.GetSignatureACL("ccla-acme-2")
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"acme-admin", "widgets-admin"}, acl)
+	sig, err = signaturesRepo.GetSignature("ccla-widgets-2")
+	assert.Nil(t, err)
+	assert.Equal(t, signatures.RevocationReasonCompanyMerged, *sig.Revocation.Reason)
+	sig, err = signaturesRepo.GetSignature("ecla-alice-widgets")
+	assert.Nil(t, err)
+	assert.Equal(t, acme.CompanyID, sig.SignatureUserCompanyID)
+	sig, err = signaturesRepo.GetSignature("ecla-bob-widgets")
+	assert.Nil(t, err)
+	assert.False(t, sig.SignatureApproved)
+
+	// The lineage is recorded on both companies
+	mergedCompany, err := companyRepo.GetCompany(widgets.CompanyID)
+	assert.Nil(t, err)
+	assert.Equal(t, acme.CompanyID, mergedCompany.MergedIntoCompanyID)
+	assert.NotEmpty(t, mergedCompany.DateMerged)
+	var mergedRecord company.DBModel
+	_, err = store.Get("cla-test-companies", widgets.CompanyID, &mergedRecord)
+	assert.Nil(t, err)
+	assert.Empty(t, mergedRecord.MergeTargetCompanyID)
+	assert.Empty(t, mergedRecord.MergeAppliedChanges)
+	survivingCompany, err := companyRepo.GetCompany(acme.CompanyID)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{widgets.CompanyID}, survivingCompany.MergedCompanyIDs)
+	assert.ElementsMatch(t, []string{"acme-admin", "widgets-admin"}, survivingCompany.CompanyACL)
+
+	// The merge is recorded by the company merged event, the moved approval list entries aren't notified
+	var loggedEvents []events.Event
+	assert.Nil(t, store.Scan("cla-test-events", &loggedEvents))
+	mergedCount := 0
+	for _, event := range loggedEvents {
+		assert.NotEqual(t, events.ClaApprovalListUpdated, event.EventType)
+		if event.EventType == events.CompanyMerged {
+			mergedCount++
+		}
+	}
+	assert.Equal(t, 1, mergedCount)
+
+	// A merged company can't be merged again
+	_, err = service.MergeCompanies(admin, widgets.CompanyID, acme.CompanyID, true)
+	assert.Equal(t, company.ErrCompanyAlreadyMerged, err)
+}
@@ -0,0 +1,159 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	v2Models "github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/coverage"
+	"github.com/stretchr/testify/assert"
+)
+
+func nearMissReasons(result *v2Models.CoverageExplanation) []string {
+	var reasons []string
+	for _, nearMiss := range result.NearMisses {
+		reasons = append(reasons, nearMiss.Reason)
+	}
+	return reasons
+}
+
+// mockGitHubTeamMembership holds the members of each organization/team-slug
+type mockGitHubTeamMembership map[string]map[string]bool
+
+func (m mockGitHubTeamMembership) IsMember(githubTeam, githubUsername string) (bool, error) {
+	return m[githubTeam][githubUsername], nil
+}
+
+func TestExplainCoverage(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	companyRepo := company.NewMemoryRepository(store, "test")
+	usersRepo := users.NewMemoryRepository(store, "test")
+	signaturesRepo := signatures.NewMemoryRepository(store, "test", companyRepo, usersRepo)
+	approvalListRepo := approval_list.NewMemoryRepository(store, "test")
+	usersService := users.NewService(usersRepo, nil)
+	signaturesService := signatures.NewService(signaturesRepo, nil, usersService, nil, nil, false, nil)
+	approvalListService := approval_list.NewService(approvalListRepo, usersRepo, companyRepo, nil, signaturesRepo, nil, nil, "", nil)
+	githubOrgs := func(githubUsername string) ([]string, error) {
+		if githubUsername == "eve-gh" {
+			return []string{"acme-eng"}, nil
+		}
+		return nil, nil
+	}
+	teamMembership := mockGitHubTeamMembership{"acme-eng/platform": {"frank-gh": true}}
+	service := coverage.NewService(signaturesService, approvalListService, usersService, githubOrgs, teamMembership)
+
+	claGroup := &models.Project{ProjectID: "cla-group-1234", ProjectName: "Project"}
+	acme, err := companyRepo.CreateCompany(&models.Company{CompanyName: "Acme"})
+	assert.Nil(t, err)
+
+	putUser := func(userID, lfUsername, email, githubUsername, companyID string) {
+		assert.Nil(t, store.Put("cla-test-users", userID, users.DBUser{
+			UserID:             userID,
+			LFUsername:         lfUsername,
+			LFEmail:            email,
+			UserGithubUsername: githubUsername,
+			UserCompanyID:      companyID,
+		}))
+	}
+	putSignature := func(sig signatures.ItemSignature) {
+		sig.SignatureProjectID = claGroup.ProjectID
+		sig.SignatureSigned = true
+		sig.SignatureApproved = true
+		assert.Nil(t, store.Put("cla-test-signatures", sig.SignatureID, sig))
+	}
+
+	putUser("user-alice", "alice", "alice@eng.acme.com", "", acme.CompanyID)
+	putUser("user-bob", "bob", "bob@acme.com", "", acme.CompanyID)
+	putUser("user-carol", "carol", "carol@gmail.com", "", acme.CompanyID)
+	putUser("user-dave", "dave", "dave@gmail.com", "dave-gh", "")
+	putUser("user-eve", "eve", "eve@gmail.com", "eve-gh", "")
+	putUser("user-frank", "frank", "frank@gmail.com", "frank-gh", "")
+
+	putSignature(signatures.ItemSignature{
+		SignatureID:            "ccla-acme",
+		SignatureReferenceID:   acme.CompanyID,
+		SignatureReferenceType: "company",
+		SignatureType:          "ccla",
+		DomainWhitelist:        []string{"*.acme.com"},
+		GitHubOrgWhitelist:     []string{"acme-eng"},
+		GitHubTeamWhitelist:    []string{"acme-eng/platform"},
+	})
+	putSignature(signatures.ItemSignature{
+		SignatureID:            "icla-dave",
+		SignatureReferenceID:   "user-dave",
+		SignatureReferenceType: "user",
+		SignatureType:          "cla",
+	})
+	for _, userID := range []string{"user-alice", "user-eve", "user-frank"} {
+		putSignature(signatures.ItemSignature{
+			SignatureID:            "ecla-" + userID,
+			SignatureReferenceID:   userID,
+			SignatureReferenceType: "user",
+			SignatureType:          "cla",
+			SignatureUserCompanyID: acme.CompanyID,
+		})
+	}
+
+	// Covered by the CCLA through the wildcard domain entry and the employee acknowledgement
+	result, err := service.ExplainCoverage(claGroup, coverage.Contributor{LFUsername: "alice"})
+	assert.Nil(t, err)
+	assert.True(t, result.Covered)
+	assert.Equal(t, coverage.CoverageTypeCCLA, result.CoverageType)
+	assert.Equal(t, "ccla-acme", result.SignatureID)
+	assert.Equal(t, "ecla-user-alice", result.EmployeeSignatureID)
+	assert.Equal(t, "Acme", result.CompanyName)
+	assert.Equal(t, &v2Models.CoverageRule{ListType: signatures.ApprovalListTypeDomain, Value: "*.acme.com", MatchedValue: "alice@eng.acme.com"}, result.MatchedRule)
+
+	// Covered by the ICLA
+	result, err = service.ExplainCoverage(claGroup, coverage.Contributor{GitHubUsername: "dave-gh"})
+	assert.Nil(t, err)
+	assert.True(t, result.Covered)
+	assert.Equal(t, coverage.CoverageTypeICLA, result.CoverageType)
+	assert.Equal(t, "icla-dave", result.SignatureID)
+
+	// Covered through the GitHub organization membership
+	result, err = service.ExplainCoverage(claGroup, coverage.Contributor{Email: "eve@gmail.com"})
+	assert.Nil(t, err)
+	assert.True(t, result.Covered)
+	assert.Equal(t, signatures.ApprovalListTypeGithubOrg, result.MatchedRule.ListType)
+	assert.Equal(t, "acme-eng", result.MatchedRule.MatchedValue)
+
+	// Covered through the GitHub team membership
+	result, err = service.ExplainCoverage(claGroup, coverage.Contributor{GitHubUsername: "frank-gh"})
+	assert.Nil(t, err)
+	assert.True(t, result.Covered)
+	assert.Equal(t, &v2Models.CoverageRule{ListType: signatures.ApprovalListTypeGithubTeam, Value: "acme-eng/platform", MatchedValue: "frank-gh"}, result.MatchedRule)
+
+	// On the approval list but the CCLA was never acknowledged
+	result, err = service.ExplainCoverage(claGroup, coverage.Contributor{Email: "bob@acme.com"})
+	assert.Nil(t, err)
+	assert.False(t, result.Covered)
+	assert.Equal(t, coverage.CoverageTypeNone, result.CoverageType)
+	assert.Equal(t, []string{coverage.NearMissEmployeeAcknowledgementMissing}, nearMissReasons(result))
+
+	// Affiliated with the company but not on the approval list, waiting for a CLA manager
+	carol, err := usersRepo.GetUser("user-carol")
+	assert.Nil(t, err)
+	requestID, err := approvalListRepo.AddCclaWhitelistRequest(acme, claGroup, carol, "Carol", "carol@gmail.com")
+	assert.Nil(t, err)
+	result, err = service.ExplainCoverage(claGroup, coverage.Contributor{LFUsername: "carol"})
+	assert.Nil(t, err)
+	assert.False(t, result.Covered)
+	assert.Equal(t, []string{coverage.NearMissCCLANotOnApprovalList, coverage.NearMissApprovalListRequestPending}, nearMissReasons(result))
+	assert.Equal(t, requestID, result.NearMisses[1].RequestID)
+
+	// Unknown contributors are reported as such
+	result, err = service.ExplainCoverage(claGroup, coverage.Contributor{Email: "nobody@example.org"})
+	assert.Nil(t, err)
+	assert.False(t, result.Covered)
+	assert.Equal(t, []string{coverage.NearMissUserNotFound}, nearMissReasons(result))
+}
@@ -0,0 +1,143 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/export"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	v2Signatures "github.com/communitybridge/easycla/cla-backend-go/v2/signatures"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamingCsvExports(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	companyRepo := company.NewMemoryRepository(store, "test")
+	usersRepo := users.NewMemoryRepository(store, "test")
+	usersService := users.NewService(usersRepo, nil)
+	companyService := company.NewService(companyRepo, "", nil, usersService)
+	signaturesRepo := signatures.NewMemoryRepository(store, "test", companyRepo, usersRepo)
+	signaturesService := signatures.NewService(signaturesRepo, companyService, usersService, nil, nil, false, nil)
+	service := v2Signatures.NewService(nil, nil, companyService, signaturesService, nil)
+
+	acme, err := companyRepo.CreateCompany(&models.Company{CompanyName: "Acme", CompanyExternalID: "sfid-acme"})
+	assert.Nil(t, err)
+	putSignature := func(sig signatures.ItemSignature) {
+		sig.SignatureProjectID = "cla-group"
+		sig.SignatureSigned = true
+		sig.SignatureApproved = true
+		assert.Nil(t, store.Put("cla-test-signatures", sig.SignatureID, sig))
+	}
+	putSignature(signatures.ItemSignature{
+		SignatureID:             "icla-alice",
+		SignatureType:           "cla",
+		UserGithubUsername:      "alice-gh",
+		UserLFUsername:          "alice",
+		UserName:                "Alice",
+		UserEmail:               "alice@gmail.com",
+		SignedOn:                "2020-03-01T10:00:00Z",
+		SigtypeSignedApprovedID: "icla#true#true#user-alice",
+	})
+	putSignature(signatures.ItemSignature{
+		SignatureID:             "icla-bob",
+		SignatureType:           "cla",
+		UserGithubUsername:      "bob-gh",
+		UserLFUsername:          "bob",
+		UserName:                "Bob, Jr.",
+		UserEmail:               "bob@gmail.com",
+		SignedOn:                "2020-06-01T10:00:00Z",
+		SigtypeSignedApprovedID: "icla#true#true#user-bob",
+	})
+	putSignature(signatures.ItemSignature{
+		SignatureID:                   "ecla-carol",
+		SignatureType:                 "cla",
+		UserLFUsername:                "carol",
+		UserName:                      "Carol",
+		UserEmail:                     "carol@acme.com",
+		DateCreated:                   "2020-04-01T10:00:00Z",
+		SignatureDocumentMajorVersion: "2",
+		SignatureDocumentMinorVersion: "1",
+		SignatureUserCompanyID:        acme.CompanyID,
+		SigtypeSignedApprovedID:       "ecla#true#true#" + acme.CompanyID,
+	})
+
+	// The default columns of all the ICLA signatures
+	var b bytes.Buffer
+	assert.Nil(t, service.ExportProjectIclaSignatures(&b, "cla-group", nil))
+	assert.Equal(t, "Github ID,LF_ID,Name,Email,Date Signed\n"+
+		"alice-gh,alice,Alice,alice@gmail.com,\"Mar 1,2020\"\n"+
+		"bob-gh,bob,\"Bob, Jr.\",bob@gmail.com,\"Jun 1,2020\"\n", b.String())
+
+	// The selected columns of the ICLA signatures in the date range
+	signedAfter := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	b.Reset()
+	assert.Nil(t, service.ExportProjectIclaSignatures(&b, "cla-group", &v2Signatures.ExportOptions{
+		Columns:     []string{"email", "signatureID"},
+		SignedAfter: &signedAfter,
+	}))
+	assert.Equal(t, "Email,Signature ID\nbob@gmail.com,icla-bob\n", b.String())
+
+	// Only the header row when no signature is in the date range
+	signedBefore := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.Reset()
+	assert.Nil(t, service.ExportProjectIclaSignatures(&b, "cla-group", &v2Signatures.ExportOptions{SignedBefore: &signedBefore}))
+	assert.Equal(t, "Github ID,LF_ID,Name,Email,Date Signed\n", b.String())
+
+	// A signedBefore date includes the signatures of the whole day, a signedAfter date starts at the beginning of the day
+	options := &v2Signatures.ExportOptions{Columns: []string{"signatureID"}}
+	assert.Nil(t, options.SetDateRange("2020-03-01", "2020-06-01"))
+	b.Reset()
+	assert.Nil(t, service.ExportProjectIclaSignatures(&b, "cla-group", options))
+	assert.Equal(t, "Signature ID\nicla-alice\nicla-bob\n", b.String())
+	options = &v2Signatures.ExportOptions{Columns: []string{"signatureID"}}
+	assert.Nil(t, options.SetDateRange("", "2020-05-31"))
+	b.Reset()
+	assert.Nil(t, service.ExportProjectIclaSignatures(&b, "cla-group", options))
+	assert.Equal(t, "Signature ID\nicla-alice\n", b.String())
+	assert.NotNil(t, options.SetDateRange("", "June 1st"))
+
+	// Unknown columns are rejected before anything is written
+	b.Reset()
+	err = service.ExportProjectIclaSignatures(&b, "cla-group", &v2Signatures.ExportOptions{Columns: []string{"phone"}})
+	assert.True(t, errors.Is(err, v2Signatures.ErrInvalidExportColumn))
+	assert.Equal(t, 0, b.Len())
+
+	// The corporate contributors of the company
+	b.Reset()
+	assert.Nil(t, service.ExportClaGroupCorporateContributors(&b, "cla-group", "sfid-acme", &v2Signatures.ExportOptions{
+		Columns: []string{"lfID", "signatureVersion", "dateSigned"},
+	}))
+	assert.Equal(t, "LF_ID,Signature Version,Date Signed\ncarol,v2.1,\"Apr 1,2020\"\n", b.String())
+
+	// Nothing is written when the company has no corporate contributor in the date range
+	b.Reset()
+	err = service.ExportClaGroupCorporateContributors(&b, "cla-group", "sfid-acme", &v2Signatures.ExportOptions{SignedAfter: &signedAfter})
+	assert.Equal(t, v2Signatures.ErrNoCorporateContributors, err)
+	assert.Equal(t, 0, b.Len())
+
+	// The ICLA signatures as JSON Lines, with the RFC3339 dates
+	b.Reset()
+	assert.Nil(t, service.ExportProjectIclaSignatures(&b, "cla-group", &v2Signatures.ExportOptions{
+		Format:  export.FormatJSONLines,
+		Columns: []string{"lfID", "dateSigned"},
+	}))
+	assert.Equal(t, `{"lfID":"alice","dateSigned":"2020-03-01T10:00:00Z"}`+"\n"+
+		`{"lfID":"bob","dateSigned":"2020-06-01T10:00:00Z"}`+"\n", b.String())
+
+	// The corporate contributors as an XLSX workbook with an ECLA sheet
+	b.Reset()
+	assert.Nil(t, service.ExportClaGroupCorporateContributors(&b, "cla-group", "sfid-acme", &v2Signatures.ExportOptions{Format: export.FormatXLSX}))
+	workbook := readZipEntries(t, b.Bytes())
+	assert.Contains(t, workbook["xl/workbook.xml"], `<sheet name="ECLA"`)
+	assert.Contains(t, workbook["xl/worksheets/sheet1.xml"], "carol@acme.com")
+}
@@ -0,0 +1,114 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/template"
+	"github.com/stretchr/testify/assert"
+)
+
+func customTemplate(name string) *models.Template {
+	return &models.Template{
+		Name:         name,
+		Description:  "custom agreement",
+		IclaHTMLBody: "<h1>{{ PROJECT_NAME }} Individual Agreement</h1><p>Full name:</p><p>Signature:</p><p>Date:</p>",
+		MetaFields: []*models.MetaField{
+			{Name: "Project Name", TemplateVariable: "PROJECT_NAME"},
+		},
+		IclaFields: []*models.Field{
+			{ID: "full_name", Name: "Full Name", FieldType: template.FieldTypeText, AnchorString: "Full name:"},
+			{ID: "sign", Name: "Signature", FieldType: template.FieldTypeSign, AnchorString: "Signature:"},
+			{ID: "date", Name: "Date", FieldType: template.FieldTypeDate, AnchorString: "Date:"},
+		},
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	assert.Nil(t, template.ValidateTemplate(customTemplate("Custom")))
+
+	// Undeclared placeholders are rejected
+	tmpl := customTemplate("Custom")
+	tmpl.IclaHTMLBody += "<p>{{ CONTACT_EMAIL }}</p>"
+	err := template.ValidateTemplate(tmpl)
+	assert.True(t, errors.Is(err, template.ErrInvalidTemplate))
+	assert.Contains(t, err.Error(), "CONTACT_EMAIL")
+
+	// Anchors missing from the document and documents without a date field are rejected
+	tmpl = customTemplate("Custom")
+	tmpl.IclaFields[0].AnchorString = "Email:"
+	tmpl.IclaFields = tmpl.IclaFields[:2]
+	err = template.ValidateTemplate(tmpl)
+	assert.True(t, errors.Is(err, template.ErrInvalidTemplate))
+	assert.Contains(t, err.Error(), "Email:")
+	assert.Contains(t, err.Error(), "requires a date field")
+
+	// The HTML body must parse
+	tmpl = customTemplate("Custom")
+	tmpl.IclaHTMLBody = "<p>{{#if PROJECT_NAME}}</p>"
+	assert.True(t, errors.Is(template.ValidateTemplate(tmpl), template.ErrInvalidTemplate))
+}
+
+func TestCustomTemplates(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	service := template.NewService("test", template.NewMemoryRepository(store, "test"), nil, nil, nil)
+	ctx := context.Background()
+
+	// Invalid templates aren't stored
+	_, err = service.CreateTemplate(ctx, &models.Template{Name: "Empty"}, "admin")
+	assert.True(t, errors.Is(err, template.ErrInvalidTemplate))
+
+	// The custom templates are listed with the built-in templates
+	created, err := service.CreateTemplate(ctx, customTemplate("Custom"), "admin")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), created.Version)
+	assert.False(t, created.BuiltIn)
+	templates, err := service.GetTemplates(ctx)
+	assert.Nil(t, err)
+	var builtIn, custom int
+	for _, tmpl := range templates {
+		if tmpl.BuiltIn {
+			builtIn++
+		} else if tmpl.ID == created.ID {
+			custom++
+		}
+	}
+	assert.True(t, builtIn > 0)
+	assert.Equal(t, 1, custom)
+
+	// Updates add versions, the previous versions stay available
+	updated, err := service.UpdateTemplate(ctx, created.ID, customTemplate("Custom v2"), "admin2")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), updated.Version)
+	assert.Equal(t, created.DateCreated, updated.DateCreated)
+	latest, err := service.GetTemplate(ctx, created.ID, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "Custom v2", latest.Name)
+	first, err := service.GetTemplate(ctx, created.ID, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "Custom", first.Name)
+	versions, err := service.GetTemplateVersions(ctx, created.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(versions))
+	assert.Equal(t, "admin2", versions[1].CreatedBy)
+	assert.Empty(t, versions[1].IclaHTMLBody)
+
+	// The built-in templates can't be modified
+	_, err = service.UpdateTemplate(ctx, template.ApacheStyleTemplateID, customTemplate("Apache"), "admin")
+	assert.Equal(t, template.ErrBuiltInTemplate, err)
+	assert.Equal(t, template.ErrBuiltInTemplate, service.DeleteTemplate(ctx, template.ApacheStyleTemplateID))
+
+	// Deleting removes every version
+	assert.Nil(t, service.DeleteTemplate(ctx, created.ID))
+	_, err = service.GetTemplate(ctx, created.ID, 1)
+	assert.Equal(t, template.ErrTemplateNotFound, err)
+	_, err = service.UpdateTemplate(ctx, created.ID, customTemplate("Custom v3"), "admin")
+	assert.Equal(t, template.ErrTemplateNotFound, err)
+}
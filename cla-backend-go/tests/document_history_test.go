@@ -0,0 +1,108 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/template"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCLAGroupDocumentHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	blobStore, err := storage.NewFilesystemBlobStore(dir, "http://localhost:8080/blobs/")
+	assert.Nil(t, err)
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	service := template.NewService("test", template.NewMemoryRepository(store, "test"), nil, blobStore, nil)
+	ctx := context.Background()
+
+	// A legacy document without source followed by two documents published with their sources
+	document := func(majorVersion int, publishedBy string) template.DynamoProjectDocument {
+		return template.DynamoProjectDocument{
+			DocumentName:         "Apache Style",
+			DocumentFileID:       template.ApacheStyleTemplateID,
+			DocumentContentType:  "storage+pdf",
+			DocumentMajorVersion: majorVersion,
+			DocumentCreationDate: "2020-06-01T10:00:00Z",
+			DocumentS3URL:        "http://localhost:8080/blobs/icla.pdf",
+			DocumentTabs:         []template.DocumentTab{{DocumentTabType: "sign", DocumentTabID: "sign"}},
+			DocumentMetaFields:   []template.DocumentMetaField{{Name: "Project Name", TemplateVariable: "PROJECT_NAME", Value: "Project"}},
+			DocumentPublishedBy:  publishedBy,
+		}
+	}
+	assert.Nil(t, store.Put("cla-test-projects", "cla-group", struct {
+		ProjectID                  string                           `json:"project_id"`
+		ProjectResignRequired      bool                             `json:"project_resign_required"`
+		ProjectIndividualDocuments []template.DynamoProjectDocument `json:"project_individual_documents"`
+	}{
+		ProjectID:                  "cla-group",
+		ProjectResignRequired:      true,
+		ProjectIndividualDocuments: []template.DynamoProjectDocument{document(1, ""), document(2, "alice"), document(3, "bob")},
+	}))
+	for version, source := range map[string]string{
+		"2.0": "<h1>Project</h1>\n<p>Full name:</p>\n<p>Signature:</p>\n",
+		"3.0": "<h1>Project</h1>\n<p>Name:</p>\n<p>Signature:</p>\n",
+	} {
+		assert.Nil(t, blobStore.Put("contract-group/cla-group/template/icla/"+version+".html", strings.NewReader(source), nil))
+		assert.Nil(t, blobStore.Put("contract-group/cla-group/template/icla/"+version+".pdf", strings.NewReader("pdf "+version), nil))
+	}
+
+	// Every version is listed, the latest is current
+	versions, err := service.GetCLAGroupDocumentHistory(ctx, "cla-group", template.DocumentTypeICLA)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(versions))
+	assert.Equal(t, "2.0", versions[1].Version())
+	assert.Equal(t, "alice", versions[1].PublishedBy)
+	assert.Equal(t, "Project", versions[1].MetaFields[0].Value)
+	assert.False(t, versions[1].Current)
+	assert.True(t, versions[2].Current)
+	versions, err = service.GetCLAGroupDocumentHistory(ctx, "cla-group", template.DocumentTypeCCLA)
+	assert.Nil(t, err)
+	assert.Empty(t, versions)
+	_, err = service.GetCLAGroupDocumentHistory(ctx, "cla-group", "pdf")
+	assert.Equal(t, template.ErrInvalidDocumentType, err)
+
+	// The sources of the versions are compared
+	diff, err := service.DiffCLAGroupDocuments(ctx, "cla-group", template.DocumentTypeICLA, "2.0", "3.0")
+	assert.Nil(t, err)
+	assert.Contains(t, diff, "-<p>Full name:</p>")
+	assert.Contains(t, diff, "+<p>Name:</p>")
+	assert.NotContains(t, diff, "-<h1>Project</h1>")
+	_, err = service.DiffCLAGroupDocuments(ctx, "cla-group", template.DocumentTypeICLA, "1.0", "3.0")
+	assert.Equal(t, template.ErrDocumentSourceNotFound, err)
+	_, err = service.DiffCLAGroupDocuments(ctx, "cla-group", template.DocumentTypeICLA, "4.0", "3.0")
+	assert.Equal(t, template.ErrDocumentVersionNotFound, err)
+	_, err = service.DiffCLAGroupDocuments(ctx, "cla-group", template.DocumentTypeICLA, "2", "3.0")
+	assert.Equal(t, template.ErrInvalidDocumentVersion, err)
+
+	// Rolling back re-publishes the version as a new major version when the CLA Group requires re-signature
+	_, err = service.RollbackCLAGroupDocument(ctx, "cla-group", template.DocumentTypeICLA, "3.0", "carol")
+	assert.Equal(t, template.ErrDocumentVersionCurrent, err)
+	_, err = service.RollbackCLAGroupDocument(ctx, "cla-group", template.DocumentTypeICLA, "1.0", "carol")
+	assert.Equal(t, template.ErrDocumentSourceNotFound, err)
+	restored, err := service.RollbackCLAGroupDocument(ctx, "cla-group", template.DocumentTypeICLA, "2.0", "carol")
+	assert.Nil(t, err)
+	assert.Equal(t, "4.0", restored.Version())
+	assert.Equal(t, "2.0", restored.RestoredVersion)
+	assert.Equal(t, "http://localhost:8080/blobs/contract-group/cla-group/template/icla/4.0.pdf", restored.DocumentS3URL)
+	assert.Equal(t, "pdf 2.0", readBlob(t, blobStore, "contract-group/cla-group/template/icla/4.0.pdf"))
+
+	versions, err = service.GetCLAGroupDocumentHistory(ctx, "cla-group", template.DocumentTypeICLA)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(versions))
+	assert.True(t, versions[3].Current)
+	assert.Equal(t, "carol", versions[3].PublishedBy)
+	diff, err = service.DiffCLAGroupDocuments(ctx, "cla-group", template.DocumentTypeICLA, "2.0", "4.0")
+	assert.Nil(t, err)
+	assert.Empty(t, diff)
+}
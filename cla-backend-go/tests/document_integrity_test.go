@@ -0,0 +1,137 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/document_integrity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentIntegrity(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	companyRepo := company.NewMemoryRepository(store, "test")
+	usersRepo := users.NewMemoryRepository(store, "test")
+	projectRepo := project.NewMemoryRepository(store, "test", repositories.NewMemoryRepository(store, "test"), gerrits.NewMemoryRepository(store, "test"), nil)
+	signaturesRepo := signatures.NewMemoryRepository(store, "test", companyRepo, usersRepo)
+	eventsService := events.NewService(events.NewMemoryRepository(store, "test"), events.NewMockRepository())
+
+	// the stored documents, keyed by file name
+	documents := map[string][]byte{}
+	download := func(filename string) ([]byte, error) {
+		content, ok := documents[filename]
+		if !ok {
+			return nil, utils.ErrS3FileNotFound
+		}
+		return content, nil
+	}
+	service := document_integrity.NewService(signaturesRepo, projectRepo, eventsService, download)
+
+	claGroup, err := projectRepo.CreateCLAGroup(&models.Project{ProjectName: "Project"})
+	assert.Nil(t, err)
+	putSignature := func(sig signatures.ItemSignature, claType string, content []byte) {
+		sig.SignatureProjectID = claGroup.ProjectID
+		sig.SignatureSigned = true
+		sig.SignatureApproved = true
+		assert.Nil(t, store.Put("cla-test-signatures", sig.SignatureID, sig))
+		if content != nil {
+			documents[utils.SignedCLAFilename(claGroup.ProjectID, claType, sig.SignatureReferenceID, sig.SignatureID)] = content
+		}
+	}
+	original := []byte("%PDF-1.4 signed document")
+	putSignature(signatures.ItemSignature{
+		SignatureID:             "icla-verified",
+		SignatureReferenceID:    "user-alice",
+		SignatureReferenceType:  "user",
+		SignatureType:           "cla",
+		SignatureDocumentSHA256: utils.DocumentDigest(original),
+	}, "icla", original)
+	putSignature(signatures.ItemSignature{
+		SignatureID:             "icla-mismatch",
+		SignatureReferenceID:    "user-bob",
+		SignatureReferenceType:  "user",
+		SignatureType:           "cla",
+		SignatureDocumentSHA256: utils.DocumentDigest(original),
+	}, "icla", []byte("%PDF-1.4 tampered document"))
+	putSignature(signatures.ItemSignature{
+		SignatureID:             "ccla-missing",
+		SignatureReferenceID:    "company-acme",
+		SignatureReferenceType:  "company",
+		SignatureType:           "ccla",
+		SignatureDocumentSHA256: utils.DocumentDigest(original),
+	}, "ccla", nil)
+	putSignature(signatures.ItemSignature{
+		SignatureID:            "ccla-unrecorded",
+		SignatureReferenceID:   "company-widgets",
+		SignatureReferenceType: "company",
+		SignatureType:          "ccla",
+	}, "ccla", original)
+	putSignature(signatures.ItemSignature{
+		SignatureID:            "ecla-carol",
+		SignatureReferenceID:   "user-carol",
+		SignatureReferenceType: "user",
+		SignatureType:          "cla",
+		SignatureUserCompanyID: "company-acme",
+	}, "", nil)
+
+	// A stored document is verified against the digest recorded on its signature
+	for signatureID, expectedStatus := range map[string]string{
+		"icla-verified":   document_integrity.StatusVerified,
+		"icla-mismatch":   document_integrity.StatusMismatch,
+		"ccla-missing":    document_integrity.StatusMissing,
+		"ccla-unrecorded": document_integrity.StatusUnrecorded,
+	} {
+		sig, err := signaturesRepo.GetSignature(signatureID)
+		assert.Nil(t, err)
+		result, err := service.VerifySignature(sig)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedStatus, result.Status, signatureID)
+	}
+	sig, err := signaturesRepo.GetSignature("icla-mismatch")
+	assert.Nil(t, err)
+	result, err := service.VerifySignature(sig)
+	assert.Nil(t, err)
+	assert.Equal(t, "icla", result.SignatureType)
+	assert.Equal(t, utils.DocumentDigest(original), result.ExpectedSha256)
+	assert.Equal(t, utils.DocumentDigest([]byte("%PDF-1.4 tampered document")), result.ActualSha256)
+
+	// Employee acknowledgements have no signed document
+	sig, err = signaturesRepo.GetSignature("ecla-carol")
+	assert.Nil(t, err)
+	_, err = service.VerifySignature(sig)
+	assert.Equal(t, document_integrity.ErrNoSignedDocument, err)
+
+	// The audit reports the mismatched and missing documents of the CLA Group
+	audit, err := service.AuditCLAGroup(claGroup.ProjectID)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), audit.DocumentCount)
+	assert.Equal(t, int64(1), audit.VerifiedCount)
+	assert.Equal(t, int64(1), audit.MismatchCount)
+	assert.Equal(t, int64(1), audit.MissingCount)
+	assert.Equal(t, int64(1), audit.UnrecordedCount)
+	var problems []string
+	for _, problem := range audit.Problems {
+		problems = append(problems, problem.SignatureID)
+	}
+	assert.ElementsMatch(t, []string{"icla-mismatch", "ccla-missing"}, problems)
+
+	summary, err := service.AuditAllCLAGroups()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, summary.CLAGroupsAudited)
+	assert.Equal(t, 4, summary.DocumentsAudited)
+	assert.Equal(t, 1, summary.MismatchCount)
+	assert.Equal(t, 1, summary.MissingCount)
+}
@@ -0,0 +1,226 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/export"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	v2Models "github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/export_jobs"
+	v2Signatures "github.com/communitybridge/easycla/cla-backend-go/v2/signatures"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryArtifactStore keeps the export job artifacts in memory
+type memoryArtifactStore struct {
+	artifacts map[string]string
+}
+
+func (s *memoryArtifactStore) Upload(key string, body io.Reader) error {
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	s.artifacts[key] = string(content)
+	return nil
+}
+
+func (s *memoryArtifactStore) Exists(key string) (bool, error) {
+	_, ok := s.artifacts[key]
+	return ok, nil
+}
+
+func (s *memoryArtifactStore) Delete(key string) error {
+	delete(s.artifacts, key)
+	return nil
+}
+
+func (s *memoryArtifactStore) DownloadLink(key string) (string, error) {
+	return "https://download/" + key, nil
+}
+
+// recordingDispatcher records the dispatched jobs instead of running them
+type recordingDispatcher struct {
+	jobIDs []string
+	err    error
+}
+
+func (d *recordingDispatcher) Dispatch(jobID string) error {
+	d.jobIDs = append(d.jobIDs, jobID)
+	return d.err
+}
+
+// fakeZipBuilder archives the ICLAs only, the filters of the filtered archives are recorded
+type fakeZipBuilder struct {
+	store   *memoryArtifactStore
+	filters []*v2Signatures.ArchiveFilter
+}
+
+func (z *fakeZipBuilder) BuildICLAZip(claGroupID string) error {
+	z.store.artifacts[utils.SignedClaGroupZipFilename(claGroupID, v2Signatures.ICLA)] = "zip"
+	return nil
+}
+
+func (z *fakeZipBuilder) BuildCCLAZip(claGroupID string) error {
+	return nil
+}
+
+func (z *fakeZipBuilder) BuildFilteredZip(w io.Writer, claType string, claGroupID string, filter *v2Signatures.ArchiveFilter) (*v2Signatures.ArchiveManifest, error) {
+	z.filters = append(z.filters, filter)
+	_, err := io.WriteString(w, claType+" zip")
+	return &v2Signatures.ArchiveManifest{ClaGroupID: claGroupID, SignatureType: claType}, err
+}
+
+func (z *fakeZipBuilder) ZipOutdated(claType string, claGroupID string) (bool, error) {
+	return false, nil
+}
+
+func TestExportJobs(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	companyRepo := company.NewMemoryRepository(store, "test")
+	usersRepo := users.NewMemoryRepository(store, "test")
+	usersService := users.NewService(usersRepo, nil)
+	companyService := company.NewService(companyRepo, "", nil, usersService)
+	signaturesService := signatures.NewService(signatures.NewMemoryRepository(store, "test", companyRepo, usersRepo), companyService, usersService, nil, nil, false, nil)
+	artifacts := &memoryArtifactStore{artifacts: map[string]string{}}
+	dispatcher := &recordingDispatcher{}
+	zipBuilder := &fakeZipBuilder{store: artifacts}
+	service := export_jobs.NewService(export_jobs.NewMemoryRepository(store, "test"), companyRepo,
+		v2Signatures.NewService(nil, nil, companyService, signaturesService, nil), nil,
+		zipBuilder, artifacts, dispatcher, time.Hour)
+
+	assert.Nil(t, store.Put("cla-test-signatures", "icla-alice", signatures.ItemSignature{
+		SignatureID:             "icla-alice",
+		SignatureProjectID:      "cla-group",
+		SignatureType:           "cla",
+		SignatureSigned:         true,
+		SignatureApproved:       true,
+		UserLFUsername:          "alice",
+		UserName:                "Alice",
+		SignedOn:                "2020-03-01T10:00:00Z",
+		SigtypeSignedApprovedID: "icla#true#true#user-alice",
+	}))
+	authUser := &auth.User{UserName: "admin"}
+	claGroup := &models.Project{ProjectID: "cla-group", ProjectICLAEnabled: true}
+	jobType := func(value string) *v2Models.ExportJobInput {
+		return &v2Models.ExportJobInput{JobType: aws.String(value)}
+	}
+
+	// Invalid requests
+	for _, input := range []*v2Models.ExportJobInput{
+		nil,
+		jobType("unknown"),
+		{JobType: aws.String(export_jobs.JobTypeICLASignatures), Format: "pdf"},
+		{JobType: aws.String(export_jobs.JobTypeICLASignatures), Columns: []string{"unknown"}},
+		{JobType: aws.String(export_jobs.JobTypeICLASignatures), SignedAfter: "yesterday"},
+		{JobType: aws.String(export_jobs.JobTypeSignedICLAZip), Format: "csv"},
+		{JobType: aws.String(export_jobs.JobTypeSignedICLAZip), CompanySFID: "sfid-acme"},
+		{JobType: aws.String(export_jobs.JobTypeSignedICLAZip), SignedBefore: "tomorrow"},
+		jobType(export_jobs.JobTypeSignedCCLAZip),
+		jobType(export_jobs.JobTypeCorporateContributors),
+	} {
+		_, err = service.SubmitJob(authUser, claGroup, input)
+		assert.True(t, errors.Is(err, export_jobs.ErrInvalidJob))
+	}
+	assert.Empty(t, dispatcher.jobIDs)
+
+	// The submitted job is dispatched and pending until it is run
+	job, err := service.SubmitJob(authUser, claGroup, &v2Models.ExportJobInput{
+		JobType: aws.String(export_jobs.JobTypeICLASignatures),
+		Columns: []string{"lfID", "name"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{job.JobID}, dispatcher.jobIDs)
+	assert.Equal(t, export_jobs.StatusPending, job.Status)
+	assert.Equal(t, export.FormatCSV, job.Format)
+
+	// The artifact of the succeeded job can be downloaded
+	assert.Nil(t, service.RunJob(job.JobID))
+	assert.Equal(t, export_jobs.ErrJobNotPending, service.RunJob(job.JobID))
+	job, err = service.GetJob("cla-group", job.JobID)
+	assert.Nil(t, err)
+	assert.Equal(t, export_jobs.StatusSucceeded, job.Status)
+	assert.Equal(t, int64(100), job.Progress)
+	key := "export-jobs/" + job.JobID + "/icla-signatures-cla-group.csv"
+	assert.Equal(t, "https://download/"+key, job.DownloadURL)
+	assert.Equal(t, "LF_ID,Name\nalice,Alice\n", artifacts.artifacts[key])
+	assert.Equal(t, int64(len(artifacts.artifacts[key])), job.ArtifactSize)
+	_, err = service.GetJob("other-cla-group", job.JobID)
+	assert.Equal(t, export_jobs.ErrJobNotFound, err)
+
+	// The signed document archive job refers to the shared archive, a missing archive fails the job
+	iclaZip, err := service.SubmitJob(authUser, claGroup, jobType(export_jobs.JobTypeSignedICLAZip))
+	assert.Nil(t, err)
+	assert.Nil(t, service.RunJob(iclaZip.JobID))
+	cclaGroup := &models.Project{ProjectID: "cla-group", ProjectCCLAEnabled: true}
+	cclaZip, err := service.SubmitJob(authUser, cclaGroup, jobType(export_jobs.JobTypeSignedCCLAZip))
+	assert.Nil(t, err)
+	assert.Equal(t, export_jobs.ErrArtifactNotFound, service.RunJob(cclaZip.JobID))
+	cclaZip, err = service.GetJob("cla-group", cclaZip.JobID)
+	assert.Nil(t, err)
+	assert.Equal(t, export_jobs.StatusFailed, cclaZip.Status)
+	assert.Equal(t, export_jobs.ErrArtifactNotFound.Error(), cclaZip.ErrorMessage)
+	assert.Empty(t, cclaZip.DownloadURL)
+
+	// The filtered archive of a company CCLA is built on demand as the managed artifact of the job
+	acme, err := companyRepo.CreateCompany(&models.Company{CompanyName: "Acme", CompanyExternalID: "sfid-acme"})
+	assert.Nil(t, err)
+	companyZip, err := service.SubmitJob(authUser, cclaGroup, &v2Models.ExportJobInput{
+		JobType:     aws.String(export_jobs.JobTypeSignedCCLAZip),
+		CompanySFID: "sfid-acme",
+		SignedAfter: "2020-01-01T00:00:00Z",
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, service.RunJob(companyZip.JobID))
+	companyZip, err = service.GetJob("cla-group", companyZip.JobID)
+	assert.Nil(t, err)
+	assert.Equal(t, export_jobs.StatusSucceeded, companyZip.Status)
+	companyZipKey := "export-jobs/" + companyZip.JobID + "/signed-ccla-documents-cla-group.zip"
+	assert.Equal(t, "https://download/"+companyZipKey, companyZip.DownloadURL)
+	assert.Equal(t, "ccla zip", artifacts.artifacts[companyZipKey])
+	if assert.Len(t, zipBuilder.filters, 1) {
+		assert.Equal(t, acme.CompanyID, zipBuilder.filters[0].CompanyID)
+		assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), zipBuilder.filters[0].SignedAfter.UTC())
+		assert.Nil(t, zipBuilder.filters[0].SignedBefore)
+	}
+
+	// A dispatch failure fails the job
+	dispatcher.err = errors.New("throttled")
+	_, err = service.SubmitJob(authUser, claGroup, jobType(export_jobs.JobTypeICLASignatures))
+	assert.NotNil(t, err)
+	dispatcher.err = nil
+	list, err := service.ListJobs("cla-group")
+	assert.Nil(t, err)
+	assert.Len(t, list.List, 5)
+
+	// Nothing expires before the retention period, the managed artifacts only are deleted afterwards
+	summary, err := service.CleanupExpiredJobs(time.Now().UTC())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, summary.JobsExpired)
+	summary, err = service.CleanupExpiredJobs(time.Now().UTC().Add(2 * time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 5, summary.JobsExpired)
+	assert.Equal(t, 2, summary.ArtifactsDeleted)
+	assert.NotContains(t, artifacts.artifacts, key)
+	assert.NotContains(t, artifacts.artifacts, companyZipKey)
+	assert.Contains(t, artifacts.artifacts, utils.SignedClaGroupZipFilename("cla-group", v2Signatures.ICLA))
+	job, err = service.GetJob("cla-group", job.JobID)
+	assert.Nil(t, err)
+	assert.Equal(t, export_jobs.StatusExpired, job.Status)
+	assert.Empty(t, job.DownloadURL)
+}
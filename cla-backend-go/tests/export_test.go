@@ -0,0 +1,107 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/communitybridge/easycla/cla-backend-go/export"
+	"github.com/stretchr/testify/assert"
+)
+
+// readZipEntries returns the content of the zip archive entries by name
+func readZipEntries(t *testing.T, archive []byte) map[string]string {
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	assert.Nil(t, err)
+	entries := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		assert.Nil(t, err)
+		content, err := ioutil.ReadAll(rc)
+		assert.Nil(t, err)
+		assert.Nil(t, rc.Close())
+		entries[f.Name] = string(content)
+	}
+	return entries
+}
+
+func TestExportFormatNegotiation(t *testing.T) {
+	format, err := export.NegotiateFormat(nil, "")
+	assert.Nil(t, err)
+	assert.Equal(t, export.FormatCSV, format)
+
+	// The format parameter takes precedence over the accept header
+	format, err = export.NegotiateFormat(aws.String("XLSX"), "application/x-ndjson")
+	assert.Nil(t, err)
+	assert.Equal(t, export.FormatXLSX, format)
+
+	// The first supported media type of the accept header
+	format, err = export.NegotiateFormat(nil, "application/json, application/x-ndjson;q=0.9, text/csv")
+	assert.Nil(t, err)
+	assert.Equal(t, export.FormatJSONLines, format)
+
+	_, err = export.NegotiateFormat(aws.String("pdf"), "")
+	assert.True(t, errors.Is(err, export.ErrUnsupportedFormat))
+
+	assert.Equal(t, export.XLSXMime, export.ContentType(export.FormatXLSX))
+	assert.Equal(t, "events.jsonl", export.Filename("events", export.FormatJSONLines))
+}
+
+// writeTestExport writes two sheets with typed values in the format
+func writeTestExport(t *testing.T, format string) []byte {
+	var b bytes.Buffer
+	exporter, err := export.NewExporter(format, &b)
+	assert.Nil(t, err)
+	columns := []export.Column{
+		{Name: "name", Header: "Name"},
+		{Name: "count", Header: "Count"},
+		{Name: "approved", Header: "Approved"},
+		{Name: "signed", Header: "Signed", DateLayout: "2006-01-02"},
+	}
+	signed := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	assert.Nil(t, exporter.BeginSheet("ICLA", columns))
+	assert.Nil(t, exporter.WriteRow([]interface{}{"Alice & Co", 3, true, signed}))
+	assert.Nil(t, exporter.BeginSheet("CCLA/ECLA", columns))
+	assert.Nil(t, exporter.WriteRow([]interface{}{"Bob", int64(1), false, nil}))
+	assert.NotNil(t, exporter.WriteRow([]interface{}{"too few values"}))
+	assert.Nil(t, exporter.Close())
+	return b.Bytes()
+}
+
+func TestCSVAndJSONLinesExports(t *testing.T) {
+	assert.Equal(t, "Name,Count,Approved,Signed\nAlice & Co,3,true,2020-03-01\n"+
+		"Name,Count,Approved,Signed\nBob,1,false,\n", string(writeTestExport(t, export.FormatCSV)))
+
+	assert.Equal(t, `{"name":"Alice & Co","count":3,"approved":true,"signed":"2020-03-01T12:00:00Z"}`+"\n"+
+		`{"name":"Bob","count":1,"approved":false,"signed":null}`+"\n", string(writeTestExport(t, export.FormatJSONLines)))
+}
+
+func TestXLSXExport(t *testing.T) {
+	entries := readZipEntries(t, writeTestExport(t, export.FormatXLSX))
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		assert.Contains(t, entries, name)
+	}
+
+	// One sheet per BeginSheet, with the invalid sheet name characters replaced
+	assert.Contains(t, entries["xl/workbook.xml"], `<sheet name="ICLA" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(t, entries["xl/workbook.xml"], `<sheet name="CCLA_ECLA" sheetId="2" r:id="rId2"/>`)
+	assert.Contains(t, entries["[Content_Types].xml"], `/xl/worksheets/sheet2.xml`)
+
+	// Typed cells - escaped inline strings, numbers, booleans and dates as serial numbers with the date style
+	sheet := entries["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A1" t="inlineStr" s="1"><is><t xml:space="preserve">Name</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="A2" t="inlineStr"><is><t xml:space="preserve">Alice &amp; Co</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="B2"><v>3</v></c>`)
+	assert.Contains(t, sheet, `<c r="C2" t="b"><v>1</v></c>`)
+	assert.Contains(t, sheet, `<c r="D2" s="2"><v>43891.5</v></c>`)
+
+	// Empty values are left out of the row
+	assert.Contains(t, entries["xl/worksheets/sheet2.xml"], `<row r="2"><c r="A2" t="inlineStr"><is><t xml:space="preserve">Bob</t></is></c><c r="B2"><v>1</v></c><c r="C2" t="b"><v>0</v></c></row>`)
+}
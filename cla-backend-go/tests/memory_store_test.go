@@ -0,0 +1,187 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryRecord struct {
+	ID    string   `dynamodbav:"id"`
+	Name  string   `dynamodbav:"name"`
+	Items []string `dynamodbav:"items"`
+}
+
+func TestMemoryStore(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+
+	var record memoryRecord
+	found, err := store.Get("records", "a", &record)
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	assert.Nil(t, store.Put("records", "b", memoryRecord{ID: "b", Name: "second"}))
+	assert.Nil(t, store.Create("records", "a", memoryRecord{ID: "a", Name: "first", Items: []string{"x"}}))
+	assert.Equal(t, storage.ErrItemAlreadyExists, store.Create("records", "a", memoryRecord{ID: "a"}))
+
+	// The records are copies, changing a loaded record doesn't change the store
+	found, err = store.Get("records", "a", &record)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	record.Items[0] = "changed"
+	var reloaded memoryRecord
+	_, err = store.Get("records", "a", &reloaded)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"x"}, reloaded.Items)
+
+	// A failed update leaves the record unchanged
+	assert.Equal(t, os.ErrInvalid, store.Update("records", "a", &record, func() error {
+		record.Name = "ignored"
+		return os.ErrInvalid
+	}))
+	assert.Nil(t, store.Update("records", "a", &record, func() error {
+		record.Name = "updated"
+		return nil
+	}))
+	assert.Equal(t, storage.ErrItemNotFound, store.Update("records", "missing", &record, func() error { return nil }))
+	assert.Nil(t, store.UpdateItem("records", "b", func(item map[string]*dynamodb.AttributeValue) error {
+		item["name"] = &dynamodb.AttributeValue{S: aws.String("patched")}
+		return nil
+	}))
+
+	// The scans are in key order
+	var records []memoryRecord
+	assert.Nil(t, store.Scan("records", &records))
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, "updated", records[0].Name)
+	assert.Equal(t, "patched", records[1].Name)
+
+	assert.Nil(t, store.Delete("records", "a"))
+	assert.Nil(t, store.Delete("records", "a"))
+	records = nil
+	assert.Nil(t, store.Scan("records", &records))
+	assert.Equal(t, 1, len(records))
+}
+
+func TestMemoryStoreFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "memory-store")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	filePath := filepath.Join(dir, "store.json")
+
+	// The store starts empty without the file and writes each change to the file
+	store, err := storage.NewMemoryStore(filePath)
+	assert.Nil(t, err)
+	assert.Nil(t, store.Put("records", "a", memoryRecord{ID: "a", Name: "first"}))
+
+	reloaded, err := storage.NewMemoryStore(filePath)
+	assert.Nil(t, err)
+	var record memoryRecord
+	found, err := reloaded.Get("records", "a", &record)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "first", record.Name)
+
+	// Changes which can't be written to the file are rolled back
+	assert.Nil(t, os.RemoveAll(dir))
+	assert.NotNil(t, store.Put("records", "a", memoryRecord{ID: "a", Name: "changed"}))
+	assert.NotNil(t, store.Put("records", "b", memoryRecord{ID: "b", Name: "second"}))
+	assert.NotNil(t, store.Delete("records", "a"))
+	found, err = store.Get("records", "a", &record)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "first", record.Name)
+	found, err = store.Get("records", "b", &record)
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	assert.Nil(t, os.MkdirAll(dir, 0700))
+	assert.Nil(t, ioutil.WriteFile(filePath, []byte("not json"), 0600))
+	_, err = storage.NewMemoryStore(filePath)
+	assert.NotNil(t, err)
+}
+
+func TestMemoryRepositories(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+
+	usersRepo := users.NewMemoryRepository(store, "test")
+	user, err := usersRepo.CreateUser(&models.User{Username: "Alice", LfUsername: "alice"})
+	assert.Nil(t, err)
+	found, err := usersRepo.GetUserByLFUserName("alice")
+	assert.Nil(t, err)
+	assert.Equal(t, user.UserID, found.UserID)
+	assert.Nil(t, usersRepo.Delete(user.UserID))
+	found, err = usersRepo.GetUser(user.UserID)
+	assert.Nil(t, err)
+	assert.Nil(t, found)
+
+	companyRepo := company.NewMemoryRepository(store, "test")
+	acme, err := companyRepo.CreateCompany(&models.Company{CompanyName: "Acme", CompanyExternalID: "sfid-acme"})
+	assert.Nil(t, err)
+	byID, err := companyRepo.GetCompany(acme.CompanyID)
+	assert.Nil(t, err)
+	assert.Equal(t, "Acme", byID.CompanyName)
+	byExternalID, err := companyRepo.GetCompanyByExternalID("sfid-acme")
+	assert.Nil(t, err)
+	assert.Equal(t, acme.CompanyID, byExternalID.CompanyID)
+
+	projectRepo := project.NewMemoryRepository(store, "test", repositories.NewMemoryRepository(store, "test"), gerrits.NewMemoryRepository(store, "test"), nil)
+	claGroup, err := projectRepo.CreateCLAGroup(&models.Project{ProjectName: "Project", ProjectICLAEnabled: true})
+	assert.Nil(t, err)
+	loaded, err := projectRepo.GetCLAGroupByID(claGroup.ProjectID, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "Project", loaded.ProjectName)
+	assert.True(t, loaded.ProjectICLAEnabled)
+	assert.Nil(t, projectRepo.DeleteCLAGroup(claGroup.ProjectID))
+}
+
+func TestMemorySessionStore(t *testing.T) {
+	store := storage.NewMemorySessionStore("/")
+
+	// A new session is created without the session cookie
+	request := httptest.NewRequest("GET", "/v3/github/login", nil)
+	session, err := store.Get(request, "cla-github")
+	assert.Nil(t, err)
+	assert.True(t, session.IsNew)
+	session.Values["state"] = "random-state"
+	recorder := httptest.NewRecorder()
+	assert.Nil(t, session.Save(request, recorder))
+	cookies := recorder.Result().Cookies()
+	assert.Equal(t, 1, len(cookies))
+	assert.True(t, cookies[0].HttpOnly)
+
+	// The session values are loaded from the session cookie
+	request = httptest.NewRequest("GET", "/v3/github/redirect", nil)
+	request.AddCookie(cookies[0])
+	session, err = store.Get(request, "cla-github")
+	assert.Nil(t, err)
+	assert.False(t, session.IsNew)
+	assert.Equal(t, "random-state", session.Values["state"])
+
+	// Deleted sessions aren't loaded anymore
+	session.Options.MaxAge = -1
+	assert.Nil(t, session.Save(request, httptest.NewRecorder()))
+	request = httptest.NewRequest("GET", "/v3/github/redirect", nil)
+	request.AddCookie(cookies[0])
+	session, err = store.Get(request, "cla-github")
+	assert.Nil(t, err)
+	assert.True(t, session.IsNew)
+}
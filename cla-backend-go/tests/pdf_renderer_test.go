@@ -0,0 +1,101 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/pdf"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/template"
+	"github.com/stretchr/testify/assert"
+)
+
+var pdfStreamRegex = regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`)
+
+// pdfPageContents returns the uncompressed content streams of the pages of the PDF
+func pdfPageContents(t *testing.T, document []byte) []string {
+	var contents []string
+	for _, match := range pdfStreamRegex.FindAllSubmatch(document, -1) {
+		zr, err := zlib.NewReader(bytes.NewReader(match[1]))
+		assert.Nil(t, err)
+		content, err := ioutil.ReadAll(zr)
+		assert.Nil(t, err)
+		contents = append(contents, string(content))
+	}
+	return contents
+}
+
+func TestNewRenderer(t *testing.T) {
+	renderer, err := pdf.NewRenderer(config.PDFRenderer{Driver: pdf.RendererLocal}, config.Docraptor{})
+	assert.Nil(t, err)
+	assert.NotNil(t, renderer)
+
+	// DocRaptor is the default renderer, it requires the API key
+	_, err = pdf.NewRenderer(config.PDFRenderer{}, config.Docraptor{})
+	assert.NotNil(t, err)
+	_, err = pdf.NewRenderer(config.PDFRenderer{Driver: "chrome"}, config.Docraptor{})
+	assert.NotNil(t, err)
+}
+
+func TestLocalRenderer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	blobStore, err := storage.NewFilesystemBlobStore(dir, "http://localhost:8080/blobs/")
+	assert.Nil(t, err)
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	service := template.NewService("test", template.NewMemoryRepository(store, "test"), pdf.NewLocalRenderer(), blobStore, nil)
+	ctx := context.Background()
+
+	assert.Nil(t, store.Put("cla-test-projects", "cla-group", struct {
+		ProjectID          string `json:"project_id"`
+		ProjectIclaEnabled bool   `json:"project_icla_enabled"`
+		ProjectCclaEnabled bool   `json:"project_ccla_enabled"`
+	}{
+		ProjectID:          "cla-group",
+		ProjectIclaEnabled: true,
+		ProjectCclaEnabled: true,
+	}))
+	fields := &models.CreateClaGroupTemplate{
+		TemplateID: template.ApacheStyleTemplateID,
+		MetaFields: []*models.MetaField{
+			{Name: "Project Name", TemplateVariable: "PROJECT_NAME", Value: "Project (Test)"},
+			{Name: "Project Entity Name", TemplateVariable: "PROJECT_ENTITY_NAME", Value: "Project Entity"},
+			{Name: "Contact Email Address", TemplateVariable: "CONTACT_EMAIL", Value: "cla@example.org"},
+		},
+	}
+
+	// The signature fields are on their own page, with the anchors of the tabs kept as text
+	document, err := service.CreateTemplatePreview(fields, "icla", "")
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(document, []byte("%PDF-")))
+	assert.Contains(t, string(document), "/Count 3")
+	pages := pdfPageContents(t, document)
+	assert.Equal(t, 3, len(pages))
+	assert.Contains(t, pages[0], `(Project Name: Project \(Test\))`)
+	for _, anchor := range []string{"Please sign:", "Date:", "Full name:", "Mailing Address:", "Country:", "E-Mail:"} {
+		assert.Contains(t, pages[2], anchor)
+	}
+
+	// The CLA Group documents are rendered without the DocRaptor API
+	pdfURLs, err := service.CreateCLAGroupTemplate(ctx, "cla-group", fields, "admin")
+	assert.Nil(t, err)
+	assert.Equal(t, "http://localhost:8080/blobs/contract-group/cla-group/template/icla/2.0.pdf", pdfURLs.IndividualPDFURL)
+	assert.Equal(t, "http://localhost:8080/blobs/contract-group/cla-group/template/ccla/2.0.pdf", pdfURLs.CorporatePDFURL)
+	assert.True(t, bytes.HasPrefix([]byte(readBlob(t, blobStore, "contract-group/cla-group/template/ccla/2.0.pdf")), []byte("%PDF-")))
+	versions, err := service.GetCLAGroupDocumentHistory(ctx, "cla-group", template.DocumentTypeCCLA)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(versions))
+	assert.Equal(t, "admin", versions[0].PublishedBy)
+}
@@ -0,0 +1,156 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list"
+	"github.com/communitybridge/easycla/cla-backend-go/cla_manager"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	"github.com/communitybridge/easycla/cla-backend-go/pending_request_expirations"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingRequestExpirations(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	companyRepo := company.NewMemoryRepository(store, "test")
+	usersRepo := users.NewMemoryRepository(store, "test")
+	projectRepo := project.NewMemoryRepository(store, "test", repositories.NewMemoryRepository(store, "test"), gerrits.NewMemoryRepository(store, "test"), nil)
+	signaturesRepo := signatures.NewMemoryRepository(store, "test", companyRepo, usersRepo)
+	approvalListRepo := approval_list.NewMemoryRepository(store, "test")
+	claManagerRepo := cla_manager.NewMemoryRepository(store, "test")
+	eventsService := events.NewService(events.NewMemoryRepository(store, "test"), events.NewMockRepository())
+
+	reminderDays, err := pending_request_expirations.ParseReminderDays(" 14,3 ,7")
+	assert.Nil(t, err)
+	_, err = pending_request_expirations.ParseReminderDays("3,soon")
+	assert.NotNil(t, err)
+	service := pending_request_expirations.NewService(approvalListRepo, claManagerRepo, signaturesRepo, companyRepo, projectRepo,
+		eventsService, "corporate.lfcla.com", reminderDays, 30)
+
+	acme, err := companyRepo.CreateCompany(&models.Company{CompanyName: "Acme"})
+	assert.Nil(t, err)
+	claGroup, err := projectRepo.CreateCLAGroup(&models.Project{ProjectName: "Project"})
+	assert.Nil(t, err)
+	assert.Nil(t, store.Put("cla-test-users", "user-manager", users.DBUser{UserID: "user-manager", LFUsername: "manager", LFEmail: "manager@acme.com"}))
+	assert.Nil(t, store.Put("cla-test-signatures", "ccla-acme", signatures.ItemSignature{
+		SignatureID:            "ccla-acme",
+		SignatureProjectID:     claGroup.ProjectID,
+		SignatureReferenceID:   acme.CompanyID,
+		SignatureReferenceType: "company",
+		SignatureType:          "ccla",
+		SignatureSigned:        true,
+		SignatureApproved:      true,
+		SignatureACL:           []string{"manager"},
+	}))
+
+	now := time.Now().UTC()
+	daysAgo := func(days int) string {
+		return utils.TimeToString(now.AddDate(0, 0, -days))
+	}
+	putApprovalListRequest := func(requestID, status string, created string, reminderCount int) {
+		assert.Nil(t, store.Put("cla-test-ccla-whitelist-requests", requestID, approval_list.CclaWhitelistRequest{
+			RequestID:     requestID,
+			RequestStatus: status,
+			CompanyID:     acme.CompanyID,
+			ProjectID:     claGroup.ProjectID,
+			UserID:        "user-" + requestID,
+			UserName:      requestID,
+			UserEmails:    []string{requestID + "@acme.com"},
+			DateCreated:   created,
+			DateModified:  created,
+			ReminderCount: reminderCount,
+		}))
+	}
+	putApprovalListRequest("fresh", approval_list.StatusPending, daysAgo(1), 0)
+	putApprovalListRequest("first", approval_list.StatusPending, daysAgo(4), 0)
+	putApprovalListRequest("final", approval_list.StatusPending, daysAgo(15), 1)
+	putApprovalListRequest("stale", approval_list.StatusPending, daysAgo(31), 3)
+	putApprovalListRequest("approved", "approved", daysAgo(60), 0)
+
+	putCLAManagerRequest := func(requestID string, updated string) {
+		assert.Nil(t, store.Put("cla-test-cla-manager-requests", requestID, cla_manager.CLAManagerRequest{
+			RequestID: requestID,
+			CompanyID: acme.CompanyID,
+			ProjectID: claGroup.ProjectID,
+			UserName:  requestID,
+			UserEmail: requestID + "@acme.com",
+			Status:    cla_manager.StatusPending,
+			Created:   daysAgo(90),
+			Updated:   updated,
+		}))
+	}
+	putCLAManagerRequest("second", daysAgo(8))
+	putCLAManagerRequest("lapsed", daysAgo(40))
+
+	summary, err := service.ProcessPendingRequests(now)
+	assert.Nil(t, err)
+	assert.Equal(t, &pending_request_expirations.Summary{
+		ApprovalListRequestsReminded: 2,
+		ApprovalListRequestsExpired:  1,
+		CLAManagerRequestsReminded:   1,
+		CLAManagerRequestsExpired:    1,
+	}, summary)
+
+	approvalListRequest := func(requestID string) *approval_list.CLARequestModel {
+		request, getErr := approvalListRepo.GetCclaWhitelistRequest(requestID)
+		assert.Nil(t, getErr)
+		return request
+	}
+	assert.Equal(t, 0, approvalListRequest("fresh").ReminderCount)
+	assert.Equal(t, 1, approvalListRequest("first").ReminderCount)
+	assert.NotEmpty(t, approvalListRequest("first").LastReminderDate)
+	// The job catches up with the latest reminder only
+	assert.Equal(t, 3, approvalListRequest("final").ReminderCount)
+	assert.Equal(t, approval_list.StatusExpired, approvalListRequest("stale").RequestStatus)
+	assert.Equal(t, "approved", approvalListRequest("approved").RequestStatus)
+
+	claManagerRequest := func(requestID string) *cla_manager.CLAManagerRequest {
+		request, getErr := claManagerRepo.GetRequest(requestID)
+		assert.Nil(t, getErr)
+		return request
+	}
+	assert.Equal(t, 2, claManagerRequest("second").ReminderCount)
+	assert.Equal(t, cla_manager.StatusExpired, claManagerRequest("lapsed").Status)
+
+	var loggedEvents []events.Event
+	assert.Nil(t, store.Scan("cla-test-events", &loggedEvents))
+	eventTypes := map[string]int{}
+	for _, event := range loggedEvents {
+		eventTypes[event.EventType]++
+	}
+	assert.Equal(t, 2, eventTypes[events.CCLAApprovalListRequestReminder])
+	assert.Equal(t, 1, eventTypes[events.CCLAApprovalListRequestExpired])
+	assert.Equal(t, 1, eventTypes[events.ClaManagerAccessRequestReminder])
+	assert.Equal(t, 1, eventTypes[events.ClaManagerAccessRequestExpired])
+
+	// Running the job again the same day does not remind the CLA managers twice
+	summary, err = service.ProcessPendingRequests(now)
+	assert.Nil(t, err)
+	assert.Equal(t, &pending_request_expirations.Summary{}, summary)
+
+	// Reviewed requests are left alone and a reopened CLA manager request starts over
+	assert.Nil(t, approvalListRepo.ApproveCclaWhitelistRequest("first"))
+	assert.Equal(t, approval_list.ErrCclaWhitelistRequestNotPending, approvalListRepo.ExpireCclaWhitelistRequest("first"))
+	reopened, err := claManagerRepo.PendingRequest(acme.CompanyID, claGroup.ProjectID, "second")
+	assert.Nil(t, err)
+	assert.Equal(t, cla_manager.StatusPending, reopened.Status)
+	assert.Equal(t, 0, reopened.ReminderCount)
+	summary, err = service.ProcessPendingRequests(now.AddDate(0, 0, 5))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, summary.CLAManagerRequestsReminded)
+	assert.Equal(t, 0, summary.CLAManagerRequestsExpired)
+}
@@ -0,0 +1,211 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	v2Models "github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/resign_campaigns"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResignCampaigns(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	companyRepo := company.NewMemoryRepository(store, "test")
+	usersRepo := users.NewMemoryRepository(store, "test")
+	projectRepo := project.NewMemoryRepository(store, "test", repositories.NewMemoryRepository(store, "test"), gerrits.NewMemoryRepository(store, "test"), nil)
+	signaturesRepo := signatures.NewMemoryRepository(store, "test", companyRepo, usersRepo)
+	eventsService := events.NewService(events.NewMemoryRepository(store, "test"), events.NewMockRepository())
+	service := resign_campaigns.NewService(resign_campaigns.NewMemoryRepository(store, "test"), projectRepo, signaturesRepo,
+		users.NewService(usersRepo, nil), eventsService, "corporate.lfcla.com", 10, 7)
+
+	claGroup, err := projectRepo.CreateCLAGroup(&models.Project{ProjectName: "Project"})
+	assert.Nil(t, err)
+	acme, err := companyRepo.CreateCompany(&models.Company{CompanyName: "Acme"})
+	assert.Nil(t, err)
+	for _, user := range []users.DBUser{
+		{UserID: "user-manager", LFUsername: "manager", LFEmail: "manager@acme.com"},
+		{UserID: "user-alice", LFUsername: "alice", LFEmail: "alice@gmail.com"},
+		{UserID: "user-bob", LFUsername: "bob", LFEmail: "bob@gmail.com"},
+	} {
+		assert.Nil(t, store.Put("cla-test-users", user.UserID, user))
+	}
+	putSignature := func(sig signatures.ItemSignature) {
+		sig.SignatureProjectID = claGroup.ProjectID
+		sig.SignatureSigned = true
+		sig.SignatureApproved = true
+		assert.Nil(t, store.Put("cla-test-signatures", sig.SignatureID, sig))
+	}
+	putSignature(signatures.ItemSignature{
+		SignatureID:                   "ccla-acme",
+		SignatureReferenceID:          acme.CompanyID,
+		SignatureReferenceType:        "company",
+		SignatureType:                 "ccla",
+		SignatureACL:                  []string{"manager"},
+		SignatureDocumentMajorVersion: "2",
+		SigtypeSignedApprovedID:       "ccla#true#true#" + acme.CompanyID,
+	})
+	putSignature(signatures.ItemSignature{
+		SignatureID:                   "icla-alice",
+		SignatureReferenceID:          "user-alice",
+		SignatureReferenceName:        "alice",
+		SignatureReferenceType:        "user",
+		SignatureType:                 "cla",
+		SignatureDocumentMajorVersion: "2",
+	})
+	putSignature(signatures.ItemSignature{
+		SignatureID:                   "icla-bob",
+		SignatureReferenceID:          "user-bob",
+		SignatureReferenceName:        "bob",
+		SignatureReferenceType:        "user",
+		SignatureType:                 "cla",
+		SignatureDocumentMajorVersion: "3",
+	})
+	putSignature(signatures.ItemSignature{
+		SignatureID:                   "ecla-manager",
+		SignatureReferenceID:          "user-manager",
+		SignatureReferenceType:        "user",
+		SignatureType:                 "cla",
+		SignatureUserCompanyID:        acme.CompanyID,
+		SignatureDocumentMajorVersion: "2",
+	})
+
+	// No campaign is started unless the CLA Group requires re-signature
+	assert.Nil(t, service.StartCampaign(claGroup.ProjectID, 3, 3))
+	campaigns, err := service.ListCampaigns(claGroup.ProjectID)
+	assert.Nil(t, err)
+	assert.Empty(t, campaigns.List)
+
+	// The previous version ICLA and CCLA signers are tracked, employee acknowledgements follow the company CCLA
+	_, err = projectRepo.UpdateCLAGroupResignPolicy(claGroup.ProjectID, true, 30)
+	assert.Nil(t, err)
+	assert.Nil(t, service.StartCampaign(claGroup.ProjectID, 3, 3))
+	campaigns, err = service.ListCampaigns(claGroup.ProjectID)
+	assert.Nil(t, err)
+	if !assert.Len(t, campaigns.List, 1) {
+		return
+	}
+	campaignID := campaigns.List[0].CampaignID
+	assert.Equal(t, resign_campaigns.StatusActive, campaigns.List[0].Status)
+	assert.NotEmpty(t, campaigns.List[0].Deadline)
+	assert.Equal(t, int64(2), campaigns.List[0].Progress.Total)
+
+	// Alice re-signs, the CLA Managers of Acme are notified once per interval
+	putSignature(signatures.ItemSignature{
+		SignatureID:                   "icla-alice-v3",
+		SignatureReferenceID:          "user-alice",
+		SignatureReferenceName:        "alice",
+		SignatureReferenceType:        "user",
+		SignatureType:                 "cla",
+		SignatureDocumentMajorVersion: "3",
+	})
+	now := time.Now().UTC()
+	summary, err := service.ProcessCampaigns(now)
+	assert.Nil(t, err)
+	assert.Equal(t, &resign_campaigns.Summary{TargetsResigned: 1, TargetsNotified: 1}, summary)
+	summary, err = service.ProcessCampaigns(now.AddDate(0, 0, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, &resign_campaigns.Summary{}, summary)
+
+	// Once the grace period is over the previous version CCLA no longer grants coverage
+	summary, err = service.ProcessCampaigns(now.AddDate(0, 0, 31))
+	assert.Nil(t, err)
+	assert.Equal(t, &resign_campaigns.Summary{TargetsLapsed: 1, CampaignsEnforced: 1}, summary)
+	corporateSig, err := signaturesRepo.GetCorporateSignature(claGroup.ProjectID, acme.CompanyID)
+	assert.Nil(t, err)
+	assert.Nil(t, corporateSig)
+	revokedSig, err := signaturesRepo.GetSignature("ccla-acme")
+	assert.Nil(t, err)
+	assert.Equal(t, signatures.RevocationReasonResignDeadlinePassed, *revokedSig.Revocation.Reason)
+
+	campaign, err := service.GetCampaign(claGroup.ProjectID, campaignID)
+	assert.Nil(t, err)
+	assert.Equal(t, resign_campaigns.StatusEnforced, campaign.Status)
+	assert.Equal(t, &v2Models.ResignCampaignProgress{Total: 2, Resigned: 1, Lapsed: 1, Notified: 1}, campaign.Progress)
+	assert.Len(t, campaign.Targets, 2)
+
+	// Closed campaigns can't be cancelled and campaigns of other CLA Groups are not found
+	_, err = service.CancelCampaign(&auth.User{UserName: "admin"}, claGroup, campaignID)
+	assert.Equal(t, resign_campaigns.ErrCampaignNotActive, err)
+	_, err = service.GetCampaign("another-cla-group", campaignID)
+	assert.Equal(t, resign_campaigns.ErrCampaignNotFound, err)
+}
+
+func TestResignCampaignEnforcementNotice(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	companyRepo := company.NewMemoryRepository(store, "test")
+	usersRepo := users.NewMemoryRepository(store, "test")
+	projectRepo := project.NewMemoryRepository(store, "test", repositories.NewMemoryRepository(store, "test"), gerrits.NewMemoryRepository(store, "test"), nil)
+	signaturesRepo := signatures.NewMemoryRepository(store, "test", companyRepo, usersRepo)
+	eventsService := events.NewService(events.NewMemoryRepository(store, "test"), events.NewMockRepository())
+	// a single notification is sent per run
+	service := resign_campaigns.NewService(resign_campaigns.NewMemoryRepository(store, "test"), projectRepo, signaturesRepo,
+		users.NewService(usersRepo, nil), eventsService, "corporate.lfcla.com", 1, 7)
+
+	claGroup, err := projectRepo.CreateCLAGroup(&models.Project{ProjectName: "Project"})
+	assert.Nil(t, err)
+	_, err = projectRepo.UpdateCLAGroupResignPolicy(claGroup.ProjectID, true, 30)
+	assert.Nil(t, err)
+	for _, companyName := range []string{"Acme", "Globex"} {
+		companyModel, companyErr := companyRepo.CreateCompany(&models.Company{CompanyName: companyName})
+		assert.Nil(t, companyErr)
+		assert.Nil(t, store.Put("cla-test-signatures", "ccla-"+companyName, signatures.ItemSignature{
+			SignatureID:                   "ccla-" + companyName,
+			SignatureProjectID:            claGroup.ProjectID,
+			SignatureReferenceID:          companyModel.CompanyID,
+			SignatureReferenceName:        companyName,
+			SignatureReferenceType:        "company",
+			SignatureType:                 "ccla",
+			SignatureSigned:               true,
+			SignatureApproved:             true,
+			SignatureDocumentMajorVersion: "2",
+			SigtypeSignedApprovedID:       "ccla#true#true#" + companyModel.CompanyID,
+		}))
+	}
+	assert.Nil(t, service.StartCampaign(claGroup.ProjectID, 3, 3))
+
+	now := time.Now().UTC()
+	summary, err := service.ProcessCampaigns(now)
+	assert.Nil(t, err)
+	assert.Equal(t, &resign_campaigns.Summary{TargetsNotified: 1}, summary)
+
+	// Past the deadline only the signature of the target notified a full grace period before is revoked, the other
+	// target is notified and awaits its notice period
+	summary, err = service.ProcessCampaigns(now.AddDate(0, 0, 31))
+	assert.Nil(t, err)
+	assert.Equal(t, &resign_campaigns.Summary{TargetsNotified: 1, TargetsLapsed: 1, TargetsAwaitingNotice: 1}, summary)
+	campaigns, err := service.ListCampaigns(claGroup.ProjectID)
+	assert.Nil(t, err)
+	if !assert.Len(t, campaigns.List, 1) {
+		return
+	}
+	assert.Equal(t, resign_campaigns.StatusActive, campaigns.List[0].Status)
+	assert.Equal(t, &v2Models.ResignCampaignProgress{Total: 2, Pending: 1, Lapsed: 1, Notified: 2}, campaigns.List[0].Progress)
+
+	summary, err = service.ProcessCampaigns(now.AddDate(0, 0, 35))
+	assert.Nil(t, err)
+	assert.Equal(t, &resign_campaigns.Summary{TargetsAwaitingNotice: 1}, summary)
+
+	// The campaign is enforced once the notice period of the last target is over
+	summary, err = service.ProcessCampaigns(now.AddDate(0, 0, 61))
+	assert.Nil(t, err)
+	assert.Equal(t, &resign_campaigns.Summary{TargetsLapsed: 1, CampaignsEnforced: 1}, summary)
+	campaigns, err = service.ListCampaigns(claGroup.ProjectID)
+	assert.Nil(t, err)
+	assert.Equal(t, resign_campaigns.StatusEnforced, campaigns.List[0].Status)
+}
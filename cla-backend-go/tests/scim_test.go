@@ -0,0 +1,149 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list_revisions"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	v2Models "github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/scim"
+	"github.com/stretchr/testify/assert"
+)
+
+func scimErrorStatus(err error) int {
+	if scimErr, ok := err.(*scim.Error); ok {
+		return scimErr.Status
+	}
+	return 0
+}
+
+func TestSCIMProvisioning(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	companyRepo := company.NewMemoryRepository(store, "test")
+	usersRepo := users.NewMemoryRepository(store, "test")
+	signaturesRepo := signatures.NewMemoryRepository(store, "test", companyRepo, usersRepo)
+	revisionsService := approval_list_revisions.NewService(approval_list_revisions.NewMemoryRepository(store, "test"))
+	eventsService := events.NewService(events.NewMemoryRepository(store, "test"), events.NewMockRepository())
+	service := scim.NewService(scim.NewMemoryRepository(store, "test"), signaturesRepo, revisionsService, users.NewService(usersRepo, nil), eventsService)
+
+	acme, err := companyRepo.CreateCompany(&models.Company{CompanyName: "Acme"})
+	assert.Nil(t, err)
+	claGroup := &models.Project{ProjectID: "cla-group-1234", ProjectName: "Project"}
+	scope := scim.Scope{CompanyModel: acme, CLAGroupModel: claGroup}
+
+	assert.Nil(t, store.Put("cla-test-users", "user-manager", users.DBUser{UserID: "user-manager", LFUsername: "manager", LFEmail: "manager@acme.com"}))
+	assert.Nil(t, store.Put("cla-test-signatures", "ccla-acme", signatures.ItemSignature{
+		SignatureID:            "ccla-acme",
+		SignatureProjectID:     claGroup.ProjectID,
+		SignatureReferenceID:   acme.CompanyID,
+		SignatureReferenceType: "company",
+		SignatureType:          "ccla",
+		SignatureSigned:        true,
+		SignatureApproved:      true,
+		SignatureACL:           []string{"manager"},
+	}))
+	emailApprovalList := func() []string {
+		sig, sigErr := signaturesRepo.GetCorporateSignature(claGroup.ProjectID, acme.CompanyID)
+		assert.Nil(t, sigErr)
+		return sig.EmailApprovalList
+	}
+
+	// Only the CLA Managers manage the token, the token value is only returned when it is created
+	_, err = service.CreateToken(&auth.User{UserName: "contributor"}, scope)
+	assert.IsType(t, &signatures.ForbiddenError{}, err)
+	manager := &auth.User{UserName: "manager", Email: "manager@acme.com"}
+	createdToken, err := service.CreateToken(manager, scope)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, createdToken.Token)
+	storedToken, err := service.GetToken(manager, scope)
+	assert.Nil(t, err)
+	assert.Empty(t, storedToken.Token)
+	assert.Equal(t, createdToken.TokenHint, storedToken.TokenHint)
+
+	_, err = service.Authenticate(scope, "Bearer not-the-token")
+	assert.Equal(t, http.StatusUnauthorized, scimErrorStatus(err))
+	token, err := service.Authenticate(scope, "Bearer "+createdToken.Token)
+	assert.Nil(t, err)
+
+	// Provisioning adds the user to the email approval list
+	user, err := service.CreateUser(token, scope, &v2Models.ScimUser{
+		UserName: "jdoe@acme.com",
+		Emails:   []*v2Models.ScimUserEmail{{Value: "jdoe@acme.com", Primary: true}},
+	})
+	assert.Nil(t, err)
+	assert.True(t, *user.Active)
+	assert.Equal(t, []string{"jdoe@acme.com"}, emailApprovalList())
+
+	_, err = service.CreateUser(token, scope, &v2Models.ScimUser{UserName: "JDoe@acme.com"})
+	assert.Equal(t, http.StatusConflict, scimErrorStatus(err))
+
+	userList, err := service.ListUsers(scope, `userName eq "JDOE@ACME.COM"`, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), userList.TotalResults)
+	_, err = service.ListUsers(scope, `userName co "jdoe"`, 1, 10)
+	assert.Equal(t, http.StatusBadRequest, scimErrorStatus(err))
+
+	group, err := service.CreateGroup(scope, &v2Models.ScimGroup{
+		DisplayName: "Contributors",
+		Members:     []*v2Models.ScimMember{{Value: user.ID}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "jdoe@acme.com", group.Members[0].Display)
+	_, err = service.CreateGroup(scope, &v2Models.ScimGroup{DisplayName: "Others", Members: []*v2Models.ScimMember{{Value: "unknown"}}})
+	assert.Equal(t, http.StatusBadRequest, scimErrorStatus(err))
+
+	// Deactivating the user in the identity provider removes the coverage - the active flag may be sent as a string
+	replace := "replace"
+	user, err = service.PatchUser(token, scope, user.ID, &v2Models.ScimPatchRequest{
+		Operations: []*v2Models.ScimPatchOperation{{Op: &replace, Path: "active", Value: "False"}},
+	})
+	assert.Nil(t, err)
+	assert.False(t, *user.Active)
+	assert.Equal(t, "Contributors", user.Groups[0].Display)
+	assert.Empty(t, emailApprovalList())
+
+	_, err = service.PatchUser(token, scope, user.ID, &v2Models.ScimPatchRequest{
+		Operations: []*v2Models.ScimPatchOperation{{Op: &replace, Value: map[string]interface{}{"active": true}}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"jdoe@acme.com"}, emailApprovalList())
+
+	// Deleting the user removes the coverage and the group membership
+	assert.Nil(t, service.DeleteUser(token, scope, user.ID))
+	assert.Empty(t, emailApprovalList())
+	group, err = service.GetGroup(scope, group.ID)
+	assert.Nil(t, err)
+	assert.Empty(t, group.Members)
+	_, err = service.GetUser(scope, user.ID)
+	assert.Equal(t, http.StatusNotFound, scimErrorStatus(err))
+
+	// Every change of the approval list is recorded
+	revisions, err := revisionsService.GetRevisions("ccla-acme")
+	assert.Nil(t, err)
+	assert.Len(t, revisions, 4)
+	var loggedEvents []events.Event
+	assert.Nil(t, store.Scan("cla-test-events", &loggedEvents))
+	eventTypes := map[string]int{}
+	for _, event := range loggedEvents {
+		eventTypes[event.EventType]++
+	}
+	assert.Equal(t, 1, eventTypes[events.SCIMTokenCreated])
+	assert.Equal(t, 2, eventTypes[events.ApprovalListSCIMEmailAdded])
+	assert.Equal(t, 2, eventTypes[events.ApprovalListSCIMEmailRemoved])
+
+	// Revoked tokens no longer authenticate
+	assert.Nil(t, service.DeleteToken(manager, scope))
+	_, err = service.Authenticate(scope, "Bearer "+createdToken.Token)
+	assert.Equal(t, http.StatusUnauthorized, scimErrorStatus(err))
+}
@@ -0,0 +1,133 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignatureRevocation(t *testing.T) {
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	companyRepo := company.NewMemoryRepository(store, "test")
+	usersRepo := users.NewMemoryRepository(store, "test")
+	usersService := users.NewService(usersRepo, nil)
+	signaturesRepo := signatures.NewMemoryRepository(store, "test", companyRepo, usersRepo)
+	eventsService := events.NewService(events.NewMemoryRepository(store, "test"), events.NewMockRepository())
+	signaturesService := signatures.NewService(signaturesRepo, company.NewService(companyRepo, "", nil, usersService), usersService,
+		eventsService, nil, false, nil)
+
+	claGroup := &models.Project{ProjectID: "cla-group-1234", ProjectName: "Project"}
+	acme, err := companyRepo.CreateCompany(&models.Company{CompanyName: "Acme"})
+	assert.Nil(t, err)
+	for _, user := range []users.DBUser{
+		{UserID: "user-admin", LFUsername: "admin", LFEmail: "admin@linuxfoundation.org"},
+		{UserID: "user-manager", LFUsername: "manager", LFEmail: "manager@acme.com"},
+		{UserID: "user-alice", LFUsername: "alice", LFEmail: "alice@gmail.com"},
+		{UserID: "user-bob", LFUsername: "bob", LFEmail: "bob@acme.com"},
+	} {
+		assert.Nil(t, store.Put("cla-test-users", user.UserID, user))
+	}
+	putSignature := func(sig signatures.ItemSignature) {
+		sig.SignatureProjectID = claGroup.ProjectID
+		sig.SignatureSigned = true
+		sig.SignatureApproved = true
+		assert.Nil(t, store.Put("cla-test-signatures", sig.SignatureID, sig))
+	}
+	putSignature(signatures.ItemSignature{
+		SignatureID:            "ccla-acme",
+		SignatureReferenceID:   acme.CompanyID,
+		SignatureReferenceType: "company",
+		SignatureType:          "ccla",
+		SignatureACL:           []string{"manager"},
+	})
+	putSignature(signatures.ItemSignature{
+		SignatureID:             "icla-alice",
+		SignatureReferenceID:    "user-alice",
+		SignatureReferenceName:  "alice",
+		SignatureReferenceType:  "user",
+		SignatureType:           "cla",
+		SigtypeSignedApprovedID: "icla#true#true#user-alice",
+	})
+	putSignature(signatures.ItemSignature{
+		SignatureID:             "ecla-bob",
+		SignatureReferenceID:    "user-bob",
+		SignatureReferenceName:  "bob",
+		SignatureReferenceType:  "user",
+		SignatureType:           "cla",
+		SignatureUserCompanyID:  acme.CompanyID,
+		SigtypeSignedApprovedID: "ecla#true#true#" + acme.CompanyID,
+	})
+	reason := func(value string) *string { return &value }
+	admin := &auth.User{UserName: "admin", Email: "admin@linuxfoundation.org"}
+
+	// Invalid reason codes are rejected
+	_, err = signaturesService.RevokeSignature(admin, claGroup, "icla-alice", &models.SignatureRevocation{Reason: reason("bored")})
+	assert.IsType(t, &signatures.BadRequestError{}, err)
+
+	// The revoked ICLA no longer covers the contributor but stays in the history with the revocation details
+	revoked, err := signaturesService.RevokeSignature(admin, claGroup, "icla-alice", &models.SignatureRevocation{
+		Reason:  reason(signatures.RevocationReasonSignedInError),
+		Comment: "signed with the wrong account",
+	})
+	assert.Nil(t, err)
+	assert.False(t, revoked.SignatureApproved)
+	assert.Equal(t, signatures.RevocationReasonSignedInError, *revoked.Revocation.Reason)
+	assert.Equal(t, "admin", revoked.Revocation.RevokedBy)
+	assert.NotEmpty(t, revoked.Revocation.RevokedOn)
+	individualSig, err := signaturesRepo.GetIndividualSignature(claGroup.ProjectID, "user-alice")
+	assert.Nil(t, err)
+	assert.Nil(t, individualSig)
+	iclaSignatures, err := signaturesRepo.GetClaGroupICLASignatures(claGroup.ProjectID, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, iclaSignatures.List)
+
+	// Revoking again is a conflict holding the current signature
+	_, err = signaturesService.RevokeSignature(admin, claGroup, "icla-alice", &models.SignatureRevocation{Reason: reason(signatures.RevocationReasonLegalRequest)})
+	if assert.IsType(t, &signatures.ConflictError{}, err) {
+		assert.Equal(t, signatures.RevocationReasonSignedInError, *err.(*signatures.ConflictError).Current.Revocation.Reason)
+	}
+
+	// The revoked employee acknowledgement no longer covers the employee, the company CCLA is unaffected
+	revoked, err = signaturesService.RevokeSignature(admin, claGroup, "ecla-bob", &models.SignatureRevocation{Reason: reason(signatures.RevocationReasonEmploymentEnded)})
+	assert.Nil(t, err)
+	assert.Equal(t, acme.CompanyID, revoked.SignatureUserCompanyID)
+	contributors, err := signaturesRepo.GetClaGroupCorporateContributors(claGroup.ProjectID, &acme.CompanyID, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, contributors.List)
+	corporateSig, err := signaturesRepo.GetCorporateSignature(claGroup.ProjectID, acme.CompanyID)
+	assert.Nil(t, err)
+	assert.NotNil(t, corporateSig)
+
+	// Unknown signatures are not found
+	revoked, err = signaturesService.RevokeSignature(admin, claGroup, "unknown", &models.SignatureRevocation{Reason: reason(signatures.RevocationReasonLegalRequest)})
+	assert.Nil(t, err)
+	assert.Nil(t, revoked)
+
+	var loggedEvents []events.Event
+	assert.Nil(t, store.Scan("cla-test-events", &loggedEvents))
+	revokedCount := 0
+	for _, event := range loggedEvents {
+		if event.EventType == events.RevokedSignature {
+			revokedCount++
+		}
+	}
+	assert.Equal(t, 2, revokedCount)
+}
+
+func TestRevocableByCompanyManager(t *testing.T) {
+	// The CLA Managers may revoke the employee acknowledgements of their company but not the CCLA or an ICLA
+	assert.True(t, signatures.RevocableByCompanyManager(&models.Signature{SignatureType: "cla", SignatureReferenceType: "user", SignatureUserCompanyID: "company-acme"}))
+	assert.False(t, signatures.RevocableByCompanyManager(&models.Signature{SignatureType: signatures.CCLA, SignatureReferenceType: "company"}))
+	assert.False(t, signatures.RevocableByCompanyManager(&models.Signature{SignatureType: "cla", SignatureReferenceType: "user"}))
+}
@@ -0,0 +1,143 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/pdf"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/template"
+	"github.com/stretchr/testify/assert"
+)
+
+func localizedTemplate(name string) *models.Template {
+	tmpl := customTemplate(name)
+	tmpl.Locale = "en"
+	tmpl.LegallyBindingLocale = "en"
+	tmpl.LocaleVariants = []*models.TemplateLocaleVariant{
+		{
+			Locale:        "pt-BR",
+			IclaHTMLBody:  "<h1>Acordo Individual {{ PROJECT_NAME }}</h1><p>Nome completo:</p><p>Assinatura:</p><p>Data:</p>",
+			AnchorStrings: map[string]string{"full_name": "Nome completo:", "sign": "Assinatura:", "date": "Data:"},
+		},
+	}
+	return tmpl
+}
+
+func TestValidateTemplateLocales(t *testing.T) {
+	assert.Nil(t, template.ValidateTemplate(localizedTemplate("Localized")))
+
+	// The translated anchors must appear in the translation
+	tmpl := localizedTemplate("Localized")
+	tmpl.LocaleVariants[0].AnchorStrings["date"] = "Data de assinatura:"
+	err := template.ValidateTemplate(tmpl)
+	assert.True(t, errors.Is(err, template.ErrInvalidTemplate))
+	assert.Contains(t, err.Error(), "Data de assinatura:")
+
+	// The anchors of the fields which aren't translated must appear in the translation
+	tmpl = localizedTemplate("Localized")
+	delete(tmpl.LocaleVariants[0].AnchorStrings, "full_name")
+	err = template.ValidateTemplate(tmpl)
+	assert.True(t, errors.Is(err, template.ErrInvalidTemplate))
+	assert.Contains(t, err.Error(), "Full name:")
+
+	// Each locale is provided once
+	tmpl = localizedTemplate("Localized")
+	tmpl.LocaleVariants = append(tmpl.LocaleVariants, tmpl.LocaleVariants[0])
+	err = template.ValidateTemplate(tmpl)
+	assert.True(t, errors.Is(err, template.ErrInvalidTemplate))
+	assert.Contains(t, err.Error(), "duplicate locale: pt-BR")
+
+	// The legally binding locale must be a locale of the template
+	tmpl = localizedTemplate("Localized")
+	tmpl.LegallyBindingLocale = "fr"
+	err = template.ValidateTemplate(tmpl)
+	assert.True(t, errors.Is(err, template.ErrInvalidTemplate))
+	assert.Contains(t, err.Error(), "legally binding locale")
+}
+
+func TestTemplateLocales(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	blobStore, err := storage.NewFilesystemBlobStore(dir, "http://localhost:8080/blobs/")
+	assert.Nil(t, err)
+	store, err := storage.NewMemoryStore("")
+	assert.Nil(t, err)
+	service := template.NewService("test", template.NewMemoryRepository(store, "test"), pdf.NewLocalRenderer(), blobStore, nil)
+	ctx := context.Background()
+
+	assert.Nil(t, store.Put("cla-test-projects", "cla-group", struct {
+		ProjectID          string `json:"project_id"`
+		ProjectIclaEnabled bool   `json:"project_icla_enabled"`
+	}{
+		ProjectID:          "cla-group",
+		ProjectIclaEnabled: true,
+	}))
+	created, err := service.CreateTemplate(ctx, localizedTemplate("Localized"), "admin")
+	assert.Nil(t, err)
+	assert.Equal(t, "en", created.LegallyBindingLocale)
+	assert.Equal(t, 1, len(created.LocaleVariants))
+	fields := &models.CreateClaGroupTemplate{
+		TemplateID: created.ID,
+		MetaFields: []*models.MetaField{
+			{Name: "Project Name", TemplateVariable: "PROJECT_NAME", Value: "Project"},
+		},
+	}
+
+	// The preview is rendered in the requested locale, the legally binding locale by default
+	document, err := service.CreateTemplatePreview(fields, "icla", "")
+	assert.Nil(t, err)
+	assert.Contains(t, strings.Join(pdfPageContents(t, document), ""), "Full name:")
+	document, err = service.CreateTemplatePreview(fields, "icla", "pt-BR")
+	assert.Nil(t, err)
+	assert.Contains(t, strings.Join(pdfPageContents(t, document), ""), "Nome completo:")
+	_, err = service.CreateTemplatePreview(fields, "icla", "fr")
+	assert.True(t, errors.Is(err, template.ErrLocaleNotFound))
+
+	// The legally binding document is published with its translations
+	pdfURLs, err := service.CreateCLAGroupTemplate(ctx, "cla-group", fields, "admin")
+	assert.Nil(t, err)
+	assert.Equal(t, "http://localhost:8080/blobs/contract-group/cla-group/template/icla/2.0.pdf", pdfURLs.IndividualPDFURL)
+	assert.True(t, bytes.HasPrefix([]byte(readBlob(t, blobStore, "contract-group/cla-group/template/icla/2.0.pt-BR.pdf")), []byte("%PDF-")))
+	versions, err := service.GetCLAGroupDocumentHistory(ctx, "cla-group", template.DocumentTypeICLA)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(versions))
+	assert.Equal(t, "en", versions[0].Locale)
+	assert.Equal(t, []string{"pt-BR"}, versions[0].TranslationLocales)
+
+	// Without re-signature the next template update is a new minor version
+	pdfURLs, err = service.CreateCLAGroupTemplate(ctx, "cla-group", fields, "admin")
+	assert.Nil(t, err)
+	assert.Equal(t, "http://localhost:8080/blobs/contract-group/cla-group/template/icla/2.1.pdf", pdfURLs.IndividualPDFURL)
+	assert.True(t, bytes.HasPrefix([]byte(readBlob(t, blobStore, "contract-group/cla-group/template/icla/2.1.pt-BR.pdf")), []byte("%PDF-")))
+
+	// The CLA Group documents resolve the PDF of each locale
+	documents := []models.ProjectDocument{
+		{DocumentMajorVersion: "1", DocumentMinorVersion: "0", DocumentS3URL: "1.0.pdf"},
+		{
+			DocumentMajorVersion: "2",
+			DocumentMinorVersion: "0",
+			DocumentS3URL:        "2.0.pdf",
+			DocumentLocale:       "en",
+			DocumentTranslations: []models.ProjectDocumentTranslation{{Locale: "pt-BR", DocumentS3URL: "2.0.pt-BR.pdf"}},
+		},
+	}
+	latest := template.LatestProjectDocument(documents)
+	assert.NotNil(t, latest)
+	url, err := template.ProjectDocumentURL(*latest, "pt-BR")
+	assert.Nil(t, err)
+	assert.Equal(t, "2.0.pt-BR.pdf", url)
+	_, err = template.ProjectDocumentURL(*latest, "fr")
+	assert.Equal(t, template.ErrLocaleNotFound, err)
+	assert.Equal(t, template.DefaultLocale, template.ProjectDocumentLocale(documents[0]))
+}
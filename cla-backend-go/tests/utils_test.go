@@ -226,6 +226,74 @@ func TestValidDomain(t *testing.T) {
 	}
 }
 
+// TestValidDomainPattern tests the domain approval list entry validator
+func TestValidDomainPattern(t *testing.T) {
+	validPatterns := []string{
+		"acme.com",
+		"*.acme.com",
+		"*.acme.co.uk",
+		" *.eng.acme.com ",
+	}
+	inValidPatterns := []string{
+		"*",
+		"*.",
+		"*.com",
+		"*acme.com",
+		".acme.com",
+		"eng.*.acme.com",
+		"*.*.acme.com",
+		"acme.*",
+	}
+
+	for _, pattern := range validPatterns {
+		msg, valid := utils.ValidDomainPattern(pattern)
+		assert.True(t, valid, fmt.Sprintf("valid domain pattern %s %s", pattern, msg))
+	}
+
+	for _, pattern := range inValidPatterns {
+		msg, valid := utils.ValidDomainPattern(pattern)
+		assert.False(t, valid, fmt.Sprintf("invalid domain pattern %s %s", pattern, msg))
+	}
+}
+
+// TestMatchEmailDomain tests matching email addresses against domain approval list entries
+func TestMatchEmailDomain(t *testing.T) {
+	// exact entries only cover the domain itself
+	matched, ok := utils.MatchEmailDomain("user@acme.com", []string{"acme.com"})
+	assert.True(t, ok)
+	assert.Equal(t, "acme.com", matched)
+	_, ok = utils.MatchEmailDomain("user@eng.acme.com", []string{"acme.com"})
+	assert.False(t, ok)
+
+	// wildcard entries cover the domain and all sub-domains, but only on a label boundary
+	for _, email := range []string{"user@acme.com", "user@eng.acme.com", "User@Build.Eng.ACME.com"} {
+		matched, ok = utils.MatchEmailDomain(email, []string{"*.acme.com"})
+		assert.True(t, ok, email)
+		assert.Equal(t, "*.acme.com", matched)
+	}
+	_, ok = utils.MatchEmailDomain("user@notacme.com", []string{"*.acme.com"})
+	assert.False(t, ok)
+	_, ok = utils.MatchEmailDomain("user@acme.com.evil.org", []string{"*.acme.com"})
+	assert.False(t, ok)
+
+	// legacy wildcard forms follow the same rule
+	for _, pattern := range []string{"*acme.com", ".acme.com"} {
+		_, ok = utils.MatchEmailDomain("user@eng.acme.com", []string{pattern})
+		assert.True(t, ok, pattern)
+		_, ok = utils.MatchEmailDomain("user@notacme.com", []string{pattern})
+		assert.False(t, ok, pattern)
+	}
+
+	// exact entries are reported before wildcard entries, then the most specific wildcard entry
+	matched, _ = utils.MatchEmailDomain("user@eng.acme.com", []string{"*.acme.com", "eng.acme.com"})
+	assert.Equal(t, "eng.acme.com", matched)
+	matched, _ = utils.MatchEmailDomain("user@build.eng.acme.com", []string{"*.acme.com", "*.eng.acme.com"})
+	assert.Equal(t, "*.eng.acme.com", matched)
+
+	_, ok = utils.MatchEmailDomain("not-an-email", []string{"*.acme.com"})
+	assert.False(t, ok)
+}
+
 // TestGitHubUsername tests the GitHub username validator
 func TestGitHubUsername(t *testing.T) {
 	validGitHubUsername := []string{
@@ -297,3 +365,30 @@ func TestGitHubOrg(t *testing.T) {
 		assert.False(t, valid, fmt.Sprintf("invalid GitHub Organization %s %s", org, msg))
 	}
 }
+
+// TestGitHubTeam tests the GitHub team validator
+func TestGitHubTeam(t *testing.T) {
+	validGitHubTeam := []string{
+		"linuxfoundation/easycla",
+		"linuxfoundation/easycla-maintainers",
+		"user-123/team_1",
+	}
+	inValidGitHubTeam := []string{
+		"linuxfoundation",
+		"linuxfoundation/",
+		"/easycla",
+		"li/easycla", // organization too short
+		"linuxfoundation/easycla/maintainers",
+		"linuxfoundation/easy cla",
+	}
+
+	for _, team := range validGitHubTeam {
+		msg, valid := utils.ValidGitHubTeam(team)
+		assert.True(t, valid, fmt.Sprintf("valid GitHub Team %s %s", team, msg))
+	}
+
+	for _, team := range inValidGitHubTeam {
+		msg, valid := utils.ValidGitHubTeam(team)
+		assert.False(t, valid, fmt.Sprintf("invalid GitHub Team %s %s", team, msg))
+	}
+}
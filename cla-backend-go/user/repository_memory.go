@@ -0,0 +1,121 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// RepositoryMemory data model
+type RepositoryMemory struct {
+	Stage string
+	Store *storage.MemoryStore
+}
+
+// userPermissions is the user permissions table data model
+type userPermissions struct {
+	Username string   `dynamodbav:"username"`
+	Projects []string `dynamodbav:"projects,stringset"`
+}
+
+// NewMemoryRepository creates a new in-memory repository model
+func NewMemoryRepository(store *storage.MemoryStore, stage string) RepositoryService {
+	return RepositoryMemory{
+		Stage: stage,
+		Store: store,
+	}
+}
+
+// GetUserAndProfilesByLFID returns the user profile by LFID
+func (repo RepositoryMemory) GetUserAndProfilesByLFID(lfidUsername string) (CLAUser, error) {
+	var users []User
+	err := repo.Store.Scan(fmt.Sprintf("cla-%s-users", repo.Stage), &users)
+	if err != nil {
+		log.Warnf("problem querying the store for user: %s , error: %+v", lfidUsername, err)
+		return CLAUser{
+			LFUsername: lfidUsername,
+		}, err
+	}
+
+	for _, user := range users {
+		if user.LFUsername == lfidUsername {
+			return CLAUser{
+				UserID:     user.UserID,
+				Name:       user.UserName,
+				LFEmail:    user.LFEmail,
+				LFUsername: user.LFUsername,
+			}, nil
+		}
+	}
+
+	log.Debugf("Get User And Profiles By LFID - user not found given LFID: %s", lfidUsername)
+	return CLAUser{
+		LFUsername: lfidUsername,
+	}, errors.New("user not found")
+}
+
+// GetUserProjectIDs returns a list of user's projects when provided the user id
+func (repo RepositoryMemory) GetUserProjectIDs(LfUsername string) ([]string, error) {
+	var permissions userPermissions
+	_, err := repo.Store.Get(fmt.Sprintf("cla-%s-user-permissions", repo.Stage), LfUsername, &permissions)
+	if err != nil {
+		log.Warnf("error fetching user project IDs: error: %v", err)
+		return []string{}, err
+	}
+	if permissions.Projects == nil {
+		return []string{}, nil
+	}
+	return permissions.Projects, nil
+}
+
+// GetClaManagerCorporateClaIDs returns a list of corporate CLAs when provided the user ID
+func (repo RepositoryMemory) GetClaManagerCorporateClaIDs(userID string) ([]string, error) {
+	return []string{}, nil
+}
+
+// GetUserCompanyIDs returns a list of company IDs associated with the specified user
+func (repo RepositoryMemory) GetUserCompanyIDs(userID string) ([]string, error) {
+	return []string{}, nil
+}
+
+// GetUser returns the user model when provided the user ID
+func (repo RepositoryMemory) GetUser(userID string) (User, error) {
+	user := User{}
+	_, err := repo.Store.Get(fmt.Sprintf("cla-%s-users", repo.Stage), userID, &user)
+	if err != nil {
+		log.Warnf("Error fetching user: %s, error: %v", userID, err)
+		return User{}, err
+	}
+	return user, nil
+}
+
+// SetCompanyID sets the specified user's company id
+func (repo RepositoryMemory) SetCompanyID(userID, companyID string) (*User, error) {
+	_, now := utils.CurrentTime()
+	err := repo.Store.UpdateItem(fmt.Sprintf("cla-%s-users", repo.Stage), userID, func(item map[string]*dynamodb.AttributeValue) error {
+		item["company_id"] = &dynamodb.AttributeValue{S: aws.String(companyID)}
+		item["date_modified"] = &dynamodb.AttributeValue{S: aws.String(now)}
+		return nil
+	})
+	if err != nil {
+		log.Warnf("Error updating User: %s with Company ID: %s, error: %v",
+			userID, companyID, err)
+		return nil, err
+	}
+
+	user, err := repo.GetUser(userID)
+	if err != nil {
+		log.Warnf("Error fetching user record by ID: %s, error: %v",
+			userID, err)
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,252 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package users
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/go-openapi/errors"
+	"github.com/google/uuid"
+)
+
+// memoryRepository data model
+type memoryRepository struct {
+	store     *storage.MemoryStore
+	tableName string
+}
+
+// NewMemoryRepository creates a new instance of the users repository backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string) UserRepository {
+	return memoryRepository{
+		store:     store,
+		tableName: fmt.Sprintf("cla-%s-users", stage),
+	}
+}
+
+// CreateUser creates a new user
+func (repo memoryRepository) CreateUser(user *models.User) (*models.User, error) {
+	theUUID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	user.UserID = theUUID.String()
+	user.DateCreated = now
+	user.DateModified = now
+	user.Version = "v1"
+
+	dbUser := DBUser{
+		UserID:             user.UserID,
+		UserExternalID:     user.UserExternalID,
+		Admin:              user.Admin,
+		LFEmail:            user.LfEmail,
+		LFUsername:         user.LfUsername,
+		UserName:           user.Username,
+		UserGithubID:       user.GithubID,
+		UserGithubUsername: user.GithubUsername,
+		DateCreated:        now,
+		DateModified:       now,
+		Version:            "v1",
+	}
+	if err := repo.store.Put(repo.tableName, dbUser.UserID, dbUser); err != nil {
+		log.Warnf("unable to create user: %+v, error: %v", user, err)
+		return nil, err
+	}
+
+	log.Debugf("Created new user: %+v", user)
+	return user, nil
+}
+
+// Save saves the user model to the data store
+func (repo memoryRepository) Save(user *models.UserUpdate) (*models.User, error) {
+	var oldUserModel *models.User
+	var err error
+	if user.LfUsername != "" {
+		oldUserModel, err = repo.GetUserByUserName(user.LfUsername, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if oldUserModel == nil && user.GithubUsername != "" {
+		oldUserModel, err = repo.GetUserByGitHubUsername(user.GithubUsername)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if oldUserModel == nil {
+		log.Warnf("error fetching existing user record: %+v", user)
+		return nil, nil
+	}
+
+	var dbUser DBUser
+	err = repo.store.Update(repo.tableName, oldUserModel.UserID, &dbUser, func() error {
+		if user.LfEmail != "" {
+			dbUser.LFEmail = user.LfEmail
+		}
+		if user.LfUsername != "" {
+			dbUser.LFUsername = user.LfUsername
+		}
+		if user.CompanyID != "" {
+			dbUser.UserCompanyID = user.CompanyID
+		}
+		if user.GithubUsername != "" {
+			dbUser.UserGithubUsername = user.GithubUsername
+		}
+		if user.GithubID != "" {
+			dbUser.UserGithubID = user.GithubID
+		}
+		dbUser.DateModified = time.Now().UTC().Format(time.RFC3339)
+		return nil
+	})
+	if err != nil {
+		log.Warnf("Error updating user record: %+v, error: %v", user, err)
+		return nil, err
+	}
+
+	return convertDBUserModel(dbUser), nil
+}
+
+// Delete deletes the specified user
+func (repo memoryRepository) Delete(userID string) error {
+	return repo.store.Delete(repo.tableName, userID)
+}
+
+// GetUser retrieves the specified user using the user id
+func (repo memoryRepository) GetUser(userID string) (*models.User, error) {
+	var dbUser DBUser
+	found, err := repo.store.Get(repo.tableName, userID, &dbUser)
+	if err != nil {
+		log.Warnf("Error retrieving user by user_id: %s, error: %+v", userID, err)
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return convertDBUserModel(dbUser), nil
+}
+
+// GetUserByLFUserName returns the user record associated with the LF Username value
+func (repo memoryRepository) GetUserByLFUserName(lfUserName string) (*models.User, error) {
+	dbUsers, err := repo.scan(func(user *DBUser) bool {
+		return user.LFUsername == lfUserName
+	})
+	if err != nil || len(dbUsers) == 0 {
+		return nil, err
+	}
+	return convertDBUserModel(dbUsers[0]), nil
+}
+
+// GetUserByUserName returns the user record associated with the user name - either the LF username or a
+// github:<github id> value
+func (repo memoryRepository) GetUserByUserName(userName string, fullMatch bool) (*models.User, error) {
+	filter := func(user *DBUser) bool {
+		return user.LFUsername == userName
+	}
+	if strings.Contains(userName, "github:") {
+		githubID := strings.Replace(userName, "github:", "", 1)
+		filter = func(user *DBUser) bool {
+			return user.UserGithubID == githubID
+		}
+	}
+
+	dbUsers, err := repo.scan(filter)
+	if err != nil || len(dbUsers) == 0 {
+		return nil, err
+	}
+	return convertDBUserModel(dbUsers[0]), nil
+}
+
+// GetUserByEmail fetches the user record by email
+func (repo memoryRepository) GetUserByEmail(userEmail string) (*models.User, error) {
+	dbUsers, err := repo.scan(func(user *DBUser) bool {
+		return user.LFEmail == userEmail
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(dbUsers) == 0 {
+		return nil, errors.NotFound("user not found when searching by lf_email: %s", userEmail)
+	}
+	return convertDBUserModel(dbUsers[0]), nil
+}
+
+// GetUserByGitHubUsername fetches the user record by github username
+func (repo memoryRepository) GetUserByGitHubUsername(gitHubUsername string) (*models.User, error) {
+	dbUsers, err := repo.scan(func(user *DBUser) bool {
+		return user.UserGithubUsername == gitHubUsername
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(dbUsers) == 0 {
+		return nil, errors.NotFound("user not found when searching by user_github_username: %s", gitHubUsername)
+	}
+	return convertDBUserModel(dbUsers[0]), nil
+}
+
+// SearchUsers returns the users where the search field matches the search term
+func (repo memoryRepository) SearchUsers(searchField string, searchTerm string, fullMatch bool) (*models.Users, error) {
+	if strings.TrimSpace(searchTerm) == "" || strings.TrimSpace(searchField) == "" {
+		return &models.Users{
+			Users:      []models.User{},
+			SearchTerm: searchTerm,
+		}, nil
+	}
+
+	// The search field is a column name, so we also load the raw records to look it up - both scans return the
+	// records in key order
+	var dbUsers []DBUser
+	if err := repo.store.Scan(repo.tableName, &dbUsers); err != nil {
+		log.Warnf("error retrieving users for search term: %s, error: %v", searchTerm, err)
+		return nil, err
+	}
+	var items []map[string]interface{}
+	if err := repo.store.Scan(repo.tableName, &items); err != nil {
+		log.Warnf("error retrieving users for search term: %s, error: %v", searchTerm, err)
+		return nil, err
+	}
+
+	var users []models.User
+	for i, item := range items {
+		value, ok := item[searchField]
+		if !ok {
+			continue
+		}
+		fieldValue := fmt.Sprint(value)
+		if (fullMatch && fieldValue == searchTerm) || (!fullMatch && strings.Contains(fieldValue, searchTerm)) {
+			users = append(users, *convertDBUserModel(dbUsers[i]))
+		}
+	}
+
+	return &models.Users{
+		ResultCount: int64(len(users)),
+		TotalCount:  int64(len(dbUsers)),
+		Users:       users,
+	}, nil
+}
+
+// scan returns the users matching the filter
+func (repo memoryRepository) scan(filter func(user *DBUser) bool) ([]DBUser, error) {
+	var dbUsers []DBUser
+	if err := repo.store.Scan(repo.tableName, &dbUsers); err != nil {
+		log.Warnf("error retrieving users, error: %v", err)
+		return nil, err
+	}
+	var matched []DBUser
+	for i := range dbUsers {
+		if filter(&dbUsers[i]) {
+			matched = append(matched, dbUsers[i])
+		}
+	}
+	if len(matched) > 1 {
+		log.Warnf("retrieved %d user results when we should return 0 or 1", len(matched))
+	}
+	return matched, nil
+}
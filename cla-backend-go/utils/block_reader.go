@@ -0,0 +1,93 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// RangeFetcher returns the content of the byte range of a remote file, start and end included
+type RangeFetcher func(start, end int64) (io.ReadCloser, error)
+
+// BlockReaderAt reads a remote file of a known size with ranged requests. The file is read by blocks and the last
+// block read is cached, so that sequential reads - reading the entries of a zip for example - only request each block
+// once and the memory used is bounded by the block size.
+type BlockReaderAt struct {
+	size       int64
+	blockSize  int64
+	fetch      RangeFetcher
+	mu         sync.Mutex
+	block      []byte
+	blockStart int64
+}
+
+// NewBlockReaderAt returns a reader of the remote file of the given size, read by blocks of blockSize bytes
+func NewBlockReaderAt(size, blockSize int64, fetch RangeFetcher) *BlockReaderAt {
+	return &BlockReaderAt{
+		size:      size,
+		blockSize: blockSize,
+		fetch:     fetch,
+	}
+}
+
+// Size returns the size of the remote file
+func (r *BlockReaderAt) Size() int64 {
+	return r.size
+}
+
+// ReadAt implements io.ReaderAt
+func (r *BlockReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for n < len(p) && off < r.size {
+		if off < r.blockStart || off >= r.blockStart+int64(len(r.block)) {
+			if err := r.load(off); err != nil {
+				return n, err
+			}
+		}
+		copied := copy(p[n:], r.block[off-r.blockStart:])
+		n += copied
+		off += int64(copied)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// load reads the block starting at the offset, the block buffer is reused
+func (r *BlockReaderAt) load(off int64) error {
+	end := off + r.blockSize - 1
+	if end >= r.size {
+		end = r.size - 1
+	}
+	body, err := r.fetch(off, end)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := body.Close(); closeErr != nil {
+			log.Warnf("problem closing the range %d-%d response body, error: %+v", off, end, closeErr)
+		}
+	}()
+	length := int(end - off + 1)
+	if cap(r.block) < length {
+		r.block = make([]byte, length)
+	}
+	r.block = r.block[:length]
+	if _, err = io.ReadFull(body, r.block); err != nil {
+		r.block = r.block[:0]
+		return err
+	}
+	r.blockStart = off
+	return nil
+}
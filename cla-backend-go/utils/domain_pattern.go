@@ -0,0 +1,124 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DomainWildcardPrefix is the prefix of a domain approval list entry which covers a domain and all of its sub-domains
+const DomainWildcardPrefix = "*."
+
+// Domain approval list matching rules:
+//
+//   - an exact entry, e.g. acme.com, matches only that domain - eng.acme.com is not covered by it
+//   - a wildcard entry, e.g. *.acme.com, matches acme.com and any sub-domain of acme.com at any depth,
+//     e.g. eng.acme.com and build.eng.acme.com - it never matches a domain that merely ends with the
+//     same characters, such as notacme.com
+//   - exact and wildcard entries are independent list values: an exact entry that is also covered by a
+//     wildcard entry is redundant but allowed, and removing one never removes the other
+//   - when more than one entry covers a domain, an exact entry is reported first, then the most specific
+//     (longest) wildcard entry
+//   - comparisons are case-insensitive
+//
+// Legacy entries written before wildcards were validated (*acme.com and .acme.com) are treated as *.acme.com
+// when matching, new entries must use either the exact or the *. form.
+
+// NormalizeDomainPattern returns the canonical form of the specified domain approval list entry - trimmed,
+// lower case, with legacy wildcard prefixes rewritten to the *. form
+func NormalizeDomainPattern(pattern string) string {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	switch {
+	case strings.HasPrefix(pattern, DomainWildcardPrefix):
+		return pattern
+	case strings.HasPrefix(pattern, "*"):
+		return DomainWildcardPrefix + strings.TrimLeft(pattern, "*.")
+	case strings.HasPrefix(pattern, "."):
+		return DomainWildcardPrefix + strings.TrimLeft(pattern, ".")
+	}
+	return pattern
+}
+
+// IsWildcardDomainPattern returns true if the specified domain approval list entry is a wildcard entry
+func IsWildcardDomainPattern(pattern string) bool {
+	return strings.HasPrefix(NormalizeDomainPattern(pattern), DomainWildcardPrefix)
+}
+
+// ValidDomainPattern tests the specified domain approval list entry, returns true if the entry is either a valid
+// domain or a *. prefixed valid domain, returns false and a message otherwise
+func ValidDomainPattern(pattern string) (string, bool) {
+	pattern = strings.TrimSpace(pattern)
+	if !strings.HasPrefix(pattern, DomainWildcardPrefix) {
+		if strings.Contains(pattern, "*") {
+			return "wildcard '*' is only allowed as a leading '*.' label", false
+		}
+		return ValidDomain(pattern)
+	}
+
+	domain := strings.TrimPrefix(pattern, DomainWildcardPrefix)
+	if strings.Contains(domain, "*") {
+		return "wildcard '*' is only allowed once, as a leading '*.' label", false
+	}
+	if msg, valid := ValidDomain(domain); !valid {
+		return msg, false
+	}
+	if !strings.Contains(domain, ".") {
+		return fmt.Sprintf("wildcard domain can't cover the top level domain '%s'", domain), false
+	}
+
+	return "", true
+}
+
+// DomainMatchesPattern returns true if the specified domain is covered by the domain approval list entry
+func DomainMatchesPattern(domain, pattern string) bool {
+	domain = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+	pattern = NormalizeDomainPattern(pattern)
+	if domain == "" || pattern == "" || pattern == DomainWildcardPrefix {
+		return false
+	}
+
+	if !strings.HasPrefix(pattern, DomainWildcardPrefix) {
+		return domain == pattern
+	}
+
+	base := strings.TrimPrefix(pattern, DomainWildcardPrefix)
+	return domain == base || strings.HasSuffix(domain, "."+base)
+}
+
+// EmailDomain returns the lower case domain part of the specified email address, or an empty string if the email
+// has no domain part
+func EmailDomain(email string) string {
+	email = strings.TrimSpace(email)
+	idx := strings.LastIndex(email, "@")
+	if idx < 0 || idx == len(email)-1 {
+		return ""
+	}
+	return strings.ToLower(email[idx+1:])
+}
+
+// MatchEmailDomain checks the domain of the specified email address against the domain approval list entries,
+// returns the entry which covers the email and true, or an empty string and false if no entry covers it
+func MatchEmailDomain(email string, patterns []string) (string, bool) {
+	domain := EmailDomain(email)
+	if domain == "" {
+		return "", false
+	}
+
+	var matched string
+	for _, pattern := range patterns {
+		if !DomainMatchesPattern(domain, pattern) {
+			continue
+		}
+		if !IsWildcardDomainPattern(pattern) {
+			// exact entries take precedence over any wildcard entry
+			return pattern, true
+		}
+		if len(NormalizeDomainPattern(pattern)) > len(NormalizeDomainPattern(matched)) {
+			matched = pattern
+		}
+	}
+
+	return matched, matched != ""
+}
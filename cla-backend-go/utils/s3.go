@@ -2,24 +2,29 @@ package utils
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"io/ioutil"
 	"strings"
 	"time"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
-
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 )
 
 // PresignedURLValidity is time for which s3 url will remain valid
 const PresignedURLValidity = 15 * time.Minute
 
+// DocumentDigestMetadataKey is the s3 object metadata key holding the SHA-256 digest of the uploaded document
+const DocumentDigestMetadataKey = "sha256"
+
+// ErrS3FileNotFound is returned when the requested file does not exist in the s3 bucket
+var ErrS3FileNotFound = errors.New("file not found in s3 bucket")
+
 // S3Storage provides methods to handle s3 storage
 type S3Storage interface {
-	Upload(fileContent []byte, projectID string, claType string, identifier string, signatureID string) error
+	Upload(fileContent []byte, projectID string, claType string, identifier string, signatureID string) (string, error)
 	Download(filename string) ([]byte, error)
 	Delete(filename string) error
 	GetPresignedURL(filename string) (string, error)
@@ -27,49 +32,56 @@ type S3Storage interface {
 
 var s3Storage S3Storage
 
-// S3Client struct provide methods to interact with s3
-type S3Client struct {
-	s3         *s3.S3
-	BucketName string
+// BlobClient struct provide methods to interact with the configured blob storage - the AWS S3 bucket, an S3
+// compatible server or the local filesystem
+type BlobClient struct {
+	store storage.BlobStore
 }
 
-// SetS3Storage set default S3Storage
-func SetS3Storage(awsSession *session.Session, bucketName string) {
-	s3Storage = &S3Client{
-		s3:         s3.New(awsSession),
-		BucketName: bucketName,
+// SetBlobStorage set default S3Storage to the blob store
+func SetBlobStorage(store storage.BlobStore) {
+	s3Storage = &BlobClient{
+		store: store,
 	}
 }
 
 // Upload file to s3 storage at path contract-group/<project-ID>/<claType>/<identifier>/<signatureID>.pdf
 // claType should be cla or ccla
 // identifier can be user-id or company-id
-func (s3c *S3Client) Upload(fileContent []byte, projectID string, claType string, identifier string, signatureID string) error {
-	filename := strings.Join([]string{"contract-group", projectID, claType, identifier, signatureID}, "/") + ".pdf"
-	_, err := s3c.s3.PutObject(&s3.PutObjectInput{
-		Bucket: aws.String(s3c.BucketName),
-		Key:    aws.String(filename),
-		Body:   bytes.NewReader(fileContent),
+// returns the SHA-256 digest of the file, which is also stored in the object metadata
+func (c *BlobClient) Upload(fileContent []byte, projectID string, claType string, identifier string, signatureID string) (string, error) {
+	filename := SignedCLAFilename(projectID, claType, identifier, signatureID)
+	digest := DocumentDigest(fileContent)
+	err := c.store.Put(filename, bytes.NewReader(fileContent), &storage.PutOptions{
+		Metadata: map[string]string{
+			DocumentDigestMetadataKey: digest,
+		},
 	})
-	return err
+	if err != nil {
+		return "", err
+	}
+	return digest, nil
 }
 
 // Download file from s3
-func (s3c *S3Client) Download(filename string) ([]byte, error) {
-	ou, err := s3c.s3.GetObject(&s3.GetObjectInput{
-		Bucket: aws.String(s3c.BucketName),
-		Key:    aws.String(filename),
-	})
+func (c *BlobClient) Download(filename string) ([]byte, error) {
+	rc, err := c.store.Get(filename)
 	if err != nil {
-		log.Warnf("problem downloading from s3 bucket: %s resource: %s, error: %+v",
-			s3c.BucketName, filename, err)
+		if err == storage.ErrBlobNotFound {
+			return nil, ErrS3FileNotFound
+		}
+		log.Warnf("problem downloading resource: %s, error: %+v", filename, err)
 		return nil, err
 	}
+	defer func() {
+		if closeErr := rc.Close(); closeErr != nil {
+			log.Warnf("problem closing resource: %s, error: %+v", filename, closeErr)
+		}
+	}()
 
-	body, err := ioutil.ReadAll(ou.Body)
+	body, err := ioutil.ReadAll(rc)
 	if err != nil {
-		log.Warnf("problem reading file from s3 bucket: %s resource: %s, error: %+v",
-			s3c.BucketName, filename, err)
+		log.Warnf("problem reading resource: %s, error: %+v", filename, err)
 		return nil, err
 	}
 
@@ -77,38 +89,33 @@ func (s3c *S3Client) Download(filename string) ([]byte, error) {
 }
 
 // Delete file from s3
-func (s3c *S3Client) Delete(filename string) error {
-	_, err := s3c.s3.DeleteObject(&s3.DeleteObjectInput{
-		Bucket: &s3c.BucketName,
-		Key:    &filename,
-	})
-	return err
+func (c *BlobClient) Delete(filename string) error {
+	return c.store.Delete(filename)
 }
 
 // GetPresignedURL provided presigned url for download
-func (s3c *S3Client) GetPresignedURL(filename string) (string, error) {
-	req, _ := s3c.s3.GetObjectRequest(&s3.GetObjectInput{
-		Bucket: &s3c.BucketName,
-		Key:    &filename,
-	})
-	url, err := req.Presign(PresignedURLValidity)
-	if err != nil {
-		return "", err
-	}
-	return url, nil
+func (c *BlobClient) GetPresignedURL(filename string) (string, error) {
+	return c.store.DownloadURL(filename, PresignedURLValidity)
 }
 
 // UploadToS3 uploads file to s3 storage at path contract-group/<project-ID>/<claType>/<identifier>/<signatureID>.pdf
 // claType should be cla or ccla
 // identifier can be user-id or company-id
-func UploadToS3(body []byte, projectID string, claType string, identifier string, signatureID string) error {
+// returns the SHA-256 digest of the file to persist on the signature record
+func UploadToS3(body []byte, projectID string, claType string, identifier string, signatureID string) (string, error) {
 	if s3Storage == nil {
-		return errors.New("s3Storage not set")
+		return "", errors.New("s3Storage not set")
 	}
 	return s3Storage.Upload(body, projectID, claType, identifier, signatureID)
 }
 
-// DownloadFromS3 downloads file from s3
+// DocumentDigest returns the hex encoded SHA-256 digest of the document content
+func DocumentDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// DownloadFromS3 downloads file from s3, returns ErrS3FileNotFound if the file does not exist
 func DownloadFromS3(filename string) ([]byte, error) {
 	if s3Storage == nil {
 		return nil, errors.New("s3Storage not set")
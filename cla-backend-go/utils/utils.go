@@ -249,3 +249,33 @@ func ValidGitHubOrg(githubOrg string) (string, bool) {
 
 	return "", true
 }
+
+// SplitGitHubTeam splits the specified GitHub team approval list entry, in the form organization/team-slug, into the
+// organization and team slug - returns empty values if the entry is not in that form
+func SplitGitHubTeam(githubTeam string) (string, string) {
+	parts := strings.Split(strings.TrimSpace(githubTeam), "/")
+	if len(parts) != 2 {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
+
+// ValidGitHubTeam tests the specified GitHub team string, in the form organization/team-slug, returns true if valid,
+// returns false otherwise
+func ValidGitHubTeam(githubTeam string) (string, bool) {
+	githubOrg, teamSlug := SplitGitHubTeam(githubTeam)
+	if githubOrg == "" || teamSlug == "" {
+		return fmt.Sprintf("invalid GitHub team: %s - expecting organization/team-slug", githubTeam), false
+	}
+
+	if msg, valid := ValidGitHubOrg(githubOrg); !valid {
+		return msg, false
+	}
+
+	re := regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+	if !re.MatchString(teamSlug) {
+		return fmt.Sprintf("invalid GitHub team slug: %s", teamSlug), false
+	}
+
+	return "", true
+}
@@ -280,7 +280,7 @@ func (s *service) CreateCLAGroup(input *models.CreateClaGroupInput, projectManag
 		log.WithFields(f).Debug("using apache style template as template_id is not passed")
 		templateFields.TemplateID = v1Template.ApacheStyleTemplateID
 	}
-	pdfUrls, err := s.v1TemplateService.CreateCLAGroupTemplate(context.Background(), claGroup.ProjectID, &templateFields)
+	pdfUrls, err := s.v1TemplateService.CreateCLAGroupTemplate(context.Background(), claGroup.ProjectID, &templateFields, projectManagerLFID)
 	if err != nil {
 		log.WithFields(f).Error("attaching cla_group_template failed", err)
 		log.WithFields(f).Debug("deleting created cla group")
@@ -568,6 +568,27 @@ func getS3Url(claGroupID string, docs []v1Models.ProjectDocument) string {
 	return url
 }
 
+// getLanguages returns the languages the latest CLA Group document is available in, the legally binding language first
+func getLanguages(docs []v1Models.ProjectDocument) []*models.ClaGroupLanguage {
+	languages := make([]*models.ClaGroupLanguage, 0)
+	document := v1Template.LatestProjectDocument(docs)
+	if document == nil {
+		return languages
+	}
+	languages = append(languages, &models.ClaGroupLanguage{
+		Locale:         v1Template.ProjectDocumentLocale(*document),
+		LegallyBinding: true,
+		PdfURL:         document.DocumentS3URL,
+	})
+	for _, translation := range document.DocumentTranslations {
+		languages = append(languages, &models.ClaGroupLanguage{
+			Locale: translation.Locale,
+			PdfURL: translation.DocumentS3URL,
+		})
+	}
+	return languages
+}
+
 // ListClaGroupsForFoundationOrProject returns the CLA Group list for the specified foundation ID
 func (s *service) ListClaGroupsForFoundationOrProject(foundationSFID string) (*models.ClaGroupList, error) {
 	out := &models.ClaGroupList{List: make([]*models.ClaGroup, 0)}
@@ -601,6 +622,8 @@ func (s *service) ListClaGroupsForFoundationOrProject(foundationSFID string) (*m
 			IclaEnabled:         v1ClaGroup.ProjectICLAEnabled,
 			CclaPdfURL:          getS3Url(v1ClaGroup.ProjectID, v1ClaGroup.ProjectCorporateDocuments),
 			IclaPdfURL:          getS3Url(v1ClaGroup.ProjectID, v1ClaGroup.ProjectIndividualDocuments),
+			CclaLanguages:       getLanguages(v1ClaGroup.ProjectCorporateDocuments),
+			IclaLanguages:       getLanguages(v1ClaGroup.ProjectIndividualDocuments),
 			ProjectList:         make([]*models.ClaGroupProject, 0),
 			// Add root_project_repositories_count to repositories_count initially
 			RepositoriesCount:            v1ClaGroup.RootProjectRepositoriesCount,
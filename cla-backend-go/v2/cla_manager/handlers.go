@@ -85,6 +85,9 @@ func Configure(api *operations.EasyclaAPI, service Service, LfxPortalURL string,
 
 		errResponse := service.DeleteCLAManager(cginfo.ClaGroupID, params)
 		if errResponse != nil {
+			if errResponse.Code == Conflict {
+				return cla_manager.NewDeleteCLAManagerConflict().WithPayload(errResponse)
+			}
 			return cla_manager.NewDeleteCLAManagerBadRequest().WithPayload(errResponse)
 		}
 
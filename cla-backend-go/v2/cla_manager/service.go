@@ -27,6 +27,7 @@ import (
 	v1ClaManager "github.com/communitybridge/easycla/cla-backend-go/cla_manager"
 	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	v1Signatures "github.com/communitybridge/easycla/cla-backend-go/signatures"
 	v1User "github.com/communitybridge/easycla/cla-backend-go/user"
 	easyCLAUser "github.com/communitybridge/easycla/cla-backend-go/users"
 	v2AcsService "github.com/communitybridge/easycla/cla-backend-go/v2/acs-service"
@@ -255,9 +256,13 @@ func (s *service) CreateCLAManager(claGroupID string, params cla_manager.CreateC
 	if addErr != nil {
 		msg := buildErrorMessageCreate(params, addErr)
 		log.Warn(msg)
+		code := BadRequest
+		if _, ok := addErr.(*v1Signatures.ConflictError); ok {
+			code = Conflict
+		}
 		return nil, &models.ErrorResponse{
 			Message: msg,
-			Code:    "400",
+			Code:    code,
 		}
 	}
 	if signature == nil {
@@ -442,9 +447,13 @@ func (s *service) DeleteCLAManager(claGroupID string, params cla_manager.DeleteC
 	if deleteErr != nil {
 		msg := buildErrorMessageDelete(params, deleteErr)
 		log.Warn(msg)
+		code := BadRequest
+		if _, ok := deleteErr.(*v1Signatures.ConflictError); ok {
+			code = Conflict
+		}
 		return &models.ErrorResponse{
 			Message: msg,
-			Code:    "400",
+			Code:    code,
 		}
 	}
 	if signature == nil {
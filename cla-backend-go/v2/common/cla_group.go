@@ -0,0 +1,42 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// LoadCLAGroup returns the CLA Group of the request if the user is authorized for its project, otherwise returns the
+// error response along with its HTTP status code - 404 if the CLA Group does not exist, 403 if the user is not
+// authorized and 500 if the CLA Group can't be loaded. The operation is the name of the request in the 403 message.
+func LoadCLAGroup(projectRepo project.ProjectRepository, authUser *auth.User, xUserName, xEmail *string, claGroupID, operation string) (*v1Models.Project, *models.ErrorResponse, int) {
+	utils.SetAuthUserProperties(authUser, xUserName, xEmail)
+	claGroupModel, err := projectRepo.GetCLAGroupByID(claGroupID, project.DontLoadRepoDetails)
+	if err != nil {
+		if err == project.ErrProjectDoesNotExist {
+			return nil, &models.ErrorResponse{
+				Code:    "404",
+				Message: fmt.Sprintf("EasyCLA - 404 Not Found - cla_group %s not found", claGroupID),
+			}, 404
+		}
+		return nil, &models.ErrorResponse{
+			Code:    "500",
+			Message: err.Error(),
+		}, 500
+	}
+	if !utils.IsUserAuthorizedForProject(authUser, claGroupModel.FoundationSFID) {
+		return nil, &models.ErrorResponse{
+			Code: "403",
+			Message: fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to %s with project scope of %s",
+				authUser.UserName, operation, claGroupModel.FoundationSFID),
+		}, 403
+	}
+	return claGroupModel, nil, 0
+}
@@ -0,0 +1,88 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package company_merge
+
+import (
+	"fmt"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/company_merge"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+// Configure setups handlers on api with service
+func Configure(api *operations.EasyclaAPI, service Service) {
+	api.CompanyMergeMergeCompaniesHandler = company_merge.MergeCompaniesHandlerFunc(
+		func(params company_merge.MergeCompaniesParams, authUser *auth.User) middleware.Responder {
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			sourceCompanyID := utils.StringValue(params.Body.SourceCompanyID)
+			f := logrus.Fields{
+				"functionName":    "MergeCompaniesHandler",
+				"sourceCompanyID": sourceCompanyID,
+				"targetCompanyID": params.CompanyID,
+				"dryRun":          params.Body.DryRun,
+				"authUser":        authUser.UserName,
+			}
+			if !utils.IsUserAdmin(authUser) {
+				return company_merge.NewMergeCompaniesForbidden().WithPayload(&models.ErrorResponse{
+					Code: "403",
+					Message: fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to Merge Companies - only Admins allowed to merge companies.",
+						authUser.UserName),
+				})
+			}
+
+			report, err := service.MergeCompanies(authUser, sourceCompanyID, params.CompanyID, params.Body.DryRun)
+			if err != nil {
+				switch err {
+				case ErrSameCompany:
+					return company_merge.NewMergeCompaniesBadRequest().WithPayload(&models.ErrorResponse{
+						Code:    "400",
+						Message: fmt.Sprintf("EasyCLA - 400 Bad Request - company %s can't be merged into itself", params.CompanyID),
+					})
+				case company.ErrCompanyDoesNotExist:
+					return company_merge.NewMergeCompaniesNotFound().WithPayload(&models.ErrorResponse{
+						Code:    "404",
+						Message: fmt.Sprintf("EasyCLA - 404 Not Found - company %s or company %s not found", sourceCompanyID, params.CompanyID),
+					})
+				case company.ErrCompanyAlreadyMerged:
+					return company_merge.NewMergeCompaniesConflict().WithPayload(&models.ErrorResponse{
+						Code:    "409",
+						Message: fmt.Sprintf("EasyCLA - 409 Conflict - company %s or company %s was already merged into another company", sourceCompanyID, params.CompanyID),
+					})
+				case company.ErrCompanyMergeConflict:
+					return company_merge.NewMergeCompaniesConflict().WithPayload(&models.ErrorResponse{
+						Code:    "409",
+						Message: fmt.Sprintf("EasyCLA - 409 Conflict - company %s is being merged into another company", sourceCompanyID),
+					})
+				}
+				log.WithFields(f).Warnf("unable to merge the companies, error: %+v", err)
+				return company_merge.NewMergeCompaniesInternalServerError().WithPayload(errorResponse(err))
+			}
+			return company_merge.NewMergeCompaniesOK().WithPayload(report)
+		})
+}
+
+type codedResponse interface {
+	Code() string
+}
+
+func errorResponse(err error) *models.ErrorResponse {
+	code := ""
+	if e, ok := err.(codedResponse); ok {
+		code = e.Code()
+	}
+
+	e := models.ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	}
+
+	return &e
+}
@@ -0,0 +1,556 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package company_merge
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list_revisions"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	v1SignatureParams "github.com/communitybridge/easycla/cla-backend-go/gen/restapi/operations/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// change actions
+const (
+	ActionTransferCorporateSignature = "transferCorporateSignature"
+	ActionMergeApprovalList          = "mergeApprovalList"
+	ActionMergeAutoApprovalRules     = "mergeAutoApprovalRules"
+	ActionAddCLAManager              = "addCLAManager"
+	ActionRevokeCorporateSignature   = "revokeCorporateSignature"
+	ActionTransferEmployeeSignature  = "transferEmployeeSignature"
+	ActionRevokeEmployeeSignature    = "revokeEmployeeSignature"
+	ActionMergeCompanyACL            = "mergeCompanyACL"
+	ActionRecordLineage              = "recordLineage"
+)
+
+// HugePageSize is the page size used to load all the signatures of a company
+const HugePageSize = int64(10000)
+
+// errors
+var (
+	ErrSameCompany = errors.New("a company can't be merged into itself")
+)
+
+// Service defines the functions of the company merge service. When one company acquires another, the acquired
+// (source) company is merged into the acquiring (target) company: the CCLAs, approval lists, CLA Managers, employee
+// acknowledgements and company ACL of the source company are moved to the target company. The progress of the merge is
+// recorded on the source company so that an interrupted merge is resumed by merging the companies again.
+type Service interface {
+	MergeCompanies(authUser *auth.User, sourceCompanyID, targetCompanyID string, dryRun bool) (*models.CompanyMergeReport, error)
+}
+
+type service struct {
+	companyRepo      company.IRepository
+	signaturesRepo   signatures.SignatureRepository
+	revisionsService approval_list_revisions.Service
+	eventsService    events.Service
+}
+
+// NewService creates a new instance of the company merge service
+func NewService(companyRepo company.IRepository, signaturesRepo signatures.SignatureRepository, revisionsService approval_list_revisions.Service,
+	eventsService events.Service) Service {
+	return service{
+		companyRepo:      companyRepo,
+		signaturesRepo:   signaturesRepo,
+		revisionsService: revisionsService,
+		eventsService:    eventsService,
+	}
+}
+
+// change is a single step of a merge plan along with the function applying it, the key identifies the change in the
+// merge progress
+type change struct {
+	key   string
+	model *models.CompanyMergeChange
+	apply func() error
+}
+
+// MergeCompanies merges the source company into the target company. The report lists every change in the order they
+// are applied - with dryRun the changes are only reported. The changes applied by an interrupted merge into the same
+// company are skipped. Returns company.ErrCompanyDoesNotExist if one of the companies does not exist, ErrSameCompany
+// if both are the same, company.ErrCompanyAlreadyMerged if one of the companies was already merged into another company
+// and company.ErrCompanyMergeConflict if the source company is being merged into another company.
+func (s service) MergeCompanies(authUser *auth.User, sourceCompanyID, targetCompanyID string, dryRun bool) (*models.CompanyMergeReport, error) {
+	f := logrus.Fields{
+		"functionName":    "MergeCompanies",
+		"sourceCompanyID": sourceCompanyID,
+		"targetCompanyID": targetCompanyID,
+		"dryRun":          dryRun,
+		"authUser":        authUser.UserName,
+	}
+
+	if sourceCompanyID == targetCompanyID {
+		return nil, ErrSameCompany
+	}
+	sourceCompany, err := s.companyRepo.GetCompany(sourceCompanyID)
+	if err != nil {
+		return nil, err
+	}
+	targetCompany, err := s.companyRepo.GetCompany(targetCompanyID)
+	if err != nil {
+		return nil, err
+	}
+	if sourceCompany.MergedIntoCompanyID != "" || targetCompany.MergedIntoCompanyID != "" {
+		return nil, company.ErrCompanyAlreadyMerged
+	}
+
+	changes, eventData, err := s.plan(authUser, sourceCompany, targetCompany)
+	if err != nil {
+		log.WithFields(f).Warnf("unable to plan the company merge, error: %+v", err)
+		return nil, err
+	}
+
+	// The changes applied by an interrupted merge were recorded on the source company
+	if !dryRun {
+		appliedChanges, startErr := s.companyRepo.StartCompanyMerge(sourceCompany.CompanyID, targetCompany.CompanyID)
+		if startErr != nil {
+			return nil, startErr
+		}
+		changes = pendingChanges(changes, appliedChanges)
+	}
+
+	report := &models.CompanyMergeReport{
+		SourceCompanyID:   sourceCompany.CompanyID,
+		SourceCompanyName: sourceCompany.CompanyName,
+		TargetCompanyID:   targetCompany.CompanyID,
+		TargetCompanyName: targetCompany.CompanyName,
+		DryRun:            dryRun,
+		Changes:           []*models.CompanyMergeChange{},
+	}
+	for _, c := range changes {
+		report.Changes = append(report.Changes, c.model)
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	for _, c := range changes {
+		if err := c.apply(); err != nil {
+			log.WithFields(f).Warnf("unable to apply the company merge change: %s - %s, error: %+v", c.model.Action, c.model.Description, err)
+			return nil, err
+		}
+		if c.key == "" {
+			continue
+		}
+		if err := s.companyRepo.RecordCompanyMergeChange(sourceCompany.CompanyID, c.key); err != nil {
+			return nil, err
+		}
+	}
+	log.WithFields(f).Debugf("applied %d company merge changes", len(changes))
+
+	s.eventsService.LogEvent(&events.LogEventArgs{
+		EventType:    events.CompanyMerged,
+		CompanyID:    targetCompany.CompanyID,
+		CompanyModel: targetCompany,
+		LfUsername:   authUser.UserName,
+		EventData:    eventData,
+	})
+
+	return report, nil
+}
+
+// plan returns the changes merging the source company into the target company along with the summary of the merge
+func (s service) plan(authUser *auth.User, sourceCompany, targetCompany *v1Models.Company) ([]change, *events.CompanyMergedEventData, error) {
+	sourceCCLAs, err := s.getCorporateSignatures(sourceCompany.CompanyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	targetCCLAs, err := s.getCorporateSignatures(targetCompany.CompanyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	targetCCLAsByCLAGroup := map[string]*v1Models.Signature{}
+	for _, sig := range targetCCLAs {
+		targetCCLAsByCLAGroup[sig.ProjectID] = sig
+	}
+
+	eventData := &events.CompanyMergedEventData{
+		SourceCompanyID:   sourceCompany.CompanyID,
+		SourceCompanyName: sourceCompany.CompanyName,
+	}
+	var changes []change
+	for _, sourceCCLA := range sourceCCLAs {
+		claGroupID := sourceCCLA.ProjectID
+		sourceSignatureID := sourceCCLA.SignatureID.String()
+		targetCCLA, found := targetCCLAsByCLAGroup[claGroupID]
+		if !found {
+			changes = append(changes, s.transferChange(ActionTransferCorporateSignature, claGroupID, sourceSignatureID, targetCompany,
+				fmt.Sprintf("transfer the CCLA of %s to %s", sourceCompany.CompanyName, targetCompany.CompanyName)))
+			eventData.CorporateSignaturesTransferred++
+		} else {
+			consolidateChanges, consolidateErr := s.consolidateChanges(authUser, sourceCompany, targetCompany, sourceCCLA, targetCCLA)
+			if consolidateErr != nil {
+				return nil, nil, consolidateErr
+			}
+			changes = append(changes, consolidateChanges...)
+			eventData.CorporateSignaturesConsolidated++
+		}
+
+		// The employee acknowledgements follow the CCLA, duplicates of the target company acknowledgements are revoked
+		sourceEmployeeSigs, err := s.getEmployeeSignatures(sourceCompany.CompanyID, claGroupID)
+		if err != nil {
+			return nil, nil, err
+		}
+		targetEmployees := map[string]bool{}
+		if found {
+			targetEmployeeSigs, err := s.getEmployeeSignatures(targetCompany.CompanyID, claGroupID)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, sig := range targetEmployeeSigs {
+				targetEmployees[sig.SignatureReferenceID.String()] = true
+			}
+		}
+		for _, sig := range sourceEmployeeSigs {
+			if targetEmployees[sig.SignatureReferenceID.String()] {
+				changes = append(changes, s.revokeChange(authUser, ActionRevokeEmployeeSignature, claGroupID, sig.SignatureID.String(), targetCompany,
+					fmt.Sprintf("revoke the acknowledgement of %s with %s - the user already acknowledged the CCLA of %s",
+						employeeName(sig), sourceCompany.CompanyName, targetCompany.CompanyName)))
+				continue
+			}
+			changes = append(changes, s.transferChange(ActionTransferEmployeeSignature, claGroupID, sig.SignatureID.String(), targetCompany,
+				fmt.Sprintf("transfer the acknowledgement of %s to %s", employeeName(sig), targetCompany.CompanyName)))
+			eventData.EmployeeSignaturesTransferred++
+		}
+	}
+
+	missingACL := missingEntries(sourceCompany.CompanyACL, targetCompany.CompanyACL)
+	if len(missingACL) > 0 {
+		companyACL := append(append([]string{}, targetCompany.CompanyACL...), missingACL...)
+		changes = append(changes, change{
+			key: ActionMergeCompanyACL,
+			model: &models.CompanyMergeChange{
+				Action:      ActionMergeCompanyACL,
+				Description: fmt.Sprintf("add %s to the ACL of %s", strings.Join(missingACL, ", "), targetCompany.CompanyName),
+			},
+			apply: func() error {
+				return s.companyRepo.UpdateCompanyAccessList(targetCompany.CompanyID, companyACL)
+			},
+		})
+	}
+
+	changes = append(changes, change{
+		model: &models.CompanyMergeChange{
+			Action:      ActionRecordLineage,
+			Description: fmt.Sprintf("record %s as merged into %s", sourceCompany.CompanyName, targetCompany.CompanyName),
+		},
+		apply: func() error {
+			_, now := utils.CurrentTime()
+			return s.companyRepo.RecordCompanyMerge(sourceCompany.CompanyID, targetCompany.CompanyID, now)
+		},
+	})
+
+	eventData.ChangeCount = len(changes)
+	return changes, eventData, nil
+}
+
+// consolidateChanges returns the changes adding the approval lists, auto-approval rules and CLA Managers of the source
+// company CCLA to the target company CCLA of the same CLA Group and revoking the source company CCLA. The approval list
+// and auto-approval rules are written to the signatures directly, like the SCIM sync, so that the merge doesn't notify
+// the CLA Managers and the contributors of every moved entry - the merge is recorded by the company merged event.
+func (s service) consolidateChanges(authUser *auth.User, sourceCompany, targetCompany *v1Models.Company, sourceCCLA, targetCCLA *v1Models.Signature) ([]change, error) {
+	claGroupID := targetCCLA.ProjectID
+	targetSignatureID := targetCCLA.SignatureID.String()
+	var changes []change
+
+	approvalList, entries := approvalListAdditions(sourceCCLA, targetCCLA, time.Now())
+	if len(entries) > 0 {
+		changes = append(changes, change{
+			key: changeKey(ActionMergeApprovalList, targetSignatureID),
+			model: &models.CompanyMergeChange{
+				Action:      ActionMergeApprovalList,
+				ClaGroupID:  claGroupID,
+				SignatureID: targetSignatureID,
+				Description: fmt.Sprintf("add %s to the approval lists of the CCLA of %s", strings.Join(entries, ", "), targetCompany.CompanyName),
+			},
+			apply: func() error {
+				previous, err := s.signaturesRepo.GetSignature(targetSignatureID)
+				if err != nil {
+					return err
+				}
+				updated, err := s.signaturesRepo.UpdateApprovalList(claGroupID, targetCompany.CompanyID, approvalList)
+				if err != nil {
+					return err
+				}
+				s.recordRevision(authUser, previous, updated)
+				return nil
+			},
+		})
+	}
+
+	rules, ruleDescriptions := mergeAutoApprovalRules(sourceCCLA.AutoApprovalRules, targetCCLA.AutoApprovalRules)
+	if len(ruleDescriptions) > 0 {
+		changes = append(changes, change{
+			key: changeKey(ActionMergeAutoApprovalRules, targetSignatureID),
+			model: &models.CompanyMergeChange{
+				Action:      ActionMergeAutoApprovalRules,
+				ClaGroupID:  claGroupID,
+				SignatureID: targetSignatureID,
+				Description: fmt.Sprintf("add the auto-approval rules %s to the CCLA of %s", strings.Join(ruleDescriptions, ", "), targetCompany.CompanyName),
+			},
+			apply: func() error {
+				current, err := s.signaturesRepo.GetSignature(targetSignatureID)
+				if err != nil {
+					return err
+				}
+				_, err = s.signaturesRepo.UpdateAutoApprovalRules(targetSignatureID, current.RecordVersion, rules)
+				return err
+			},
+		})
+	}
+
+	sourceACL, err := s.signaturesRepo.GetSignatureACL(sourceCCLA.SignatureID.String())
+	if err != nil {
+		return nil, err
+	}
+	targetACL, err := s.signaturesRepo.GetSignatureACL(targetSignatureID)
+	if err != nil {
+		return nil, err
+	}
+	for _, claManagerID := range missingEntries(sourceACL, targetACL) {
+		claManagerID := claManagerID
+		changes = append(changes, change{
+			key: changeKey(ActionAddCLAManager, targetSignatureID, claManagerID),
+			model: &models.CompanyMergeChange{
+				Action:      ActionAddCLAManager,
+				ClaGroupID:  claGroupID,
+				SignatureID: targetSignatureID,
+				Description: fmt.Sprintf("add %s as CLA Manager of the CCLA of %s", claManagerID, targetCompany.CompanyName),
+			},
+			apply: func() error {
+				_, err := s.signaturesRepo.AddCLAManager(targetSignatureID, claManagerID)
+				return err
+			},
+		})
+	}
+
+	changes = append(changes, s.revokeChange(authUser, ActionRevokeCorporateSignature, claGroupID, sourceCCLA.SignatureID.String(), targetCompany,
+		fmt.Sprintf("revoke the CCLA of %s - consolidated into the CCLA of %s", sourceCompany.CompanyName, targetCompany.CompanyName)))
+	return changes, nil
+}
+
+// recordRevision records the approval list change of the target company CCLA in the approval list revision history,
+// on behalf of the user merging the companies
+func (s service) recordRevision(authUser *auth.User, previous, updated *v1Models.Signature) {
+	if previous == nil || updated == nil || updated.RecordVersion <= previous.RecordVersion {
+		return
+	}
+	actor := &v1Models.User{LfUsername: authUser.UserName, LfEmail: authUser.Email}
+	if _, err := s.revisionsService.CreateRevision(previous, updated, actor); err != nil {
+		log.Warnf("unable to record the approval list revision for signature ID: %s, error: %+v", updated.SignatureID, err)
+	}
+}
+
+// transferChange returns the change moving the signature to the target company
+func (s service) transferChange(action, claGroupID, signatureID string, targetCompany *v1Models.Company, description string) change {
+	return change{
+		key: changeKey(action, signatureID),
+		model: &models.CompanyMergeChange{
+			Action:      action,
+			ClaGroupID:  claGroupID,
+			SignatureID: signatureID,
+			Description: description,
+		},
+		apply: func() error {
+			_, err := s.signaturesRepo.TransferSignatureCompany(signatureID, targetCompany)
+			return err
+		},
+	}
+}
+
+// revokeChange returns the change revoking the signature consolidated into a signature of the target company
+func (s service) revokeChange(authUser *auth.User, action, claGroupID, signatureID string, targetCompany *v1Models.Company, description string) change {
+	return change{
+		key: changeKey(action, signatureID),
+		model: &models.CompanyMergeChange{
+			Action:      action,
+			ClaGroupID:  claGroupID,
+			SignatureID: signatureID,
+			Description: description,
+		},
+		apply: func() error {
+			_, now := utils.CurrentTime()
+			_, err := s.signaturesRepo.RevokeSignature(signatureID, &v1Models.SignatureRevocation{
+				Reason:    aws.String(signatures.RevocationReasonCompanyMerged),
+				Comment:   fmt.Sprintf("merged into company: %s (%s)", targetCompany.CompanyName, targetCompany.CompanyID),
+				RevokedOn: now,
+				RevokedBy: authUser.UserName,
+			})
+			if _, alreadyRevoked := err.(*signatures.ConflictError); alreadyRevoked {
+				return nil
+			}
+			return err
+		},
+	}
+}
+
+// changeKey returns the key identifying the change in the merge progress
+func changeKey(action string, ids ...string) string {
+	return strings.Join(append([]string{action}, ids...), "#")
+}
+
+// pendingChanges returns the changes which were not applied yet
+func pendingChanges(changes []change, appliedChanges []string) []change {
+	var pending []change
+	for _, c := range changes {
+		if c.key != "" && utils.StringInSlice(c.key, appliedChanges) {
+			continue
+		}
+		pending = append(pending, c)
+	}
+	return pending
+}
+
+// approvalListAdditions returns the approval list update adding the entries of the source CCLA missing from the target
+// CCLA along with the added entries. The entries of the source CCLA which expired are not added, the expiration dates
+// of the other entries are kept.
+func approvalListAdditions(sourceCCLA, targetCCLA *v1Models.Signature, now time.Time) (*v1Models.ApprovalList, []string) {
+	expirations := map[string]*v1Models.ApprovalListExpiration{}
+	for _, expiration := range sourceCCLA.ApprovalListExpirations {
+		expirations[expiration.ListType+"#"+expiration.Value] = expiration
+	}
+
+	approvalList := &v1Models.ApprovalList{}
+	var entries []string
+	for _, list := range []struct {
+		listType string
+		source   []string
+		target   []string
+		add      *[]string
+	}{
+		{signatures.ApprovalListTypeEmail, sourceCCLA.EmailApprovalList, targetCCLA.EmailApprovalList, &approvalList.AddEmailApprovalList},
+		{signatures.ApprovalListTypeDomain, sourceCCLA.DomainApprovalList, targetCCLA.DomainApprovalList, &approvalList.AddDomainApprovalList},
+		{signatures.ApprovalListTypeGithubUsername, sourceCCLA.GithubUsernameApprovalList, targetCCLA.GithubUsernameApprovalList, &approvalList.AddGithubUsernameApprovalList},
+		{signatures.ApprovalListTypeGithubOrg, sourceCCLA.GithubOrgApprovalList, targetCCLA.GithubOrgApprovalList, &approvalList.AddGithubOrgApprovalList},
+		{signatures.ApprovalListTypeGithubTeam, sourceCCLA.GithubTeamApprovalList, targetCCLA.GithubTeamApprovalList, &approvalList.AddGithubTeamApprovalList},
+	} {
+		for _, entry := range missingEntries(list.source, list.target) {
+			expiration, found := expirations[list.listType+"#"+entry]
+			if found {
+				expirationDate, err := utils.ParseDateTime(expiration.ExpirationDate)
+				if err == nil && !expirationDate.After(now) {
+					continue
+				}
+				approvalList.ApprovalListExpirations = append(approvalList.ApprovalListExpirations, &v1Models.ApprovalListExpiration{
+					ListType:       list.listType,
+					Value:          entry,
+					ExpirationDate: expiration.ExpirationDate,
+				})
+			}
+			*list.add = append(*list.add, entry)
+			entries = append(entries, entry)
+		}
+	}
+	return approvalList, entries
+}
+
+// mergeAutoApprovalRules returns the auto-approval rules of the target CCLA with the values of the source CCLA rules
+// added, along with the descriptions of the added values - no descriptions if the target rules already hold every value
+func mergeAutoApprovalRules(sourceRules, targetRules []*v1Models.AutoApprovalRule) ([]*v1Models.AutoApprovalRule, []string) {
+	var rules []*v1Models.AutoApprovalRule
+	rulesByType := map[string]*v1Models.AutoApprovalRule{}
+	for _, rule := range targetRules {
+		merged := &v1Models.AutoApprovalRule{RuleType: rule.RuleType, Values: append([]string{}, rule.Values...)}
+		rules = append(rules, merged)
+		rulesByType[utils.StringValue(rule.RuleType)] = merged
+	}
+
+	var descriptions []string
+	for _, rule := range sourceRules {
+		ruleType := utils.StringValue(rule.RuleType)
+		merged, found := rulesByType[ruleType]
+		if !found {
+			merged = &v1Models.AutoApprovalRule{RuleType: aws.String(ruleType)}
+			rules = append(rules, merged)
+			rulesByType[ruleType] = merged
+		}
+		added := missingEntries(rule.Values, merged.Values)
+		if len(added) == 0 {
+			continue
+		}
+		merged.Values = append(merged.Values, added...)
+		descriptions = append(descriptions, fmt.Sprintf("%s: %s", ruleType, strings.Join(added, ", ")))
+	}
+	return rules, descriptions
+}
+
+// getCorporateSignatures returns the signed and approved CCLAs of the company
+func (s service) getCorporateSignatures(companyID string) ([]*v1Models.Signature, error) {
+	var sigs []*v1Models.Signature
+	var lastScannedKey *string
+	for {
+		sigModels, err := s.signaturesRepo.GetCompanySignatures(v1SignatureParams.GetCompanySignaturesParams{
+			CompanyID:     companyID,
+			SignatureType: aws.String(signatures.CCLA),
+			NextKey:       lastScannedKey,
+		}, HugePageSize, signatures.DontLoadACLDetails)
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, sigModels.Signatures...)
+		if sigModels.LastKeyScanned == "" {
+			break
+		}
+		lastScannedKey = aws.String(sigModels.LastKeyScanned)
+	}
+	return sigs, nil
+}
+
+// getEmployeeSignatures returns the signed and approved employee acknowledgements of the company for the CLA Group
+func (s service) getEmployeeSignatures(companyID, claGroupID string) ([]*v1Models.Signature, error) {
+	var sigs []*v1Models.Signature
+	var lastScannedKey *string
+	for {
+		sigModels, err := s.signaturesRepo.GetProjectCompanyEmployeeSignatures(v1SignatureParams.GetProjectCompanyEmployeeSignaturesParams{
+			CompanyID: companyID,
+			ProjectID: claGroupID,
+			NextKey:   lastScannedKey,
+		}, HugePageSize)
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, sigModels.Signatures...)
+		if sigModels.LastKeyScanned == "" {
+			break
+		}
+		lastScannedKey = aws.String(sigModels.LastKeyScanned)
+	}
+	return sigs, nil
+}
+
+// missingEntries returns the entries which are not in the existing list
+func missingEntries(entries, existing []string) []string {
+	var missing []string
+	for _, entry := range entries {
+		if !utils.StringInSlice(entry, existing) && !utils.StringInSlice(entry, missing) {
+			missing = append(missing, entry)
+		}
+	}
+	return missing
+}
+
+// employeeName returns the name of the user who acknowledged the CCLA
+func employeeName(sig *v1Models.Signature) string {
+	switch {
+	case sig.UserName != "":
+		return sig.UserName
+	case sig.UserLFID != "":
+		return sig.UserLFID
+	case sig.UserGHUsername != "":
+		return sig.UserGHUsername
+	}
+	return sig.SignatureReferenceID.String()
+}
@@ -0,0 +1,96 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/coverage"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+// Configure setups handlers on api with service
+func Configure(api *operations.EasyclaAPI, service Service, projectRepo project.ProjectRepository) {
+	api.CoverageExplainCoverageHandler = coverage.ExplainCoverageHandlerFunc(
+		func(params coverage.ExplainCoverageParams, authUser *auth.User) middleware.Responder {
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			f := logrus.Fields{
+				"functionName": "ExplainCoverageHandler",
+				"claGroupID":   params.ClaGroupID,
+				"authUser":     authUser.UserName,
+			}
+
+			contributor := Contributor{
+				Email:          stringValue(params.Email),
+				GitHubUsername: stringValue(params.GithubUsername),
+				LFUsername:     stringValue(params.LfUsername),
+			}
+			if contributor.Email == "" && contributor.GitHubUsername == "" && contributor.LFUsername == "" {
+				return coverage.NewExplainCoverageBadRequest().WithPayload(&models.ErrorResponse{
+					Code:    "400",
+					Message: "EasyCLA - 400 Bad Request - one of the email, githubUsername or lfUsername parameters is required",
+				})
+			}
+
+			claGroupModel, err := projectRepo.GetCLAGroupByID(params.ClaGroupID, project.DontLoadRepoDetails)
+			if err != nil {
+				if err == project.ErrProjectDoesNotExist {
+					return coverage.NewExplainCoverageNotFound().WithPayload(&models.ErrorResponse{
+						Code:    "404",
+						Message: fmt.Sprintf("EasyCLA - 404 Not Found - cla_group %s not found", params.ClaGroupID),
+					})
+				}
+				return coverage.NewExplainCoverageInternalServerError().WithPayload(errorResponse(err))
+			}
+
+			if !utils.IsUserAuthorizedForProject(authUser, claGroupModel.FoundationSFID) {
+				return coverage.NewExplainCoverageForbidden().WithPayload(&models.ErrorResponse{
+					Code: "403",
+					Message: fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to ExplainCoverage with project scope of %s",
+						authUser.UserName, claGroupModel.FoundationSFID),
+				})
+			}
+
+			result, err := service.ExplainCoverage(claGroupModel, contributor)
+			if err != nil {
+				log.WithFields(f).Warnf("unable to explain the coverage of the contributor, error: %+v", err)
+				return coverage.NewExplainCoverageInternalServerError().WithPayload(errorResponse(err))
+			}
+			return coverage.NewExplainCoverageOK().WithPayload(result)
+		})
+}
+
+// stringValue returns the trimmed value of the optional parameter
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSpace(*value)
+}
+
+type codedResponse interface {
+	Code() string
+}
+
+func errorResponse(err error) *models.ErrorResponse {
+	code := ""
+	if e, ok := err.(codedResponse); ok {
+		code = e.Code()
+	}
+
+	e := models.ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	}
+
+	return &e
+}
@@ -0,0 +1,462 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list"
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	v1Signatures "github.com/communitybridge/easycla/cla-backend-go/gen/restapi/operations/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/github"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// coverage types
+const (
+	CoverageTypeICLA = "icla"
+	CoverageTypeCCLA = "ccla"
+	CoverageTypeNone = "none"
+)
+
+// near miss reasons
+const (
+	NearMissUserNotFound                   = "user_not_found"
+	NearMissCCLANotOnApprovalList          = "ccla_not_on_approval_list"
+	NearMissApprovalListEntryExpired       = "approval_list_entry_expired"
+	NearMissEmployeeAcknowledgementMissing = "employee_acknowledgement_missing"
+	NearMissApprovalListRequestPending     = "approval_list_request_pending"
+)
+
+// employeeSignaturesPageSize is the page size used when loading the employee acknowledgements of a company
+const employeeSignaturesPageSize = int64(10000)
+
+// Contributor identifies the contributor to explain - at least one of the values is required
+type Contributor struct {
+	Email          string
+	GitHubUsername string
+	LFUsername     string
+}
+
+// GitHubOrgLookup returns the GitHub organizations the GitHub user is a public member of
+type GitHubOrgLookup func(githubUsername string) ([]string, error)
+
+// Service defines the functions of the coverage explanation service
+type Service interface {
+	ExplainCoverage(claGroupModel *v1Models.Project, contributor Contributor) (*models.CoverageExplanation, error)
+}
+
+type service struct {
+	signatureService    signatures.SignatureService
+	approvalListService approval_list.IService
+	usersService        users.Service
+	githubOrgLookup     GitHubOrgLookup
+	teamMembership      signatures.GitHubTeamMembership
+}
+
+// NewService creates a new coverage explanation service - the GitHub API is used to look up the organizations of
+// the contributor when no lookup is specified - GitHub team entries are only matched when a team membership resolver
+// is specified
+func NewService(signatureService signatures.SignatureService, approvalListService approval_list.IService, usersService users.Service, githubOrgLookup GitHubOrgLookup, teamMembership signatures.GitHubTeamMembership) Service {
+	if githubOrgLookup == nil {
+		githubOrgLookup = github.GetUserOrganizations
+	}
+	return service{
+		signatureService:    signatureService,
+		approvalListService: approvalListService,
+		usersService:        usersService,
+		githubOrgLookup:     githubOrgLookup,
+		teamMembership:      teamMembership,
+	}
+}
+
+// contributorIdentity holds the values of the contributor matched against the approval lists
+type contributorIdentity struct {
+	userModel      *v1Models.User
+	emails         []string
+	githubUsername string
+
+	// the GitHub organizations are only looked up once a CCLA has a GitHub organization approval list
+	githubOrgs       []string
+	githubOrgsLoaded bool
+}
+
+// ExplainCoverage returns the signature which covers the contributor, the approval list rule which matched and the
+// near misses encountered along the way
+func (s service) ExplainCoverage(claGroupModel *v1Models.Project, contributor Contributor) (*models.CoverageExplanation, error) {
+	f := logrus.Fields{
+		"functionName":   "ExplainCoverage",
+		"claGroupID":     claGroupModel.ProjectID,
+		"email":          contributor.Email,
+		"githubUsername": contributor.GitHubUsername,
+		"lfUsername":     contributor.LFUsername,
+	}
+
+	identity := s.resolveContributor(contributor)
+	result := &models.CoverageExplanation{
+		ClaGroupID:     claGroupModel.ProjectID,
+		ClaGroupName:   claGroupModel.ProjectName,
+		CoverageType:   CoverageTypeNone,
+		LfUsername:     contributor.LFUsername,
+		GithubUsername: identity.githubUsername,
+		Emails:         identity.emails,
+	}
+	if identity.userModel != nil {
+		result.UserID = identity.userModel.UserID
+		if result.LfUsername == "" {
+			result.LfUsername = identity.userModel.LfUsername
+		}
+	} else {
+		result.NearMisses = append(result.NearMisses, &models.CoverageNearMiss{
+			Reason:  NearMissUserNotFound,
+			Message: "no EasyCLA user record matches the contributor - the contributor has not signed or acknowledged any agreement",
+		})
+	}
+
+	// An ICLA covers the contributor regardless of any company affiliation
+	if identity.userModel != nil {
+		iclaSignature, err := s.signatureService.GetIndividualSignature(claGroupModel.ProjectID, identity.userModel.UserID)
+		if err != nil {
+			log.WithFields(f).Warnf("unable to lookup the ICLA signature for user ID: %s, error: %+v", identity.userModel.UserID, err)
+			return nil, err
+		}
+		if iclaSignature != nil {
+			result.Covered = true
+			result.CoverageType = CoverageTypeICLA
+			result.SignatureID = iclaSignature.SignatureID.String()
+			return result, nil
+		}
+	}
+
+	companies, err := s.signatureService.GetCompanyIDsWithSignedCorporateSignatures(claGroupModel.ProjectID)
+	if err != nil {
+		log.WithFields(f).Warnf("unable to lookup the companies with signed CCLAs, error: %+v", err)
+		return nil, err
+	}
+
+	checked := map[string]bool{}
+	for _, company := range companies {
+		if checked[company.CompanyID] {
+			continue
+		}
+		checked[company.CompanyID] = true
+
+		cclaSignature, sigErr := s.signatureService.GetCorporateSignature(claGroupModel.ProjectID, company.CompanyID)
+		if sigErr != nil {
+			log.WithFields(f).Warnf("unable to lookup the CCLA signature for company ID: %s, error: %+v", company.CompanyID, sigErr)
+			return nil, sigErr
+		}
+		if cclaSignature == nil {
+			continue
+		}
+
+		covered, nearMisses, explainErr := s.explainCorporateCoverage(claGroupModel, company, cclaSignature, identity, result)
+		if explainErr != nil {
+			return nil, explainErr
+		}
+		result.NearMisses = append(result.NearMisses, nearMisses...)
+		if covered {
+			return result, nil
+		}
+	}
+
+	return result, nil
+}
+
+// explainCorporateCoverage checks the contributor against the CCLA signature of the company - returns true and
+// updates the result when the CCLA covers the contributor, returns the near misses otherwise
+func (s service) explainCorporateCoverage(claGroupModel *v1Models.Project, company signatures.SignatureCompanyID, cclaSignature *v1Models.Signature, identity *contributorIdentity, result *models.CoverageExplanation) (bool, []*models.CoverageNearMiss, error) {
+	var nearMisses []*models.CoverageNearMiss
+	nearMiss := func(reason, message string, rule *models.CoverageRule) {
+		nearMisses = append(nearMisses, &models.CoverageNearMiss{
+			Reason:      reason,
+			Message:     message,
+			SignatureID: cclaSignature.SignatureID.String(),
+			CompanyID:   company.CompanyID,
+			CompanyName: company.CompanyName,
+			Rule:        rule,
+		})
+	}
+
+	rule, expiredRules := s.matchApprovalList(cclaSignature, identity, time.Now())
+	if rule == nil {
+		for _, expiredRule := range expiredRules {
+			nearMiss(NearMissApprovalListEntryExpired,
+				fmt.Sprintf("the %s approval list entry %s of %s matched the contributor but expired on %s",
+					expiredRule.ListType, expiredRule.Value, company.CompanyName, expiredRule.ExpirationDate), expiredRule)
+		}
+
+		// Only the company the contributor is affiliated with is a near miss - any other CCLA is simply unrelated
+		if identity.userModel == nil || identity.userModel.CompanyID != company.CompanyID {
+			return false, nearMisses, nil
+		}
+		if len(expiredRules) == 0 {
+			nearMiss(NearMissCCLANotOnApprovalList,
+				fmt.Sprintf("%s signed a CCLA but none of its approval lists include the contributor", company.CompanyName), nil)
+		}
+
+		pendingRequests, err := s.pendingApprovalListRequests(claGroupModel.ProjectID, company.CompanyID, identity.userModel.UserID)
+		if err != nil {
+			return false, nil, err
+		}
+		for _, request := range pendingRequests {
+			nearMisses = append(nearMisses, &models.CoverageNearMiss{
+				Reason:      NearMissApprovalListRequestPending,
+				Message:     fmt.Sprintf("the contributor requested to be added to the approval list of %s on %s - the request is waiting for a CLA manager", company.CompanyName, request.DateCreated),
+				SignatureID: cclaSignature.SignatureID.String(),
+				CompanyID:   company.CompanyID,
+				CompanyName: company.CompanyName,
+				RequestID:   request.RequestID,
+			})
+		}
+		return false, nearMisses, nil
+	}
+
+	// The approval list allows the contributor - the contributor still has to acknowledge the CCLA as an employee
+	var employeeSignature *v1Models.Signature
+	if identity.userModel != nil {
+		var err error
+		employeeSignature, err = s.employeeSignature(claGroupModel.ProjectID, company.CompanyID, identity.userModel.UserID)
+		if err != nil {
+			return false, nil, err
+		}
+	}
+	if employeeSignature == nil {
+		nearMiss(NearMissEmployeeAcknowledgementMissing,
+			fmt.Sprintf("the contributor is on the %s approval list of %s but has not acknowledged the CCLA as an employee of %s",
+				rule.ListType, company.CompanyName, company.CompanyName), rule)
+		return false, nearMisses, nil
+	}
+
+	result.Covered = true
+	result.CoverageType = CoverageTypeCCLA
+	result.SignatureID = cclaSignature.SignatureID.String()
+	result.CompanyID = company.CompanyID
+	result.CompanyName = company.CompanyName
+	result.EmployeeSignatureID = employeeSignature.SignatureID.String()
+	result.MatchedRule = rule
+	return true, nearMisses, nil
+}
+
+// matchApprovalList returns the approval list rule of the CCLA signature which allows the contributor, or nil and the
+// rules which would have allowed the contributor had they not expired - the lists are checked in the order email,
+// domain, GitHub username and GitHub organization
+func (s service) matchApprovalList(cclaSignature *v1Models.Signature, identity *contributorIdentity, now time.Time) (*models.CoverageRule, []*models.CoverageRule) {
+	var expiredRules []*models.CoverageRule
+	newRule := func(listType, value, matchedValue string) *models.CoverageRule {
+		return &models.CoverageRule{
+			ListType:       listType,
+			Value:          value,
+			MatchedValue:   matchedValue,
+			ExpirationDate: expirationDate(cclaSignature, listType, value),
+		}
+	}
+	// active returns the entries which haven't expired - the expired entries are remembered when they match
+	active := func(listType string, entries []string, matches func(entry string) (string, bool)) []string {
+		var activeEntries []string
+		for _, entry := range entries {
+			if !signatures.IsApprovalListEntryExpired(cclaSignature, listType, entry, now) {
+				activeEntries = append(activeEntries, entry)
+				continue
+			}
+			if matchedValue, ok := matches(entry); ok {
+				expiredRules = append(expiredRules, newRule(listType, entry, matchedValue))
+			}
+		}
+		return activeEntries
+	}
+
+	emailMatches := func(entry string) (string, bool) {
+		for _, email := range identity.emails {
+			if strings.EqualFold(strings.TrimSpace(entry), email) {
+				return email, true
+			}
+		}
+		return "", false
+	}
+	for _, entry := range active(signatures.ApprovalListTypeEmail, cclaSignature.EmailApprovalList, emailMatches) {
+		if matchedValue, ok := emailMatches(entry); ok {
+			return newRule(signatures.ApprovalListTypeEmail, entry, matchedValue), nil
+		}
+	}
+
+	domainMatches := func(entry string) (string, bool) {
+		for _, email := range identity.emails {
+			if _, ok := utils.MatchEmailDomain(email, []string{entry}); ok {
+				return email, true
+			}
+		}
+		return "", false
+	}
+	activeDomains := active(signatures.ApprovalListTypeDomain, cclaSignature.DomainApprovalList, domainMatches)
+	for _, email := range identity.emails {
+		if entry, ok := utils.MatchEmailDomain(email, activeDomains); ok {
+			return newRule(signatures.ApprovalListTypeDomain, entry, email), nil
+		}
+	}
+
+	if identity.githubUsername != "" {
+		githubUsernameMatches := func(entry string) (string, bool) {
+			return identity.githubUsername, strings.EqualFold(strings.TrimSpace(entry), identity.githubUsername)
+		}
+		for _, entry := range active(signatures.ApprovalListTypeGithubUsername, cclaSignature.GithubUsernameApprovalList, githubUsernameMatches) {
+			if matchedValue, ok := githubUsernameMatches(entry); ok {
+				return newRule(signatures.ApprovalListTypeGithubUsername, entry, matchedValue), nil
+			}
+		}
+
+		if len(cclaSignature.GithubOrgApprovalList) > 0 {
+			githubOrgMatches := func(entry string) (string, bool) {
+				for _, org := range s.githubOrgs(identity) {
+					if strings.EqualFold(strings.TrimSpace(entry), org) {
+						return org, true
+					}
+				}
+				return "", false
+			}
+			for _, entry := range active(signatures.ApprovalListTypeGithubOrg, cclaSignature.GithubOrgApprovalList, githubOrgMatches) {
+				if matchedValue, ok := githubOrgMatches(entry); ok {
+					return newRule(signatures.ApprovalListTypeGithubOrg, entry, matchedValue), nil
+				}
+			}
+		}
+
+		if len(cclaSignature.GithubTeamApprovalList) > 0 && s.teamMembership != nil {
+			githubTeamMatches := func(entry string) (string, bool) {
+				return identity.githubUsername, len(signatures.MatchGitHubTeams(s.teamMembership, []string{entry}, identity.githubUsername)) > 0
+			}
+			for _, entry := range active(signatures.ApprovalListTypeGithubTeam, cclaSignature.GithubTeamApprovalList, githubTeamMatches) {
+				if matchedValue, ok := githubTeamMatches(entry); ok {
+					return newRule(signatures.ApprovalListTypeGithubTeam, entry, matchedValue), nil
+				}
+			}
+		}
+	}
+
+	return nil, expiredRules
+}
+
+// githubOrgs returns the GitHub organizations of the contributor, looked up on first use
+func (s service) githubOrgs(identity *contributorIdentity) []string {
+	if !identity.githubOrgsLoaded {
+		identity.githubOrgsLoaded = true
+		orgs, err := s.githubOrgLookup(identity.githubUsername)
+		if err != nil {
+			// Explain the rest of the decision - the GitHub organization rules simply won't match
+			log.Warnf("unable to lookup the GitHub organizations of %s, error: %+v", identity.githubUsername, err)
+			return nil
+		}
+		identity.githubOrgs = orgs
+	}
+	return identity.githubOrgs
+}
+
+// resolveContributor looks up the EasyCLA user record of the contributor and collects the email addresses and GitHub
+// username to match against the approval lists
+func (s service) resolveContributor(contributor Contributor) *contributorIdentity {
+	identity := &contributorIdentity{
+		githubUsername: strings.TrimSpace(contributor.GitHubUsername),
+	}
+
+	// The user lookups report a missing user as an error - either way there is no user to explain
+	if lfUsername := strings.TrimSpace(contributor.LFUsername); lfUsername != "" {
+		userModel, err := s.usersService.GetUserByLFUserName(lfUsername)
+		if err != nil {
+			log.Debugf("unable to lookup user by LF username: %s, error: %+v", lfUsername, err)
+		}
+		identity.userModel = userModel
+	}
+	if email := strings.TrimSpace(contributor.Email); identity.userModel == nil && email != "" {
+		userModel, err := s.usersService.GetUserByEmail(email)
+		if err != nil {
+			log.Debugf("unable to lookup user by email: %s, error: %+v", email, err)
+		}
+		identity.userModel = userModel
+	}
+	if identity.userModel == nil && identity.githubUsername != "" {
+		userModel, err := s.usersService.GetUserByGitHubUsername(identity.githubUsername)
+		if err != nil {
+			log.Debugf("unable to lookup user by GitHub username: %s, error: %+v", identity.githubUsername, err)
+		}
+		identity.userModel = userModel
+	}
+
+	addEmail := func(email string) {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email != "" && !utils.StringInSlice(email, identity.emails) {
+			identity.emails = append(identity.emails, email)
+		}
+	}
+	addEmail(contributor.Email)
+	if identity.userModel != nil {
+		addEmail(identity.userModel.LfEmail)
+		for _, email := range identity.userModel.Emails {
+			addEmail(email)
+		}
+		if identity.githubUsername == "" {
+			identity.githubUsername = identity.userModel.GithubUsername
+		}
+	}
+
+	return identity
+}
+
+// employeeSignature returns the employee acknowledgement of the user for the company, or nil if the user has not
+// acknowledged the CCLA
+func (s service) employeeSignature(claGroupID, companyID, userID string) (*v1Models.Signature, error) {
+	pageSize := employeeSignaturesPageSize
+	params := v1Signatures.GetProjectCompanyEmployeeSignaturesParams{
+		CompanyID: companyID,
+		ProjectID: claGroupID,
+		PageSize:  &pageSize,
+	}
+	for {
+		employeeSignatures, err := s.signatureService.GetProjectCompanyEmployeeSignatures(params)
+		if err != nil {
+			log.Warnf("unable to lookup the employee signatures for company ID: %s, CLA Group ID: %s, error: %+v", companyID, claGroupID, err)
+			return nil, err
+		}
+		for _, sig := range employeeSignatures.Signatures {
+			if sig.SignatureReferenceID.String() == userID {
+				return sig, nil
+			}
+		}
+		if employeeSignatures.LastKeyScanned == "" {
+			return nil, nil
+		}
+		nextKey := employeeSignatures.LastKeyScanned
+		params.NextKey = &nextKey
+	}
+}
+
+// pendingApprovalListRequests returns the pending requests of the user to be added to the approval list of the company
+func (s service) pendingApprovalListRequests(claGroupID, companyID, userID string) ([]v1Models.CclaWhitelistRequest, error) {
+	status := "pending"
+	requests, err := s.approvalListService.ListCclaWhitelistRequestByCompanyProjectUser(companyID, &claGroupID, &status, &userID)
+	if err != nil {
+		log.Warnf("unable to lookup the approval list requests for company ID: %s, CLA Group ID: %s, user ID: %s, error: %+v", companyID, claGroupID, userID, err)
+		return nil, err
+	}
+	if requests == nil {
+		return nil, nil
+	}
+	return requests.List, nil
+}
+
+// expirationDate returns the expiration date of the approval list entry, or an empty string if it doesn't expire
+func expirationDate(sig *v1Models.Signature, listType, value string) string {
+	for _, expiration := range sig.ApprovalListExpirations {
+		if expiration.ListType == listType && strings.EqualFold(expiration.Value, value) {
+			return expiration.ExpirationDate
+		}
+	}
+	return ""
+}
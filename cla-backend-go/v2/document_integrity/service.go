@@ -0,0 +1,229 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package document_integrity
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	v1ProjectParams "github.com/communitybridge/easycla/cla-backend-go/gen/restapi/operations/project"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// verification statuses
+const (
+	StatusVerified   = "verified"
+	StatusMismatch   = "mismatch"
+	StatusMissing    = "missing"
+	StatusUnrecorded = "unrecorded"
+)
+
+// claGroupPageSize is the number of CLA Groups loaded per page when auditing all the CLA Groups
+const claGroupPageSize = int64(100)
+
+// ErrNoSignedDocument is returned when the signature is an employee acknowledgement, which has no signed document
+var ErrNoSignedDocument = errors.New("employee signature does not have signed document")
+
+// systemUser is the actor recorded on the events created by the scheduled audit
+var systemUser = &v1Models.User{
+	UserID:     "easycla system",
+	LfUsername: "easycla system",
+	Username:   "easycla system",
+}
+
+// Summary holds the results of auditing the signed documents of all the CLA Groups
+type Summary struct {
+	CLAGroupsAudited int
+	DocumentsAudited int
+	MismatchCount    int
+	MissingCount     int
+}
+
+// DownloadFunc returns the content of the stored file, utils.ErrS3FileNotFound when the file does not exist
+type DownloadFunc func(filename string) ([]byte, error)
+
+// Service defines the functions of the signed document integrity service. The SHA-256 digest of each ICLA and CCLA
+// signed document is recorded on its signature when the document is stored, the service recomputes the digest of the
+// stored document and compares it with the recorded one.
+type Service interface {
+	VerifySignature(sig *v1Models.Signature) (*models.SignedDocumentVerification, error)
+	AuditCLAGroup(claGroupID string) (*models.SignedDocumentAudit, error)
+	AuditAllCLAGroups() (*Summary, error)
+}
+
+type service struct {
+	signaturesRepo signatures.SignatureRepository
+	projectRepo    project.ProjectRepository
+	eventsService  events.Service
+	download       DownloadFunc
+}
+
+// NewService creates a new instance of the signed document integrity service - the stored documents are loaded
+// with download, which is utils.DownloadFromS3 outside of the tests
+func NewService(signaturesRepo signatures.SignatureRepository, projectRepo project.ProjectRepository, eventsService events.Service, download DownloadFunc) Service {
+	return service{
+		signaturesRepo: signaturesRepo,
+		projectRepo:    projectRepo,
+		eventsService:  eventsService,
+		download:       download,
+	}
+}
+
+// VerifySignature recomputes the digest of the stored signed document of the signature and compares it with the
+// recorded digest, returns ErrNoSignedDocument for employee acknowledgements
+func (s service) VerifySignature(sig *v1Models.Signature) (*models.SignedDocumentVerification, error) {
+	signatureType, ok := signedDocumentType(sig)
+	if !ok {
+		return nil, ErrNoSignedDocument
+	}
+	result := &models.SignedDocumentVerification{
+		SignatureID:    sig.SignatureID.String(),
+		ClaGroupID:     sig.ProjectID,
+		SignatureType:  signatureType,
+		ExpectedSha256: sig.SignatureDocumentSha256,
+	}
+	_, result.VerifiedOn = utils.CurrentTime()
+
+	content, err := s.download(utils.SignedCLAFilename(sig.ProjectID, signatureType, sig.SignatureReferenceID.String(), sig.SignatureID.String()))
+	switch {
+	case err == utils.ErrS3FileNotFound:
+		result.Status = StatusMissing
+		return result, nil
+	case err != nil:
+		return nil, err
+	}
+
+	result.ActualSha256 = utils.DocumentDigest(content)
+	switch {
+	case result.ExpectedSha256 == "":
+		result.Status = StatusUnrecorded
+	case result.ExpectedSha256 == result.ActualSha256:
+		result.Status = StatusVerified
+	default:
+		result.Status = StatusMismatch
+	}
+	return result, nil
+}
+
+// AuditCLAGroup verifies the stored signed documents of all the ICLA and CCLA signatures of the CLA Group, a
+// signed document audit failed event is logged when mismatched or missing documents are found
+func (s service) AuditCLAGroup(claGroupID string) (*models.SignedDocumentAudit, error) {
+	f := logrus.Fields{
+		"functionName": "AuditCLAGroup",
+		"claGroupID":   claGroupID,
+	}
+	claGroupModel, err := s.projectRepo.GetCLAGroupByID(claGroupID, project.DontLoadRepoDetails)
+	if err != nil {
+		return nil, err
+	}
+	sigs, err := s.signaturesRepo.ProjectSignatures(claGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := &models.SignedDocumentAudit{
+		ClaGroupID: claGroupID,
+		Problems:   []*models.SignedDocumentVerification{},
+	}
+	_, audit.AuditedOn = utils.CurrentTime()
+	var problemSignatureIDs []string
+	for _, sig := range sigs.Signatures {
+		if _, ok := signedDocumentType(sig); !ok {
+			continue
+		}
+		result, err := s.VerifySignature(sig)
+		if err != nil {
+			log.WithFields(f).Warnf("unable to verify the signed document of signature: %s, error: %+v", sig.SignatureID, err)
+			return nil, err
+		}
+		audit.DocumentCount++
+		switch result.Status {
+		case StatusVerified:
+			audit.VerifiedCount++
+		case StatusUnrecorded:
+			audit.UnrecordedCount++
+		case StatusMismatch:
+			audit.MismatchCount++
+		case StatusMissing:
+			audit.MissingCount++
+		}
+		if result.Status == StatusMismatch || result.Status == StatusMissing {
+			audit.Problems = append(audit.Problems, result)
+			problemSignatureIDs = append(problemSignatureIDs, result.SignatureID)
+		}
+	}
+	log.WithFields(f).Debugf("audited %d signed documents - %d verified, %d unrecorded, %d mismatched, %d missing",
+		audit.DocumentCount, audit.VerifiedCount, audit.UnrecordedCount, audit.MismatchCount, audit.MissingCount)
+
+	if len(problemSignatureIDs) > 0 {
+		s.eventsService.LogEvent(&events.LogEventArgs{
+			EventType:         events.SignedDocumentAuditFailed,
+			ProjectID:         claGroupModel.ProjectID,
+			ProjectModel:      claGroupModel,
+			UserModel:         systemUser,
+			ExternalProjectID: claGroupModel.ProjectExternalID,
+			EventData: &events.SignedDocumentAuditFailedEventData{
+				DocumentCount: int(audit.DocumentCount),
+				MismatchCount: int(audit.MismatchCount),
+				MissingCount:  int(audit.MissingCount),
+				SignatureIDs:  problemSignatureIDs,
+			},
+		})
+	}
+	return audit, nil
+}
+
+// AuditAllCLAGroups audits the signed documents of every CLA Group, used by the scheduled audit
+func (s service) AuditAllCLAGroups() (*Summary, error) {
+	f := logrus.Fields{
+		"functionName": "AuditAllCLAGroups",
+	}
+	summary := &Summary{}
+	var nextKey *string
+	for {
+		claGroups, err := s.projectRepo.GetCLAGroups(&v1ProjectParams.GetProjectsParams{
+			NextKey:  nextKey,
+			PageSize: aws.Int64(claGroupPageSize),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, claGroup := range claGroups.Projects {
+			audit, err := s.AuditCLAGroup(claGroup.ProjectID)
+			if err != nil {
+				// keep going with the other CLA Groups, this one is audited again on the next run
+				log.WithFields(f).Warnf("unable to audit the signed documents of CLA Group: %s, error: %+v", claGroup.ProjectID, err)
+				continue
+			}
+			summary.CLAGroupsAudited++
+			summary.DocumentsAudited += int(audit.DocumentCount)
+			summary.MismatchCount += int(audit.MismatchCount)
+			summary.MissingCount += int(audit.MissingCount)
+		}
+		if claGroups.LastKeyScanned == "" {
+			break
+		}
+		nextKey = aws.String(claGroups.LastKeyScanned)
+	}
+	return summary, nil
+}
+
+// signedDocumentType returns the signature type of the signed document of the signature, false for employee
+// acknowledgements which have no signed document
+func signedDocumentType(sig *v1Models.Signature) (string, bool) {
+	switch {
+	case sig.SignatureType == "ccla":
+		return "ccla", true
+	case sig.SignatureType == "cla" && sig.SignatureUserCompanyID == "":
+		return "icla", true
+	}
+	return "", false
+}
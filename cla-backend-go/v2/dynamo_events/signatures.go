@@ -37,6 +37,7 @@ type Signature struct {
 	DomainWhitelist               []string `json:"domain_whitelist"`
 	GitHubWhitelist               []string `json:"github_whitelist"`
 	GitHubOrgWhitelist            []string `json:"github_org_whitelist"`
+	GitHubTeamWhitelist           []string `json:"github_team_whitelist"`
 	SignatureACL                  []string `json:"signature_acl"`
 	SigtypeSignedApprovedID       string   `json:"sigtype_signed_approved_id"`
 	UserGithubUsername            string   `json:"user_github_username"`
@@ -0,0 +1,92 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/communitybridge/easycla/cla-backend-go/export"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// eventsExportSheet is the sheet of the events exports
+const eventsExportSheet = "Events"
+
+// eventsExportColumns are the columns of the events exports
+var eventsExportColumns = []export.Column{
+	{Name: "dateTime", Header: "Date-Time"},
+	{Name: "activity", Header: "Activity"},
+	{Name: "performedBy", Header: "Performed By"},
+	{Name: "companyName", Header: "Company Name"},
+	{Name: "project", Header: "Project"},
+}
+
+// EventsExportResponse creates a new response handler for the events export files in the format
+func EventsExportResponse(name, format string, events *models.EventList) middleware.Responder {
+	return &EventsExportResponderFunc{
+		Name:   name,
+		Format: format,
+		Events: events,
+	}
+}
+
+// EventsExportResponderFunc wraps a func as a Responder interface
+type EventsExportResponderFunc struct {
+	Name   string
+	Format string
+	Events *models.EventList
+}
+
+// WriteResponse writes to the response - the export is built before the response status is sent so that an error
+// is still reported as an error response
+func (fn EventsExportResponderFunc) WriteResponse(rw http.ResponseWriter, pr runtime.Producer) {
+	var b bytes.Buffer
+	if err := ExportEvents(&b, fn.Format, fn.Events); err != nil {
+		msg := fmt.Sprintf("issue converting event models to %s format - error: %+v.", fn.Format, err)
+		log.Warn(msg)
+		rw.Header().Set(runtime.HeaderContentType, runtime.JSONMime)
+		rw.WriteHeader(http.StatusInternalServerError)
+		if produceErr := runtime.JSONProducer().Produce(rw, map[string]string{"error": msg}); produceErr != nil {
+			log.Warnf("issue writing error response response - error: %+v", produceErr)
+		}
+		return
+	}
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment;filename=%s", export.Filename(fn.Name, fn.Format)))
+	rw.Header().Set(runtime.HeaderContentType, export.ContentType(fn.Format))
+	rw.WriteHeader(http.StatusOK)
+	if _, err := rw.Write(b.Bytes()); err != nil {
+		log.Warnf("issue writing %s events export - error: %+v", fn.Format, err)
+	}
+}
+
+// ExportEvents writes the events to w in the export format
+func ExportEvents(w io.Writer, format string, eventList *models.EventList) error {
+	exporter, err := export.NewExporter(format, w)
+	if err != nil {
+		return err
+	}
+	if err = exporter.BeginSheet(eventsExportSheet, eventsExportColumns); err != nil {
+		return err
+	}
+	for _, event := range eventList.Events {
+		// the event time is exported as is when it can't be parsed
+		var eventTime interface{} = event.EventTime
+		if et, parseErr := utils.ParseDateTime(event.EventTime); parseErr == nil {
+			eventTime = et
+		}
+		err = exporter.WriteRow([]interface{}{eventTime, event.EventData, event.UserName, event.EventCompanyName, event.EventProjectSFName})
+		if err != nil {
+			return err
+		}
+	}
+	return exporter.Close()
+}
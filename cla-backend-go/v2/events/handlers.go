@@ -18,6 +18,7 @@ import (
 	"github.com/LF-Engineering/lfx-kit/auth"
 	v1Company "github.com/communitybridge/easycla/cla-backend-go/company"
 	v1Events "github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/export"
 	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
@@ -74,14 +75,18 @@ func Configure(api *operations.EasyclaAPI, service v1Events.Service, v1CompanyRe
 				})
 			}
 
+			format, err := export.NegotiateFormat(params.Format, params.HTTPRequest.Header.Get("Accept"))
+			if err != nil {
+				return WriteResponse(http.StatusBadRequest, runtime.JSONMime, runtime.JSONProducer(), errorResponse(err))
+			}
+
 			result, err := service.GetFoundationEvents(params.FoundationSFID, nil, nil, v1Events.ReturnAllEvents, nil)
 			if err != nil {
 				return WriteResponse(http.StatusBadRequest, runtime.JSONMime, runtime.JSONProducer(), errorResponse(err))
 			}
 
-			filename := fmt.Sprintf("foundation-events-%s.csv", params.FoundationSFID)
-			csvResponder := CSVEventsResponse(filename, result)
-			return csvResponder
+			name := fmt.Sprintf("foundation-events-%s", params.FoundationSFID)
+			return EventsExportResponse(name, format, result)
 		})
 	api.EventsGetFoundationEventsHandler = events.GetFoundationEventsHandlerFunc(
 		func(params events.GetFoundationEventsParams, authUser *auth.User) middleware.Responder {
@@ -115,6 +120,11 @@ func Configure(api *operations.EasyclaAPI, service v1Events.Service, v1CompanyRe
 						authUser.UserName, params.ProjectSFID),
 				})
 			}
+			format, err := export.NegotiateFormat(params.Format, params.HTTPRequest.Header.Get("Accept"))
+			if err != nil {
+				return WriteResponse(http.StatusBadRequest, runtime.JSONMime, runtime.JSONProducer(), errorResponse(err))
+			}
+
 			pm, err := projectsClaGroupsRepo.GetClaGroupIDForProject(params.ProjectSFID)
 			if err != nil {
 				if err == projects_cla_groups.ErrProjectNotAssociatedWithClaGroup {
@@ -131,9 +141,8 @@ func Configure(api *operations.EasyclaAPI, service v1Events.Service, v1CompanyRe
 				return events.NewGetProjectEventsAsCSVBadRequest().WithPayload(errorResponse(err))
 			}
 
-			filename := fmt.Sprintf("project-events-%s.csv", params.ProjectSFID)
-			csvResponder := CSVEventsResponse(filename, result)
-			return csvResponder
+			name := fmt.Sprintf("project-events-%s", params.ProjectSFID)
+			return EventsExportResponse(name, format, result)
 		})
 
 	api.EventsGetProjectEventsHandler = events.GetProjectEventsHandlerFunc(
@@ -0,0 +1,111 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package export_jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// artifactKeyPrefix is the key prefix of the managed export job artifacts
+const artifactKeyPrefix = "export-jobs"
+
+// ArtifactStore stores the export job artifacts
+type ArtifactStore interface {
+	Upload(key string, body io.Reader) error
+	Exists(key string) (bool, error)
+	Delete(key string) error
+	DownloadLink(key string) (string, error)
+}
+
+// NewBlobArtifactStore returns the artifact store of the signed documents blob store - the download links are valid
+// for utils.PresignedURLValidity when the blob store signs them
+func NewBlobArtifactStore(store storage.BlobStore) ArtifactStore {
+	return &blobArtifactStore{
+		store: store,
+	}
+}
+
+type blobArtifactStore struct {
+	store storage.BlobStore
+}
+
+// Upload uploads the artifact, streamed to the blob store
+func (s *blobArtifactStore) Upload(key string, body io.Reader) error {
+	err := s.store.Put(key, body, nil)
+	if err != nil {
+		log.Warnf("unable to upload export job artifact: %s, error: %v", key, err)
+	}
+	return err
+}
+
+// Exists returns true if the artifact exists
+func (s *blobArtifactStore) Exists(key string) (bool, error) {
+	_, err := s.store.Stat(key)
+	if err != nil {
+		if err == storage.ErrBlobNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Delete deletes the artifact
+func (s *blobArtifactStore) Delete(key string) error {
+	return s.store.Delete(key)
+}
+
+// DownloadLink returns the download URL of the artifact
+func (s *blobArtifactStore) DownloadLink(key string) (string, error) {
+	return s.store.DownloadURL(key, utils.PresignedURLValidity)
+}
+
+// artifactKey returns the key of the managed artifact of the job
+func artifactKey(job *Job, name string) string {
+	return fmt.Sprintf("%s/%s/%s.%s", artifactKeyPrefix, job.JobID, name, job.Format)
+}
+
+// Dispatcher starts the submitted export jobs
+type Dispatcher interface {
+	Dispatch(jobID string) error
+}
+
+// NewLambdaDispatcher returns the dispatcher invoking the export job lambda asynchronously for each job
+func NewLambdaDispatcher(awsSession *session.Session, functionName string) Dispatcher {
+	return &lambdaDispatcher{
+		lambdaClient: lambda.New(awsSession),
+		functionName: functionName,
+	}
+}
+
+type lambdaDispatcher struct {
+	lambdaClient *lambda.Lambda
+	functionName string
+}
+
+// Dispatch invokes the export job lambda without waiting for the job to complete
+func (d *lambdaDispatcher) Dispatch(jobID string) error {
+	payload, err := json.Marshal(&JobEvent{JobID: jobID})
+	if err != nil {
+		return err
+	}
+	_, err = d.lambdaClient.Invoke(&lambda.InvokeInput{
+		FunctionName:   aws.String(d.functionName),
+		InvocationType: aws.String(lambda.InvocationTypeEvent),
+		Payload:        payload,
+	})
+	if err != nil {
+		log.Warnf("unable to invoke %s for export job ID: %s, error: %v", d.functionName, jobID, err)
+	}
+	return err
+}
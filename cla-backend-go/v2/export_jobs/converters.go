@@ -0,0 +1,30 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package export_jobs
+
+import (
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+)
+
+// buildJobModel converts the export job into the response model, the download URL is only set for the succeeded jobs
+func buildJobModel(job *Job, downloadURL string) *models.ExportJob {
+	return &models.ExportJob{
+		JobID:         job.JobID,
+		JobType:       job.JobType,
+		Format:        job.Format,
+		ClaGroupID:    job.CLAGroupID,
+		CompanySFID:   job.CompanySFID,
+		Status:        job.Status,
+		Progress:      int64(job.Progress),
+		ErrorMessage:  job.ErrorMessage,
+		ArtifactSize:  job.ArtifactSize,
+		DownloadURL:   downloadURL,
+		RequestedBy:   job.RequestedBy,
+		DateStarted:   job.DateStarted,
+		DateCompleted: job.DateCompleted,
+		DateExpires:   job.DateExpires,
+		DateCreated:   job.DateCreated,
+		DateModified:  job.DateModified,
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package export_jobs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/export_jobs"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/common"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+// Configure setups handlers on api with service
+func Configure(api *operations.EasyclaAPI, service Service, projectRepo project.ProjectRepository) { //nolint
+	api.ExportJobsSubmitExportJobHandler = export_jobs.SubmitExportJobHandlerFunc(
+		func(params export_jobs.SubmitExportJobParams, authUser *auth.User) middleware.Responder {
+			f := logrus.Fields{
+				"functionName": "SubmitExportJobHandler",
+				"claGroupID":   params.ClaGroupID,
+				"authUser":     authUser.UserName,
+			}
+			claGroupModel, errResponse, code := common.LoadCLAGroup(projectRepo, authUser, params.XUSERNAME, params.XEMAIL, params.ClaGroupID, "SubmitExportJob")
+			switch code {
+			case 403:
+				return export_jobs.NewSubmitExportJobForbidden().WithPayload(errResponse)
+			case 404:
+				return export_jobs.NewSubmitExportJobNotFound().WithPayload(errResponse)
+			case 500:
+				return export_jobs.NewSubmitExportJobInternalServerError().WithPayload(errResponse)
+			}
+
+			job, err := service.SubmitJob(authUser, claGroupModel, params.Body)
+			if err != nil {
+				if errors.Is(err, ErrInvalidJob) {
+					return export_jobs.NewSubmitExportJobBadRequest().WithPayload(&models.ErrorResponse{
+						Code:    "400",
+						Message: fmt.Sprintf("EasyCLA - 400 Bad Request - %s", err),
+					})
+				}
+				log.WithFields(f).Warnf("unable to submit the export job, error: %+v", err)
+				return export_jobs.NewSubmitExportJobInternalServerError().WithPayload(errorResponse(err))
+			}
+			return export_jobs.NewSubmitExportJobAccepted().WithPayload(job)
+		})
+
+	api.ExportJobsListExportJobsHandler = export_jobs.ListExportJobsHandlerFunc(
+		func(params export_jobs.ListExportJobsParams, authUser *auth.User) middleware.Responder {
+			f := logrus.Fields{
+				"functionName": "ListExportJobsHandler",
+				"claGroupID":   params.ClaGroupID,
+				"authUser":     authUser.UserName,
+			}
+			_, errResponse, code := common.LoadCLAGroup(projectRepo, authUser, params.XUSERNAME, params.XEMAIL, params.ClaGroupID, "ListExportJobs")
+			switch code {
+			case 403:
+				return export_jobs.NewListExportJobsForbidden().WithPayload(errResponse)
+			case 404:
+				return export_jobs.NewListExportJobsNotFound().WithPayload(errResponse)
+			case 500:
+				return export_jobs.NewListExportJobsInternalServerError().WithPayload(errResponse)
+			}
+
+			jobs, err := service.ListJobs(params.ClaGroupID)
+			if err != nil {
+				log.WithFields(f).Warnf("unable to list the export jobs, error: %+v", err)
+				return export_jobs.NewListExportJobsInternalServerError().WithPayload(errorResponse(err))
+			}
+			return export_jobs.NewListExportJobsOK().WithPayload(jobs)
+		})
+
+	api.ExportJobsGetExportJobHandler = export_jobs.GetExportJobHandlerFunc(
+		func(params export_jobs.GetExportJobParams, authUser *auth.User) middleware.Responder {
+			f := logrus.Fields{
+				"functionName": "GetExportJobHandler",
+				"claGroupID":   params.ClaGroupID,
+				"jobID":        params.JobID,
+				"authUser":     authUser.UserName,
+			}
+			_, errResponse, code := common.LoadCLAGroup(projectRepo, authUser, params.XUSERNAME, params.XEMAIL, params.ClaGroupID, "GetExportJob")
+			switch code {
+			case 403:
+				return export_jobs.NewGetExportJobForbidden().WithPayload(errResponse)
+			case 404:
+				return export_jobs.NewGetExportJobNotFound().WithPayload(errResponse)
+			case 500:
+				return export_jobs.NewGetExportJobInternalServerError().WithPayload(errResponse)
+			}
+
+			job, err := service.GetJob(params.ClaGroupID, params.JobID)
+			if err != nil {
+				if err == ErrJobNotFound {
+					return export_jobs.NewGetExportJobNotFound().WithPayload(&models.ErrorResponse{
+						Code:    "404",
+						Message: fmt.Sprintf("EasyCLA - 404 Not Found - export job %s not found", params.JobID),
+					})
+				}
+				log.WithFields(f).Warnf("unable to get the export job, error: %+v", err)
+				return export_jobs.NewGetExportJobInternalServerError().WithPayload(errorResponse(err))
+			}
+			return export_jobs.NewGetExportJobOK().WithPayload(job)
+		})
+}
+
+type codedResponse interface {
+	Code() string
+}
+
+func errorResponse(err error) *models.ErrorResponse {
+	code := ""
+	if e, ok := err.(codedResponse); ok {
+		code = e.Code()
+	}
+
+	e := models.ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	}
+
+	return &e
+}
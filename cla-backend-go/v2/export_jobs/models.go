@@ -0,0 +1,90 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package export_jobs
+
+import (
+	v2Signatures "github.com/communitybridge/easycla/cla-backend-go/v2/signatures"
+)
+
+// Job statuses
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusExpired   = "expired"
+)
+
+// Job types
+const (
+	JobTypeICLASignatures        = "icla-signatures"
+	JobTypeCorporateContributors = "corporate-contributors"
+	JobTypeCLAGroupEvents        = "cla-group-events"
+	JobTypeSignedICLAZip         = "signed-icla-zip"
+	JobTypeSignedCCLAZip         = "signed-ccla-zip"
+)
+
+// FormatZip is the format of the signed document archive jobs
+const FormatZip = "zip"
+
+// Job is an export job of a CLA Group - the export runs in the background and its artifact can be downloaded until
+// the job expires. A managed artifact belongs to the job and is deleted when the job expires, the shared artifacts,
+// such as the signed document archives of the CLA Group, are kept.
+type Job struct {
+	JobID           string   `json:"job_id"`
+	JobType         string   `json:"job_type"`
+	Format          string   `json:"format"`
+	CLAGroupID      string   `json:"cla_group_id"`
+	CompanySFID     string   `json:"company_sfid,omitempty"`
+	Columns         []string `json:"columns,omitempty"`
+	SignedAfter     string   `json:"signed_after,omitempty"`
+	SignedBefore    string   `json:"signed_before,omitempty"`
+	Status          string   `json:"status"`
+	Progress        int      `json:"progress"`
+	ErrorMessage    string   `json:"error_message,omitempty"`
+	ArtifactKey     string   `json:"artifact_key,omitempty"`
+	ArtifactSize    int64    `json:"artifact_size,omitempty"`
+	ArtifactManaged bool     `json:"artifact_managed"`
+	RequestedBy     string   `json:"requested_by"`
+	DateStarted     string   `json:"date_started,omitempty"`
+	DateCompleted   string   `json:"date_completed,omitempty"`
+	DateExpires     string   `json:"date_expires"`
+	DateCreated     string   `json:"date_created"`
+	DateModified    string   `json:"date_modified"`
+}
+
+// exportOptions returns the signature export options of the job
+func (job *Job) exportOptions() (*v2Signatures.ExportOptions, error) {
+	options := &v2Signatures.ExportOptions{Format: job.Format, Columns: job.Columns}
+	if err := options.SetDateRange(job.SignedAfter, job.SignedBefore); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// archive returns true if the job builds a signed document archive
+func (job *Job) archive() bool {
+	return job.JobType == JobTypeSignedICLAZip || job.JobType == JobTypeSignedCCLAZip
+}
+
+// archiveType returns the signature type of the signed document archive job
+func (job *Job) archiveType() string {
+	if job.JobType == JobTypeSignedCCLAZip {
+		return v2Signatures.CCLA
+	}
+	return v2Signatures.ICLA
+}
+
+// filtered returns true if the signed document archive job only archives some of the signed documents, the filtered
+// archives are built on demand while the complete archives are shared by the jobs
+func (job *Job) filtered() bool {
+	return job.CompanySFID != "" || job.SignedAfter != "" || job.SignedBefore != ""
+}
+
+// JobEvent is the payload of the export job lambda - it runs the job of the event or, for the scheduled events,
+// cleans up the expired jobs
+type JobEvent struct {
+	JobID   string `json:"job_id,omitempty"`
+	Cleanup bool   `json:"cleanup,omitempty"`
+}
@@ -0,0 +1,192 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package export_jobs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// ErrJobNotFound is returned when the export job doesn't exist
+var ErrJobNotFound = errors.New("export job not found")
+
+// CLAGroupIDIndex is the index of the export jobs by CLA Group
+const CLAGroupIDIndex = "cla-group-id-index"
+
+// Repository defines the functions of the export job repository
+type Repository interface {
+	CreateJob(job *Job) error
+	GetJob(jobID string) (*Job, error)
+	GetCLAGroupJobs(claGroupID string) ([]*Job, error)
+	GetJobsExpiringBefore(dateTime string) ([]*Job, error)
+	UpdateJob(job *Job) error
+}
+
+// NewRepository creates a new instance of the export job repository
+func NewRepository(awsSession *session.Session, stage string) Repository {
+	return &repo{
+		stage:          stage,
+		dynamoDBClient: dynamodb.New(awsSession),
+		tableName:      fmt.Sprintf("cla-%s-export-jobs", stage),
+	}
+}
+
+type repo struct {
+	stage          string
+	dynamoDBClient *dynamodb.DynamoDB
+	tableName      string
+}
+
+// CreateJob stores the new export job
+func (repo *repo) CreateJob(job *Job) error {
+	av, err := dynamodbattribute.MarshalMap(job)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(repo.tableName),
+		ConditionExpression: aws.String("attribute_not_exists(job_id)"),
+	})
+	if err != nil {
+		log.Warnf("unable to create export job ID: %s, error: %v", job.JobID, err)
+		return err
+	}
+	return nil
+}
+
+// GetJob returns the export job, ErrJobNotFound if it doesn't exist
+func (repo *repo) GetJob(jobID string) (*Job, error) {
+	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"job_id": {S: aws.String(jobID)},
+		},
+		TableName: aws.String(repo.tableName),
+	})
+	if err != nil {
+		log.Warnf("error retrieving export job ID: %s, error: %v", jobID, err)
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrJobNotFound
+	}
+
+	var job Job
+	err = dynamodbattribute.UnmarshalMap(result.Item, &job)
+	if err != nil {
+		log.Warnf("error unmarshalling export job ID: %s, error: %v", jobID, err)
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetCLAGroupJobs returns the export jobs of the CLA Group
+func (repo *repo) GetCLAGroupJobs(claGroupID string) ([]*Job, error) {
+	condition := expression.Key("cla_group_id").Equal(expression.Value(claGroupID))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.Warnf("error building expression for export job query, CLA Group ID: %s, error: %v", claGroupID, err)
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(repo.tableName),
+		IndexName:                 aws.String(CLAGroupIDIndex),
+	}
+
+	var jobs []*Job
+	for {
+		results, queryErr := repo.dynamoDBClient.Query(queryInput)
+		if queryErr != nil {
+			log.Warnf("error retrieving export jobs for CLA Group ID: %s, error: %v", claGroupID, queryErr)
+			return nil, queryErr
+		}
+
+		var page []*Job
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &page)
+		if err != nil {
+			log.Warnf("error unmarshalling export jobs for CLA Group ID: %s, error: %v", claGroupID, err)
+			return nil, err
+		}
+		jobs = append(jobs, page...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return jobs, nil
+}
+
+// GetJobsExpiringBefore returns the export jobs, not expired yet, with an expiry date/time at or before the RFC3339
+// date/time
+func (repo *repo) GetJobsExpiringBefore(dateTime string) ([]*Job, error) {
+	filter := expression.Name("date_expires").LessThanEqual(expression.Value(dateTime)).
+		And(expression.Name("status").NotEqual(expression.Value(StatusExpired)))
+	expr, err := expression.NewBuilder().WithFilter(filter).Build()
+	if err != nil {
+		log.Warnf("error building expression for expired export job scan, error: %v", err)
+		return nil, err
+	}
+
+	scanInput := &dynamodb.ScanInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		TableName:                 aws.String(repo.tableName),
+	}
+
+	var jobs []*Job
+	for {
+		results, scanErr := repo.dynamoDBClient.Scan(scanInput)
+		if scanErr != nil {
+			log.Warnf("error scanning for expired export jobs, error: %v", scanErr)
+			return nil, scanErr
+		}
+
+		var page []*Job
+		if err := dynamodbattribute.UnmarshalListOfMaps(results.Items, &page); err != nil {
+			log.Warnf("error unmarshalling expired export jobs, error: %v", err)
+			return nil, err
+		}
+		jobs = append(jobs, page...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return jobs, nil
+}
+
+// UpdateJob stores the updated export job
+func (repo *repo) UpdateJob(job *Job) error {
+	av, err := dynamodbattribute.MarshalMap(job)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(repo.tableName),
+	})
+	if err != nil {
+		log.Warnf("unable to store export job ID: %s, error: %v", job.JobID, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package export_jobs
+
+import (
+	"fmt"
+
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+)
+
+// NewMemoryRepository creates a new export job repository backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string) Repository {
+	return &memoryRepo{
+		store:     store,
+		tableName: fmt.Sprintf("cla-%s-export-jobs", stage),
+	}
+}
+
+type memoryRepo struct {
+	store     *storage.MemoryStore
+	tableName string
+}
+
+func (repo *memoryRepo) CreateJob(job *Job) error {
+	return repo.store.Create(repo.tableName, job.JobID, job)
+}
+
+func (repo *memoryRepo) GetJob(jobID string) (*Job, error) {
+	var job Job
+	found, err := repo.store.Get(repo.tableName, jobID, &job)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrJobNotFound
+	}
+	return &job, nil
+}
+
+func (repo *memoryRepo) GetCLAGroupJobs(claGroupID string) ([]*Job, error) {
+	return repo.scanJobs(func(job *Job) bool {
+		return job.CLAGroupID == claGroupID
+	})
+}
+
+func (repo *memoryRepo) GetJobsExpiringBefore(dateTime string) ([]*Job, error) {
+	return repo.scanJobs(func(job *Job) bool {
+		return job.DateExpires <= dateTime && job.Status != StatusExpired
+	})
+}
+
+func (repo *memoryRepo) UpdateJob(job *Job) error {
+	return repo.store.Put(repo.tableName, job.JobID, job)
+}
+
+func (repo *memoryRepo) scanJobs(match func(job *Job) bool) ([]*Job, error) {
+	var jobs []*Job
+	if err := repo.store.Scan(repo.tableName, &jobs); err != nil {
+		return nil, err
+	}
+	var result []*Job
+	for _, job := range jobs {
+		if match(job) {
+			result = append(result, job)
+		}
+	}
+	return result, nil
+}
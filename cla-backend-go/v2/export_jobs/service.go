@@ -0,0 +1,452 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package export_jobs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/export"
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	v2Events "github.com/communitybridge/easycla/cla-backend-go/v2/events"
+	v2Signatures "github.com/communitybridge/easycla/cla-backend-go/v2/signatures"
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRetention is the time during which the export job artifacts can be downloaded
+const DefaultRetention = 24 * time.Hour
+
+// the coarse progress, in percent, of the export job stages
+const (
+	progressStarted   = 10
+	progressGenerated = 70
+	progressCompleted = 100
+)
+
+// errors
+var (
+	ErrInvalidJob       = errors.New("invalid export job")
+	ErrJobNotPending    = errors.New("export job is not pending")
+	ErrArtifactNotFound = errors.New("export job artifact not found")
+)
+
+// CleanupSummary holds the results of the expired export job cleanup
+type CleanupSummary struct {
+	JobsExpired      int
+	ArtifactsDeleted int
+}
+
+// Service defines the functions of the export job service. The export jobs are submitted by the API and run in the
+// background by the dispatcher, the job status and progress are polled until the artifact can be downloaded. The
+// jobs expire after the retention period - their managed artifacts are then deleted by the scheduled cleanup.
+type Service interface {
+	SubmitJob(authUser *auth.User, claGroupModel *v1Models.Project, input *models.ExportJobInput) (*models.ExportJob, error)
+	GetJob(claGroupID, jobID string) (*models.ExportJob, error)
+	ListJobs(claGroupID string) (*models.ExportJobList, error)
+	RunJob(jobID string) error
+	CleanupExpiredJobs(now time.Time) (*CleanupSummary, error)
+}
+
+type service struct {
+	repo              Repository
+	companyRepo       company.IRepository
+	signaturesService v2Signatures.Service
+	eventsService     events.Service
+	zipBuilder        v2Signatures.ZipBuilder
+	store             ArtifactStore
+	dispatcher        Dispatcher
+	retention         time.Duration
+}
+
+// NewService creates a new instance of the export job service - the jobs are run in the background of the current
+// process when no dispatcher is provided
+func NewService(repo Repository, companyRepo company.IRepository, signaturesService v2Signatures.Service, eventsService events.Service, zipBuilder v2Signatures.ZipBuilder,
+	store ArtifactStore, dispatcher Dispatcher, retention time.Duration) Service {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return service{
+		repo:              repo,
+		companyRepo:       companyRepo,
+		signaturesService: signaturesService,
+		eventsService:     eventsService,
+		zipBuilder:        zipBuilder,
+		store:             store,
+		dispatcher:        dispatcher,
+		retention:         retention,
+	}
+}
+
+// SubmitJob stores the export job requested by the user and dispatches it
+func (s service) SubmitJob(authUser *auth.User, claGroupModel *v1Models.Project, input *models.ExportJobInput) (*models.ExportJob, error) {
+	f := logrus.Fields{
+		"functionName": "SubmitJob",
+		"claGroupID":   claGroupModel.ProjectID,
+		"authUser":     authUser.UserName,
+	}
+	format, err := validateInput(claGroupModel, input)
+	if err != nil {
+		return nil, err
+	}
+
+	now, currentTimeString := utils.CurrentTime()
+	job := &Job{
+		JobID:        uuid.Must(uuid.NewV4()).String(),
+		JobType:      utils.StringValue(input.JobType),
+		Format:       format,
+		CLAGroupID:   claGroupModel.ProjectID,
+		CompanySFID:  input.CompanySFID,
+		Columns:      input.Columns,
+		SignedAfter:  input.SignedAfter,
+		SignedBefore: input.SignedBefore,
+		Status:       StatusPending,
+		RequestedBy:  authUser.UserName,
+		DateExpires:  utils.TimeToString(now.Add(s.retention)),
+		DateCreated:  currentTimeString,
+		DateModified: currentTimeString,
+	}
+	if err = s.repo.CreateJob(job); err != nil {
+		return nil, err
+	}
+	log.WithFields(f).Debugf("submitted %s export job: %s", job.JobType, job.JobID)
+
+	if s.dispatcher == nil {
+		go func() {
+			if runErr := s.RunJob(job.JobID); runErr != nil {
+				log.WithFields(f).Warnf("export job %s failed, error: %+v", job.JobID, runErr)
+			}
+		}()
+		return buildJobModel(job, ""), nil
+	}
+	if err = s.dispatcher.Dispatch(job.JobID); err != nil {
+		// the job is recorded as failed, the dispatch error is returned either way
+		_ = s.completeJob(job, fmt.Errorf("unable to dispatch the export job: %w", err))
+		return nil, err
+	}
+	return buildJobModel(job, ""), nil
+}
+
+// GetJob returns the export job of the CLA Group with the download link of its artifact once it succeeded
+func (s service) GetJob(claGroupID, jobID string) (*models.ExportJob, error) {
+	job, err := s.repo.GetJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.CLAGroupID != claGroupID {
+		return nil, ErrJobNotFound
+	}
+	downloadURL := ""
+	if job.Status == StatusSucceeded && job.ArtifactKey != "" {
+		downloadURL, err = s.store.DownloadLink(job.ArtifactKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buildJobModel(job, downloadURL), nil
+}
+
+// ListJobs returns the export jobs of the CLA Group, the most recent first
+func (s service) ListJobs(claGroupID string) (*models.ExportJobList, error) {
+	jobs, err := s.repo.GetCLAGroupJobs(claGroupID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].DateCreated > jobs[j].DateCreated
+	})
+	result := &models.ExportJobList{List: []*models.ExportJob{}}
+	for _, job := range jobs {
+		result.List = append(result.List, buildJobModel(job, ""))
+	}
+	return result, nil
+}
+
+// RunJob generates the artifact of the pending export job, ErrJobNotPending is returned if the job was already run
+func (s service) RunJob(jobID string) error {
+	f := logrus.Fields{
+		"functionName": "RunJob",
+		"jobID":        jobID,
+	}
+	job, err := s.repo.GetJob(jobID)
+	if err != nil {
+		return err
+	}
+	if job.Status != StatusPending {
+		return ErrJobNotPending
+	}
+
+	_, currentTimeString := utils.CurrentTime()
+	job.Status = StatusRunning
+	job.Progress = progressStarted
+	job.DateStarted = currentTimeString
+	job.DateModified = currentTimeString
+	if err = s.repo.UpdateJob(job); err != nil {
+		return err
+	}
+
+	switch {
+	case job.archive() && job.filtered():
+		err = s.buildFilteredArchive(job)
+	case job.archive():
+		err = s.buildArchive(job)
+	default:
+		err = s.buildExport(job)
+	}
+	if err != nil {
+		log.WithFields(f).Warnf("unable to generate the %s export job artifact, error: %+v", job.JobType, err)
+	}
+	return s.completeJob(job, err)
+}
+
+// completeJob records the outcome of the job, the job expires after the retention period. The job error, if any, is
+// returned unless the job can't be updated.
+func (s service) completeJob(job *Job, jobErr error) error {
+	now, currentTimeString := utils.CurrentTime()
+	if jobErr != nil {
+		job.Status = StatusFailed
+		job.ErrorMessage = jobErr.Error()
+		job.ArtifactKey = ""
+		job.ArtifactSize = 0
+	} else {
+		job.Status = StatusSucceeded
+		job.Progress = progressCompleted
+	}
+	job.DateCompleted = currentTimeString
+	job.DateExpires = utils.TimeToString(now.Add(s.retention))
+	job.DateModified = currentTimeString
+	if err := s.repo.UpdateJob(job); err != nil {
+		return err
+	}
+	return jobErr
+}
+
+// buildExport writes the export to a temporary file which is uploaded as the managed artifact of the job
+func (s service) buildExport(job *Job) error {
+	return s.uploadArtifact(job, func(w io.Writer) (string, error) {
+		return s.writeExport(job, w)
+	})
+}
+
+// uploadArtifact writes the artifact of the job to a temporary file which is uploaded as the managed artifact of the
+// job, write returns the name of the artifact
+func (s service) uploadArtifact(job *Job, write func(w io.Writer) (string, error)) error {
+	file, err := ioutil.TempFile("", "export-job-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			log.Warnf("unable to close the export job file: %s, error: %+v", file.Name(), closeErr)
+		}
+		if removeErr := os.Remove(file.Name()); removeErr != nil {
+			log.Warnf("unable to remove the export job file: %s, error: %+v", file.Name(), removeErr)
+		}
+	}()
+
+	name, err := write(file)
+	if err != nil {
+		return err
+	}
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	job.Progress = progressGenerated
+	_, job.DateModified = utils.CurrentTime()
+	if err = s.repo.UpdateJob(job); err != nil {
+		return err
+	}
+
+	key := artifactKey(job, name)
+	if err = s.store.Upload(key, file); err != nil {
+		return err
+	}
+	job.ArtifactKey = key
+	job.ArtifactSize = size
+	job.ArtifactManaged = true
+	return nil
+}
+
+// writeExport writes the export of the job to w and returns the name of the artifact
+func (s service) writeExport(job *Job, w io.Writer) (string, error) {
+	options, err := job.exportOptions()
+	if err != nil {
+		return "", err
+	}
+	switch job.JobType {
+	case JobTypeICLASignatures:
+		return fmt.Sprintf("icla-signatures-%s", job.CLAGroupID),
+			s.signaturesService.ExportProjectIclaSignatures(w, job.CLAGroupID, options)
+	case JobTypeCorporateContributors:
+		return fmt.Sprintf("corporate-contributors-%s-%s", job.CLAGroupID, job.CompanySFID),
+			s.signaturesService.ExportClaGroupCorporateContributors(w, job.CLAGroupID, job.CompanySFID, options)
+	case JobTypeCLAGroupEvents:
+		eventList, err := s.eventsService.GetClaGroupEvents(job.CLAGroupID, nil, nil, events.ReturnAllEvents, nil)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("cla-group-events-%s", job.CLAGroupID), v2Events.ExportEvents(w, job.Format, eventList)
+	}
+	return "", fmt.Errorf("%w: unsupported job type %s", ErrInvalidJob, job.JobType)
+}
+
+// buildFilteredArchive writes the archive of the signed documents matching the job filters to a temporary file which
+// is uploaded as the managed artifact of the job
+func (s service) buildFilteredArchive(job *Job) error {
+	filter, err := s.archiveFilter(job)
+	if err != nil {
+		return err
+	}
+	return s.uploadArtifact(job, func(w io.Writer) (string, error) {
+		_, err := s.zipBuilder.BuildFilteredZip(w, job.archiveType(), job.CLAGroupID, filter)
+		return fmt.Sprintf("signed-%s-documents-%s", job.archiveType(), job.CLAGroupID), err
+	})
+}
+
+// archiveFilter returns the signed document archive filter of the job, the company SFID is resolved to its internal
+// company ID
+func (s service) archiveFilter(job *Job) (*v2Signatures.ArchiveFilter, error) {
+	options, err := job.exportOptions()
+	if err != nil {
+		return nil, err
+	}
+	filter := &v2Signatures.ArchiveFilter{SignedAfter: options.SignedAfter, SignedBefore: options.SignedBefore}
+	if job.CompanySFID != "" {
+		companyModel, err := s.companyRepo.GetCompanyByExternalID(job.CompanySFID)
+		if err != nil {
+			return nil, err
+		}
+		filter.CompanyID = companyModel.CompanyID
+	}
+	return filter, nil
+}
+
+// buildArchive refreshes the signed document archive of the CLA Group, the archive is shared by the jobs and isn't
+// deleted when the job expires
+func (s service) buildArchive(job *Job) error {
+	claType := job.archiveType()
+	buildZip := s.zipBuilder.BuildICLAZip
+	if claType == v2Signatures.CCLA {
+		buildZip = s.zipBuilder.BuildCCLAZip
+	}
+	// the large archives are built in several passes
+	err := buildZip(job.CLAGroupID)
+	for err == v2Signatures.ErrZipIncomplete {
+		err = buildZip(job.CLAGroupID)
+	}
+	if err != nil {
+		return err
+	}
+
+	key := utils.SignedClaGroupZipFilename(job.CLAGroupID, claType)
+	exists, err := s.store.Exists(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		// no signed document was archived
+		return ErrArtifactNotFound
+	}
+	job.ArtifactKey = key
+	job.ArtifactManaged = false
+	return nil
+}
+
+// CleanupExpiredJobs expires the jobs whose retention period is over and deletes their managed artifacts. The jobs
+// whose artifact can't be deleted are left as is and retried on the next run.
+func (s service) CleanupExpiredJobs(now time.Time) (*CleanupSummary, error) {
+	f := logrus.Fields{
+		"functionName": "CleanupExpiredJobs",
+	}
+	jobs, err := s.repo.GetJobsExpiringBefore(utils.TimeToString(now))
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &CleanupSummary{}
+	for _, job := range jobs {
+		if job.ArtifactManaged && job.ArtifactKey != "" {
+			if err = s.store.Delete(job.ArtifactKey); err != nil {
+				log.WithFields(f).Warnf("unable to delete the artifact: %s of export job: %s, error: %+v", job.ArtifactKey, job.JobID, err)
+				continue
+			}
+			summary.ArtifactsDeleted++
+		}
+		if job.Status == StatusPending || job.Status == StatusRunning {
+			job.ErrorMessage = "export job did not complete before it expired"
+		}
+		job.Status = StatusExpired
+		job.ArtifactKey = ""
+		job.ArtifactSize = 0
+		job.DateModified = utils.TimeToString(now)
+		if err = s.repo.UpdateJob(job); err != nil {
+			return nil, err
+		}
+		summary.JobsExpired++
+	}
+	return summary, nil
+}
+
+// validateInput validates the export job request and returns the format of the export
+func validateInput(claGroupModel *v1Models.Project, input *models.ExportJobInput) (string, error) {
+	if input == nil || input.JobType == nil {
+		return "", fmt.Errorf("%w: missing job type", ErrInvalidJob)
+	}
+	jobType := *input.JobType
+	switch jobType {
+	case JobTypeSignedICLAZip, JobTypeSignedCCLAZip:
+		if input.Format != "" && input.Format != FormatZip {
+			return "", fmt.Errorf("%w: the %s job only supports the %s format", ErrInvalidJob, jobType, FormatZip)
+		}
+		if jobType == JobTypeSignedICLAZip && !claGroupModel.ProjectICLAEnabled {
+			return "", fmt.Errorf("%w: individual contribution is not supported for this CLA Group", ErrInvalidJob)
+		}
+		if jobType == JobTypeSignedCCLAZip && !claGroupModel.ProjectCCLAEnabled {
+			return "", fmt.Errorf("%w: corporate contribution is not supported for this CLA Group", ErrInvalidJob)
+		}
+		if jobType == JobTypeSignedICLAZip && input.CompanySFID != "" {
+			return "", fmt.Errorf("%w: the %s job can't be filtered by company", ErrInvalidJob, jobType)
+		}
+		job := &Job{SignedAfter: input.SignedAfter, SignedBefore: input.SignedBefore}
+		if _, err := job.exportOptions(); err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidJob, err)
+		}
+		return FormatZip, nil
+	case JobTypeICLASignatures, JobTypeCorporateContributors, JobTypeCLAGroupEvents:
+	default:
+		return "", fmt.Errorf("%w: unsupported job type %s", ErrInvalidJob, jobType)
+	}
+
+	format, err := export.NegotiateFormat(&input.Format, "")
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidJob, err)
+	}
+	if jobType == JobTypeICLASignatures && !claGroupModel.ProjectICLAEnabled {
+		return "", fmt.Errorf("%w: individual contribution is not supported for this CLA Group", ErrInvalidJob)
+	}
+	if jobType == JobTypeCorporateContributors && input.CompanySFID == "" {
+		return "", fmt.Errorf("%w: the %s job requires the company SFID", ErrInvalidJob, jobType)
+	}
+	job := &Job{Columns: input.Columns, SignedAfter: input.SignedAfter, SignedBefore: input.SignedBefore}
+	if _, err = job.exportOptions(); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidJob, err)
+	}
+	return format, nil
+}
@@ -0,0 +1,332 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	project_service "github.com/communitybridge/easycla/cla-backend-go/v2/project-service"
+)
+
+// memoryRepo calculates the metrics from the records of the in-memory store and keeps the results in the store
+type memoryRepo struct {
+	*repo
+	store *storage.MemoryStore
+}
+
+// itemMemorySignature is the signature item including the flags used to filter the signatures table
+type itemMemorySignature struct {
+	ItemSignature
+	SignatureSigned   bool `json:"signature_signed"`
+	SignatureApproved bool `json:"signature_approved"`
+}
+
+// NewMemoryRepository creates new metrics repository backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string, apiGwURL string, pcgRepo projects_cla_groups.Repository) Repository {
+	return &memoryRepo{
+		repo: &repo{
+			metricTableName:       fmt.Sprintf("cla-%s-metrics", stage),
+			stage:                 stage,
+			apiGatewayURL:         apiGwURL,
+			projectsClaGroupsRepo: pcgRepo,
+		},
+		store: store,
+	}
+}
+
+func (repo *memoryRepo) tableName(name string) string {
+	return fmt.Sprintf("cla-%s-%s", repo.stage, name)
+}
+
+func (repo *memoryRepo) calculateMetrics() (*Metrics, error) {
+	metrics := newMetrics()
+	t := time.Now()
+
+	var users []*ItemUser
+	if err := repo.store.Scan(repo.tableName("users"), &users); err != nil {
+		return nil, err
+	}
+	usersCache := make(map[string]*ItemUser)
+	for _, user := range users {
+		if user.LfUsername != "" {
+			usersCache[user.LfUsername] = user
+		}
+	}
+
+	var projects []*ItemProject
+	if err := repo.store.Scan(repo.tableName("projects"), &projects); err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		metrics.TotalCountMetrics.ProjectsCount++
+		metrics.ProjectMetrics.processProjectItem(project, repo.apiGatewayURL)
+	}
+
+	var companies []*ItemCompany
+	if err := repo.store.Scan(repo.tableName("companies"), &companies); err != nil {
+		return nil, err
+	}
+	for _, company := range companies {
+		metrics.CompanyMetrics.processCompanyItem(company)
+		metrics.TotalCountMetrics.CompaniesCount++
+	}
+
+	var sigs []*itemMemorySignature
+	if err := repo.store.Scan(repo.tableName("signatures"), &sigs); err != nil {
+		return nil, err
+	}
+	for _, sig := range sigs {
+		if sig.SignatureSigned && sig.SignatureApproved {
+			metrics.processSignature(&sig.ItemSignature, usersCache)
+		}
+	}
+
+	var repos []*ItemRepository
+	if err := repo.store.Scan(repo.tableName("repositories"), &repos); err != nil {
+		return nil, err
+	}
+	for _, r := range repos {
+		metrics.TotalCountMetrics.GithubRepositoriesCount++
+		metrics.ProjectMetrics.processRepositories(r)
+	}
+
+	var gerritInstances []*ItemGerritInstance
+	if err := repo.store.Scan(repo.tableName("gerrit-instances"), &gerritInstances); err != nil {
+		return nil, err
+	}
+	for _, gi := range gerritInstances {
+		metrics.TotalCountMetrics.GerritRepositoriesCount++
+		metrics.ProjectMetrics.processGerritInstance(gi)
+	}
+
+	metrics.ClaManagersDistribution = calculateClaManagerDistribution(metrics.CompanyMetrics)
+	_, metrics.CalculatedAt = utils.CurrentTime()
+	log.Println("calculate metrics took time", time.Since(t).String())
+	return metrics, nil
+}
+
+// putMetric stores the metric using the metric type and id as the key
+func (repo *memoryRepo) putMetric(id, metricType string, metric interface{}) error {
+	av, err := dynamodbattribute.MarshalMap(metric)
+	if err != nil {
+		return err
+	}
+	addIDTypeTime(av, id, metricType)
+	return repo.store.Put(repo.metricTableName, metricType+"#"+id, av)
+}
+
+func (repo *memoryRepo) saveMetrics(metrics *Metrics) error {
+	tm := metrics.TotalCountMetrics
+	tm.RepositoriesCount = tm.GithubRepositoriesCount + tm.GerritRepositoriesCount
+	if err := repo.putMetric(IDTotalCount, MetricTypeTotalCount, tm); err != nil {
+		return err
+	}
+	for id, cm := range metrics.CompanyMetrics.CompanyMetrics {
+		if err := repo.putMetric(id, MetricTypeCompany, cm); err != nil {
+			return err
+		}
+	}
+	for id, pm := range metrics.ProjectMetrics.ProjectMetrics {
+		if err := repo.putMetric(id, MetricTypeProject, pm); err != nil {
+			return err
+		}
+	}
+	if err := repo.putMetric(IDClaManagerDistribution, MetricTypeClaManagerDistribution, metrics.ClaManagersDistribution); err != nil {
+		return err
+	}
+
+	claGroupMapping, err := repo.getClaGroupProjectsMapping()
+	if err != nil {
+		return err
+	}
+	psc := project_service.GetClient()
+	for id, cpm := range metrics.CompanyProjectMetrics.CompanyProjectMetrics {
+		pm, ok := metrics.ProjectMetrics.ProjectMetrics[cpm.ProjectID]
+		if !ok {
+			continue
+		}
+		cm, ok := metrics.CompanyMetrics.CompanyMetrics[cpm.CompanyID]
+		if !ok {
+			continue
+		}
+		claGroupMap, ok := claGroupMapping[cpm.ProjectID]
+		if !ok {
+			continue
+		}
+		if len(claGroupMap.projectSFIDList) == 1 {
+			cpm.ProjectSFID = claGroupMap.projectSFIDList[0]
+		} else {
+			cpm.ProjectSFID = claGroupMap.foundationSFID
+		}
+		projectDetails, err := psc.GetProject(cpm.ProjectSFID)
+		if err != nil {
+			log.Warnf("saveMetrics error = unable to get project details from project-service. %s", cpm.ProjectSFID)
+			continue
+		}
+		cpm.ProjectName = projectDetails.Name
+		cpm.CompanyName = cm.CompanyName
+		cpm.ClaGroupName = pm.ProjectName
+		if err := repo.putMetric(id, MetricTypeCompanyProject, cpm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *memoryRepo) clearOldMetrics(beforeTime time.Time) error {
+	type ItemMetric struct {
+		ID         string `json:"id"`
+		MetricType string `json:"metric_type"`
+		CreatedAt  string `json:"created_at"`
+	}
+	var metrics []*ItemMetric
+	if err := repo.store.Scan(repo.metricTableName, &metrics); err != nil {
+		return err
+	}
+	before := utils.TimeToString(beforeTime)
+	for _, m := range metrics {
+		if m.CreatedAt < before {
+			if err := repo.store.Delete(repo.metricTableName, m.MetricType+"#"+m.ID); err != nil {
+				log.Error(fmt.Sprintf("error deleting outdated metric with id:%s, metric_type:%s", m.ID, m.MetricType), err)
+			}
+		}
+	}
+	return nil
+}
+
+func (repo *memoryRepo) CalculateAndSaveMetrics() error {
+	timeBeforeStartingMetricsCalculation := time.Now()
+	m, err := repo.calculateMetrics()
+	if err != nil {
+		return err
+	}
+	err = repo.saveMetrics(m)
+	if err != nil {
+		return err
+	}
+	return repo.clearOldMetrics(timeBeforeStartingMetricsCalculation)
+}
+
+func (repo *memoryRepo) GetClaManagerDistribution() (*ClaManagersDistribution, error) {
+	var out ClaManagersDistribution
+	err := repo.getMetricByID(IDClaManagerDistribution, MetricTypeClaManagerDistribution, &out)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (repo *memoryRepo) GetTotalCountMetrics() (*TotalCountMetrics, error) {
+	var out TotalCountMetrics
+	err := repo.getMetricByID(IDTotalCount, MetricTypeTotalCount, &out)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (repo *memoryRepo) GetCompanyMetrics() ([]*CompanyMetric, error) {
+	var out []*CompanyMetric
+	if err := repo.scanMetrics(MetricTypeCompany, "", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (repo *memoryRepo) GetProjectMetrics(pageSize int64, nextKey string) ([]*ProjectMetric, string, error) {
+	var projectMetrics []*ProjectMetric
+	if err := repo.scanMetrics(MetricTypeProject, "", &projectMetrics); err != nil {
+		return nil, "", err
+	}
+	start := 0
+	if nextKey != "" {
+		for start < len(projectMetrics) && projectMetrics[start].ID <= nextKey {
+			start++
+		}
+	}
+	projectMetrics = projectMetrics[start:]
+	if int64(len(projectMetrics)) <= pageSize {
+		return projectMetrics, "", nil
+	}
+	projectMetrics = projectMetrics[0:pageSize]
+	return projectMetrics, projectMetrics[pageSize-1].ID, nil
+}
+
+func (repo *memoryRepo) GetCompanyMetric(companyID string) (*CompanyMetric, error) {
+	var out CompanyMetric
+	err := repo.getMetricByID(companyID, MetricTypeCompany, &out)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (repo *memoryRepo) GetProjectMetric(projectID string) (*ProjectMetric, error) {
+	var out ProjectMetric
+	err := repo.getMetricByID(projectID, MetricTypeProject, &out)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (repo *memoryRepo) GetProjectMetricBySalesForceID(salesforceID string) ([]*ProjectMetric, error) {
+	var projectMetrics []*ProjectMetric
+	if err := repo.scanMetrics(MetricTypeProject, "", &projectMetrics); err != nil {
+		return nil, err
+	}
+	var out []*ProjectMetric
+	for _, pm := range projectMetrics {
+		if pm.SalesforceID == salesforceID {
+			out = append(out, pm)
+		}
+	}
+	if len(out) == 0 {
+		return nil, ErrMetricNotFound
+	}
+	return out, nil
+}
+
+func (repo *memoryRepo) ListCompanyProjectMetrics(companyID string) ([]*CompanyProjectMetric, error) {
+	out := make([]*CompanyProjectMetric, 0)
+	if err := repo.scanMetrics(MetricTypeCompanyProject, companyID+"#", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (repo *memoryRepo) getMetricByID(id string, metricType string, out interface{}) error {
+	found, err := repo.store.Get(repo.metricTableName, metricType+"#"+id, out)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrMetricNotFound
+	}
+	return nil
+}
+
+// scanMetrics loads the metrics of the specified type whose id starts with the id prefix, ordered by id
+func (repo *memoryRepo) scanMetrics(metricType, idPrefix string, out interface{}) error {
+	var items []map[string]interface{}
+	if err := repo.store.Scan(repo.metricTableName, &items); err != nil {
+		return err
+	}
+	var matched []map[string]interface{}
+	for _, item := range items {
+		id, _ := item["id"].(string)
+		if item["metric_type"] == metricType && strings.HasPrefix(id, idPrefix) {
+			matched = append(matched, item)
+		}
+	}
+	av, err := dynamodbattribute.MarshalList(matched)
+	if err != nil {
+		return err
+	}
+	return dynamodbattribute.UnmarshalList(av, out)
+}
@@ -0,0 +1,66 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaigns
+
+import (
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+)
+
+// buildCampaignModel converts the campaign into the response model with its progress, the targets are only included
+// when requested
+func buildCampaignModel(campaign *Campaign, targets []*Target, includeTargets bool) *models.ResignCampaign {
+	progress := &models.ResignCampaignProgress{Total: int64(len(targets))}
+	for _, target := range targets {
+		switch target.Status {
+		case TargetStatusPending:
+			progress.Pending++
+		case TargetStatusResigned:
+			progress.Resigned++
+		case TargetStatusLapsed:
+			progress.Lapsed++
+		}
+		if target.NotificationCount > 0 {
+			progress.Notified++
+		}
+	}
+
+	result := &models.ResignCampaign{
+		CampaignID:       campaign.CampaignID,
+		ClaGroupID:       campaign.CLAGroupID,
+		Status:           campaign.Status,
+		IclaMajorVersion: int64(campaign.ICLAMajorVersion),
+		CclaMajorVersion: int64(campaign.CCLAMajorVersion),
+		GracePeriodDays:  campaign.GracePeriodDays,
+		Deadline:         campaign.Deadline,
+		ClosedBy:         campaign.ClosedBy,
+		DateClosed:       campaign.DateClosed,
+		DateCreated:      campaign.DateCreated,
+		DateModified:     campaign.DateModified,
+		Progress:         progress,
+	}
+	if includeTargets {
+		result.Targets = []*models.ResignCampaignTarget{}
+		for _, target := range targets {
+			result.Targets = append(result.Targets, buildTargetModel(target))
+		}
+	}
+	return result
+}
+
+// buildTargetModel converts the campaign target into the response model
+func buildTargetModel(target *Target) *models.ResignCampaignTarget {
+	return &models.ResignCampaignTarget{
+		TargetID:             target.TargetID,
+		SignatureID:          target.SignatureID,
+		SignatureType:        target.SignatureType,
+		ReferenceID:          target.ReferenceID,
+		ReferenceName:        target.ReferenceName,
+		DocumentMajorVersion: int64(target.DocumentMajorVersion),
+		Status:               target.Status,
+		NotificationCount:    int64(target.NotificationCount),
+		LastNotificationDate: target.LastNotificationDate,
+		ResignedSignatureID:  target.ResignedSignatureID,
+		DateResolved:         target.DateResolved,
+	}
+}
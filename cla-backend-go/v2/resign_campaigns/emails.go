@@ -0,0 +1,135 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaigns
+
+import (
+	"fmt"
+
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// recipient is the name and email address of a re-sign notification recipient
+type recipient struct {
+	name    string
+	address string
+}
+
+// userAddress returns the email address of the user
+func userAddress(user v1Models.User) string {
+	if user.LfEmail != "" {
+		return user.LfEmail
+	}
+	if len(user.Emails) > 0 {
+		return user.Emails[0]
+	}
+	return ""
+}
+
+// targetRecipients returns the signer of the ICLA or the CLA Managers of the CCLA
+func (s service) targetRecipients(target *Target) []recipient {
+	if target.SignatureType == SignatureTypeICLA {
+		user, err := s.usersService.GetUser(target.ReferenceID)
+		if err != nil || user == nil {
+			log.Warnf("unable to lookup the signer by user ID: %s of re-sign campaign target: %s, error: %+v", target.ReferenceID, target.TargetID, err)
+			return nil
+		}
+		return []recipient{{name: user.Username, address: userAddress(*user)}}
+	}
+
+	sig, err := s.signaturesRepo.GetSignature(target.SignatureID)
+	if err != nil || sig == nil {
+		log.Warnf("unable to lookup the CCLA signature: %s of re-sign campaign target: %s, error: %+v", target.SignatureID, target.TargetID, err)
+		return nil
+	}
+	var recipients []recipient
+	for _, claManager := range sig.SignatureACL {
+		recipients = append(recipients, recipient{name: claManager.Username, address: userAddress(claManager)})
+	}
+	return recipients
+}
+
+// deadlineContent returns the paragraph explaining when the previous version signature stops covering contributions
+func deadlineContent(campaign *Campaign) string {
+	if campaign.Deadline == "" {
+		return ""
+	}
+	return fmt.Sprintf("<p>The previous version signature no longer covers any contribution after %s.</p>", campaign.Deadline)
+}
+
+// sendResignNotification asks the ICLA signer or the CLA Managers of the CCLA to sign the new document version
+func (s service) sendResignNotification(claGroupModel *v1Models.Project, campaign *Campaign, target *Target) {
+	var subject, action string
+	if target.SignatureType == SignatureTypeICLA {
+		subject = fmt.Sprintf("EasyCLA: Please sign the new Individual CLA version for %s", claGroupModel.ProjectName)
+		action = fmt.Sprintf(`<p>To keep your contributions covered, please sign version %d of the Individual CLA the next
+time EasyCLA asks you to on a pull request or change request.</p>`, campaign.ICLAMajorVersion)
+	} else {
+		subject = fmt.Sprintf("EasyCLA: Please sign the new Corporate CLA version of %s for %s", target.ReferenceName, claGroupModel.ProjectName)
+		action = fmt.Sprintf(`<p>To keep the contributions of %s covered, a signatory of %s should sign version %d of the
+Corporate CLA. Please <a href="https://%s#/company/%s" target="_blank">log into the EasyCLA Corporate Console</a> to
+start the signature.</p>`, target.ReferenceName, target.ReferenceName, campaign.CCLAMajorVersion, s.corporateConsoleURL, target.ReferenceID)
+	}
+
+	for _, r := range s.targetRecipients(target) {
+		body := fmt.Sprintf(`
+<p>Hello %s,</p>
+<p>This is a notification email from EasyCLA regarding the project %s.</p>
+<p>The CLA of %s was updated to a new version which requires the existing signers to sign again. The signature of %s
+is on version %d.</p>
+%s
+%s
+%s
+%s`,
+			r.name, claGroupModel.ProjectName,
+			claGroupModel.ProjectName, target.ReferenceName,
+			target.DocumentMajorVersion,
+			action, deadlineContent(campaign),
+			utils.GetEmailHelpContent(claGroupModel.Version == utils.V2), utils.GetEmailSignOffContent())
+		sendEmail("sendResignNotification", subject, body, r)
+	}
+}
+
+// sendSignatureLapsedNotification notifies the ICLA signer or the CLA Managers of the CCLA that the previous version
+// signature was revoked after the campaign deadline
+func (s service) sendSignatureLapsedNotification(claGroupModel *v1Models.Project, campaign *Campaign, target *Target) {
+	subject := fmt.Sprintf("EasyCLA: Previous CLA version signature of %s for %s revoked", target.ReferenceName, claGroupModel.ProjectName)
+	for _, r := range s.targetRecipients(target) {
+		body := fmt.Sprintf(`
+<p>Hello %s,</p>
+<p>This is a notification email from EasyCLA regarding the project %s.</p>
+<p>The version %d signature of %s was not renewed before the re-sign deadline of %s and no longer covers any
+contribution to %s. Please sign the new version of the CLA to contribute again.</p>
+%s
+%s`,
+			r.name, claGroupModel.ProjectName,
+			target.DocumentMajorVersion, target.ReferenceName, campaign.Deadline,
+			claGroupModel.ProjectName,
+			utils.GetEmailHelpContent(claGroupModel.Version == utils.V2), utils.GetEmailSignOffContent())
+		sendEmail("sendSignatureLapsedNotification", subject, body, r)
+	}
+}
+
+// sendEmail sends the email to the recipient, problems are only logged
+func sendEmail(functionName, subject, body string, r recipient) {
+	f := logrus.Fields{
+		"function":         functionName,
+		"recipientName":    r.name,
+		"recipientAddress": r.address,
+	}
+	if r.address == "" {
+		log.WithFields(f).Warnf("unable to send email with subject: %s - recipient has no email address", subject)
+		return
+	}
+
+	recipients := []string{r.address}
+	err := utils.SendEmail(subject, body, recipients)
+	if err != nil {
+		log.WithFields(f).Warnf("problem sending email with subject: %s to recipients: %+v, error: %+v", subject, recipients, err)
+	} else {
+		log.WithFields(f).Debugf("sent email with subject: %s to recipients: %+v", subject, recipients)
+	}
+}
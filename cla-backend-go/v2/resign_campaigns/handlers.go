@@ -0,0 +1,173 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaigns
+
+import (
+	"fmt"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/resign_campaigns"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/common"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+// Configure setups handlers on api with service
+func Configure(api *operations.EasyclaAPI, service Service, projectRepo project.ProjectRepository) { //nolint
+	api.ResignCampaignsGetResignPolicyHandler = resign_campaigns.GetResignPolicyHandlerFunc(
+		func(params resign_campaigns.GetResignPolicyParams, authUser *auth.User) middleware.Responder {
+			claGroupModel, errResponse, code := common.LoadCLAGroup(projectRepo, authUser, params.XUSERNAME, params.XEMAIL, params.ClaGroupID, "GetResignPolicy")
+			switch code {
+			case 403:
+				return resign_campaigns.NewGetResignPolicyForbidden().WithPayload(errResponse)
+			case 404:
+				return resign_campaigns.NewGetResignPolicyNotFound().WithPayload(errResponse)
+			case 500:
+				return resign_campaigns.NewGetResignPolicyInternalServerError().WithPayload(errResponse)
+			}
+			return resign_campaigns.NewGetResignPolicyOK().WithPayload(service.GetResignPolicy(claGroupModel))
+		})
+
+	api.ResignCampaignsUpdateResignPolicyHandler = resign_campaigns.UpdateResignPolicyHandlerFunc(
+		func(params resign_campaigns.UpdateResignPolicyParams, authUser *auth.User) middleware.Responder {
+			f := logrus.Fields{
+				"functionName": "UpdateResignPolicyHandler",
+				"claGroupID":   params.ClaGroupID,
+				"authUser":     authUser.UserName,
+			}
+			claGroupModel, errResponse, code := common.LoadCLAGroup(projectRepo, authUser, params.XUSERNAME, params.XEMAIL, params.ClaGroupID, "UpdateResignPolicy")
+			switch code {
+			case 403:
+				return resign_campaigns.NewUpdateResignPolicyForbidden().WithPayload(errResponse)
+			case 404:
+				return resign_campaigns.NewUpdateResignPolicyNotFound().WithPayload(errResponse)
+			case 500:
+				return resign_campaigns.NewUpdateResignPolicyInternalServerError().WithPayload(errResponse)
+			}
+
+			policy, err := service.UpdateResignPolicy(authUser, claGroupModel, params.Body)
+			if err != nil {
+				log.WithFields(f).Warnf("unable to update the re-sign policy, error: %+v", err)
+				return resign_campaigns.NewUpdateResignPolicyInternalServerError().WithPayload(errorResponse(err))
+			}
+			return resign_campaigns.NewUpdateResignPolicyOK().WithPayload(policy)
+		})
+
+	api.ResignCampaignsListResignCampaignsHandler = resign_campaigns.ListResignCampaignsHandlerFunc(
+		func(params resign_campaigns.ListResignCampaignsParams, authUser *auth.User) middleware.Responder {
+			f := logrus.Fields{
+				"functionName": "ListResignCampaignsHandler",
+				"claGroupID":   params.ClaGroupID,
+				"authUser":     authUser.UserName,
+			}
+			_, errResponse, code := common.LoadCLAGroup(projectRepo, authUser, params.XUSERNAME, params.XEMAIL, params.ClaGroupID, "ListResignCampaigns")
+			switch code {
+			case 403:
+				return resign_campaigns.NewListResignCampaignsForbidden().WithPayload(errResponse)
+			case 404:
+				return resign_campaigns.NewListResignCampaignsNotFound().WithPayload(errResponse)
+			case 500:
+				return resign_campaigns.NewListResignCampaignsInternalServerError().WithPayload(errResponse)
+			}
+
+			campaigns, err := service.ListCampaigns(params.ClaGroupID)
+			if err != nil {
+				log.WithFields(f).Warnf("unable to list the re-sign campaigns, error: %+v", err)
+				return resign_campaigns.NewListResignCampaignsInternalServerError().WithPayload(errorResponse(err))
+			}
+			return resign_campaigns.NewListResignCampaignsOK().WithPayload(campaigns)
+		})
+
+	api.ResignCampaignsGetResignCampaignHandler = resign_campaigns.GetResignCampaignHandlerFunc(
+		func(params resign_campaigns.GetResignCampaignParams, authUser *auth.User) middleware.Responder {
+			f := logrus.Fields{
+				"functionName": "GetResignCampaignHandler",
+				"claGroupID":   params.ClaGroupID,
+				"campaignID":   params.CampaignID,
+				"authUser":     authUser.UserName,
+			}
+			_, errResponse, code := common.LoadCLAGroup(projectRepo, authUser, params.XUSERNAME, params.XEMAIL, params.ClaGroupID, "GetResignCampaign")
+			switch code {
+			case 403:
+				return resign_campaigns.NewGetResignCampaignForbidden().WithPayload(errResponse)
+			case 404:
+				return resign_campaigns.NewGetResignCampaignNotFound().WithPayload(errResponse)
+			case 500:
+				return resign_campaigns.NewGetResignCampaignInternalServerError().WithPayload(errResponse)
+			}
+
+			campaign, err := service.GetCampaign(params.ClaGroupID, params.CampaignID)
+			if err != nil {
+				if err == ErrCampaignNotFound {
+					return resign_campaigns.NewGetResignCampaignNotFound().WithPayload(&models.ErrorResponse{
+						Code:    "404",
+						Message: fmt.Sprintf("EasyCLA - 404 Not Found - re-sign campaign %s not found", params.CampaignID),
+					})
+				}
+				log.WithFields(f).Warnf("unable to get the re-sign campaign, error: %+v", err)
+				return resign_campaigns.NewGetResignCampaignInternalServerError().WithPayload(errorResponse(err))
+			}
+			return resign_campaigns.NewGetResignCampaignOK().WithPayload(campaign)
+		})
+
+	api.ResignCampaignsCancelResignCampaignHandler = resign_campaigns.CancelResignCampaignHandlerFunc(
+		func(params resign_campaigns.CancelResignCampaignParams, authUser *auth.User) middleware.Responder {
+			f := logrus.Fields{
+				"functionName": "CancelResignCampaignHandler",
+				"claGroupID":   params.ClaGroupID,
+				"campaignID":   params.CampaignID,
+				"authUser":     authUser.UserName,
+			}
+			claGroupModel, errResponse, code := common.LoadCLAGroup(projectRepo, authUser, params.XUSERNAME, params.XEMAIL, params.ClaGroupID, "CancelResignCampaign")
+			switch code {
+			case 403:
+				return resign_campaigns.NewCancelResignCampaignForbidden().WithPayload(errResponse)
+			case 404:
+				return resign_campaigns.NewCancelResignCampaignNotFound().WithPayload(errResponse)
+			case 500:
+				return resign_campaigns.NewCancelResignCampaignInternalServerError().WithPayload(errResponse)
+			}
+
+			campaign, err := service.CancelCampaign(authUser, claGroupModel, params.CampaignID)
+			if err != nil {
+				switch err {
+				case ErrCampaignNotFound:
+					return resign_campaigns.NewCancelResignCampaignNotFound().WithPayload(&models.ErrorResponse{
+						Code:    "404",
+						Message: fmt.Sprintf("EasyCLA - 404 Not Found - re-sign campaign %s not found", params.CampaignID),
+					})
+				case ErrCampaignNotActive:
+					return resign_campaigns.NewCancelResignCampaignConflict().WithPayload(&models.ErrorResponse{
+						Code:    "409",
+						Message: fmt.Sprintf("EasyCLA - 409 Conflict - re-sign campaign %s is not active", params.CampaignID),
+					})
+				}
+				log.WithFields(f).Warnf("unable to cancel the re-sign campaign, error: %+v", err)
+				return resign_campaigns.NewCancelResignCampaignInternalServerError().WithPayload(errorResponse(err))
+			}
+			return resign_campaigns.NewCancelResignCampaignOK().WithPayload(campaign)
+		})
+}
+
+type codedResponse interface {
+	Code() string
+}
+
+func errorResponse(err error) *models.ErrorResponse {
+	code := ""
+	if e, ok := err.(codedResponse); ok {
+		code = e.Code()
+	}
+
+	e := models.ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	}
+
+	return &e
+}
@@ -0,0 +1,61 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaigns
+
+// Campaign statuses
+const (
+	StatusActive     = "active"
+	StatusCompleted  = "completed"
+	StatusEnforced   = "enforced"
+	StatusCancelled  = "cancelled"
+	StatusSuperseded = "superseded"
+)
+
+// Target statuses
+const (
+	TargetStatusPending  = "pending"
+	TargetStatusResigned = "resigned"
+	TargetStatusLapsed   = "lapsed"
+)
+
+// Target signature types
+const (
+	SignatureTypeICLA = "icla"
+	SignatureTypeCCLA = "ccla"
+)
+
+// Campaign is the re-sign campaign started when a new major version of the CLA Group documents requires re-signature,
+// a zero major version means the document type was not updated
+type Campaign struct {
+	CampaignID       string `json:"campaign_id"`
+	CLAGroupID       string `json:"cla_group_id"`
+	Status           string `json:"status"`
+	ICLAMajorVersion int    `json:"icla_major_version"`
+	CCLAMajorVersion int    `json:"ccla_major_version"`
+	GracePeriodDays  int64  `json:"grace_period_days"`
+	Deadline         string `json:"deadline,omitempty"`
+	ClosedBy         string `json:"closed_by,omitempty"`
+	DateClosed       string `json:"date_closed,omitempty"`
+	DateCreated      string `json:"date_created"`
+	DateModified     string `json:"date_modified"`
+}
+
+// Target is an ICLA or CCLA signature of a previous major version tracked by a re-sign campaign
+type Target struct {
+	TargetID              string `json:"target_id"`
+	CampaignID            string `json:"campaign_id"`
+	SignatureID           string `json:"signature_id"`
+	SignatureType         string `json:"signature_type"`
+	ReferenceID           string `json:"reference_id"`
+	ReferenceName         string `json:"reference_name"`
+	DocumentMajorVersion  int    `json:"document_major_version"`
+	Status                string `json:"status"`
+	NotificationCount     int    `json:"notification_count"`
+	FirstNotificationDate string `json:"first_notification_date,omitempty"`
+	LastNotificationDate  string `json:"last_notification_date,omitempty"`
+	ResignedSignatureID   string `json:"resigned_signature_id,omitempty"`
+	DateResolved          string `json:"date_resolved,omitempty"`
+	DateCreated           string `json:"date_created"`
+	DateModified          string `json:"date_modified"`
+}
@@ -0,0 +1,298 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaigns
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// errors
+var (
+	ErrCampaignNotFound  = errors.New("re-sign campaign not found")
+	ErrCampaignNotActive = errors.New("re-sign campaign is not active")
+)
+
+// indexes
+const (
+	CLAGroupIDIndex = "cla-group-id-index"
+	CampaignIDIndex = "campaign-id-index"
+)
+
+// Repository defines the functions of the re-sign campaign and target repository
+type Repository interface {
+	CreateCampaign(campaign *Campaign) error
+	GetCampaign(campaignID string) (*Campaign, error)
+	GetCLAGroupCampaigns(claGroupID string) ([]*Campaign, error)
+	GetActiveCampaigns() ([]*Campaign, error)
+	CloseCampaign(campaignID, status, closedBy string) (*Campaign, error)
+
+	CreateTargets(targets []*Target) error
+	GetTargets(campaignID string) ([]*Target, error)
+	UpdateTarget(target *Target) error
+}
+
+// NewRepository creates a new instance of the re-sign campaign repository
+func NewRepository(awsSession *session.Session, stage string) Repository {
+	return &repo{
+		stage:              stage,
+		dynamoDBClient:     dynamodb.New(awsSession),
+		campaignsTableName: fmt.Sprintf("cla-%s-resign-campaigns", stage),
+		targetsTableName:   fmt.Sprintf("cla-%s-resign-campaign-targets", stage),
+	}
+}
+
+type repo struct {
+	stage              string
+	dynamoDBClient     *dynamodb.DynamoDB
+	campaignsTableName string
+	targetsTableName   string
+}
+
+// CreateCampaign stores the new re-sign campaign
+func (repo *repo) CreateCampaign(campaign *Campaign) error {
+	av, err := dynamodbattribute.MarshalMap(campaign)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(repo.campaignsTableName),
+		ConditionExpression: aws.String("attribute_not_exists(campaign_id)"),
+	})
+	if err != nil {
+		log.Warnf("unable to create re-sign campaign ID: %s, error: %v", campaign.CampaignID, err)
+		return err
+	}
+	return nil
+}
+
+// GetCampaign returns the re-sign campaign, ErrCampaignNotFound if it doesn't exist
+func (repo *repo) GetCampaign(campaignID string) (*Campaign, error) {
+	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"campaign_id": {S: aws.String(campaignID)},
+		},
+		TableName: aws.String(repo.campaignsTableName),
+	})
+	if err != nil {
+		log.Warnf("error retrieving re-sign campaign ID: %s, error: %v", campaignID, err)
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrCampaignNotFound
+	}
+
+	var campaign Campaign
+	err = dynamodbattribute.UnmarshalMap(result.Item, &campaign)
+	if err != nil {
+		log.Warnf("error unmarshalling re-sign campaign ID: %s, error: %v", campaignID, err)
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// GetCLAGroupCampaigns returns the re-sign campaigns of the CLA Group
+func (repo *repo) GetCLAGroupCampaigns(claGroupID string) ([]*Campaign, error) {
+	condition := expression.Key("cla_group_id").Equal(expression.Value(claGroupID))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.Warnf("error building expression for re-sign campaign query, CLA Group ID: %s, error: %v", claGroupID, err)
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(repo.campaignsTableName),
+		IndexName:                 aws.String(CLAGroupIDIndex),
+	}
+
+	var campaigns []*Campaign
+	for {
+		results, queryErr := repo.dynamoDBClient.Query(queryInput)
+		if queryErr != nil {
+			log.Warnf("error retrieving re-sign campaigns for CLA Group ID: %s, error: %v", claGroupID, queryErr)
+			return nil, queryErr
+		}
+
+		var page []*Campaign
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &page)
+		if err != nil {
+			log.Warnf("error unmarshalling re-sign campaigns for CLA Group ID: %s, error: %v", claGroupID, err)
+			return nil, err
+		}
+		campaigns = append(campaigns, page...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return campaigns, nil
+}
+
+// GetActiveCampaigns returns the active re-sign campaigns across the CLA Groups
+func (repo *repo) GetActiveCampaigns() ([]*Campaign, error) {
+	filter := expression.Name("status").Equal(expression.Value(StatusActive))
+	expr, err := expression.NewBuilder().WithFilter(filter).Build()
+	if err != nil {
+		log.Warnf("error building expression for active re-sign campaign scan, error: %v", err)
+		return nil, err
+	}
+
+	scanInput := &dynamodb.ScanInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		TableName:                 aws.String(repo.campaignsTableName),
+	}
+
+	var campaigns []*Campaign
+	for {
+		results, scanErr := repo.dynamoDBClient.Scan(scanInput)
+		if scanErr != nil {
+			log.Warnf("error scanning for active re-sign campaigns, error: %v", scanErr)
+			return nil, scanErr
+		}
+
+		var page []*Campaign
+		if err := dynamodbattribute.UnmarshalListOfMaps(results.Items, &page); err != nil {
+			log.Warnf("error unmarshalling active re-sign campaigns, error: %v", err)
+			return nil, err
+		}
+		campaigns = append(campaigns, page...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return campaigns, nil
+}
+
+// CloseCampaign moves the active re-sign campaign to the specified status, ErrCampaignNotActive if the campaign was
+// already closed
+func (repo *repo) CloseCampaign(campaignID, status, closedBy string) (*Campaign, error) {
+	_, currentTimeString := utils.CurrentTime()
+	_, err := repo.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"campaign_id": {S: aws.String(campaignID)},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#S": aws.String("status"),
+			"#B": aws.String("closed_by"),
+			"#C": aws.String("date_closed"),
+			"#M": aws.String("date_modified"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":s":      {S: aws.String(status)},
+			":b":      {S: aws.String(closedBy)},
+			":c":      {S: aws.String(currentTimeString)},
+			":active": {S: aws.String(StatusActive)},
+		},
+		UpdateExpression:    aws.String("SET #S = :s, #B = :b, #C = :c, #M = :c"),
+		ConditionExpression: aws.String("attribute_exists(campaign_id) AND #S = :active"),
+		TableName:           aws.String(repo.campaignsTableName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			if _, getErr := repo.GetCampaign(campaignID); getErr != nil {
+				return nil, getErr
+			}
+			return nil, ErrCampaignNotActive
+		}
+		log.Warnf("unable to close re-sign campaign ID: %s, error: %v", campaignID, err)
+		return nil, err
+	}
+	return repo.GetCampaign(campaignID)
+}
+
+// CreateTargets stores the targets of a new re-sign campaign
+func (repo *repo) CreateTargets(targets []*Target) error {
+	for _, target := range targets {
+		if err := repo.putTarget(target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTargets returns the targets of the re-sign campaign
+func (repo *repo) GetTargets(campaignID string) ([]*Target, error) {
+	condition := expression.Key("campaign_id").Equal(expression.Value(campaignID))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.Warnf("error building expression for re-sign campaign target query, campaign ID: %s, error: %v", campaignID, err)
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(repo.targetsTableName),
+		IndexName:                 aws.String(CampaignIDIndex),
+	}
+
+	var targets []*Target
+	for {
+		results, queryErr := repo.dynamoDBClient.Query(queryInput)
+		if queryErr != nil {
+			log.Warnf("error retrieving re-sign campaign targets for campaign ID: %s, error: %v", campaignID, queryErr)
+			return nil, queryErr
+		}
+
+		var page []*Target
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &page)
+		if err != nil {
+			log.Warnf("error unmarshalling re-sign campaign targets for campaign ID: %s, error: %v", campaignID, err)
+			return nil, err
+		}
+		targets = append(targets, page...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return targets, nil
+}
+
+// UpdateTarget stores the updated re-sign campaign target
+func (repo *repo) UpdateTarget(target *Target) error {
+	return repo.putTarget(target)
+}
+
+// putTarget stores the re-sign campaign target
+func (repo *repo) putTarget(target *Target) error {
+	av, err := dynamodbattribute.MarshalMap(target)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(repo.targetsTableName),
+	})
+	if err != nil {
+		log.Warnf("unable to store re-sign campaign target ID: %s, error: %v", target.TargetID, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaigns
+
+import (
+	"fmt"
+
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// NewMemoryRepository creates a new re-sign campaign repository backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string) Repository {
+	return &memoryRepo{
+		store:              store,
+		campaignsTableName: fmt.Sprintf("cla-%s-resign-campaigns", stage),
+		targetsTableName:   fmt.Sprintf("cla-%s-resign-campaign-targets", stage),
+	}
+}
+
+type memoryRepo struct {
+	store              *storage.MemoryStore
+	campaignsTableName string
+	targetsTableName   string
+}
+
+func (repo *memoryRepo) CreateCampaign(campaign *Campaign) error {
+	return repo.store.Create(repo.campaignsTableName, campaign.CampaignID, campaign)
+}
+
+func (repo *memoryRepo) GetCampaign(campaignID string) (*Campaign, error) {
+	var campaign Campaign
+	found, err := repo.store.Get(repo.campaignsTableName, campaignID, &campaign)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrCampaignNotFound
+	}
+	return &campaign, nil
+}
+
+func (repo *memoryRepo) GetCLAGroupCampaigns(claGroupID string) ([]*Campaign, error) {
+	return repo.scanCampaigns(func(campaign *Campaign) bool {
+		return campaign.CLAGroupID == claGroupID
+	})
+}
+
+func (repo *memoryRepo) GetActiveCampaigns() ([]*Campaign, error) {
+	return repo.scanCampaigns(func(campaign *Campaign) bool {
+		return campaign.Status == StatusActive
+	})
+}
+
+func (repo *memoryRepo) CloseCampaign(campaignID, status, closedBy string) (*Campaign, error) {
+	_, currentTimeString := utils.CurrentTime()
+	var campaign Campaign
+	err := repo.store.Update(repo.campaignsTableName, campaignID, &campaign, func() error {
+		if campaign.Status != StatusActive {
+			return ErrCampaignNotActive
+		}
+		campaign.Status = status
+		campaign.ClosedBy = closedBy
+		campaign.DateClosed = currentTimeString
+		campaign.DateModified = currentTimeString
+		return nil
+	})
+	if err == storage.ErrItemNotFound {
+		return nil, ErrCampaignNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+func (repo *memoryRepo) CreateTargets(targets []*Target) error {
+	for _, target := range targets {
+		if err := repo.store.Put(repo.targetsTableName, target.TargetID, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *memoryRepo) GetTargets(campaignID string) ([]*Target, error) {
+	var targets []*Target
+	if err := repo.store.Scan(repo.targetsTableName, &targets); err != nil {
+		return nil, err
+	}
+	var result []*Target
+	for _, target := range targets {
+		if target.CampaignID == campaignID {
+			result = append(result, target)
+		}
+	}
+	return result, nil
+}
+
+func (repo *memoryRepo) UpdateTarget(target *Target) error {
+	return repo.store.Put(repo.targetsTableName, target.TargetID, target)
+}
+
+func (repo *memoryRepo) scanCampaigns(match func(campaign *Campaign) bool) ([]*Campaign, error) {
+	var campaigns []*Campaign
+	if err := repo.store.Scan(repo.campaignsTableName, &campaigns); err != nil {
+		return nil, err
+	}
+	var result []*Campaign
+	for _, campaign := range campaigns {
+		if match(campaign) {
+			result = append(result, campaign)
+		}
+	}
+	return result, nil
+}
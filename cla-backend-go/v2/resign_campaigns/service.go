@@ -0,0 +1,595 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaigns
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultBatchSize is the maximum number of re-sign notifications sent per run of the scheduled job
+const DefaultBatchSize = 200
+
+// DefaultNotificationIntervalDays is the number of days between two re-sign notifications of the same target
+const DefaultNotificationIntervalDays = 7
+
+// maxNotifications is the number of re-sign notifications after which a target is no longer notified
+const maxNotifications = 3
+
+// systemUser is the actor recorded on the events created by the template updates and the scheduled job
+var systemUser = &v1Models.User{
+	UserID:     "easycla system",
+	LfUsername: "easycla system",
+	Username:   "easycla system",
+}
+
+// Summary holds the results of processing the active re-sign campaigns, the targets awaiting notice are the pending
+// targets of campaigns past their deadline which were not notified a full grace period before
+type Summary struct {
+	TargetsResigned       int
+	TargetsNotified       int
+	TargetsLapsed         int
+	TargetsAwaitingNotice int
+	CampaignsCompleted    int
+	CampaignsEnforced     int
+}
+
+// Service defines the functions of the re-sign campaign service. A campaign starts when the template of a CLA Group
+// requiring re-signature creates a new major version of its documents - the ICLA and CCLA signers of the previous
+// versions are notified in batches until they sign the new version. When the CLA Group has a grace period, the previous
+// version signatures which were not re-signed are revoked once the grace period is over - a signature is only revoked
+// when its signer was first notified at least a full grace period before.
+type Service interface {
+	StartCampaign(claGroupID string, iclaMajorVersion, cclaMajorVersion int) error
+	ProcessCampaigns(now time.Time) (*Summary, error)
+
+	GetResignPolicy(claGroupModel *v1Models.Project) *models.ResignPolicy
+	UpdateResignPolicy(authUser *auth.User, claGroupModel *v1Models.Project, policy *models.ResignPolicy) (*models.ResignPolicy, error)
+	ListCampaigns(claGroupID string) (*models.ResignCampaignList, error)
+	GetCampaign(claGroupID, campaignID string) (*models.ResignCampaign, error)
+	CancelCampaign(authUser *auth.User, claGroupModel *v1Models.Project, campaignID string) (*models.ResignCampaign, error)
+}
+
+type service struct {
+	repo                     Repository
+	projectRepo              project.ProjectRepository
+	signaturesRepo           signatures.SignatureRepository
+	usersService             users.Service
+	eventsService            events.Service
+	corporateConsoleURL      string
+	batchSize                int
+	notificationIntervalDays int
+}
+
+// NewService creates a new instance of the re-sign campaign service - at most batchSize notifications are sent per
+// run of the scheduled job and a target is notified again after notificationIntervalDays
+func NewService(repo Repository, projectRepo project.ProjectRepository, signaturesRepo signatures.SignatureRepository, usersService users.Service,
+	eventsService events.Service, corporateConsoleURL string, batchSize, notificationIntervalDays int) Service {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if notificationIntervalDays <= 0 {
+		notificationIntervalDays = DefaultNotificationIntervalDays
+	}
+	return service{
+		repo:                     repo,
+		projectRepo:              projectRepo,
+		signaturesRepo:           signaturesRepo,
+		usersService:             usersService,
+		eventsService:            eventsService,
+		corporateConsoleURL:      corporateConsoleURL,
+		batchSize:                batchSize,
+		notificationIntervalDays: notificationIntervalDays,
+	}
+}
+
+// StartCampaign starts the re-sign campaign of the CLA Group for the new ICLA and CCLA major versions, nothing is done
+// if the CLA Group doesn't require re-signature. The active campaign of the CLA Group is superseded by the new one,
+// which keeps tracking the document types the superseded campaign was tracking.
+func (s service) StartCampaign(claGroupID string, iclaMajorVersion, cclaMajorVersion int) error {
+	f := logrus.Fields{
+		"functionName":     "StartCampaign",
+		"claGroupID":       claGroupID,
+		"iclaMajorVersion": iclaMajorVersion,
+		"cclaMajorVersion": cclaMajorVersion,
+	}
+	claGroupModel, err := s.projectRepo.GetCLAGroupByID(claGroupID, project.DontLoadRepoDetails)
+	if err != nil {
+		return err
+	}
+	if !claGroupModel.ProjectResignRequired {
+		log.WithFields(f).Debug("CLA Group doesn't require re-signature - no re-sign campaign started")
+		return nil
+	}
+
+	campaigns, err := s.repo.GetCLAGroupCampaigns(claGroupID)
+	if err != nil {
+		return err
+	}
+	for _, campaign := range campaigns {
+		if campaign.Status != StatusActive {
+			continue
+		}
+		if _, err = s.repo.CloseCampaign(campaign.CampaignID, StatusSuperseded, systemUser.LfUsername); err != nil && err != ErrCampaignNotActive {
+			return err
+		}
+		if iclaMajorVersion == 0 {
+			iclaMajorVersion = campaign.ICLAMajorVersion
+		}
+		if cclaMajorVersion == 0 {
+			cclaMajorVersion = campaign.CCLAMajorVersion
+		}
+	}
+
+	now, currentTimeString := utils.CurrentTime()
+	campaign := &Campaign{
+		CampaignID:       uuid.Must(uuid.NewV4()).String(),
+		CLAGroupID:       claGroupID,
+		Status:           StatusActive,
+		ICLAMajorVersion: iclaMajorVersion,
+		CCLAMajorVersion: cclaMajorVersion,
+		GracePeriodDays:  claGroupModel.ProjectResignGracePeriodDays,
+		DateCreated:      currentTimeString,
+		DateModified:     currentTimeString,
+	}
+	if campaign.GracePeriodDays > 0 {
+		campaign.Deadline = utils.TimeToString(now.AddDate(0, 0, int(campaign.GracePeriodDays)))
+	}
+
+	var targets []*Target
+	err = s.signaturesRepo.ForEachProjectSignatureVersion(claGroupID, func(sig *v1Models.Signature) error {
+		signatureType := targetSignatureType(sig)
+		majorVersion, ok := signatureMajorVersion(sig)
+		if signatureType == "" || !ok || majorVersion >= campaign.requiredMajorVersion(signatureType) {
+			return nil
+		}
+		targets = append(targets, &Target{
+			TargetID:             uuid.Must(uuid.NewV4()).String(),
+			CampaignID:           campaign.CampaignID,
+			SignatureID:          sig.SignatureID.String(),
+			SignatureType:        signatureType,
+			ReferenceID:          sig.SignatureReferenceID.String(),
+			ReferenceName:        signatureReferenceName(sig),
+			DocumentMajorVersion: majorVersion,
+			Status:               TargetStatusPending,
+			DateCreated:          currentTimeString,
+			DateModified:         currentTimeString,
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.CreateCampaign(campaign); err != nil {
+		return err
+	}
+	if err = s.repo.CreateTargets(targets); err != nil {
+		return err
+	}
+	log.WithFields(f).Debugf("started re-sign campaign: %s with %d targets", campaign.CampaignID, len(targets))
+
+	s.eventsService.LogEvent(&events.LogEventArgs{
+		EventType:         events.ResignCampaignStarted,
+		ProjectID:         claGroupModel.ProjectID,
+		ProjectModel:      claGroupModel,
+		UserModel:         systemUser,
+		ExternalProjectID: claGroupModel.ProjectExternalID,
+		EventData: &events.ResignCampaignStartedEventData{
+			CampaignID:       campaign.CampaignID,
+			ICLAMajorVersion: campaign.ICLAMajorVersion,
+			CCLAMajorVersion: campaign.CCLAMajorVersion,
+			TargetCount:      len(targets),
+			Deadline:         campaign.Deadline,
+		},
+	})
+	return nil
+}
+
+// ProcessCampaigns records the targets of the active campaigns which re-signed, sends the due notifications, revokes
+// the signatures of the notified targets once the campaign deadline passed and closes the campaigns which have no
+// pending target left
+func (s service) ProcessCampaigns(now time.Time) (*Summary, error) {
+	f := logrus.Fields{
+		"functionName": "ProcessCampaigns",
+		"now":          utils.TimeToString(now),
+	}
+	campaigns, err := s.repo.GetActiveCampaigns()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{}
+	remainingNotifications := s.batchSize
+	for _, campaign := range campaigns {
+		notified, err := s.processCampaign(campaign, now, remainingNotifications, summary)
+		if err != nil {
+			// keep going with the other campaigns, this one is retried on the next run
+			log.WithFields(f).Warnf("unable to process re-sign campaign: %s, error: %+v", campaign.CampaignID, err)
+			continue
+		}
+		remainingNotifications -= notified
+	}
+	return summary, nil
+}
+
+// processCampaign processes the active campaign, returns the number of notifications sent
+func (s service) processCampaign(campaign *Campaign, now time.Time, remainingNotifications int, summary *Summary) (int, error) {
+	claGroupModel, err := s.projectRepo.GetCLAGroupByID(campaign.CLAGroupID, project.DontLoadRepoDetails)
+	if err != nil {
+		return 0, err
+	}
+	targets, err := s.repo.GetTargets(campaign.CampaignID)
+	if err != nil {
+		return 0, err
+	}
+	latestSignatures := map[string]latestSignature{}
+	err = s.signaturesRepo.ForEachProjectSignatureVersion(campaign.CLAGroupID, func(sig *v1Models.Signature) error {
+		addLatestSignature(latestSignatures, sig)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	currentTimeString := utils.TimeToString(now)
+
+	var pending []*Target
+	resigned, lapsed := 0, 0
+	for _, target := range targets {
+		switch target.Status {
+		case TargetStatusResigned:
+			resigned++
+		case TargetStatusLapsed:
+			lapsed++
+		}
+		if target.Status != TargetStatusPending {
+			continue
+		}
+		latest, ok := latestSignatures[target.SignatureType+":"+target.ReferenceID]
+		if ok && latest.majorVersion >= campaign.requiredMajorVersion(target.SignatureType) {
+			target.Status = TargetStatusResigned
+			target.ResignedSignatureID = latest.signatureID
+			target.DateResolved = currentTimeString
+			target.DateModified = currentTimeString
+			if err = s.repo.UpdateTarget(target); err != nil {
+				return 0, err
+			}
+			resigned++
+			summary.TargetsResigned++
+			continue
+		}
+		pending = append(pending, target)
+	}
+
+	if campaign.Deadline != "" && len(pending) > 0 {
+		deadline, parseErr := utils.ParseDateTime(campaign.Deadline)
+		if parseErr != nil {
+			return 0, parseErr
+		}
+		if !now.Before(deadline) {
+			var revoked int
+			pending, revoked, err = s.enforceCampaign(claGroupModel, campaign, pending, now, summary)
+			if err != nil {
+				return 0, err
+			}
+			lapsed += revoked
+			summary.TargetsAwaitingNotice += len(pending)
+		}
+	}
+
+	if len(pending) == 0 {
+		return 0, s.closeCampaign(claGroupModel, campaign, resigned, lapsed, summary)
+	}
+
+	notified := 0
+	for _, target := range pending {
+		if notified >= remainingNotifications {
+			break
+		}
+		if !s.notificationDue(target, now) {
+			continue
+		}
+		s.sendResignNotification(claGroupModel, campaign, target)
+		if target.NotificationCount == 0 {
+			target.FirstNotificationDate = currentTimeString
+		}
+		target.NotificationCount++
+		target.LastNotificationDate = currentTimeString
+		target.DateModified = currentTimeString
+		if err = s.repo.UpdateTarget(target); err != nil {
+			return notified, err
+		}
+		notified++
+		summary.TargetsNotified++
+	}
+	if notified > 0 {
+		s.logCampaignEvent(claGroupModel, events.ResignCampaignNotificationsSent, &events.ResignCampaignNotificationsSentEventData{
+			CampaignID:        campaign.CampaignID,
+			NotificationCount: notified,
+		})
+	}
+	return notified, nil
+}
+
+// enforceCampaign revokes the previous version signatures of the pending targets once the campaign deadline passed,
+// only the targets first notified at least a full grace period before are revoked. Returns the targets which remain
+// pending and the number of revoked signatures.
+func (s service) enforceCampaign(claGroupModel *v1Models.Project, campaign *Campaign, pending []*Target, now time.Time, summary *Summary) ([]*Target, int, error) {
+	currentTimeString := utils.TimeToString(now)
+	reason := signatures.RevocationReasonResignDeadlinePassed
+	var remaining []*Target
+	revoked := 0
+	for _, target := range pending {
+		if !noticePeriodOver(campaign, target, now) {
+			remaining = append(remaining, target)
+			continue
+		}
+		revocation := &v1Models.SignatureRevocation{
+			Reason:    &reason,
+			Comment:   "re-sign campaign " + campaign.CampaignID,
+			RevokedOn: currentTimeString,
+			RevokedBy: systemUser.LfUsername,
+		}
+		_, err := s.signaturesRepo.RevokeSignature(target.SignatureID, revocation)
+		if _, alreadyRevoked := err.(*signatures.ConflictError); err != nil && !alreadyRevoked {
+			return nil, revoked, err
+		}
+		target.Status = TargetStatusLapsed
+		target.DateResolved = currentTimeString
+		target.DateModified = currentTimeString
+		if err = s.repo.UpdateTarget(target); err != nil {
+			return nil, revoked, err
+		}
+		revoked++
+		summary.TargetsLapsed++
+		s.sendSignatureLapsedNotification(claGroupModel, campaign, target)
+	}
+	return remaining, revoked, nil
+}
+
+// closeCampaign closes the campaign which has no pending target left, the campaign is enforced if signatures of its
+// targets were revoked and completed otherwise
+func (s service) closeCampaign(claGroupModel *v1Models.Project, campaign *Campaign, resigned, lapsed int, summary *Summary) error {
+	if lapsed == 0 {
+		if _, err := s.repo.CloseCampaign(campaign.CampaignID, StatusCompleted, systemUser.LfUsername); err != nil {
+			return err
+		}
+		summary.CampaignsCompleted++
+		s.logCampaignEvent(claGroupModel, events.ResignCampaignCompleted, &events.ResignCampaignCompletedEventData{
+			CampaignID:    campaign.CampaignID,
+			ResignedCount: resigned,
+		})
+		return nil
+	}
+
+	if _, err := s.repo.CloseCampaign(campaign.CampaignID, StatusEnforced, systemUser.LfUsername); err != nil {
+		return err
+	}
+	summary.CampaignsEnforced++
+	s.logCampaignEvent(claGroupModel, events.ResignCampaignEnforced, &events.ResignCampaignEnforcedEventData{
+		CampaignID:   campaign.CampaignID,
+		RevokedCount: lapsed,
+		Deadline:     campaign.Deadline,
+	})
+	return nil
+}
+
+// noticePeriodOver returns true if the target was first notified at least the grace period of the campaign before,
+// the targets notified before the first notification date was recorded use their last notification date
+func noticePeriodOver(campaign *Campaign, target *Target, now time.Time) bool {
+	if target.NotificationCount == 0 {
+		return false
+	}
+	firstNotificationDate := target.FirstNotificationDate
+	if firstNotificationDate == "" {
+		firstNotificationDate = target.LastNotificationDate
+	}
+	firstNotification, err := utils.ParseDateTime(firstNotificationDate)
+	if err != nil {
+		log.Warnf("invalid first notification date: %s of re-sign campaign target: %s", firstNotificationDate, target.TargetID)
+		return false
+	}
+	return !now.Before(firstNotification.AddDate(0, 0, int(campaign.GracePeriodDays)))
+}
+
+// notificationDue returns true if the target was never notified or was last notified before the notification interval
+func (s service) notificationDue(target *Target, now time.Time) bool {
+	if target.NotificationCount >= maxNotifications {
+		return false
+	}
+	if target.LastNotificationDate == "" {
+		return true
+	}
+	lastNotification, err := utils.ParseDateTime(target.LastNotificationDate)
+	if err != nil {
+		log.Warnf("invalid last notification date: %s of re-sign campaign target: %s", target.LastNotificationDate, target.TargetID)
+		return true
+	}
+	return !now.Before(lastNotification.AddDate(0, 0, s.notificationIntervalDays))
+}
+
+// logCampaignEvent logs the re-sign campaign event of the scheduled job
+func (s service) logCampaignEvent(claGroupModel *v1Models.Project, eventType string, eventData events.EventData) {
+	s.eventsService.LogEvent(&events.LogEventArgs{
+		EventType:         eventType,
+		ProjectID:         claGroupModel.ProjectID,
+		ProjectModel:      claGroupModel,
+		UserModel:         systemUser,
+		ExternalProjectID: claGroupModel.ProjectExternalID,
+		EventData:         eventData,
+	})
+}
+
+// GetResignPolicy returns the re-sign policy of the CLA Group
+func (s service) GetResignPolicy(claGroupModel *v1Models.Project) *models.ResignPolicy {
+	return &models.ResignPolicy{
+		ClaGroupID:      claGroupModel.ProjectID,
+		ResignRequired:  claGroupModel.ProjectResignRequired,
+		GracePeriodDays: claGroupModel.ProjectResignGracePeriodDays,
+	}
+}
+
+// UpdateResignPolicy updates the re-sign policy of the CLA Group, the policy applies to the campaigns started after the
+// update
+func (s service) UpdateResignPolicy(authUser *auth.User, claGroupModel *v1Models.Project, policy *models.ResignPolicy) (*models.ResignPolicy, error) {
+	updatedModel, err := s.projectRepo.UpdateCLAGroupResignPolicy(claGroupModel.ProjectID, policy.ResignRequired, policy.GracePeriodDays)
+	if err != nil {
+		return nil, err
+	}
+
+	s.eventsService.LogEvent(&events.LogEventArgs{
+		EventType:         events.ResignPolicyUpdated,
+		ProjectID:         updatedModel.ProjectID,
+		ProjectModel:      updatedModel,
+		LfUsername:        authUser.UserName,
+		ExternalProjectID: updatedModel.ProjectExternalID,
+		EventData: &events.ResignPolicyUpdatedEventData{
+			ResignRequired:  updatedModel.ProjectResignRequired,
+			GracePeriodDays: updatedModel.ProjectResignGracePeriodDays,
+		},
+	})
+	return s.GetResignPolicy(updatedModel), nil
+}
+
+// ListCampaigns returns the re-sign campaigns of the CLA Group with their progress, the most recent first
+func (s service) ListCampaigns(claGroupID string) (*models.ResignCampaignList, error) {
+	campaigns, err := s.repo.GetCLAGroupCampaigns(claGroupID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(campaigns, func(i, j int) bool {
+		return campaigns[i].DateCreated > campaigns[j].DateCreated
+	})
+
+	result := &models.ResignCampaignList{List: []*models.ResignCampaign{}}
+	for _, campaign := range campaigns {
+		targets, err := s.repo.GetTargets(campaign.CampaignID)
+		if err != nil {
+			return nil, err
+		}
+		result.List = append(result.List, buildCampaignModel(campaign, targets, false))
+	}
+	return result, nil
+}
+
+// GetCampaign returns the re-sign campaign of the CLA Group with its progress and targets, ErrCampaignNotFound if the
+// campaign doesn't belong to the CLA Group
+func (s service) GetCampaign(claGroupID, campaignID string) (*models.ResignCampaign, error) {
+	campaign, err := s.repo.GetCampaign(campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.CLAGroupID != claGroupID {
+		return nil, ErrCampaignNotFound
+	}
+	targets, err := s.repo.GetTargets(campaignID)
+	if err != nil {
+		return nil, err
+	}
+	return buildCampaignModel(campaign, targets, true), nil
+}
+
+// CancelCampaign cancels the active re-sign campaign of the CLA Group, ErrCampaignNotActive if it was already closed
+func (s service) CancelCampaign(authUser *auth.User, claGroupModel *v1Models.Project, campaignID string) (*models.ResignCampaign, error) {
+	campaign, err := s.repo.GetCampaign(campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.CLAGroupID != claGroupModel.ProjectID {
+		return nil, ErrCampaignNotFound
+	}
+	campaign, err = s.repo.CloseCampaign(campaignID, StatusCancelled, authUser.UserName)
+	if err != nil {
+		return nil, err
+	}
+
+	s.eventsService.LogEvent(&events.LogEventArgs{
+		EventType:         events.ResignCampaignCancelled,
+		ProjectID:         claGroupModel.ProjectID,
+		ProjectModel:      claGroupModel,
+		LfUsername:        authUser.UserName,
+		ExternalProjectID: claGroupModel.ProjectExternalID,
+		EventData:         &events.ResignCampaignCancelledEventData{CampaignID: campaignID},
+	})
+
+	targets, err := s.repo.GetTargets(campaignID)
+	if err != nil {
+		return nil, err
+	}
+	return buildCampaignModel(campaign, targets, true), nil
+}
+
+// requiredMajorVersion returns the major version the targets of the signature type must sign
+func (campaign *Campaign) requiredMajorVersion(signatureType string) int {
+	if signatureType == SignatureTypeICLA {
+		return campaign.ICLAMajorVersion
+	}
+	return campaign.CCLAMajorVersion
+}
+
+// targetSignatureType returns the re-sign campaign signature type of the signature, empty for the employee
+// acknowledgements which follow the company CCLA
+func targetSignatureType(sig *v1Models.Signature) string {
+	switch {
+	case sig.SignatureType == "cla" && sig.SignatureReferenceType == "user" && sig.SignatureUserCompanyID == "":
+		return SignatureTypeICLA
+	case sig.SignatureType == "ccla" && sig.SignatureReferenceType == "company":
+		return SignatureTypeCCLA
+	}
+	return ""
+}
+
+// signatureMajorVersion returns the document major version of the signature
+func signatureMajorVersion(sig *v1Models.Signature) (int, bool) {
+	majorVersion, err := strconv.Atoi(sig.SignatureMajorVersion)
+	if err != nil {
+		log.Warnf("invalid document major version: %s of signature: %s", sig.SignatureMajorVersion, sig.SignatureID)
+		return 0, false
+	}
+	return majorVersion, true
+}
+
+// signatureReferenceName returns the name of the user or company which signed
+func signatureReferenceName(sig *v1Models.Signature) string {
+	if sig.SignatureReferenceType == "company" && sig.CompanyName != "" {
+		return sig.CompanyName
+	}
+	if sig.SignatureReferenceName != "" {
+		return sig.SignatureReferenceName
+	}
+	return sig.UserName
+}
+
+// latestSignature is the signature of the most recent document major version of a user or company
+type latestSignature struct {
+	signatureID  string
+	majorVersion int
+}
+
+// addLatestSignature records the ICLA or CCLA signature in the latest signatures keyed by signature type and reference
+// ID if it is of a more recent document major version
+func addLatestSignature(latest map[string]latestSignature, sig *v1Models.Signature) {
+	signatureType := targetSignatureType(sig)
+	majorVersion, ok := signatureMajorVersion(sig)
+	if signatureType == "" || !ok {
+		return
+	}
+	key := signatureType + ":" + sig.SignatureReferenceID.String()
+	if current, found := latest[key]; !found || majorVersion > current.majorVersion {
+		latest[key] = latestSignature{signatureID: sig.SignatureID.String(), majorVersion: majorVersion}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package scim
+
+import (
+	"strconv"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+)
+
+func toScimToken(token *Token) *models.ScimToken {
+	return &models.ScimToken{
+		TokenHint:   token.TokenHint,
+		CompanyID:   token.CompanyID,
+		ClaGroupID:  token.CLAGroupID,
+		CreatedBy:   token.CreatedBy,
+		DateCreated: token.DateCreated,
+	}
+}
+
+func toScimMeta(resource *Resource) *models.ScimMeta {
+	return &models.ScimMeta{
+		ResourceType: resource.ResourceType,
+		Created:      resource.DateCreated,
+		LastModified: resource.DateModified,
+		Version:      strconv.FormatInt(resource.Version, 10),
+	}
+}
+
+// toScimUser converts the user resource to the SCIM user model, including the groups the user is a member of
+func toScimUser(resource *Resource, groups []*Resource) *models.ScimUser {
+	active := resource.Active
+	user := &models.ScimUser{
+		Schemas:     []string{SchemaUser},
+		ID:          resource.ResourceID,
+		ExternalID:  resource.ExternalID,
+		UserName:    resource.UserName,
+		DisplayName: resource.DisplayName,
+		Active:      &active,
+		Meta:        toScimMeta(resource),
+	}
+	for i, email := range resource.Emails {
+		user.Emails = append(user.Emails, &models.ScimUserEmail{
+			Value:   email,
+			Type:    "work",
+			Primary: i == 0,
+		})
+	}
+	for _, group := range groups {
+		if containsFold(group.Members, resource.ResourceID) {
+			user.Groups = append(user.Groups, &models.ScimMember{
+				Value:   group.ResourceID,
+				Display: group.DisplayName,
+			})
+		}
+	}
+	return user
+}
+
+// toScimGroup converts the group resource to the SCIM group model, the members are displayed by user name
+func toScimGroup(resource *Resource, users []*Resource) *models.ScimGroup {
+	group := &models.ScimGroup{
+		Schemas:     []string{SchemaGroup},
+		ID:          resource.ResourceID,
+		ExternalID:  resource.ExternalID,
+		DisplayName: resource.DisplayName,
+		Meta:        toScimMeta(resource),
+	}
+	for _, member := range resource.Members {
+		display := ""
+		if user := findResource(users, member); user != nil {
+			display = user.UserName
+		}
+		group.Members = append(group.Members, &models.ScimMember{
+			Value:   member,
+			Display: display,
+		})
+	}
+	return group
+}
+
+// findResource returns the resource with the specified ID, nil if not found
+func findResource(resources []*Resource, resourceID string) *Resource {
+	for _, resource := range resources {
+		if resource.ResourceID == resourceID {
+			return resource
+		}
+	}
+	return nil
+}
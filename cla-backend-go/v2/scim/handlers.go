@@ -0,0 +1,368 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/scim"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	signatureService "github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// Configure setups handlers on api with service
+func Configure(api *operations.EasyclaAPI, service Service, companyService company.IService, projectRepo project.ProjectRepository) { //nolint
+	// loadScope returns the company and CLA Group of the request
+	loadScope := func(companySFID, claGroupID string) (Scope, error) {
+		companyModel, err := companyService.GetCompanyByExternalID(companySFID)
+		if err != nil || companyModel == nil {
+			return Scope{}, company.ErrCompanyDoesNotExist
+		}
+		claGroupModel, err := projectRepo.GetCLAGroupByID(claGroupID, project.DontLoadRepoDetails)
+		if err != nil {
+			return Scope{}, err
+		}
+		return Scope{CompanyModel: companyModel, CLAGroupModel: claGroupModel}, nil
+	}
+
+	// scimScope authenticates the SCIM request - unknown companies and CLA Groups are reported as an invalid token so
+	// the endpoint doesn't reveal which exist
+	scimScope := func(authorization, companySFID, claGroupID string) (Scope, *Token, error) {
+		scope, err := loadScope(companySFID, claGroupID)
+		if err != nil {
+			if err == company.ErrCompanyDoesNotExist || err == project.ErrProjectDoesNotExist {
+				return Scope{}, nil, newError(http.StatusUnauthorized, "", "invalid bearer token")
+			}
+			return Scope{}, nil, err
+		}
+		token, err := service.Authenticate(scope, authorization)
+		if err != nil {
+			return Scope{}, nil, err
+		}
+		return scope, token, nil
+	}
+
+	// tokenScope authorizes the SCIM token management request of the CLA Manager
+	tokenScope := func(authUser *auth.User, xUserName, xEmail *string, companySFID, claGroupID string) (Scope, *models.ErrorResponse) {
+		utils.SetAuthUserProperties(authUser, xUserName, xEmail)
+		if !utils.IsUserAuthorizedForOrganization(authUser, companySFID) {
+			return Scope{}, &models.ErrorResponse{
+				Code: "403",
+				Message: fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to manage the SCIM token with Organization scope of %s",
+					authUser.UserName, companySFID),
+			}
+		}
+		scope, err := loadScope(companySFID, claGroupID)
+		if err != nil {
+			if err == company.ErrCompanyDoesNotExist || err == project.ErrProjectDoesNotExist {
+				return Scope{}, &models.ErrorResponse{
+					Code:    "404",
+					Message: fmt.Sprintf("EasyCLA - 404 Not Found - company %s or CLA Group %s not found", companySFID, claGroupID),
+				}
+			}
+			return Scope{}, errorResponse(err)
+		}
+		return scope, nil
+	}
+
+	api.ScimGetScimTokenHandler = scim.GetScimTokenHandlerFunc(func(params scim.GetScimTokenParams, authUser *auth.User) middleware.Responder {
+		scope, errResponse := tokenScope(authUser, params.XUSERNAME, params.XEMAIL, params.CompanySFID, params.ClaGroupID)
+		if errResponse != nil {
+			switch errResponse.Code {
+			case "403":
+				return scim.NewGetScimTokenForbidden().WithPayload(errResponse)
+			case "404":
+				return scim.NewGetScimTokenNotFound().WithPayload(errResponse)
+			}
+			return scim.NewGetScimTokenInternalServerError().WithPayload(errResponse)
+		}
+
+		result, err := service.GetToken(authUser, scope)
+		if err != nil {
+			if _, ok := err.(*signatureService.ForbiddenError); ok {
+				return scim.NewGetScimTokenForbidden().WithPayload(errorResponse(err))
+			}
+			if _, ok := err.(*signatureService.BadRequestError); ok {
+				return scim.NewGetScimTokenBadRequest().WithPayload(errorResponse(err))
+			}
+			if err == ErrTokenNotFound {
+				return scim.NewGetScimTokenNotFound().WithPayload(errorResponse(err))
+			}
+			log.Warnf("unable to load the SCIM token for company ID: %s, CLA Group ID: %s, error: %+v", params.CompanySFID, params.ClaGroupID, err)
+			return scim.NewGetScimTokenInternalServerError().WithPayload(errorResponse(err))
+		}
+		return scim.NewGetScimTokenOK().WithPayload(result)
+	})
+
+	api.ScimCreateScimTokenHandler = scim.CreateScimTokenHandlerFunc(func(params scim.CreateScimTokenParams, authUser *auth.User) middleware.Responder {
+		scope, errResponse := tokenScope(authUser, params.XUSERNAME, params.XEMAIL, params.CompanySFID, params.ClaGroupID)
+		if errResponse != nil {
+			switch errResponse.Code {
+			case "403":
+				return scim.NewCreateScimTokenForbidden().WithPayload(errResponse)
+			case "404":
+				return scim.NewCreateScimTokenNotFound().WithPayload(errResponse)
+			}
+			return scim.NewCreateScimTokenInternalServerError().WithPayload(errResponse)
+		}
+
+		result, err := service.CreateToken(authUser, scope)
+		if err != nil {
+			if _, ok := err.(*signatureService.ForbiddenError); ok {
+				return scim.NewCreateScimTokenForbidden().WithPayload(errorResponse(err))
+			}
+			if _, ok := err.(*signatureService.BadRequestError); ok {
+				return scim.NewCreateScimTokenBadRequest().WithPayload(errorResponse(err))
+			}
+			log.Warnf("unable to create the SCIM token for company ID: %s, CLA Group ID: %s, error: %+v", params.CompanySFID, params.ClaGroupID, err)
+			return scim.NewCreateScimTokenInternalServerError().WithPayload(errorResponse(err))
+		}
+		return scim.NewCreateScimTokenOK().WithPayload(result)
+	})
+
+	api.ScimDeleteScimTokenHandler = scim.DeleteScimTokenHandlerFunc(func(params scim.DeleteScimTokenParams, authUser *auth.User) middleware.Responder {
+		scope, errResponse := tokenScope(authUser, params.XUSERNAME, params.XEMAIL, params.CompanySFID, params.ClaGroupID)
+		if errResponse != nil {
+			switch errResponse.Code {
+			case "403":
+				return scim.NewDeleteScimTokenForbidden().WithPayload(errResponse)
+			case "404":
+				return scim.NewDeleteScimTokenNotFound().WithPayload(errResponse)
+			}
+			return scim.NewDeleteScimTokenInternalServerError().WithPayload(errResponse)
+		}
+
+		err := service.DeleteToken(authUser, scope)
+		if err != nil {
+			if _, ok := err.(*signatureService.ForbiddenError); ok {
+				return scim.NewDeleteScimTokenForbidden().WithPayload(errorResponse(err))
+			}
+			if _, ok := err.(*signatureService.BadRequestError); ok {
+				return scim.NewDeleteScimTokenBadRequest().WithPayload(errorResponse(err))
+			}
+			if err == ErrTokenNotFound {
+				return scim.NewDeleteScimTokenNotFound().WithPayload(errorResponse(err))
+			}
+			log.Warnf("unable to revoke the SCIM token for company ID: %s, CLA Group ID: %s, error: %+v", params.CompanySFID, params.ClaGroupID, err)
+			return scim.NewDeleteScimTokenInternalServerError().WithPayload(errorResponse(err))
+		}
+		return scim.NewDeleteScimTokenNoContent()
+	})
+
+	// SCIM Users
+	api.ScimListScimUsersHandler = scim.ListScimUsersHandlerFunc(func(params scim.ListScimUsersParams) middleware.Responder {
+		scope, _, err := scimScope(params.Authorization, params.CompanySFID, params.ClaGroupID)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		result, err := service.ListUsers(scope, stringValue(params.Filter), int64Value(params.StartIndex, 1), int64Value(params.Count, DefaultPageSize))
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		return scimResponse(http.StatusOK, result)
+	})
+
+	api.ScimCreateScimUserHandler = scim.CreateScimUserHandlerFunc(func(params scim.CreateScimUserParams) middleware.Responder {
+		scope, token, err := scimScope(params.Authorization, params.CompanySFID, params.ClaGroupID)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		result, err := service.CreateUser(token, scope, params.Body)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		return scimResponse(http.StatusCreated, result)
+	})
+
+	api.ScimGetScimUserHandler = scim.GetScimUserHandlerFunc(func(params scim.GetScimUserParams) middleware.Responder {
+		scope, _, err := scimScope(params.Authorization, params.CompanySFID, params.ClaGroupID)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		result, err := service.GetUser(scope, params.ScimID)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		return scimResponse(http.StatusOK, result)
+	})
+
+	api.ScimReplaceScimUserHandler = scim.ReplaceScimUserHandlerFunc(func(params scim.ReplaceScimUserParams) middleware.Responder {
+		scope, token, err := scimScope(params.Authorization, params.CompanySFID, params.ClaGroupID)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		result, err := service.ReplaceUser(token, scope, params.ScimID, params.Body)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		return scimResponse(http.StatusOK, result)
+	})
+
+	api.ScimPatchScimUserHandler = scim.PatchScimUserHandlerFunc(func(params scim.PatchScimUserParams) middleware.Responder {
+		scope, token, err := scimScope(params.Authorization, params.CompanySFID, params.ClaGroupID)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		result, err := service.PatchUser(token, scope, params.ScimID, params.Body)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		return scimResponse(http.StatusOK, result)
+	})
+
+	api.ScimDeleteScimUserHandler = scim.DeleteScimUserHandlerFunc(func(params scim.DeleteScimUserParams) middleware.Responder {
+		scope, token, err := scimScope(params.Authorization, params.CompanySFID, params.ClaGroupID)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		if err := service.DeleteUser(token, scope, params.ScimID); err != nil {
+			return scimErrorResponse(err)
+		}
+		return scim.NewDeleteScimUserNoContent()
+	})
+
+	// SCIM Groups
+	api.ScimListScimGroupsHandler = scim.ListScimGroupsHandlerFunc(func(params scim.ListScimGroupsParams) middleware.Responder {
+		scope, _, err := scimScope(params.Authorization, params.CompanySFID, params.ClaGroupID)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		result, err := service.ListGroups(scope, stringValue(params.Filter), int64Value(params.StartIndex, 1), int64Value(params.Count, DefaultPageSize))
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		return scimResponse(http.StatusOK, result)
+	})
+
+	api.ScimCreateScimGroupHandler = scim.CreateScimGroupHandlerFunc(func(params scim.CreateScimGroupParams) middleware.Responder {
+		scope, _, err := scimScope(params.Authorization, params.CompanySFID, params.ClaGroupID)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		result, err := service.CreateGroup(scope, params.Body)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		return scimResponse(http.StatusCreated, result)
+	})
+
+	api.ScimGetScimGroupHandler = scim.GetScimGroupHandlerFunc(func(params scim.GetScimGroupParams) middleware.Responder {
+		scope, _, err := scimScope(params.Authorization, params.CompanySFID, params.ClaGroupID)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		result, err := service.GetGroup(scope, params.ScimID)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		return scimResponse(http.StatusOK, result)
+	})
+
+	api.ScimReplaceScimGroupHandler = scim.ReplaceScimGroupHandlerFunc(func(params scim.ReplaceScimGroupParams) middleware.Responder {
+		scope, _, err := scimScope(params.Authorization, params.CompanySFID, params.ClaGroupID)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		result, err := service.ReplaceGroup(scope, params.ScimID, params.Body)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		return scimResponse(http.StatusOK, result)
+	})
+
+	api.ScimPatchScimGroupHandler = scim.PatchScimGroupHandlerFunc(func(params scim.PatchScimGroupParams) middleware.Responder {
+		scope, _, err := scimScope(params.Authorization, params.CompanySFID, params.ClaGroupID)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		result, err := service.PatchGroup(scope, params.ScimID, params.Body)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		return scimResponse(http.StatusOK, result)
+	})
+
+	api.ScimDeleteScimGroupHandler = scim.DeleteScimGroupHandlerFunc(func(params scim.DeleteScimGroupParams) middleware.Responder {
+		scope, _, err := scimScope(params.Authorization, params.CompanySFID, params.ClaGroupID)
+		if err != nil {
+			return scimErrorResponse(err)
+		}
+		if err := service.DeleteGroup(scope, params.ScimID); err != nil {
+			return scimErrorResponse(err)
+		}
+		return scim.NewDeleteScimGroupNoContent()
+	})
+}
+
+// scimResponse writes the payload as a SCIM JSON document with the specified status
+func scimResponse(status int, payload interface{}) middleware.Responder {
+	return middleware.ResponderFunc(func(rw http.ResponseWriter, pr runtime.Producer) {
+		rw.Header().Set(runtime.HeaderContentType, "application/scim+json")
+		rw.WriteHeader(status)
+		if err := json.NewEncoder(rw).Encode(payload); err != nil {
+			log.Warnf("unable to write the SCIM response, error: %v", err)
+		}
+	})
+}
+
+// scimErrorResponse writes the error as a SCIM error document - errors which aren't SCIM errors are internal errors
+func scimErrorResponse(err error) middleware.Responder {
+	var scimErr *Error
+	if !errors.As(err, &scimErr) {
+		log.Warnf("unable to process the SCIM request, error: %+v", err)
+		scimErr = newError(http.StatusInternalServerError, "", "unable to process the SCIM request")
+	}
+	return scimResponse(scimErr.Status, &models.ScimError{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(scimErr.Status),
+		ScimType: scimErr.ScimType,
+		Detail:   scimErr.Detail,
+	})
+}
+
+// stringValue returns the value of the optional parameter
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// int64Value returns the value of the optional parameter, the default value if not specified
+func int64Value(value *int64, defaultValue int64) int64 {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+type codedResponse interface {
+	Code() string
+}
+
+func errorResponse(err error) *models.ErrorResponse {
+	code := ""
+	if e, ok := err.(codedResponse); ok {
+		code = e.Code()
+	}
+
+	e := models.ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+	}
+
+	return &e
+}
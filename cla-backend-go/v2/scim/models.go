@@ -0,0 +1,54 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package scim
+
+// SCIM resource types
+const (
+	ResourceTypeUser  = "User"
+	ResourceTypeGroup = "Group"
+)
+
+// SCIM schema URNs
+const (
+	SchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// Token is the SCIM bearer token of a company and CLA Group - only the SHA-256 digest of the token is stored
+type Token struct {
+	TokenKey       string `json:"token_key"`
+	CompanyID      string `json:"company_id"`
+	CLAGroupID     string `json:"cla_group_id"`
+	TokenDigest    string `json:"token_digest"`
+	TokenHint      string `json:"token_hint"`
+	CreatedBy      string `json:"created_by"`
+	CreatedByEmail string `json:"created_by_email"`
+	DateCreated    string `json:"date_created"`
+}
+
+// Resource is a SCIM user or group provisioned by the identity provider of a company for a CLA Group
+type Resource struct {
+	ResourceID   string   `json:"resource_id"`
+	ResourceType string   `json:"resource_type"`
+	ScopeKey     string   `json:"scope_key"`
+	CompanyID    string   `json:"company_id"`
+	CLAGroupID   string   `json:"cla_group_id"`
+	ExternalID   string   `json:"external_id,omitempty"`
+	UserName     string   `json:"user_name,omitempty"`
+	DisplayName  string   `json:"display_name,omitempty"`
+	Emails       []string `json:"emails,omitempty"`
+	Active       bool     `json:"active"`
+	Members      []string `json:"members,omitempty"`
+	DateCreated  string   `json:"date_created"`
+	DateModified string   `json:"date_modified"`
+	Version      int64    `json:"version"`
+}
+
+// scopeKey returns the key of the company and CLA Group the tokens and resources belong to
+func scopeKey(companyID, claGroupID string) string {
+	return companyID + ":" + claGroupID
+}
@@ -0,0 +1,341 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package scim
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// filterRegex matches the only filters supported - attribute eq "value"
+var filterRegex = regexp.MustCompile(`(?i)^\s*([a-z]+)\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// memberFilterPathRegex matches the member paths of the group patch operations, such as members[value eq "2819c223"]
+var memberFilterPathRegex = regexp.MustCompile(`(?i)^\s*members\s*\[\s*value\s+eq\s+"((?:[^"\\]|\\.)*)"\s*\]\s*$`)
+
+// parseFilter returns the function matching the resources of the filter - only the eq operator is supported, on the
+// userName and externalId attributes of the users and the displayName and externalId attributes of the groups
+func parseFilter(resourceType, filter string) (func(resource *Resource) bool, error) {
+	if strings.TrimSpace(filter) == "" {
+		return func(*Resource) bool { return true }, nil
+	}
+
+	matches := filterRegex.FindStringSubmatch(filter)
+	if matches == nil {
+		return nil, newError(http.StatusBadRequest, "invalidFilter", "unsupported filter: %s - only attribute eq \"value\" filters are supported", filter)
+	}
+	value, err := strconv.Unquote(`"` + matches[2] + `"`)
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "invalidFilter", "invalid filter value: %s", matches[2])
+	}
+
+	switch strings.ToLower(matches[1]) {
+	case "externalid":
+		return func(resource *Resource) bool { return resource.ExternalID == value }, nil
+	case "username":
+		if resourceType == ResourceTypeUser {
+			return func(resource *Resource) bool { return strings.EqualFold(resource.UserName, value) }, nil
+		}
+	case "displayname":
+		if resourceType == ResourceTypeGroup {
+			return func(resource *Resource) bool { return strings.EqualFold(resource.DisplayName, value) }, nil
+		}
+	}
+	return nil, newError(http.StatusBadRequest, "invalidFilter", "unsupported filter attribute: %s", matches[1])
+}
+
+// applyScimUser sets the attributes of the SCIM user on the resource - a user without email addresses is identified
+// by its user name, which must then be an email address
+func applyScimUser(resource *Resource, user *models.ScimUser) error {
+	if user == nil {
+		return newError(http.StatusBadRequest, "invalidSyntax", "the user is required")
+	}
+	resource.UserName = strings.TrimSpace(user.UserName)
+	resource.ExternalID = user.ExternalID
+	resource.DisplayName = user.DisplayName
+	resource.Active = user.Active == nil || *user.Active
+	resource.Emails = nil
+	for _, email := range user.Emails {
+		if email != nil {
+			resource.Emails = appendUniqueFold(resource.Emails, strings.TrimSpace(email.Value))
+		}
+	}
+	return validateUser(resource)
+}
+
+// validateUser ensures the user has a user name and valid email addresses
+func validateUser(resource *Resource) error {
+	if resource.UserName == "" {
+		return newError(http.StatusBadRequest, "invalidValue", "userName is required")
+	}
+	if len(resource.Emails) == 0 {
+		resource.Emails = []string{resource.UserName}
+	}
+	for _, email := range resource.Emails {
+		if !utils.ValidEmail(email) {
+			return newError(http.StatusBadRequest, "invalidValue", "invalid email address: %s", email)
+		}
+	}
+	return nil
+}
+
+// applyScimGroup sets the attributes of the SCIM group on the resource
+func applyScimGroup(resource *Resource, group *models.ScimGroup) {
+	if group == nil {
+		return
+	}
+	resource.DisplayName = strings.TrimSpace(group.DisplayName)
+	resource.ExternalID = group.ExternalID
+	resource.Members = nil
+	for _, member := range group.Members {
+		if member != nil {
+			resource.Members = appendUniqueFold(resource.Members, member.Value)
+		}
+	}
+}
+
+// patchOperations returns the validated operations of the patch request
+func patchOperations(patch *models.ScimPatchRequest) ([]*models.ScimPatchOperation, error) {
+	if patch == nil || len(patch.Operations) == 0 {
+		return nil, newError(http.StatusBadRequest, "invalidSyntax", "at least one patch operation is required")
+	}
+	for _, operation := range patch.Operations {
+		if operation == nil || operation.Op == nil {
+			return nil, newError(http.StatusBadRequest, "invalidSyntax", "the patch operation is required")
+		}
+		switch strings.ToLower(*operation.Op) {
+		case "add", "replace", "remove":
+		default:
+			return nil, newError(http.StatusBadRequest, "invalidSyntax", "unsupported patch operation: %s", *operation.Op)
+		}
+	}
+	return patch.Operations, nil
+}
+
+// patchUser applies the patch operations to the user - attributes which EasyCLA doesn't store, such as the name of
+// the user, are ignored so identity providers can push their full user profile
+func patchUser(resource *Resource, patch *models.ScimPatchRequest) error {
+	operations, err := patchOperations(patch)
+	if err != nil {
+		return err
+	}
+	for _, operation := range operations {
+		op := strings.ToLower(*operation.Op)
+		if operation.Path == "" {
+			values, ok := operation.Value.(map[string]interface{})
+			if !ok {
+				return newError(http.StatusBadRequest, "invalidValue", "an object value is required when no path is specified")
+			}
+			for attribute, value := range values {
+				if err := patchUserAttribute(resource, op, attribute, value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := patchUserAttribute(resource, op, operation.Path, operation.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func patchUserAttribute(resource *Resource, op, path string, value interface{}) error {
+	attribute := strings.ToLower(strings.TrimSpace(path))
+	switch {
+	case attribute == "active":
+		if op == "remove" {
+			return newError(http.StatusBadRequest, "mutability", "active can't be removed")
+		}
+		active, ok := boolValue(value)
+		if !ok {
+			return newError(http.StatusBadRequest, "invalidValue", "invalid active value: %v", value)
+		}
+		resource.Active = active
+	case attribute == "username":
+		if op == "remove" {
+			return newError(http.StatusBadRequest, "mutability", "userName can't be removed")
+		}
+		userName, ok := value.(string)
+		if !ok {
+			return newError(http.StatusBadRequest, "invalidValue", "invalid userName value: %v", value)
+		}
+		resource.UserName = strings.TrimSpace(userName)
+	case attribute == "displayname":
+		resource.DisplayName = stringValue(op, value)
+	case attribute == "externalid":
+		resource.ExternalID = stringValue(op, value)
+	case attribute == "emails":
+		emails := emailValues(value)
+		switch op {
+		case "add":
+			for _, email := range emails {
+				resource.Emails = appendUniqueFold(resource.Emails, email)
+			}
+		case "replace":
+			resource.Emails = emails
+		case "remove":
+			if value == nil {
+				resource.Emails = nil
+			}
+			for _, email := range emails {
+				resource.Emails = removeFold(resource.Emails, email)
+			}
+		}
+	case strings.HasPrefix(attribute, "emails["):
+		// A filtered email path such as emails[type eq "work"].value - EasyCLA doesn't keep the email types, the
+		// value replaces the primary email address
+		email := stringValue(op, value)
+		if email == "" {
+			return nil
+		}
+		if len(resource.Emails) == 0 {
+			resource.Emails = []string{email}
+			return nil
+		}
+		resource.Emails = append([]string{email}, removeFold(resource.Emails[1:], email)...)
+	}
+	return nil
+}
+
+// patchGroup applies the patch operations to the group
+func patchGroup(resource *Resource, patch *models.ScimPatchRequest) error {
+	operations, err := patchOperations(patch)
+	if err != nil {
+		return err
+	}
+	for _, operation := range operations {
+		op := strings.ToLower(*operation.Op)
+		if operation.Path == "" {
+			values, ok := operation.Value.(map[string]interface{})
+			if !ok {
+				return newError(http.StatusBadRequest, "invalidValue", "an object value is required when no path is specified")
+			}
+			for attribute, value := range values {
+				patchGroupAttribute(resource, op, attribute, value)
+			}
+			continue
+		}
+
+		if matches := memberFilterPathRegex.FindStringSubmatch(operation.Path); matches != nil {
+			if op != "remove" {
+				return newError(http.StatusBadRequest, "invalidPath", "unsupported path for the %s operation: %s", op, operation.Path)
+			}
+			member, unquoteErr := strconv.Unquote(`"` + matches[1] + `"`)
+			if unquoteErr != nil {
+				return newError(http.StatusBadRequest, "invalidPath", "invalid path: %s", operation.Path)
+			}
+			resource.Members = removeFold(resource.Members, member)
+			continue
+		}
+		patchGroupAttribute(resource, op, operation.Path, operation.Value)
+	}
+	return nil
+}
+
+func patchGroupAttribute(resource *Resource, op, path string, value interface{}) {
+	switch strings.ToLower(strings.TrimSpace(path)) {
+	case "displayname":
+		resource.DisplayName = stringValue(op, value)
+	case "externalid":
+		resource.ExternalID = stringValue(op, value)
+	case "members":
+		members := memberValues(value)
+		switch op {
+		case "add":
+			for _, member := range members {
+				resource.Members = appendUniqueFold(resource.Members, member)
+			}
+		case "replace":
+			resource.Members = members
+		case "remove":
+			if value == nil {
+				resource.Members = nil
+			}
+			for _, member := range members {
+				resource.Members = removeFold(resource.Members, member)
+			}
+		}
+	}
+}
+
+// boolValue returns the boolean of the value - some identity providers send the active flag as a string
+func boolValue(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		return b, err == nil
+	}
+	return false, false
+}
+
+// stringValue returns the string of the value, empty for remove operations
+func stringValue(op string, value interface{}) string {
+	if op == "remove" {
+		return ""
+	}
+	s, _ := value.(string)
+	return strings.TrimSpace(s)
+}
+
+// emailValues returns the email addresses of a list of SCIM email objects
+func emailValues(value interface{}) []string {
+	var emails []string
+	items, _ := value.([]interface{})
+	for _, item := range items {
+		if email, ok := item.(map[string]interface{}); ok {
+			if s, ok := email["value"].(string); ok && strings.TrimSpace(s) != "" {
+				emails = appendUniqueFold(emails, strings.TrimSpace(s))
+			}
+		}
+	}
+	return emails
+}
+
+// memberValues returns the member IDs of a list of SCIM member objects
+func memberValues(value interface{}) []string {
+	var members []string
+	items, _ := value.([]interface{})
+	for _, item := range items {
+		if member, ok := item.(map[string]interface{}); ok {
+			if s, ok := member["value"].(string); ok && s != "" {
+				members = appendUniqueFold(members, s)
+			}
+		}
+	}
+	return members
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUniqueFold(values []string, value string) []string {
+	if value == "" || containsFold(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+func removeFold(values []string, value string) []string {
+	var result []string
+	for _, v := range values {
+		if !strings.EqualFold(v, value) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,252 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package scim
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// errors
+var (
+	ErrTokenNotFound         = errors.New("scim token not found")
+	ErrResourceNotFound      = errors.New("scim resource not found")
+	ErrResourceAlreadyExists = errors.New("scim resource already exists")
+	ErrResourceModified      = errors.New("scim resource was modified by another request")
+)
+
+// index
+const (
+	ScopeKeyIndex = "scope-key-index"
+)
+
+// Repository defines the functions of the SCIM token and resource repository
+type Repository interface {
+	GetToken(companyID, claGroupID string) (*Token, error)
+	PutToken(token *Token) error
+	DeleteToken(companyID, claGroupID string) error
+
+	GetResources(companyID, claGroupID, resourceType string) ([]*Resource, error)
+	GetResource(resourceID string) (*Resource, error)
+	CreateResource(resource *Resource) error
+	UpdateResource(resource *Resource, previousVersion int64) error
+	DeleteResource(resourceID string) error
+}
+
+// NewRepository creates a new instance of the SCIM repository
+func NewRepository(awsSession *session.Session, stage string) Repository {
+	return &repo{
+		stage:              stage,
+		dynamoDBClient:     dynamodb.New(awsSession),
+		tokensTableName:    fmt.Sprintf("cla-%s-scim-tokens", stage),
+		resourcesTableName: fmt.Sprintf("cla-%s-scim-resources", stage),
+	}
+}
+
+type repo struct {
+	stage              string
+	dynamoDBClient     *dynamodb.DynamoDB
+	tokensTableName    string
+	resourcesTableName string
+}
+
+// GetToken returns the SCIM token of the company and CLA Group, ErrTokenNotFound if the company has no token
+func (repo *repo) GetToken(companyID, claGroupID string) (*Token, error) {
+	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"token_key": {S: aws.String(scopeKey(companyID, claGroupID))},
+		},
+		TableName: aws.String(repo.tokensTableName),
+	})
+	if err != nil {
+		log.Warnf("error retrieving the scim token for company ID: %s, CLA Group ID: %s, error: %v", companyID, claGroupID, err)
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrTokenNotFound
+	}
+
+	var token Token
+	err = dynamodbattribute.UnmarshalMap(result.Item, &token)
+	if err != nil {
+		log.Warnf("error unmarshalling the scim token for company ID: %s, CLA Group ID: %s, error: %v", companyID, claGroupID, err)
+		return nil, err
+	}
+	return &token, nil
+}
+
+// PutToken stores the SCIM token, replacing the previous token of the company and CLA Group
+func (repo *repo) PutToken(token *Token) error {
+	token.TokenKey = scopeKey(token.CompanyID, token.CLAGroupID)
+	av, err := dynamodbattribute.MarshalMap(token)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(repo.tokensTableName),
+	})
+	if err != nil {
+		log.Warnf("unable to store the scim token for company ID: %s, CLA Group ID: %s, error: %v", token.CompanyID, token.CLAGroupID, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteToken removes the SCIM token of the company and CLA Group
+func (repo *repo) DeleteToken(companyID, claGroupID string) error {
+	_, err := repo.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"token_key": {S: aws.String(scopeKey(companyID, claGroupID))},
+		},
+		TableName: aws.String(repo.tokensTableName),
+	})
+	if err != nil {
+		log.Warnf("unable to delete the scim token for company ID: %s, CLA Group ID: %s, error: %v", companyID, claGroupID, err)
+		return err
+	}
+	return nil
+}
+
+// GetResources returns the SCIM resources of the specified type provisioned for the company and CLA Group
+func (repo *repo) GetResources(companyID, claGroupID, resourceType string) ([]*Resource, error) {
+	condition := expression.Key("scope_key").Equal(expression.Value(scopeKey(companyID, claGroupID)))
+	filter := expression.Name("resource_type").Equal(expression.Value(resourceType))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).WithFilter(filter).Build()
+	if err != nil {
+		log.Warnf("error building expression for scim resource query, company ID: %s, CLA Group ID: %s, error: %v",
+			companyID, claGroupID, err)
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		TableName:                 aws.String(repo.resourcesTableName),
+		IndexName:                 aws.String(ScopeKeyIndex),
+	}
+
+	var resources []*Resource
+	for {
+		results, queryErr := repo.dynamoDBClient.Query(queryInput)
+		if queryErr != nil {
+			log.Warnf("error retrieving scim resources for company ID: %s, CLA Group ID: %s, error: %v", companyID, claGroupID, queryErr)
+			return nil, queryErr
+		}
+
+		var page []*Resource
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &page)
+		if err != nil {
+			log.Warnf("error unmarshalling scim resources for company ID: %s, CLA Group ID: %s, error: %v", companyID, claGroupID, err)
+			return nil, err
+		}
+		resources = append(resources, page...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return resources, nil
+}
+
+// GetResource returns the SCIM resource, ErrResourceNotFound if it doesn't exist
+func (repo *repo) GetResource(resourceID string) (*Resource, error) {
+	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"resource_id": {S: aws.String(resourceID)},
+		},
+		TableName: aws.String(repo.resourcesTableName),
+	})
+	if err != nil {
+		log.Warnf("error retrieving scim resource ID: %s, error: %v", resourceID, err)
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrResourceNotFound
+	}
+
+	var resource Resource
+	err = dynamodbattribute.UnmarshalMap(result.Item, &resource)
+	if err != nil {
+		log.Warnf("error unmarshalling scim resource ID: %s, error: %v", resourceID, err)
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// CreateResource stores the new SCIM resource
+func (repo *repo) CreateResource(resource *Resource) error {
+	av, err := dynamodbattribute.MarshalMap(resource)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(repo.resourcesTableName),
+		ConditionExpression: aws.String("attribute_not_exists(resource_id)"),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrResourceAlreadyExists
+		}
+		log.Warnf("unable to create scim resource ID: %s, error: %v", resource.ResourceID, err)
+		return err
+	}
+	return nil
+}
+
+// UpdateResource stores the SCIM resource if it is still at the previous version, ErrResourceModified otherwise
+func (repo *repo) UpdateResource(resource *Resource, previousVersion int64) error {
+	av, err := dynamodbattribute.MarshalMap(resource)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(repo.resourcesTableName),
+		ConditionExpression: aws.String("version = :version"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":version": {N: aws.String(strconv.FormatInt(previousVersion, 10))},
+		},
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrResourceModified
+		}
+		log.Warnf("unable to update scim resource ID: %s, error: %v", resource.ResourceID, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteResource removes the SCIM resource
+func (repo *repo) DeleteResource(resourceID string) error {
+	_, err := repo.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"resource_id": {S: aws.String(resourceID)},
+		},
+		TableName: aws.String(repo.resourcesTableName),
+	})
+	if err != nil {
+		log.Warnf("unable to delete scim resource ID: %s, error: %v", resourceID, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package scim
+
+import (
+	"fmt"
+
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+)
+
+// NewMemoryRepository creates a new SCIM repository backed by the in-memory store
+func NewMemoryRepository(store *storage.MemoryStore, stage string) Repository {
+	return &memoryRepo{
+		store:              store,
+		tokensTableName:    fmt.Sprintf("cla-%s-scim-tokens", stage),
+		resourcesTableName: fmt.Sprintf("cla-%s-scim-resources", stage),
+	}
+}
+
+type memoryRepo struct {
+	store              *storage.MemoryStore
+	tokensTableName    string
+	resourcesTableName string
+}
+
+func (repo *memoryRepo) GetToken(companyID, claGroupID string) (*Token, error) {
+	var token Token
+	found, err := repo.store.Get(repo.tokensTableName, scopeKey(companyID, claGroupID), &token)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrTokenNotFound
+	}
+	return &token, nil
+}
+
+func (repo *memoryRepo) PutToken(token *Token) error {
+	token.TokenKey = scopeKey(token.CompanyID, token.CLAGroupID)
+	return repo.store.Put(repo.tokensTableName, token.TokenKey, token)
+}
+
+func (repo *memoryRepo) DeleteToken(companyID, claGroupID string) error {
+	return repo.store.Delete(repo.tokensTableName, scopeKey(companyID, claGroupID))
+}
+
+func (repo *memoryRepo) GetResources(companyID, claGroupID, resourceType string) ([]*Resource, error) {
+	var resources []*Resource
+	if err := repo.store.Scan(repo.resourcesTableName, &resources); err != nil {
+		return nil, err
+	}
+	var result []*Resource
+	for _, resource := range resources {
+		if resource.ScopeKey == scopeKey(companyID, claGroupID) && resource.ResourceType == resourceType {
+			result = append(result, resource)
+		}
+	}
+	return result, nil
+}
+
+func (repo *memoryRepo) GetResource(resourceID string) (*Resource, error) {
+	var resource Resource
+	found, err := repo.store.Get(repo.resourcesTableName, resourceID, &resource)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrResourceNotFound
+	}
+	return &resource, nil
+}
+
+func (repo *memoryRepo) CreateResource(resource *Resource) error {
+	err := repo.store.Create(repo.resourcesTableName, resource.ResourceID, resource)
+	if err == storage.ErrItemAlreadyExists {
+		return ErrResourceAlreadyExists
+	}
+	return err
+}
+
+func (repo *memoryRepo) UpdateResource(resource *Resource, previousVersion int64) error {
+	var current Resource
+	err := repo.store.Update(repo.resourcesTableName, resource.ResourceID, &current, func() error {
+		if current.Version != previousVersion {
+			return ErrResourceModified
+		}
+		current = *resource
+		return nil
+	})
+	if err == storage.ErrItemNotFound {
+		return ErrResourceNotFound
+	}
+	return err
+}
+
+func (repo *memoryRepo) DeleteResource(resourceID string) error {
+	return repo.store.Delete(repo.resourcesTableName, resourceID)
+}
@@ -0,0 +1,709 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package scim
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list_revisions"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// tokenPrefix makes the SCIM tokens easy to recognize, for example by secret scanners
+const tokenPrefix = "easycla_scim_"
+
+// DefaultPageSize is the number of resources returned by the list requests when the identity provider doesn't
+// specify a count
+const DefaultPageSize = 100
+
+// Scope is the company and CLA Group a SCIM request applies to
+type Scope struct {
+	CompanyModel  *v1Models.Company
+	CLAGroupModel *v1Models.Project
+}
+
+// Service defines the functions of the SCIM provisioning service. The email addresses of the active SCIM users are
+// kept on the CCLA email approval list of the company - deactivating or deleting a user in the identity provider
+// removes them. Groups are stored so identity providers which push group memberships work, coverage follows the
+// active flag of the users.
+type Service interface {
+	CreateToken(authUser *auth.User, scope Scope) (*models.ScimToken, error)
+	GetToken(authUser *auth.User, scope Scope) (*models.ScimToken, error)
+	DeleteToken(authUser *auth.User, scope Scope) error
+	Authenticate(scope Scope, authorization string) (*Token, error)
+
+	ListUsers(scope Scope, filter string, startIndex, count int64) (*models.ScimUserList, error)
+	GetUser(scope Scope, userID string) (*models.ScimUser, error)
+	CreateUser(token *Token, scope Scope, user *models.ScimUser) (*models.ScimUser, error)
+	ReplaceUser(token *Token, scope Scope, userID string, user *models.ScimUser) (*models.ScimUser, error)
+	PatchUser(token *Token, scope Scope, userID string, patch *models.ScimPatchRequest) (*models.ScimUser, error)
+	DeleteUser(token *Token, scope Scope, userID string) error
+
+	ListGroups(scope Scope, filter string, startIndex, count int64) (*models.ScimGroupList, error)
+	GetGroup(scope Scope, groupID string) (*models.ScimGroup, error)
+	CreateGroup(scope Scope, group *models.ScimGroup) (*models.ScimGroup, error)
+	ReplaceGroup(scope Scope, groupID string, group *models.ScimGroup) (*models.ScimGroup, error)
+	PatchGroup(scope Scope, groupID string, patch *models.ScimPatchRequest) (*models.ScimGroup, error)
+	DeleteGroup(scope Scope, groupID string) error
+}
+
+type service struct {
+	repo             Repository
+	signaturesRepo   signatures.SignatureRepository
+	revisionsService approval_list_revisions.Service
+	usersService     users.Service
+	eventsService    events.Service
+}
+
+// NewService creates a new instance of the SCIM provisioning service
+func NewService(repo Repository, signaturesRepo signatures.SignatureRepository, revisionsService approval_list_revisions.Service, usersService users.Service, eventsService events.Service) Service {
+	return service{
+		repo:             repo,
+		signaturesRepo:   signaturesRepo,
+		revisionsService: revisionsService,
+		usersService:     usersService,
+		eventsService:    eventsService,
+	}
+}
+
+// Error is a SCIM error - the status is the HTTP status code of the response
+type Error struct {
+	Status   int
+	ScimType string
+	Detail   string
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+func newError(status int, scimType, format string, args ...interface{}) *Error {
+	return &Error{Status: status, ScimType: scimType, Detail: fmt.Sprintf(format, args...)}
+}
+
+// tokenDigest returns the hex encoded SHA-256 digest of the token
+func tokenDigest(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(digest[:])
+}
+
+// cclaManagerSignature returns the CCLA of the scope, a forbidden error if the user is not one of its CLA Managers
+func (s service) cclaManagerSignature(authUser *auth.User, scope Scope) (*v1Models.Signature, error) {
+	sig, err := s.signaturesRepo.GetCorporateSignature(scope.CLAGroupModel.ProjectID, scope.CompanyModel.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	if sig == nil {
+		return nil, signatures.NewBadRequestError(fmt.Sprintf("company %s has not signed a CCLA for the CLA Group %s",
+			scope.CompanyModel.CompanyName, scope.CLAGroupModel.ProjectName))
+	}
+	if !utils.CurrentUserInACL(authUser, sig.SignatureACL) {
+		return nil, signatures.NewForbiddenError(fmt.Sprintf("user %s is not a CLA Manager of company %s for the CLA Group %s",
+			authUser.UserName, scope.CompanyModel.CompanyName, scope.CLAGroupModel.ProjectName))
+	}
+	return sig, nil
+}
+
+// CreateToken creates the SCIM token of the company and CLA Group, replacing the previous token - the token value is
+// only returned by this call
+func (s service) CreateToken(authUser *auth.User, scope Scope) (*models.ScimToken, error) {
+	if _, err := s.cclaManagerSignature(authUser, scope); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	value := tokenPrefix + hex.EncodeToString(secret)
+
+	_, now := utils.CurrentTime()
+	token := &Token{
+		CompanyID:      scope.CompanyModel.CompanyID,
+		CLAGroupID:     scope.CLAGroupModel.ProjectID,
+		TokenDigest:    tokenDigest(value),
+		TokenHint:      value[len(value)-4:],
+		CreatedBy:      authUser.UserName,
+		CreatedByEmail: authUser.Email,
+		DateCreated:    now,
+	}
+	if err := s.repo.PutToken(token); err != nil {
+		return nil, err
+	}
+
+	s.eventsService.LogEvent(&events.LogEventArgs{
+		EventType:         events.SCIMTokenCreated,
+		ProjectID:         scope.CLAGroupModel.ProjectID,
+		ProjectModel:      scope.CLAGroupModel,
+		CompanyID:         scope.CompanyModel.CompanyID,
+		CompanyModel:      scope.CompanyModel,
+		LfUsername:        authUser.UserName,
+		ExternalProjectID: scope.CLAGroupModel.ProjectExternalID,
+		EventData:         &events.SCIMTokenCreatedEventData{TokenHint: token.TokenHint},
+	})
+
+	result := toScimToken(token)
+	result.Token = value
+	return result, nil
+}
+
+// GetToken returns the details of the SCIM token of the company and CLA Group, without the token value
+func (s service) GetToken(authUser *auth.User, scope Scope) (*models.ScimToken, error) {
+	if _, err := s.cclaManagerSignature(authUser, scope); err != nil {
+		return nil, err
+	}
+	token, err := s.repo.GetToken(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	return toScimToken(token), nil
+}
+
+// DeleteToken revokes the SCIM token of the company and CLA Group
+func (s service) DeleteToken(authUser *auth.User, scope Scope) error {
+	if _, err := s.cclaManagerSignature(authUser, scope); err != nil {
+		return err
+	}
+	token, err := s.repo.GetToken(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteToken(token.CompanyID, token.CLAGroupID); err != nil {
+		return err
+	}
+
+	s.eventsService.LogEvent(&events.LogEventArgs{
+		EventType:         events.SCIMTokenRevoked,
+		ProjectID:         scope.CLAGroupModel.ProjectID,
+		ProjectModel:      scope.CLAGroupModel,
+		CompanyID:         scope.CompanyModel.CompanyID,
+		CompanyModel:      scope.CompanyModel,
+		LfUsername:        authUser.UserName,
+		ExternalProjectID: scope.CLAGroupModel.ProjectExternalID,
+		EventData:         &events.SCIMTokenRevokedEventData{TokenHint: token.TokenHint},
+	})
+	return nil
+}
+
+// Authenticate returns the SCIM token of the company and CLA Group matching the bearer token of the authorization
+// header, an unauthorized error otherwise
+func (s service) Authenticate(scope Scope, authorization string) (*Token, error) {
+	fields := strings.Fields(authorization)
+	if len(fields) != 2 || !strings.EqualFold(fields[0], "Bearer") {
+		return nil, newError(http.StatusUnauthorized, "", "a bearer token is required")
+	}
+
+	token, err := s.repo.GetToken(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID)
+	if err != nil {
+		if err == ErrTokenNotFound {
+			return nil, newError(http.StatusUnauthorized, "", "invalid bearer token")
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(tokenDigest(fields[1])), []byte(token.TokenDigest)) != 1 {
+		return nil, newError(http.StatusUnauthorized, "", "invalid bearer token")
+	}
+	return token, nil
+}
+
+// getResource returns the resource of the scope, a not found error if it doesn't exist or belongs to another scope
+func (s service) getResource(scope Scope, resourceType, resourceID string) (*Resource, error) {
+	resource, err := s.repo.GetResource(resourceID)
+	if err != nil {
+		if err == ErrResourceNotFound {
+			return nil, newError(http.StatusNotFound, "", "%s %s not found", resourceType, resourceID)
+		}
+		return nil, err
+	}
+	if resource.ResourceType != resourceType || resource.ScopeKey != scopeKey(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID) {
+		return nil, newError(http.StatusNotFound, "", "%s %s not found", resourceType, resourceID)
+	}
+	return resource, nil
+}
+
+// listResources returns the resources of the scope matching the filter, ordered by creation date
+func (s service) listResources(scope Scope, resourceType, filter string) ([]*Resource, error) {
+	match, err := parseFilter(resourceType, filter)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := s.repo.GetResources(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	var result []*Resource
+	for _, resource := range resources {
+		if match(resource) {
+			result = append(result, resource)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].DateCreated != result[j].DateCreated {
+			return result[i].DateCreated < result[j].DateCreated
+		}
+		return result[i].ResourceID < result[j].ResourceID
+	})
+	return result, nil
+}
+
+// page returns the page of the resources starting at the 1-based start index
+func page(resources []*Resource, startIndex, count int64) ([]*Resource, int64) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 {
+		count = 0
+	}
+	start := startIndex - 1
+	if start > int64(len(resources)) {
+		start = int64(len(resources))
+	}
+	end := start + count
+	if end > int64(len(resources)) {
+		end = int64(len(resources))
+	}
+	return resources[start:end], startIndex
+}
+
+// ListUsers returns the page of the SCIM users of the scope matching the filter
+func (s service) ListUsers(scope Scope, filter string, startIndex, count int64) (*models.ScimUserList, error) {
+	resources, err := s.listResources(scope, ResourceTypeUser, filter)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.repo.GetResources(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID, ResourceTypeGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	pageResources, startIndex := page(resources, startIndex, count)
+	result := &models.ScimUserList{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: int64(len(resources)),
+		StartIndex:   startIndex,
+		ItemsPerPage: int64(len(pageResources)),
+		Resources:    []*models.ScimUser{},
+	}
+	for _, resource := range pageResources {
+		result.Resources = append(result.Resources, toScimUser(resource, groups))
+	}
+	return result, nil
+}
+
+// GetUser returns the SCIM user
+func (s service) GetUser(scope Scope, userID string) (*models.ScimUser, error) {
+	resource, err := s.getResource(scope, ResourceTypeUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.repo.GetResources(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID, ResourceTypeGroup)
+	if err != nil {
+		return nil, err
+	}
+	return toScimUser(resource, groups), nil
+}
+
+// CreateUser provisions the SCIM user - the email addresses of an active user are added to the approval list
+func (s service) CreateUser(token *Token, scope Scope, user *models.ScimUser) (*models.ScimUser, error) {
+	resource := &Resource{}
+	if err := applyScimUser(resource, user); err != nil {
+		return nil, err
+	}
+	if err := s.checkUserNameUnique(scope, resource); err != nil {
+		return nil, err
+	}
+
+	resourceID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	_, now := utils.CurrentTime()
+	resource.ResourceID = resourceID.String()
+	resource.ResourceType = ResourceTypeUser
+	resource.ScopeKey = scopeKey(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID)
+	resource.CompanyID = scope.CompanyModel.CompanyID
+	resource.CLAGroupID = scope.CLAGroupModel.ProjectID
+	resource.DateCreated = now
+	resource.DateModified = now
+	resource.Version = 1
+	if err := s.repo.CreateResource(resource); err != nil {
+		return nil, err
+	}
+
+	if err := s.syncApprovalList(token, scope, resource.UserName, resource.Emails); err != nil {
+		return nil, err
+	}
+	return toScimUser(resource, nil), nil
+}
+
+// ReplaceUser replaces the attributes of the SCIM user and updates the approval list accordingly
+func (s service) ReplaceUser(token *Token, scope Scope, userID string, user *models.ScimUser) (*models.ScimUser, error) {
+	current, err := s.getResource(scope, ResourceTypeUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *current
+	if err := applyScimUser(&updated, user); err != nil {
+		return nil, err
+	}
+	return s.updateUser(token, scope, current, &updated)
+}
+
+// PatchUser applies the SCIM PatchOp operations to the SCIM user and updates the approval list accordingly
+func (s service) PatchUser(token *Token, scope Scope, userID string, patch *models.ScimPatchRequest) (*models.ScimUser, error) {
+	current, err := s.getResource(scope, ResourceTypeUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *current
+	updated.Emails = append([]string(nil), current.Emails...)
+	if err := patchUser(&updated, patch); err != nil {
+		return nil, err
+	}
+	if err := validateUser(&updated); err != nil {
+		return nil, err
+	}
+	return s.updateUser(token, scope, current, &updated)
+}
+
+// updateUser stores the updated SCIM user and synchronizes the approval list with the previous and new email
+// addresses of the user
+func (s service) updateUser(token *Token, scope Scope, current, updated *Resource) (*models.ScimUser, error) {
+	if err := s.checkUserNameUnique(scope, updated); err != nil {
+		return nil, err
+	}
+	_, now := utils.CurrentTime()
+	updated.DateModified = now
+	updated.Version = current.Version + 1
+	if err := s.repo.UpdateResource(updated, current.Version); err != nil {
+		if err == ErrResourceModified {
+			return nil, newError(http.StatusConflict, "", "user %s was modified by another request", current.ResourceID)
+		}
+		return nil, err
+	}
+
+	if err := s.syncApprovalList(token, scope, updated.UserName, append(append([]string(nil), current.Emails...), updated.Emails...)); err != nil {
+		return nil, err
+	}
+	groups, err := s.repo.GetResources(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID, ResourceTypeGroup)
+	if err != nil {
+		return nil, err
+	}
+	return toScimUser(updated, groups), nil
+}
+
+// DeleteUser deprovisions the SCIM user - the email addresses of the user are removed from the approval list and the
+// user is removed from its groups
+func (s service) DeleteUser(token *Token, scope Scope, userID string) error {
+	current, err := s.getResource(scope, ResourceTypeUser, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteResource(current.ResourceID); err != nil {
+		return err
+	}
+
+	groups, err := s.repo.GetResources(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID, ResourceTypeGroup)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if !containsFold(group.Members, current.ResourceID) {
+			continue
+		}
+		updated := *group
+		updated.Members = removeFold(group.Members, current.ResourceID)
+		updated.Version = group.Version + 1
+		if updateErr := s.repo.UpdateResource(&updated, group.Version); updateErr != nil {
+			log.Warnf("unable to remove SCIM user %s from group %s, error: %+v", current.ResourceID, group.ResourceID, updateErr)
+		}
+	}
+
+	return s.syncApprovalList(token, scope, current.UserName, current.Emails)
+}
+
+// checkUserNameUnique returns a uniqueness error if another user of the scope has the same user name
+func (s service) checkUserNameUnique(scope Scope, resource *Resource) error {
+	users, err := s.repo.GetResources(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID, ResourceTypeUser)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		if user.ResourceID != resource.ResourceID && strings.EqualFold(user.UserName, resource.UserName) {
+			return newError(http.StatusConflict, "uniqueness", "user name %s is already in use", resource.UserName)
+		}
+	}
+	return nil
+}
+
+// ListGroups returns the page of the SCIM groups of the scope matching the filter
+func (s service) ListGroups(scope Scope, filter string, startIndex, count int64) (*models.ScimGroupList, error) {
+	resources, err := s.listResources(scope, ResourceTypeGroup, filter)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.repo.GetResources(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID, ResourceTypeUser)
+	if err != nil {
+		return nil, err
+	}
+
+	pageResources, startIndex := page(resources, startIndex, count)
+	result := &models.ScimGroupList{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: int64(len(resources)),
+		StartIndex:   startIndex,
+		ItemsPerPage: int64(len(pageResources)),
+		Resources:    []*models.ScimGroup{},
+	}
+	for _, resource := range pageResources {
+		result.Resources = append(result.Resources, toScimGroup(resource, users))
+	}
+	return result, nil
+}
+
+// GetGroup returns the SCIM group
+func (s service) GetGroup(scope Scope, groupID string) (*models.ScimGroup, error) {
+	resource, err := s.getResource(scope, ResourceTypeGroup, groupID)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.repo.GetResources(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID, ResourceTypeUser)
+	if err != nil {
+		return nil, err
+	}
+	return toScimGroup(resource, users), nil
+}
+
+// CreateGroup provisions the SCIM group - the members must be users of the scope
+func (s service) CreateGroup(scope Scope, group *models.ScimGroup) (*models.ScimGroup, error) {
+	resource := &Resource{}
+	applyScimGroup(resource, group)
+	users, err := s.validateGroup(scope, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	_, now := utils.CurrentTime()
+	resource.ResourceID = resourceID.String()
+	resource.ResourceType = ResourceTypeGroup
+	resource.ScopeKey = scopeKey(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID)
+	resource.CompanyID = scope.CompanyModel.CompanyID
+	resource.CLAGroupID = scope.CLAGroupModel.ProjectID
+	resource.Active = true
+	resource.DateCreated = now
+	resource.DateModified = now
+	resource.Version = 1
+	if err := s.repo.CreateResource(resource); err != nil {
+		return nil, err
+	}
+	return toScimGroup(resource, users), nil
+}
+
+// ReplaceGroup replaces the display name and members of the SCIM group
+func (s service) ReplaceGroup(scope Scope, groupID string, group *models.ScimGroup) (*models.ScimGroup, error) {
+	current, err := s.getResource(scope, ResourceTypeGroup, groupID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *current
+	applyScimGroup(&updated, group)
+	return s.updateGroup(scope, current, &updated)
+}
+
+// PatchGroup applies the SCIM PatchOp operations to the SCIM group
+func (s service) PatchGroup(scope Scope, groupID string, patch *models.ScimPatchRequest) (*models.ScimGroup, error) {
+	current, err := s.getResource(scope, ResourceTypeGroup, groupID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *current
+	updated.Members = append([]string(nil), current.Members...)
+	if err := patchGroup(&updated, patch); err != nil {
+		return nil, err
+	}
+	return s.updateGroup(scope, current, &updated)
+}
+
+func (s service) updateGroup(scope Scope, current, updated *Resource) (*models.ScimGroup, error) {
+	users, err := s.validateGroup(scope, updated)
+	if err != nil {
+		return nil, err
+	}
+	_, now := utils.CurrentTime()
+	updated.DateModified = now
+	updated.Version = current.Version + 1
+	if err := s.repo.UpdateResource(updated, current.Version); err != nil {
+		if err == ErrResourceModified {
+			return nil, newError(http.StatusConflict, "", "group %s was modified by another request", current.ResourceID)
+		}
+		return nil, err
+	}
+	return toScimGroup(updated, users), nil
+}
+
+// DeleteGroup deletes the SCIM group - the members of the group remain provisioned
+func (s service) DeleteGroup(scope Scope, groupID string) error {
+	current, err := s.getResource(scope, ResourceTypeGroup, groupID)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteResource(current.ResourceID)
+}
+
+// validateGroup ensures the group has a unique display name and its members are users of the scope, returns the
+// users of the scope
+func (s service) validateGroup(scope Scope, group *Resource) ([]*Resource, error) {
+	if strings.TrimSpace(group.DisplayName) == "" {
+		return nil, newError(http.StatusBadRequest, "invalidValue", "displayName is required")
+	}
+
+	groups, err := s.repo.GetResources(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID, ResourceTypeGroup)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range groups {
+		if other.ResourceID != group.ResourceID && strings.EqualFold(other.DisplayName, group.DisplayName) {
+			return nil, newError(http.StatusConflict, "uniqueness", "group display name %s is already in use", group.DisplayName)
+		}
+	}
+
+	users, err := s.repo.GetResources(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID, ResourceTypeUser)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range group.Members {
+		if findResource(users, member) == nil {
+			return nil, newError(http.StatusBadRequest, "invalidValue", "member %s is not a provisioned user", member)
+		}
+	}
+	return users, nil
+}
+
+// syncApprovalList adds the specified email addresses to the CCLA email approval list when they belong to an active
+// SCIM user of the scope and removes them otherwise
+func (s service) syncApprovalList(token *Token, scope Scope, scimUserName string, emails []string) error {
+	f := logrus.Fields{
+		"functionName": "syncApprovalList",
+		"companyID":    scope.CompanyModel.CompanyID,
+		"claGroupID":   scope.CLAGroupModel.ProjectID,
+		"scimUserName": scimUserName,
+	}
+
+	users, err := s.repo.GetResources(scope.CompanyModel.CompanyID, scope.CLAGroupModel.ProjectID, ResourceTypeUser)
+	if err != nil {
+		return err
+	}
+	approved := map[string]bool{}
+	for _, user := range users {
+		if !user.Active {
+			continue
+		}
+		for _, email := range user.Emails {
+			approved[strings.ToLower(email)] = true
+		}
+	}
+
+	sig, err := s.signaturesRepo.GetCorporateSignature(scope.CLAGroupModel.ProjectID, scope.CompanyModel.CompanyID)
+	if err != nil {
+		return err
+	}
+	if sig == nil {
+		return newError(http.StatusBadRequest, "", "company %s has no CCLA for the CLA Group %s",
+			scope.CompanyModel.CompanyName, scope.CLAGroupModel.ProjectName)
+	}
+	onApprovalList := map[string]bool{}
+	for _, email := range sig.EmailApprovalList {
+		onApprovalList[strings.ToLower(email)] = true
+	}
+
+	params := &v1Models.ApprovalList{}
+	seen := map[string]bool{}
+	for _, email := range emails {
+		key := strings.ToLower(email)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if approved[key] && !onApprovalList[key] {
+			params.AddEmailApprovalList = append(params.AddEmailApprovalList, email)
+		}
+		if !approved[key] && onApprovalList[key] {
+			params.RemoveEmailApprovalList = append(params.RemoveEmailApprovalList, email)
+		}
+	}
+	if len(params.AddEmailApprovalList) == 0 && len(params.RemoveEmailApprovalList) == 0 {
+		return nil
+	}
+
+	updatedSig, err := s.signaturesRepo.UpdateApprovalList(scope.CLAGroupModel.ProjectID, scope.CompanyModel.CompanyID, params)
+	if err != nil {
+		log.WithFields(f).Warnf("unable to update the approval list, error: %+v", err)
+		return err
+	}
+
+	// The changes are recorded on behalf of the CLA Manager who created the token
+	actor := s.tokenOwner(token)
+	if updatedSig != nil && updatedSig.RecordVersion > sig.RecordVersion {
+		if _, revisionErr := s.revisionsService.CreateRevision(sig, updatedSig, actor); revisionErr != nil {
+			log.WithFields(f).Warnf("unable to record the approval list revision for signature ID: %s, error: %+v", updatedSig.SignatureID, revisionErr)
+		}
+	}
+
+	logEvent := func(eventType string, eventData events.EventData) {
+		s.eventsService.LogEvent(&events.LogEventArgs{
+			EventType:         eventType,
+			ProjectID:         scope.CLAGroupModel.ProjectID,
+			ProjectModel:      scope.CLAGroupModel,
+			CompanyID:         scope.CompanyModel.CompanyID,
+			CompanyModel:      scope.CompanyModel,
+			LfUsername:        actor.LfUsername,
+			UserID:            actor.UserID,
+			UserModel:         actor,
+			ExternalProjectID: scope.CLAGroupModel.ProjectExternalID,
+			EventData:         eventData,
+		})
+	}
+	for _, email := range params.AddEmailApprovalList {
+		logEvent(events.ApprovalListSCIMEmailAdded, &events.ApprovalListSCIMEmailAddedEventData{Email: email, SCIMUserName: scimUserName})
+	}
+	for _, email := range params.RemoveEmailApprovalList {
+		logEvent(events.ApprovalListSCIMEmailRemoved, &events.ApprovalListSCIMEmailRemovedEventData{Email: email, SCIMUserName: scimUserName})
+	}
+	return nil
+}
+
+// tokenOwner returns the user model of the CLA Manager who created the token
+func (s service) tokenOwner(token *Token) *v1Models.User {
+	if s.usersService != nil {
+		userModel, err := s.usersService.GetUserByUserName(token.CreatedBy, true)
+		if err == nil && userModel != nil {
+			return userModel
+		}
+	}
+	return &v1Models.User{
+		LfUsername: token.CreatedBy,
+		LfEmail:    token.CreatedByEmail,
+		Username:   token.CreatedBy,
+	}
+}
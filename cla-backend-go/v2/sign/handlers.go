@@ -44,7 +44,7 @@ func Configure(api *operations.EasyclaAPI, service Service) {
 				if err == projects_cla_groups.ErrProjectNotAssociatedWithClaGroup {
 					return sign.NewRequestCorporateSignatureNotFound().WithPayload(errorResponse(err))
 				}
-				if err == ErrCCLANotEnabled || err == ErrTemplateNotConfigured {
+				if err == ErrCCLANotEnabled || err == ErrTemplateNotConfigured || err == ErrLocaleNotAvailable {
 					return sign.NewRequestCorporateSignatureBadRequest().WithPayload(errorResponse(err))
 				}
 				if _, ok := err.(*organizations.ListOrgUsrAdminScopesNotFound); ok {
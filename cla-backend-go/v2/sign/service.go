@@ -28,6 +28,7 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/company"
 	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	v1Template "github.com/communitybridge/easycla/cla-backend-go/template"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 )
 
@@ -40,6 +41,7 @@ const (
 var (
 	ErrCCLANotEnabled        = errors.New("corporate license agreement is not enabled with this project")
 	ErrTemplateNotConfigured = errors.New("cla template not configured for this project")
+	ErrLocaleNotAvailable    = errors.New("corporate license agreement is not available in the document locale")
 	ErrNotInOrg              error
 )
 
@@ -80,6 +82,7 @@ type requestCorporateSignatureInput struct {
 	AuthorityName  string `json:"authority_name,omitempty"`
 	AuthorityEmail string `json:"authority_email,omitempty"`
 	ReturnURL      string `json:"return_url,omitempty"`
+	DocumentLocale string `json:"document_locale,omitempty"`
 }
 
 type requestCorporateSignatureOutput struct {
@@ -170,9 +173,19 @@ func (s *service) RequestCorporateSignature(lfUsername string, authorizationHead
 	if !proj.ProjectCCLAEnabled {
 		return nil, ErrCCLANotEnabled
 	}
-	if len(proj.ProjectCorporateDocuments) == 0 {
+	document := v1Template.LatestProjectDocument(proj.ProjectCorporateDocuments)
+	if document == nil {
 		return nil, ErrTemplateNotConfigured
 	}
+	// The signatory may view a translation of the CCLA, the document in the legally binding locale is signed - the
+	// locale is recorded on the signature by the signing request
+	documentLocale := input.DocumentLocale
+	if documentLocale == "" {
+		documentLocale = v1Template.ProjectDocumentLocale(*document)
+	}
+	if _, err = v1Template.ProjectDocumentURL(*document, documentLocale); err != nil {
+		return nil, ErrLocaleNotAvailable
+	}
 	if input.SendAsEmail {
 		// this would be used only in case of cla-signatory
 		err = prepareUserForSigning(input.AuthorityEmail.String(), utils.StringValue(input.CompanySfid), utils.StringValue(input.ProjectSfid))
@@ -187,6 +200,7 @@ func (s *service) RequestCorporateSignature(lfUsername string, authorizationHead
 		AuthorityName:  input.AuthorityName,
 		AuthorityEmail: input.AuthorityEmail.String(),
 		ReturnURL:      input.ReturnURL.String(),
+		DocumentLocale: documentLocale,
 	})
 	if err != nil {
 		if input.AuthorityEmail.String() != "" {
@@ -219,6 +233,7 @@ func requestCorporateSignature(authToken string, apiURL string, input *requestCo
 		"AuthorityEmail": input.AuthorityEmail,
 		"ReturnURL":      input.ReturnURL,
 		"SendAsEmail":    input.SendAsEmail,
+		"DocumentLocale": input.DocumentLocale,
 	}
 	requestBody, err := json.Marshal(input)
 	if err != nil {
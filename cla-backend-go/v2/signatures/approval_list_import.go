@@ -0,0 +1,366 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/github"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	signatureService "github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/jinzhu/copier"
+	"github.com/sirupsen/logrus"
+)
+
+// approval list import CSV columns - only the type and value columns are required
+const (
+	importColumnType = iota
+	importColumnValue
+	importColumnExpirationDate
+	importColumnComment
+)
+
+// GitHubLookup confirms GitHub users and organizations exist before they are added to an approval list
+type GitHubLookup interface {
+	UserExists(githubUsername string) (bool, error)
+	OrganizationExists(githubOrg string) (bool, error)
+}
+
+// githubLookup is the GitHub API backed lookup
+type githubLookup struct{}
+
+// UserExists returns true if the GitHub user exists
+func (l githubLookup) UserExists(githubUsername string) (bool, error) {
+	user, err := github.GetUserDetails(githubUsername)
+	if err != nil {
+		if errors.Is(err, github.ErrGithubUserNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return user != nil, nil
+}
+
+// OrganizationExists returns true if the GitHub organization exists
+func (l githubLookup) OrganizationExists(githubOrg string) (bool, error) {
+	org, err := github.GetOrganization(githubOrg)
+	if err != nil {
+		if errors.Is(err, github.ErrGithubOrganizationNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return org != nil, nil
+}
+
+// cachedGitHubLookup remembers the GitHub users and organizations confirmed or denied during an import, so that each
+// user and organization is only looked up once - lookup failures aren't cached
+type cachedGitHubLookup struct {
+	lookup GitHubLookup
+	users  map[string]bool
+	orgs   map[string]bool
+}
+
+// newCachedGitHubLookup creates a new lookup cache in front of the lookup
+func newCachedGitHubLookup(lookup GitHubLookup) *cachedGitHubLookup {
+	return &cachedGitHubLookup{
+		lookup: lookup,
+		users:  map[string]bool{},
+		orgs:   map[string]bool{},
+	}
+}
+
+// UserExists returns true if the GitHub user exists
+func (l *cachedGitHubLookup) UserExists(githubUsername string) (bool, error) {
+	return cachedLookup(l.users, githubUsername, l.lookup.UserExists)
+}
+
+// OrganizationExists returns true if the GitHub organization exists
+func (l *cachedGitHubLookup) OrganizationExists(githubOrg string) (bool, error) {
+	return cachedLookup(l.orgs, githubOrg, l.lookup.OrganizationExists)
+}
+
+// cachedLookup returns the cached answer for the GitHub name, which is case insensitive, or looks it up
+func cachedLookup(cache map[string]bool, name string, lookup func(string) (bool, error)) (bool, error) {
+	key := strings.ToLower(name)
+	if exists, ok := cache[key]; ok {
+		return exists, nil
+	}
+	exists, err := lookup(name)
+	if err != nil {
+		return false, err
+	}
+	cache[key] = exists
+	return exists, nil
+}
+
+// ValidateApprovalListImport parses the approval list import CSV content and validates each row, returns the
+// per row report - the rows are reported in CSV order. The duplicate rows are reported before the GitHub entries are
+// looked up, and each GitHub user and organization is only looked up once.
+func ValidateApprovalListImport(csvContent string, lookup GitHubLookup) (*models.ApprovalListImportReport, error) {
+	lookup = newCachedGitHubLookup(lookup)
+	report := &models.ApprovalListImportReport{}
+	seen := map[string]int64{}
+	headerChecked := false
+	scanner := bufio.NewScanner(strings.NewReader(csvContent))
+	var lineNumber int64
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Each entry is a single line - parse the line on its own so the report can point at it
+		reader := csv.NewReader(strings.NewReader(line))
+		reader.TrimLeadingSpace = true
+		record, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse line %d of the approval list import CSV content - %v", lineNumber, err)
+		}
+
+		// Skip the optional header row
+		if !headerChecked {
+			headerChecked = true
+			if strings.EqualFold(strings.TrimSpace(record[importColumnType]), "type") {
+				continue
+			}
+		}
+
+		row := parseApprovalListImportRow(lineNumber, record)
+		validateApprovalListImportRow(row)
+
+		// The same entry listed twice is reported once as valid - the later rows are flagged
+		if row.Valid {
+			key := row.Type + ":" + strings.ToLower(row.Value)
+			if firstLine, ok := seen[key]; ok {
+				row.Valid = false
+				row.Message = fmt.Sprintf("duplicate of the entry on line %d", firstLine)
+			} else {
+				seen[key] = row.LineNumber
+			}
+		}
+		if row.Valid {
+			verifyApprovalListImportRow(row, lookup)
+		}
+
+		report.TotalRows++
+		if row.Valid {
+			report.ValidRows++
+		} else {
+			report.InvalidRows++
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read the approval list import CSV content - %v", err)
+	}
+
+	return report, nil
+}
+
+// parseApprovalListImportRow converts the CSV record into a report row
+func parseApprovalListImportRow(lineNumber int64, record []string) *models.ApprovalListImportRow {
+	column := func(idx int) string {
+		if idx < len(record) {
+			return strings.TrimSpace(record[idx])
+		}
+		return ""
+	}
+	return &models.ApprovalListImportRow{
+		LineNumber:     lineNumber,
+		Type:           column(importColumnType),
+		Value:          column(importColumnValue),
+		ExpirationDate: column(importColumnExpirationDate),
+		Comment:        column(importColumnComment),
+	}
+}
+
+// validateApprovalListImportRow checks the syntax of the row entry and its expiration date - sets the valid flag and
+// the message of the row
+func validateApprovalListImportRow(row *models.ApprovalListImportRow) {
+	invalid := func(msg string) {
+		row.Valid = false
+		row.Message = msg
+	}
+
+	if row.Value == "" {
+		invalid("value is empty")
+		return
+	}
+
+	switch row.Type {
+	case signatureService.ApprovalListTypeEmail:
+		if !utils.ValidEmail(row.Value) {
+			invalid(fmt.Sprintf("invalid email address: %s", row.Value))
+			return
+		}
+	case signatureService.ApprovalListTypeDomain:
+		if msg, valid := utils.ValidDomainPattern(row.Value); !valid {
+			invalid(fmt.Sprintf("invalid domain: %s - %s", row.Value, msg))
+			return
+		}
+		row.Value = utils.NormalizeDomainPattern(row.Value)
+	case signatureService.ApprovalListTypeGithubUsername:
+		if msg, valid := utils.ValidGitHubUsername(row.Value); !valid {
+			invalid(msg)
+			return
+		}
+	case signatureService.ApprovalListTypeGithubOrg:
+		if msg, valid := utils.ValidGitHubOrg(row.Value); !valid {
+			invalid(msg)
+			return
+		}
+	case signatureService.ApprovalListTypeGithubTeam:
+		if msg, valid := utils.ValidGitHubTeam(row.Value); !valid {
+			invalid(msg)
+			return
+		}
+	default:
+		invalid(fmt.Sprintf("invalid type: '%s' - expecting one of %s, %s, %s, %s or %s", row.Type,
+			signatureService.ApprovalListTypeEmail, signatureService.ApprovalListTypeDomain,
+			signatureService.ApprovalListTypeGithubUsername, signatureService.ApprovalListTypeGithubOrg,
+			signatureService.ApprovalListTypeGithubTeam))
+		return
+	}
+
+	if row.ExpirationDate != "" {
+		expirationTime, err := utils.ParseDateTime(row.ExpirationDate)
+		if err != nil {
+			invalid(fmt.Sprintf("invalid expiration date: %s", row.ExpirationDate))
+			return
+		}
+		if !expirationTime.After(time.Now()) {
+			invalid(fmt.Sprintf("expiration date %s must be in the future", row.ExpirationDate))
+			return
+		}
+		row.ExpirationDate = utils.TimeToString(expirationTime)
+	}
+
+	row.Valid = true
+}
+
+// verifyApprovalListImportRow checks that the GitHub user or organization of the row entry exists - the rows whose
+// entry doesn't exist or couldn't be confirmed are flagged as invalid
+func verifyApprovalListImportRow(row *models.ApprovalListImportRow, lookup GitHubLookup) {
+	invalid := func(msg string) {
+		row.Valid = false
+		row.Message = msg
+	}
+
+	switch row.Type {
+	case signatureService.ApprovalListTypeGithubUsername:
+		exists, err := lookup.UserExists(row.Value)
+		if err != nil {
+			invalid(fmt.Sprintf("unable to confirm GitHub user %s exists - %v", row.Value, err))
+			return
+		}
+		if !exists {
+			invalid(fmt.Sprintf("GitHub user %s does not exist", row.Value))
+		}
+	case signatureService.ApprovalListTypeGithubOrg:
+		exists, err := lookup.OrganizationExists(row.Value)
+		if err != nil {
+			invalid(fmt.Sprintf("unable to confirm GitHub organization %s exists - %v", row.Value, err))
+			return
+		}
+		if !exists {
+			invalid(fmt.Sprintf("GitHub organization %s does not exist", row.Value))
+		}
+	case signatureService.ApprovalListTypeGithubTeam:
+		githubOrg, _ := utils.SplitGitHubTeam(row.Value)
+		exists, err := lookup.OrganizationExists(githubOrg)
+		if err != nil {
+			invalid(fmt.Sprintf("unable to confirm GitHub organization %s exists - %v", githubOrg, err))
+			return
+		}
+		if !exists {
+			invalid(fmt.Sprintf("GitHub organization %s of team %s does not exist", githubOrg, row.Value))
+		}
+	}
+}
+
+// buildApprovalListImportUpdate converts the valid rows of the report into an approval list update
+func buildApprovalListImportUpdate(report *models.ApprovalListImportReport, recordVersion *int64) *v1Models.ApprovalList {
+	params := &v1Models.ApprovalList{
+		RecordVersion: recordVersion,
+	}
+	for _, row := range report.Rows {
+		if !row.Valid {
+			continue
+		}
+		switch row.Type {
+		case signatureService.ApprovalListTypeEmail:
+			params.AddEmailApprovalList = append(params.AddEmailApprovalList, row.Value)
+		case signatureService.ApprovalListTypeDomain:
+			params.AddDomainApprovalList = append(params.AddDomainApprovalList, row.Value)
+		case signatureService.ApprovalListTypeGithubUsername:
+			params.AddGithubUsernameApprovalList = append(params.AddGithubUsernameApprovalList, row.Value)
+		case signatureService.ApprovalListTypeGithubOrg:
+			params.AddGithubOrgApprovalList = append(params.AddGithubOrgApprovalList, row.Value)
+		case signatureService.ApprovalListTypeGithubTeam:
+			params.AddGithubTeamApprovalList = append(params.AddGithubTeamApprovalList, row.Value)
+		}
+		if row.ExpirationDate != "" {
+			params.ApprovalListExpirations = append(params.ApprovalListExpirations, &v1Models.ApprovalListExpiration{
+				ListType:       row.Type,
+				Value:          row.Value,
+				ExpirationDate: row.ExpirationDate,
+			})
+		}
+	}
+	return params
+}
+
+// ImportApprovalList validates the approval list import and, unless this is a dry run, adds the valid rows to the
+// approval list in a single update - the update is audited like any other approval list change
+func (s service) ImportApprovalList(authUser *auth.User, projectModel *v1Models.Project, companyModel *v1Models.Company, claGroupID string, params *models.ApprovalListImport, dryRun bool) (*models.ApprovalListImportReport, error) {
+	f := logrus.Fields{
+		"functionName": "ImportApprovalList",
+		"claGroupID":   claGroupID,
+		"companyID":    companyModel.CompanyID,
+		"dryRun":       dryRun,
+	}
+
+	var csvContent string
+	if params.Csv != nil {
+		csvContent = *params.Csv
+	}
+	report, err := ValidateApprovalListImport(csvContent, s.githubLookup)
+	if err != nil {
+		log.WithFields(f).Warnf("unable to validate the approval list import, error: %+v", err)
+		return nil, signatureService.NewBadRequestError(err.Error())
+	}
+	report.DryRun = dryRun
+	log.WithFields(f).Debugf("validated approval list import - rows: %d, valid: %d, invalid: %d",
+		report.TotalRows, report.ValidRows, report.InvalidRows)
+
+	if dryRun || report.ValidRows == 0 {
+		return report, nil
+	}
+
+	updatedSig, err := s.v1SignatureService.UpdateApprovalList(authUser, projectModel, companyModel, claGroupID, buildApprovalListImportUpdate(report, params.RecordVersion))
+	if err != nil {
+		log.WithFields(f).Warnf("unable to apply the approval list import, error: %+v", err)
+		return nil, err
+	}
+
+	v2Sig := models.Signature{}
+	if err = copier.Copy(&v2Sig, updatedSig); err != nil {
+		return nil, err
+	}
+	report.Applied = true
+	report.Signature = &v2Sig
+
+	return report, nil
+}
@@ -0,0 +1,184 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/juju/zip"
+)
+
+// ManifestFilename is the name of the manifest listing the signed documents of an archive
+const ManifestFilename = "manifest.json"
+
+// ArchiveFilter selects the signed documents of an on-demand archive - the CCLA of a single company, identified by
+// its internal company ID, and the documents signed within a date range. The filter fields are optional and the date
+// range bounds are inclusive.
+type ArchiveFilter struct {
+	CompanyID    string
+	SignedAfter  *time.Time
+	SignedBefore *time.Time
+}
+
+// includes returns true if the signature matches the filter
+func (f *ArchiveFilter) includes(sig *v1Models.Signature) bool {
+	if f == nil {
+		return true
+	}
+	if f.CompanyID != "" && sig.SignatureReferenceID.String() != f.CompanyID {
+		return false
+	}
+	options := &ExportOptions{SignedAfter: f.SignedAfter, SignedBefore: f.SignedBefore}
+	return options.includes(sig.SignatureID.String(), signatureDate(sig))
+}
+
+// ArchiveManifest lists the signed documents of an archive, the manifest is stored in the archive
+type ArchiveManifest struct {
+	ClaGroupID          string             `json:"cla_group_id"`
+	SignatureType       string             `json:"signature_type"`
+	GeneratedOn         string             `json:"generated_on"`
+	CompanyID           string             `json:"company_id,omitempty"`
+	SignedAfter         string             `json:"signed_after,omitempty"`
+	SignedBefore        string             `json:"signed_before,omitempty"`
+	Documents           []*ArchiveDocument `json:"documents"`
+	MissingSignatureIDs []string           `json:"missing_signature_ids,omitempty"`
+}
+
+// ArchiveDocument is a signed document of an archive, with the SHA-256 checksum of the archived file
+type ArchiveDocument struct {
+	SignatureID string `json:"signature_id"`
+	Filename    string `json:"filename"`
+	ReferenceID string `json:"reference_id,omitempty"`
+	SignerName  string `json:"signer_name,omitempty"`
+	SignerLFID  string `json:"signer_lf_id,omitempty"`
+	CompanyName string `json:"company_name,omitempty"`
+	DateSigned  string `json:"date_signed,omitempty"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+}
+
+// newArchiveDocument returns the archive document of the signed document file, sig is nil when the signature of the
+// file is not found
+func newArchiveDocument(filename string, sig *v1Models.Signature) *ArchiveDocument {
+	doc := &ArchiveDocument{
+		SignatureID: strings.TrimSuffix(filename, ".pdf"),
+		Filename:    filename,
+	}
+	if sig == nil {
+		return doc
+	}
+	doc.ReferenceID = sig.SignatureReferenceID.String()
+	doc.SignerName = sig.UserName
+	if sig.SignatoryName != "" {
+		doc.SignerName = sig.SignatoryName
+	}
+	doc.SignerLFID = sig.UserLFID
+	doc.CompanyName = sig.CompanyName
+	doc.DateSigned = signatureDate(sig)
+	return doc
+}
+
+// signatureDate returns the date the signature was signed on, the creation date of the older signatures
+func signatureDate(sig *v1Models.Signature) string {
+	if sig.SignedOn != "" {
+		return sig.SignedOn
+	}
+	return sig.SignatureCreated
+}
+
+// signedDocumentSignature returns true if the signature has a signed document of the signature type - the employee
+// acknowledgements are ICLA signatures without signed document
+func signedDocumentSignature(claType string, sig *v1Models.Signature) bool {
+	switch claType {
+	case ICLA:
+		return sig.SignatureType == ClaSignatureType && sig.SignatureUserCompanyID == ""
+	case CCLA:
+		return sig.SignatureType == CclaSignatureType
+	}
+	return false
+}
+
+// archiveWriter writes the signed documents of an archive, the manifest is written when the archive is closed
+type archiveWriter struct {
+	zw       *zip.Writer
+	manifest *ArchiveManifest
+	files    *utils.StringSet
+}
+
+func newArchiveWriter(w io.Writer, manifest *ArchiveManifest) *archiveWriter {
+	return &archiveWriter{
+		zw:       zip.NewWriter(w),
+		manifest: manifest,
+		files:    utils.NewStringSet(),
+	}
+}
+
+// add writes the content of the document to the archive and records the document in the manifest
+func (a *archiveWriter) add(doc *ArchiveDocument, content []byte) error {
+	return a.addFrom(doc, bytes.NewReader(content))
+}
+
+// addFrom streams the content of the document to the archive, computing its checksum, and records the document in
+// the manifest
+func (a *archiveWriter) addFrom(doc *ArchiveDocument, r io.Reader) error {
+	header := &zip.FileHeader{
+		Name:   doc.Filename,
+		Method: zip.Deflate,
+	}
+	header.SetMode(0644)
+	f, err := a.zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	digest := sha256.New()
+	size, err := io.Copy(io.MultiWriter(f, digest), r)
+	if err != nil {
+		return err
+	}
+	doc.Size = size
+	doc.SHA256 = hex.EncodeToString(digest.Sum(nil))
+	a.manifest.Documents = append(a.manifest.Documents, doc)
+	a.files.Add(doc.Filename)
+	return nil
+}
+
+// includes returns true if the file was added to the archive
+func (a *archiveWriter) includes(filename string) bool {
+	return a.files.Include(filename)
+}
+
+// Close writes the manifest, listing the documents by filename, and closes the archive
+func (a *archiveWriter) Close() error {
+	sort.Slice(a.manifest.Documents, func(i, j int) bool {
+		return a.manifest.Documents[i].Filename < a.manifest.Documents[j].Filename
+	})
+	if a.manifest.Documents == nil {
+		a.manifest.Documents = []*ArchiveDocument{}
+	}
+	_, a.manifest.GeneratedOn = utils.CurrentTime()
+	header := &zip.FileHeader{
+		Name:   ManifestFilename,
+		Method: zip.Deflate,
+	}
+	header.SetMode(0644)
+	f, err := a.zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(a.manifest); err != nil {
+		return err
+	}
+	return a.zw.Close()
+}
@@ -4,6 +4,7 @@
 package signatures
 
 import (
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list_revisions"
 	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
 	"github.com/go-openapi/strfmt"
@@ -44,3 +45,87 @@ func v2SignaturesReplaceCompanyID(src *v1Models.Signatures, internalID, external
 
 	return &dst, nil
 }
+
+func v2ApprovalListEntries(src approval_list_revisions.ApprovalListEntries) *models.ApprovalListEntries {
+	return &models.ApprovalListEntries{
+		EmailApprovalList:          src.EmailApprovalList,
+		DomainApprovalList:         src.DomainApprovalList,
+		GithubUsernameApprovalList: src.GithubUsernameApprovalList,
+		GithubOrgApprovalList:      src.GithubOrgApprovalList,
+		GithubTeamApprovalList:     src.GithubTeamApprovalList,
+	}
+}
+
+func v2ApprovalListRevisions(signatureID string, src []*approval_list_revisions.Revision) *models.ApprovalListRevisions {
+	dst := &models.ApprovalListRevisions{
+		SignatureID: signatureID,
+		Revisions:   make([]*models.ApprovalListRevision, 0, len(src)),
+	}
+	for _, revision := range src {
+		dst.Revisions = append(dst.Revisions, &models.ApprovalListRevision{
+			SignatureID:     revision.SignatureID,
+			RevisionNumber:  revision.RevisionNumber,
+			ClaGroupID:      revision.ClaGroupID,
+			CompanyID:       revision.CompanyID,
+			ActorUserID:     revision.ActorUserID,
+			ActorUsername:   revision.ActorUsername,
+			ActorLFUsername: revision.ActorLFUsername,
+			ActorEmail:      revision.ActorEmail,
+			DateCreated:     revision.DateCreated,
+			Added:           v2ApprovalListEntries(revision.Added),
+			Removed:         v2ApprovalListEntries(revision.Removed),
+			ApprovalList:    v2ApprovalListEntries(revision.ApprovalList),
+		})
+	}
+	return dst
+}
+
+func v2ApprovalListRevisionDiff(src *approval_list_revisions.RevisionDiff) *models.ApprovalListRevisionDiff {
+	return &models.ApprovalListRevisionDiff{
+		SignatureID:  src.SignatureID,
+		FromRevision: src.FromRevision,
+		ToRevision:   src.ToRevision,
+		Added:        v2ApprovalListEntries(src.Added),
+		Removed:      v2ApprovalListEntries(src.Removed),
+	}
+}
+
+func v2ApprovalListSnapshot(src *approval_list_revisions.ApprovalListSnapshot) *models.ApprovalListSnapshot {
+	return &models.ApprovalListSnapshot{
+		SignatureID:     src.SignatureID,
+		AsOf:            src.AsOf,
+		RevisionNumber:  src.RevisionNumber,
+		SignatureExists: src.SignatureExists,
+		ApprovalList:    v2ApprovalListEntries(src.ApprovalList),
+	}
+}
+
+func v2AutoApprovalRules(src *v1Models.Signature) *models.AutoApprovalRules {
+	recordVersion := src.RecordVersion
+	dst := &models.AutoApprovalRules{
+		SignatureID:   src.SignatureID.String(),
+		Rules:         make([]*models.AutoApprovalRule, 0, len(src.AutoApprovalRules)),
+		RecordVersion: &recordVersion,
+	}
+	for _, rule := range src.AutoApprovalRules {
+		dst.Rules = append(dst.Rules, &models.AutoApprovalRule{
+			RuleType: rule.RuleType,
+			Values:   rule.Values,
+		})
+	}
+	return dst
+}
+
+func v1AutoApprovalRules(src []*models.AutoApprovalRule) []*v1Models.AutoApprovalRule {
+	var dst []*v1Models.AutoApprovalRule
+	for _, rule := range src {
+		if rule == nil {
+			continue
+		}
+		dst = append(dst, &v1Models.AutoApprovalRule{
+			RuleType: rule.RuleType,
+			Values:   rule.Values,
+		})
+	}
+	return dst
+}
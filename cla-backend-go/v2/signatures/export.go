@@ -0,0 +1,263 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/export"
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// exportDateFormat is the format of the signature dates in the CSV exports
+const exportDateFormat = "Jan 2,2006"
+
+// the sheets of the exports, one per signature type
+const (
+	iclaExportSheet = "ICLA"
+	eclaExportSheet = "ECLA"
+)
+
+// errors
+var (
+	ErrInvalidExportColumn     = errors.New("invalid export column")
+	ErrNoCorporateContributors = errors.New("no corporate contributors found")
+)
+
+// ExportOptions selects the format, the columns, in order, and the signature date range of an export. CSV is used
+// when no format is selected and the default columns when no column is selected, the date range bounds are optional
+// and inclusive.
+type ExportOptions struct {
+	Format       string
+	Columns      []string
+	SignedAfter  *time.Time
+	SignedBefore *time.Time
+}
+
+// exportDateLayout is the layout of the date only bounds of the export date range
+const exportDateLayout = "2006-01-02"
+
+// SetDateRange sets the signature date range of the export from RFC3339 formatted date/times or from dates, the empty
+// bounds are not set. A signedAfter date starts at the beginning of the day and a signedBefore date includes the
+// whole day.
+func (o *ExportOptions) SetDateRange(signedAfter, signedBefore string) error {
+	if signedAfter != "" {
+		t, err := parseExportDate(signedAfter, false)
+		if err != nil {
+			return fmt.Errorf("invalid signedAfter value: %s", signedAfter)
+		}
+		o.SignedAfter = &t
+	}
+	if signedBefore != "" {
+		t, err := parseExportDate(signedBefore, true)
+		if err != nil {
+			return fmt.Errorf("invalid signedBefore value: %s", signedBefore)
+		}
+		o.SignedBefore = &t
+	}
+	return nil
+}
+
+// parseExportDate parses the date range bound, a date is the beginning of the day (UTC) or, with endOfDay, the last
+// instant of the day
+func parseExportDate(value string, endOfDay bool) (time.Time, error) {
+	if t, err := utils.ParseDateTime(value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(exportDateLayout, strings.TrimSpace(value))
+	if err != nil {
+		return t, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
+
+// format returns the export format of the options
+func (o *ExportOptions) format() string {
+	if o == nil || o.Format == "" {
+		return export.FormatCSV
+	}
+	return o.Format
+}
+
+// includes returns true if the signature date is within the date range of the export, signatures with an invalid
+// date are only included when no date range is set
+func (o *ExportOptions) includes(signatureID, signedOn string) bool {
+	if o == nil || (o.SignedAfter == nil && o.SignedBefore == nil) {
+		return true
+	}
+	t, err := utils.ParseDateTime(signedOn)
+	if err != nil {
+		log.WithFields(logrus.Fields{"signature_id": signatureID, "signature_created": signedOn}).
+			Warn("invalid time format present for signature - excluded from the date range export")
+		return false
+	}
+	if o.SignedAfter != nil && t.Before(*o.SignedAfter) {
+		return false
+	}
+	if o.SignedBefore != nil && t.After(*o.SignedBefore) {
+		return false
+	}
+	return true
+}
+
+// exportColumn is a column of an export, value returns the typed column value of the exported record
+type exportColumn struct {
+	header string
+	value  func(record interface{}) interface{}
+}
+
+// iclaExportColumns are the columns of the ICLA signatures export
+var iclaExportColumns = map[string]exportColumn{
+	"signatureID": {"Signature ID", func(r interface{}) interface{} { return r.(*v1Models.IclaSignature).SignatureID }},
+	"githubID":    {"Github ID", func(r interface{}) interface{} { return r.(*v1Models.IclaSignature).GithubUsername }},
+	"lfID":        {"LF_ID", func(r interface{}) interface{} { return r.(*v1Models.IclaSignature).LfUsername }},
+	"name":        {"Name", func(r interface{}) interface{} { return r.(*v1Models.IclaSignature).UserName }},
+	"email":       {"Email", func(r interface{}) interface{} { return r.(*v1Models.IclaSignature).UserEmail }},
+	"dateSigned": {"Date Signed", func(r interface{}) interface{} {
+		sig := r.(*v1Models.IclaSignature)
+		return exportDate(sig.SignatureID, sig.SignedOn)
+	}},
+}
+
+// corporateContributorExportColumns are the columns of the corporate contributors export
+var corporateContributorExportColumns = map[string]exportColumn{
+	"githubID":         {"Github ID", func(r interface{}) interface{} { return r.(*v1Models.CorporateContributor).GithubID }},
+	"lfID":             {"LF_ID", func(r interface{}) interface{} { return r.(*v1Models.CorporateContributor).LinuxFoundationID }},
+	"name":             {"Name", func(r interface{}) interface{} { return r.(*v1Models.CorporateContributor).Name }},
+	"email":            {"Email", func(r interface{}) interface{} { return r.(*v1Models.CorporateContributor).Email }},
+	"signatureVersion": {"Signature Version", func(r interface{}) interface{} { return r.(*v1Models.CorporateContributor).SignatureVersion }},
+	"dateSigned": {"Date Signed", func(r interface{}) interface{} {
+		contributor := r.(*v1Models.CorporateContributor)
+		return exportDate(contributor.LinuxFoundationID, contributor.Timestamp)
+	}},
+}
+
+// defaultExportColumns are the columns of the exports when no column is selected
+var defaultExportColumns = []string{"githubID", "lfID", "name", "email", "dateSigned"}
+
+// exportDate returns the signature date of the exports, the zero time - an empty cell - if the date is invalid
+func exportDate(signatureID, signedOn string) time.Time {
+	t, err := utils.ParseDateTime(signedOn)
+	if err != nil {
+		log.WithFields(logrus.Fields{"signature_id": signatureID, "signature_created": signedOn}).
+			Error("invalid time format present for signatures")
+		return time.Time{}
+	}
+	return t
+}
+
+// signatureExportWriter writes the records of an export sheet with the selected columns, the sheet is started along
+// with the first record so that nothing is written until there is a record to export
+type signatureExportWriter struct {
+	exporter     export.Exporter
+	sheet        string
+	columns      []exportColumn
+	sheetColumns []export.Column
+	rows         int
+}
+
+// newSignatureExportWriter returns an export writer of the sheet for the selected columns, ErrInvalidExportColumn
+// is returned if a selected column is not one of the available columns
+func newSignatureExportWriter(w io.Writer, sheet string, available map[string]exportColumn, options *ExportOptions) (*signatureExportWriter, error) {
+	names := defaultExportColumns
+	if options != nil && len(options.Columns) > 0 {
+		names = options.Columns
+	}
+	e := &signatureExportWriter{sheet: sheet}
+	for _, name := range names {
+		column, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidExportColumn, name)
+		}
+		e.columns = append(e.columns, column)
+		e.sheetColumns = append(e.sheetColumns, export.Column{Name: name, Header: column.header, DateLayout: exportDateFormat})
+	}
+	exporter, err := export.NewExporter(options.format(), w)
+	if err != nil {
+		return nil, err
+	}
+	e.exporter = exporter
+	return e, nil
+}
+
+// write writes the row of the record, the sheet is started with the first record
+func (e *signatureExportWriter) write(record interface{}) error {
+	if e.rows == 0 {
+		if err := e.exporter.BeginSheet(e.sheet, e.sheetColumns); err != nil {
+			return err
+		}
+	}
+	values := make([]interface{}, len(e.columns))
+	for i, column := range e.columns {
+		values[i] = column.value(record)
+	}
+	e.rows++
+	return e.exporter.WriteRow(values)
+}
+
+// close starts the sheet if no record was written and completes the export
+func (e *signatureExportWriter) close() error {
+	if e.rows == 0 {
+		if err := e.exporter.BeginSheet(e.sheet, e.sheetColumns); err != nil {
+			return err
+		}
+	}
+	return e.exporter.Close()
+}
+
+// ExportProjectIclaSignatures streams the ICLA signatures of the CLA Group to w in the format of the options, the
+// signatures are loaded and written one page at a time
+func (s service) ExportProjectIclaSignatures(w io.Writer, claGroupID string, options *ExportOptions) error {
+	writer, err := newSignatureExportWriter(w, iclaExportSheet, iclaExportColumns, options)
+	if err != nil {
+		return err
+	}
+	err = s.v1SignatureService.ForEachClaGroupICLASignature(claGroupID, func(sig *v1Models.IclaSignature) error {
+		if !options.includes(sig.SignatureID, sig.SignedOn) {
+			return nil
+		}
+		return writer.write(sig)
+	})
+	if err != nil {
+		return err
+	}
+	return writer.close()
+}
+
+// ExportClaGroupCorporateContributors streams the corporate contributors of the company for the CLA Group to w in
+// the format of the options, the contributors are loaded and written one page at a time. ErrNoCorporateContributors
+// is returned, before anything is written, if the company has no corporate contributor in the date range.
+func (s service) ExportClaGroupCorporateContributors(w io.Writer, claGroupID string, companySFID string, options *ExportOptions) error {
+	comp, err := s.v1CompanyService.GetCompanyByExternalID(companySFID)
+	if err != nil {
+		return err
+	}
+	writer, err := newSignatureExportWriter(w, eclaExportSheet, corporateContributorExportColumns, options)
+	if err != nil {
+		return err
+	}
+	err = s.v1SignatureService.ForEachClaGroupCorporateContributor(claGroupID, &comp.CompanyID, func(contributor *v1Models.CorporateContributor) error {
+		if !options.includes(contributor.LinuxFoundationID, contributor.Timestamp) {
+			return nil
+		}
+		return writer.write(contributor)
+	})
+	if err != nil {
+		return err
+	}
+	if writer.rows == 0 {
+		return ErrNoCorporateContributors
+	}
+	return writer.close()
+}
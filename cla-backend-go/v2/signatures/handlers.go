@@ -6,10 +6,13 @@ package signatures
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list_revisions"
 	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/document_integrity"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/organization-service/client/organizations"
 
 	"github.com/go-openapi/runtime"
@@ -24,6 +27,7 @@ import (
 	"github.com/LF-Engineering/lfx-kit/auth"
 
 	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/export"
 	v1Signatures "github.com/communitybridge/easycla/cla-backend-go/gen/restapi/operations/signatures"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/signatures"
@@ -31,12 +35,12 @@ import (
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 	signatureService "github.com/communitybridge/easycla/cla-backend-go/signatures"
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/gorilla/sessions"
 	"github.com/jinzhu/copier"
-	"github.com/savaki/dynastore"
 )
 
 // Configure setups handlers on api with service
-func Configure(api *operations.EasyclaAPI, projectService project.Service, projectRepo project.ProjectRepository, companyService company.IService, v1SignatureService signatureService.SignatureService, sessionStore *dynastore.Store, eventsService events.Service, v2service Service, projectClaGroupsRepo projects_cla_groups.Repository) { //nolint
+func Configure(api *operations.EasyclaAPI, projectService project.Service, projectRepo project.ProjectRepository, companyService company.IService, v1SignatureService signatureService.SignatureService, sessionStore sessions.Store, eventsService events.Service, v2service Service, projectClaGroupsRepo projects_cla_groups.Repository, revisionsService approval_list_revisions.Service, documentIntegrityService document_integrity.Service) { //nolint
 
 	// Get Signature
 	api.SignaturesGetSignatureHandler = signatures.GetSignatureHandlerFunc(func(params signatures.GetSignatureParams, authUser *auth.User) middleware.Responder {
@@ -115,10 +119,23 @@ func Configure(api *operations.EasyclaAPI, projectService project.Service, proje
 		// Invoke the update v1SignatureService function
 		updatedSig, updateErr := v1SignatureService.UpdateApprovalList(authUser, projectModel, companyModel, params.ClaGroupID, &v1ApprovalList)
 		if updateErr != nil || updatedSig == nil {
-			if err, ok := err.(*signatureService.ForbiddenError); ok {
+			if err, ok := updateErr.(*signatureService.ForbiddenError); ok {
 				return signatures.NewUpdateApprovalListForbidden().WithPayload(errorResponse(err))
 			}
 
+			// Another request modified the approval list in the meantime - return the current state so the caller can
+			// re-apply the changes
+			if conflictErr, ok := updateErr.(*signatureService.ConflictError); ok {
+				log.Warnf("conflict updating signature approval list using CLA Group ID: %s, error: %v", params.ClaGroupID, conflictErr)
+				currentSig := models.Signature{}
+				if conflictErr.Current != nil {
+					if err = copier.Copy(&currentSig, conflictErr.Current); err != nil {
+						return signatures.NewUpdateApprovalListInternalServerError().WithPayload(errorResponse(err))
+					}
+				}
+				return signatures.NewUpdateApprovalListConflict().WithPayload(&currentSig)
+			}
+
 			log.Warnf("unable to update signature approval list using CLA Group ID: %s", params.ClaGroupID)
 			return signatures.NewUpdateApprovalListBadRequest().WithPayload(errorResponse(updateErr))
 		}
@@ -133,6 +150,273 @@ func Configure(api *operations.EasyclaAPI, projectService project.Service, proje
 		return signatures.NewUpdateApprovalListOK().WithPayload(&v2Sig)
 	})
 
+	// Import Approval List entries from a CSV document
+	api.SignaturesImportApprovalListHandler = signatures.ImportApprovalListHandlerFunc(func(params signatures.ImportApprovalListParams, authUser *auth.User) middleware.Responder {
+		if params.XEMAIL == nil || params.XUSERNAME == nil || params.XACL == "" {
+			msg := fmt.Sprintf("EasyCLA - 403 Forbidden - unknown user is not authorized to import project company signature approval list for project ID: %s, company ID: %s",
+				params.ProjectSFID, params.CompanySFID)
+			log.Warn(msg)
+			return signatures.NewImportApprovalListForbidden().WithPayload(errorResponse(errors.New(msg)))
+		}
+
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+
+		// Must be in the Project|Organization Scope to see this
+		if !utils.IsUserAuthorizedForProjectOrganization(authUser, params.ProjectSFID, params.CompanySFID) {
+			msg := fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to import Project Company Approval List with Project|Organization scope of %s | %s",
+				authUser.UserName, params.ProjectSFID, params.CompanySFID)
+			log.Warn(msg)
+			return signatures.NewImportApprovalListForbidden().WithPayload(&models.ErrorResponse{
+				Code:    "403",
+				Message: msg,
+			})
+		}
+
+		if params.Body == nil || params.Body.Csv == nil || strings.TrimSpace(*params.Body.Csv) == "" {
+			return signatures.NewImportApprovalListBadRequest().WithPayload(&models.ErrorResponse{
+				Code:    "400",
+				Message: "EasyCLA - 400 Bad Request - the approval list import CSV content is empty",
+			})
+		}
+
+		companyModel, compErr := companyService.GetCompanyByExternalID(params.CompanySFID)
+		if compErr != nil || companyModel == nil {
+			log.Warnf("unable to locate company by external company ID: %s", params.CompanySFID)
+			return signatures.NewImportApprovalListNotFound().WithPayload(errorResponse(compErr))
+		}
+
+		projectModel, projErr := projectService.GetCLAGroupByID(params.ClaGroupID)
+		if projErr != nil || projectModel == nil {
+			log.Warnf("unable to locate project by CLA Group ID: %s", params.ClaGroupID)
+			return signatures.NewImportApprovalListNotFound().WithPayload(errorResponse(projErr))
+		}
+
+		dryRun := params.DryRun != nil && *params.DryRun
+		report, importErr := v2service.ImportApprovalList(authUser, projectModel, companyModel, params.ClaGroupID, params.Body, dryRun)
+		if importErr != nil {
+			if err, ok := importErr.(*signatureService.ForbiddenError); ok {
+				return signatures.NewImportApprovalListForbidden().WithPayload(errorResponse(err))
+			}
+
+			// Another request modified the approval list in the meantime - return the current state so the caller can
+			// re-run the import
+			if conflictErr, ok := importErr.(*signatureService.ConflictError); ok {
+				log.Warnf("conflict importing signature approval list using CLA Group ID: %s, error: %v", params.ClaGroupID, conflictErr)
+				currentSig := models.Signature{}
+				if conflictErr.Current != nil {
+					if err := copier.Copy(&currentSig, conflictErr.Current); err != nil {
+						return signatures.NewImportApprovalListInternalServerError().WithPayload(errorResponse(err))
+					}
+				}
+				return signatures.NewImportApprovalListConflict().WithPayload(&currentSig)
+			}
+
+			log.Warnf("unable to import signature approval list using CLA Group ID: %s", params.ClaGroupID)
+			return signatures.NewImportApprovalListBadRequest().WithPayload(errorResponse(importErr))
+		}
+
+		return signatures.NewImportApprovalListOK().WithPayload(report)
+	})
+
+	// List the Approval List Revisions
+	api.SignaturesListApprovalListRevisionsHandler = signatures.ListApprovalListRevisionsHandlerFunc(func(params signatures.ListApprovalListRevisionsParams, authUser *auth.User) middleware.Responder {
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		sig, errResponse := getApprovalListSignature(authUser, companyService, v1SignatureService, params.ProjectSFID, params.CompanySFID, params.ClaGroupID)
+		if errResponse != nil {
+			if errResponse.Code == "403" {
+				return signatures.NewListApprovalListRevisionsForbidden().WithPayload(errResponse)
+			}
+			return signatures.NewListApprovalListRevisionsNotFound().WithPayload(errResponse)
+		}
+
+		revisions, err := revisionsService.GetRevisions(sig.SignatureID.String())
+		if err != nil {
+			log.Warnf("unable to load the approval list revisions for signature ID: %s, error: %+v", sig.SignatureID, err)
+			return signatures.NewListApprovalListRevisionsInternalServerError().WithPayload(errorResponse(err))
+		}
+
+		return signatures.NewListApprovalListRevisionsOK().WithPayload(v2ApprovalListRevisions(sig.SignatureID.String(), revisions))
+	})
+
+	// Compare two Approval List Revisions
+	api.SignaturesGetApprovalListRevisionDiffHandler = signatures.GetApprovalListRevisionDiffHandlerFunc(func(params signatures.GetApprovalListRevisionDiffParams, authUser *auth.User) middleware.Responder {
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		sig, errResponse := getApprovalListSignature(authUser, companyService, v1SignatureService, params.ProjectSFID, params.CompanySFID, params.ClaGroupID)
+		if errResponse != nil {
+			if errResponse.Code == "403" {
+				return signatures.NewGetApprovalListRevisionDiffForbidden().WithPayload(errResponse)
+			}
+			return signatures.NewGetApprovalListRevisionDiffNotFound().WithPayload(errResponse)
+		}
+
+		diff, err := revisionsService.GetRevisionDiff(sig.SignatureID.String(), params.FromRevision, params.ToRevision)
+		if err != nil {
+			log.Warnf("unable to compare the approval list revisions %d and %d for signature ID: %s, error: %+v",
+				params.FromRevision, params.ToRevision, sig.SignatureID, err)
+			if errors.Is(err, approval_list_revisions.ErrRevisionNotFound) {
+				return signatures.NewGetApprovalListRevisionDiffNotFound().WithPayload(errorResponse(err))
+			}
+			return signatures.NewGetApprovalListRevisionDiffInternalServerError().WithPayload(errorResponse(err))
+		}
+
+		return signatures.NewGetApprovalListRevisionDiffOK().WithPayload(v2ApprovalListRevisionDiff(diff))
+	})
+
+	// Reconstruct the Approval List as of a point in time
+	api.SignaturesGetApprovalListAsOfHandler = signatures.GetApprovalListAsOfHandlerFunc(func(params signatures.GetApprovalListAsOfParams, authUser *auth.User) middleware.Responder {
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		asOf, err := utils.ParseDateTime(params.Timestamp)
+		if err != nil {
+			return signatures.NewGetApprovalListAsOfBadRequest().WithPayload(errorResponse(err))
+		}
+
+		sig, errResponse := getApprovalListSignature(authUser, companyService, v1SignatureService, params.ProjectSFID, params.CompanySFID, params.ClaGroupID)
+		if errResponse != nil {
+			if errResponse.Code == "403" {
+				return signatures.NewGetApprovalListAsOfForbidden().WithPayload(errResponse)
+			}
+			return signatures.NewGetApprovalListAsOfNotFound().WithPayload(errResponse)
+		}
+
+		snapshot, err := revisionsService.GetApprovalListAsOf(sig, asOf)
+		if err != nil {
+			log.Warnf("unable to reconstruct the approval list as of %s for signature ID: %s, error: %+v",
+				params.Timestamp, sig.SignatureID, err)
+			return signatures.NewGetApprovalListAsOfInternalServerError().WithPayload(errorResponse(err))
+		}
+
+		return signatures.NewGetApprovalListAsOfOK().WithPayload(v2ApprovalListSnapshot(snapshot))
+	})
+
+	// Get the CCLA Approval List Request Auto-Approval Rules
+	api.SignaturesGetAutoApprovalRulesHandler = signatures.GetAutoApprovalRulesHandlerFunc(func(params signatures.GetAutoApprovalRulesParams, authUser *auth.User) middleware.Responder {
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		sig, errResponse := getApprovalListSignature(authUser, companyService, v1SignatureService, params.ProjectSFID, params.CompanySFID, params.ClaGroupID)
+		if errResponse != nil {
+			if errResponse.Code == "403" {
+				return signatures.NewGetAutoApprovalRulesForbidden().WithPayload(errResponse)
+			}
+			return signatures.NewGetAutoApprovalRulesNotFound().WithPayload(errResponse)
+		}
+
+		return signatures.NewGetAutoApprovalRulesOK().WithPayload(v2AutoApprovalRules(sig))
+	})
+
+	// Replace the CCLA Approval List Request Auto-Approval Rules
+	api.SignaturesUpdateAutoApprovalRulesHandler = signatures.UpdateAutoApprovalRulesHandlerFunc(func(params signatures.UpdateAutoApprovalRulesParams, authUser *auth.User) middleware.Responder {
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		if !utils.IsUserAuthorizedForProjectOrganization(authUser, params.ProjectSFID, params.CompanySFID) {
+			msg := fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to update the auto-approval rules with Project|Organization scope of %s | %s",
+				authUser.UserName, params.ProjectSFID, params.CompanySFID)
+			log.Warn(msg)
+			return signatures.NewUpdateAutoApprovalRulesForbidden().WithPayload(&models.ErrorResponse{
+				Code:    "403",
+				Message: msg,
+			})
+		}
+
+		companyModel, compErr := companyService.GetCompanyByExternalID(params.CompanySFID)
+		if compErr != nil || companyModel == nil {
+			log.Warnf("unable to locate company by external company ID: %s", params.CompanySFID)
+			return signatures.NewUpdateAutoApprovalRulesNotFound().WithPayload(errorResponse(compErr))
+		}
+
+		projectModel, projErr := projectService.GetCLAGroupByID(params.ClaGroupID)
+		if projErr != nil || projectModel == nil {
+			log.Warnf("unable to locate project by CLA Group ID: %s", params.ClaGroupID)
+			return signatures.NewUpdateAutoApprovalRulesNotFound().WithPayload(errorResponse(projErr))
+		}
+
+		updatedSig, updateErr := v1SignatureService.UpdateAutoApprovalRules(authUser, projectModel, companyModel, params.ClaGroupID, v1AutoApprovalRules(params.Body.Rules), params.Body.RecordVersion)
+		if updateErr != nil {
+			if err, ok := updateErr.(*signatureService.ForbiddenError); ok {
+				return signatures.NewUpdateAutoApprovalRulesForbidden().WithPayload(errorResponse(err))
+			}
+			if conflictErr, ok := updateErr.(*signatureService.ConflictError); ok {
+				log.Warnf("conflict updating the auto-approval rules using CLA Group ID: %s, error: %v", params.ClaGroupID, conflictErr)
+				currentSig := models.Signature{}
+				if conflictErr.Current != nil {
+					if err := copier.Copy(&currentSig, conflictErr.Current); err != nil {
+						return signatures.NewUpdateAutoApprovalRulesInternalServerError().WithPayload(errorResponse(err))
+					}
+				}
+				return signatures.NewUpdateAutoApprovalRulesConflict().WithPayload(&currentSig)
+			}
+			log.Warnf("unable to update the auto-approval rules using CLA Group ID: %s, error: %v", params.ClaGroupID, updateErr)
+			return signatures.NewUpdateAutoApprovalRulesBadRequest().WithPayload(errorResponse(updateErr))
+		}
+
+		return signatures.NewUpdateAutoApprovalRulesOK().WithPayload(v2AutoApprovalRules(updatedSig))
+	})
+
+	// Revoke Signature
+	api.SignaturesRevokeSignatureHandler = signatures.RevokeSignatureHandlerFunc(func(params signatures.RevokeSignatureParams, authUser *auth.User) middleware.Responder {
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		signatureModel, err := v1SignatureService.GetSignature(params.SignatureID)
+		if err != nil {
+			log.Warnf("error looking up signature using signature_id: %s, error: %+v", params.SignatureID, err)
+			return signatures.NewRevokeSignatureBadRequest().WithPayload(errorResponse(err))
+		}
+		if signatureModel == nil {
+			return signatures.NewRevokeSignatureNotFound()
+		}
+
+		haveAccess, err := isUserAuthorizedToRevokeSignature(authUser, signatureModel, companyService, projectClaGroupsRepo)
+		if err != nil {
+			log.Warnf("error checking the revoke access of user: %s for signature_id: %s, error: %+v", authUser.UserName, params.SignatureID, err)
+			return signatures.NewRevokeSignatureInternalServerError().WithPayload(errorResponse(err))
+		}
+		if !haveAccess {
+			msg := fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to revoke the signature: %s",
+				authUser.UserName, params.SignatureID)
+			log.Warn(msg)
+			return signatures.NewRevokeSignatureForbidden().WithPayload(&models.ErrorResponse{
+				Code:    "403",
+				Message: msg,
+			})
+		}
+
+		projectModel, projErr := projectService.GetCLAGroupByID(signatureModel.ProjectID)
+		if projErr != nil || projectModel == nil {
+			log.Warnf("unable to locate project by CLA Group ID: %s", signatureModel.ProjectID)
+			return signatures.NewRevokeSignatureNotFound().WithPayload(errorResponse(projErr))
+		}
+
+		revocation := v1Models.SignatureRevocation{}
+		err = copier.Copy(&revocation, params.Body)
+		if err != nil {
+			return signatures.NewRevokeSignatureInternalServerError().WithPayload(errorResponse(err))
+		}
+
+		revokedSig, revokeErr := v1SignatureService.RevokeSignature(authUser, projectModel, params.SignatureID, &revocation)
+		if revokeErr != nil {
+			if err, ok := revokeErr.(*signatureService.BadRequestError); ok {
+				return signatures.NewRevokeSignatureBadRequest().WithPayload(errorResponse(err))
+			}
+			if conflictErr, ok := revokeErr.(*signatureService.ConflictError); ok {
+				log.Warnf("conflict revoking signature_id: %s, error: %v", params.SignatureID, conflictErr)
+				currentSig := models.Signature{}
+				if conflictErr.Current != nil {
+					if err := copier.Copy(&currentSig, conflictErr.Current); err != nil {
+						return signatures.NewRevokeSignatureInternalServerError().WithPayload(errorResponse(err))
+					}
+				}
+				return signatures.NewRevokeSignatureConflict().WithPayload(&currentSig)
+			}
+			log.Warnf("unable to revoke signature_id: %s, error: %v", params.SignatureID, revokeErr)
+			return signatures.NewRevokeSignatureInternalServerError().WithPayload(errorResponse(revokeErr))
+		}
+		if revokedSig == nil {
+			return signatures.NewRevokeSignatureNotFound()
+		}
+
+		resp, err := v2Signature(revokedSig)
+		if err != nil {
+			return signatures.NewRevokeSignatureInternalServerError().WithPayload(errorResponse(err))
+		}
+		return signatures.NewRevokeSignatureOK().WithPayload(resp)
+	})
+
 	// Retrieve GitHub Approval Entries
 	api.SignaturesGetGitHubOrgWhitelistHandler = signatures.GetGitHubOrgWhitelistHandlerFunc(func(params signatures.GetGitHubOrgWhitelistParams, authUser *auth.User) middleware.Responder {
 		session, err := sessionStore.Get(params.HTTPRequest, github.SessionStoreKey)
@@ -182,7 +466,7 @@ func Configure(api *operations.EasyclaAPI, projectService project.Service, proje
 			return signatures.NewAddGitHubOrgWhitelistInternalServerError().WithPayload(errorResponse(err))
 		}
 
-		ghApprovalList, err := v1SignatureService.AddGithubOrganizationToWhitelist(params.SignatureID, input, githubAccessToken)
+		ghApprovalList, err := v1SignatureService.AddGithubOrganizationToWhitelist(params.SignatureID, input, githubAccessToken, authUserActor(authUser))
 		if err != nil {
 			log.Warnf("error adding github organization %s using signature_id: %s to the approval list, error: %+v",
 				*params.Body.OrganizationID, params.SignatureID, err)
@@ -240,7 +524,7 @@ func Configure(api *operations.EasyclaAPI, projectService project.Service, proje
 			return signatures.NewDeleteGitHubOrgWhitelistInternalServerError().WithPayload(errorResponse(err))
 		}
 
-		ghApprovalList, err := v1SignatureService.DeleteGithubOrganizationFromWhitelist(params.SignatureID, input, githubAccessToken)
+		ghApprovalList, err := v1SignatureService.DeleteGithubOrganizationFromWhitelist(params.SignatureID, input, githubAccessToken, authUserActor(authUser))
 		if err != nil {
 			log.Warnf("error deleting github organization %s using signature_id: %s from the approval list, error: %+v",
 				*params.Body.OrganizationID, params.SignatureID, err)
@@ -408,7 +692,7 @@ func Configure(api *operations.EasyclaAPI, projectService project.Service, proje
 		}
 		return signatures.NewGetUserSignaturesOK().WithPayload(resp)
 	})
-	// Download ECLAs as a CSV document
+	// Download ECLAs as a CSV, JSON Lines or XLSX document
 	api.SignaturesDownloadProjectSignatureEmployeeAsCSVHandler = signatures.DownloadProjectSignatureEmployeeAsCSVHandlerFunc(
 		func(params signatures.DownloadProjectSignatureEmployeeAsCSVParams, authUser *auth.User) middleware.Responder {
 			claGroupModel, err := projectService.GetCLAGroupByID(params.ClaGroupID)
@@ -426,32 +710,31 @@ func Configure(api *operations.EasyclaAPI, projectService project.Service, proje
 				})
 			}
 
-			result, err := v2service.GetClaGroupCorporateContributorsCsv(params.ClaGroupID, params.CompanySFID)
+			options, err := exportOptions(params.Format, params.HTTPRequest.Header.Get("Accept"), params.Columns, params.SignedAfter, params.SignedBefore)
 			if err != nil {
+				return signatures.NewDownloadProjectSignatureEmployeeAsCSVBadRequest().WithPayload(errorResponse(err))
+			}
+			return exportResponder(options.Format, "corporate-contributors", func(w io.Writer) error {
+				return v2service.ExportClaGroupCorporateContributors(w, params.ClaGroupID, params.CompanySFID, options)
+			}, func(err error) middleware.Responder {
 				if _, ok := err.(*organizations.GetOrgNotFound); ok {
 					formatErr := errors.New("error retrieving company using companySFID")
 					return signatures.NewDownloadProjectSignatureEmployeeAsCSVNotFound().WithPayload(errorResponse(formatErr))
 				}
-				if ok := err.Error() == "not Found"; ok {
+				if errors.Is(err, ErrInvalidExportColumn) {
+					return signatures.NewDownloadProjectSignatureEmployeeAsCSVBadRequest().WithPayload(errorResponse(err))
+				}
+				if err == ErrNoCorporateContributors {
 					message := fmt.Sprintf("request not found for Company ID: %s, Cla Group ID: %s",
 						params.CompanySFID, params.ClaGroupID)
 					formatErr := errors.New(message)
 					return signatures.NewDownloadProjectSignatureEmployeeAsCSVNotFound().WithPayload(errorResponse(formatErr))
 				}
 				return signatures.NewDownloadProjectSignatureEmployeeAsCSVInternalServerError().WithPayload(errorResponse(err))
-			}
-
-			return middleware.ResponderFunc(func(rw http.ResponseWriter, pr runtime.Producer) {
-				rw.Header().Set("Content-Type", "text/csv")
-				rw.WriteHeader(http.StatusOK)
-				_, err := rw.Write(result)
-				if err != nil {
-					log.Warnf("Error writing csv file, error: %v", err)
-				}
 			})
 		})
 
-	// Download ICLAs as a CSV document
+	// Download ICLAs as a CSV, JSON Lines or XLSX document
 	api.SignaturesDownloadProjectSignatureICLAAsCSVHandler = signatures.DownloadProjectSignatureICLAAsCSVHandlerFunc(
 		func(params signatures.DownloadProjectSignatureICLAAsCSVParams, authUser *auth.User) middleware.Responder {
 			claGroupModel, err := projectService.GetCLAGroupByID(params.ClaGroupID)
@@ -475,17 +758,17 @@ func Configure(api *operations.EasyclaAPI, projectService project.Service, proje
 				})
 			}
 
-			result, err := v2service.GetProjectIclaSignaturesCsv(params.ClaGroupID)
+			options, err := exportOptions(params.Format, params.HTTPRequest.Header.Get("Accept"), params.Columns, params.SignedAfter, params.SignedBefore)
 			if err != nil {
-				return signatures.NewDownloadProjectSignatureICLAAsCSVInternalServerError().WithPayload(errorResponse(err))
+				return signatures.NewDownloadProjectSignatureICLAAsCSVBadRequest().WithPayload(errorResponse(err))
 			}
-			return middleware.ResponderFunc(func(rw http.ResponseWriter, pr runtime.Producer) {
-				rw.Header().Set("Content-Type", "text/csv")
-				rw.WriteHeader(http.StatusOK)
-				_, err := rw.Write(result)
-				if err != nil {
-					log.Warnf("Error writing csv file, error: %v", err)
+			return exportResponder(options.Format, "icla-signatures", func(w io.Writer) error {
+				return v2service.ExportProjectIclaSignatures(w, params.ClaGroupID, options)
+			}, func(err error) middleware.Responder {
+				if errors.Is(err, ErrInvalidExportColumn) {
+					return signatures.NewDownloadProjectSignatureICLAAsCSVBadRequest().WithPayload(errorResponse(err))
 				}
+				return signatures.NewDownloadProjectSignatureICLAAsCSVInternalServerError().WithPayload(errorResponse(err))
 			})
 		})
 
@@ -577,6 +860,64 @@ func Configure(api *operations.EasyclaAPI, projectService project.Service, proje
 		return signatures.NewGetSignatureSignedDocumentOK().WithPayload(doc)
 	})
 
+	api.SignaturesVerifySignatureSignedDocumentHandler = signatures.VerifySignatureSignedDocumentHandlerFunc(func(params signatures.VerifySignatureSignedDocumentParams, authUser *auth.User) middleware.Responder {
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+
+		signature, err := v1SignatureService.GetSignature(params.SignatureID)
+		if err != nil {
+			return signatures.NewVerifySignatureSignedDocumentInternalServerError().WithPayload(errorResponse(err))
+		}
+		if signature == nil {
+			return signatures.NewVerifySignatureSignedDocumentNotFound().WithPayload(errorResponse(errors.New("signature not found")))
+		}
+		haveAccess, err := isUserHaveAccessOfSignedSignaturePDF(authUser, signature, companyService, projectClaGroupsRepo)
+		if err != nil {
+			return signatures.NewVerifySignatureSignedDocumentInternalServerError().WithPayload(errorResponse(err))
+		}
+		if !haveAccess {
+			return signatures.NewVerifySignatureSignedDocumentForbidden().WithPayload(&models.ErrorResponse{
+				Code:    "403",
+				Message: "EasyCLA - 403 Forbidden : user does not have access of signature",
+			})
+		}
+		result, err := documentIntegrityService.VerifySignature(signature)
+		if err != nil {
+			if err == document_integrity.ErrNoSignedDocument {
+				return signatures.NewVerifySignatureSignedDocumentBadRequest().WithPayload(&models.ErrorResponse{
+					Code:    "400",
+					Message: "EasyCLA - 400 Bad Request - employee signature does not have signed document",
+				})
+			}
+			return signatures.NewVerifySignatureSignedDocumentInternalServerError().WithPayload(errorResponse(err))
+		}
+		return signatures.NewVerifySignatureSignedDocumentOK().WithPayload(result)
+	})
+
+	api.SignaturesAuditClaGroupSignedDocumentsHandler = signatures.AuditClaGroupSignedDocumentsHandlerFunc(func(params signatures.AuditClaGroupSignedDocumentsParams, authUser *auth.User) middleware.Responder {
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+
+		claGroup, err := projectService.GetCLAGroupByID(params.ClaGroupID)
+		if err != nil {
+			if err == project.ErrProjectDoesNotExist {
+				return signatures.NewAuditClaGroupSignedDocumentsNotFound().WithPayload(errorResponse(err))
+			}
+			return signatures.NewAuditClaGroupSignedDocumentsInternalServerError().WithPayload(errorResponse(err))
+		}
+		if !utils.IsUserAuthorizedForProject(authUser, claGroup.FoundationSFID) {
+			return signatures.NewAuditClaGroupSignedDocumentsForbidden().WithPayload(&models.ErrorResponse{
+				Code: "403",
+				Message: fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to AuditClaGroupSignedDocuments with project scope of %s",
+					authUser.UserName, claGroup.FoundationSFID),
+			})
+		}
+		audit, err := documentIntegrityService.AuditCLAGroup(params.ClaGroupID)
+		if err != nil {
+			log.Warnf("unable to audit the signed documents of CLA Group: %s, error: %+v", params.ClaGroupID, err)
+			return signatures.NewAuditClaGroupSignedDocumentsInternalServerError().WithPayload(errorResponse(err))
+		}
+		return signatures.NewAuditClaGroupSignedDocumentsOK().WithPayload(audit)
+	})
+
 	api.SignaturesDownloadProjectSignatureICLAsHandler = signatures.DownloadProjectSignatureICLAsHandlerFunc(
 		func(params signatures.DownloadProjectSignatureICLAsParams, authUser *auth.User) middleware.Responder {
 			claGroup, err := projectService.GetCLAGroupByID(params.ClaGroupID)
@@ -681,10 +1022,141 @@ func isUserHaveAccessOfSignedSignaturePDF(authUser *auth.User, signature *v1Mode
 	return false, nil
 }
 
+// isUserAuthorizedToRevokeSignature returns true if the user is an admin, a project manager of the CLA group foundation
+// or, for an employee acknowledgement, a CLA Manager of the company for the project
+func isUserAuthorizedToRevokeSignature(authUser *auth.User, signature *v1Models.Signature, companyService company.IService, projectClaGroupRepo projects_cla_groups.Repository) (bool, error) {
+	if authUser.Admin {
+		return true, nil
+	}
+	projects, err := projectClaGroupRepo.GetProjectsIdsForClaGroup(signature.ProjectID)
+	if err != nil {
+		return false, err
+	}
+	if len(projects) == 0 {
+		return false, fmt.Errorf("cannot find project(s) associated with CLA group cla_group_id: %s - please update the database with the foundation/project mapping",
+			signature.ProjectID)
+	}
+
+	pmScope := authUser.ResourceIDsByTypeAndRole(auth.Project, "project-manager")
+	if len(pmScope) > 0 && utils.NewStringSetFromStringArray(pmScope).Include(projects[0].FoundationSFID) {
+		return true, nil
+	}
+	if !signatureService.RevocableByCompanyManager(signature) {
+		return false, nil
+	}
+
+	comp, err := companyService.GetCompany(signature.SignatureUserCompanyID)
+	if err != nil {
+		return false, err
+	}
+	expectedScope := fmt.Sprintf("%s|%s", projects[0].ProjectSFID, comp.CompanyExternalID)
+	cmScope := authUser.ResourceIDsByTypeAndRole(auth.ProjectOrganization, "cla-manager")
+	return len(cmScope) > 0 && utils.NewStringSetFromStringArray(cmScope).Include(expectedScope), nil
+}
+
+// getApprovalListSignature returns the CCLA signature of the company for the CLA group if the user is authorized to
+// view its approval list - the error response code is either 403 or 404
+func getApprovalListSignature(authUser *auth.User, companyService company.IService, v1SignatureService signatureService.SignatureService, projectSFID, companySFID, claGroupID string) (*v1Models.Signature, *models.ErrorResponse) {
+	// Must be in the Project|Organization Scope to see this
+	if !utils.IsUserAuthorizedForProjectOrganization(authUser, projectSFID, companySFID) {
+		msg := fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to view the Project Company Approval List with Project|Organization scope of %s | %s",
+			authUser.UserName, projectSFID, companySFID)
+		log.Warn(msg)
+		return nil, &models.ErrorResponse{
+			Code:    "403",
+			Message: msg,
+		}
+	}
+
+	companyModel, compErr := companyService.GetCompanyByExternalID(companySFID)
+	if compErr != nil || companyModel == nil {
+		msg := fmt.Sprintf("unable to locate company by external company ID: %s", companySFID)
+		log.Warn(msg)
+		return nil, &models.ErrorResponse{
+			Code:    "404",
+			Message: msg,
+		}
+	}
+
+	signed, approved := true, true
+	pageSize := int64(1)
+	sig, sigErr := v1SignatureService.GetProjectCompanySignature(companyModel.CompanyID, claGroupID, &signed, &approved, nil, &pageSize)
+	if sigErr != nil || sig == nil {
+		msg := fmt.Sprintf("unable to locate the signature for company ID: %s CLA Group ID: %s, type: ccla, signed: %t, approved: %t",
+			companyModel.CompanyID, claGroupID, signed, approved)
+		log.Warn(msg)
+		return nil, &models.ErrorResponse{
+			Code:    "404",
+			Message: msg,
+		}
+	}
+
+	return sig, nil
+}
+
 type codedResponse interface {
 	Code() string
 }
 
+// exportOptions returns the options of an export from the request parameters - the format is negotiated from the
+// format parameter and the accept header, the date range bounds are RFC3339 formatted date/times or dates
+func exportOptions(format *string, accept string, columns []string, signedAfter, signedBefore *string) (*ExportOptions, error) {
+	exportFormat, err := export.NegotiateFormat(format, accept)
+	if err != nil {
+		return nil, err
+	}
+	options := &ExportOptions{Format: exportFormat, Columns: columns}
+	if err := options.SetDateRange(utils.StringValue(signedAfter), utils.StringValue(signedBefore)); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// exportResponseWriter sends the export response status and headers with the first write and flushes every write
+// to the client, so that the export is streamed as it is produced
+type exportResponseWriter struct {
+	rw       http.ResponseWriter
+	format   string
+	filename string
+	started  bool
+}
+
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.start()
+	}
+	n, err := w.rw.Write(p)
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
+
+func (w *exportResponseWriter) start() {
+	w.started = true
+	w.rw.Header().Set("Content-Type", export.ContentType(w.format))
+	w.rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename(w.filename, w.format)))
+	w.rw.WriteHeader(http.StatusOK)
+}
+
+// exportResponder streams the export written by write to the response in the format. The error of write is
+// converted with onError if nothing was written yet, otherwise the response status was already sent and the error
+// is only logged.
+func exportResponder(format, filename string, write func(w io.Writer) error, onError func(err error) middleware.Responder) middleware.Responder {
+	return middleware.ResponderFunc(func(rw http.ResponseWriter, pr runtime.Producer) {
+		w := &exportResponseWriter{rw: rw, format: format, filename: filename}
+		err := write(w)
+		switch {
+		case err != nil && !w.started:
+			onError(err).WriteResponse(rw, pr)
+		case err != nil:
+			log.Warnf("Error writing %s export, error: %v", format, err)
+		case !w.started:
+			w.start()
+		}
+	})
+}
+
 func errorResponse(err error) *models.ErrorResponse {
 	code := ""
 	if e, ok := err.(codedResponse); ok {
@@ -698,3 +1170,12 @@ func errorResponse(err error) *models.ErrorResponse {
 
 	return &e
 }
+
+// authUserActor returns the user model of the authenticated user, recorded as the actor of the approval list revisions
+func authUserActor(authUser *auth.User) *v1Models.User {
+	return &v1Models.User{
+		Username:   authUser.UserName,
+		LfUsername: authUser.UserName,
+		LfEmail:    authUser.Email,
+	}
+}
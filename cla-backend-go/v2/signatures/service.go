@@ -1,15 +1,10 @@
 package signatures
 
 import (
-	"bytes"
 	"errors"
-	"fmt"
+	"io"
 
-	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
-
-	"github.com/aws/aws-sdk-go/aws"
+	"github.com/LF-Engineering/lfx-kit/auth"
 
 	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
 
@@ -18,11 +13,10 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/company"
 	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
-	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 	"github.com/communitybridge/easycla/cla-backend-go/project"
 	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
-	"github.com/sirupsen/logrus"
 )
 
 // constants
@@ -43,24 +37,25 @@ type service struct {
 	v1CompanyService      company.IService
 	v1SignatureService    signatures.SignatureService
 	projectsClaGroupsRepo projects_cla_groups.Repository
-	s3                    *s3.S3
-	signaturesBucket      string
+	blobStore             storage.BlobStore
+	githubLookup          GitHubLookup
 }
 
 // Service contains method of v2 signature service
 type Service interface {
 	GetProjectCompanySignatures(companySFID string, projectSFID string) (*models.Signatures, error)
-	GetProjectIclaSignaturesCsv(claGroupID string) ([]byte, error)
+	ExportProjectIclaSignatures(w io.Writer, claGroupID string, options *ExportOptions) error
 	GetProjectIclaSignatures(claGroupID string, searchTerm *string) (*models.IclaSignatures, error)
-	GetClaGroupCorporateContributorsCsv(claGroupID string, companySFID string) ([]byte, error)
+	ExportClaGroupCorporateContributors(w io.Writer, claGroupID string, companySFID string, options *ExportOptions) error
 	GetClaGroupCorporateContributors(claGroupID string, companySFID *string, searchTerm *string) (*models.CorporateContributorList, error)
 	GetSignedDocument(signatureID string) (*models.SignedDocument, error)
 	GetSignedIclaZipPdf(claGroupID string) (*models.URLObject, error)
 	GetSignedCclaZipPdf(claGroupID string) (*models.URLObject, error)
+	ImportApprovalList(authUser *auth.User, projectModel *v1Models.Project, companyModel *v1Models.Company, claGroupID string, params *models.ApprovalListImport, dryRun bool) (*models.ApprovalListImportReport, error)
 }
 
 // NewService creates instance of v2 signature service
-func NewService(awsSession *session.Session, signaturesBucketName string, v1ProjectService project.Service,
+func NewService(blobStore storage.BlobStore, v1ProjectService project.Service,
 	v1CompanyService company.IService,
 	v1SignatureService signatures.SignatureService,
 	pcgRepo projects_cla_groups.Repository) *service {
@@ -69,8 +64,8 @@ func NewService(awsSession *session.Session, signaturesBucketName string, v1Proj
 		v1CompanyService:      v1CompanyService,
 		v1SignatureService:    v1SignatureService,
 		projectsClaGroupsRepo: pcgRepo,
-		s3:                    s3.New(awsSession),
-		signaturesBucket:      signaturesBucketName,
+		blobStore:             blobStore,
+		githubLookup:          githubLookup{},
 	}
 }
 
@@ -99,66 +94,6 @@ func (s *service) GetProjectCompanySignatures(companySFID string, projectSFID st
 	return v2SignaturesReplaceCompanyID(resp, companyModel.CompanyID, companySFID)
 }
 
-func iclaSigCsvLine(sig *v1Models.IclaSignature) string {
-	var dateTime string
-	t, err := utils.ParseDateTime(sig.SignedOn)
-	if err != nil {
-		log.WithFields(logrus.Fields{"signature_id": sig.SignatureID, "signature_created": sig.SignedOn}).
-			Error("invalid time format present for signatures")
-	} else {
-		dateTime = t.Format("Jan 2,2006")
-	}
-	return fmt.Sprintf("\n%s,%s,%s,%s,\"%s\"", sig.GithubUsername, sig.LfUsername, sig.UserName, sig.UserEmail, dateTime)
-}
-
-func eclaSigCsvLine(sig *v1Models.CorporateContributor) string {
-	var dateTime string
-	t, err := utils.ParseDateTime(sig.Timestamp)
-	if err != nil {
-		log.WithFields(logrus.Fields{"signature_id": sig.LinuxFoundationID, "signature_created": sig.Timestamp}).
-			Error("invalid time format present for signatures")
-	} else {
-		dateTime = t.Format("Jan 2,2006")
-	}
-	return fmt.Sprintf("\n%s,%s,%s,%s,\"%s\"", sig.GithubID, sig.LinuxFoundationID, sig.Name, sig.Email, dateTime)
-}
-
-func (s service) GetClaGroupCorporateContributorsCsv(claGroupID string, companySFID string) ([]byte, error) {
-	var b bytes.Buffer
-	comp, companyErr := s.v1CompanyService.GetCompanyByExternalID(companySFID)
-	if companyErr != nil {
-		return nil, companyErr
-	}
-
-	result, err := s.v1SignatureService.GetClaGroupCorporateContributors(claGroupID, &comp.CompanyID, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(result.List) == 0 {
-		return nil, errors.New("not Found")
-	}
-
-	b.WriteString(`Github ID,LF_ID,Name,Email,Date Signed`)
-	for _, sig := range result.List {
-		b.WriteString(eclaSigCsvLine(sig))
-	}
-	return b.Bytes(), nil
-}
-
-func (s service) GetProjectIclaSignaturesCsv(claGroupID string) ([]byte, error) {
-	var b bytes.Buffer
-	result, err := s.v1SignatureService.GetClaGroupICLASignatures(claGroupID, nil)
-	if err != nil {
-		return nil, err
-	}
-	b.WriteString(`Github ID,LF_ID,Name,Email,Date Signed`)
-	for _, sig := range result.List {
-		b.WriteString(iclaSigCsvLine(sig))
-	}
-	return b.Bytes(), nil
-}
-
 func (s service) GetProjectIclaSignatures(claGroupID string, searchTerm *string) (*models.IclaSignatures, error) {
 	var out models.IclaSignatures
 	result, err := s.v1SignatureService.GetClaGroupICLASignatures(claGroupID, searchTerm)
@@ -192,8 +127,9 @@ func (s service) GetSignedDocument(signatureID string) (*models.SignedDocument,
 		return nil, err
 	}
 	return &models.SignedDocument{
-		SignatureID:  signatureID,
-		SignedClaURL: signedURL,
+		SignatureID:    signatureID,
+		SignedClaURL:   signedURL,
+		DocumentSha256: sig.SignatureDocumentSha256,
 	}, nil
 }
 
@@ -233,13 +169,9 @@ func (s service) GetSignedIclaZipPdf(claGroupID string) (*models.URLObject, erro
 }
 
 func (s service) IsZipPresentOnS3(zipFilePath string) (bool, error) {
-	_, err := s.s3.GetObject(&s3.GetObjectInput{
-		Bucket: aws.String(s.signaturesBucket),
-		Key:    aws.String(zipFilePath),
-	})
+	_, err := s.blobStore.Stat(zipFilePath)
 	if err != nil {
-		aerr, ok := err.(awserr.Error)
-		if ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+		if err == storage.ErrBlobNotFound {
 			return false, nil
 		}
 		return false, err
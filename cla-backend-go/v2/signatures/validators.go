@@ -31,7 +31,9 @@ func hasApprovalListUpdates(params signatures.UpdateApprovalListParams) bool {
 	if len(params.Body.AddEmailApprovalList) > 0 || len(params.Body.RemoveEmailApprovalList) > 0 ||
 		len(params.Body.AddDomainApprovalList) > 0 || len(params.Body.RemoveDomainApprovalList) > 0 ||
 		len(params.Body.AddGithubUsernameApprovalList) > 0 || len(params.Body.RemoveGithubUsernameApprovalList) > 0 ||
-		len(params.Body.AddGithubOrgApprovalList) > 0 || len(params.Body.RemoveGithubOrgApprovalList) > 0 {
+		len(params.Body.AddGithubOrgApprovalList) > 0 || len(params.Body.RemoveGithubOrgApprovalList) > 0 ||
+		len(params.Body.AddGithubTeamApprovalList) > 0 || len(params.Body.RemoveGithubTeamApprovalList) > 0 ||
+		len(params.Body.ApprovalListExpirations) > 0 {
 		return true
 	}
 
@@ -58,14 +60,15 @@ func entriesAreValid(params signatures.UpdateApprovalListParams) (string, bool)
 
 	// Ensure the domains are valid
 	for _, domain := range params.Body.AddDomainApprovalList {
-		msg, valid := utils.ValidDomain(domain)
+		msg, valid := utils.ValidDomainPattern(domain)
 		if !valid {
 			isValid = false
 			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid add approval list domain %s - %s", domain, msg))
 		}
 	}
 	for _, domain := range params.Body.RemoveDomainApprovalList {
-		msg, valid := utils.ValidDomain(domain)
+		// allow legacy wildcard entries (*acme.com, .acme.com) to be removed
+		msg, valid := utils.ValidDomainPattern(utils.NormalizeDomainPattern(domain))
 		if !valid {
 			isValid = false
 			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid remove approval list domain %s - %s", domain, msg))
@@ -104,5 +107,21 @@ func entriesAreValid(params signatures.UpdateApprovalListParams) (string, bool)
 		}
 	}
 
+	// Ensure the github Team values are valid
+	for _, githubTeam := range params.Body.AddGithubTeamApprovalList {
+		msg, valid := utils.ValidGitHubTeam(githubTeam)
+		if !valid {
+			isValid = false
+			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid add approval list GitHub Team %s - %s", githubTeam, msg))
+		}
+	}
+	for _, githubTeam := range params.Body.RemoveGithubTeamApprovalList {
+		msg, valid := utils.ValidGitHubTeam(githubTeam)
+		if !valid {
+			isValid = false
+			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid remove approval list GitHub Team %s - %s", githubTeam, msg))
+		}
+	}
+
 	return strings.Join(listOfErrors, ", "), isValid
 }
@@ -1,53 +1,74 @@
 package signatures
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 
 	"github.com/juju/zip"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
-
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
-
-	"github.com/aws/aws-sdk-go/aws/awserr"
-
-	"github.com/aws/aws-sdk-go/aws"
-
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
 )
 
 // constants
 const (
-	ICLA               = "icla"
-	CCLA               = "ccla"
-	ParallelDownloader = 100
+	ICLA = "icla"
+	CCLA = "ccla"
+	// ParallelDownloader is the number of signed documents downloaded in parallel, each download is held in memory
+	// until it is written to the zip
+	ParallelDownloader = 10
 )
 
+// the zips are streamed to the blob store uploads
+const (
+	// the previous zip is read by blocks, the blocks read to check the zip entries are smaller as only the central
+	// directory is read
+	zipReadBlockSize  = 8 * 1024 * 1024
+	zipCheckBlockSize = 256 * 1024
+	// zipBuildBudget is the time after which no new document is added to the zip being built, the remaining documents
+	// are added by the next build - the zipbuilder-lambda times out after 15 minutes
+	zipBuildBudget = 10 * time.Minute
+	// the uploads of the builds interrupted before completing are aborted after staleZipUploadAge, if the blob store
+	// keeps them
+	staleZipUploadAge = time.Hour
+)
+
+// ErrZipIncomplete is returned when the zip was built and uploaded within the time budget of the build without all
+// the new signed documents, the remaining documents are added by the next build
+var ErrZipIncomplete = errors.New("zip build incomplete, signed documents remain to be added")
+
 // Zipper implements ZipBuilder interface
 type Zipper struct {
-	s3         *s3.S3
-	bucketName string
+	store          storage.BlobStore
+	signaturesRepo signatures.SignatureRepository
 }
 
 // ZipBuilder provides method to build ICLA/CCLA zip
 type ZipBuilder interface {
 	BuildICLAZip(claGroupID string) error
 	BuildCCLAZip(claGroupID string) error
+	BuildFilteredZip(w io.Writer, claType string, claGroupID string, filter *ArchiveFilter) (*ArchiveManifest, error)
+	ZipOutdated(claType string, claGroupID string) (bool, error)
 }
 
-// NewZipBuilder returns the ZipBuilder
-func NewZipBuilder(awsSession *session.Session, bucketName string) ZipBuilder {
+// NewZipBuilder returns the ZipBuilder of the signed documents of the blob store, the signatures are loaded to list the
+// signers in the zip manifests
+func NewZipBuilder(store storage.BlobStore, signaturesRepo signatures.SignatureRepository) ZipBuilder {
 	return &Zipper{
-		s3:         s3.New(awsSession),
-		bucketName: bucketName,
+		store:          store,
+		signaturesRepo: signaturesRepo,
 	}
 }
 
@@ -59,6 +80,16 @@ func s3ZipPrefix(claType string, claGroupID string) string {
 	return fmt.Sprintf("contract-group/%s/%s/", claGroupID, claType)
 }
 
+// s3DocumentFilename returns the filename of the signed document of the s3 key, false if the key isn't a signed
+// document key - contract-group/<cla-group-ID>/<claType>/<identifier>/<signatureID>.pdf
+func s3DocumentFilename(key string) (string, bool) {
+	tmp := strings.Split(key, "/")
+	if len(tmp) != 5 {
+		return "", false
+	}
+	return tmp[4], true
+}
+
 // BuildICLAZip builds icla pdfs zip for cla-group and upload it on s3
 func (z *Zipper) BuildICLAZip(claGroupID string) error {
 	return z.buildZip(ICLA, claGroupID)
@@ -69,216 +100,373 @@ func (z *Zipper) BuildCCLAZip(claGroupID string) error {
 	return z.buildZip(CCLA, claGroupID)
 }
 
+// buildZip adds the signed documents added since the previous build to the zip of the cla-group. The entries of the
+// previous zip are streamed from the blob store and the new documents are downloaded with bounded concurrency, the new
+// zip is streamed to an upload which only replaces the previous zip once completed. The documents not added within
+// the build time budget are added by the next build, an interrupted build leaves the previous zip as is and is resumed
+// by the next build.
 func (z *Zipper) buildZip(claType string, claGroupID string) error {
 	f := logrus.Fields{"cla_group_id": claGroupID, "cla_type": claType}
-	// get zip file from s3
-	buff, err := z.getZipFileFromS3(claType, claGroupID)
+	started := time.Now()
+	remoteZipFileKey := s3ZipFilepath(claType, claGroupID)
+	previous, err := z.openZip(remoteZipFileKey, zipReadBlockSize)
 	if err != nil {
 		return err
 	}
-	var files *utils.StringSet
-	if len(buff.Bytes()) != 0 {
-		// read files already present in zip
-		log.Debug("reading files present in zip")
-		files, err = getZipFiles(buff)
-		if err != nil {
-			return err
+	existing, hasManifest := zipFilenames(previous)
+	documentKeys, err := z.documentKeys(claType, claGroupID)
+	if err != nil {
+		return err
+	}
+	var inputs []*DownloadFileInput
+	for filename, key := range documentKeys {
+		if existing.Include(filename) {
+			// skip files which are already present in zip
+			continue
 		}
+		inputs = append(inputs, &DownloadFileInput{
+			filename: filename,
+			key:      key,
+		})
+	}
+	if len(inputs) == 0 && (hasManifest || previous == nil) {
+		log.WithFields(f).Debug("no new signed document, zip is up to date")
+		return nil
 	}
-	log.WithFields(f).Debug("getting zip writer")
-	writer, err := getZipWriter(buff)
+	sort.Slice(inputs, func(i, j int) bool {
+		return inputs[i].filename < inputs[j].filename
+	})
+	sigs, err := z.claGroupSignatures(claType, claGroupID)
 	if err != nil {
 		return err
 	}
-	var zipUpdated bool
-	log.WithFields(f).Debug("getting s3 files")
-	downloaderInputChan := make(chan *DownloadFileInput)
-	downloaderOutputChan := make(chan *FileContent)
-	var wg sync.WaitGroup
-	wg.Add(ParallelDownloader)
-	for i := 1; i <= ParallelDownloader; i++ {
-		go z.downloader(&wg, downloaderInputChan, downloaderOutputChan)
-	}
-	go func() {
-		wg.Wait()
-		close(downloaderOutputChan)
-	}()
-	go func() {
-		err = z.s3.ListObjectsPages(&s3.ListObjectsInput{
-			Bucket: aws.String(z.bucketName),
-			Prefix: aws.String(s3ZipPrefix(claType, claGroupID)),
-		}, func(output *s3.ListObjectsOutput, b bool) bool {
-			for _, obj := range output.Contents {
-				key := utils.StringValue(obj.Key)
-				tmp := strings.Split(key, "/")
-				if len(tmp) != 5 {
-					continue
-				}
-				filename := tmp[4]
-				if files != nil && files.Include(filename) {
-					// skip files which are already present in zip
-					log.Debugf("file %s already present in zip", filename)
-					continue
-				}
-				downloaderInputChan <- &DownloadFileInput{
-					filename: filename,
-					key:      obj.Key,
-				}
+	z.abortStaleUploads(remoteZipFileKey)
+
+	log.WithFields(f).Debugf("Uploading zip file %s with %d new signed documents", remoteZipFileKey, len(inputs))
+	var added int
+	var complete bool
+	err = z.upload(remoteZipFileKey, func(w io.Writer) error {
+		archive := newArchiveWriter(w, &ArchiveManifest{ClaGroupID: claGroupID, SignatureType: claType})
+		if previous != nil {
+			if copyErr := copyZipFiles(archive, previous, sigs); copyErr != nil {
+				return copyErr
 			}
-			return true
+		}
+		copied := len(archive.manifest.Documents)
+		var writeErr error
+		complete, writeErr = z.writeDocuments(archive, inputs, sigs, started.Add(zipBuildBudget))
+		if writeErr != nil {
+			return writeErr
+		}
+		added = len(archive.manifest.Documents) - copied
+		return archive.Close()
+	})
+	if err != nil {
+		log.Warnf("Uploading zip file %s failed. error = %s", remoteZipFileKey, err.Error())
+		return err
+	}
+	log.WithFields(f).Debugf("Uploaded zip file %s with %d new signed documents in %s", remoteZipFileKey, added, time.Since(started))
+	if !complete {
+		return ErrZipIncomplete
+	}
+	return nil
+}
+
+// BuildFilteredZip writes the zip of the signed documents of the cla-group matching the filter to w. The documents
+// are downloaded on demand, the zip isn't stored. The signatures whose signed document is missing are listed in the
+// manifest.
+func (z *Zipper) BuildFilteredZip(w io.Writer, claType string, claGroupID string, filter *ArchiveFilter) (*ArchiveManifest, error) {
+	if claType != ICLA && claType != CCLA {
+		return nil, fmt.Errorf("invalid signature type: %s", claType)
+	}
+	sigs, err := z.claGroupSignatures(claType, claGroupID)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &ArchiveManifest{ClaGroupID: claGroupID, SignatureType: claType}
+	if filter != nil {
+		manifest.CompanyID = filter.CompanyID
+		if filter.SignedAfter != nil {
+			manifest.SignedAfter = utils.TimeToString(*filter.SignedAfter)
+		}
+		if filter.SignedBefore != nil {
+			manifest.SignedBefore = utils.TimeToString(*filter.SignedBefore)
+		}
+	}
+
+	var inputs []*DownloadFileInput
+	for signatureID, sig := range sigs {
+		if !filter.includes(sig) {
+			continue
+		}
+		inputs = append(inputs, &DownloadFileInput{
+			filename: signatureID + ".pdf",
+			key:      utils.SignedCLAFilename(claGroupID, claType, sig.SignatureReferenceID.String(), signatureID),
 		})
-		close(downloaderInputChan)
+	}
+	log.WithFields(logrus.Fields{"cla_group_id": claGroupID, "cla_type": claType}).
+		Debugf("building zip of %d signed documents", len(inputs))
+
+	archive := newArchiveWriter(w, manifest)
+	if _, err = z.writeDocuments(archive, inputs, sigs, time.Time{}); err != nil {
+		return nil, err
+	}
+	for _, input := range inputs {
+		if !archive.includes(input.filename) {
+			manifest.MissingSignatureIDs = append(manifest.MissingSignatureIDs, strings.TrimSuffix(input.filename, ".pdf"))
+		}
+	}
+	sort.Strings(manifest.MissingSignatureIDs)
+	if err = archive.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// ZipOutdated returns true if signed documents of the cla-group are missing from its zip - the zip entries are
+// compared with the stored documents. The zips built before the manifests were added are outdated.
+func (z *Zipper) ZipOutdated(claType string, claGroupID string) (bool, error) {
+	previous, err := z.openZip(s3ZipFilepath(claType, claGroupID), zipCheckBlockSize)
+	if err != nil {
+		return false, err
+	}
+	existing, hasManifest := zipFilenames(previous)
+	if previous != nil && !hasManifest {
+		return true, nil
+	}
+	documentKeys, err := z.documentKeys(claType, claGroupID)
+	if err != nil {
+		return false, err
+	}
+	for filename := range documentKeys {
+		if !existing.Include(filename) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// claGroupSignatures returns the signatures of the cla-group with a signed document of the signature type, by
+// signature ID
+func (z *Zipper) claGroupSignatures(claType string, claGroupID string) (map[string]*v1Models.Signature, error) {
+	sigs, err := z.signaturesRepo.ProjectSignatures(claGroupID)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*v1Models.Signature)
+	for _, sig := range sigs.Signatures {
+		if signedDocumentSignature(claType, sig) {
+			result[sig.SignatureID.String()] = sig
+		}
+	}
+	return result, nil
+}
+
+// documentKeys returns the keys of the stored signed documents of the cla-group, by filename
+func (z *Zipper) documentKeys(claType string, claGroupID string) (map[string]string, error) {
+	keys := make(map[string]string)
+	err := z.store.List(s3ZipPrefix(claType, claGroupID), func(info *storage.BlobInfo) bool {
+		if filename, ok := s3DocumentFilename(info.Key); ok {
+			keys[filename] = info.Key
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// openZip opens the zip stored at the key, nil if there is no zip. The zip is read by blocks with ranged reads, the
+// reads fail if the zip is replaced while it is read.
+func (z *Zipper) openZip(key string, blockSize int64) (*zip.Reader, error) {
+	info, err := z.store.Stat(key)
+	if err != nil {
+		if err == storage.ErrBlobNotFound {
+			log.Debugf("zip file %s does not exist", key)
+			return nil, nil
+		}
+		return nil, err
+	}
+	reader := utils.NewBlockReaderAt(info.Size, blockSize, func(start, end int64) (io.ReadCloser, error) {
+		return z.store.GetRange(key, start, end, info.ETag)
+	})
+	return zip.NewReader(reader, info.Size)
+}
+
+// zipFilenames returns the signed document filenames of the zip, and true if the zip has a manifest
+func zipFilenames(r *zip.Reader) (*utils.StringSet, bool) {
+	files := utils.NewStringSet()
+	var hasManifest bool
+	if r == nil {
+		return files, false
+	}
+	for _, file := range r.File {
+		if file.Name == ManifestFilename {
+			hasManifest = true
+			continue
+		}
+		files.Add(file.Name)
+	}
+	return files, hasManifest
+}
+
+// upload streams the content written by write to the blob store. The blob is only replaced once the content is
+// completely written, the upload is aborted if write fails.
+func (z *Zipper) upload(key string, write func(w io.Writer) error) error {
+	pr, pw := io.Pipe()
+	writeErrChan := make(chan error, 1)
+	go func() {
+		writeErr := write(pw)
+		// a nil error closes the pipe normally, the upload is aborted otherwise
+		_ = pw.CloseWithError(writeErr)
+		writeErrChan <- writeErr
 	}()
-	zipUpdated = writeFileToZip(writer, downloaderOutputChan)
+	err := z.store.Put(key, pr, &storage.PutOptions{ContentType: "application/zip"})
+	if err != nil {
+		// the writes fail once the upload failed
+		_ = pr.CloseWithError(err)
+	}
+	writeErr := <-writeErrChan
 	if err != nil {
 		return err
 	}
-	writer.Close()
-	if zipUpdated {
-		remoteZipFileKey := s3ZipFilepath(claType, claGroupID)
-		log.Debugf("Uploading zip file %s", remoteZipFileKey)
-		err := z.uploadFile(buff, remoteZipFileKey)
-		if err != nil {
-			log.Warnf("Uploading zip file %s failed. error = %s", remoteZipFileKey, err.Error())
-			return err
-		}
-		log.Debugf("Uploaded zip file %s", remoteZipFileKey)
+	return writeErr
+}
+
+// abortStaleUploads aborts the uploads of the key left by the interrupted builds, the recent uploads may be in
+// progress and are left as is
+func (z *Zipper) abortStaleUploads(key string) {
+	aborter, ok := z.store.(storage.StaleUploadAborter)
+	if !ok {
+		return
+	}
+	if err := aborter.AbortStaleUploads(key, staleZipUploadAge); err != nil {
+		log.Warnf("unable to abort the stale uploads of %s. error = %v", key, err)
 	}
-	return nil
 }
 
-// FileContent contains file content of s3 file
+// FileContent contains file content of the stored file
 type FileContent struct {
-	buff     *aws.WriteAtBuffer
+	content  []byte
 	filename string
 }
 
 // DownloadFileInput is input to downloader
 type DownloadFileInput struct {
 	filename string
-	key      *string
+	key      string
 }
 
-func writeFileToZip(writer *zip.Writer, filesInput chan *FileContent) bool {
-	var zipUpdated bool
-	for fileContent := range filesInput {
-		filename := fileContent.filename
-		buff := fileContent.buff
-		log.Debugf("Adding file : %s to zip", filename)
-		header := &zip.FileHeader{
-			Name:   filename,
-			Method: zip.Deflate,
+// writeDocuments downloads the documents and adds them to the archive. Once the deadline, if any, is passed no other
+// document is downloaded - the first downloads are always made so that each build makes progress. Returns false if
+// documents were left out because of the deadline.
+func (z *Zipper) writeDocuments(archive *archiveWriter, inputs []*DownloadFileInput, sigs map[string]*v1Models.Signature, deadline time.Time) (bool, error) {
+	downloaderInputChan := make(chan *DownloadFileInput)
+	downloaderOutputChan := z.startDownloaders(downloaderInputChan)
+	done := make(chan struct{})
+	complete := true
+	go func() {
+		defer close(downloaderInputChan)
+		for i, input := range inputs {
+			if i >= ParallelDownloader && !deadline.IsZero() && time.Now().After(deadline) {
+				log.Debugf("zip build budget exceeded, %d documents are left for the next build", len(inputs)-i)
+				complete = false
+				return
+			}
+			select {
+			case downloaderInputChan <- input:
+			case <-done:
+				return
+			}
 		}
-		header.SetMode(0644)
-		f, err := writer.CreateHeader(header)
-		if err != nil {
-			log.WithField("file", filename).Error("unable to write file header in zip")
+	}()
+	var writeErr error
+	for fileContent := range downloaderOutputChan {
+		if writeErr != nil {
 			continue
 		}
-		_, err = f.Write(buff.Bytes())
-		if err != nil {
-			log.WithField("file", filename).Error("unable to write file data in zip")
-			continue
+		filename := fileContent.filename
+		log.Debugf("Adding file : %s to zip", filename)
+		doc := newArchiveDocument(filename, sigs[strings.TrimSuffix(filename, ".pdf")])
+		if err := archive.add(doc, fileContent.content); err != nil {
+			log.WithField("file", filename).Error("unable to write file in zip", err)
+			writeErr = err
+			// no other document is downloaded, the downloads in progress are consumed
+			close(done)
 		}
-		zipUpdated = true
 	}
-	return zipUpdated
+	return complete, writeErr
 }
 
-func (z *Zipper) downloader(wg *sync.WaitGroup, inputChan chan *DownloadFileInput, outputChan chan *FileContent) {
-	defer wg.Done()
-	for in := range inputChan {
-		log.Debugf("Downloading file : %s", in.filename)
-		buff := &aws.WriteAtBuffer{}
-		downloader := s3manager.NewDownloaderWithClient(z.s3)
-		_, err := downloader.Download(buff,
-			&s3.GetObjectInput{
-				Bucket: aws.String(z.bucketName),
-				Key:    in.key,
-			})
-		if err != nil {
-			log.WithField("key", utils.StringValue(in.key)).Error("unable to download file from s3", err)
+// copyZipFiles streams the files of the previous zip to the archive, the manifest is regenerated
+func copyZipFiles(archive *archiveWriter, r *zip.Reader, sigs map[string]*v1Models.Signature) error {
+	for _, file := range r.File {
+		if file.Name == ManifestFilename {
 			continue
 		}
-		outputChan <- &FileContent{
-			buff:     buff,
-			filename: in.filename,
+		if err := copyZipFile(archive, file, sigs); err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
-func getZipFiles(buff *bytes.Buffer) (*utils.StringSet, error) {
-	reader := bytes.NewReader(buff.Bytes())
-	files := utils.NewStringSet()
-	r, err := zip.NewReader(reader, reader.Size())
+func copyZipFile(archive *archiveWriter, file *zip.File, sigs map[string]*v1Models.Signature) error {
+	rc, err := file.Open()
 	if err != nil {
-		return nil, err
-	}
-	for _, file := range r.File {
-		files.Add(file.Name)
+		return err
 	}
-	return files, nil
+	defer func() {
+		if closeErr := rc.Close(); closeErr != nil {
+			log.Warnf("unable to close zip file %s. error = %v", file.Name, closeErr)
+		}
+	}()
+	return archive.addFrom(newArchiveDocument(file.Name, sigs[strings.TrimSuffix(file.Name, ".pdf")]), rc)
 }
 
-func getZipWriter(buff *bytes.Buffer) (*zip.Writer, error) {
-	var writer *zip.Writer
-	if len(buff.Bytes()) == 0 {
-		writer = zip.NewWriter(buff)
-	} else {
-		reader := bytes.NewReader(buff.Bytes())
-		r, err := zip.NewReader(reader, reader.Size())
-		if err != nil {
-			return nil, err
-		}
-		writer = r.Append(buff)
+// startDownloaders downloads the files of the input channel in parallel, the output channel is closed once the input
+// channel is closed and all the files are downloaded
+func (z *Zipper) startDownloaders(inputChan chan *DownloadFileInput) chan *FileContent {
+	outputChan := make(chan *FileContent)
+	var wg sync.WaitGroup
+	wg.Add(ParallelDownloader)
+	for i := 1; i <= ParallelDownloader; i++ {
+		go z.downloader(&wg, inputChan, outputChan)
 	}
-	return writer, nil
+	go func() {
+		wg.Wait()
+		close(outputChan)
+	}()
+	return outputChan
 }
 
-func (z *Zipper) getZipFileFromS3(claType string, claGroupID string) (*bytes.Buffer, error) {
-	var buff aws.WriteAtBuffer
-	remoteFileKey := s3ZipFilepath(claType, claGroupID)
-	_, err := z.s3.GetObject(&s3.GetObjectInput{
-		Bucket: aws.String(z.bucketName),
-		Key:    aws.String(remoteFileKey),
-	})
-	if err != nil {
-		aerr, ok := err.(awserr.Error)
-		if ok && aerr.Code() == s3.ErrCodeNoSuchKey {
-			log.Debugf("zip file %s does not exist on s3", remoteFileKey)
-			return bytes.NewBuffer(buff.Bytes()), nil
+func (z *Zipper) downloader(wg *sync.WaitGroup, inputChan chan *DownloadFileInput, outputChan chan *FileContent) {
+	defer wg.Done()
+	for in := range inputChan {
+		log.Debugf("Downloading file : %s", in.filename)
+		content, err := z.download(in.key)
+		if err != nil {
+			log.WithField("key", in.key).Error("unable to download file", err)
+			continue
+		}
+		outputChan <- &FileContent{
+			content:  content,
+			filename: in.filename,
 		}
-		return nil, err
-	}
-	log.Debugf("Downloading zip file %s", remoteFileKey)
-
-	downloader := s3manager.NewDownloaderWithClient(z.s3)
-
-	_, err = downloader.Download(&buff,
-		&s3.GetObjectInput{
-			Bucket: aws.String(z.bucketName),
-			Key:    aws.String(remoteFileKey),
-		})
-	if err != nil {
-		return nil, err
 	}
-	log.Debugf("Downloading zip file %s completed", remoteFileKey)
-	return bytes.NewBuffer(buff.Bytes()), nil
 }
 
-func (z *Zipper) uploadFile(localFileContent *bytes.Buffer, s3ZipFile string) error {
-	uploader := s3manager.NewUploaderWithClient(z.s3)
-	// Upload the file to S3.
-	_, err := uploader.Upload(&s3manager.UploadInput{
-		Bucket: aws.String(z.bucketName),
-		Key:    aws.String(s3ZipFile),
-		Body:   localFileContent,
-	})
-
-	//in case it fails to upload
+// download returns the content of the stored file
+func (z *Zipper) download(key string) ([]byte, error) {
+	rc, err := z.store.Get(key)
 	if err != nil {
-		log.Warnf("failed to upload file %s. error = %v", s3ZipFile, err)
-		return err
+		return nil, err
 	}
-	return nil
+	defer func() {
+		if closeErr := rc.Close(); closeErr != nil {
+			log.Warnf("unable to close file %s. error = %v", key, closeErr)
+		}
+	}()
+	return ioutil.ReadAll(rc)
 }
@@ -0,0 +1,50 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	v1Template "github.com/communitybridge/easycla/cla-backend-go/template"
+)
+
+// buildDocumentVersionModel converts the CLA Group document version into the response model
+func buildDocumentVersionModel(version *v1Template.DocumentVersion) *models.DocumentVersion {
+	metaFields := []*models.MetaField{}
+	for _, metaField := range version.MetaFields {
+		metaFields = append(metaFields, &models.MetaField{
+			Name:             metaField.Name,
+			TemplateVariable: metaField.TemplateVariable,
+			Value:            metaField.Value,
+		})
+	}
+	return &models.DocumentVersion{
+		DocumentType:    version.DocumentType,
+		Version:         version.Version(),
+		MajorVersion:    int64(version.MajorVersion),
+		MinorVersion:    int64(version.MinorVersion),
+		DocumentName:    version.DocumentName,
+		TemplateID:      version.TemplateID,
+		TemplateVersion: version.TemplateVersion,
+		MetaFields:      metaFields,
+		PublishedBy:     version.PublishedBy,
+		DateCreated:     version.DateCreated,
+		DocumentS3URL:   version.DocumentS3URL,
+		RestoredVersion: version.RestoredVersion,
+		Current:         version.Current,
+		Locale:          version.Locale,
+		Translations:    version.TranslationLocales,
+	}
+}
+
+// buildDocumentHistoryModel converts the CLA Group document versions into the response model
+func buildDocumentHistoryModel(claGroupID string, versions []v1Template.DocumentVersion) *models.DocumentHistory {
+	result := &models.DocumentHistory{
+		ClaGroupID: claGroupID,
+		List:       []*models.DocumentVersion{},
+	}
+	for i := range versions {
+		result.List = append(result.List, buildDocumentVersionModel(&versions[i]))
+	}
+	return result
+}
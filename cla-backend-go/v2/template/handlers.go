@@ -4,6 +4,8 @@
 package template
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/LF-Engineering/lfx-kit/auth"
@@ -14,15 +16,17 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/template"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
 	v1Template "github.com/communitybridge/easycla/cla-backend-go/template"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/common"
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/jinzhu/copier"
 )
 
 // Configure API call
-func Configure(api *operations.EasyclaAPI, service v1Template.Service, eventsService v1Events.Service) {
+func Configure(api *operations.EasyclaAPI, service v1Template.Service, eventsService v1Events.Service, projectRepo project.ProjectRepository) { //nolint
 	// Retrieve a list of available templates
 	api.TemplateGetTemplatesHandler = template.GetTemplatesHandlerFunc(func(params template.GetTemplatesParams, user *auth.User) middleware.Responder {
 
@@ -45,7 +49,7 @@ func Configure(api *operations.EasyclaAPI, service v1Template.Service, eventsSer
 		if err != nil {
 			return template.NewGetTemplatesInternalServerError().WithPayload(errorResponse(err))
 		}
-		pdfUrls, err := service.CreateCLAGroupTemplate(params.HTTPRequest.Context(), params.ClaGroupID, input)
+		pdfUrls, err := service.CreateCLAGroupTemplate(params.HTTPRequest.Context(), params.ClaGroupID, input, user.UserName)
 		if err != nil {
 			log.Warnf("Error generating PDFs from provided templates, error: %v", err)
 			return template.NewGetTemplatesBadRequest().WithPayload(errorResponse(err))
@@ -65,13 +69,213 @@ func Configure(api *operations.EasyclaAPI, service v1Template.Service, eventsSer
 		return template.NewCreateCLAGroupTemplateOK().WithPayload(response)
 	})
 
+	api.TemplateGetTemplateHandler = template.GetTemplateHandlerFunc(func(params template.GetTemplateParams, user *auth.User) middleware.Responder {
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		var version int64
+		if params.Version != nil {
+			version = *params.Version
+		}
+		templateModel, err := service.GetTemplate(params.HTTPRequest.Context(), params.TemplateID, version)
+		if err != nil {
+			if err == v1Template.ErrTemplateNotFound {
+				return template.NewGetTemplateNotFound().WithPayload(errorResponse(err))
+			}
+			return template.NewGetTemplateInternalServerError().WithPayload(errorResponse(err))
+		}
+		response := &models.Template{}
+		err = copier.Copy(response, &templateModel)
+		if err != nil {
+			return template.NewGetTemplateInternalServerError().WithPayload(errorResponse(err))
+		}
+		return template.NewGetTemplateOK().WithPayload(response)
+	})
+
+	api.TemplateGetTemplateVersionsHandler = template.GetTemplateVersionsHandlerFunc(func(params template.GetTemplateVersionsParams, user *auth.User) middleware.Responder {
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		versions, err := service.GetTemplateVersions(params.HTTPRequest.Context(), params.TemplateID)
+		if err != nil {
+			switch err {
+			case v1Template.ErrTemplateNotFound:
+				return template.NewGetTemplateVersionsNotFound().WithPayload(errorResponse(err))
+			case v1Template.ErrBuiltInTemplate:
+				return template.NewGetTemplateVersionsBadRequest().WithPayload(errorResponse(err))
+			}
+			return template.NewGetTemplateVersionsInternalServerError().WithPayload(errorResponse(err))
+		}
+		response := []models.Template{}
+		err = copier.Copy(&response, versions)
+		if err != nil {
+			return template.NewGetTemplateVersionsInternalServerError().WithPayload(errorResponse(err))
+		}
+		return template.NewGetTemplateVersionsOK().WithPayload(response)
+	})
+
+	api.TemplateCreateTemplateHandler = template.CreateTemplateHandlerFunc(func(params template.CreateTemplateParams, user *auth.User) middleware.Responder {
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		if !utils.IsUserAdmin(user) {
+			return template.NewCreateTemplateForbidden().WithPayload(forbiddenResponse(user, "Upload Templates"))
+		}
+		input := &v1Models.Template{}
+		err := copier.Copy(input, &params.Body)
+		if err != nil {
+			return template.NewCreateTemplateInternalServerError().WithPayload(errorResponse(err))
+		}
+		templateModel, err := service.CreateTemplate(params.HTTPRequest.Context(), input, user.UserName)
+		if err != nil {
+			if errors.Is(err, v1Template.ErrInvalidTemplate) {
+				return template.NewCreateTemplateBadRequest().WithPayload(errorResponse(err))
+			}
+			log.Warnf("Error creating template: %s, error: %v", input.Name, err)
+			return template.NewCreateTemplateInternalServerError().WithPayload(errorResponse(err))
+		}
+		response := &models.Template{}
+		err = copier.Copy(response, &templateModel)
+		if err != nil {
+			return template.NewCreateTemplateInternalServerError().WithPayload(errorResponse(err))
+		}
+		return template.NewCreateTemplateOK().WithPayload(response)
+	})
+
+	api.TemplateUpdateTemplateHandler = template.UpdateTemplateHandlerFunc(func(params template.UpdateTemplateParams, user *auth.User) middleware.Responder {
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		if !utils.IsUserAdmin(user) {
+			return template.NewUpdateTemplateForbidden().WithPayload(forbiddenResponse(user, "Update Templates"))
+		}
+		input := &v1Models.Template{}
+		err := copier.Copy(input, &params.Body)
+		if err != nil {
+			return template.NewUpdateTemplateInternalServerError().WithPayload(errorResponse(err))
+		}
+		templateModel, err := service.UpdateTemplate(params.HTTPRequest.Context(), params.TemplateID, input, user.UserName)
+		if err != nil {
+			switch {
+			case err == v1Template.ErrTemplateNotFound:
+				return template.NewUpdateTemplateNotFound().WithPayload(errorResponse(err))
+			case err == v1Template.ErrBuiltInTemplate, errors.Is(err, v1Template.ErrInvalidTemplate):
+				return template.NewUpdateTemplateBadRequest().WithPayload(errorResponse(err))
+			case err == v1Template.ErrTemplateVersionConflict:
+				return template.NewUpdateTemplateConflict().WithPayload(errorResponse(err))
+			}
+			log.Warnf("Error updating template: %s, error: %v", params.TemplateID, err)
+			return template.NewUpdateTemplateInternalServerError().WithPayload(errorResponse(err))
+		}
+		response := &models.Template{}
+		err = copier.Copy(response, &templateModel)
+		if err != nil {
+			return template.NewUpdateTemplateInternalServerError().WithPayload(errorResponse(err))
+		}
+		return template.NewUpdateTemplateOK().WithPayload(response)
+	})
+
+	api.TemplateDeleteTemplateHandler = template.DeleteTemplateHandlerFunc(func(params template.DeleteTemplateParams, user *auth.User) middleware.Responder {
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		if !utils.IsUserAdmin(user) {
+			return template.NewDeleteTemplateForbidden().WithPayload(forbiddenResponse(user, "Delete Templates"))
+		}
+		err := service.DeleteTemplate(params.HTTPRequest.Context(), params.TemplateID)
+		if err != nil {
+			switch err {
+			case v1Template.ErrTemplateNotFound:
+				return template.NewDeleteTemplateNotFound().WithPayload(errorResponse(err))
+			case v1Template.ErrBuiltInTemplate:
+				return template.NewDeleteTemplateBadRequest().WithPayload(errorResponse(err))
+			}
+			log.Warnf("Error deleting template: %s, error: %v", params.TemplateID, err)
+			return template.NewDeleteTemplateInternalServerError().WithPayload(errorResponse(err))
+		}
+		return template.NewDeleteTemplateNoContent()
+	})
+
+	api.TemplateGetCLAGroupDocumentHistoryHandler = template.GetCLAGroupDocumentHistoryHandlerFunc(func(params template.GetCLAGroupDocumentHistoryParams, user *auth.User) middleware.Responder {
+		_, errResponse, code := common.LoadCLAGroup(projectRepo, user, params.XUSERNAME, params.XEMAIL, params.ClaGroupID, "GetCLAGroupDocumentHistory")
+		switch code {
+		case 403:
+			return template.NewGetCLAGroupDocumentHistoryForbidden().WithPayload(errResponse)
+		case 404:
+			return template.NewGetCLAGroupDocumentHistoryNotFound().WithPayload(errResponse)
+		case 500:
+			return template.NewGetCLAGroupDocumentHistoryInternalServerError().WithPayload(errResponse)
+		}
+		versions, err := service.GetCLAGroupDocumentHistory(params.HTTPRequest.Context(), params.ClaGroupID, params.DocumentType)
+		if err != nil {
+			if err == v1Template.ErrInvalidDocumentType {
+				return template.NewGetCLAGroupDocumentHistoryBadRequest().WithPayload(errorResponse(err))
+			}
+			return template.NewGetCLAGroupDocumentHistoryInternalServerError().WithPayload(errorResponse(err))
+		}
+		return template.NewGetCLAGroupDocumentHistoryOK().WithPayload(buildDocumentHistoryModel(params.ClaGroupID, versions))
+	})
+
+	api.TemplateGetCLAGroupDocumentDiffHandler = template.GetCLAGroupDocumentDiffHandlerFunc(func(params template.GetCLAGroupDocumentDiffParams, user *auth.User) middleware.Responder {
+		_, errResponse, code := common.LoadCLAGroup(projectRepo, user, params.XUSERNAME, params.XEMAIL, params.ClaGroupID, "GetCLAGroupDocumentDiff")
+		switch code {
+		case 403:
+			return template.NewGetCLAGroupDocumentDiffForbidden().WithPayload(errResponse)
+		case 404:
+			return template.NewGetCLAGroupDocumentDiffNotFound().WithPayload(errResponse)
+		case 500:
+			return template.NewGetCLAGroupDocumentDiffInternalServerError().WithPayload(errResponse)
+		}
+		diff, err := service.DiffCLAGroupDocuments(params.HTTPRequest.Context(), params.ClaGroupID, params.DocumentType, params.FromVersion, params.ToVersion)
+		if err != nil {
+			switch err {
+			case v1Template.ErrInvalidDocumentType, v1Template.ErrInvalidDocumentVersion:
+				return template.NewGetCLAGroupDocumentDiffBadRequest().WithPayload(errorResponse(err))
+			case v1Template.ErrDocumentVersionNotFound, v1Template.ErrDocumentSourceNotFound:
+				return template.NewGetCLAGroupDocumentDiffNotFound().WithPayload(errorResponse(err))
+			}
+			return template.NewGetCLAGroupDocumentDiffInternalServerError().WithPayload(errorResponse(err))
+		}
+		return template.NewGetCLAGroupDocumentDiffOK().WithPayload(&models.DocumentDiff{
+			DocumentType: params.DocumentType,
+			FromVersion:  params.FromVersion,
+			ToVersion:    params.ToVersion,
+			Diff:         diff,
+		})
+	})
+
+	api.TemplateRollbackCLAGroupDocumentHandler = template.RollbackCLAGroupDocumentHandlerFunc(func(params template.RollbackCLAGroupDocumentParams, user *auth.User) middleware.Responder {
+		_, errResponse, code := common.LoadCLAGroup(projectRepo, user, params.XUSERNAME, params.XEMAIL, params.ClaGroupID, "RollbackCLAGroupDocument")
+		switch code {
+		case 403:
+			return template.NewRollbackCLAGroupDocumentForbidden().WithPayload(errResponse)
+		case 404:
+			return template.NewRollbackCLAGroupDocumentNotFound().WithPayload(errResponse)
+		case 500:
+			return template.NewRollbackCLAGroupDocumentInternalServerError().WithPayload(errResponse)
+		}
+		documentType, version := utils.StringValue(params.Body.DocumentType), utils.StringValue(params.Body.Version)
+		restored, err := service.RollbackCLAGroupDocument(params.HTTPRequest.Context(), params.ClaGroupID, documentType, version, user.UserName)
+		if err != nil {
+			switch err {
+			case v1Template.ErrInvalidDocumentType, v1Template.ErrInvalidDocumentVersion, v1Template.ErrDocumentVersionCurrent:
+				return template.NewRollbackCLAGroupDocumentBadRequest().WithPayload(errorResponse(err))
+			case v1Template.ErrDocumentVersionNotFound, v1Template.ErrDocumentSourceNotFound:
+				return template.NewRollbackCLAGroupDocumentNotFound().WithPayload(errorResponse(err))
+			}
+			log.Warnf("Error rolling back the %s of CLA Group: %s to version %s, error: %v", documentType, params.ClaGroupID, version, err)
+			return template.NewRollbackCLAGroupDocumentInternalServerError().WithPayload(errorResponse(err))
+		}
+		eventsService.LogEvent(&events.LogEventArgs{
+			EventType:  events.CLATemplateRolledBack,
+			ProjectID:  params.ClaGroupID,
+			LfUsername: user.UserName,
+			EventData: &events.CLATemplateRolledBackEventData{
+				DocumentType:    documentType,
+				RestoredVersion: version,
+				Version:         restored.Version(),
+			},
+		})
+		return template.NewRollbackCLAGroupDocumentOK().WithPayload(buildDocumentVersionModel(restored))
+	})
+
 	api.TemplateTemplatePreviewHandler = template.TemplatePreviewHandlerFunc(func(params template.TemplatePreviewParams, user *auth.User) middleware.Responder {
 		var param v1Models.CreateClaGroupTemplate
 		err := copier.Copy(&param, &params.TemplatePreviewInput)
 		if err != nil {
 			return writeResponse(http.StatusInternalServerError, runtime.JSONMime, runtime.JSONProducer(), errorResponse(err))
 		}
-		pdf, err := service.CreateTemplatePreview(&param, params.TemplateFor)
+		pdf, err := service.CreateTemplatePreview(&param, params.TemplateFor, utils.StringValue(params.Locale))
 		if err != nil {
 			log.Warnf("Error generating PDFs from provided templates, error: %v", err)
 			return writeResponse(http.StatusBadRequest, runtime.JSONMime, runtime.JSONProducer(), errorResponse(err))
@@ -104,6 +308,14 @@ func errorResponse(err error) *models.ErrorResponse {
 	return &e
 }
 
+// forbiddenResponse returns the response of the template operations only available to admins
+func forbiddenResponse(user *auth.User, operation string) *models.ErrorResponse {
+	return &models.ErrorResponse{
+		Code:    "403",
+		Message: fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to %s - only Admins allowed.", user.UserName, operation),
+	}
+}
+
 func writeResponse(httpStatus int, contentType string, contentProducer runtime.Producer, data interface{}) middleware.Responder {
 	return middleware.ResponderFunc(func(rw http.ResponseWriter, pr runtime.Producer) {
 		rw.Header().Set(runtime.HeaderContentType, contentType)
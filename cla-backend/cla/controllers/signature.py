@@ -14,7 +14,7 @@ import requests
 
 import cla.hug_types
 from cla.controllers import company
-from cla.models import DoesNotExist
+from cla.models import DoesNotExist, VersionConflict
 from cla.models.event_types import EventType
 from cla.models.dynamo_models import User, Project, Signature, Company, Event
 from cla.utils import get_email_service, get_email_help_content, get_email_sign_off_content
@@ -294,6 +294,11 @@ def update_signature(signature_id,  # pylint: disable=too-many-arguments,too-man
                 'github_org_whitelist': 'Invalid value passed in for the github org whitelist'
             }}
 
+    try:
+        signature.save_with_version_check()
+    except VersionConflict as err:
+        return {'errors': {'signature_id': str(err)}}
+
     event_data = update_str
     Event.create_event(
         event_data=event_data,
@@ -301,7 +306,6 @@ def update_signature(signature_id,  # pylint: disable=too-many-arguments,too-man
         contains_pii=True,
     )
 
-    signature.save()
     notify_whitelist_change(auth_user=auth_user, old_signature=old_signature,new_signature=signature)
     return signature.to_dict()
 
@@ -951,7 +955,10 @@ def add_cla_manager(auth_user, signature_id, lfid):
 
     # Add lfid to acl
     signature.add_signature_acl(lfid)
-    signature.save()
+    try:
+        signature.save_with_version_check()
+    except VersionConflict as err:
+        return {'errors': {'signature_id': str(err)}}
 
     # send email to newly added CLA manager
     try:
@@ -998,7 +1005,10 @@ def remove_cla_manager(username, signature_id, lfid):
         return {'errors': {'user': "You cannot remove this manager because a CCLA must have at least one CLA manager."}}
     # Remove LFID from the acl
     signature.remove_signature_acl(lfid)
-    signature.save()
+    try:
+        signature.save_with_version_check()
+    except VersionConflict as err:
+        return {'errors': {'signature_id': str(err)}}
 
     # get cla managers for email content
     managers = get_cla_managers(username, signature_id)
@@ -12,7 +12,7 @@ from cla.models import DoesNotExist
 from cla.utils import get_signing_service, get_signature_instance, get_email_service
 
 
-def request_individual_signature(project_id, user_id, return_url_type, return_url=None):
+def request_individual_signature(project_id, user_id, return_url_type, return_url=None, document_locale=None):
     """
     Handle POST request to send ICLA signature request to user.
 
@@ -24,16 +24,21 @@ def request_individual_signature(project_id, user_id, return_url_type, return_ur
     :type return_url_type: string
     :param return_url: The URL to return the user to after signing is complete.
     :type return_url: string
+    :param document_locale: The locale of the ICLA viewed by the user, the legally binding locale by default.
+    :type document_locale: string
     """
     signing_service = get_signing_service()
     if return_url_type == "Gerrit":
-        return signing_service.request_individual_signature_gerrit(str(project_id), str(user_id), return_url)
+        return signing_service.request_individual_signature_gerrit(str(project_id), str(user_id), return_url,
+                                                                   document_locale)
     elif return_url_type == "Github":
-        return signing_service.request_individual_signature(str(project_id), str(user_id), return_url)
+        return signing_service.request_individual_signature(str(project_id), str(user_id), return_url,
+                                                            document_locale)
 
 
 def request_corporate_signature(auth_user, project_id, company_id, send_as_email=False, 
-                                authority_name=None, authority_email=None, return_url_type=None, return_url=None):
+                                authority_name=None, authority_email=None, return_url_type=None, return_url=None,
+                                document_locale=None):
     """
     Creates CCLA signature object that represents a company signing a CCLA.
 
@@ -56,10 +61,12 @@ def request_corporate_signature(auth_user, project_id, company_id, send_as_email
     :type return_url: str
     :param return_url: The URL to return the user to after signing is complete.
     :type return_url: string
+    :param document_locale: The locale of the CCLA viewed by the signatory, the legally binding locale by default.
+    :type document_locale: string
     """
     return get_signing_service().request_corporate_signature(auth_user, str(project_id), str(company_id), send_as_email,
                                                              authority_name, authority_email,
-                                                             return_url_type, return_url)
+                                                             return_url_type, return_url, document_locale)
 
 
 def request_employee_signature(project_id, company_id, user_id, return_url_type, return_url=None):
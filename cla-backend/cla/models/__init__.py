@@ -18,3 +18,6 @@ class MultipleResults(Exception):
     should only have one matching result.
     """
     pass
+class VersionConflict(Exception):
+    """Exception raised when a record was updated since it was loaded."""
+    pass
@@ -9,6 +9,7 @@ https://developers.docusign.com/esign-rest-api/guides/post-go-live
 
 """
 
+import hashlib
 import io
 import os
 import urllib.request
@@ -42,6 +43,12 @@ lf_group_client_secret = os.environ.get('LF_GROUP_CLIENT_SECRET', '')
 lf_group_refresh_token = os.environ.get('LF_GROUP_REFRESH_TOKEN', '')
 lf_group = LFGroup(lf_group_client_url, lf_group_client_id, lf_group_client_secret, lf_group_refresh_token)
 
+# locale of the documents published without a locale
+DEFAULT_DOCUMENT_LOCALE = 'en'
+
+# revocation reason of the signatures revoked by a re-sign campaign once the re-sign deadline passed
+REVOCATION_REASON_RESIGN_DEADLINE_PASSED = 'resignDeadlinePassed'
+
 
 class ProjectDoesNotExist(Exception):
     pass
@@ -119,7 +126,7 @@ class DocuSign(signing_service_interface.SigningService):
         self.s3storage = S3Storage()
         self.s3storage.initialize(None)
 
-    def request_individual_signature(self, project_id, user_id, return_url=None):
+    def request_individual_signature(self, project_id, user_id, return_url=None, document_locale=None):
         request_info = 'project: {project_id}, user: {user_id} with return_url: {return_url}'.format(
             project_id=project_id, user_id=user_id, return_url=return_url)
         cla.log.debug('Individual Signature - creating new signature for: {}'.format(request_info))
@@ -161,6 +168,13 @@ class DocuSign(signing_service_interface.SigningService):
         cla.log.debug('Individual Signature - loaded latest individual document for project: {}'.
                       format(project))
 
+        # The signatory may view a translation of the ICLA, the document in the legally binding locale is signed
+        signature_document_locale = get_signature_document_locale(last_document, document_locale)
+        if signature_document_locale is None:
+            cla.log.warning('Individual Signature - ICLA is not available in the locale: {} for: {}'.
+                            format(document_locale, request_info))
+            return {'errors': {'document_locale': 'ICLA is not available in the locale: {}'.format(document_locale)}}
+
         cla.log.debug('Individual Signature - creating default individual values for user: {}'.format(user))
         default_cla_values = create_default_individual_values(user)
         cla.log.debug('Individual Signature - created default individual values: {}'.format(default_cla_values))
@@ -180,6 +194,7 @@ class DocuSign(signing_service_interface.SigningService):
                           format(latest_signature.get_signature_id()))
 
             # Re-generate and set the signing url - this will update the signature record
+            latest_signature.set_signature_document_locale(signature_document_locale)
             self.populate_sign_url(latest_signature, callback_url, default_values=default_cla_values)
 
             return {'user_id': user_id,
@@ -236,6 +251,7 @@ class DocuSign(signing_service_interface.SigningService):
                               signature_approved=True,
                               signature_return_url=return_url,
                               signature_callback_url=callback_url)
+        signature.set_signature_document_locale(signature_document_locale)
 
         # Set signature ACL
         cla.log.debug('Individual Signature - setting ACL using user GH id: {}'.format(user.get_user_github_id()))
@@ -256,7 +272,7 @@ class DocuSign(signing_service_interface.SigningService):
         cla.log.debug('Individual Signature - returning response: {}'.format(response))
         return response
 
-    def request_individual_signature_gerrit(self, project_id, user_id, return_url=None):
+    def request_individual_signature_gerrit(self, project_id, user_id, return_url=None, document_locale=None):
         request_info = 'project: {project_id}, user: {user_id} with return_url: {return_url}'.format(
             project_id=project_id, user_id=user_id, return_url=return_url)
         cla.log.info('Creating new Gerrit signature for {}'.format(request_info))
@@ -285,11 +301,19 @@ class DocuSign(signing_service_interface.SigningService):
         # signed the most recent major version, they do not need to sign again.
         latest_signature = user.get_latest_signature(str(project_id))
         last_document = project.get_latest_individual_document()
+
+        # The signatory may view a translation of the ICLA, the document in the legally binding locale is signed
+        signature_document_locale = get_signature_document_locale(last_document, document_locale)
+        if signature_document_locale is None:
+            cla.log.warning('ICLA is not available in the locale: {} for: {}'.format(document_locale, request_info))
+            return {'errors': {'document_locale': 'ICLA is not available in the locale: {}'.format(document_locale)}}
+
         if latest_signature is not None and \
                 last_document.get_document_major_version() == latest_signature.get_signature_document_major_version():
             cla.log.info('User already has a signatures with this project: %s', latest_signature.get_signature_id())
 
             # Re-generate and set the signing url - this will update the signature record
+            latest_signature.set_signature_document_locale(signature_document_locale)
             self.populate_sign_url(latest_signature, callback_url, default_values=default_cla_values)
 
             return {'user_id': user_id,
@@ -335,6 +359,7 @@ class DocuSign(signing_service_interface.SigningService):
                               signature_approved=True,
                               signature_return_url=return_url,
                               signature_callback_url=callback_url)
+        signature.set_signature_document_locale(signature_document_locale)
 
         # Set signature ACL
         signature.set_signature_acl(user.get_lf_username())
@@ -669,7 +694,8 @@ class DocuSign(signing_service_interface.SigningService):
 
     def handle_signing_new_corporate_signature(self, signature, project, company, user,
                                                signatory_name=None, signatory_email=None,
-                                               send_as_email=False, return_url_type=None, return_url=None):
+                                               send_as_email=False, return_url_type=None, return_url=None,
+                                               document_locale=None):
         cla.log.debug('Handle signing of new corporate signature - '
                       f'project: {project}, '
                       f'company: {company}, '
@@ -694,6 +720,12 @@ class DocuSign(signing_service_interface.SigningService):
             cla.log.info('Contract Group {} does not have a CCLA'.format(project))
             return {'errors': {'project_id': 'Contract Group does not support CCLAs.'}}
 
+        # The signatory may view a translation of the CCLA, the document in the legally binding locale is signed
+        signature_document_locale = get_signature_document_locale(last_document, document_locale)
+        if signature_document_locale is None:
+            cla.log.warning(f'Contract Group {project} CCLA is not available in the locale: {document_locale}')
+            return {'errors': {'document_locale': f'CCLA is not available in the locale: {document_locale}'}}
+
         # No signature exists, create the new Signature.
         cla.log.info(f'Creating new signature for project {project} on company {company}')
         if signature is None:
@@ -719,6 +751,7 @@ class DocuSign(signing_service_interface.SigningService):
 
         # Set signature ACL
         signature.set_signature_acl(user.get_lf_username())
+        signature.set_signature_document_locale(signature_document_locale)
 
         self.populate_sign_url(signature, callback_url,
                                signatory_name, signatory_email,
@@ -739,7 +772,7 @@ class DocuSign(signing_service_interface.SigningService):
 
     def request_corporate_signature(self, auth_user, project_id, company_id, send_as_email=False,
                                     signatory_name=None, signatory_email=None, return_url_type=None,
-                                    return_url=None):
+                                    return_url=None, document_locale=None):
 
         cla.log.debug('Request corporate signature - '
                       f'project id: {project_id}, '
@@ -848,14 +881,16 @@ class DocuSign(signing_service_interface.SigningService):
             return self.handle_signing_new_corporate_signature(
                 signature=None, project=project, company=company, user=cla_manager_user,
                 signatory_name=signatory_name, signatory_email=signatory_email,
-                send_as_email=send_as_email, return_url_type=return_url_type, return_url=return_url)
+                send_as_email=send_as_email, return_url_type=return_url_type, return_url=return_url,
+                document_locale=document_locale)
 
         cla.log.debug(f'Previous unsigned CCLA signatures on file for project: {project_id}, company: {company_id}')
         # TODO: should I delete all but one?
         return self.handle_signing_new_corporate_signature(
             signature=signatures[0], project=project, company=company, user=cla_manager_user,
             signatory_name=signatory_name, signatory_email=signatory_email,
-            send_as_email=send_as_email, return_url_type=return_url_type, return_url=return_url)
+            send_as_email=send_as_email, return_url_type=return_url_type, return_url=return_url,
+            document_locale=document_locale)
 
     def populate_sign_url(self, signature, callback_url=None,
                           authority_or_signatory_name=None,
@@ -1148,7 +1183,7 @@ class DocuSign(signing_service_interface.SigningService):
 
             # Store document on S3
             project_id = signature.get_signature_project_id()
-            self.send_to_s3(document_data, project_id, signature_id, 'icla', user_id)
+            self.store_signed_document(signature, document_data, project_id, 'icla', user_id)
 
             try:
                 # Load the Project by ID and send audit event
@@ -1254,7 +1289,7 @@ class DocuSign(signing_service_interface.SigningService):
 
             # Store document on S3
             project_id = signature.get_signature_project_id()
-            self.send_to_s3(document_data, project_id, signature_id, 'icla', user_id)
+            self.store_signed_document(signature, document_data, project_id, 'icla', user_id)
             cla.log.debug('signed_individual_callback_gerrit - uploaded ICLA document to s3')
 
     def signed_corporate_callback(self, content, project_id, company_id):
@@ -1343,6 +1378,8 @@ class DocuSign(signing_service_interface.SigningService):
             cla.log.info(f'signed_corporate_callback - {signature.get_signature_reference_type()} - '
                          f'CLA signature signed ({signature_id}) - setting signature signed attribute to true')
             signature.set_signature_signed(True)
+            if signature.get_signature_reference_type() == 'company':
+                carry_over_approval_lists(signature)
             signature.save()
 
             # Update our event/activity log
@@ -1406,7 +1443,7 @@ class DocuSign(signing_service_interface.SigningService):
 
             # Store document on S3
             cla.log.debug('signed_corporate_callback - uploading CCLA document to s3...')
-            self.send_to_s3(document_data, project_id, signature_id, 'ccla', company_id)
+            self.store_signed_document(signature, document_data, project_id, 'ccla', company_id)
             cla.log.debug('signed_corporate_callback - uploaded CCLA document to s3')
             cla.log.debug('signed_corporate_callback - DONE!')
 
@@ -1480,6 +1517,18 @@ class DocuSign(signing_service_interface.SigningService):
         cla.log.info(f'Sending signed CLA document to {recipient} with subject: {subject}')
         cla.utils.get_email_service().send(subject, body, recipient)
 
+    def store_signed_document(self, signature, document_data, project_id, cla_type, identifier):
+        """
+        Stores the signed document on S3 and records its SHA-256 digest on the signature so the stored document can
+        later be verified against the digest.
+        """
+        signature_id = signature.get_signature_id()
+        self.send_to_s3(document_data, project_id, signature_id, cla_type, identifier)
+        digest = hashlib.sha256(document_data).hexdigest()
+        cla.log.debug(f'store_signed_document - recording SHA-256 digest: {digest} on signature: {signature_id}')
+        signature.set_signature_document_sha256(digest)
+        signature.save()
+
     def send_to_s3(self, document_data, project_id, signature_id, cla_type, identifier):
         # cla_type could be: icla or ccla (String)
         # identifier could be: user_id or company_id
@@ -1703,6 +1752,49 @@ def create_default_company_values(company: Company,
     return values
 
 
+def get_signature_document_locale(document: Optional[Document], document_locale: Optional[str] = None) -> Optional[str]:
+    """
+    Returns the locale of the document viewed by the signatory - the legally binding locale of the document when no
+    locale is requested, None when the document is not available in the requested locale.
+    """
+    if document is None:
+        # the missing document is reported by the signing request
+        return document_locale or DEFAULT_DOCUMENT_LOCALE
+    legally_binding_locale = document.get_document_locale() or DEFAULT_DOCUMENT_LOCALE
+    if not document_locale or document_locale == legally_binding_locale:
+        return legally_binding_locale
+    if document_locale in document.get_document_translation_locales():
+        return document_locale
+    return None
+
+
+def carry_over_approval_lists(signature: Signature):
+    """
+    Copies the approval lists of the previous CCLA of the company, revoked because the company didn't re-sign the new
+    document version before the re-sign deadline, to the re-signed CCLA - the employees covered by the previous CCLA
+    stay covered without the CLA Managers entering the approval lists again. Nothing is copied when the re-signed CCLA
+    already has approval lists.
+    """
+    if signature.has_approval_lists():
+        return
+    revoked_signatures = [
+        sig for sig in Signature().get_signatures_by_project(
+            signature.get_signature_project_id(),
+            signature_signed=True,
+            signature_approved=False,
+            signature_reference_type='company',
+            signature_reference_id=signature.get_signature_reference_id())
+        if sig.get_signature_revocation() is not None and
+        sig.get_signature_revocation().reason == REVOCATION_REASON_RESIGN_DEADLINE_PASSED
+    ]
+    if len(revoked_signatures) == 0:
+        return
+    previous_signature = max(revoked_signatures, key=lambda sig: sig.get_signature_revocation().revoked_on)
+    cla.log.info(f'carry_over_approval_lists - copying the approval lists of the revoked CCLA: '
+                 f'{previous_signature.get_signature_id()} to the re-signed CCLA: {signature.get_signature_id()}')
+    signature.copy_approval_lists(previous_signature)
+
+
 def create_default_individual_values(user: User) -> Dict[str, Any]:
     values = {}
 
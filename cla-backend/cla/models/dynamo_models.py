@@ -8,7 +8,6 @@ Easily access CLA models backed by DynamoDB using pynamodb.
 import base64
 import datetime
 import os
-import re
 import time
 import uuid
 from typing import Optional, List
@@ -27,6 +26,7 @@ from pynamodb.attributes import (
     JSONAttribute,
     MapAttribute,
 )
+from pynamodb.exceptions import PutError
 from pynamodb.expressions.condition import Condition
 from pynamodb.indexes import GlobalSecondaryIndex, AllProjection
 from pynamodb.models import Model
@@ -703,6 +703,15 @@ class DocumentTab(model_interfaces.DocumentTab):
         self.model.document_tab_anchor_y_offset = document_tab_anchor_y_offset
 
 
+class DocumentTranslationModel(MapAttribute):
+    """
+    Represents a translation of a document in the project model - managed by the Go backend.
+    """
+
+    locale = UnicodeAttribute()
+    document_s3_url = UnicodeAttribute(null=True)
+
+
 class DocumentModel(MapAttribute):
     """
     Represents a document in the project model.
@@ -721,6 +730,9 @@ class DocumentModel(MapAttribute):
     document_legal_entity_name = UnicodeAttribute(null=True)
     document_s3_url = UnicodeAttribute(null=True)
     document_tabs = ListAttribute(of=DocumentTabModel, default=[])
+    # legally binding locale of the document and its translations - managed by the Go backend
+    document_locale = UnicodeAttribute(null=True)
+    document_translations = ListAttribute(of=DocumentTranslationModel, null=True)
 
 
 class Document(model_interfaces.Document):
@@ -777,6 +789,8 @@ class Document(model_interfaces.Document):
             "document_legal_entity_name": self.model.document_legal_entity_name,
             "document_s3_url": self.model.document_s3_url,
             "document_tabs": self.model.document_tabs,
+            "document_locale": self.model.document_locale,
+            "document_translations": self.model.document_translations,
         }
 
     def get_document_name(self):
@@ -819,6 +833,12 @@ class Document(model_interfaces.Document):
     def get_document_s3_url(self):
         return self.model.document_s3_url
 
+    def get_document_locale(self):
+        return self.model.document_locale
+
+    def get_document_translation_locales(self):
+        return [translation.locale for translation in self.model.document_translations or []]
+
     def get_document_tabs(self):
         tabs = []
         for tab in self.model.document_tabs:
@@ -929,6 +949,10 @@ class ProjectModel(BaseModel):
     project_icla_enabled = BooleanAttribute(default=True)
     project_ccla_enabled = BooleanAttribute(default=True)
     project_ccla_requires_icla_signature = BooleanAttribute(default=False)
+    # re-sign policy of new major document versions - managed by the Go backend, declared so that the
+    # project saves of this backend keep it
+    project_resign_required = BooleanAttribute(null=True)
+    project_resign_grace_period_days = NumberAttribute(null=True)
     foundation_sfid = UnicodeAttribute(null=True)
     root_project_repositories_count = NumberAttribute(null=True)
     # Indexes
@@ -1670,20 +1694,11 @@ class User(model_interfaces.User):  # pylint: disable=too-many-public-methods
         :return: True if at least one email is matched against pattern else False
         :rtype: bool
         """
-        for pattern in patterns:
-            if pattern.startswith("*."):
-                pattern = pattern.replace("*.", ".*")
-            elif pattern.startswith("*"):
-                pattern = pattern.replace("*", ".*")
-            elif pattern.startswith("."):
-                pattern = pattern.replace(".", ".*")
-
-            preprocessed_pattern = "^.*@" + pattern + "$"
-            pat = re.compile(preprocessed_pattern)
-            for email in emails:
-                if pat.match(email) is not None:
-                    self.log_debug("found user email in email whitelist pattern")
-                    return True
+        for email in emails:
+            pattern = cla.utils.match_email_domain(email, patterns)
+            if pattern is not None:
+                self.log_debug(f"found user email in email whitelist pattern: {pattern}")
+                return True
         return False
 
     # Accepts a Signature object
@@ -1705,7 +1720,7 @@ class User(model_interfaces.User):  # pylint: disable=too-many-public-methods
             emails = [email.strip() for email in emails]
 
         # First, we check email whitelist
-        whitelist = ccla_signature.get_email_whitelist()
+        whitelist = ccla_signature.get_active_approval_list("email", ccla_signature.get_email_whitelist())
         cla.log.debug(f"is_whitelisted - testing user emails: {emails} with " f"CCLA whitelist emails: {whitelist}")
 
         if whitelist is not None:
@@ -1718,11 +1733,9 @@ class User(model_interfaces.User):  # pylint: disable=too-many-public-methods
             cla.log.debug(f"is_whitelisted - no email whitelist match for user: {self}")
 
         # Secondly, let's check domain whitelist
-        # If a naked domain (e.g. google.com) is provided, we prefix it with '^.*@',
-        # so that sub-domains are not allowed.
-        # If a '*', '*.' or '.' prefix is provided, we replace the prefix with '.*\.',
-        # which will allow subdomains.
-        patterns = ccla_signature.get_domain_whitelist()
+        # A naked domain (e.g. google.com) matches only that domain, sub-domains are not allowed.
+        # A '*.' prefix (or the legacy '*' and '.' prefixes) matches the domain and all of its sub-domains.
+        patterns = ccla_signature.get_active_approval_list("domain", ccla_signature.get_domain_whitelist())
         cla.log.debug(
             f"is_whitelisted - testing user email domains: {emails} with "
             f"whitelist domain values in database: {patterns}"
@@ -1770,7 +1783,8 @@ class User(model_interfaces.User):  # pylint: disable=too-many-public-methods
         if github_username is not None:
             # remove leading and trailing whitespace from github username
             github_username = github_username.strip()
-            github_whitelist = ccla_signature.get_github_whitelist()
+            github_whitelist = ccla_signature.get_active_approval_list(
+                "githubUsername", ccla_signature.get_github_whitelist())
             cla.log.debug(
                 f"is_whitelisted - testing user github username: {github_username} with "
                 f"CCLA github whitelist: {github_whitelist}"
@@ -1791,7 +1805,8 @@ class User(model_interfaces.User):  # pylint: disable=too-many-public-methods
             github_orgs = cla.utils.lookup_github_organizations(github_username)
             if "error" not in github_orgs:
                 # Fetch the list of orgs this user is part of
-                github_org_whitelist = ccla_signature.get_github_org_whitelist()
+                github_org_whitelist = ccla_signature.get_active_approval_list(
+                    "githubOrg", ccla_signature.get_github_org_whitelist())
                 cla.log.debug(
                     f"is_whitelisted - testing user github orgs: {github_orgs} with "
                     f"CCLA github org whitelist values: {github_org_whitelist}"
@@ -1803,6 +1818,15 @@ class User(model_interfaces.User):  # pylint: disable=too-many-public-methods
                         if dynamo_github_org.lower() in (s.lower() for s in github_orgs):
                             self.log_debug("found matching github org for user")
                             return True
+
+            # Check github team whitelist
+            github_team_whitelist = ccla_signature.get_active_approval_list(
+                "githubTeam", ccla_signature.get_github_team_whitelist())
+            if github_team_whitelist is not None:
+                for github_team in github_team_whitelist:
+                    if cla.utils.is_github_team_member(github_username, github_team):
+                        self.log_debug(f"found user in github team: {github_team}")
+                        return True
         else:
             cla.log.debug(
                 "is_whitelisted - users github_username is not defined " "- skipping github org whitelist check"
@@ -2028,6 +2052,37 @@ def create_filter(attributes, model):
     return filter_condition
 
 
+class ApprovalListExpirationModel(MapAttribute):
+    """
+    Represents the expiration date of a single approval list entry of a CCLA signature.
+    """
+
+    list_type = UnicodeAttribute()  # email, domain, githubUsername or githubOrg
+    value = UnicodeAttribute()
+    expiration_date = UnicodeAttribute()
+    reminder_sent_date = UnicodeAttribute(null=True)
+
+
+class AutoApprovalRuleModel(MapAttribute):
+    """
+    Represents a rule which approves the CCLA approval list requests of a CCLA signature without a CLA manager.
+    """
+
+    rule_type = UnicodeAttribute()  # emailDomain or githubOrg
+    values = ListAttribute(null=True)
+
+
+class SignatureRevocationModel(MapAttribute):
+    """
+    Represents the revocation of a signature - revoked signatures are no longer approved.
+    """
+
+    reason = UnicodeAttribute()  # employmentEnded, signedInError or legalRequest
+    comment = UnicodeAttribute(null=True)
+    revoked_on = UnicodeAttribute()
+    revoked_by = UnicodeAttribute()
+
+
 class SignatureModel(BaseModel):  # pylint: disable=too-many-instance-attributes
     """
     Represents an signature in the database.
@@ -2088,6 +2143,20 @@ class SignatureModel(BaseModel):  # pylint: disable=too-many-instance-attributes
     email_whitelist = ListAttribute(null=True)
     github_whitelist = ListAttribute(null=True)
     github_org_whitelist = ListAttribute(null=True)
+    # GitHub teams in the form organization/team-slug
+    github_team_whitelist = ListAttribute(null=True)
+    # optional expiration dates of the approval list entries - expired entries no longer grant coverage
+    approval_list_expirations = ListAttribute(of=ApprovalListExpirationModel, null=True)
+    # optional rules which approve CCLA approval list requests without a CLA manager - managed by the Go backend
+    auto_approval_rules = ListAttribute(of=AutoApprovalRuleModel, null=True)
+    # set when the signature was revoked - managed by the Go backend
+    signature_revocation = SignatureRevocationModel(null=True)
+    # hex encoded SHA-256 digest of the signed document, captured when the document is stored
+    signature_document_sha256 = UnicodeAttribute(null=True)
+    # locale of the document viewed by the signatory, the legally binding document is signed
+    signature_document_locale = UnicodeAttribute(null=True)
+    # optimistic concurrency version of the approval lists and the ACL - incremented by each save and update
+    record_version = NumberAttribute(null=True)
 
     # Additional attributes for ICLAs
     user_email = UnicodeAttribute(null=True)
@@ -2128,6 +2197,7 @@ class Signature(model_interfaces.Signature):  # pylint: disable=too-many-public-
             email_whitelist=None,
             github_whitelist=None,
             github_org_whitelist=None,
+            github_team_whitelist=None,
             note=None,
             signature_project_external_id=None,
             signature_company_signatory_id=None,
@@ -2170,6 +2240,7 @@ class Signature(model_interfaces.Signature):  # pylint: disable=too-many-public-
         self.model.email_whitelist = email_whitelist
         self.model.github_whitelist = github_whitelist
         self.model.github_org_whitelist = github_org_whitelist
+        self.model.github_team_whitelist = github_team_whitelist
         self.model.note = note
         self.model.signature_project_external_id = signature_project_external_id
         self.model.signature_company_signatory_id = signature_company_signatory_id
@@ -2229,8 +2300,32 @@ class Signature(model_interfaces.Signature):  # pylint: disable=too-many-public-
         return dict(self.model)
 
     def save(self):
+        # Bump the record version so that the record version guarded updates of the approval lists and the ACL
+        # detect the change
+        self.model.record_version = (self.model.record_version or 0) + 1
         self.model.save()
 
+    def save_with_version_check(self):
+        """
+        Saves the signature only if the record wasn't updated since it was loaded, like the record version guarded
+        updates of the Go backend. Raises VersionConflict otherwise.
+        """
+        record_version = self.model.record_version or 0
+        if record_version == 0:
+            condition = SignatureModel.record_version.does_not_exist() | (SignatureModel.record_version == 0)
+        else:
+            condition = SignatureModel.record_version == record_version
+        self.model.record_version = record_version + 1
+        try:
+            self.model.save(condition)
+        except PutError as err:
+            self.model.record_version = record_version
+            cause = getattr(err, 'cause', None)
+            if getattr(cause, 'response', {}).get('Error', {}).get('Code') == 'ConditionalCheckFailedException':
+                raise cla.models.VersionConflict(
+                    f'signature {self.model.signature_id} was updated since it was loaded')
+            raise
+
     def load(self, signature_id):
         try:
             signature = self.model.get(signature_id)
@@ -2320,6 +2415,66 @@ class Signature(model_interfaces.Signature):  # pylint: disable=too-many-public-
     def get_github_org_whitelist(self):
         return self.model.github_org_whitelist
 
+    def get_github_team_whitelist(self):
+        return self.model.github_team_whitelist
+
+    def get_approval_list_expirations(self):
+        return self.model.approval_list_expirations
+
+    def get_auto_approval_rules(self):
+        return self.model.auto_approval_rules
+
+    def get_signature_revocation(self):
+        return self.model.signature_revocation
+
+    def get_signature_document_sha256(self):
+        return self.model.signature_document_sha256
+
+    def get_signature_document_locale(self):
+        return self.model.signature_document_locale
+
+    def get_record_version(self):
+        return self.model.record_version or 0
+
+    def set_signature_document_sha256(self, signature_document_sha256):
+        self.model.signature_document_sha256 = signature_document_sha256
+
+    def set_signature_document_locale(self, signature_document_locale):
+        self.model.signature_document_locale = signature_document_locale
+
+    def get_active_approval_list(self, list_type, entries):
+        """
+        Helper function that filters out the approval list entries which carry an expiration date that has passed.
+
+        :param list_type: the approval list type - email, domain, githubUsername or githubOrg
+        :type list_type: str
+        :param entries: the approval list entries
+        :type entries: list
+        :return: the entries which have not expired, None if no entries were provided
+        :rtype: list
+        """
+        expirations = self.get_approval_list_expirations()
+        if entries is None or not expirations:
+            return entries
+
+        now = datetime.datetime.now(datetime.timezone.utc)
+        expired = set()
+        for expiration in expirations:
+            if expiration.list_type != list_type:
+                continue
+            try:
+                expiration_date = dateutil.parser.parse(expiration.expiration_date)
+            except (ValueError, OverflowError):
+                cla.log.warning(f"invalid approval list expiration date: {expiration.expiration_date} "
+                                f"for {list_type} entry: {expiration.value}")
+                continue
+            if expiration_date.tzinfo is None:
+                expiration_date = expiration_date.replace(tzinfo=datetime.timezone.utc)
+            if expiration_date <= now:
+                expired.add(expiration.value.lower())
+
+        return [entry for entry in entries if entry.lower() not in expired]
+
     def get_note(self):
         return self.model.note
 
@@ -2458,6 +2613,25 @@ class Signature(model_interfaces.Signature):  # pylint: disable=too-many-public-
     def set_github_org_whitelist(self, github_org_whitelist):
         self.model.github_org_whitelist = [github_org.strip() for github_org in github_org_whitelist]
 
+    def set_github_team_whitelist(self, github_team_whitelist):
+        self.model.github_team_whitelist = [github_team.strip() for github_team in github_team_whitelist]
+
+    def has_approval_lists(self):
+        return any([self.model.domain_whitelist, self.model.email_whitelist, self.model.github_whitelist,
+                    self.model.github_org_whitelist, self.model.github_team_whitelist, self.model.auto_approval_rules])
+
+    def copy_approval_lists(self, signature):
+        """
+        Copies the approval lists, their expirations and the auto-approval rules of the signature.
+        """
+        self.model.domain_whitelist = signature.model.domain_whitelist
+        self.model.email_whitelist = signature.model.email_whitelist
+        self.model.github_whitelist = signature.model.github_whitelist
+        self.model.github_org_whitelist = signature.model.github_org_whitelist
+        self.model.github_team_whitelist = signature.model.github_team_whitelist
+        self.model.approval_list_expirations = signature.model.approval_list_expirations
+        self.model.auto_approval_rules = signature.model.auto_approval_rules
+
     def set_note(self, note):
         self.model.note = note
 
@@ -2933,6 +3107,9 @@ class CompanyModel(BaseModel):
     company_name = UnicodeAttribute()
     company_external_id_index = ExternalCompanyIndex()
     company_acl = UnicodeSetAttribute(default=set())
+    merged_into_company_id = UnicodeAttribute(null=True)
+    merged_company_ids = UnicodeSetAttribute(null=True)
+    date_merged = UnicodeAttribute(null=True)
 
 
 class Company(model_interfaces.Company):  # pylint: disable=too-many-public-methods
@@ -2996,6 +3173,15 @@ class Company(model_interfaces.Company):  # pylint: disable=too-many-public-meth
     def get_company_acl(self):
         return self.model.company_acl
 
+    def get_merged_into_company_id(self):
+        return self.model.merged_into_company_id
+
+    def get_merged_company_ids(self):
+        return self.model.merged_company_ids
+
+    def get_date_merged(self):
+        return self.model.date_merged
+
     def set_company_id(self, company_id):
         self.model.company_id = company_id
 
@@ -778,6 +778,12 @@ class Signature(object):  # pylint: disable=too-many-public-methods
         """
         raise NotImplementedError()
 
+    def save_with_version_check(self):
+        """
+        Saves the model only if it wasn't updated since it was loaded.
+        """
+        raise NotImplementedError()
+
     def load(self, signature_id):
         """
         Simple abstraction around the supported ORMs to load a model.
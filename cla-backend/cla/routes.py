@@ -1108,7 +1108,7 @@ def delete_project_document(
                         'user_id': 'some-user-uuid'}",
 )
 def request_individual_signature(
-    project_id: hug.types.uuid, user_id: hug.types.uuid, return_url_type=None, return_url=None,
+    project_id: hug.types.uuid, user_id: hug.types.uuid, return_url_type=None, return_url=None, document_locale=None,
 ):
     """
     POST: /request-individual-signature
@@ -1116,7 +1116,8 @@ def request_individual_signature(
     DATA: {'project_id': 'some-project-id',
            'user_id': 'some-user-id',
            'return_url_type': Gerrit/Github. Optional depending on presence of return_url
-           'return_url': <optional>}
+           'return_url': <optional>,
+           'document_locale': <optional>}
 
     Creates a new signature given project and user IDs. The user will be redirected to the
     return_url once signature is complete.
@@ -1131,7 +1132,9 @@ def request_individual_signature(
     User should hit the provided URL to initiate the signing process through the
     signing service provider.
     """
-    return cla.controllers.signing.request_individual_signature(project_id, user_id, return_url_type, return_url)
+    return cla.controllers.signing.request_individual_signature(
+        project_id, user_id, return_url_type, return_url, document_locale
+    )
 
 
 @hug.post(
@@ -1149,6 +1152,7 @@ def request_corporate_signature(
     authority_email=None,
     return_url_type=None,
     return_url=None,
+    document_locale=None,
 ):
     """
     POST: /request-corporate-signature
@@ -1158,7 +1162,8 @@ def request_corporate_signature(
            'send_as_email': 'boolean',
            'authority_name': 'string',
            'authority_email': 'string',
-           'return_url': <optional>}
+           'return_url': <optional>,
+           'document_locale': <optional>}
 
     Creates a new signature given project and company IDs. The manager will be redirected to the
     return_url once signature is complete.
@@ -1180,6 +1185,7 @@ def request_corporate_signature(
     # staff_verify(user) or company_manager_verify(user, company_id)
     return cla.controllers.signing.request_corporate_signature(
         auth_user, project_id, company_id, send_as_email, authority_name, authority_email, return_url_type, return_url,
+        document_locale,
     )
 
 
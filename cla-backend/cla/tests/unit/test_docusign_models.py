@@ -0,0 +1,146 @@
+# Copyright The Linux Foundation and each contributor to CommunityBridge.
+# SPDX-License-Identifier: MIT
+
+"""
+Test the signature saves of the DocuSign callbacks
+"""
+from unittest.mock import patch, MagicMock
+
+import pytest
+from botocore.exceptions import ClientError
+
+import cla
+from cla.models import VersionConflict
+from cla.models.docusign_models import DocuSign, carry_over_approval_lists
+from cla.models.dynamo_models import Signature, SignatureRevocationModel, User, Project, Event
+from cla.tests.unit.data import SIGNATURE_TABLE_DATA
+
+PATCH_METHOD = "pynamodb.connection.Connection._make_api_call"
+
+CALLBACK_CONTENT = """<?xml version="1.0" encoding="utf-8"?>
+<DocuSignEnvelopeInformation xmlns="http://www.docusign.net/API/3.0">
+  <EnvelopeStatus>
+    <RecipientStatuses>
+      <RecipientStatus>
+        <Status>Completed</Status>
+        <ClientUserId>sig_id</ClientUserId>
+      </RecipientStatus>
+    </RecipientStatuses>
+    <EnvelopeID>envelope_id</EnvelopeID>
+  </EnvelopeStatus>
+</DocuSignEnvelopeInformation>
+"""
+
+
+def loaded_signature():
+    signature = Signature()
+    signature.set_signature_id("sig_id")
+    signature.set_signature_project_id("proj_id")
+    signature.set_signature_reference_id("user_id")
+    signature.set_signature_signed(False)
+    signature.model.record_version = 1
+    signature.load = MagicMock()
+    return signature
+
+
+def put_item_calls(req):
+    return [call[0][1] for call in req.call_args_list if call[0][0] == "PutItem"]
+
+
+def test_signed_individual_callback_saves_twice():
+    signature = loaded_signature()
+    docusign = DocuSign()
+    docusign.get_signed_document = MagicMock(return_value=b"%PDF-signed")
+    docusign.send_signed_document = MagicMock()
+    docusign.send_to_s3 = MagicMock()
+
+    with patch(PATCH_METHOD) as req, \
+            patch.object(cla.utils, "get_signature_instance", return_value=signature), \
+            patch.object(cla.utils, "delete_active_signature_metadata"), \
+            patch.object(User, "load"), \
+            patch.object(User, "get_user_id", return_value="user_id"), \
+            patch.object(Project, "load"), \
+            patch.object(Project, "get_project_name", return_value="Project"), \
+            patch.object(Event, "create_event"), \
+            patch("cla.models.docusign_models.update_repository_provider") as update_provider:
+        req.return_value = SIGNATURE_TABLE_DATA
+        docusign.signed_individual_callback(CALLBACK_CONTENT, "installation_id", "repository_id", "change_id")
+
+        # The signature is saved when it's signed and again with the digest of the stored document, neither save is
+        # guarded by the record version
+        puts = put_item_calls(req)
+        assert len(puts) == 2
+        assert all("ConditionExpression" not in put for put in puts)
+        assert signature.get_signature_signed()
+        assert signature.get_signature_document_sha256() is not None
+        assert signature.get_record_version() == 3
+        update_provider.assert_called_once()
+
+
+def test_save_with_version_check():
+    signature = loaded_signature()
+    with patch(PATCH_METHOD) as req:
+        req.return_value = SIGNATURE_TABLE_DATA
+        signature.save_with_version_check()
+        puts = put_item_calls(req)
+        assert len(puts) == 1
+        assert "ConditionExpression" in puts[0]
+        assert signature.get_record_version() == 2
+
+
+def test_save_with_version_check_conflict():
+    signature = loaded_signature()
+
+    def make_api_call(operation_name, operation_kwargs):
+        if operation_name == "PutItem":
+            raise ClientError({"Error": {"Code": "ConditionalCheckFailedException", "Message": "failed"}}, "PutItem")
+        return SIGNATURE_TABLE_DATA
+
+    with patch(PATCH_METHOD) as req:
+        req.side_effect = make_api_call
+        with pytest.raises(VersionConflict):
+            signature.save_with_version_check()
+        assert signature.get_record_version() == 1
+
+
+def revoked_ccla(signature_id, reason, revoked_on, domains):
+    signature = Signature()
+    signature.set_signature_id(signature_id)
+    signature.set_domain_whitelist(domains)
+    signature.model.signature_revocation = SignatureRevocationModel(
+        reason=reason, revoked_on=revoked_on, revoked_by="easycla system")
+    return signature
+
+
+def test_carry_over_approval_lists():
+    signature = Signature()
+    signature.set_signature_id("ccla_v2")
+    signature.set_signature_project_id("proj_id")
+    signature.set_signature_reference_id("company_id")
+    previous = [
+        revoked_ccla("ccla_v0", "resignDeadlinePassed", "2024-01-01T00:00:00Z", ["old.example.com"]),
+        revoked_ccla("ccla_v1", "resignDeadlinePassed", "2025-01-01T00:00:00Z", ["acme.com"]),
+        revoked_ccla("ccla_error", "signedInError", "2025-06-01T00:00:00Z", ["error.example.com"]),
+    ]
+    previous[1].set_email_whitelist(["contractor@example.com"])
+
+    # The approval lists of the latest CCLA revoked by a re-sign campaign are copied
+    with patch.object(Signature, "get_signatures_by_project", return_value=previous) as get_signatures:
+        carry_over_approval_lists(signature)
+        get_signatures.assert_called_once_with("proj_id", signature_signed=True, signature_approved=False,
+                                               signature_reference_type="company",
+                                               signature_reference_id="company_id")
+    assert signature.get_domain_whitelist() == ["acme.com"]
+    assert signature.get_email_whitelist() == ["contractor@example.com"]
+
+    # The approval lists entered on the re-signed CCLA are kept
+    signature.set_domain_whitelist(["new.example.com"])
+    with patch.object(Signature, "get_signatures_by_project", return_value=previous):
+        carry_over_approval_lists(signature)
+    assert signature.get_domain_whitelist() == ["new.example.com"]
+
+    # Nothing is copied from the CCLAs which weren't revoked by a re-sign campaign
+    signature = Signature()
+    with patch.object(Signature, "get_signatures_by_project", return_value=previous[2:]):
+        carry_over_approval_lists(signature)
+    assert not signature.has_approval_lists()
@@ -5,7 +5,7 @@ from unittest.mock import patch,MagicMock
 
 import pytest
 
-from cla.models.dynamo_models import Signature, User, UserModel
+from cla.models.dynamo_models import ApprovalListExpirationModel, Signature, User, UserModel
 
 
 @pytest.fixture()
@@ -64,6 +64,14 @@ def test_pattern_with_dot_prefix(create_user):
     domain_emails = ["harold@help.bar.com"]
     assert create_user.preprocess_pattern(domain_emails, patterns) == True
 
+def test_wildcard_pattern_matches_on_label_boundary(create_user):
+    """Test wildcard patterns only match the domain and its sub-domains """
+    patterns = ["*.bar.com"]
+    assert create_user.preprocess_pattern(["harold@build.help.bar.com"], patterns) == True
+    assert create_user.preprocess_pattern(["harold@foobar.com"], patterns) == False
+    assert create_user.preprocess_pattern(["harold@bar.com.evil.org"], patterns) == False
+    assert create_user.preprocess_pattern(["harold@foobar.com"], ["*bar.com"]) == False
+
 def test_email_whitelist_fail(create_user):
     """Test email that fails domain and email whitelist checks """
     signature = Signature()
@@ -78,3 +86,21 @@ def test_gerrit_project_whitelisting(create_user):
     signature.get_email_whitelist = MagicMock(return_value={"phillip.leigh@amdocs.com"})
     create_user.get_all_user_emails = MagicMock(return_value=["phillip.leigh@amdocs.com"])
     assert create_user.is_whitelisted(signature) == True
+
+def test_expired_approval_list_entries(create_user):
+    """Test expired approval list entries no longer grant coverage """
+    signature = Signature()
+    signature.get_email_whitelist = MagicMock(return_value=["contractor@acme.com", "employee@acme.com"])
+    signature.get_domain_whitelist = MagicMock(return_value=None)
+    signature.get_approval_list_expirations = MagicMock(return_value=[
+        ApprovalListExpirationModel(list_type="email", value="contractor@acme.com",
+                                    expiration_date="2020-01-01T00:00:00Z"),
+        ApprovalListExpirationModel(list_type="email", value="employee@acme.com",
+                                    expiration_date="2999-01-01T00:00:00Z"),
+    ])
+    create_user.get_user_github_username = MagicMock(return_value=None)
+    create_user.get_user_github_id = MagicMock(return_value=None)
+    create_user.get_all_user_emails = MagicMock(return_value=["contractor@acme.com"])
+    assert create_user.is_whitelisted(signature) == False
+    create_user.get_all_user_emails = MagicMock(return_value=["employee@acme.com"])
+    assert create_user.is_whitelisted(signature) == True
@@ -161,5 +161,39 @@ class TestUtils(unittest.TestCase):
         self.assertTrue(utils.is_whitelisted(signature, github_username='foo'))
 
 
+    def test_is_github_team_member_cache(self) -> None:
+        """
+        Test that the GitHub team memberships are cached until they expire and that the teams of the organization are
+        only walked the first time the team is checked
+        """
+        team = Mock(slug='Reviewers', id=42)
+        team.has_in_members.return_value = True
+        client = Mock()
+        organization = client.get_organization.return_value
+        organization.get_teams.return_value = [team]
+        organization.get_team.return_value = team
+        utils._github_team_member_cache.clear()
+        utils._github_team_id_cache.clear()
+        with patch('cla.models.github_models.get_github_client', return_value=client), \
+                patch('cla.utils.time.time', return_value=1000):
+            self.assertTrue(utils.is_github_team_member('octocat', 'acme/reviewers'))
+            self.assertTrue(utils.is_github_team_member('OctoCat', 'acme/Reviewers'))
+            self.assertEqual(team.has_in_members.call_count, 1)
+            self.assertTrue(utils.is_github_team_member('hubot', 'acme/reviewers'))
+            self.assertEqual(team.has_in_members.call_count, 2)
+            self.assertFalse(utils.is_github_team_member('octocat', 'acme/maintainers'))
+
+        with patch('cla.models.github_models.get_github_client', return_value=client), \
+                patch('cla.utils.time.time', return_value=1000 + utils.GITHUB_TEAM_MEMBER_CACHE_TTL):
+            self.assertTrue(utils.is_github_team_member('octocat', 'acme/reviewers'))
+            self.assertEqual(team.has_in_members.call_count, 3)
+
+        # The teams are walked for each unknown team, the known team is loaded by its id
+        self.assertEqual(organization.get_teams.call_count, 2)
+        organization.get_team.assert_called_with(42)
+        utils._github_team_member_cache.clear()
+        utils._github_team_id_cache.clear()
+
+
 if __name__ == '__main__':
     unittest.main()
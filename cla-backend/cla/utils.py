@@ -8,6 +8,7 @@ Utility functions for the CLA project.
 import inspect
 import json
 import os
+import time
 import urllib.parse
 from typing import List, Optional
 
@@ -1281,6 +1282,66 @@ def lookup_github_organizations(github_username: str):
     return [github_org['login'] for github_org in r.json()]
 
 
+# How long the GitHub team memberships are cached, in seconds - same as the team members cache of the Go backend
+GITHUB_TEAM_MEMBER_CACHE_TTL = 10 * 60
+
+# The GitHub team memberships keyed by (github username, organization/team-slug), in lower case - the values are
+# (is member, expiry time) tuples
+_github_team_member_cache = {}
+
+# The GitHub team ids keyed by organization/team-slug, in lower case - the team id doesn't change when the team is
+# renamed so the ids aren't expired
+_github_team_id_cache = {}
+
+
+def is_github_team_member(github_username: str, github_team: str) -> bool:
+    """
+    Checks the membership of the GitHub user in the GitHub team, in the form organization/team-slug. The team
+    organization must have the EasyCLA GitHub App installed. Teams which can't be resolved are treated as no match.
+    The memberships are cached for GITHUB_TEAM_MEMBER_CACHE_TTL seconds, the failed checks aren't cached.
+
+    :param github_username: the GitHub username
+    :param github_team: the GitHub team in the form organization/team-slug
+    :return: True if the user is a member of the team, False otherwise
+    """
+    parts = github_team.strip().split('/')
+    if len(parts) != 2 or not parts[0] or not parts[1]:
+        cla.log.warning(f'is_github_team_member - invalid github team: {github_team}')
+        return False
+    organization_name, team_slug = parts
+
+    cache_key = (github_username.lower(), f'{organization_name}/{team_slug}'.lower())
+    cached = _github_team_member_cache.get(cache_key)
+    if cached is not None and time.time() < cached[1]:
+        return cached[0]
+
+    team_key = cache_key[1]
+    try:
+        from cla.models.github_models import get_github_client
+        client = get_github_client(organization_name)
+        organization = client.get_organization(organization_name)
+        team_id = _github_team_id_cache.get(team_key)
+        if team_id is not None:
+            team = organization.get_team(team_id)
+        else:
+            # Only walk the teams of the organization the first time the team is checked
+            team = next((t for t in organization.get_teams() if t.slug.lower() == team_slug.lower()), None)
+            if team is None:
+                cla.log.debug(f'is_github_team_member - github team: {github_team} not found')
+                return False
+            _github_team_id_cache[team_key] = team.id
+        is_member = team.has_in_members(client.get_user(github_username))
+    except Exception as err:
+        # The team may have been deleted, look it up again on the next check
+        _github_team_id_cache.pop(team_key, None)
+        cla.log.warning(f'is_github_team_member - unable to check the membership of {github_username} '
+                        f'in github team: {github_team}, error: {err}')
+        return False
+
+    _github_team_member_cache[cache_key] = (is_member, time.time() + GITHUB_TEAM_MEMBER_CACHE_TTL)
+    return is_member
+
+
 def update_github_username(github_user: dict, user: User):
     """
     When provided a GitHub user model from the GitHub service, updates the CLA
@@ -1302,6 +1363,83 @@ def update_github_username(github_user: dict, user: User):
             user.set_user_github_username(github_user['login'])
 
 
+DOMAIN_WILDCARD_PREFIX = "*."
+
+
+def normalize_domain_pattern(pattern: str) -> str:
+    """
+    Returns the canonical form of a domain approval list entry - trimmed, lower case, with the
+    legacy wildcard prefixes ('*' and '.') rewritten to the '*.' form.
+
+    Matching rules (kept in sync with the Go backend utils.DomainMatchesPattern):
+      - an exact entry (acme.com) matches only that domain
+      - a wildcard entry (*.acme.com) matches acme.com and any sub-domain at any depth, but never a
+        domain which merely ends with the same characters (notacme.com)
+      - exact and wildcard entries are independent, an exact entry is reported before a wildcard entry
+
+    :param pattern: the domain approval list entry
+    :type pattern: str
+    :return: the normalized entry
+    :rtype: str
+    """
+    pattern = pattern.strip().lower()
+    if pattern.startswith(DOMAIN_WILDCARD_PREFIX):
+        return pattern
+    if pattern.startswith("*"):
+        return DOMAIN_WILDCARD_PREFIX + pattern.lstrip("*.")
+    if pattern.startswith("."):
+        return DOMAIN_WILDCARD_PREFIX + pattern.lstrip(".")
+    return pattern
+
+
+def domain_matches_pattern(domain: str, pattern: str) -> bool:
+    """
+    Helper function to determine whether a domain is covered by a domain approval list entry.
+
+    :param domain: the domain to check, e.g. eng.acme.com
+    :type domain: str
+    :param pattern: the domain approval list entry, e.g. *.acme.com
+    :type pattern: str
+    :return: True if the domain is covered by the entry, False otherwise
+    :rtype: bool
+    """
+    domain = domain.strip().lower().rstrip(".")
+    pattern = normalize_domain_pattern(pattern)
+    if not domain or not pattern or pattern == DOMAIN_WILDCARD_PREFIX:
+        return False
+    if not pattern.startswith(DOMAIN_WILDCARD_PREFIX):
+        return domain == pattern
+    base = pattern[len(DOMAIN_WILDCARD_PREFIX):]
+    return domain == base or domain.endswith("." + base)
+
+
+def match_email_domain(email: str, patterns) -> Optional[str]:
+    """
+    Checks the domain of an email address against the domain approval list entries.
+
+    :param email: the email address to check
+    :type email: str
+    :param patterns: the domain approval list entries
+    :type patterns: list
+    :return: the entry which covers the email - exact entries first, then the most specific wildcard
+             entry - or None if no entry covers the email
+    :rtype: str
+    """
+    email = email.strip()
+    if "@" not in email:
+        return None
+    domain = email.rsplit("@", 1)[1]
+    matched = None
+    for pattern in patterns:
+        if not domain_matches_pattern(domain, pattern):
+            continue
+        if not normalize_domain_pattern(pattern).startswith(DOMAIN_WILDCARD_PREFIX):
+            return pattern
+        if matched is None or len(normalize_domain_pattern(pattern)) > len(normalize_domain_pattern(matched)):
+            matched = pattern
+    return matched
+
+
 def is_whitelisted(ccla_signature: Signature, email=None, github_username=None, github_id=None):
     """
     Given either email, github username or github id a check is made against ccla signature to
@@ -1316,7 +1454,7 @@ def is_whitelisted(ccla_signature: Signature, email=None, github_username=None,
 
     if email:
         # Checking email whitelist
-        whitelist = ccla_signature.get_email_whitelist()
+        whitelist = ccla_signature.get_active_approval_list("email", ccla_signature.get_email_whitelist())
         cla.log.debug(f'is_whitelisted - testing email: {email} with '
                       f'CCLA whitelist emails: {whitelist}'
                       )
@@ -1326,7 +1464,7 @@ def is_whitelisted(ccla_signature: Signature, email=None, github_username=None,
                 return True
 
         # Checking domain whitelist
-        patterns = ccla_signature.get_domain_whitelist()
+        patterns = ccla_signature.get_active_approval_list("domain", ccla_signature.get_domain_whitelist())
         cla.log.debug(
             f"is_whitelisted - testing user email domain: {email} with "
             f"whitelist domain values in database: {patterns}"
@@ -1348,7 +1486,7 @@ def is_whitelisted(ccla_signature: Signature, email=None, github_username=None,
     if github_username is not None:
         # remove leading and trailing whitespace from github username
         github_username = github_username.strip()
-        github_whitelist = ccla_signature.get_github_whitelist()
+        github_whitelist = ccla_signature.get_active_approval_list("githubUsername", ccla_signature.get_github_whitelist())
         cla.log.debug(
             f"is_whitelisted - testing user github username: {github_username} with "
             f"CCLA github whitelist: {github_whitelist}"
@@ -1369,7 +1507,8 @@ def is_whitelisted(ccla_signature: Signature, email=None, github_username=None,
         github_orgs = cla.utils.lookup_github_organizations(github_username)
         if "error" not in github_orgs:
             # Fetch the list of orgs this user is part of
-            github_org_whitelist = ccla_signature.get_github_org_whitelist()
+            github_org_whitelist = ccla_signature.get_active_approval_list(
+                "githubOrg", ccla_signature.get_github_org_whitelist())
             cla.log.debug(
                 f"is_whitelisted - testing user github orgs: {github_orgs} with "
                 f"CCLA github org whitelist values: {github_org_whitelist}"
@@ -1381,6 +1520,15 @@ def is_whitelisted(ccla_signature: Signature, email=None, github_username=None,
                     if dynamo_github_org.lower() in (s.lower() for s in github_orgs):
                         cla.log.debug("found matching github org for user")
                         return True
+
+        # Check github team whitelist
+        github_team_whitelist = ccla_signature.get_active_approval_list(
+            "githubTeam", ccla_signature.get_github_team_whitelist())
+        if github_team_whitelist is not None:
+            for github_team in github_team_whitelist:
+                if is_github_team_member(github_username, github_team):
+                    cla.log.debug(f"found user in github team: {github_team}")
+                    return True
     else:
         cla.log.debug(
             "is_whitelisted - users github_username is not defined " "- skipping github org whitelist check"